@@ -0,0 +1,45 @@
+package pqt
+
+// Aggregate represents aggregate function computed by generated aggregate methods.
+type Aggregate int
+
+const (
+	// AggregateCount counts rows, or non-null values if column is given (COUNT).
+	AggregateCount Aggregate = iota + 1
+	// AggregateSum sums values of the column (SUM).
+	AggregateSum
+	// AggregateAvg computes average of values of the column (AVG).
+	AggregateAvg
+	// AggregateMin returns the smallest value of the column (MIN).
+	AggregateMin
+	// AggregateMax returns the largest value of the column (MAX).
+	AggregateMax
+)
+
+// String returns name of the function, empty string for unknown one.
+func (a Aggregate) String() string {
+	switch a {
+	case AggregateCount:
+		return "COUNT"
+	case AggregateSum:
+		return "SUM"
+	case AggregateAvg:
+		return "AVG"
+	case AggregateMin:
+		return "MIN"
+	case AggregateMax:
+		return "MAX"
+	default:
+		return ""
+	}
+}
+
+// AggregateResult is a single group returned by generated aggregate methods.
+// Group holds value of the column results are grouped by, as returned by the driver, nil if results are not grouped.
+// Value holds the result if it is a number, that is for COUNT and for any function of a numeric column.
+// Otherwise, for MIN and MAX of e.g. text, timestamp or uuid column, Raw holds the result as returned by the driver and Value is zero.
+type AggregateResult struct {
+	Group interface{}
+	Value float64
+	Raw   interface{}
+}
@@ -0,0 +1,21 @@
+package pqt
+
+import (
+	"testing"
+)
+
+func TestAggregate_String(t *testing.T) {
+	cases := map[Aggregate]string{
+		0:              "",
+		AggregateCount: "COUNT",
+		AggregateSum:   "SUM",
+		AggregateAvg:   "AVG",
+		AggregateMin:   "MIN",
+		AggregateMax:   "MAX",
+	}
+	for a, expected := range cases {
+		if got := a.String(); got != expected {
+			t.Errorf("wrong function, expected %q but got %q", expected, got)
+		}
+	}
+}
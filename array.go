@@ -96,6 +96,8 @@ func (a ArrayInt64) Value() (driver.Value, error) {
 	return buffer.Bytes(), nil
 }
 
+var arrayStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
 // ArrayString is a slice of strings that implements necessary interfaces.
 type ArrayString []string
 
@@ -129,11 +131,51 @@ func (a *ArrayString) Scan(src interface{}) error {
 		return fmt.Errorf(`pqt: expected to get source argument in format "{text1,text2,...,textN}", but got %s`, srcs)
 	}
 
-	*a = strings.Split(string(srcs[1:l-1]), arraySeparator)
+	elems, err := parseArrayString(srcs[1 : l-1])
+	if err != nil {
+		return fmt.Errorf(`pqt: expected to get source argument in format "{text1,text2,...,textN}", but got %s: %s`, srcs, err.Error())
+	}
+	*a = elems
 
 	return nil
 }
 
+// parseArrayString splits content of one dimensional array literal (without surrounding braces) into elements.
+// Double quoted elements are unescaped, others are taken as they are.
+func parseArrayString(s string) ([]string, error) {
+	elems := make([]string, 0)
+	if s == "" {
+		return elems, nil
+	}
+
+	var (
+		buf    bytes.Buffer
+		quoted bool
+	)
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case quoted && ch == '\\':
+			i++
+			if i == len(s) {
+				return nil, fmt.Errorf("unexpected end of input after escape character")
+			}
+			buf.WriteByte(s[i])
+		case ch == '"':
+			quoted = !quoted
+		case !quoted && ch == arraySeparator[0]:
+			elems = append(elems, buf.String())
+			buf.Reset()
+		default:
+			buf.WriteByte(ch)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quoted element")
+	}
+
+	return append(elems, buf.String()), nil
+}
+
 // Value satisfy driver.Valuer interface.
 func (a ArrayString) Value() (driver.Value, error) {
 	var (
@@ -151,7 +193,14 @@ func (a ArrayString) Value() (driver.Value, error) {
 				return nil, err
 			}
 		}
-		if _, err = buffer.WriteString(v); err != nil {
+		// Every element is quoted, otherwise separators, braces, white spaces, empty string or NULL change the meaning of the literal.
+		if err = buffer.WriteByte('"'); err != nil {
+			return nil, err
+		}
+		if _, err = arrayStringEscaper.WriteString(&buffer, v); err != nil {
+			return nil, err
+		}
+		if err = buffer.WriteByte('"'); err != nil {
 			return nil, err
 		}
 	}
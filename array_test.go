@@ -94,8 +94,9 @@ func TestArrayInt64_Scan_nil(t *testing.T) {
 
 func TestArrayString_Value(t *testing.T) {
 	success := map[string]pqt.ArrayString{
-		"{1,2,3,4}":             {0: "1", 1: "2", 2: "3", 3: "4"},
-		"{hehe1,string,some,'}": {0: "hehe1", 1: "string", 2: "some", 3: "'"},
+		`{"1","2","3","4"}`:             {0: "1", 1: "2", 2: "3", 3: "4"},
+		`{"hehe1","string","some","'"}`: {0: "hehe1", 1: "string", 2: "some", 3: "'"},
+		`{"a,b","\"quoted\"","","NULL","{x}","back\\slash","white space"}`: {"a,b", `"quoted"`, "", "NULL", "{x}", `back\slash`, "white space"},
 		"{}": {},
 	}
 
@@ -121,7 +122,9 @@ SuccessLoop:
 
 func TestArrayString_Scan(t *testing.T) {
 	success := map[string]pqt.ArrayString{
-		"{1,2,3,4}": {0: "1", 1: "2", 2: "3", 3: "4"},
+		"{1,2,3,4}":                 {0: "1", 1: "2", 2: "3", 3: "4"},
+		`{"a,b","\"q\"","",NULL,x}`: {"a,b", `"q"`, "", "NULL", "x"},
+		"{}":                        {},
 	}
 
 SuccessLoop:
@@ -176,6 +179,38 @@ func TestArrayString_Scan_nil(t *testing.T) {
 	}
 }
 
+func TestArrayString_roundTrip(t *testing.T) {
+	given := pqt.ArrayString{"a", "b", "a,b", `"`, "", "NULL", "{}", `\`}
+	v, err := given.Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	var got pqt.ArrayString
+	if err := got.Scan(v); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !reflect.DeepEqual(given, got) {
+		t.Errorf("unexpected output, expected %v but got %v", given, got)
+	}
+}
+
+func TestArrayInt64_roundTrip(t *testing.T) {
+	given := pqt.ArrayInt64{1, 2}
+	v, err := given.Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	var got pqt.ArrayInt64
+	if err := got.Scan(v); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !reflect.DeepEqual(given, got) {
+		t.Errorf("unexpected output, expected %v but got %v", given, got)
+	}
+}
+
 func TestArrayFloat64_Value(t *testing.T) {
 	success := map[string]pqt.ArrayFloat64{
 		"{1.1,2.2,3.5,4.65}": {0: 1.1, 1: 2.2, 2: 3.5, 3: 4.65},
@@ -0,0 +1,11 @@
+package pqt
+
+import "time"
+
+// Cache is a key value store used by generated cached repositories, e.g. backed by memcached or redis.
+// Implementation has to be safe for concurrent use. Zero ttl means that the value does not expire.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, val []byte, ttl time.Duration)
+	Delete(key string)
+}
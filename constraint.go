@@ -25,27 +25,54 @@ const (
 // ConstraintOption ...
 type ConstraintOption func(*Constraint)
 
+// DeferrableMode determines when deferrable constraint is checked by default, within a transaction it can be changed using SET CONSTRAINTS.
+type DeferrableMode int
+
+const (
+	// DeferrableImmediate checks constraint after each statement.
+	DeferrableImmediate DeferrableMode = iota + 1
+	// DeferrableDeferred checks constraint at the end of the transaction.
+	DeferrableDeferred
+)
+
+// WithDeferrable makes constraint deferrable and initially checked as given mode says.
+// Only unique, primary key, exclusion and foreign key constraints can be deferred.
+func WithDeferrable(initially DeferrableMode) ConstraintOption {
+	return func(c *Constraint) {
+		c.DeferrableInitiallyDeferred = initially == DeferrableDeferred
+		c.DeferrableInitiallyImmediate = initially == DeferrableImmediate
+	}
+}
+
 // Constraint ...
 type Constraint struct {
-	Type, Check                                                          string
+	Type, Check, Where, Identifier, Method                               string
 	Table, ReferenceTable                                                *Table
 	Columns, ReferenceColumns                                            Columns
+	Exclude                                                              []ExcludeElement
 	Attribute                                                            []*Attribute
 	Match, OnDelete, OnUpdate                                            int32
 	NoInherit, DeferrableInitiallyDeferred, DeferrableInitiallyImmediate bool
+	// UniqueIndex and Concurrently apply to indexes only, see NewIndex.
+	UniqueIndex, Concurrently bool
 }
 
-// Name ...
+// Name returns name of the constraint as it is known by the database.
+// If Identifier is set, it is returned as is, otherwise name is derived from the table, columns and type of the constraint.
 func (c *Constraint) Name() string {
+	if c.Identifier != "" {
+		return c.Identifier
+	}
+
 	var schema string
 
 	switch {
 	case c.Table == nil:
 		return "<missing table>"
-	case c.Table.Schema == nil || c.Table.Schema.Name == "":
+	case c.Table.SchemaName() == "":
 		schema = "public"
 	default:
-		schema = c.Table.Schema.Name
+		schema = c.Table.SchemaName()
 	}
 
 	if len(c.Columns) == 0 {
@@ -72,6 +99,17 @@ func Unique(table *Table, columns ...*Column) *Constraint {
 	}
 }
 
+// ConditionalUnique constraint works like Unique, but applies only to rows that satisfy given predicate, e.g. "deleted_at IS NULL".
+// PostgreSQL does not support such constraint directly, so it is created as a partial unique index.
+func ConditionalUnique(table *Table, predicate string, columns ...*Column) *Constraint {
+	return &Constraint{
+		Type:    ConstraintTypeUnique,
+		Table:   table,
+		Columns: columns,
+		Where:   predicate,
+	}
+}
+
 // PrimaryKey constraint is simply a combination of a unique constraint and a not-null constraint.
 func PrimaryKey(table *Table, columns ...*Column) *Constraint {
 	return &Constraint{
@@ -91,6 +129,15 @@ func Check(table *Table, check string, columns ...*Column) *Constraint {
 	}
 }
 
+// NamedCheck works like Check, but the constraint is created using given name instead of generated one.
+// It allows to refer to the constraint independently of the columns it checks, e.g. while handling an error.
+func NamedCheck(table *Table, name, check string, columns ...*Column) *Constraint {
+	c := Check(table, check, columns...)
+	c.Identifier = name
+
+	return c
+}
+
 // Exclusion constraint ensure that if any two rows are compared on the specified columns
 // or expressions using the specified operators,
 // at least one of these operator comparisons will return false or null.
@@ -102,6 +149,45 @@ func Exclusion(table *Table, columns ...*Column) *Constraint {
 	}
 }
 
+// ExcludeElement is a single element of an exclusion constraint, e.g. "period WITH &&".
+type ExcludeElement struct {
+	// Column that is compared, ignored if Expression is set.
+	Column *Column
+	// Expression that is compared instead of a column, e.g. "tstzrange(starts_at, ends_at)".
+	Expression string
+	// Operator used to compare elements of two rows, e.g. "=" or "&&".
+	Operator string
+}
+
+// String implements Stringer interface.
+func (e ExcludeElement) String() string {
+	if e.Expression != "" {
+		return fmt.Sprintf("(%s) WITH %s", e.Expression, e.Operator)
+	}
+	if e.Column == nil {
+		return fmt.Sprintf("<missing column> WITH %s", e.Operator)
+	}
+	return fmt.Sprintf("%s WITH %s", e.Column.Name, e.Operator)
+}
+
+// NamedExclusion works like Exclusion, but elements are compared using given operators and index method, e.g. "gist".
+// Constraint is created using given name, if it is empty, the name is generated.
+func NamedExclusion(table *Table, name, method string, elements ...ExcludeElement) *Constraint {
+	var columns Columns
+	for _, e := range elements {
+		if e.Column != nil && e.Expression == "" {
+			columns = append(columns, e.Column)
+		}
+	}
+
+	c := Exclusion(table, columns...)
+	c.Identifier = name
+	c.Method = method
+	c.Exclude = elements
+
+	return c
+}
+
 // ForeignKey constraint specifies that the values in a column (or a group of columns)
 // must match the values appearing in some row of another table.
 // We say this maintains the referential integrity between two related tables.
@@ -145,6 +231,18 @@ func ForeignKey(table *Table, columns, references Columns, opts ...ConstraintOpt
 	return fk
 }
 
+// Deferrable returns DEFERRABLE clause of the constraint, empty string if constraint is not deferrable.
+func (c *Constraint) Deferrable() string {
+	switch {
+	case c.DeferrableInitiallyDeferred:
+		return "DEFERRABLE INITIALLY DEFERRED"
+	case c.DeferrableInitiallyImmediate:
+		return "DEFERRABLE INITIALLY IMMEDIATE"
+	default:
+		return ""
+	}
+}
+
 // Index ...
 func Index(table *Table, columns ...*Column) *Constraint {
 	return &Constraint{
@@ -154,6 +252,47 @@ func Index(table *Table, columns ...*Column) *Constraint {
 	}
 }
 
+// NewIndex returns index on given columns, created after the table using CREATE INDEX statement once added using Table.AddConstraint.
+// If name is empty, it is generated the same way as for any other constraint.
+func NewIndex(table *Table, name string, columns Columns, opts ...ConstraintOption) *Constraint {
+	idx := Index(table, columns...)
+	idx.Identifier = name
+	for _, o := range opts {
+		o(idx)
+	}
+
+	return idx
+}
+
+// WithIndexUnique makes index reject rows with duplicate values.
+func WithIndexUnique() ConstraintOption {
+	return func(c *Constraint) {
+		c.UniqueIndex = true
+	}
+}
+
+// WithIndexMethod sets index method, e.g. "btree", "hash", "gist", "gin" or "brin".
+func WithIndexMethod(method string) ConstraintOption {
+	return func(c *Constraint) {
+		c.Method = method
+	}
+}
+
+// WithIndexWhere makes index partial, it covers only rows that satisfy given predicate.
+func WithIndexWhere(predicate string) ConstraintOption {
+	return func(c *Constraint) {
+		c.Where = predicate
+	}
+}
+
+// WithIndexConcurrently makes index built without locking out writes to the table.
+// Such statement cannot be executed inside a transaction block.
+func WithIndexConcurrently() ConstraintOption {
+	return func(c *Constraint) {
+		c.Concurrently = true
+	}
+}
+
 // String implements Stringer interface.
 func (c *Constraint) String() string {
 	return c.Name()
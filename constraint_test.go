@@ -21,6 +21,7 @@ func TestConstraint_Name(t *testing.T) {
 		}(), id),
 		"<missing table>": pqt.Check(nil, "a > b", id),
 		"public.news_key": pqt.Unique(pqt.NewTable("news")),
+		"positive_price":  pqt.NamedCheck(pqt.NewTable("product"), "positive_price", "price > 0"),
 	}
 
 	for expected, given := range success {
@@ -32,6 +33,38 @@ func TestConstraint_Name(t *testing.T) {
 	}
 }
 
+func TestExcludeElement_String(t *testing.T) {
+	period := pqt.NewColumn("period", pqt.TypePseudo("TSTZRANGE"))
+	cases := map[string]pqt.ExcludeElement{
+		"period WITH &&": {Column: period, Operator: "&&"},
+		"(tstzrange(starts_at, ends_at)) WITH &&": {Column: period, Expression: "tstzrange(starts_at, ends_at)", Operator: "&&"},
+	}
+
+	for expected, given := range cases {
+		if got := given.String(); got != expected {
+			t.Errorf("wrong output, expected %s got %s", expected, got)
+		}
+	}
+}
+
+func TestNamedExclusion(t *testing.T) {
+	roomID := pqt.NewColumn("room_id", pqt.TypeIntegerBig())
+	period := pqt.NewColumn("period", pqt.TypePseudo("TSTZRANGE"))
+	c := pqt.NamedExclusion(pqt.NewTable("reservation"), "reservation_overlap", "gist",
+		pqt.ExcludeElement{Column: roomID, Operator: "="},
+		pqt.ExcludeElement{Column: period, Operator: "&&"},
+	)
+	if c.Type != pqt.ConstraintTypeExclusion {
+		t.Errorf("wrong type, expected %s but got %s", pqt.ConstraintTypeExclusion, c.Type)
+	}
+	if c.Name() != "reservation_overlap" {
+		t.Errorf("wrong name, expected reservation_overlap but got %s", c.Name())
+	}
+	if len(c.Columns) != 2 {
+		t.Errorf("wrong number of columns, expected %d but got %d", 2, len(c.Columns))
+	}
+}
+
 func TestForeignKey(t *testing.T) {
 	t1 := pqt.NewTable("left")
 	c11 := pqt.NewColumn("id", pqt.TypeSerialBig())
@@ -62,3 +95,37 @@ func TestForeignKey(t *testing.T) {
 		t.Errorf("reference table does not match, expected %v but got %v", t2, cstr.ReferenceTable)
 	}
 }
+
+func TestConstraint_Deferrable(t *testing.T) {
+	id := pqt.NewColumn("id", pqt.TypeSerial())
+	cases := map[string]*pqt.Constraint{
+		"":                               pqt.Unique(pqt.NewTable("user"), id),
+		"DEFERRABLE INITIALLY DEFERRED":  pqt.ForeignKey(nil, pqt.Columns{id}, pqt.Columns{id}, pqt.WithDeferrable(pqt.DeferrableDeferred)),
+		"DEFERRABLE INITIALLY IMMEDIATE": pqt.ForeignKey(nil, pqt.Columns{id}, pqt.Columns{id}, pqt.WithDeferrable(pqt.DeferrableImmediate)),
+	}
+
+	for expected, given := range cases {
+		if got := given.Deferrable(); got != expected {
+			t.Errorf("wrong clause, expected '%s' got '%s'", expected, got)
+		}
+	}
+}
+
+func TestNewIndex(t *testing.T) {
+	tbl := pqt.NewTable("news")
+	title := pqt.NewColumn("title", pqt.TypeText())
+	idx := pqt.NewIndex(tbl, "", pqt.Columns{title}, pqt.WithIndexUnique(), pqt.WithIndexMethod("btree"), pqt.WithIndexWhere("title <> ''"), pqt.WithIndexConcurrently())
+
+	if idx.Type != pqt.ConstraintTypeIndex {
+		t.Errorf("wrong type: %s", idx.Type)
+	}
+	if idx.Name() != "public.news_title_idx" {
+		t.Errorf("wrong name: %s", idx.Name())
+	}
+	if !idx.UniqueIndex || idx.Method != "btree" || idx.Where != "title <> ''" || !idx.Concurrently {
+		t.Errorf("options are not applied: %#v", idx)
+	}
+	if name := pqt.NewIndex(tbl, "news_title", pqt.Columns{title}).Name(); name != "news_title" {
+		t.Errorf("wrong name: %s", name)
+	}
+}
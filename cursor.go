@@ -0,0 +1,71 @@
+package pqt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// CursorPage selects page of results of cursor based pagination, as implemented by generated findPage methods.
+// At most one cursor can be set, if none is, the first page is selected.
+type CursorPage struct {
+	// After selects rows placed after the row the cursor was taken from.
+	After string
+	// Before selects rows placed before the row the cursor was taken from.
+	Before string
+}
+
+// EncodeCursor returns opaque cursor that holds given values of the columns.
+func EncodeCursor(values map[string]interface{}) (string, error) {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("pqt: cursor cannot be encoded: %s", err.Error())
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor returns values of the columns held by given cursor.
+// Numbers are returned as json.Number, so that no precision is lost.
+func DecodeCursor(cursor string) (map[string]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("pqt: malformed cursor: %s", err.Error())
+	}
+
+	var values map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("pqt: malformed cursor: %s", err.Error())
+	}
+
+	return values, nil
+}
+
+// DecodeCursorTo decodes values of the columns held by given cursor into given destinations, keyed by column names.
+// Destination is a pointer to the property the value was taken from, so that value keeps its type, e.g. int64 above 2^53.
+// Every destination has to have its value in the cursor.
+func DecodeCursorTo(cursor string, dest map[string]interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fmt.Errorf("pqt: malformed cursor: %s", err.Error())
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(b, &values); err != nil {
+		return fmt.Errorf("pqt: malformed cursor: %s", err.Error())
+	}
+	for name, d := range dest {
+		v, ok := values[name]
+		if !ok {
+			return fmt.Errorf("pqt: cursor is missing value of column %s", name)
+		}
+		if err := json.Unmarshal(v, d); err != nil {
+			return fmt.Errorf("pqt: malformed cursor value of column %s: %s", name, err.Error())
+		}
+	}
+
+	return nil
+}
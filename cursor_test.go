@@ -0,0 +1,74 @@
+package pqt
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncodeCursor(t *testing.T) {
+	cursor, err := EncodeCursor(map[string]interface{}{"id": int64(9007199254740993), "name": "john"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	got, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	expected := map[string]interface{}{"id": json.Number("9007199254740993"), "name": "john"}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("wrong values, expected %v but got %v", expected, got)
+	}
+}
+
+func TestDecodeCursor_malformed(t *testing.T) {
+	for _, cursor := range []string{"!!!", "bm90IGpzb24"} {
+		if _, err := DecodeCursor(cursor); err == nil {
+			t.Errorf("expected error for cursor %s", cursor)
+		}
+	}
+}
+
+func TestDecodeCursorTo(t *testing.T) {
+	createdAt := time.Date(2017, 1, 2, 3, 4, 5, 6, time.UTC)
+	cursor, err := EncodeCursor(map[string]interface{}{"id": int64(9007199254740993), "created_at": createdAt})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	var (
+		id int64
+		at time.Time
+	)
+	if err := DecodeCursorTo(cursor, map[string]interface{}{"id": &id, "created_at": &at}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if id != 9007199254740993 {
+		t.Errorf("wrong id, expected 9007199254740993 but got %d", id)
+	}
+	if !at.Equal(createdAt) {
+		t.Errorf("wrong created at, expected %s but got %s", createdAt, at)
+	}
+}
+
+func TestDecodeCursorTo_invalid(t *testing.T) {
+	cursor, err := EncodeCursor(map[string]interface{}{"id": "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	var id int64
+	for name, dest := range map[string]map[string]interface{}{
+		"missing": {"name": new(string)},
+		"type":    {"id": &id},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := DecodeCursorTo(cursor, dest); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+	if err := DecodeCursorTo("!!!", map[string]interface{}{"id": &id}); err == nil {
+		t.Error("expected error for malformed cursor")
+	}
+}
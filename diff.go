@@ -0,0 +1,429 @@
+package pqt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Migration is a single, reversible step that transforms one schema into another.
+type Migration struct {
+	Up, Down string
+	// Warning is not empty if migration cannot be applied safely as it is,
+	// for example existing rows need to be backfilled before NOT NULL constraint is added.
+	Warning string
+	// Destructive is true if migration drops a table or a column, data lost that way cannot be restored by Down.
+	Destructive bool
+}
+
+// Diff compares tables, columns and constraints of given schemas and returns migrations that transform old schema into new one.
+// Migrations are ordered, constraints are dropped first and added last, so that foreign keys never refer to missing columns.
+// Column renames are ambiguous, they are detected only if new column is defined using WithRenamedFrom option,
+// otherwise they are reported as a drop and an add of the column.
+// Migrations that drop tables or columns are marked as destructive, so that they can be reviewed before being applied.
+func Diff(old, new *Schema) []Migration {
+	var (
+		// Foreign keys are dropped before and added after other constraints, as they may depend on them.
+		dropFKs, drops, creates, alters, adds, addFKs, removes []Migration
+	)
+
+	drop := func(t *Table, c *Constraint) {
+		m := Migration{Up: dropConstraintQuery(t, c), Down: addConstraintQuery(t, c)}
+		if c.Type == ConstraintTypeForeignKey {
+			dropFKs = append(dropFKs, m)
+		} else {
+			drops = append(drops, m)
+		}
+	}
+	add := func(t *Table, c *Constraint) {
+		m := Migration{Up: addConstraintQuery(t, c), Down: dropConstraintQuery(t, c)}
+		if c.Type == ConstraintTypeForeignKey {
+			addFKs = append(addFKs, m)
+		} else {
+			adds = append(adds, m)
+		}
+	}
+
+	oldTables := tablesByName(old)
+	newTables := tablesByName(new)
+
+	for _, nt := range new.Tables {
+		ot, ok := oldTables[nt.FullName()]
+		// Materialized view cannot be altered, so it is recreated if its query changes.
+		if ok && ot.Query != nt.Query {
+			drops = append(drops, Migration{
+				Up:          dropTableQuery(ot),
+				Down:        createTableQuery(ot),
+				Destructive: !ot.IsMaterializedView(),
+			})
+			ok = false
+		}
+		if !ok {
+			up := createTableQuery(nt)
+			if nt.Namespace != "" {
+				// Schema the table is placed in using WithSchema may not exist yet.
+				up = fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;\n", nt.Namespace) + up
+			}
+			creates = append(creates, Migration{
+				Up:   up,
+				Down: dropTableQuery(nt),
+			})
+			for _, c := range diffConstraints(nil, nt) {
+				add(nt, c)
+			}
+			continue
+		}
+
+		for _, c := range diffConstraints(nt, ot) {
+			drop(ot, c)
+		}
+		// Columns of materialized view are defined by its query.
+		if !nt.IsMaterializedView() {
+			alters = append(alters, diffColumns(ot, nt)...)
+		}
+		for _, c := range diffConstraints(ot, nt) {
+			add(nt, c)
+		}
+	}
+
+	for _, ot := range old.Tables {
+		if _, ok := newTables[ot.FullName()]; ok {
+			continue
+		}
+
+		// Other constraints are dropped together with the table, but foreign keys are dropped upfront,
+		// so that removed tables that refer to each other can be dropped in any order.
+		down := bytes.NewBufferString(createTableQuery(ot))
+		for _, c := range diffConstraints(nil, ot) {
+			if c.Type == ConstraintTypeForeignKey {
+				drop(ot, c)
+				continue
+			}
+			fmt.Fprintf(down, "\n%s", addConstraintQuery(ot, c))
+		}
+		removes = append(removes, Migration{
+			Up:          dropTableQuery(ot),
+			Down:        down.String(),
+			Destructive: true,
+		})
+	}
+
+	var migrations []Migration
+	for _, ms := range [][]Migration{dropFKs, drops, creates, alters, adds, addFKs, removes} {
+		migrations = append(migrations, ms...)
+	}
+
+	return migrations
+}
+
+func diffColumns(ot, nt *Table) []Migration {
+	var migrations []Migration
+
+	oldColumns := make(map[string]*Column, len(ot.Columns))
+	for _, c := range ot.Columns {
+		oldColumns[c.Name] = c
+	}
+	matched := make(map[string]struct{}, len(nt.Columns))
+
+	for _, nc := range nt.Columns {
+		oc, ok := oldColumns[nc.Name]
+		if !ok && nc.RenamedFrom != "" {
+			if oc, ok = oldColumns[nc.RenamedFrom]; ok {
+				migrations = append(migrations, Migration{
+					Up:   fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", nt.FullName(), oc.Name, nc.Name),
+					Down: fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", nt.FullName(), nc.Name, oc.Name),
+				})
+			}
+		}
+		if !ok {
+			m := Migration{
+				Up:   fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", nt.FullName(), columnQuery(nc)),
+				Down: fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", nt.FullName(), nc.Name),
+			}
+			if _, hasDefault := nc.DefaultOn(EventInsert); nc.NotNull && !hasDefault && nc.Generated == "" {
+				m.Warning = fmt.Sprintf("column %s.%s is NOT NULL and has no default, existing rows need to be backfilled", nt.FullName(), nc.Name)
+			}
+			migrations = append(migrations, m)
+			continue
+		}
+		matched[oc.Name] = struct{}{}
+
+		if from, to := columnType(oc), columnType(nc); from != to {
+			migrations = append(migrations, Migration{
+				Up:   fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;", nt.FullName(), nc.Name, to, nc.Name, to),
+				Down: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;", nt.FullName(), nc.Name, from, nc.Name, from),
+			})
+		}
+
+		od, oldHasDefault := oc.DefaultOn(EventInsert)
+		nd, newHasDefault := nc.DefaultOn(EventInsert)
+		if od != nd || oldHasDefault != newHasDefault {
+			migrations = append(migrations, Migration{
+				Up:   alterDefaultQuery(nt, nc.Name, nd, newHasDefault),
+				Down: alterDefaultQuery(nt, nc.Name, od, oldHasDefault),
+			})
+		}
+
+		switch {
+		case nc.NotNull && !oc.NotNull:
+			migrations = append(migrations, Migration{
+				Up:      fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", nt.FullName(), nc.Name),
+				Down:    fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", nt.FullName(), nc.Name),
+				Warning: fmt.Sprintf("column %s.%s becomes NOT NULL, existing rows with NULL value need to be backfilled", nt.FullName(), nc.Name),
+			})
+		case !nc.NotNull && oc.NotNull:
+			migrations = append(migrations, Migration{
+				Up:   fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", nt.FullName(), nc.Name),
+				Down: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", nt.FullName(), nc.Name),
+			})
+		}
+	}
+
+	for _, oc := range ot.Columns {
+		if _, ok := matched[oc.Name]; ok {
+			continue
+		}
+		migrations = append(migrations, Migration{
+			Up:          fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", ot.FullName(), oc.Name),
+			Down:        fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", ot.FullName(), columnQuery(oc)),
+			Destructive: true,
+		})
+	}
+
+	return migrations
+}
+
+// diffConstraints returns constraints of the table b that are not present in the table a.
+// Constraints are compared by name and definition, so modified constraint is returned as well.
+func diffConstraints(a, b *Table) []*Constraint {
+	existing := make(map[string]string)
+	if a != nil {
+		for _, c := range allConstraints(a) {
+			existing[c.Name()] = constraintQuery(c)
+		}
+	}
+
+	var diff []*Constraint
+	for _, c := range allConstraints(b) {
+		if q, ok := existing[c.Name()]; ok && q == constraintQuery(c) {
+			continue
+		}
+		diff = append(diff, c)
+	}
+
+	return diff
+}
+
+func allConstraints(t *Table) []*Constraint {
+	constraints := make([]*Constraint, 0, len(t.Constraints)+len(t.Columns))
+	for _, c := range t.Columns {
+		constraints = append(constraints, c.Constraints()...)
+	}
+
+	return append(constraints, t.Constraints...)
+}
+
+func tablesByName(s *Schema) map[string]*Table {
+	tables := make(map[string]*Table, len(s.Tables))
+	for _, t := range s.Tables {
+		tables[t.FullName()] = t
+	}
+
+	return tables
+}
+
+func columnType(c *Column) string {
+	if mt, ok := c.Type.(MappableType); ok {
+		return mt.From.String()
+	}
+	return c.Type.String()
+}
+
+func alterDefaultQuery(t *Table, column, value string, ok bool) string {
+	if !ok {
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", t.FullName(), column)
+	}
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", t.FullName(), column, value)
+}
+
+func dropTableQuery(t *Table) string {
+	if t.IsMaterializedView() {
+		return fmt.Sprintf("DROP MATERIALIZED VIEW %s;", t.FullName())
+	}
+	return fmt.Sprintf("DROP TABLE %s;", t.FullName())
+}
+
+// createTableQuery returns CREATE TABLE statement without constraints, those are added by separate migrations.
+func createTableQuery(t *Table) string {
+	if t.IsMaterializedView() {
+		return fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS\n%s;", t.FullName(), strings.TrimSuffix(strings.TrimSpace(t.Query), ";"))
+	}
+	if t.IsPartition() {
+		return fmt.Sprintf("CREATE TABLE %s PARTITION OF %s %s;", t.FullName(), t.PartitionOf.FullName(), t.PartitionBounds)
+	}
+
+	buf := bytes.NewBufferString("CREATE ")
+	if t.Temporary {
+		buf.WriteString("TEMPORARY ")
+	}
+	if t.Unlogged {
+		buf.WriteString("UNLOGGED ")
+	}
+	fmt.Fprintf(buf, "TABLE %s (\n", t.FullName())
+	for i, c := range t.Columns {
+		fmt.Fprintf(buf, "\t%s", columnQuery(c))
+		if i < len(t.Columns)-1 {
+			buf.WriteRune(',')
+		}
+		buf.WriteRune('\n')
+	}
+	buf.WriteRune(')')
+	if t.PartitionStrategy != "" {
+		fmt.Fprintf(buf, " PARTITION BY %s (%s)", t.PartitionStrategy, strings.Join(t.PartitionColumns, ", "))
+	}
+	if len(t.StorageParameters) > 0 {
+		params := make([]string, 0, len(t.StorageParameters))
+		for _, p := range t.StorageParameters {
+			params = append(params, p.Key+"="+p.Value)
+		}
+		fmt.Fprintf(buf, " WITH (%s)", strings.Join(params, ", "))
+	}
+	buf.WriteRune(';')
+
+	return buf.String()
+}
+
+func columnQuery(c *Column) string {
+	buf := bytes.NewBufferString(c.Name)
+	buf.WriteRune(' ')
+	buf.WriteString(c.Type.String())
+	if c.Collate != "" {
+		buf.WriteRune(' ')
+		buf.WriteString(c.Collate)
+	}
+	if c.Generated != "" {
+		generation := c.Generation
+		if generation == "" {
+			generation = GeneratedStored
+		}
+		fmt.Fprintf(buf, " GENERATED ALWAYS AS (%s) %s", c.Generated, generation)
+	} else if c.Identity != "" {
+		fmt.Fprintf(buf, " GENERATED %s AS IDENTITY", c.Identity)
+	} else if d, ok := c.DefaultOn(EventInsert); ok {
+		buf.WriteString(" DEFAULT ")
+		buf.WriteString(d)
+	}
+	if c.NotNull {
+		buf.WriteString(" NOT NULL")
+	}
+
+	return buf.String()
+}
+
+// addConstraintQuery returns statement that adds given constraint to the table.
+// Conditional unique constraint is created as a partial unique index.
+func addConstraintQuery(t *Table, c *Constraint) string {
+	if c.Type == ConstraintTypeIndex {
+		return constraintDefinition(c) + ";"
+	}
+	if c.Where != "" {
+		return fmt.Sprintf(`CREATE UNIQUE INDEX "%s" ON %s (%s) WHERE %s;`, c.Name(), t.FullName(), JoinColumns(c.Columns, ", "), c.Where)
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", t.FullName(), constraintQuery(c))
+}
+
+func dropConstraintQuery(t *Table, c *Constraint) string {
+	if c.Where != "" || c.Type == ConstraintTypeIndex {
+		// Index lives in the same schema as its table.
+		if name := t.SchemaName(); name != "" {
+			return fmt.Sprintf(`DROP INDEX %s."%s";`, name, c.Name())
+		}
+		return fmt.Sprintf(`DROP INDEX "%s";`, c.Name())
+	}
+	return fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT "%s";`, t.FullName(), c.Name())
+}
+
+func constraintQuery(c *Constraint) string {
+	if d := c.Deferrable(); d != "" {
+		return constraintDefinition(c) + " " + d
+	}
+	return constraintDefinition(c)
+}
+
+func constraintDefinition(c *Constraint) string {
+	switch c.Type {
+	case ConstraintTypeUnique:
+		// Not a valid table constraint, but allows to detect that predicate changed.
+		if c.Where != "" {
+			return fmt.Sprintf(`CONSTRAINT "%s" UNIQUE (%s) WHERE %s`, c.Name(), JoinColumns(c.Columns, ", "), c.Where)
+		}
+		return fmt.Sprintf(`CONSTRAINT "%s" UNIQUE (%s)`, c.Name(), JoinColumns(c.Columns, ", "))
+	case ConstraintTypePrimaryKey:
+		return fmt.Sprintf(`CONSTRAINT "%s" PRIMARY KEY (%s)`, c.Name(), JoinColumns(c.Columns, ", "))
+	case ConstraintTypeCheck:
+		return fmt.Sprintf(`CONSTRAINT "%s" CHECK (%s)`, c.Name(), c.Check)
+	case ConstraintTypeForeignKey:
+		var ref string
+		if c.ReferenceTable != nil {
+			ref = c.ReferenceTable.FullName()
+		}
+		q := fmt.Sprintf(`CONSTRAINT "%s" FOREIGN KEY (%s) REFERENCES %s (%s)`, c.Name(), JoinColumns(c.Columns, ", "), ref, JoinColumns(c.ReferenceColumns, ", "))
+		if on := referentialAction(c.OnDelete); on != "" {
+			q += " ON DELETE " + on
+		}
+		if on := referentialAction(c.OnUpdate); on != "" {
+			q += " ON UPDATE " + on
+		}
+		return q
+	case ConstraintTypeIndex:
+		q := "CREATE "
+		if c.UniqueIndex {
+			q += "UNIQUE "
+		}
+		q += "INDEX "
+		if c.Concurrently {
+			q += "CONCURRENTLY "
+		}
+		var table string
+		if c.Table != nil {
+			table = c.Table.FullName()
+		}
+		q += fmt.Sprintf(`"%s" ON %s`, c.Name(), table)
+		if c.Method != "" {
+			q += " USING " + c.Method
+		}
+		q += fmt.Sprintf(" (%s)", JoinColumns(c.Columns, ", "))
+		if c.Where != "" {
+			q += " WHERE " + c.Where
+		}
+		return q
+	case ConstraintTypeExclusion:
+		elements := make([]string, 0, len(c.Exclude))
+		for _, e := range c.Exclude {
+			elements = append(elements, e.String())
+		}
+		if c.Method != "" {
+			return fmt.Sprintf(`CONSTRAINT "%s" EXCLUDE USING %s (%s)`, c.Name(), c.Method, strings.Join(elements, ", "))
+		}
+		return fmt.Sprintf(`CONSTRAINT "%s" EXCLUDE (%s)`, c.Name(), strings.Join(elements, ", "))
+	default:
+		return fmt.Sprintf(`CONSTRAINT "%s"`, c.Name())
+	}
+}
+
+func referentialAction(on int32) string {
+	switch on {
+	case Cascade:
+		return "CASCADE"
+	case Restrict:
+		return "RESTRICT"
+	case SetNull:
+		return "SET NULL"
+	case SetDefault:
+		return "SET DEFAULT"
+	case NoAction:
+		return "NO ACTION"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,167 @@
+package pqt_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/piotrkowalczuk/pqt"
+)
+
+func TestDiff(t *testing.T) {
+	oldUserID := pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())
+	oldUser := pqt.NewTable("user").
+		AddColumn(oldUserID).
+		AddColumn(pqt.NewColumn("name", pqt.TypeText())).
+		AddColumn(pqt.NewColumn("age", pqt.TypeInteger()))
+	oldNews := pqt.NewTable("news").
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+		AddColumn(pqt.NewColumn("user_id", pqt.TypeIntegerBig(), pqt.WithReference(oldUserID)))
+	old := pqt.NewSchema("example").AddTable(oldUser).AddTable(oldNews)
+
+	newUser := pqt.NewTable("user").
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+		AddColumn(pqt.NewColumn("username", pqt.TypeVarchar(100), pqt.WithNotNull(), pqt.WithRenamedFrom("name"))).
+		AddColumn(pqt.NewColumn("email", pqt.TypeText(), pqt.WithNotNull(), pqt.WithUnique()))
+	newComment := pqt.NewTable("comment").
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey()))
+	new := pqt.NewSchema("example").AddTable(newUser).AddTable(newComment)
+
+	expected := []pqt.Migration{
+		{
+			Up:   `ALTER TABLE example.news DROP CONSTRAINT "example.news_user_id_fkey";`,
+			Down: `ALTER TABLE example.news ADD CONSTRAINT "example.news_user_id_fkey" FOREIGN KEY (user_id) REFERENCES example.user (id);`,
+		},
+		{
+			Up:   "CREATE TABLE example.comment (\n\tid BIGSERIAL\n);",
+			Down: "DROP TABLE example.comment;",
+		},
+		{
+			Up:      "ALTER TABLE example.user ADD COLUMN email TEXT NOT NULL;",
+			Down:    "ALTER TABLE example.user DROP COLUMN email;",
+			Warning: "column example.user.email is NOT NULL and has no default, existing rows need to be backfilled",
+		},
+		{
+			Up:   "ALTER TABLE example.user RENAME COLUMN name TO username;",
+			Down: "ALTER TABLE example.user RENAME COLUMN username TO name;",
+		},
+		{
+			Up:   "ALTER TABLE example.user ALTER COLUMN username TYPE VARCHAR(100) USING username::VARCHAR(100);",
+			Down: "ALTER TABLE example.user ALTER COLUMN username TYPE TEXT USING username::TEXT;",
+		},
+		{
+			Up:      "ALTER TABLE example.user ALTER COLUMN username SET NOT NULL;",
+			Down:    "ALTER TABLE example.user ALTER COLUMN username DROP NOT NULL;",
+			Warning: "column example.user.username becomes NOT NULL, existing rows with NULL value need to be backfilled",
+		},
+		{
+			Up:          "ALTER TABLE example.user DROP COLUMN age;",
+			Down:        "ALTER TABLE example.user ADD COLUMN age INTEGER;",
+			Destructive: true,
+		},
+		{
+			Up:   `ALTER TABLE example.user ADD CONSTRAINT "example.user_email_key" UNIQUE (email);`,
+			Down: `ALTER TABLE example.user DROP CONSTRAINT "example.user_email_key";`,
+		},
+		{
+			Up:   `ALTER TABLE example.comment ADD CONSTRAINT "example.comment_id_pkey" PRIMARY KEY (id);`,
+			Down: `ALTER TABLE example.comment DROP CONSTRAINT "example.comment_id_pkey";`,
+		},
+		{
+			Up:          "DROP TABLE example.news;",
+			Down:        "CREATE TABLE example.news (\n\tid BIGSERIAL,\n\tuser_id BIGINT\n);\nALTER TABLE example.news ADD CONSTRAINT \"example.news_id_pkey\" PRIMARY KEY (id);",
+			Destructive: true,
+		},
+	}
+
+	got := pqt.Diff(old, new)
+	if len(got) != len(expected) {
+		t.Fatalf("wrong number of migrations, expected %d but got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(expected[i], got[i]) {
+			t.Errorf("wrong migration %d, expected:\n\t%#v\nbut got:\n\t%#v", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestDiff_equal(t *testing.T) {
+	build := func() *pqt.Schema {
+		return pqt.NewSchema("example").AddTable(pqt.NewTable("user").
+			AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+			AddColumn(pqt.NewColumn("name", pqt.TypeText(), pqt.WithNotNull(), pqt.WithDefault("''"))))
+	}
+
+	if got := pqt.Diff(build(), build()); len(got) != 0 {
+		t.Errorf("expected no migrations, got %v", got)
+	}
+}
+
+func TestDiff_index(t *testing.T) {
+	build := func(opts ...pqt.ConstraintOption) *pqt.Schema {
+		title := pqt.NewColumn("title", pqt.TypeText())
+		news := pqt.NewTable("news").AddColumn(title)
+		if opts != nil {
+			news.AddConstraint(pqt.NewIndex(news, "news_title_idx", pqt.Columns{title}, opts...))
+		}
+		return pqt.NewSchema("example").AddTable(news)
+	}
+
+	expected := []pqt.Migration{
+		{
+			Up:   `DROP INDEX example."news_title_idx";`,
+			Down: `CREATE INDEX "news_title_idx" ON example.news (title);`,
+		},
+		{
+			Up:   `CREATE UNIQUE INDEX "news_title_idx" ON example.news USING btree (title);`,
+			Down: `DROP INDEX example."news_title_idx";`,
+		},
+	}
+
+	got := pqt.Diff(build(pqt.WithIndexMethod("")), build(pqt.WithIndexUnique(), pqt.WithIndexMethod("btree")))
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("wrong migrations, expected:\n\t%#v\nbut got:\n\t%#v", expected, got)
+	}
+}
+
+func TestDiff_tableSchema(t *testing.T) {
+	old := pqt.NewSchema("example")
+	new := pqt.NewSchema("example").AddTable(pqt.NewTable("log", pqt.WithSchema("audit")).
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig())))
+
+	expected := []pqt.Migration{
+		{
+			Up:   "CREATE SCHEMA IF NOT EXISTS audit;\nCREATE TABLE audit.log (\n\tid BIGSERIAL\n);",
+			Down: "DROP TABLE audit.log;",
+		},
+	}
+
+	got := pqt.Diff(old, new)
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("wrong migrations, expected:\n\t%#v\nbut got:\n\t%#v", expected, got)
+	}
+}
+
+func TestDiff_materializedView(t *testing.T) {
+	build := func(query string) *pqt.Schema {
+		return pqt.NewSchema("example").AddTable(pqt.NewMaterializedView("stats", query, pqt.NewColumn("total", pqt.TypeIntegerBig())))
+	}
+
+	if got := pqt.Diff(build("SELECT 1 AS total"), build("SELECT 1 AS total")); len(got) != 0 {
+		t.Errorf("expected no migrations, got %v", got)
+	}
+
+	expected := []pqt.Migration{
+		{
+			Up:   "DROP MATERIALIZED VIEW example.stats;",
+			Down: "CREATE MATERIALIZED VIEW example.stats AS\nSELECT 1 AS total;",
+		},
+		{
+			Up:   "CREATE MATERIALIZED VIEW example.stats AS\nSELECT 2 AS total;",
+			Down: "DROP MATERIALIZED VIEW example.stats;",
+		},
+	}
+	got := pqt.Diff(build("SELECT 1 AS total"), build("SELECT 2 AS total"))
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("wrong migrations, expected:\n\t%#v\nbut got:\n\t%#v", expected, got)
+	}
+}
@@ -0,0 +1,224 @@
+package pqt
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SchemaDrift describes single difference between the schema definition and deployed database.
+// Empty Actual means that table or column is missing in the database, empty Expected that it is not part of the definition.
+type SchemaDrift struct {
+	Table, Column    string
+	Expected, Actual string
+}
+
+// String implements fmt.Stringer interface.
+func (sd SchemaDrift) String() string {
+	name := sd.Table
+	if sd.Column != "" {
+		name += "." + sd.Column
+	}
+
+	switch {
+	case sd.Actual == "":
+		return fmt.Sprintf("%s: missing, expected %s", name, sd.Expected)
+	case sd.Expected == "":
+		return fmt.Sprintf("%s: unexpected %s", name, sd.Actual)
+	default:
+		return fmt.Sprintf("%s: expected %s but got %s", name, sd.Expected, sd.Actual)
+	}
+}
+
+// SchemaDriftError is returned by AssertSchemaDeployed if deployed database does not match the schema definition.
+type SchemaDriftError struct {
+	Drifts []SchemaDrift
+}
+
+// Error implements error interface.
+func (e *SchemaDriftError) Error() string {
+	drifts := make([]string, 0, len(e.Drifts))
+	for _, d := range e.Drifts {
+		drifts = append(drifts, d.String())
+	}
+
+	return fmt.Sprintf("pqt: schema drift detected: %s", strings.Join(drifts, "; "))
+}
+
+type deployedColumn struct {
+	udtName  string
+	nullable bool
+}
+
+// AssertSchemaDeployed compares column definitions of the schema tables with those available in information_schema.
+// It checks that each column exists, has expected type and nullability. Columns that are not part of the definition are reported as well.
+// If any difference is found, *SchemaDriftError is returned.
+// Temporary tables and materialized views are skipped, the latter are not listed by information_schema.
+func AssertSchemaDeployed(db *sql.DB, s *Schema) error {
+	// Tables can be placed in schemas other than the one they belong to, see WithSchema.
+	schemas := []interface{}{deployedSchemaName(s.Name)}
+	placeholders := []string{"$1"}
+	for _, t := range s.Tables {
+		name := deployedSchemaName(t.SchemaName())
+		known := false
+		for _, n := range schemas {
+			known = known || n == name
+		}
+		if !known {
+			schemas = append(schemas, name)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(schemas)))
+		}
+	}
+
+	rows, err := db.Query(`SELECT table_schema, table_name, column_name, udt_name, is_nullable FROM information_schema.columns WHERE table_schema IN (`+strings.Join(placeholders, ", ")+`)`, schemas...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	deployed := make(map[string]map[string]deployedColumn)
+	for rows.Next() {
+		var (
+			schema, table, column, udtName, nullable string
+		)
+		if err = rows.Scan(&schema, &table, &column, &udtName, &nullable); err != nil {
+			return err
+		}
+		table = schema + "." + table
+		if _, ok := deployed[table]; !ok {
+			deployed[table] = make(map[string]deployedColumn)
+		}
+		deployed[table][column] = deployedColumn{udtName: udtName, nullable: nullable == "YES"}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if drifts := schemaDrifts(s, deployed); len(drifts) > 0 {
+		return &SchemaDriftError{Drifts: drifts}
+	}
+	return nil
+}
+
+// deployedSchemaName returns name of the schema as listed by information_schema, unnamed schema is the public one.
+func deployedSchemaName(name string) string {
+	if name == "" {
+		return "public"
+	}
+	return name
+}
+
+func schemaDrifts(s *Schema, deployed map[string]map[string]deployedColumn) []SchemaDrift {
+	var drifts []SchemaDrift
+	for _, t := range s.Tables {
+		if t.Temporary || t.IsMaterializedView() {
+			continue
+		}
+		columns, ok := deployed[deployedSchemaName(t.SchemaName())+"."+t.Name]
+		if !ok {
+			drifts = append(drifts, SchemaDrift{Table: t.FullName(), Expected: "table"})
+			continue
+		}
+
+		var pk Columns
+		for _, c := range t.Constraints {
+			if c.Type == ConstraintTypePrimaryKey {
+				pk = c.Columns
+			}
+		}
+
+		defined := make(map[string]struct{}, len(t.Columns))
+		for _, c := range t.Columns {
+			defined[c.Name] = struct{}{}
+
+			expected := deployedColumn{
+				udtName:  udtName(c.Type),
+				nullable: !c.NotNull && !c.PrimaryKey && !pk.Contains(c),
+			}
+			actual, ok := columns[c.Name]
+			switch {
+			case !ok:
+				drifts = append(drifts, SchemaDrift{Table: t.FullName(), Column: c.Name, Expected: expected.String()})
+			case actual != expected:
+				drifts = append(drifts, SchemaDrift{Table: t.FullName(), Column: c.Name, Expected: expected.String(), Actual: actual.String()})
+			}
+		}
+		for _, name := range sortedColumnNames(columns) {
+			if _, ok := defined[name]; !ok {
+				drifts = append(drifts, SchemaDrift{Table: t.FullName(), Column: name, Actual: columns[name].String()})
+			}
+		}
+	}
+
+	return drifts
+}
+
+func (dc deployedColumn) String() string {
+	if dc.nullable {
+		return dc.udtName + " NULL"
+	}
+	return dc.udtName + " NOT NULL"
+}
+
+func sortedColumnNames(columns map[string]deployedColumn) []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// udtName returns name of the underlying type as presented by information_schema.columns.udt_name.
+// Column of domain type is reported with the base type of the domain.
+func udtName(t Type) string {
+	switch tt := t.(type) {
+	case MappableType:
+		return udtName(tt.From)
+	case DomainType:
+		return udtName(tt.Base)
+	}
+
+	name := strings.ToUpper(t.String())
+	var array bool
+	if i := strings.Index(name, "["); i >= 0 {
+		name, array = name[:i], true
+	}
+	if i := strings.Index(name, "("); i >= 0 {
+		name = name[:i]
+	}
+
+	switch name = strings.TrimSpace(name); name {
+	case "BIGINT", "BIGSERIAL":
+		name = "int8"
+	case "INTEGER", "SERIAL":
+		name = "int4"
+	case "SMALLINT", "SMALLSERIAL":
+		name = "int2"
+	case "DOUBLE PRECISION":
+		name = "float8"
+	case "REAL":
+		name = "float4"
+	case "DECIMAL", "NUMERIC":
+		name = "numeric"
+	case "BOOL":
+		name = "bool"
+	case "VARCHAR":
+		name = "varchar"
+	case "CHARACTER":
+		name = "bpchar"
+	default:
+		// Schema prefix of user defined types is not part of udt_name.
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		name = strings.ToLower(name)
+	}
+
+	if array {
+		return "_" + name
+	}
+	return name
+}
@@ -0,0 +1,72 @@
+package pqt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchemaDrifts(t *testing.T) {
+	user := NewTable("user").
+		AddColumn(NewColumn("id", TypeSerialBig(), WithPrimaryKey())).
+		AddColumn(NewColumn("name", TypeText(), WithNotNull())).
+		AddColumn(NewColumn("tags", TypeArray(TypeText()))).
+		AddColumn(NewColumn("age", TypeInteger())).
+		AddColumn(NewColumn("title", TypeDomain("title", TypeText(), "")))
+	news := NewTable("news").
+		AddColumn(NewColumn("id", TypeSerialBig(), WithPrimaryKey()))
+	log := NewTable("log", WithSchema("audit")).
+		AddColumn(NewColumn("id", TypeSerialBig(), WithPrimaryKey()))
+	s := NewSchema("example").AddTable(user).AddTable(news).AddTable(log)
+
+	deployed := map[string]map[string]deployedColumn{
+		"example.user": {
+			"id":      {udtName: "int8"},
+			"name":    {udtName: "text", nullable: true},
+			"tags":    {udtName: "_text", nullable: true},
+			"created": {udtName: "timestamptz"},
+			"title":   {udtName: "text", nullable: true},
+		},
+		"audit.log": {
+			"id": {udtName: "int8"},
+		},
+	}
+
+	got := schemaDrifts(s, deployed)
+	expected := []SchemaDrift{
+		{Table: "example.user", Column: "age", Expected: "int4 NULL"},
+		{Table: "example.user", Column: "name", Expected: "text NOT NULL", Actual: "text NULL"},
+		{Table: "example.user", Column: "created", Actual: "timestamptz NOT NULL"},
+		{Table: "example.news", Expected: "table"},
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("wrong drifts, expected:\n\t%v\nbut got:\n\t%v", expected, got)
+	}
+
+	err := &SchemaDriftError{Drifts: got}
+	if err.Error() == "" {
+		t.Error("error message should not be empty")
+	}
+}
+
+func TestUdtName(t *testing.T) {
+	cases := map[string]struct {
+		given    Type
+		expected string
+	}{
+		"bigserial":        {given: TypeSerialBig(), expected: "int8"},
+		"varchar":          {given: TypeVarchar(100), expected: "varchar"},
+		"decimal":          {given: TypeDecimal(10, 2), expected: "numeric"},
+		"timestamp":        {given: TypeTimestampTZ(), expected: "timestamptz"},
+		"text-array":       {given: TypeArray(TypeText()), expected: "_text"},
+		"enum":             {given: TypeEnumerated("example.status", "on", "off"), expected: "status"},
+		"domain":           {given: TypeDomain("title", TypeText(), ""), expected: "text"},
+		"domain-of-domain": {given: TypeDomain("short_title", TypeDomain("title", TypeVarchar(100), ""), ""), expected: "varchar"},
+		"mappable-domain":  {given: TypeMappable(TypeDomain("amount", TypeNumeric(10, 2), "VALUE >= 0")), expected: "numeric"},
+	}
+
+	for hint, c := range cases {
+		if got := udtName(c.given); got != c.expected {
+			t.Errorf("%s: wrong udt name, expected %s but got %s", hint, c.expected, got)
+		}
+	}
+}
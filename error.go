@@ -1,15 +1,109 @@
 package pqt
 
-import "github.com/lib/pq"
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// ErrNotFound is returned by generated findOne methods if no row matches given criteria.
+// It wraps sql.ErrNoRows, so errors.Is reports true for both of them.
+var ErrNotFound = fmt.Errorf("pqt: not found: %w", sql.ErrNoRows)
+
+// ErrMultipleRows is returned by generated findOne methods if more than one row match given criteria.
+var ErrMultipleRows = errors.New("pqt: multiple rows found")
+
+// ErrDeleteWithoutCriteria is returned by generated deleteByCriteria methods if criteria is empty and full scan is not allowed.
+var ErrDeleteWithoutCriteria = errors.New("pqt: refusing to delete without criteria")
+
+// ErrVersionConflict is returned by generated update methods of a table with version column,
+// if the row does not exist or its version differs from the one given in the patch, usually because it was modified concurrently.
+var ErrVersionConflict = errors.New("pqt: version conflict")
+
+// ErrNothingToUpdate is returned by generated update and patch methods if the patch does not hold any value to set.
+var ErrNothingToUpdate = errors.New("pqt: nothing to update")
+
+// Violation describes single reason why entity cannot be stored in the database.
+type Violation struct {
+	Column, Reason string
+}
+
+// ValidationError is returned by generated validate methods, it lists every violation found, not only the first one.
+type ValidationError struct {
+	Table      string
+	Violations []Violation
+}
+
+// Error implements error interface.
+func (e *ValidationError) Error() string {
+	buf := bytes.NewBufferString("pqt: validation failure of ")
+	buf.WriteString(e.Table)
+	buf.WriteString(": ")
+	for i, v := range e.Violations {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(v.Column)
+		buf.WriteString(" ")
+		buf.WriteString(v.Reason)
+	}
+
+	return buf.String()
+}
+
+// Error is a database error produced by the pq library, reduced to properties that matter for error handling.
+// Original error is available using Unwrap, so errors.Is and errors.As keep working.
+type Error struct {
+	// Code is the SQLSTATE code, e.g. 23505 for unique violation or 40001 for serialization failure.
+	Code                              string
+	Schema, Table, Column, Constraint string
+
+	err *pq.Error
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns original error.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// AsError finds the first error in err chain that was produced by the pq library and converts it into Error.
+// Otherwise, it returns false.
+func AsError(err error) (*Error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	var pqerr *pq.Error
+	if !errors.As(err, &pqerr) {
+		return nil, false
+	}
+
+	return &Error{
+		Code:       string(pqerr.Code),
+		Schema:     pqerr.Schema,
+		Table:      pqerr.Table,
+		Column:     pqerr.Column,
+		Constraint: pqerr.Constraint,
+		err:        pqerr,
+	}, true
+}
 
 // ErrorConstraint returns the error constraint of err if it was produced by the pq library.
 // Otherwise, it returns empty string.
 func ErrorConstraint(err error) string {
-	if err == nil {
-		return ""
-	}
-	if pqerr, ok := err.(*pq.Error); ok {
-		return pqerr.Constraint
+	if e, ok := AsError(err); ok {
+		return e.Constraint
 	}
 
 	return ""
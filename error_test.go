@@ -1,6 +1,7 @@
 package pqt
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"testing"
@@ -51,3 +52,115 @@ func TestErrorConstraint_nonSQL(t *testing.T) {
 		t.Fatalf("wrong constraint, expected empty string but got %s", got)
 	}
 }
+
+func TestErrorConstraint_checkViolation(t *testing.T) {
+	expected := "positive_price"
+	err := &pq.Error{
+		Code:       "23514",
+		Constraint: expected,
+	}
+	got := ErrorConstraint(err)
+	if got != expected {
+		t.Fatalf("wrong constraint, expected %s but got %s", expected, got)
+	}
+}
+
+func TestErrorConstraint_exclusionViolation(t *testing.T) {
+	expected := "reservation_overlap"
+	err := &pq.Error{
+		Code:       "23P01",
+		Constraint: expected,
+	}
+	got := ErrorConstraint(err)
+	if got != expected {
+		t.Fatalf("wrong constraint, expected %s but got %s", expected, got)
+	}
+}
+
+func TestErrorConstraint_wrapped(t *testing.T) {
+	expected := "something"
+	err := fmt.Errorf("insert: %w", &pq.Error{
+		Constraint: expected,
+	})
+	got := ErrorConstraint(err)
+	if got != expected {
+		t.Fatalf("wrong constraint, expected %s but got %s", expected, got)
+	}
+}
+
+func TestAsError(t *testing.T) {
+	pqerr := &pq.Error{
+		Code:       "23505",
+		Message:    "duplicate key value violates unique constraint",
+		Schema:     "example",
+		Table:      "user",
+		Column:     "username",
+		Constraint: "example.user_username_key",
+	}
+	e, ok := AsError(fmt.Errorf("insert: %w", pqerr))
+	if !ok {
+		t.Fatal("expected error to be converted")
+	}
+	if e.Code != "23505" || e.Schema != "example" || e.Table != "user" || e.Column != "username" || e.Constraint != "example.user_username_key" {
+		t.Errorf("wrong error: %#v", e)
+	}
+	if e.Error() != pqerr.Error() {
+		t.Errorf("wrong message, expected %s but got %s", pqerr.Error(), e.Error())
+	}
+	if !errors.Is(e, pqerr) {
+		t.Error("expected error to wrap original one")
+	}
+
+	var got *Error
+	if !errors.As(fmt.Errorf("retry: %w", e), &got) || got != e {
+		t.Error("expected error to be found using errors.As")
+	}
+	if again, ok := AsError(e); !ok || again != e {
+		t.Error("expected the same error to be returned")
+	}
+}
+
+func TestAsError_nonSQL(t *testing.T) {
+	if _, ok := AsError(errors.New("normal error")); ok {
+		t.Error("unexpected conversion of normal error")
+	}
+	if _, ok := AsError(nil); ok {
+		t.Error("unexpected conversion of nil error")
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := &ValidationError{
+		Table: "public.user",
+		Violations: []Violation{
+			{Column: "username", Reason: "is required"},
+			{Column: "password", Reason: "cannot be null"},
+		},
+	}
+	expected := "pqt: validation failure of public.user: username is required, password cannot be null"
+	if got := err.Error(); got != expected {
+		t.Errorf("wrong message, expected %s but got %s", expected, got)
+	}
+}
+
+func TestErrNotFound(t *testing.T) {
+	err := fmt.Errorf("find one: %w", ErrNotFound)
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("expected error to be ErrNotFound")
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Error("expected error to wrap sql.ErrNoRows")
+	}
+	if errors.Is(errors.New("connection refused"), ErrNotFound) {
+		t.Error("unexpected match of unrelated error")
+	}
+}
+
+func TestErrNothingToUpdate(t *testing.T) {
+	if ErrNothingToUpdate.Error() != "pqt: nothing to update" {
+		t.Errorf("wrong message: %s", ErrNothingToUpdate.Error())
+	}
+	if !errors.Is(fmt.Errorf("patch: %w", ErrNothingToUpdate), ErrNothingToUpdate) {
+		t.Error("expected error to be ErrNothingToUpdate")
+	}
+}
@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"math"
 
@@ -62,15 +64,19 @@ func main() {
 			log:     log,
 		},
 		category: categoryRepositoryBase{
-			db:      db,
-			table:   tableCategory,
-			columns: tableCategoryColumns,
-			dbg:     true,
-			log:     log,
+			db:       db,
+			table:    tableCategory,
+			columns:  tableCategoryColumns,
+			dbg:      true,
+			log:      log,
+			bulkSize: 10,
 		},
 	}
 
-	count, err := repo.news.count(&newsCriteria{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count, err := repo.news.countContext(ctx, &newsCriteria{})
 	if err != nil {
 		sklog.Fatal(log, err)
 	}
@@ -91,27 +97,42 @@ func main() {
 	}
 
 	nb := 20
+	comments := make([]*commentEntity, 0, nb)
 	for i := 0; i < nb; i++ {
-		_, err = repo.comment.insert(&commentEntity{
+		comments = append(comments, &commentEntity{
 			newsID:    news.id,
 			newsTitle: news.title,
 			content:   "Etiam eget nunc vel tellus placerat accumsan. Quisque dictum commodo orci, a eleifend nulla viverra malesuada. Etiam dui purus, dapibus a risus sed, porta scelerisque lorem. Sed vehicula mauris tellus, at dapibus risus facilisis vitae. Sed at lacus mollis, cursus sapien eu, egestas ligula. Cras blandit, arcu quis aliquam dictum, nibh purus pulvinar turpis, in dapibus est nibh et enim. Donec ex arcu, iaculis eget euismod id, lobortis nec enim. Quisque sed massa vel dui convallis ultrices. Nulla rutrum sed lacus vel ornare. Aliquam vulputate condimentum elit at pellentesque. Curabitur vitae sem tincidunt, volutpat urna ut, consequat turpis. Pellentesque varius justo libero, a volutpat lacus vulputate at. Integer tristique pharetra urna vel pharetra. In porttitor tincidunt eros, vel eleifend quam elementum a.",
 		})
-		if err != nil {
-			sklog.Fatal(log, err)
-		}
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		sklog.Fatal(log, err)
+	}
+	if _, err = repo.comment.withTx(tx).insertBatch(comments); err != nil {
+		tx.Rollback()
+		sklog.Fatal(log, err)
+	}
+	if err = tx.Commit(); err != nil {
+		sklog.Fatal(log, err)
 	}
 
+	count, err = repo.comment.count(&commentCriteria{countDistinct: tableCommentColumnNewsID})
+	if err != nil {
+		sklog.Fatal(log, err)
+	}
+	sklog.Debug(log, "number of commented news", "count", count)
+
 	iter, err := repo.comment.findIter(&commentCriteria{
 		newsID: qtypes.EqualInt64(news.id),
 		sort: map[string]bool{
 			"id": false,
-			"non_existing_column": true,
 		},
 	})
 	if err != nil {
 		sklog.Fatal(log, err)
 	}
+	defer iter.Close()
 	got := 0
 	for iter.Next() {
 		com, err := iter.Comment()
@@ -159,6 +180,20 @@ func main() {
 		sklog.Info(log, "proper number of categories")
 	}
 
+	bulk := make([]*categoryEntity, 0, nb)
+	for i := 0; i < nb; i++ {
+		bulk = append(bulk, &categoryEntity{
+			name: "bulk_category" + strconv.Itoa(i),
+		})
+	}
+	loaded, err := repo.category.bulkInsert(bulk)
+	if err != nil {
+		sklog.Fatal(log, err)
+	}
+	if loaded != int64(nb) {
+		sklog.Fatal(log, fmt.Errorf("wrong number of loaded categories, expected %d but got %d", nb, loaded))
+	}
+
 	_, err = repo.category.insert(&categoryEntity{
 		parentID: &ntypes.Int64{Int64: int64(math.MaxInt64 - 1), Valid: true},
 		name:     "does not work",
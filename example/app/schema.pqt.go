@@ -5,7 +5,10 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -13,16 +16,55 @@ import (
 
 	"github.com/go-kit/kit/log"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/lib/pq"
 	"github.com/piotrkowalczuk/ntypes"
 	"github.com/piotrkowalczuk/pqcomp"
+	"github.com/piotrkowalczuk/pqt"
 	"github.com/piotrkowalczuk/pqt/pqtgo"
 	"github.com/piotrkowalczuk/qtypes"
 )
 
+type newsStatus string
+
+const (
+	newsStatusDraft     newsStatus = "draft"
+	newsStatusPublished newsStatus = "published"
+)
+
+// Value implements driver.Valuer interface.
+func (e newsStatus) Value() (driver.Value, error) {
+	return string(e), nil
+}
+
+// Scan implements sql.Scanner interface.
+func (e *newsStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = newsStatus(s)
+	case string:
+		*e = newsStatus(s)
+	default:
+		return fmt.Errorf("newsStatus: expected slice of bytes or string as a source argument in Scan, not %T", src)
+	}
+
+	return nil
+}
+
+// valid returns true if value is one of the values of the enumerated type.
+func (e newsStatus) valid() bool {
+	switch e {
+	case newsStatusDraft, newsStatusPublished:
+		return true
+	default:
+		return false
+	}
+}
+
 const (
 	tableCategory                             = "example.category"
 	tableCategoryColumnContent                = "content"
 	tableCategoryColumnCreatedAt              = "created_at"
+	tableCategoryColumnDeletedAt              = "deleted_at"
 	tableCategoryColumnID                     = "id"
 	tableCategoryColumnName                   = "name"
 	tableCategoryColumnParentID               = "parent_id"
@@ -35,6 +77,7 @@ var (
 	tableCategoryColumns = []string{
 		tableCategoryColumnContent,
 		tableCategoryColumnCreatedAt,
+		tableCategoryColumnDeletedAt,
 		tableCategoryColumnID,
 		tableCategoryColumnName,
 		tableCategoryColumnParentID,
@@ -47,6 +90,8 @@ type categoryEntity struct {
 	content string
 	// createdAt ...
 	createdAt time.Time
+	// deletedAt ...
+	deletedAt *time.Time
 	// id ...
 	id int64
 	// name ...
@@ -69,6 +114,8 @@ func (e *categoryEntity) prop(cn string) (interface{}, bool) {
 		return &e.content, true
 	case tableCategoryColumnCreatedAt:
 		return &e.createdAt, true
+	case tableCategoryColumnDeletedAt:
+		return &e.deletedAt, true
 	case tableCategoryColumnID:
 		return &e.id, true
 	case tableCategoryColumnName:
@@ -93,18 +140,168 @@ func (e *categoryEntity) props(cns ...string) ([]interface{}, error) {
 	}
 	return res, nil
 }
+func (e *categoryEntity) value(cn string) (interface{}, bool) {
+	switch cn {
+	case tableCategoryColumnContent:
+		return e.content, true
+	case tableCategoryColumnCreatedAt:
+		return e.createdAt, true
+	case tableCategoryColumnDeletedAt:
+		return e.deletedAt, true
+	case tableCategoryColumnID:
+		return e.id, true
+	case tableCategoryColumnName:
+		return e.name, true
+	case tableCategoryColumnParentID:
+		return e.parentID, true
+	case tableCategoryColumnUpdatedAt:
+		return e.updatedAt, true
+	default:
+		return nil, false
+	}
+}
+func (e *categoryEntity) values(cns ...string) ([]interface{}, error) {
+
+	res := make([]interface{}, 0, len(cns))
+	for _, cn := range cns {
+		if value, ok := e.value(cn); ok {
+			res = append(res, value)
+		} else {
+			return nil, fmt.Errorf("unexpected column provided: %s", cn)
+		}
+	}
+	return res, nil
+}
+
+// toMap returns values of the entity keyed by column names, nullable properties are either underlying value or nil.
+func (e *categoryEntity) toMap() map[string]interface{} {
+	m := make(map[string]interface{}, 7)
+	m[tableCategoryColumnContent] = e.content
+	m[tableCategoryColumnCreatedAt] = e.createdAt
+	if e.deletedAt != nil {
+		m[tableCategoryColumnDeletedAt] = *e.deletedAt
+	} else {
+		m[tableCategoryColumnDeletedAt] = nil
+	}
+	m[tableCategoryColumnID] = e.id
+	m[tableCategoryColumnName] = e.name
+	if e.parentID != nil && e.parentID.Valid {
+		m[tableCategoryColumnParentID] = e.parentID.Int64
+	} else {
+		m[tableCategoryColumnParentID] = nil
+	}
+	if e.updatedAt != nil {
+		m[tableCategoryColumnUpdatedAt] = *e.updatedAt
+	} else {
+		m[tableCategoryColumnUpdatedAt] = nil
+	}
+
+	return m
+}
+
+// categoryEntityFromMap returns entity built from given values keyed by column names, missing columns are left unset.
+// Error is returned if column is unknown or its value is not of the expected type, nil is accepted by nullable columns only.
+func categoryEntityFromMap(m map[string]interface{}) (*categoryEntity, error) {
+	e := &categoryEntity{}
+	for cn, v := range m {
+		switch cn {
+		case tableCategoryColumnContent:
+			if tv, ok := v.(string); ok {
+				e.content = tv
+				continue
+			}
+			return nil, fmt.Errorf("category from map failure, column %s expects value of type string, got %T", cn, v)
+		case tableCategoryColumnCreatedAt:
+			if tv, ok := v.(time.Time); ok {
+				e.createdAt = tv
+				continue
+			}
+			return nil, fmt.Errorf("category from map failure, column %s expects value of type time.Time, got %T", cn, v)
+		case tableCategoryColumnDeletedAt:
+			if v == nil {
+				e.deletedAt = nil
+				continue
+			}
+			if tv, ok := v.(time.Time); ok {
+				e.deletedAt = &tv
+				continue
+			}
+			return nil, fmt.Errorf("category from map failure, column %s expects value of type time.Time, got %T", cn, v)
+		case tableCategoryColumnID:
+			if tv, ok := v.(int64); ok {
+				e.id = tv
+				continue
+			}
+			return nil, fmt.Errorf("category from map failure, column %s expects value of type int64, got %T", cn, v)
+		case tableCategoryColumnName:
+			if tv, ok := v.(string); ok {
+				e.name = tv
+				continue
+			}
+			return nil, fmt.Errorf("category from map failure, column %s expects value of type string, got %T", cn, v)
+		case tableCategoryColumnParentID:
+			if v == nil {
+				e.parentID = nil
+				continue
+			}
+			if tv, ok := v.(int64); ok {
+				e.parentID = &ntypes.Int64{Int64: tv, Valid: true}
+				continue
+			}
+			return nil, fmt.Errorf("category from map failure, column %s expects value of type int64, got %T", cn, v)
+		case tableCategoryColumnUpdatedAt:
+			if v == nil {
+				e.updatedAt = nil
+				continue
+			}
+			if tv, ok := v.(time.Time); ok {
+				e.updatedAt = &tv
+				continue
+			}
+			return nil, fmt.Errorf("category from map failure, column %s expects value of type time.Time, got %T", cn, v)
+		default:
+			return nil, fmt.Errorf("category from map failure, unexpected column provided: %s", cn)
+		}
+	}
+
+	return e, nil
+}
+
+// validate returns pqt.ValidationError if any of mandatory properties is not set.
+func (e *categoryEntity) validate() error {
+	var violations []pqt.Violation
+	if e.content == "" {
+		violations = append(violations, pqt.Violation{Column: tableCategoryColumnContent, Reason: "is required"})
+	}
+	if e.name == "" {
+		violations = append(violations, pqt.Violation{Column: tableCategoryColumnName, Reason: "is required"})
+	}
+	if len(violations) > 0 {
+		return &pqt.ValidationError{Table: tableCategory, Violations: violations}
+	}
+
+	return nil
+}
 
 // categoryIterator is not thread safe.
+// It has to be closed once it is no longer needed, otherwise underlying connection is not released.
 type categoryIterator struct {
 	rows *sql.Rows
 	cols []string
+	// cancel releases context the rows are read within, if the iterator owns it.
+	cancel context.CancelFunc
 }
 
+var _ pqtgo.Iterator = &categoryIterator{}
+
 func (i *categoryIterator) Next() bool {
 	return i.rows.Next()
 }
 
 func (i *categoryIterator) Close() error {
+	if i.cancel != nil {
+		defer i.cancel()
+	}
 	return i.rows.Close()
 }
 
@@ -124,8 +321,8 @@ func (i *categoryIterator) Columns() ([]string, error) {
 	return i.cols, nil
 }
 
-// Ent is wrapper around category method that makes iterator more generic.
-func (i *categoryIterator) Ent() (interface{}, error) {
+// Ent is wrapper around category method, it allows to use the same method name regardless of the table.
+func (i *categoryIterator) Ent() (*categoryEntity, error) {
 	return i.Category()
 }
 
@@ -146,15 +343,29 @@ func (i *categoryIterator) Category() (*categoryEntity, error) {
 	return &ent, nil
 }
 
+// categoryCriteria selects entities that queries of the repository operate on, its zero value selects all of them.
+// Offset and Limit are translated into OFFSET and LIMIT clauses if non-zero, lock appends locking clause that is effective within a transaction only.
+// Keys of sort have to be columns of the table.
+// Expressions of sortExpr precede sort, they can refer to columns of the table and call functions listed in pqt.SortFunctions.
+// Entities marked as deleted are skipped unless includeDeleted is set.
 type categoryCriteria struct {
-	offset, limit int64
-	sort          map[string]bool
-	content       *qtypes.String
-	createdAt     *qtypes.Timestamp
-	id            *qtypes.Int64
-	name          *qtypes.String
-	parentID      *qtypes.Int64
-	updatedAt     *qtypes.Timestamp
+	Offset, Limit   int64
+	sort            map[string]bool
+	sortExpr        []pqt.SortExpr
+	countDistinct   string
+	lock            pqt.LockMode
+	lateral         []pqt.LateralJoin
+	includeDeleted  bool
+	content         *qtypes.String
+	createdAt       *qtypes.Timestamp
+	createdAtBounds pqt.RangeBounds
+	deletedAt       *qtypes.Timestamp
+	deletedAtBounds pqt.RangeBounds
+	id              *qtypes.Int64
+	name            *qtypes.String
+	parentID        *qtypes.Int64
+	updatedAt       *qtypes.Timestamp
+	updatedAtBounds pqt.RangeBounds
 }
 
 func (c *categoryCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *pqtgo.CompositionOpts) (err error) {
@@ -164,119 +375,243 @@ func (c *categoryCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt
 	}
 
 	if c.createdAt != nil && c.createdAt.Valid {
-		createdAtt1 := c.createdAt.Value()
-		if createdAtt1 != nil {
-			createdAt1, err := ptypes.Timestamp(createdAtt1)
-			if err != nil {
-				return err
+		switch c.createdAt.Type {
+		case qtypes.QueryType_NULL:
+			if com.Dirty {
+				com.WriteString(" AND ")
 			}
-			switch c.createdAt.Type {
-			case qtypes.QueryType_NULL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+			com.Dirty = true
 
-				com.WriteString(tableCategoryColumnCreatedAt)
-				if c.createdAt.Negation {
-					com.WriteString(" IS NOT NULL ")
-				} else {
-					com.WriteString(" IS NULL ")
-				}
-			case qtypes.QueryType_EQUAL:
+			com.WriteString(tableCategoryColumnCreatedAt)
+			if c.createdAt.Negation {
+				com.WriteString(" IS NOT NULL ")
+			} else {
+				com.WriteString(" IS NULL ")
+			}
+		case qtypes.QueryType_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnCreatedAt)
+			if c.createdAt.Negation {
+				com.WriteString(" <> ")
+			} else {
+				com.WriteString(" = ")
+			}
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_GREATER:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnCreatedAt)
+			com.WriteString(">")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_GREATER_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnCreatedAt)
+			com.WriteString(">=")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_LESS:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnCreatedAt)
+			com.WriteString(" < ")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_LESS_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnCreatedAt)
+			com.WriteString(" <= ")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_IN:
+			if len(c.createdAt.Values) > 0 {
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
 				com.WriteString(tableCategoryColumnCreatedAt)
-				if c.createdAt.Negation {
-					com.WriteString(" <> ")
-				} else {
-					com.WriteString(" = ")
+				com.WriteString(" IN (")
+				for i, v := range c.createdAt.Values {
+					if i != 0 {
+						com.WriteString(", ")
+					}
+					com.WritePlaceholder()
+					com.Add(v)
+				}
+				com.WriteString(") ")
+			}
+		case qtypes.QueryType_BETWEEN:
+			// Missing bound leaves the range open on that side.
+			if len(c.createdAt.Values) > 0 && c.createdAt.Values[0] != nil {
+				createdAt1, err := ptypes.Timestamp(c.createdAt.Values[0])
+				if err != nil {
+					return err
 				}
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_GREATER:
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
 				com.WriteString(tableCategoryColumnCreatedAt)
-				com.WriteString(">")
+				com.WriteString(" " + c.createdAtBounds.LowerOperator() + " ")
 				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_GREATER_EQUAL:
+				com.Add(createdAt1)
+			}
+			if len(c.createdAt.Values) > 1 && c.createdAt.Values[1] != nil {
+				createdAt2, err := ptypes.Timestamp(c.createdAt.Values[1])
+				if err != nil {
+					return err
+				}
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
 				com.WriteString(tableCategoryColumnCreatedAt)
-				com.WriteString(">=")
+				com.WriteString(" " + c.createdAtBounds.UpperOperator() + " ")
 				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_LESS:
+				com.Add(createdAt2)
+			}
+		}
+	}
+
+	if c.deletedAt != nil && c.deletedAt.Valid {
+		switch c.deletedAt.Type {
+		case qtypes.QueryType_NULL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnDeletedAt)
+			if c.deletedAt.Negation {
+				com.WriteString(" IS NOT NULL ")
+			} else {
+				com.WriteString(" IS NULL ")
+			}
+		case qtypes.QueryType_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnDeletedAt)
+			if c.deletedAt.Negation {
+				com.WriteString(" <> ")
+			} else {
+				com.WriteString(" = ")
+			}
+			com.WritePlaceholder()
+			com.Add(c.deletedAt.Value())
+		case qtypes.QueryType_GREATER:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnDeletedAt)
+			com.WriteString(">")
+			com.WritePlaceholder()
+			com.Add(c.deletedAt.Value())
+		case qtypes.QueryType_GREATER_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnDeletedAt)
+			com.WriteString(">=")
+			com.WritePlaceholder()
+			com.Add(c.deletedAt.Value())
+		case qtypes.QueryType_LESS:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnDeletedAt)
+			com.WriteString(" < ")
+			com.WritePlaceholder()
+			com.Add(c.deletedAt.Value())
+		case qtypes.QueryType_LESS_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnDeletedAt)
+			com.WriteString(" <= ")
+			com.WritePlaceholder()
+			com.Add(c.deletedAt.Value())
+		case qtypes.QueryType_IN:
+			if len(c.deletedAt.Values) > 0 {
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
-				com.WriteString(tableCategoryColumnCreatedAt)
-				com.WriteString(" < ")
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_LESS_EQUAL:
+				com.WriteString(tableCategoryColumnDeletedAt)
+				com.WriteString(" IN (")
+				for i, v := range c.deletedAt.Values {
+					if i != 0 {
+						com.WriteString(", ")
+					}
+					com.WritePlaceholder()
+					com.Add(v)
+				}
+				com.WriteString(") ")
+			}
+		case qtypes.QueryType_BETWEEN:
+			// Missing bound leaves the range open on that side.
+			if len(c.deletedAt.Values) > 0 && c.deletedAt.Values[0] != nil {
+				deletedAt1, err := ptypes.Timestamp(c.deletedAt.Values[0])
+				if err != nil {
+					return err
+				}
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
-				com.WriteString(tableCategoryColumnCreatedAt)
-				com.WriteString(" <= ")
+				com.WriteString(tableCategoryColumnDeletedAt)
+				com.WriteString(" " + c.deletedAtBounds.LowerOperator() + " ")
 				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_IN:
-				if len(c.createdAt.Values) > 0 {
-					if com.Dirty {
-						com.WriteString(" AND ")
-					}
-					com.Dirty = true
-
-					com.WriteString(tableCategoryColumnCreatedAt)
-					com.WriteString(" IN (")
-					for i, v := range c.createdAt.Values {
-						if i != 0 {
-							com.WriteString(", ")
-						}
-						com.WritePlaceholder()
-						com.Add(v)
-					}
-					com.WriteString(") ")
+				com.Add(deletedAt1)
+			}
+			if len(c.deletedAt.Values) > 1 && c.deletedAt.Values[1] != nil {
+				deletedAt2, err := ptypes.Timestamp(c.deletedAt.Values[1])
+				if err != nil {
+					return err
 				}
-			case qtypes.QueryType_BETWEEN:
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
-				createdAtt2 := c.createdAt.Values[1]
-				if createdAtt2 != nil {
-					createdAt2, err := ptypes.Timestamp(createdAtt2)
-					if err != nil {
-						return err
-					}
-					com.WriteString(tableCategoryColumnCreatedAt)
-					com.WriteString(" > ")
-					com.WritePlaceholder()
-					com.Add(createdAt1)
-					com.WriteString(" AND ")
-					com.WriteString(tableCategoryColumnCreatedAt)
-					com.WriteString(" < ")
-					com.WritePlaceholder()
-					com.Add(createdAt2)
-				}
+				com.WriteString(tableCategoryColumnDeletedAt)
+				com.WriteString(" " + c.deletedAtBounds.UpperOperator() + " ")
+				com.WritePlaceholder()
+				com.Add(deletedAt2)
 			}
 		}
 	}
@@ -294,144 +629,162 @@ func (c *categoryCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt
 	}
 
 	if c.updatedAt != nil && c.updatedAt.Valid {
-		updatedAtt1 := c.updatedAt.Value()
-		if updatedAtt1 != nil {
-			updatedAt1, err := ptypes.Timestamp(updatedAtt1)
-			if err != nil {
-				return err
+		switch c.updatedAt.Type {
+		case qtypes.QueryType_NULL:
+			if com.Dirty {
+				com.WriteString(" AND ")
 			}
-			switch c.updatedAt.Type {
-			case qtypes.QueryType_NULL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+			com.Dirty = true
 
-				com.WriteString(tableCategoryColumnUpdatedAt)
-				if c.updatedAt.Negation {
-					com.WriteString(" IS NOT NULL ")
-				} else {
-					com.WriteString(" IS NULL ")
-				}
-			case qtypes.QueryType_EQUAL:
+			com.WriteString(tableCategoryColumnUpdatedAt)
+			if c.updatedAt.Negation {
+				com.WriteString(" IS NOT NULL ")
+			} else {
+				com.WriteString(" IS NULL ")
+			}
+		case qtypes.QueryType_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnUpdatedAt)
+			if c.updatedAt.Negation {
+				com.WriteString(" <> ")
+			} else {
+				com.WriteString(" = ")
+			}
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_GREATER:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnUpdatedAt)
+			com.WriteString(">")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_GREATER_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnUpdatedAt)
+			com.WriteString(">=")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_LESS:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnUpdatedAt)
+			com.WriteString(" < ")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_LESS_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCategoryColumnUpdatedAt)
+			com.WriteString(" <= ")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_IN:
+			if len(c.updatedAt.Values) > 0 {
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
 				com.WriteString(tableCategoryColumnUpdatedAt)
-				if c.updatedAt.Negation {
-					com.WriteString(" <> ")
-				} else {
-					com.WriteString(" = ")
+				com.WriteString(" IN (")
+				for i, v := range c.updatedAt.Values {
+					if i != 0 {
+						com.WriteString(", ")
+					}
+					com.WritePlaceholder()
+					com.Add(v)
 				}
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_GREATER:
-				if com.Dirty {
-					com.WriteString(" AND ")
+				com.WriteString(") ")
+			}
+		case qtypes.QueryType_BETWEEN:
+			// Missing bound leaves the range open on that side.
+			if len(c.updatedAt.Values) > 0 && c.updatedAt.Values[0] != nil {
+				updatedAt1, err := ptypes.Timestamp(c.updatedAt.Values[0])
+				if err != nil {
+					return err
 				}
-				com.Dirty = true
-
-				com.WriteString(tableCategoryColumnUpdatedAt)
-				com.WriteString(">")
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_GREATER_EQUAL:
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
 				com.WriteString(tableCategoryColumnUpdatedAt)
-				com.WriteString(">=")
+				com.WriteString(" " + c.updatedAtBounds.LowerOperator() + " ")
 				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_LESS:
-				if com.Dirty {
-					com.WriteString(" AND ")
+				com.Add(updatedAt1)
+			}
+			if len(c.updatedAt.Values) > 1 && c.updatedAt.Values[1] != nil {
+				updatedAt2, err := ptypes.Timestamp(c.updatedAt.Values[1])
+				if err != nil {
+					return err
 				}
-				com.Dirty = true
-
-				com.WriteString(tableCategoryColumnUpdatedAt)
-				com.WriteString(" < ")
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_LESS_EQUAL:
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
 				com.WriteString(tableCategoryColumnUpdatedAt)
-				com.WriteString(" <= ")
+				com.WriteString(" " + c.updatedAtBounds.UpperOperator() + " ")
 				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_IN:
-				if len(c.updatedAt.Values) > 0 {
-					if com.Dirty {
-						com.WriteString(" AND ")
-					}
-					com.Dirty = true
-
-					com.WriteString(tableCategoryColumnUpdatedAt)
-					com.WriteString(" IN (")
-					for i, v := range c.updatedAt.Values {
-						if i != 0 {
-							com.WriteString(", ")
-						}
-						com.WritePlaceholder()
-						com.Add(v)
-					}
-					com.WriteString(") ")
-				}
-			case qtypes.QueryType_BETWEEN:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
-
-				updatedAtt2 := c.updatedAt.Values[1]
-				if updatedAtt2 != nil {
-					updatedAt2, err := ptypes.Timestamp(updatedAtt2)
-					if err != nil {
-						return err
-					}
-					com.WriteString(tableCategoryColumnUpdatedAt)
-					com.WriteString(" > ")
-					com.WritePlaceholder()
-					com.Add(updatedAt1)
-					com.WriteString(" AND ")
-					com.WriteString(tableCategoryColumnUpdatedAt)
-					com.WriteString(" < ")
-					com.WritePlaceholder()
-					com.Add(updatedAt2)
-				}
+				com.Add(updatedAt2)
 			}
 		}
 	}
 
-	if len(c.sort) > 0 {
-		i := 0
-		com.WriteString(" ORDER BY ")
+	if !c.includeDeleted {
+		if com.Dirty {
+			com.WriteString(" AND ")
+		}
+		com.Dirty = true
+		com.WriteString(tableCategoryColumnDeletedAt)
+		com.WriteString(" IS NULL")
+	}
 
-		for cn, asc := range c.sort {
-			for _, tcn := range tableCategoryColumns {
-				if cn == tcn {
-					if i > 0 {
-						com.WriteString(", ")
-					}
-					com.WriteString(cn)
-					if !asc {
-						com.WriteString(" DESC ")
-					}
-					i++
-					break
-				}
+	return c.writeSuffix(com)
+}
+
+// writeSuffix writes ORDER BY, OFFSET, LIMIT and locking clauses that follow the condition.
+func (c *categoryCriteria) writeSuffix(com *pqtgo.Composer) (err error) {
+SortLoop:
+	for cn := range c.sort {
+		for _, tcn := range tableCategoryColumns {
+			if cn == tcn {
+				continue SortLoop
 			}
 		}
+		return fmt.Errorf("pqt: unknown sort column %q", cn)
+	}
+
+	// Columns are ordered by name, map does not preserve the order and random one would make results unstable.
+	// Sort expressions, e.g. window functions, are ordered explicitly, so they come first.
+	if keys := pqtgo.Keyset(c.sort, tableCategoryColumns); len(keys) > 0 || len(c.sortExpr) > 0 {
+		orderBy, err := pqtgo.OrderBy(c.sortExpr, keys, tableCategoryColumns)
+		if err != nil {
+			return err
+		}
+		com.WriteString(" ORDER BY ")
+		com.WriteString(orderBy)
+		com.WriteString(" ")
 	}
-	if c.offset > 0 {
+	if c.Offset > 0 {
 		if _, err = com.WriteString(" OFFSET "); err != nil {
 			return
 		}
@@ -441,9 +794,9 @@ func (c *categoryCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt
 		if _, err = com.WriteString(" "); err != nil {
 			return
 		}
-		com.Add(c.offset)
+		com.Add(c.Offset)
 	}
-	if c.limit > 0 {
+	if c.Limit > 0 {
 		if _, err = com.WriteString(" LIMIT "); err != nil {
 			return
 		}
@@ -453,28 +806,161 @@ func (c *categoryCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt
 		if _, err = com.WriteString(" "); err != nil {
 			return
 		}
-		com.Add(c.limit)
+		com.Add(c.Limit)
+	}
+	if c.lock != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.lock.String())
 	}
 
 	return
 }
 
+// whereClause returns condition built from the criteria, without WHERE keyword, and its arguments in order.
+// Placeholders are numbered starting at given index, so the clause can be embedded into a custom query.
+// Sort, offset, limit and lock are ignored.
+func (c *categoryCriteria) whereClause(startIdx int) (string, []interface{}, error) {
+	cc := *c
+	cc.sort, cc.sortExpr, cc.Offset, cc.Limit, cc.lock = nil, nil, 0, 0, pqt.LockNone
+
+	com := pqtgo.NewComposerAt(7, startIdx)
+	if err := cc.WriteComposition("", com, pqtgo.And); err != nil {
+		return "", nil, err
+	}
+
+	return com.String(), com.Args(), nil
+}
+
 type categoryPatch struct {
 	content   *ntypes.String
 	createdAt *time.Time
+	deletedAt *time.Time
 	name      *ntypes.String
 	parentID  *ntypes.Int64
 	updatedAt *time.Time
 }
 
+// validate returns pqt.ValidationError if patch sets NULL to any of NOT NULL columns.
+func (p *categoryPatch) validate() error {
+	var violations []pqt.Violation
+	if p.content != nil && !p.content.Valid {
+		violations = append(violations, pqt.Violation{Column: tableCategoryColumnContent, Reason: "cannot be null"})
+	}
+	if p.name != nil && !p.name.Valid {
+		violations = append(violations, pqt.Violation{Column: tableCategoryColumnName, Reason: "cannot be null"})
+	}
+	if len(violations) > 0 {
+		return &pqt.ValidationError{Table: tableCategory, Violations: violations}
+	}
+
+	return nil
+}
+
+// categoryPage is a single page of entities returned by findPageContext method.
+// Cursors of the first and the last entity allow to request adjacent pages.
+type categoryPage struct {
+	edges                    []*categoryEntity
+	hasNextPage, hasPrevPage bool
+	startCursor, endCursor   string
+}
+
+// categoryLateral is a single row returned by findLateralContext method.
+// Values of columns of lateral joins are keyed by alias.column.
+type categoryLateral struct {
+	entity  *categoryEntity
+	lateral map[string]interface{}
+}
+
+// categoryBeforeInsertHook is called by insertContext, insertReturningColumnsContext, insertBatchContext, bulkInsertContext, upsertOnContext and upsertContext before the entity is stored, it can modify the entity, e.g. set columns from the context.
+// Returned error aborts the insert.
+type categoryBeforeInsertHook func(ctx context.Context, e *categoryEntity) error
+
+// categoryAfterInsertHook is called by insertContext and insertBatchContext after the entity is stored, with values returned by the database.
+// It is not called by insertReturningColumnsContext and bulkInsertContext, they do not read back the whole entity.
+// It is not called by upsertOnContext and upsertContext either, they cannot tell inserted row from updated one.
+// Returned error is passed to the caller, but the entity is already stored, unless insert is executed within a transaction that is rolled back.
+type categoryAfterInsertHook func(ctx context.Context, e *categoryEntity) error
+
+// categoryBeforeUpdateHook is called by updateOneByIDContext, updateOneByIDReturningColumnsContext and patchOneByIDContext before the entity is modified, it can modify the patch.
+// It is not called by upsertOnContext and upsertContext, they do not know the primary key in advance.
+// Returned error aborts the update.
+type categoryBeforeUpdateHook func(ctx context.Context, id int64, patch *categoryPatch) error
+
+// categoryAfterUpdateHook is called by updateOneByIDContext after the entity is modified, with values returned by the database.
+// It is not called by updateOneByIDReturningColumnsContext and patchOneByIDContext, they do not read back the whole entity.
+// It is not called by upsertOnContext and upsertContext either, they cannot tell inserted row from updated one.
+// Returned error is passed to the caller, but the entity is already modified, unless update is executed within a transaction that is rolled back.
+type categoryAfterUpdateHook func(ctx context.Context, e *categoryEntity) error
+
+// categoryBeforeDeleteHook is called by hardDeleteOneByIDContext, hardDeleteAndReturnOneByIDContext and softDeleteOneByIDContext before the entity is removed.
+// It is not called by deleteByCriteriaContext.
+// Returned error aborts the delete.
+type categoryBeforeDeleteHook func(ctx context.Context, id int64) error
+
+// categoryAfterDeleteHook is called by hardDeleteOneByIDContext, hardDeleteAndReturnOneByIDContext and softDeleteOneByIDContext after the entity is removed, it is not called if there was no such entity.
+// It is not called by deleteByCriteriaContext.
+// Returned error is passed to the caller, but the entity is already removed, unless delete is executed within a transaction that is rolled back.
+type categoryAfterDeleteHook func(ctx context.Context, id int64) error
+
+// categoryRepositoryBase is a data access layer of the example.category table.
+// Optional explain hook receives execution plans of read only queries in debug mode, optional planner builds conditions out of criteria instead of them.
+// Methods called without context are bounded by timeout, if it is set.
 type categoryRepositoryBase struct {
-	table   string
-	columns []string
-	db      *sql.DB
-	dbg     bool
-	log     log.Logger
+	table        string
+	columns      []string
+	db           pqtgo.Querier
+	dbg          bool
+	log          log.Logger
+	bulkSize     int
+	explain      pqt.ExplainHook
+	planner      pqt.Planner
+	beforeInsert categoryBeforeInsertHook
+	afterInsert  categoryAfterInsertHook
+	beforeUpdate categoryBeforeUpdateHook
+	afterUpdate  categoryAfterUpdateHook
+	beforeDelete categoryBeforeDeleteHook
+	afterDelete  categoryAfterDeleteHook
+	timeout      time.Duration
+}
+
+// withTx returns copy of the repository that executes all queries within given transaction.
+func (r *categoryRepositoryBase) withTx(tx *sql.Tx) *categoryRepositoryBase {
+	rt := *r
+	rt.db = tx
+
+	return &rt
+}
+
+// explainQuery runs given query using EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) and passes the plan to the explain hook.
+// As the query is executed, it is called in debug mode by read only methods only.
+func (r *categoryRepositoryBase) explainQuery(ctx context.Context, query string, args []interface{}) error {
+	var plan []byte
+	if err := r.db.QueryRowContext(ctx, "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+query, args...).Scan(&plan); err != nil {
+		return err
+	}
+	r.explain(json.RawMessage(plan), query, args)
+
+	return nil
+}
+
+// plan returns condition of given criteria and its arguments, built by the planner if it is set.
+func (r *categoryRepositoryBase) plan(c *categoryCriteria) (string, []interface{}, error) {
+	if r.planner != nil {
+		return r.planner.Plan(c)
+	}
+	return c.whereClause(1)
+}
+
+// defaultContext returns context used by methods called without one, it is bounded by the timeout of the repository, if it is set.
+// Context given explicitly by the caller is used as it is.
+func (r *categoryRepositoryBase) defaultContext() (context.Context, context.CancelFunc) {
+	if r.timeout > 0 {
+		return context.WithTimeout(context.Background(), r.timeout)
+	}
+	return context.Background(), func() {}
 }
 
+// scanCategoryRows reads all rows into entities, each row has to consist of columns listed in tableCategoryColumns, in the same order.
 func scanCategoryRows(rows *sql.Rows) ([]*categoryEntity, error) {
 	var (
 		entities []*categoryEntity
@@ -485,6 +971,7 @@ func scanCategoryRows(rows *sql.Rows) ([]*categoryEntity, error) {
 		err = rows.Scan(
 			&ent.content,
 			&ent.createdAt,
+			&ent.deletedAt,
 			&ent.id,
 			&ent.name,
 			&ent.parentID,
@@ -503,918 +990,888 @@ func scanCategoryRows(rows *sql.Rows) ([]*categoryEntity, error) {
 	return entities, nil
 }
 
-func (r *categoryRepositoryBase) count(c *categoryCriteria) (int64, error) {
+// scanCategoryRow reads single row into entity, the row has to consist of columns listed in tableCategoryColumns, in the same order.
+// sql.ErrNoRows is returned if the query selected no rows.
+func scanCategoryRow(row *sql.Row) (*categoryEntity, error) {
+	var ent categoryEntity
+	err := row.Scan(
+		&ent.content,
+		&ent.createdAt,
+		&ent.deletedAt,
+		&ent.id,
+		&ent.name,
+		&ent.parentID,
+		&ent.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+
+// countQuery returns query and arguments used by countContext to count entities that match given criteria, without executing it.
+func (r *categoryRepositoryBase) countQuery(c *categoryCriteria) (string, []interface{}, error) {
 
-	com := pqtgo.NewComposer(6)
-	buf := bytes.NewBufferString("SELECT COUNT(*) FROM ")
+	buf := bytes.NewBufferString("SELECT ")
+	if c.countDistinct != "" {
+		var known bool
+		for _, cn := range tableCategoryColumns {
+			if cn == c.countDistinct {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return "", nil, fmt.Errorf("category count failure, unknown column: %s", c.countDistinct)
+		}
+		buf.WriteString("COUNT(DISTINCT " + c.countDistinct + ")")
+	} else {
+		buf.WriteString("COUNT(*)")
+	}
+	buf.WriteString(" FROM ")
 	buf.WriteString(r.table)
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
-		return 0, err
+	// The same condition as used by find, but without sort, offset and limit that make no sense for an aggregate.
+	where, args, err := r.plan(c)
+	if err != nil {
+		return "", nil, err
 	}
-	if com.Dirty {
+	if where != "" {
 		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
+
+	return buf.String(), args, nil
+}
+
+// countContext returns number of entities that match given criteria. Sort, offset, limit and lock are ignored.
+func (r *categoryRepositoryBase) countContext(ctx context.Context, c *categoryCriteria) (int64, error) {
+	query, args, err := r.countQuery(c)
+	if err != nil {
+		return 0, err
 	}
 
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Count"); err != nil {
+		if err := r.log.Log("msg", query, "function", "Count"); err != nil {
 			return 0, err
 		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return 0, err
+			}
+		}
 	}
 
 	var count int64
-	if err := r.db.QueryRow(buf.String(), com.Args()...).Scan(&count); err != nil {
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
 		return 0, err
 	}
 	return count, nil
 }
+func (r *categoryRepositoryBase) count(c *categoryCriteria) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-func (r *categoryRepositoryBase) find(c *categoryCriteria) ([]*categoryEntity, error) {
+	return r.countContext(ctx, c)
+}
 
-	com := pqtgo.NewComposer(1)
-	buf := bytes.NewBufferString("SELECT ")
-	buf.WriteString(strings.Join(r.columns, ", "))
-	buf.WriteString(" FROM ")
-	buf.WriteString(r.table)
-	buf.WriteString(" ")
+// countDistinctContext returns number of distinct values of given column among entities that match given criteria.
+func (r *categoryRepositoryBase) countDistinctContext(ctx context.Context, cn string, c *categoryCriteria) (int64, error) {
+	cc := *c
+	cc.countDistinct = cn
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
-		return nil, err
+	return r.countContext(ctx, &cc)
+}
+func (r *categoryRepositoryBase) countDistinct(cn string, c *categoryCriteria) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.countDistinctContext(ctx, cn, c)
+}
+
+// existsContext returns true if at least one entity matches given criteria, database stops at the first matching row.
+// Sort, offset and limit are ignored.
+func (r *categoryRepositoryBase) existsContext(ctx context.Context, c *categoryCriteria) (bool, error) {
+	buf := bytes.NewBufferString("SELECT EXISTS (SELECT 1 FROM ")
+	buf.WriteString(r.table)
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return false, err
 	}
-	if com.Dirty {
+	if where != "" {
 		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
-	}
+	buf.WriteString(")")
 
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Find"); err != nil {
-			return nil, err
+		if err := r.log.Log("msg", buf.String(), "function", "Exists"); err != nil {
+			return false, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return false, err
+			}
 		}
 	}
 
-	rows, err := r.db.Query(buf.String(), com.Args()...)
-	if err != nil {
-		return nil, err
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, buf.String(), args...).Scan(&exists); err != nil {
+		return false, err
 	}
+	return exists, nil
+}
+func (r *categoryRepositoryBase) exists(c *categoryCriteria) (bool, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-	defer rows.Close()
-
-	return scanCategoryRows(rows)
+	return r.existsContext(ctx, c)
 }
-func (r *categoryRepositoryBase) findIter(c *categoryCriteria) (*categoryIterator, error) {
 
-	com := pqtgo.NewComposer(1)
+// aggregateContext computes given aggregate function of the column among entities that match given criteria, grouped by values of groupBy column.
+// Column can be empty for pqt.AggregateCount, which then counts entities. If groupBy is empty, single result with nil group is returned.
+// Groups are ordered by their values. Sort, offset, limit and lock are ignored.
+// Unknown functions and columns are rejected, as well as SUM and AVG of non-numeric column, whose MIN and MAX are returned in Raw field.
+func (r *categoryRepositoryBase) aggregateContext(ctx context.Context, c *categoryCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error) {
+	if fn.String() == "" {
+		return nil, fmt.Errorf("category aggregate failure, unknown function: %d", fn)
+	}
+	if column == "" && fn != pqt.AggregateCount {
+		return nil, fmt.Errorf("category aggregate failure, column is required by %s", fn)
+	}
+	for _, cn := range []string{column, groupBy} {
+		if cn == "" {
+			continue
+		}
+		var known bool
+		for _, tc := range tableCategoryColumns {
+			if tc == cn {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("category aggregate failure, unknown column: %s", cn)
+		}
+	}
+	// Result of COUNT and of any function of a numeric column is a number, other values are scanned as they are.
+	numeric := fn == pqt.AggregateCount
+	switch column {
+	case tableCategoryColumnID, tableCategoryColumnParentID:
+		numeric = true
+	}
+	if !numeric && (fn == pqt.AggregateSum || fn == pqt.AggregateAvg) {
+		return nil, fmt.Errorf("category aggregate failure, %s requires numeric column, got: %s", fn, column)
+	}
+
+	expr := "*"
+	if column != "" {
+		expr = column
+	}
 	buf := bytes.NewBufferString("SELECT ")
-	buf.WriteString(strings.Join(r.columns, ", "))
+	if groupBy != "" {
+		buf.WriteString(groupBy)
+		buf.WriteString(", ")
+	}
+	buf.WriteString(fn.String() + "(" + expr + ")")
 	buf.WriteString(" FROM ")
 	buf.WriteString(r.table)
-	buf.WriteString(" ")
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
+	where, args, err := r.plan(c)
+	if err != nil {
 		return nil, err
 	}
-	if com.Dirty {
+	if where != "" {
 		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
+	if groupBy != "" {
+		buf.WriteString(" GROUP BY " + groupBy + " ORDER BY " + groupBy)
 	}
 
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Find"); err != nil {
+		if err := r.log.Log("msg", buf.String(), "function", "Aggregate"); err != nil {
 			return nil, err
 		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	rows, err := r.db.Query(buf.String(), com.Args()...)
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return &categoryIterator{rows: rows}, nil
+	var res []pqt.AggregateResult
+	for rows.Next() {
+		var (
+			group, raw interface{}
+			// Aggregate of a group that has no values, e.g. SUM of nulls, is null.
+			value sql.NullFloat64
+		)
+		dest := []interface{}{&value}
+		if !numeric {
+			dest[0] = &raw
+		}
+		if groupBy != "" {
+			dest = append([]interface{}{&group}, dest...)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		res = append(res, pqt.AggregateResult{Group: group, Value: value.Float64, Raw: raw})
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return res, nil
 }
-func (r *categoryRepositoryBase) findOneByID(id int64) (*categoryEntity, error) {
-	var (
-		ent categoryEntity
-	)
-	query := `SELECT content,
-created_at,
-id,
-name,
-parent_id,
-updated_at
- FROM example.category WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(
-		&ent.content,
-		&ent.createdAt,
-		&ent.id,
-		&ent.name,
-		&ent.parentID,
-		&ent.updatedAt,
-	)
+func (r *categoryRepositoryBase) aggregate(c *categoryCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.aggregateContext(ctx, c, fn, column, groupBy)
+}
+
+// findQuery returns query and arguments used by findContext to retrieve entities that match given criteria, without executing it.
+func (r *categoryRepositoryBase) findQuery(c *categoryCriteria) (string, []interface{}, error) {
+	where, args, err := r.plan(c)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
-	return &ent, nil
-}
-func (r *categoryRepositoryBase) insert(e *categoryEntity) (*categoryEntity, error) {
-	insert := pqcomp.New(0, 6)
-	insert.AddExpr(tableCategoryColumnContent, "", e.content)
-	insert.AddExpr(tableCategoryColumnCreatedAt, "", e.createdAt)
-	insert.AddExpr(tableCategoryColumnName, "", e.name)
-	insert.AddExpr(tableCategoryColumnParentID, "", e.parentID)
-	insert.AddExpr(tableCategoryColumnUpdatedAt, "", e.updatedAt)
+	buf := bytes.NewBufferString("SELECT ")
+	buf.WriteString(strings.Join(r.columns, ", "))
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
 
-	b := bytes.NewBufferString("INSERT INTO " + r.table)
+	com := pqtgo.NewComposerAt(2, len(args)+1)
+	if err := c.writeSuffix(com); err != nil {
+		return "", nil, err
+	}
+	buf.ReadFrom(com)
 
-	if insert.Len() != 0 {
-		b.WriteString(" (")
-		for insert.Next() {
-			if !insert.First() {
-				b.WriteString(", ")
-			}
+	return buf.String(), append(args, com.Args()...), nil
+}
 
-			fmt.Fprintf(b, "%s", insert.Key())
-		}
-		insert.Reset()
-		b.WriteString(") VALUES (")
-		for insert.Next() {
-			if !insert.First() {
-				b.WriteString(", ")
-			}
+// findContext returns entities that match given criteria.
+func (r *categoryRepositoryBase) findContext(ctx context.Context, c *categoryCriteria) ([]*categoryEntity, error) {
 
-			fmt.Fprintf(b, "%s", insert.PlaceHolder())
-		}
-		b.WriteString(")")
-		if len(r.columns) > 0 {
-			b.WriteString(" RETURNING ")
-			b.WriteString(strings.Join(r.columns, ", "))
-		}
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
 	}
 
 	if r.dbg {
-		if err := r.log.Log("msg", b.String(), "function", "Insert"); err != nil {
+		if err := r.log.Log("msg", query, "function", "Find"); err != nil {
 			return nil, err
 		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	err := r.db.QueryRow(b.String(), insert.Args()...).Scan(
-		&e.content,
-		&e.createdAt,
-		&e.id,
-		&e.name,
-		&e.parentID,
-		&e.updatedAt,
-	)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 
-	return e, nil
-}
-func (r *categoryRepositoryBase) upsert(e *categoryEntity, p *categoryPatch, inf ...string) (*categoryEntity, error) {
-	insert := pqcomp.New(0, 6)
-	update := insert.Compose(6)
-	insert.AddExpr(tableCategoryColumnContent, "", e.content)
-	insert.AddExpr(tableCategoryColumnCreatedAt, "", e.createdAt)
-	insert.AddExpr(tableCategoryColumnName, "", e.name)
-	insert.AddExpr(tableCategoryColumnParentID, "", e.parentID)
-	insert.AddExpr(tableCategoryColumnUpdatedAt, "", e.updatedAt)
-	if len(inf) > 0 {
-		update.AddExpr(tableCategoryColumnContent, "=", p.content)
-		update.AddExpr(tableCategoryColumnCreatedAt, "=", p.createdAt)
-		update.AddExpr(tableCategoryColumnName, "=", p.name)
-		update.AddExpr(tableCategoryColumnParentID, "=", p.parentID)
-		update.AddExpr(tableCategoryColumnUpdatedAt, "=", p.updatedAt)
-	}
-
-	b := bytes.NewBufferString("INSERT INTO " + r.table)
+	defer rows.Close()
 
-	if insert.Len() > 0 {
-		b.WriteString(" (")
-		for insert.Next() {
-			if !insert.First() {
-				b.WriteString(", ")
-			}
+	return scanCategoryRows(rows)
+}
+func (r *categoryRepositoryBase) find(c *categoryCriteria) ([]*categoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-			fmt.Fprintf(b, "%s", insert.Key())
-		}
-		insert.Reset()
-		b.WriteString(") VALUES (")
-		for insert.Next() {
-			if !insert.First() {
-				b.WriteString(", ")
-			}
+	return r.findContext(ctx, c)
+}
 
-			fmt.Fprintf(b, "%s", insert.PlaceHolder())
-		}
-		b.WriteString(")")
-	}
-	b.WriteString(" ON CONFLICT ")
-	if len(inf) > 0 && update.Len() > 0 {
-		b.WriteString(" (")
-		for j, i := range inf {
-			if j != 0 {
-				b.WriteString(", ")
-			}
-			b.WriteString(i)
-		}
-		b.WriteString(") ")
-		b.WriteString(" DO UPDATE SET ")
-		for update.Next() {
-			if !update.First() {
-				b.WriteString(", ")
-			}
+// findIterContext returns iterator over entities that match given criteria.
+// Caller is responsible for closing the iterator, also if the loop exits early.
+func (r *categoryRepositoryBase) findIterContext(ctx context.Context, c *categoryCriteria) (*categoryIterator, error) {
 
-			b.WriteString(update.Key())
-			b.WriteString(" ")
-			b.WriteString(update.Oper())
-			b.WriteString(" ")
-			b.WriteString(update.PlaceHolder())
-		}
-	} else {
-		b.WriteString(" DO NOTHING ")
-	}
-	if insert.Len() > 0 {
-		if len(r.columns) > 0 {
-			b.WriteString(" RETURNING ")
-			b.WriteString(strings.Join(r.columns, ", "))
-		}
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
 	}
 
 	if r.dbg {
-		if err := r.log.Log("msg", b.String(), "function", "Upsert"); err != nil {
+		if err := r.log.Log("msg", query, "function", "Find"); err != nil {
 			return nil, err
 		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	err := r.db.QueryRow(b.String(), insert.Args()...).Scan(
-		&e.content,
-		&e.createdAt,
-		&e.id,
-		&e.name,
-		&e.parentID,
-		&e.updatedAt,
-	)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 
-	return e, nil
+	return &categoryIterator{rows: rows}, nil
 }
-func (r *categoryRepositoryBase) updateOneByID(id int64, patch *categoryPatch) (*categoryEntity, error) {
-	update := pqcomp.New(1, 6)
-	update.AddArg(id)
+func (r *categoryRepositoryBase) findIter(c *categoryCriteria) (*categoryIterator, error) {
+	ctx, cancel := r.defaultContext()
+	it, err := r.findIterContext(ctx, c)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	it.cancel = cancel
 
-	update.AddExpr(tableCategoryColumnContent, pqcomp.Equal, patch.content)
-	if patch.createdAt != nil {
-		update.AddExpr(tableCategoryColumnCreatedAt, pqcomp.Equal, patch.createdAt)
+	return it, nil
+}
 
+// findPageContext returns page of entities that match given criteria using cursor based (keyset) pagination.
+// Rows are ordered by sort columns of the criteria, followed by primary key, so cursors remain valid if rows are inserted or deleted.
+// Limit of the criteria defines size of the page, offset is not supported.
+// Values held by cursors are decoded into properties of the entity, so they keep types of their columns.
+func (r *categoryRepositoryBase) findPageContext(ctx context.Context, c *categoryCriteria, page pqt.CursorPage) (*categoryPage, error) {
+	if c.Offset > 0 {
+		return nil, errors.New("category find page failure, offset is not supported")
 	}
-	update.AddExpr(tableCategoryColumnName, pqcomp.Equal, patch.name)
-	update.AddExpr(tableCategoryColumnParentID, pqcomp.Equal, patch.parentID)
-	if patch.updatedAt != nil {
-		update.AddExpr(tableCategoryColumnUpdatedAt, pqcomp.Equal, patch.updatedAt)
-	} else {
-		update.AddExpr(tableCategoryColumnUpdatedAt, pqcomp.Equal, "NOW()")
+	if len(c.sortExpr) > 0 {
+		return nil, errors.New("category find page failure, sort expressions are not supported")
 	}
-
-	if update.Len() == 0 {
-		return nil, errors.New("category update failure, nothing to update")
+	if c.Limit <= 0 {
+		return nil, errors.New("category find page failure, limit has to be positive")
 	}
-	query := "UPDATE example.category SET "
-	for update.Next() {
-		if !update.First() {
-			query += ", "
+	if page.After != "" && page.Before != "" {
+		return nil, errors.New("category find page failure, after and before cursors cannot be used together")
+	}
+
+SortLoop:
+	for cn := range c.sort {
+		for _, tcn := range tableCategoryColumns {
+			if cn == tcn {
+				continue SortLoop
+			}
 		}
+		return nil, fmt.Errorf("pqt: unknown sort column %q", cn)
+	}
 
-		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
+	keys := pqtgo.Keyset(c.sort, tableCategoryColumns, tableCategoryColumnID)
+	if len(keys) == 0 {
+		return nil, errors.New("category find page failure, sort or primary key is required")
 	}
-	query += " WHERE id = $1 RETURNING " + strings.Join(r.columns, ", ")
-	var e categoryEntity
-	err := r.db.QueryRow(query, update.Args()...).Scan(
-		&e.content,
-		&e.createdAt,
-		&e.id,
-		&e.name,
-		&e.parentID,
-		&e.updatedAt,
-	)
-	if err != nil {
-		return nil, err
+	cursor := func(ent *categoryEntity) (string, error) {
+		values := make(map[string]interface{}, len(keys))
+		for _, k := range keys {
+			values[k.Name], _ = ent.value(k.Name)
+		}
+		return pqt.EncodeCursor(values)
 	}
 
-	return &e, nil
-}
-
-func (r *categoryRepositoryBase) deleteOneByID(id int64) (int64, error) {
-	query := "DELETE FROM example.category WHERE id = $1"
+	// Previous page is fetched by traversing rows in opposite order, starting from the cursor.
+	from, backward := page.After, page.Before != ""
+	order := keys
+	if backward {
+		from, order = page.Before, pqtgo.ReverseKeyset(keys)
+	}
 
-	res, err := r.db.Exec(query, id)
+	where, args, err := r.plan(c)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
+	com := pqtgo.NewComposerAt(7, len(args)+1)
+	for _, arg := range args {
+		com.Add(arg)
+	}
+	if where != "" {
+		// Condition of the planner can consist of alternatives, keyset condition is added to all of them.
+		com.WriteString("(" + where + ")")
+		com.Dirty = true
+	}
+	buf := bytes.NewBufferString("SELECT ")
+	buf.WriteString(strings.Join(r.columns, ", "))
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
 
-	return res.RowsAffected()
-}
-
-const (
-	tablePackage                               = "example.package"
-	tablePackageColumnBreak                    = "break"
-	tablePackageColumnCategoryID               = "category_id"
-	tablePackageColumnCreatedAt                = "created_at"
-	tablePackageColumnID                       = "id"
-	tablePackageColumnUpdatedAt                = "updated_at"
-	tablePackageConstraintCategoryIDForeignKey = "example.package_category_id_fkey"
-	tablePackageConstraintPrimaryKey           = "example.package_id_pkey"
-)
+	if from != "" {
+		// Cursor values are decoded into properties of the entity, so they keep types of the columns.
+		var cur categoryEntity
+		dest := make(map[string]interface{}, len(order))
+		for _, k := range order {
+			dest[k.Name], _ = cur.prop(k.Name)
+		}
+		if err := pqt.DecodeCursorTo(from, dest); err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, 0, len(order))
+		for _, k := range order {
+			v, _ := cur.value(k.Name)
+			values = append(values, v)
+		}
+		if com.Dirty {
+			com.WriteString(" AND ")
+		}
+		if err := pqtgo.WriteKeysetCondition(com, order, values); err != nil {
+			return nil, err
+		}
+		com.Dirty = true
+	}
+	if com.Dirty {
+		buf.WriteString(" WHERE ")
+	}
+	com.WriteString(" ORDER BY ")
+	com.WriteString(pqtgo.KeysetOrderBy(order))
+	com.WriteString(" LIMIT ")
+	if err := com.WritePlaceholder(); err != nil {
+		return nil, err
+	}
+	// One more row tells if there is another page in the direction of traversal.
+	com.Add(c.Limit + 1)
+	if c.lock != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.lock.String())
+	}
+	buf.ReadFrom(com)
 
-var (
-	tablePackageColumns = []string{
-		tablePackageColumnBreak,
-		tablePackageColumnCategoryID,
-		tablePackageColumnCreatedAt,
-		tablePackageColumnID,
-		tablePackageColumnUpdatedAt,
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindPage"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), com.Args()); err != nil {
+				return nil, err
+			}
+		}
 	}
-)
 
-type packageEntity struct {
-	// brk ...
-	brk *ntypes.String
-	// categoryID ...
-	categoryID *ntypes.Int64
-	// createdAt ...
-	createdAt time.Time
-	// id ...
-	id int64
-	// updatedAt ...
-	updatedAt *time.Time
-	// category ...
-	category *categoryEntity
-}
+	rows, err := r.db.QueryContext(ctx, buf.String(), com.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-func (e *packageEntity) prop(cn string) (interface{}, bool) {
-	switch cn {
-	case tablePackageColumnBreak:
-		return &e.brk, true
-	case tablePackageColumnCategoryID:
-		return &e.categoryID, true
-	case tablePackageColumnCreatedAt:
-		return &e.createdAt, true
-	case tablePackageColumnID:
-		return &e.id, true
-	case tablePackageColumnUpdatedAt:
-		return &e.updatedAt, true
-	default:
-		return nil, false
+	ents, err := scanCategoryRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	more := int64(len(ents)) > c.Limit
+	if more {
+		ents = ents[:c.Limit]
+	}
+	if backward {
+		for i, j := 0, len(ents)-1; i < j; i, j = i+1, j-1 {
+			ents[i], ents[j] = ents[j], ents[i]
+		}
 	}
-}
-func (e *packageEntity) props(cns ...string) ([]interface{}, error) {
 
-	res := make([]interface{}, 0, len(cns))
-	for _, cn := range cns {
-		if prop, ok := e.prop(cn); ok {
-			res = append(res, prop)
-		} else {
-			return nil, fmt.Errorf("unexpected column provided: %s", cn)
+	res := &categoryPage{edges: ents}
+	if backward {
+		res.hasNextPage, res.hasPrevPage = true, more
+	} else {
+		res.hasNextPage, res.hasPrevPage = more, page.After != ""
+	}
+	if len(ents) > 0 {
+		if res.startCursor, err = cursor(ents[0]); err != nil {
+			return nil, err
+		}
+		if res.endCursor, err = cursor(ents[len(ents)-1]); err != nil {
+			return nil, err
 		}
 	}
+
 	return res, nil
 }
+func (r *categoryRepositoryBase) findPage(c *categoryCriteria, page pqt.CursorPage) (*categoryPage, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-// packageIterator is not thread safe.
-type packageIterator struct {
-	rows *sql.Rows
-	cols []string
+	return r.findPageContext(ctx, c, page)
 }
 
-func (i *packageIterator) Next() bool {
-	return i.rows.Next()
-}
+// findAndCountContext returns entities that match given criteria together with total number of matching entities, regardless of offset and limit.
+// Total is selected by the same query using COUNT(*) OVER() window function, which is evaluated before OFFSET and LIMIT are applied.
+// If the page is empty, but offset is set, total is obtained using countContext, so it is known even if the offset goes past the last entity.
+// Window functions cannot be combined with locking clause, so lock is not supported.
+func (r *categoryRepositoryBase) findAndCountContext(ctx context.Context, c *categoryCriteria) ([]*categoryEntity, int64, error) {
+	if c.lock != pqt.LockNone {
+		return nil, 0, errors.New("category find and count failure, lock is not supported")
+	}
 
-func (i *packageIterator) Close() error {
-	return i.rows.Close()
-}
+	rr := *r
+	rr.columns = append(r.columns[:len(r.columns):len(r.columns)], "COUNT(*) OVER()")
+	query, args, err := rr.findQuery(c)
+	if err != nil {
+		return nil, 0, err
+	}
 
-func (i *packageIterator) Err() error {
-	return i.rows.Err()
-}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "FindAndCount"); err != nil {
+			return nil, 0, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
 
-// Columns is wrapper around sql.Rows.Columns method, that also cache outpu inside iterator.
-func (i *packageIterator) Columns() ([]string, error) {
-	if i.cols == nil {
-		cols, err := i.rows.Columns()
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		entities []*categoryEntity
+		total    int64
+	)
+	for rows.Next() {
+		var ent categoryEntity
+		err = rows.Scan(
+			&ent.content,
+			&ent.createdAt,
+			&ent.deletedAt,
+			&ent.id,
+			&ent.name,
+			&ent.parentID,
+			&ent.updatedAt,
+			&total,
+		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
-		i.cols = cols
+
+		entities = append(entities, &ent)
 	}
-	return i.cols, nil
+	if rows.Err() != nil {
+		return nil, 0, rows.Err()
+	}
+	if len(entities) == 0 && c.Offset > 0 {
+		if total, err = r.countContext(ctx, c); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return entities, total, nil
 }
+func (r *categoryRepositoryBase) findAndCount(c *categoryCriteria) ([]*categoryEntity, int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-// Ent is wrapper around package method that makes iterator more generic.
-func (i *packageIterator) Ent() (interface{}, error) {
-	return i.Package()
+	return r.findAndCountContext(ctx, c)
 }
 
-func (i *packageIterator) Package() (*packageEntity, error) {
-	var ent packageEntity
-	cols, err := i.rows.Columns()
-	if err != nil {
-		return nil, err
-	}
+// findIncludingDeletedContext works like findContext, but returns also entities marked as deleted.
+func (r *categoryRepositoryBase) findIncludingDeletedContext(ctx context.Context, c *categoryCriteria) ([]*categoryEntity, error) {
+	cc := *c
+	cc.includeDeleted = true
 
-	props, err := ent.props(cols...)
+	return r.findContext(ctx, &cc)
+}
+func (r *categoryRepositoryBase) findIncludingDeleted(c *categoryCriteria) ([]*categoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findIncludingDeletedContext(ctx, c)
+}
+
+// findOneContext returns single entity that matches given criteria.
+// pqt.ErrNotFound is returned if there is none, pqt.ErrMultipleRows if there is more than one.
+func (r *categoryRepositoryBase) findOneContext(ctx context.Context, c *categoryCriteria) (*categoryEntity, error) {
+	cc := *c
+	cc.Limit = 2
+
+	ents, err := r.findContext(ctx, &cc)
 	if err != nil {
 		return nil, err
 	}
-	if err := i.rows.Scan(props...); err != nil {
-		return nil, err
+	switch len(ents) {
+	case 0:
+		return nil, pqt.ErrNotFound
+	case 1:
+		return ents[0], nil
+	default:
+		return nil, pqt.ErrMultipleRows
 	}
-	return &ent, nil
 }
+func (r *categoryRepositoryBase) findOne(c *categoryCriteria) (*categoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-type packageCriteria struct {
-	offset, limit int64
-	sort          map[string]bool
-	brk           *qtypes.String
-	categoryID    *qtypes.Int64
-	createdAt     *qtypes.Timestamp
-	id            *qtypes.Int64
-	updatedAt     *qtypes.Timestamp
+	return r.findOneContext(ctx, c)
 }
 
-func (c *packageCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *pqtgo.CompositionOpts) (err error) {
+// findLateralContext works like findContext, but also joins lateral sub-queries of the criteria using JOIN LATERAL (...) AS alias ON TRUE.
+// Each row of the result holds entity and values of columns of lateral joins, entity is repeated if sub-query returns multiple rows.
+func (r *categoryRepositoryBase) findLateralContext(ctx context.Context, c *categoryCriteria) ([]*categoryLateral, error) {
+	lateral, err := pqtgo.LateralColumns(c.lateral)
+	if err != nil {
+		return nil, err
+	}
 
-	if err = pqtgo.WriteCompositionQueryString(c.brk, tablePackageColumnBreak, com, pqtgo.And); err != nil {
-		return
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBufferString("SELECT t0.content, t0.created_at, t0.deleted_at, t0.id, t0.name, t0.parent_id, t0.updated_at")
+	for _, cn := range lateral {
+		buf.WriteString(", ")
+		buf.WriteString(cn)
 	}
+	buf.WriteString(" FROM (")
+	buf.WriteString(query)
+	buf.WriteString(") AS t0")
 
-	if err = pqtgo.WriteCompositionQueryInt64(c.categoryID, tablePackageColumnCategoryID, com, pqtgo.And); err != nil {
-		return
+	// Placeholders of lateral sub-queries follow those of the criteria.
+	com := pqtgo.NewComposerAt(int64(len(c.lateral)), len(args)+1)
+	if err := pqtgo.WriteLateralJoins(com, c.lateral); err != nil {
+		return nil, err
 	}
+	buf.ReadFrom(com)
+	args = append(args, com.Args()...)
 
-	if c.createdAt != nil && c.createdAt.Valid {
-		createdAtt1 := c.createdAt.Value()
-		if createdAtt1 != nil {
-			createdAt1, err := ptypes.Timestamp(createdAtt1)
-			if err != nil {
-				return err
+	// Joins do not preserve order of the sub-query, so it is repeated by the outer query.
+	// Columns are qualified, lateral joins can expose columns of the same names.
+	if keys := pqtgo.Keyset(c.sort, tableCategoryColumns); len(keys) > 0 || len(c.sortExpr) > 0 {
+		orderBy, err := pqtgo.QualifiedOrderBy("t0", c.sortExpr, keys, tableCategoryColumns)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(" ORDER BY ")
+		buf.WriteString(orderBy)
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindLateral"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
 			}
-			switch c.createdAt.Type {
-			case qtypes.QueryType_NULL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+		}
+	}
 
-				com.WriteString(tablePackageColumnCreatedAt)
-				if c.createdAt.Negation {
-					com.WriteString(" IS NOT NULL ")
-				} else {
-					com.WriteString(" IS NULL ")
-				}
-			case qtypes.QueryType_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-				com.WriteString(tablePackageColumnCreatedAt)
-				if c.createdAt.Negation {
-					com.WriteString(" <> ")
-				} else {
-					com.WriteString(" = ")
-				}
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_GREATER:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	var res []*categoryLateral
+	for rows.Next() {
+		var ent categoryEntity
+		values := make([]interface{}, len(lateral))
+		dest := []interface{}{
+			&ent.content,
+			&ent.createdAt,
+			&ent.deletedAt,
+			&ent.id,
+			&ent.name,
+			&ent.parentID,
+			&ent.updatedAt,
+		}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
 
-				com.WriteString(tablePackageColumnCreatedAt)
-				com.WriteString(">")
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_GREATER_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+		row := &categoryLateral{entity: &ent, lateral: make(map[string]interface{}, len(lateral))}
+		for i, cn := range lateral {
+			row.lateral[cn] = values[i]
+		}
+		res = append(res, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
 
-				com.WriteString(tablePackageColumnCreatedAt)
-				com.WriteString(">=")
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_LESS:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	return res, nil
+}
+func (r *categoryRepositoryBase) findLateral(c *categoryCriteria) ([]*categoryLateral, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-				com.WriteString(tablePackageColumnCreatedAt)
-				com.WriteString(" < ")
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_LESS_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	return r.findLateralContext(ctx, c)
+}
 
-				com.WriteString(tablePackageColumnCreatedAt)
-				com.WriteString(" <= ")
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_IN:
-				if len(c.createdAt.Values) > 0 {
-					if com.Dirty {
-						com.WriteString(" AND ")
-					}
-					com.Dirty = true
-
-					com.WriteString(tablePackageColumnCreatedAt)
-					com.WriteString(" IN (")
-					for i, v := range c.createdAt.Values {
-						if i != 0 {
-							com.WriteString(", ")
-						}
-						com.WritePlaceholder()
-						com.Add(v)
-					}
-					com.WriteString(") ")
-				}
-			case qtypes.QueryType_BETWEEN:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+// findOneByIDQuery returns query and arguments used by findOneByIDContext to retrieve the entity, without executing it.
+func (r *categoryRepositoryBase) findOneByIDQuery(id int64) (string, []interface{}, error) {
+	return `SELECT content, created_at, deleted_at, id, name, parent_id, updated_at FROM example.category WHERE id = $1 AND deleted_at IS NULL`, []interface{}{id}, nil
+}
 
-				createdAtt2 := c.createdAt.Values[1]
-				if createdAtt2 != nil {
-					createdAt2, err := ptypes.Timestamp(createdAtt2)
-					if err != nil {
-						return err
-					}
-					com.WriteString(tablePackageColumnCreatedAt)
-					com.WriteString(" > ")
-					com.WritePlaceholder()
-					com.Add(createdAt1)
-					com.WriteString(" AND ")
-					com.WriteString(tablePackageColumnCreatedAt)
-					com.WriteString(" < ")
-					com.WritePlaceholder()
-					com.Add(createdAt2)
-				}
-			}
+func (r *categoryRepositoryBase) findOneByIDContext(ctx context.Context, id int64) (*categoryEntity, error) {
+	query, args, err := r.findOneByIDQuery(id)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "FindOneByID"); err != nil {
+			return nil, err
 		}
 	}
 
-	if err = pqtgo.WriteCompositionQueryInt64(c.id, tablePackageColumnID, com, pqtgo.And); err != nil {
-		return
+	var ent categoryEntity
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&ent.content,
+		&ent.createdAt,
+		&ent.deletedAt,
+		&ent.id,
+		&ent.name,
+		&ent.parentID,
+		&ent.updatedAt,
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	if c.updatedAt != nil && c.updatedAt.Valid {
-		updatedAtt1 := c.updatedAt.Value()
-		if updatedAtt1 != nil {
-			updatedAt1, err := ptypes.Timestamp(updatedAtt1)
-			if err != nil {
-				return err
-			}
-			switch c.updatedAt.Type {
-			case qtypes.QueryType_NULL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	return &ent, nil
+}
+func (r *categoryRepositoryBase) findOneByID(id int64) (*categoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-				com.WriteString(tablePackageColumnUpdatedAt)
-				if c.updatedAt.Negation {
-					com.WriteString(" IS NOT NULL ")
-				} else {
-					com.WriteString(" IS NULL ")
-				}
-			case qtypes.QueryType_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	return r.findOneByIDContext(ctx, id)
+}
 
-				com.WriteString(tablePackageColumnUpdatedAt)
-				if c.updatedAt.Negation {
-					com.WriteString(" <> ")
-				} else {
-					com.WriteString(" = ")
-				}
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_GREATER:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+// insertQuery returns query and arguments used by insertContext to save given entity, without executing it.
+func (r *categoryRepositoryBase) insertQuery(e *categoryEntity) (string, []interface{}, error) {
+	if err := e.validate(); err != nil {
+		return "", nil, err
+	}
 
-				com.WriteString(tablePackageColumnUpdatedAt)
-				com.WriteString(">")
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_GREATER_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	insert := pqcomp.New(0, 7)
+	insert.AddExpr(tableCategoryColumnContent, "", e.content)
 
-				com.WriteString(tablePackageColumnUpdatedAt)
-				com.WriteString(">=")
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_LESS:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tableCategoryColumnCreatedAt, "", e.createdAt)
+	}
 
-				com.WriteString(tablePackageColumnUpdatedAt)
-				com.WriteString(" < ")
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_LESS_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	insert.AddExpr(tableCategoryColumnDeletedAt, "", e.deletedAt)
+	insert.AddExpr(tableCategoryColumnName, "", e.name)
+	insert.AddExpr(tableCategoryColumnParentID, "", e.parentID)
+	insert.AddExpr(tableCategoryColumnUpdatedAt, "", e.updatedAt)
 
-				com.WriteString(tablePackageColumnUpdatedAt)
-				com.WriteString(" <= ")
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_IN:
-				if len(c.updatedAt.Values) > 0 {
-					if com.Dirty {
-						com.WriteString(" AND ")
-					}
-					com.Dirty = true
-
-					com.WriteString(tablePackageColumnUpdatedAt)
-					com.WriteString(" IN (")
-					for i, v := range c.updatedAt.Values {
-						if i != 0 {
-							com.WriteString(", ")
-						}
-						com.WritePlaceholder()
-						com.Add(v)
-					}
-					com.WriteString(") ")
-				}
-			case qtypes.QueryType_BETWEEN:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
 
-				updatedAtt2 := c.updatedAt.Values[1]
-				if updatedAtt2 != nil {
-					updatedAt2, err := ptypes.Timestamp(updatedAtt2)
-					if err != nil {
-						return err
-					}
-					com.WriteString(tablePackageColumnUpdatedAt)
-					com.WriteString(" > ")
-					com.WritePlaceholder()
-					com.Add(updatedAt1)
-					com.WriteString(" AND ")
-					com.WriteString(tablePackageColumnUpdatedAt)
-					com.WriteString(" < ")
-					com.WritePlaceholder()
-					com.Add(updatedAt2)
-				}
+	if insert.Len() != 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
 			}
-		}
-	}
-
-	if len(c.sort) > 0 {
-		i := 0
-		com.WriteString(" ORDER BY ")
 
-		for cn, asc := range c.sort {
-			for _, tcn := range tablePackageColumns {
-				if cn == tcn {
-					if i > 0 {
-						com.WriteString(", ")
-					}
-					com.WriteString(cn)
-					if !asc {
-						com.WriteString(" DESC ")
-					}
-					i++
-					break
-				}
-			}
-		}
-	}
-	if c.offset > 0 {
-		if _, err = com.WriteString(" OFFSET "); err != nil {
-			return
-		}
-		if err = com.WritePlaceholder(); err != nil {
-			return
-		}
-		if _, err = com.WriteString(" "); err != nil {
-			return
-		}
-		com.Add(c.offset)
-	}
-	if c.limit > 0 {
-		if _, err = com.WriteString(" LIMIT "); err != nil {
-			return
+			fmt.Fprintf(b, "%s", insert.Key())
 		}
-		if err = com.WritePlaceholder(); err != nil {
-			return
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
 		}
-		if _, err = com.WriteString(" "); err != nil {
-			return
+		b.WriteString(")")
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
 		}
-		com.Add(c.limit)
 	}
 
-	return
-}
-
-type packagePatch struct {
-	brk        *ntypes.String
-	categoryID *ntypes.Int64
-	createdAt  *time.Time
-	updatedAt  *time.Time
-}
-
-type packageRepositoryBase struct {
-	table   string
-	columns []string
-	db      *sql.DB
-	dbg     bool
-	log     log.Logger
+	return b.String(), insert.Args(), nil
 }
 
-func scanPackageRows(rows *sql.Rows) ([]*packageEntity, error) {
-	var (
-		entities []*packageEntity
-		err      error
-	)
-	for rows.Next() {
-		var ent packageEntity
-		err = rows.Scan(
-			&ent.brk,
-			&ent.categoryID,
-			&ent.createdAt,
-			&ent.id,
-			&ent.updatedAt,
-		)
-		if err != nil {
+// insertContext saves given entity and returns it with values read back from the database, e.g. defaults.
+func (r *categoryRepositoryBase) insertContext(ctx context.Context, e *categoryEntity) (*categoryEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
 			return nil, err
 		}
-
-		entities = append(entities, &ent)
-	}
-	if rows.Err() != nil {
-		return nil, rows.Err()
-	}
-
-	return entities, nil
-}
-
-func (r *packageRepositoryBase) count(c *packageCriteria) (int64, error) {
-
-	com := pqtgo.NewComposer(5)
-	buf := bytes.NewBufferString("SELECT COUNT(*) FROM ")
-	buf.WriteString(r.table)
-
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
-		return 0, err
-	}
-	if com.Dirty {
-		buf.WriteString(" WHERE ")
-	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
-	}
-
-	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Count"); err != nil {
-			return 0, err
-		}
-	}
-
-	var count int64
-	if err := r.db.QueryRow(buf.String(), com.Args()...).Scan(&count); err != nil {
-		return 0, err
 	}
-	return count, nil
-}
-
-func (r *packageRepositoryBase) find(c *packageCriteria) ([]*packageEntity, error) {
-
-	com := pqtgo.NewComposer(1)
-	buf := bytes.NewBufferString("SELECT ")
-	buf.WriteString(strings.Join(r.columns, ", "))
-	buf.WriteString(" FROM ")
-	buf.WriteString(r.table)
-	buf.WriteString(" ")
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
+	query, args, err := r.insertQuery(e)
+	if err != nil {
 		return nil, err
 	}
-	if com.Dirty {
-		buf.WriteString(" WHERE ")
-	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
-	}
-
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Find"); err != nil {
+		if err := r.log.Log("msg", query, "function", "Insert"); err != nil {
 			return nil, err
 		}
 	}
 
-	rows, err := r.db.Query(buf.String(), com.Args()...)
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.content,
+		&e.createdAt,
+		&e.deletedAt,
+		&e.id,
+		&e.name,
+		&e.parentID,
+		&e.updatedAt,
+	)
 	if err != nil {
 		return nil, err
 	}
+	if r.afterInsert != nil {
+		if err := r.afterInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
 
-	defer rows.Close()
-
-	return scanPackageRows(rows)
+	return e, nil
 }
-func (r *packageRepositoryBase) findIter(c *packageCriteria) (*packageIterator, error) {
-
-	com := pqtgo.NewComposer(1)
-	buf := bytes.NewBufferString("SELECT ")
-	buf.WriteString(strings.Join(r.columns, ", "))
-	buf.WriteString(" FROM ")
-	buf.WriteString(r.table)
-	buf.WriteString(" ")
+func (r *categoryRepositoryBase) insert(e *categoryEntity) (*categoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
-		return nil, err
-	}
-	if com.Dirty {
-		buf.WriteString(" WHERE ")
-	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
-	}
+	return r.insertContext(ctx, e)
+}
 
-	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Find"); err != nil {
+// insertReturningColumnsContext works like insertContext, but returns new entity with only given columns populated, unknown columns are rejected before execution.
+func (r *categoryRepositoryBase) insertReturningColumnsContext(ctx context.Context, e *categoryEntity, cols ...string) (*categoryEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
 			return nil, err
 		}
 	}
 
-	rows, err := r.db.Query(buf.String(), com.Args()...)
+	if len(cols) == 0 {
+		return nil, errors.New("category insert failure, no columns to return")
+	}
+	var ent categoryEntity
+	props, err := ent.props(cols...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &packageIterator{rows: rows}, nil
-}
-func (r *packageRepositoryBase) findOneByID(id int64) (*packageEntity, error) {
-	var (
-		ent packageEntity
-	)
-	query := `SELECT break,
-category_id,
-created_at,
-id,
-updated_at
- FROM example.package WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(
-		&ent.brk,
-		&ent.categoryID,
-		&ent.createdAt,
-		&ent.id,
-		&ent.updatedAt,
-	)
-	if err != nil {
+	if err := e.validate(); err != nil {
 		return nil, err
 	}
 
-	return &ent, nil
-}
-func (r *packageRepositoryBase) insert(e *packageEntity) (*packageEntity, error) {
-	insert := pqcomp.New(0, 5)
-	insert.AddExpr(tablePackageColumnBreak, "", e.brk)
-	insert.AddExpr(tablePackageColumnCategoryID, "", e.categoryID)
-	insert.AddExpr(tablePackageColumnCreatedAt, "", e.createdAt)
-	insert.AddExpr(tablePackageColumnUpdatedAt, "", e.updatedAt)
+	insert := pqcomp.New(0, 7)
+	insert.AddExpr(tableCategoryColumnContent, "", e.content)
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tableCategoryColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tableCategoryColumnDeletedAt, "", e.deletedAt)
+	insert.AddExpr(tableCategoryColumnName, "", e.name)
+	insert.AddExpr(tableCategoryColumnParentID, "", e.parentID)
+	insert.AddExpr(tableCategoryColumnUpdatedAt, "", e.updatedAt)
 
 	b := bytes.NewBufferString("INSERT INTO " + r.table)
 
@@ -1437,43 +1894,226 @@ func (r *packageRepositoryBase) insert(e *packageEntity) (*packageEntity, error)
 			fmt.Fprintf(b, "%s", insert.PlaceHolder())
 		}
 		b.WriteString(")")
-		if len(r.columns) > 0 {
-			b.WriteString(" RETURNING ")
-			b.WriteString(strings.Join(r.columns, ", "))
-		}
+		b.WriteString(" RETURNING " + strings.Join(cols, ", "))
 	}
 
 	if r.dbg {
-		if err := r.log.Log("msg", b.String(), "function", "Insert"); err != nil {
+		if err := r.log.Log("msg", b.String(), "function", "InsertReturningColumns"); err != nil {
 			return nil, err
 		}
 	}
 
-	err := r.db.QueryRow(b.String(), insert.Args()...).Scan(
-		&e.brk,
-		&e.categoryID,
-		&e.createdAt,
-		&e.id,
-		&e.updatedAt,
-	)
+	err = r.db.QueryRowContext(ctx, b.String(), insert.Args()...).Scan(props...)
 	if err != nil {
 		return nil, err
 	}
 
-	return e, nil
+	return &ent, nil
 }
-func (r *packageRepositoryBase) upsert(e *packageEntity, p *packagePatch, inf ...string) (*packageEntity, error) {
-	insert := pqcomp.New(0, 5)
-	update := insert.Compose(5)
-	insert.AddExpr(tablePackageColumnBreak, "", e.brk)
-	insert.AddExpr(tablePackageColumnCategoryID, "", e.categoryID)
-	insert.AddExpr(tablePackageColumnCreatedAt, "", e.createdAt)
-	insert.AddExpr(tablePackageColumnUpdatedAt, "", e.updatedAt)
-	if len(inf) > 0 {
-		update.AddExpr(tablePackageColumnBreak, "=", p.brk)
-		update.AddExpr(tablePackageColumnCategoryID, "=", p.categoryID)
-		update.AddExpr(tablePackageColumnCreatedAt, "=", p.createdAt)
-		update.AddExpr(tablePackageColumnUpdatedAt, "=", p.updatedAt)
+func (r *categoryRepositoryBase) insertReturningColumns(e *categoryEntity, cols ...string) (*categoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.insertReturningColumnsContext(ctx, e, cols...)
+}
+
+// insertBatchContext saves given entities using multi-row statements and returns them with values read back from the database.
+// Column with default value gets DEFAULT in rows that do not hold explicit value, like in insertContext.
+// All entities are validated first, so none of them is sent if any is invalid.
+func (r *categoryRepositoryBase) insertBatchContext(ctx context.Context, es []*categoryEntity) ([]*categoryEntity, error) {
+	for _, e := range es {
+		if r.beforeInsert != nil {
+			if err := r.beforeInsert(ctx, e); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := e.validate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, chunk := range pqtgo.Chunks(len(es), 6) {
+		batch := es[chunk[0]:chunk[1]]
+		com := pqtgo.NewComposer(int64(len(batch) * 6))
+		for i, e := range batch {
+			if i != 0 {
+				com.WriteString(", ")
+			}
+			com.WriteString("(")
+			com.WritePlaceholder()
+			com.Add(e.content)
+			com.WriteString(", ")
+			if !e.createdAt.IsZero() {
+				com.WritePlaceholder()
+				com.Add(e.createdAt)
+			} else {
+				com.WriteString("DEFAULT")
+			}
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.deletedAt)
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.name)
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.parentID)
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.updatedAt)
+			com.WriteString(")")
+		}
+
+		b := bytes.NewBufferString("INSERT INTO " + r.table + " (")
+		b.WriteString(tableCategoryColumnContent)
+		b.WriteString(", ")
+		b.WriteString(tableCategoryColumnCreatedAt)
+		b.WriteString(", ")
+		b.WriteString(tableCategoryColumnDeletedAt)
+		b.WriteString(", ")
+		b.WriteString(tableCategoryColumnName)
+		b.WriteString(", ")
+		b.WriteString(tableCategoryColumnParentID)
+		b.WriteString(", ")
+		b.WriteString(tableCategoryColumnUpdatedAt)
+		b.WriteString(") VALUES ")
+		b.ReadFrom(com)
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+
+		if r.dbg {
+			if err := r.log.Log("msg", b.String(), "function", "InsertBatch"); err != nil {
+				return nil, err
+			}
+		}
+
+		rows, err := r.db.QueryContext(ctx, b.String(), com.Args()...)
+		if err != nil {
+			return nil, err
+		}
+		returned := 0
+		for ; returned < len(batch) && rows.Next(); returned++ {
+			err = rows.Scan(
+				&batch[returned].content,
+				&batch[returned].createdAt,
+				&batch[returned].deletedAt,
+				&batch[returned].id,
+				&batch[returned].name,
+				&batch[returned].parentID,
+				&batch[returned].updatedAt,
+			)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+		}
+		if err = rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+		if len(r.columns) > 0 && returned != len(batch) {
+			return nil, fmt.Errorf("category insert batch failure, %d rows inserted, but %d returned", len(batch), returned)
+		}
+		for _, e := range batch {
+			if r.afterInsert != nil {
+				if err := r.afterInsert(ctx, e); err != nil {
+					return nil, err
+				}
+			}
+
+		}
+	}
+
+	return es, nil
+}
+func (r *categoryRepositoryBase) insertBatch(es []*categoryEntity) ([]*categoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.insertBatchContext(ctx, es)
+}
+
+// bulkInsertContext loads given entities using COPY protocol and returns number of loaded rows.
+// Serial columns and columns with default value are omitted, they are populated by the database.
+// COPY does not support RETURNING clause, so generated values (e.g. ids) are not set on given entities.
+// All entities are validated first, so none of them is sent if any is invalid.
+// Database of the repository has to implement pqtgo.TxBeginner, like *sql.DB, or pqtgo.Preparer within a transaction, like *sql.Tx.
+func (r *categoryRepositoryBase) bulkInsertContext(ctx context.Context, es []*categoryEntity) (int64, error) {
+	for _, e := range es {
+		if r.beforeInsert != nil {
+			if err := r.beforeInsert(ctx, e); err != nil {
+				return 0, err
+			}
+		}
+
+		if err := e.validate(); err != nil {
+			return 0, err
+		}
+	}
+	query := pq.CopyInSchema("example", "category", tableCategoryColumnContent, tableCategoryColumnDeletedAt, tableCategoryColumnName, tableCategoryColumnParentID, tableCategoryColumnUpdatedAt)
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "BulkInsert"); err != nil {
+			return 0, err
+		}
+	}
+
+	return pqtgo.CopyIn(ctx, r.db, query, len(es), r.bulkSize, func(i int) []interface{} {
+		return []interface{}{
+			es[i].content,
+			es[i].deletedAt,
+			es[i].name,
+			es[i].parentID,
+			es[i].updatedAt,
+		}
+	})
+}
+func (r *categoryRepositoryBase) bulkInsert(es []*categoryEntity) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.bulkInsertContext(ctx, es)
+}
+
+// upsertOnContext saves given entity, on conflict with given target it updates existing row using the patch, or values of the entity if patch is nil.
+// pqt.ErrUpsertIgnored is returned if conflicting row was not updated.
+func (r *categoryRepositoryBase) upsertOnContext(ctx context.Context, e *categoryEntity, p *categoryPatch, ct pqt.UpsertConflictTarget) (*categoryEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+	if p != nil {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	insert := pqcomp.New(0, 7)
+	update := insert.Compose(7)
+	insert.AddExpr(tableCategoryColumnContent, "", e.content)
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tableCategoryColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tableCategoryColumnDeletedAt, "", e.deletedAt)
+	insert.AddExpr(tableCategoryColumnName, "", e.name)
+	insert.AddExpr(tableCategoryColumnParentID, "", e.parentID)
+	insert.AddExpr(tableCategoryColumnUpdatedAt, "", e.updatedAt)
+	if p != nil && !ct.IsZero() {
+		update.AddExpr(tableCategoryColumnContent, "=", p.content)
+		update.AddExpr(tableCategoryColumnCreatedAt, "=", p.createdAt)
+		update.AddExpr(tableCategoryColumnDeletedAt, "=", p.deletedAt)
+		update.AddExpr(tableCategoryColumnName, "=", p.name)
+		update.AddExpr(tableCategoryColumnParentID, "=", p.parentID)
+		update.AddExpr(tableCategoryColumnUpdatedAt, "=", p.updatedAt)
 	}
 
 	b := bytes.NewBufferString("INSERT INTO " + r.table)
@@ -1498,16 +2138,25 @@ func (r *packageRepositoryBase) upsert(e *packageEntity, p *packagePatch, inf ..
 		}
 		b.WriteString(")")
 	}
-	b.WriteString(" ON CONFLICT ")
-	if len(inf) > 0 && update.Len() > 0 {
-		b.WriteString(" (")
-		for j, i := range inf {
-			if j != 0 {
-				b.WriteString(", ")
+	var excluded []string
+	if p == nil && !ct.IsZero() {
+		insert.Reset()
+		for insert.Next() {
+			if !ct.HasColumn(insert.Key()) {
+				excluded = append(excluded, insert.Key()+" = EXCLUDED."+insert.Key())
 			}
-			b.WriteString(i)
 		}
-		b.WriteString(") ")
+	}
+
+	b.WriteString(" ON CONFLICT ")
+	if !ct.IsZero() {
+		b.WriteString(ct.String())
+	}
+	switch {
+	case len(excluded) > 0:
+		b.WriteString(" DO UPDATE SET ")
+		b.WriteString(strings.Join(excluded, ", "))
+	case !ct.IsZero() && update.Len() > 0:
 		b.WriteString(" DO UPDATE SET ")
 		for update.Next() {
 			if !update.First() {
@@ -1520,7 +2169,7 @@ func (r *packageRepositoryBase) upsert(e *packageEntity, p *packagePatch, inf ..
 			b.WriteString(" ")
 			b.WriteString(update.PlaceHolder())
 		}
-	} else {
+	default:
 		b.WriteString(" DO NOTHING ")
 	}
 	if insert.Len() > 0 {
@@ -1536,39 +2185,69 @@ func (r *packageRepositoryBase) upsert(e *packageEntity, p *packagePatch, inf ..
 		}
 	}
 
-	err := r.db.QueryRow(b.String(), insert.Args()...).Scan(
-		&e.brk,
-		&e.categoryID,
+	err := r.db.QueryRowContext(ctx, b.String(), insert.Args()...).Scan(
+		&e.content,
 		&e.createdAt,
+		&e.deletedAt,
 		&e.id,
+		&e.name,
+		&e.parentID,
 		&e.updatedAt,
 	)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, pqt.ErrUpsertIgnored
+		}
 		return nil, err
 	}
 
 	return e, nil
 }
-func (r *packageRepositoryBase) updateOneByID(id int64, patch *packagePatch) (*packageEntity, error) {
-	update := pqcomp.New(1, 5)
+func (r *categoryRepositoryBase) upsertOn(e *categoryEntity, p *categoryPatch, ct pqt.UpsertConflictTarget) (*categoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.upsertOnContext(ctx, e, p, ct)
+}
+
+// upsertContext is a shorthand for upsertOnContext, given columns are used to infer the conflict target.
+func (r *categoryRepositoryBase) upsertContext(ctx context.Context, e *categoryEntity, p *categoryPatch, inf ...string) (*categoryEntity, error) {
+	return r.upsertOnContext(ctx, e, p, pqt.ConflictOnColumns(inf...))
+}
+func (r *categoryRepositoryBase) upsert(e *categoryEntity, p *categoryPatch, inf ...string) (*categoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.upsertContext(ctx, e, p, inf...)
+}
+
+// updateOneByIDQuery returns query and arguments used by updateOneByIDContext and its variants to modify the entity, without executing it.
+// Given columns are listed in RETURNING clause as they are, which is omitted if there are none.
+func (r *categoryRepositoryBase) updateOneByIDQuery(id int64, patch *categoryPatch, returning []string) (string, []interface{}, error) {
+	if err := patch.validate(); err != nil {
+		return "", nil, err
+	}
+	update := pqcomp.New(1, 7)
 	update.AddArg(id)
 
-	update.AddExpr(tablePackageColumnBreak, pqcomp.Equal, patch.brk)
-	update.AddExpr(tablePackageColumnCategoryID, pqcomp.Equal, patch.categoryID)
+	update.AddExpr(tableCategoryColumnContent, pqcomp.Equal, patch.content)
 	if patch.createdAt != nil {
-		update.AddExpr(tablePackageColumnCreatedAt, pqcomp.Equal, patch.createdAt)
+		update.AddExpr(tableCategoryColumnCreatedAt, pqcomp.Equal, patch.createdAt)
 
 	}
+	update.AddExpr(tableCategoryColumnDeletedAt, pqcomp.Equal, patch.deletedAt)
+	update.AddExpr(tableCategoryColumnName, pqcomp.Equal, patch.name)
+	update.AddExpr(tableCategoryColumnParentID, pqcomp.Equal, patch.parentID)
 	if patch.updatedAt != nil {
-		update.AddExpr(tablePackageColumnUpdatedAt, pqcomp.Equal, patch.updatedAt)
+		update.AddExpr(tableCategoryColumnUpdatedAt, pqcomp.Equal, patch.updatedAt)
 	} else {
-		update.AddExpr(tablePackageColumnUpdatedAt, pqcomp.Equal, "NOW()")
+		update.AddExpr(tableCategoryColumnUpdatedAt, pqcomp.Equal, "NOW()")
 	}
 
 	if update.Len() == 0 {
-		return nil, errors.New("package update failure, nothing to update")
+		return "", nil, pqt.ErrNothingToUpdate
 	}
-	query := "UPDATE example.package SET "
+	query := "UPDATE example.category SET "
 	for update.Next() {
 		if !update.First() {
 			query += ", "
@@ -1576,101 +2255,402 @@ func (r *packageRepositoryBase) updateOneByID(id int64, patch *packagePatch) (*p
 
 		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
 	}
-	query += " WHERE id = $1 RETURNING " + strings.Join(r.columns, ", ")
-	var e packageEntity
-	err := r.db.QueryRow(query, update.Args()...).Scan(
-		&e.brk,
-		&e.categoryID,
+	query += " WHERE id = $1"
+	if len(returning) > 0 {
+		query += " RETURNING " + strings.Join(returning, ", ")
+	}
+	return query, update.Args(), nil
+}
+
+// updateOneByIDContext modifies the entity using values set in the patch and returns it.
+// pqt.ErrNothingToUpdate is returned if the patch holds no value.
+func (r *categoryRepositoryBase) updateOneByIDContext(ctx context.Context, id int64, patch *categoryPatch) (*categoryEntity, error) {
+	if r.beforeUpdate != nil {
+		if err := r.beforeUpdate(ctx, id, patch); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := r.updateOneByIDQuery(id, patch, r.columns)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "UpdateOneByID"); err != nil {
+			return nil, err
+		}
+	}
+
+	var e categoryEntity
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.content,
 		&e.createdAt,
+		&e.deletedAt,
 		&e.id,
+		&e.name,
+		&e.parentID,
 		&e.updatedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if r.afterUpdate != nil {
+		if err := r.afterUpdate(ctx, &e); err != nil {
+			return nil, err
+		}
+	}
 
 	return &e, nil
 }
+func (r *categoryRepositoryBase) updateOneByID(id int64, patch *categoryPatch) (*categoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-func (r *packageRepositoryBase) deleteOneByID(id int64) (int64, error) {
-	query := "DELETE FROM example.package WHERE id = $1"
+	return r.updateOneByIDContext(ctx, id, patch)
+}
+
+// updateOneByIDReturningColumnsContext works like updateOneByIDContext, but returns entity with only given columns populated, unknown columns are rejected before execution.
+func (r *categoryRepositoryBase) updateOneByIDReturningColumnsContext(ctx context.Context, id int64, patch *categoryPatch, cols ...string) (*categoryEntity, error) {
+	if r.beforeUpdate != nil {
+		if err := r.beforeUpdate(ctx, id, patch); err != nil {
+			return nil, err
+		}
+	}
 
-	res, err := r.db.Exec(query, id)
+	if len(cols) == 0 {
+		return nil, errors.New("category update failure, no columns to return")
+	}
+	var ent categoryEntity
+	props, err := ent.props(cols...)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	query, args, err := r.updateOneByIDQuery(id, patch, cols)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "UpdateOneByIDReturningColumns"); err != nil {
+			return nil, err
+		}
 	}
 
-	return res.RowsAffected()
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(props...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
 }
+func (r *categoryRepositoryBase) updateOneByIDReturningColumns(id int64, patch *categoryPatch, cols ...string) (*categoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-const (
-	tableNews                          = "example.news"
-	tableNewsColumnContent             = "content"
-	tableNewsColumnContinue            = "continue"
-	tableNewsColumnCreatedAt           = "created_at"
-	tableNewsColumnID                  = "id"
-	tableNewsColumnLead                = "lead"
-	tableNewsColumnTitle               = "title"
-	tableNewsColumnUpdatedAt           = "updated_at"
-	tableNewsConstraintPrimaryKey      = "example.news_id_pkey"
-	tableNewsConstraintTitleUnique     = "example.news_title_key"
-	tableNewsConstraintTitleLeadUnique = "example.news_title_lead_key"
-)
+	return r.updateOneByIDReturningColumnsContext(ctx, id, patch, cols...)
+}
 
-var (
-	tableNewsColumns = []string{
-		tableNewsColumnContent,
-		tableNewsColumnContinue,
-		tableNewsColumnCreatedAt,
-		tableNewsColumnID,
-		tableNewsColumnLead,
-		tableNewsColumnTitle,
-		tableNewsColumnUpdatedAt,
+// patchOneByIDContext works like updateOneByIDContext, but returns number of affected rows instead of the entity.
+func (r *categoryRepositoryBase) patchOneByIDContext(ctx context.Context, id int64, patch *categoryPatch) (int64, error) {
+	if r.beforeUpdate != nil {
+		if err := r.beforeUpdate(ctx, id, patch); err != nil {
+			return 0, err
+		}
+	}
+	query, args, err := r.updateOneByIDQuery(id, patch, nil)
+	if err != nil {
+		return 0, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "PatchOneByID"); err != nil {
+			return 0, err
+		}
 	}
-)
 
-type newsEntity struct {
-	// content ...
-	content string
-	// cont ...
-	cont bool
-	// createdAt ...
-	createdAt time.Time
-	// id ...
-	id int64
-	// lead ...
-	lead *ntypes.String
-	// title ...
-	title string
-	// updatedAt ...
-	updatedAt *time.Time
-	// commentsByNewsTitle ...
-	commentsByNewsTitle []*commentEntity
-	// comments ...
-	comments []*commentEntity
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
 }
+func (r *categoryRepositoryBase) patchOneByID(id int64, patch *categoryPatch) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-func (e *newsEntity) prop(cn string) (interface{}, bool) {
-	switch cn {
-	case tableNewsColumnContent:
-		return &e.content, true
-	case tableNewsColumnContinue:
-		return &e.cont, true
-	case tableNewsColumnCreatedAt:
-		return &e.createdAt, true
-	case tableNewsColumnID:
-		return &e.id, true
-	case tableNewsColumnLead:
-		return &e.lead, true
-	case tableNewsColumnTitle:
-		return &e.title, true
-	case tableNewsColumnUpdatedAt:
+	return r.patchOneByIDContext(ctx, id, patch)
+}
+
+// hardDeleteOneByIDQuery returns query and arguments used by hardDeleteOneByIDContext to remove the entity, without executing it.
+func (r *categoryRepositoryBase) hardDeleteOneByIDQuery(id int64) (string, []interface{}, error) {
+	return "DELETE FROM example.category WHERE id = $1", []interface{}{id}, nil
+}
+
+// hardDeleteOneByIDContext removes the entity and returns number of affected rows.
+func (r *categoryRepositoryBase) hardDeleteOneByIDContext(ctx context.Context, id int64) (int64, error) {
+	if r.beforeDelete != nil {
+		if err := r.beforeDelete(ctx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	query, args, err := r.hardDeleteOneByIDQuery(id)
+	if err != nil {
+		return 0, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "HardDeleteOneByID"); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil || affected == 0 {
+		return affected, err
+	}
+	if r.afterDelete != nil {
+		if err := r.afterDelete(ctx, id); err != nil {
+			return affected, err
+		}
+	}
+
+	return affected, nil
+}
+func (r *categoryRepositoryBase) hardDeleteOneByID(id int64) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.hardDeleteOneByIDContext(ctx, id)
+}
+
+// hardDeleteAndReturnOneByIDContext removes the entity and returns it as it was right before removal.
+// pqt.ErrNotFound is returned if there is no such entity.
+func (r *categoryRepositoryBase) hardDeleteAndReturnOneByIDContext(ctx context.Context, id int64) (*categoryEntity, error) {
+	if r.beforeDelete != nil {
+		if err := r.beforeDelete(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := r.hardDeleteOneByIDQuery(id)
+	if err != nil {
+		return nil, err
+	}
+	query += " RETURNING " + strings.Join(r.columns, ", ")
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "HardDeleteAndReturnOneByID"); err != nil {
+			return nil, err
+		}
+	}
+
+	var e categoryEntity
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.content,
+		&e.createdAt,
+		&e.deletedAt,
+		&e.id,
+		&e.name,
+		&e.parentID,
+		&e.updatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, pqt.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.afterDelete != nil {
+		if err := r.afterDelete(ctx, id); err != nil {
+			return &e, err
+		}
+	}
+
+	return &e, nil
+}
+func (r *categoryRepositoryBase) hardDeleteAndReturnOneByID(id int64) (*categoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.hardDeleteAndReturnOneByIDContext(ctx, id)
+}
+
+// deleteByCriteriaContext removes entities that match given criteria and returns their number.
+// Empty criteria is rejected with pqt.ErrDeleteWithoutCriteria unless full scan is allowed. Sort, offset, limit and lock are not supported.
+func (r *categoryRepositoryBase) deleteByCriteriaContext(ctx context.Context, c *categoryCriteria, allowFullScan bool) (int64, error) {
+	if len(c.sort) > 0 || len(c.sortExpr) > 0 || c.Offset > 0 || c.Limit > 0 || c.lock != pqt.LockNone {
+		return 0, errors.New("category delete failure, sort, offset, limit and lock are not supported")
+	}
+
+	if !allowFullScan {
+		cc := *c
+		cc.includeDeleted = true
+		if where, _, err := r.plan(&cc); err != nil {
+			return 0, err
+		} else if where == "" {
+			return 0, pqt.ErrDeleteWithoutCriteria
+		}
+	}
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return 0, err
+	}
+	buf := bytes.NewBufferString("DELETE FROM ")
+	buf.WriteString(r.table)
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	} else if !allowFullScan {
+		return 0, pqt.ErrDeleteWithoutCriteria
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "DeleteByCriteria"); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := r.db.ExecContext(ctx, buf.String(), args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+func (r *categoryRepositoryBase) deleteByCriteria(c *categoryCriteria, allowFullScan bool) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.deleteByCriteriaContext(ctx, c, allowFullScan)
+}
+
+// softDeleteOneByIDContext marks entity as deleted instead of removing it, sql.ErrNoRows is returned if there is no such entity or it is already deleted.
+func (r *categoryRepositoryBase) softDeleteOneByIDContext(ctx context.Context, id int64) error {
+	if r.beforeDelete != nil {
+		if err := r.beforeDelete(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	query := "UPDATE example.category SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL"
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "SoftDeleteOneByID"); err != nil {
+			return err
+		}
+	}
+
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	if r.afterDelete != nil {
+		if err := r.afterDelete(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+func (r *categoryRepositoryBase) softDeleteOneByID(id int64) error {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.softDeleteOneByIDContext(ctx, id)
+}
+
+// truncateContext removes all rows from the table.
+// If cascade is true, tables that have foreign key references to the table are truncated as well.
+// If restartIdentity is true, sequences owned by columns of the table are restarted.
+func (r *categoryRepositoryBase) truncateContext(ctx context.Context, cascade, restartIdentity bool) error {
+	buf := bytes.NewBufferString("TRUNCATE ")
+	buf.WriteString(r.table)
+	if restartIdentity {
+		buf.WriteString(" RESTART IDENTITY")
+	}
+	if cascade {
+		buf.WriteString(" CASCADE")
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Truncate"); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.db.ExecContext(ctx, buf.String())
+	return err
+}
+func (r *categoryRepositoryBase) truncate(cascade, restartIdentity bool) error {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.truncateContext(ctx, cascade, restartIdentity)
+}
+
+const (
+	tablePackage                               = "example.package"
+	tablePackageColumnBreak                    = "break"
+	tablePackageColumnCategoryID               = "category_id"
+	tablePackageColumnCreatedAt                = "created_at"
+	tablePackageColumnID                       = "id"
+	tablePackageColumnUpdatedAt                = "updated_at"
+	tablePackageConstraintCategoryIDForeignKey = "example.package_category_id_fkey"
+	tablePackageConstraintPrimaryKey           = "example.package_id_pkey"
+)
+
+var (
+	tablePackageColumns = []string{
+		tablePackageColumnBreak,
+		tablePackageColumnCategoryID,
+		tablePackageColumnCreatedAt,
+		tablePackageColumnID,
+		tablePackageColumnUpdatedAt,
+	}
+)
+
+type packageEntity struct {
+	// brk ...
+	brk *ntypes.String
+	// categoryID ...
+	categoryID *ntypes.Int64
+	// createdAt ...
+	createdAt time.Time
+	// id ...
+	id int64
+	// updatedAt ...
+	updatedAt *time.Time
+	// category ...
+	category *categoryEntity
+}
+
+func (e *packageEntity) prop(cn string) (interface{}, bool) {
+	switch cn {
+	case tablePackageColumnBreak:
+		return &e.brk, true
+	case tablePackageColumnCategoryID:
+		return &e.categoryID, true
+	case tablePackageColumnCreatedAt:
+		return &e.createdAt, true
+	case tablePackageColumnID:
+		return &e.id, true
+	case tablePackageColumnUpdatedAt:
 		return &e.updatedAt, true
 	default:
 		return nil, false
 	}
 }
-func (e *newsEntity) props(cns ...string) ([]interface{}, error) {
+func (e *packageEntity) props(cns ...string) ([]interface{}, error) {
 
 	res := make([]interface{}, 0, len(cns))
 	for _, cn := range cns {
@@ -1682,27 +2662,153 @@ func (e *newsEntity) props(cns ...string) ([]interface{}, error) {
 	}
 	return res, nil
 }
+func (e *packageEntity) value(cn string) (interface{}, bool) {
+	switch cn {
+	case tablePackageColumnBreak:
+		return e.brk, true
+	case tablePackageColumnCategoryID:
+		return e.categoryID, true
+	case tablePackageColumnCreatedAt:
+		return e.createdAt, true
+	case tablePackageColumnID:
+		return e.id, true
+	case tablePackageColumnUpdatedAt:
+		return e.updatedAt, true
+	default:
+		return nil, false
+	}
+}
+func (e *packageEntity) values(cns ...string) ([]interface{}, error) {
+
+	res := make([]interface{}, 0, len(cns))
+	for _, cn := range cns {
+		if value, ok := e.value(cn); ok {
+			res = append(res, value)
+		} else {
+			return nil, fmt.Errorf("unexpected column provided: %s", cn)
+		}
+	}
+	return res, nil
+}
 
-// newsIterator is not thread safe.
-type newsIterator struct {
+// toMap returns values of the entity keyed by column names, nullable properties are either underlying value or nil.
+func (e *packageEntity) toMap() map[string]interface{} {
+	m := make(map[string]interface{}, 5)
+	if e.brk != nil && e.brk.Valid {
+		m[tablePackageColumnBreak] = e.brk.String
+	} else {
+		m[tablePackageColumnBreak] = nil
+	}
+	if e.categoryID != nil && e.categoryID.Valid {
+		m[tablePackageColumnCategoryID] = e.categoryID.Int64
+	} else {
+		m[tablePackageColumnCategoryID] = nil
+	}
+	m[tablePackageColumnCreatedAt] = e.createdAt
+	m[tablePackageColumnID] = e.id
+	if e.updatedAt != nil {
+		m[tablePackageColumnUpdatedAt] = *e.updatedAt
+	} else {
+		m[tablePackageColumnUpdatedAt] = nil
+	}
+
+	return m
+}
+
+// packageEntityFromMap returns entity built from given values keyed by column names, missing columns are left unset.
+// Error is returned if column is unknown or its value is not of the expected type, nil is accepted by nullable columns only.
+func packageEntityFromMap(m map[string]interface{}) (*packageEntity, error) {
+	e := &packageEntity{}
+	for cn, v := range m {
+		switch cn {
+		case tablePackageColumnBreak:
+			if v == nil {
+				e.brk = nil
+				continue
+			}
+			if tv, ok := v.(string); ok {
+				e.brk = &ntypes.String{String: tv, Valid: true}
+				continue
+			}
+			return nil, fmt.Errorf("package from map failure, column %s expects value of type string, got %T", cn, v)
+		case tablePackageColumnCategoryID:
+			if v == nil {
+				e.categoryID = nil
+				continue
+			}
+			if tv, ok := v.(int64); ok {
+				e.categoryID = &ntypes.Int64{Int64: tv, Valid: true}
+				continue
+			}
+			return nil, fmt.Errorf("package from map failure, column %s expects value of type int64, got %T", cn, v)
+		case tablePackageColumnCreatedAt:
+			if tv, ok := v.(time.Time); ok {
+				e.createdAt = tv
+				continue
+			}
+			return nil, fmt.Errorf("package from map failure, column %s expects value of type time.Time, got %T", cn, v)
+		case tablePackageColumnID:
+			if tv, ok := v.(int64); ok {
+				e.id = tv
+				continue
+			}
+			return nil, fmt.Errorf("package from map failure, column %s expects value of type int64, got %T", cn, v)
+		case tablePackageColumnUpdatedAt:
+			if v == nil {
+				e.updatedAt = nil
+				continue
+			}
+			if tv, ok := v.(time.Time); ok {
+				e.updatedAt = &tv
+				continue
+			}
+			return nil, fmt.Errorf("package from map failure, column %s expects value of type time.Time, got %T", cn, v)
+		default:
+			return nil, fmt.Errorf("package from map failure, unexpected column provided: %s", cn)
+		}
+	}
+
+	return e, nil
+}
+
+// validate returns pqt.ValidationError if any of mandatory properties is not set.
+func (e *packageEntity) validate() error {
+	var violations []pqt.Violation
+	if len(violations) > 0 {
+		return &pqt.ValidationError{Table: tablePackage, Violations: violations}
+	}
+
+	return nil
+}
+
+// packageIterator is not thread safe.
+// It has to be closed once it is no longer needed, otherwise underlying connection is not released.
+type packageIterator struct {
 	rows *sql.Rows
 	cols []string
+	// cancel releases context the rows are read within, if the iterator owns it.
+	cancel context.CancelFunc
 }
 
-func (i *newsIterator) Next() bool {
+var _ pqtgo.Iterator = &packageIterator{}
+
+func (i *packageIterator) Next() bool {
 	return i.rows.Next()
 }
 
-func (i *newsIterator) Close() error {
+func (i *packageIterator) Close() error {
+	if i.cancel != nil {
+		defer i.cancel()
+	}
 	return i.rows.Close()
 }
 
-func (i *newsIterator) Err() error {
+func (i *packageIterator) Err() error {
 	return i.rows.Err()
 }
 
 // Columns is wrapper around sql.Rows.Columns method, that also cache outpu inside iterator.
-func (i *newsIterator) Columns() ([]string, error) {
+func (i *packageIterator) Columns() ([]string, error) {
 	if i.cols == nil {
 		cols, err := i.rows.Columns()
 		if err != nil {
@@ -1713,13 +2819,13 @@ func (i *newsIterator) Columns() ([]string, error) {
 	return i.cols, nil
 }
 
-// Ent is wrapper around news method that makes iterator more generic.
-func (i *newsIterator) Ent() (interface{}, error) {
-	return i.News()
+// Ent is wrapper around package method, it allows to use the same method name regardless of the table.
+func (i *packageIterator) Ent() (*packageEntity, error) {
+	return i.Package()
 }
 
-func (i *newsIterator) News() (*newsEntity, error) {
-	var ent newsEntity
+func (i *packageIterator) Package() (*packageEntity, error) {
+	var ent packageEntity
 	cols, err := i.rows.Columns()
 	if err != nil {
 		return nil, err
@@ -1735,303 +2841,309 @@ func (i *newsIterator) News() (*newsEntity, error) {
 	return &ent, nil
 }
 
-type newsCriteria struct {
-	offset, limit int64
-	sort          map[string]bool
-	content       *qtypes.String
-	cont          *ntypes.Bool
-	createdAt     *qtypes.Timestamp
-	id            *qtypes.Int64
-	lead          *qtypes.String
-	title         *qtypes.String
-	updatedAt     *qtypes.Timestamp
+// packageCriteria selects entities that queries of the repository operate on, its zero value selects all of them.
+// Offset and Limit are translated into OFFSET and LIMIT clauses if non-zero, lock appends locking clause that is effective within a transaction only.
+// Keys of sort have to be columns of the table.
+// Expressions of sortExpr precede sort, they can refer to columns of the table and call functions listed in pqt.SortFunctions.
+type packageCriteria struct {
+	Offset, Limit   int64
+	sort            map[string]bool
+	sortExpr        []pqt.SortExpr
+	countDistinct   string
+	lock            pqt.LockMode
+	lateral         []pqt.LateralJoin
+	brk             *qtypes.String
+	categoryID      *qtypes.Int64
+	createdAt       *qtypes.Timestamp
+	createdAtBounds pqt.RangeBounds
+	id              *qtypes.Int64
+	updatedAt       *qtypes.Timestamp
+	updatedAtBounds pqt.RangeBounds
 }
 
-func (c *newsCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *pqtgo.CompositionOpts) (err error) {
+func (c *packageCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *pqtgo.CompositionOpts) (err error) {
 
-	if err = pqtgo.WriteCompositionQueryString(c.content, tableNewsColumnContent, com, pqtgo.And); err != nil {
+	if err = pqtgo.WriteCompositionQueryString(c.brk, tablePackageColumnBreak, com, pqtgo.And); err != nil {
 		return
 	}
-	if c.cont != nil && c.cont.Valid {
-		if com.Dirty {
-			com.WriteString(" AND ")
-		}
-		com.Dirty = true
-		com.WriteString(tableNewsColumnContinue)
-		com.WriteString(" = ")
-		com.WritePlaceholder()
-		com.Add(c.cont)
+
+	if err = pqtgo.WriteCompositionQueryInt64(c.categoryID, tablePackageColumnCategoryID, com, pqtgo.And); err != nil {
+		return
 	}
 
 	if c.createdAt != nil && c.createdAt.Valid {
-		createdAtt1 := c.createdAt.Value()
-		if createdAtt1 != nil {
-			createdAt1, err := ptypes.Timestamp(createdAtt1)
-			if err != nil {
-				return err
+		switch c.createdAt.Type {
+		case qtypes.QueryType_NULL:
+			if com.Dirty {
+				com.WriteString(" AND ")
 			}
-			switch c.createdAt.Type {
-			case qtypes.QueryType_NULL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+			com.Dirty = true
 
-				com.WriteString(tableNewsColumnCreatedAt)
-				if c.createdAt.Negation {
-					com.WriteString(" IS NOT NULL ")
-				} else {
-					com.WriteString(" IS NULL ")
-				}
-			case qtypes.QueryType_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+			com.WriteString(tablePackageColumnCreatedAt)
+			if c.createdAt.Negation {
+				com.WriteString(" IS NOT NULL ")
+			} else {
+				com.WriteString(" IS NULL ")
+			}
+		case qtypes.QueryType_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
 
-				com.WriteString(tableNewsColumnCreatedAt)
-				if c.createdAt.Negation {
-					com.WriteString(" <> ")
-				} else {
-					com.WriteString(" = ")
-				}
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_GREATER:
+			com.WriteString(tablePackageColumnCreatedAt)
+			if c.createdAt.Negation {
+				com.WriteString(" <> ")
+			} else {
+				com.WriteString(" = ")
+			}
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_GREATER:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tablePackageColumnCreatedAt)
+			com.WriteString(">")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_GREATER_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tablePackageColumnCreatedAt)
+			com.WriteString(">=")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_LESS:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tablePackageColumnCreatedAt)
+			com.WriteString(" < ")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_LESS_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tablePackageColumnCreatedAt)
+			com.WriteString(" <= ")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_IN:
+			if len(c.createdAt.Values) > 0 {
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
-				com.WriteString(tableNewsColumnCreatedAt)
-				com.WriteString(">")
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_GREATER_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
+				com.WriteString(tablePackageColumnCreatedAt)
+				com.WriteString(" IN (")
+				for i, v := range c.createdAt.Values {
+					if i != 0 {
+						com.WriteString(", ")
+					}
+					com.WritePlaceholder()
+					com.Add(v)
 				}
-				com.Dirty = true
-
-				com.WriteString(tableNewsColumnCreatedAt)
-				com.WriteString(">=")
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_LESS:
-				if com.Dirty {
-					com.WriteString(" AND ")
+				com.WriteString(") ")
+			}
+		case qtypes.QueryType_BETWEEN:
+			// Missing bound leaves the range open on that side.
+			if len(c.createdAt.Values) > 0 && c.createdAt.Values[0] != nil {
+				createdAt1, err := ptypes.Timestamp(c.createdAt.Values[0])
+				if err != nil {
+					return err
 				}
-				com.Dirty = true
-
-				com.WriteString(tableNewsColumnCreatedAt)
-				com.WriteString(" < ")
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_LESS_EQUAL:
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
-				com.WriteString(tableNewsColumnCreatedAt)
-				com.WriteString(" <= ")
+				com.WriteString(tablePackageColumnCreatedAt)
+				com.WriteString(" " + c.createdAtBounds.LowerOperator() + " ")
 				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_IN:
-				if len(c.createdAt.Values) > 0 {
-					if com.Dirty {
-						com.WriteString(" AND ")
-					}
-					com.Dirty = true
-
-					com.WriteString(tableNewsColumnCreatedAt)
-					com.WriteString(" IN (")
-					for i, v := range c.createdAt.Values {
-						if i != 0 {
-							com.WriteString(", ")
-						}
-						com.WritePlaceholder()
-						com.Add(v)
-					}
-					com.WriteString(") ")
+				com.Add(createdAt1)
+			}
+			if len(c.createdAt.Values) > 1 && c.createdAt.Values[1] != nil {
+				createdAt2, err := ptypes.Timestamp(c.createdAt.Values[1])
+				if err != nil {
+					return err
 				}
-			case qtypes.QueryType_BETWEEN:
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
-				createdAtt2 := c.createdAt.Values[1]
-				if createdAtt2 != nil {
-					createdAt2, err := ptypes.Timestamp(createdAtt2)
-					if err != nil {
-						return err
-					}
-					com.WriteString(tableNewsColumnCreatedAt)
-					com.WriteString(" > ")
-					com.WritePlaceholder()
-					com.Add(createdAt1)
-					com.WriteString(" AND ")
-					com.WriteString(tableNewsColumnCreatedAt)
-					com.WriteString(" < ")
-					com.WritePlaceholder()
-					com.Add(createdAt2)
-				}
+				com.WriteString(tablePackageColumnCreatedAt)
+				com.WriteString(" " + c.createdAtBounds.UpperOperator() + " ")
+				com.WritePlaceholder()
+				com.Add(createdAt2)
 			}
 		}
 	}
 
-	if err = pqtgo.WriteCompositionQueryInt64(c.id, tableNewsColumnID, com, pqtgo.And); err != nil {
-		return
-	}
-
-	if err = pqtgo.WriteCompositionQueryString(c.lead, tableNewsColumnLead, com, pqtgo.And); err != nil {
-		return
-	}
-
-	if err = pqtgo.WriteCompositionQueryString(c.title, tableNewsColumnTitle, com, pqtgo.And); err != nil {
+	if err = pqtgo.WriteCompositionQueryInt64(c.id, tablePackageColumnID, com, pqtgo.And); err != nil {
 		return
 	}
 
 	if c.updatedAt != nil && c.updatedAt.Valid {
-		updatedAtt1 := c.updatedAt.Value()
-		if updatedAtt1 != nil {
-			updatedAt1, err := ptypes.Timestamp(updatedAtt1)
-			if err != nil {
-				return err
+		switch c.updatedAt.Type {
+		case qtypes.QueryType_NULL:
+			if com.Dirty {
+				com.WriteString(" AND ")
 			}
-			switch c.updatedAt.Type {
-			case qtypes.QueryType_NULL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+			com.Dirty = true
 
-				com.WriteString(tableNewsColumnUpdatedAt)
-				if c.updatedAt.Negation {
-					com.WriteString(" IS NOT NULL ")
-				} else {
-					com.WriteString(" IS NULL ")
-				}
-			case qtypes.QueryType_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+			com.WriteString(tablePackageColumnUpdatedAt)
+			if c.updatedAt.Negation {
+				com.WriteString(" IS NOT NULL ")
+			} else {
+				com.WriteString(" IS NULL ")
+			}
+		case qtypes.QueryType_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
 
-				com.WriteString(tableNewsColumnUpdatedAt)
-				if c.updatedAt.Negation {
-					com.WriteString(" <> ")
-				} else {
-					com.WriteString(" = ")
-				}
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_GREATER:
+			com.WriteString(tablePackageColumnUpdatedAt)
+			if c.updatedAt.Negation {
+				com.WriteString(" <> ")
+			} else {
+				com.WriteString(" = ")
+			}
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_GREATER:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tablePackageColumnUpdatedAt)
+			com.WriteString(">")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_GREATER_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tablePackageColumnUpdatedAt)
+			com.WriteString(">=")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_LESS:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tablePackageColumnUpdatedAt)
+			com.WriteString(" < ")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_LESS_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tablePackageColumnUpdatedAt)
+			com.WriteString(" <= ")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_IN:
+			if len(c.updatedAt.Values) > 0 {
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
-				com.WriteString(tableNewsColumnUpdatedAt)
-				com.WriteString(">")
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_GREATER_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
+				com.WriteString(tablePackageColumnUpdatedAt)
+				com.WriteString(" IN (")
+				for i, v := range c.updatedAt.Values {
+					if i != 0 {
+						com.WriteString(", ")
+					}
+					com.WritePlaceholder()
+					com.Add(v)
 				}
-				com.Dirty = true
-
-				com.WriteString(tableNewsColumnUpdatedAt)
-				com.WriteString(">=")
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_LESS:
-				if com.Dirty {
-					com.WriteString(" AND ")
+				com.WriteString(") ")
+			}
+		case qtypes.QueryType_BETWEEN:
+			// Missing bound leaves the range open on that side.
+			if len(c.updatedAt.Values) > 0 && c.updatedAt.Values[0] != nil {
+				updatedAt1, err := ptypes.Timestamp(c.updatedAt.Values[0])
+				if err != nil {
+					return err
 				}
-				com.Dirty = true
-
-				com.WriteString(tableNewsColumnUpdatedAt)
-				com.WriteString(" < ")
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_LESS_EQUAL:
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
-				com.WriteString(tableNewsColumnUpdatedAt)
-				com.WriteString(" <= ")
+				com.WriteString(tablePackageColumnUpdatedAt)
+				com.WriteString(" " + c.updatedAtBounds.LowerOperator() + " ")
 				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_IN:
-				if len(c.updatedAt.Values) > 0 {
-					if com.Dirty {
-						com.WriteString(" AND ")
-					}
-					com.Dirty = true
-
-					com.WriteString(tableNewsColumnUpdatedAt)
-					com.WriteString(" IN (")
-					for i, v := range c.updatedAt.Values {
-						if i != 0 {
-							com.WriteString(", ")
-						}
-						com.WritePlaceholder()
-						com.Add(v)
-					}
-					com.WriteString(") ")
+				com.Add(updatedAt1)
+			}
+			if len(c.updatedAt.Values) > 1 && c.updatedAt.Values[1] != nil {
+				updatedAt2, err := ptypes.Timestamp(c.updatedAt.Values[1])
+				if err != nil {
+					return err
 				}
-			case qtypes.QueryType_BETWEEN:
 				if com.Dirty {
 					com.WriteString(" AND ")
 				}
 				com.Dirty = true
 
-				updatedAtt2 := c.updatedAt.Values[1]
-				if updatedAtt2 != nil {
-					updatedAt2, err := ptypes.Timestamp(updatedAtt2)
-					if err != nil {
-						return err
-					}
-					com.WriteString(tableNewsColumnUpdatedAt)
-					com.WriteString(" > ")
-					com.WritePlaceholder()
-					com.Add(updatedAt1)
-					com.WriteString(" AND ")
-					com.WriteString(tableNewsColumnUpdatedAt)
-					com.WriteString(" < ")
-					com.WritePlaceholder()
-					com.Add(updatedAt2)
-				}
+				com.WriteString(tablePackageColumnUpdatedAt)
+				com.WriteString(" " + c.updatedAtBounds.UpperOperator() + " ")
+				com.WritePlaceholder()
+				com.Add(updatedAt2)
 			}
 		}
 	}
 
-	if len(c.sort) > 0 {
-		i := 0
-		com.WriteString(" ORDER BY ")
+	return c.writeSuffix(com)
+}
 
-		for cn, asc := range c.sort {
-			for _, tcn := range tableNewsColumns {
-				if cn == tcn {
-					if i > 0 {
-						com.WriteString(", ")
-					}
-					com.WriteString(cn)
-					if !asc {
-						com.WriteString(" DESC ")
-					}
-					i++
-					break
-				}
+// writeSuffix writes ORDER BY, OFFSET, LIMIT and locking clauses that follow the condition.
+func (c *packageCriteria) writeSuffix(com *pqtgo.Composer) (err error) {
+SortLoop:
+	for cn := range c.sort {
+		for _, tcn := range tablePackageColumns {
+			if cn == tcn {
+				continue SortLoop
 			}
 		}
+		return fmt.Errorf("pqt: unknown sort column %q", cn)
 	}
-	if c.offset > 0 {
+
+	// Columns are ordered by name, map does not preserve the order and random one would make results unstable.
+	// Sort expressions, e.g. window functions, are ordered explicitly, so they come first.
+	if keys := pqtgo.Keyset(c.sort, tablePackageColumns); len(keys) > 0 || len(c.sortExpr) > 0 {
+		orderBy, err := pqtgo.OrderBy(c.sortExpr, keys, tablePackageColumns)
+		if err != nil {
+			return err
+		}
+		com.WriteString(" ORDER BY ")
+		com.WriteString(orderBy)
+		com.WriteString(" ")
+	}
+	if c.Offset > 0 {
 		if _, err = com.WriteString(" OFFSET "); err != nil {
 			return
 		}
@@ -2041,9 +3153,9 @@ func (c *newsCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *pq
 		if _, err = com.WriteString(" "); err != nil {
 			return
 		}
-		com.Add(c.offset)
+		com.Add(c.Offset)
 	}
-	if c.limit > 0 {
+	if c.Limit > 0 {
 		if _, err = com.WriteString(" LIMIT "); err != nil {
 			return
 		}
@@ -2053,43 +3165,165 @@ func (c *newsCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *pq
 		if _, err = com.WriteString(" "); err != nil {
 			return
 		}
-		com.Add(c.limit)
+		com.Add(c.Limit)
+	}
+	if c.lock != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.lock.String())
 	}
 
 	return
 }
 
-type newsPatch struct {
-	content   *ntypes.String
-	cont      *ntypes.Bool
-	createdAt *time.Time
-	lead      *ntypes.String
-	title     *ntypes.String
-	updatedAt *time.Time
+// whereClause returns condition built from the criteria, without WHERE keyword, and its arguments in order.
+// Placeholders are numbered starting at given index, so the clause can be embedded into a custom query.
+// Sort, offset, limit and lock are ignored.
+func (c *packageCriteria) whereClause(startIdx int) (string, []interface{}, error) {
+	cc := *c
+	cc.sort, cc.sortExpr, cc.Offset, cc.Limit, cc.lock = nil, nil, 0, 0, pqt.LockNone
+
+	com := pqtgo.NewComposerAt(5, startIdx)
+	if err := cc.WriteComposition("", com, pqtgo.And); err != nil {
+		return "", nil, err
+	}
+
+	return com.String(), com.Args(), nil
 }
 
-type newsRepositoryBase struct {
-	table   string
-	columns []string
-	db      *sql.DB
-	dbg     bool
-	log     log.Logger
+type packagePatch struct {
+	brk        *ntypes.String
+	categoryID *ntypes.Int64
+	createdAt  *time.Time
+	updatedAt  *time.Time
 }
 
-func scanNewsRows(rows *sql.Rows) ([]*newsEntity, error) {
+// validate returns pqt.ValidationError if patch sets NULL to any of NOT NULL columns.
+func (p *packagePatch) validate() error {
+	var violations []pqt.Violation
+	if len(violations) > 0 {
+		return &pqt.ValidationError{Table: tablePackage, Violations: violations}
+	}
+
+	return nil
+}
+
+// packagePage is a single page of entities returned by findPageContext method.
+// Cursors of the first and the last entity allow to request adjacent pages.
+type packagePage struct {
+	edges                    []*packageEntity
+	hasNextPage, hasPrevPage bool
+	startCursor, endCursor   string
+}
+
+// packageLateral is a single row returned by findLateralContext method.
+// Values of columns of lateral joins are keyed by alias.column.
+type packageLateral struct {
+	entity  *packageEntity
+	lateral map[string]interface{}
+}
+
+// packageBeforeInsertHook is called by insertContext, insertReturningColumnsContext, insertBatchContext, bulkInsertContext, upsertOnContext and upsertContext before the entity is stored, it can modify the entity, e.g. set columns from the context.
+// Returned error aborts the insert.
+type packageBeforeInsertHook func(ctx context.Context, e *packageEntity) error
+
+// packageAfterInsertHook is called by insertContext and insertBatchContext after the entity is stored, with values returned by the database.
+// It is not called by insertReturningColumnsContext and bulkInsertContext, they do not read back the whole entity.
+// It is not called by upsertOnContext and upsertContext either, they cannot tell inserted row from updated one.
+// Returned error is passed to the caller, but the entity is already stored, unless insert is executed within a transaction that is rolled back.
+type packageAfterInsertHook func(ctx context.Context, e *packageEntity) error
+
+// packageBeforeUpdateHook is called by updateOneByIDContext, updateOneByIDReturningColumnsContext and patchOneByIDContext before the entity is modified, it can modify the patch.
+// It is not called by upsertOnContext and upsertContext, they do not know the primary key in advance.
+// Returned error aborts the update.
+type packageBeforeUpdateHook func(ctx context.Context, id int64, patch *packagePatch) error
+
+// packageAfterUpdateHook is called by updateOneByIDContext after the entity is modified, with values returned by the database.
+// It is not called by updateOneByIDReturningColumnsContext and patchOneByIDContext, they do not read back the whole entity.
+// It is not called by upsertOnContext and upsertContext either, they cannot tell inserted row from updated one.
+// Returned error is passed to the caller, but the entity is already modified, unless update is executed within a transaction that is rolled back.
+type packageAfterUpdateHook func(ctx context.Context, e *packageEntity) error
+
+// packageBeforeDeleteHook is called by deleteOneByIDContext and deleteAndReturnOneByIDContext before the entity is removed.
+// It is not called by deleteByCriteriaContext.
+// Returned error aborts the delete.
+type packageBeforeDeleteHook func(ctx context.Context, id int64) error
+
+// packageAfterDeleteHook is called by deleteOneByIDContext and deleteAndReturnOneByIDContext after the entity is removed, it is not called if there was no such entity.
+// It is not called by deleteByCriteriaContext.
+// Returned error is passed to the caller, but the entity is already removed, unless delete is executed within a transaction that is rolled back.
+type packageAfterDeleteHook func(ctx context.Context, id int64) error
+
+// packageRepositoryBase is a data access layer of the example.package table.
+// Optional explain hook receives execution plans of read only queries in debug mode, optional planner builds conditions out of criteria instead of them.
+// Methods called without context are bounded by timeout, if it is set.
+type packageRepositoryBase struct {
+	table        string
+	columns      []string
+	db           pqtgo.Querier
+	dbg          bool
+	log          log.Logger
+	bulkSize     int
+	explain      pqt.ExplainHook
+	planner      pqt.Planner
+	beforeInsert packageBeforeInsertHook
+	afterInsert  packageAfterInsertHook
+	beforeUpdate packageBeforeUpdateHook
+	afterUpdate  packageAfterUpdateHook
+	beforeDelete packageBeforeDeleteHook
+	afterDelete  packageAfterDeleteHook
+	timeout      time.Duration
+}
+
+// withTx returns copy of the repository that executes all queries within given transaction.
+func (r *packageRepositoryBase) withTx(tx *sql.Tx) *packageRepositoryBase {
+	rt := *r
+	rt.db = tx
+
+	return &rt
+}
+
+// explainQuery runs given query using EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) and passes the plan to the explain hook.
+// As the query is executed, it is called in debug mode by read only methods only.
+func (r *packageRepositoryBase) explainQuery(ctx context.Context, query string, args []interface{}) error {
+	var plan []byte
+	if err := r.db.QueryRowContext(ctx, "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+query, args...).Scan(&plan); err != nil {
+		return err
+	}
+	r.explain(json.RawMessage(plan), query, args)
+
+	return nil
+}
+
+// plan returns condition of given criteria and its arguments, built by the planner if it is set.
+func (r *packageRepositoryBase) plan(c *packageCriteria) (string, []interface{}, error) {
+	if r.planner != nil {
+		return r.planner.Plan(c)
+	}
+	return c.whereClause(1)
+}
+
+// defaultContext returns context used by methods called without one, it is bounded by the timeout of the repository, if it is set.
+// Context given explicitly by the caller is used as it is.
+func (r *packageRepositoryBase) defaultContext() (context.Context, context.CancelFunc) {
+	if r.timeout > 0 {
+		return context.WithTimeout(context.Background(), r.timeout)
+	}
+	return context.Background(), func() {}
+}
+
+// scanPackageRows reads all rows into entities, each row has to consist of columns listed in tablePackageColumns, in the same order.
+func scanPackageRows(rows *sql.Rows) ([]*packageEntity, error) {
 	var (
-		entities []*newsEntity
+		entities []*packageEntity
 		err      error
 	)
 	for rows.Next() {
-		var ent newsEntity
+		var ent packageEntity
 		err = rows.Scan(
-			&ent.content,
-			&ent.cont,
+			&ent.brk,
+			&ent.categoryID,
 			&ent.createdAt,
 			&ent.id,
-			&ent.lead,
-			&ent.title,
 			&ent.updatedAt,
 		)
 		if err != nil {
@@ -2105,1210 +3339,8887 @@ func scanNewsRows(rows *sql.Rows) ([]*newsEntity, error) {
 	return entities, nil
 }
 
-func (r *newsRepositoryBase) count(c *newsCriteria) (int64, error) {
+// scanPackageRow reads single row into entity, the row has to consist of columns listed in tablePackageColumns, in the same order.
+// sql.ErrNoRows is returned if the query selected no rows.
+func scanPackageRow(row *sql.Row) (*packageEntity, error) {
+	var ent packageEntity
+	err := row.Scan(
+		&ent.brk,
+		&ent.categoryID,
+		&ent.createdAt,
+		&ent.id,
+		&ent.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
 
-	com := pqtgo.NewComposer(7)
-	buf := bytes.NewBufferString("SELECT COUNT(*) FROM ")
+// countQuery returns query and arguments used by countContext to count entities that match given criteria, without executing it.
+func (r *packageRepositoryBase) countQuery(c *packageCriteria) (string, []interface{}, error) {
+
+	buf := bytes.NewBufferString("SELECT ")
+	if c.countDistinct != "" {
+		var known bool
+		for _, cn := range tablePackageColumns {
+			if cn == c.countDistinct {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return "", nil, fmt.Errorf("package count failure, unknown column: %s", c.countDistinct)
+		}
+		buf.WriteString("COUNT(DISTINCT " + c.countDistinct + ")")
+	} else {
+		buf.WriteString("COUNT(*)")
+	}
+	buf.WriteString(" FROM ")
 	buf.WriteString(r.table)
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
-		return 0, err
+	// The same condition as used by find, but without sort, offset and limit that make no sense for an aggregate.
+	where, args, err := r.plan(c)
+	if err != nil {
+		return "", nil, err
 	}
-	if com.Dirty {
+	if where != "" {
 		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
+
+	return buf.String(), args, nil
+}
+
+// countContext returns number of entities that match given criteria. Sort, offset, limit and lock are ignored.
+func (r *packageRepositoryBase) countContext(ctx context.Context, c *packageCriteria) (int64, error) {
+	query, args, err := r.countQuery(c)
+	if err != nil {
+		return 0, err
 	}
 
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Count"); err != nil {
+		if err := r.log.Log("msg", query, "function", "Count"); err != nil {
 			return 0, err
 		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return 0, err
+			}
+		}
 	}
 
 	var count int64
-	if err := r.db.QueryRow(buf.String(), com.Args()...).Scan(&count); err != nil {
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
 		return 0, err
 	}
 	return count, nil
 }
+func (r *packageRepositoryBase) count(c *packageCriteria) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-func (r *newsRepositoryBase) find(c *newsCriteria) ([]*newsEntity, error) {
+	return r.countContext(ctx, c)
+}
 
-	com := pqtgo.NewComposer(1)
-	buf := bytes.NewBufferString("SELECT ")
-	buf.WriteString(strings.Join(r.columns, ", "))
-	buf.WriteString(" FROM ")
+// countDistinctContext returns number of distinct values of given column among entities that match given criteria.
+func (r *packageRepositoryBase) countDistinctContext(ctx context.Context, cn string, c *packageCriteria) (int64, error) {
+	cc := *c
+	cc.countDistinct = cn
+
+	return r.countContext(ctx, &cc)
+}
+func (r *packageRepositoryBase) countDistinct(cn string, c *packageCriteria) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.countDistinctContext(ctx, cn, c)
+}
+
+// existsContext returns true if at least one entity matches given criteria, database stops at the first matching row.
+// Sort, offset and limit are ignored.
+func (r *packageRepositoryBase) existsContext(ctx context.Context, c *packageCriteria) (bool, error) {
+	buf := bytes.NewBufferString("SELECT EXISTS (SELECT 1 FROM ")
 	buf.WriteString(r.table)
-	buf.WriteString(" ")
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
-		return nil, err
+	where, args, err := r.plan(c)
+	if err != nil {
+		return false, err
 	}
-	if com.Dirty {
+	if where != "" {
 		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
-	}
+	buf.WriteString(")")
 
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Find"); err != nil {
-			return nil, err
+		if err := r.log.Log("msg", buf.String(), "function", "Exists"); err != nil {
+			return false, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return false, err
+			}
 		}
 	}
 
-	rows, err := r.db.Query(buf.String(), com.Args()...)
-	if err != nil {
-		return nil, err
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, buf.String(), args...).Scan(&exists); err != nil {
+		return false, err
 	}
+	return exists, nil
+}
+func (r *packageRepositoryBase) exists(c *packageCriteria) (bool, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-	defer rows.Close()
-
-	return scanNewsRows(rows)
+	return r.existsContext(ctx, c)
 }
-func (r *newsRepositoryBase) findIter(c *newsCriteria) (*newsIterator, error) {
 
-	com := pqtgo.NewComposer(1)
+// aggregateContext computes given aggregate function of the column among entities that match given criteria, grouped by values of groupBy column.
+// Column can be empty for pqt.AggregateCount, which then counts entities. If groupBy is empty, single result with nil group is returned.
+// Groups are ordered by their values. Sort, offset, limit and lock are ignored.
+// Unknown functions and columns are rejected, as well as SUM and AVG of non-numeric column, whose MIN and MAX are returned in Raw field.
+func (r *packageRepositoryBase) aggregateContext(ctx context.Context, c *packageCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error) {
+	if fn.String() == "" {
+		return nil, fmt.Errorf("package aggregate failure, unknown function: %d", fn)
+	}
+	if column == "" && fn != pqt.AggregateCount {
+		return nil, fmt.Errorf("package aggregate failure, column is required by %s", fn)
+	}
+	for _, cn := range []string{column, groupBy} {
+		if cn == "" {
+			continue
+		}
+		var known bool
+		for _, tc := range tablePackageColumns {
+			if tc == cn {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("package aggregate failure, unknown column: %s", cn)
+		}
+	}
+	// Result of COUNT and of any function of a numeric column is a number, other values are scanned as they are.
+	numeric := fn == pqt.AggregateCount
+	switch column {
+	case tablePackageColumnCategoryID, tablePackageColumnID:
+		numeric = true
+	}
+	if !numeric && (fn == pqt.AggregateSum || fn == pqt.AggregateAvg) {
+		return nil, fmt.Errorf("package aggregate failure, %s requires numeric column, got: %s", fn, column)
+	}
+
+	expr := "*"
+	if column != "" {
+		expr = column
+	}
 	buf := bytes.NewBufferString("SELECT ")
-	buf.WriteString(strings.Join(r.columns, ", "))
+	if groupBy != "" {
+		buf.WriteString(groupBy)
+		buf.WriteString(", ")
+	}
+	buf.WriteString(fn.String() + "(" + expr + ")")
 	buf.WriteString(" FROM ")
 	buf.WriteString(r.table)
-	buf.WriteString(" ")
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
+	where, args, err := r.plan(c)
+	if err != nil {
 		return nil, err
 	}
-	if com.Dirty {
+	if where != "" {
 		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
+	if groupBy != "" {
+		buf.WriteString(" GROUP BY " + groupBy + " ORDER BY " + groupBy)
 	}
 
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Find"); err != nil {
+		if err := r.log.Log("msg", buf.String(), "function", "Aggregate"); err != nil {
 			return nil, err
 		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	rows, err := r.db.Query(buf.String(), com.Args()...)
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return &newsIterator{rows: rows}, nil
-}
-func (r *newsRepositoryBase) findOneByID(id int64) (*newsEntity, error) {
-	var (
-		ent newsEntity
-	)
-	query := `SELECT content,
-continue,
-created_at,
-id,
-lead,
-title,
-updated_at
- FROM example.news WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(
-		&ent.content,
-		&ent.cont,
-		&ent.createdAt,
-		&ent.id,
-		&ent.lead,
-		&ent.title,
-		&ent.updatedAt,
-	)
+	var res []pqt.AggregateResult
+	for rows.Next() {
+		var (
+			group, raw interface{}
+			// Aggregate of a group that has no values, e.g. SUM of nulls, is null.
+			value sql.NullFloat64
+		)
+		dest := []interface{}{&value}
+		if !numeric {
+			dest[0] = &raw
+		}
+		if groupBy != "" {
+			dest = append([]interface{}{&group}, dest...)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		res = append(res, pqt.AggregateResult{Group: group, Value: value.Float64, Raw: raw})
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return res, nil
+}
+func (r *packageRepositoryBase) aggregate(c *packageCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.aggregateContext(ctx, c, fn, column, groupBy)
+}
+
+// findQuery returns query and arguments used by findContext to retrieve entities that match given criteria, without executing it.
+func (r *packageRepositoryBase) findQuery(c *packageCriteria) (string, []interface{}, error) {
+	where, args, err := r.plan(c)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
-	return &ent, nil
+	buf := bytes.NewBufferString("SELECT ")
+	buf.WriteString(strings.Join(r.columns, ", "))
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+
+	com := pqtgo.NewComposerAt(2, len(args)+1)
+	if err := c.writeSuffix(com); err != nil {
+		return "", nil, err
+	}
+	buf.ReadFrom(com)
+
+	return buf.String(), append(args, com.Args()...), nil
 }
-func (r *newsRepositoryBase) findOneByTitle(title string) (*newsEntity, error) {
-	var (
-		ent newsEntity
-	)
-	query := `SELECT content, continue, created_at, id, lead, title, updated_at FROM example.news WHERE title = $1`
-	err := r.db.QueryRow(query, title).Scan(
-		&ent.content,
-		&ent.cont,
-		&ent.createdAt,
-		&ent.id,
-		&ent.lead,
-		&ent.title,
-		&ent.updatedAt,
-	)
+
+// findContext returns entities that match given criteria.
+func (r *packageRepositoryBase) findContext(ctx context.Context, c *packageCriteria) ([]*packageEntity, error) {
+
+	query, args, err := r.findQuery(c)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ent, nil
-}
-func (r *newsRepositoryBase) findOneByTitleAndLead(title string, lead string) (*newsEntity, error) {
-	var (
-		ent newsEntity
-	)
-	query := `SELECT content, continue, created_at, id, lead, title, updated_at FROM example.news WHERE title = $1 AND lead = $2`
-	err := r.db.QueryRow(query, title, lead).Scan(
-		&ent.content,
-		&ent.cont,
-		&ent.createdAt,
-		&ent.id,
-		&ent.lead,
-		&ent.title,
-		&ent.updatedAt,
-	)
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Find"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ent, nil
-}
-func (r *newsRepositoryBase) insert(e *newsEntity) (*newsEntity, error) {
-	insert := pqcomp.New(0, 7)
-	insert.AddExpr(tableNewsColumnContent, "", e.content)
-	insert.AddExpr(tableNewsColumnContinue, "", e.cont)
-	insert.AddExpr(tableNewsColumnCreatedAt, "", e.createdAt)
-	insert.AddExpr(tableNewsColumnLead, "", e.lead)
-	insert.AddExpr(tableNewsColumnTitle, "", e.title)
-	insert.AddExpr(tableNewsColumnUpdatedAt, "", e.updatedAt)
+	defer rows.Close()
 
-	b := bytes.NewBufferString("INSERT INTO " + r.table)
+	return scanPackageRows(rows)
+}
+func (r *packageRepositoryBase) find(c *packageCriteria) ([]*packageEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-	if insert.Len() != 0 {
-		b.WriteString(" (")
-		for insert.Next() {
-			if !insert.First() {
-				b.WriteString(", ")
-			}
+	return r.findContext(ctx, c)
+}
 
-			fmt.Fprintf(b, "%s", insert.Key())
-		}
-		insert.Reset()
-		b.WriteString(") VALUES (")
-		for insert.Next() {
-			if !insert.First() {
-				b.WriteString(", ")
-			}
+// findIterContext returns iterator over entities that match given criteria.
+// Caller is responsible for closing the iterator, also if the loop exits early.
+func (r *packageRepositoryBase) findIterContext(ctx context.Context, c *packageCriteria) (*packageIterator, error) {
 
-			fmt.Fprintf(b, "%s", insert.PlaceHolder())
-		}
-		b.WriteString(")")
-		if len(r.columns) > 0 {
-			b.WriteString(" RETURNING ")
-			b.WriteString(strings.Join(r.columns, ", "))
-		}
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
 	}
 
 	if r.dbg {
-		if err := r.log.Log("msg", b.String(), "function", "Insert"); err != nil {
+		if err := r.log.Log("msg", query, "function", "Find"); err != nil {
 			return nil, err
 		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	err := r.db.QueryRow(b.String(), insert.Args()...).Scan(
-		&e.content,
-		&e.cont,
-		&e.createdAt,
-		&e.id,
-		&e.lead,
-		&e.title,
-		&e.updatedAt,
-	)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 
-	return e, nil
+	return &packageIterator{rows: rows}, nil
 }
-func (r *newsRepositoryBase) upsert(e *newsEntity, p *newsPatch, inf ...string) (*newsEntity, error) {
-	insert := pqcomp.New(0, 7)
-	update := insert.Compose(7)
-	insert.AddExpr(tableNewsColumnContent, "", e.content)
-	insert.AddExpr(tableNewsColumnContinue, "", e.cont)
-	insert.AddExpr(tableNewsColumnCreatedAt, "", e.createdAt)
-	insert.AddExpr(tableNewsColumnLead, "", e.lead)
-	insert.AddExpr(tableNewsColumnTitle, "", e.title)
-	insert.AddExpr(tableNewsColumnUpdatedAt, "", e.updatedAt)
-	if len(inf) > 0 {
-		update.AddExpr(tableNewsColumnContent, "=", p.content)
-		update.AddExpr(tableNewsColumnContinue, "=", p.cont)
-		update.AddExpr(tableNewsColumnCreatedAt, "=", p.createdAt)
-		update.AddExpr(tableNewsColumnLead, "=", p.lead)
-		update.AddExpr(tableNewsColumnTitle, "=", p.title)
-		update.AddExpr(tableNewsColumnUpdatedAt, "=", p.updatedAt)
+func (r *packageRepositoryBase) findIter(c *packageCriteria) (*packageIterator, error) {
+	ctx, cancel := r.defaultContext()
+	it, err := r.findIterContext(ctx, c)
+	if err != nil {
+		cancel()
+		return nil, err
 	}
+	it.cancel = cancel
 
-	b := bytes.NewBufferString("INSERT INTO " + r.table)
+	return it, nil
+}
 
-	if insert.Len() > 0 {
-		b.WriteString(" (")
-		for insert.Next() {
-			if !insert.First() {
-				b.WriteString(", ")
-			}
+// findPageContext returns page of entities that match given criteria using cursor based (keyset) pagination.
+// Rows are ordered by sort columns of the criteria, followed by primary key, so cursors remain valid if rows are inserted or deleted.
+// Limit of the criteria defines size of the page, offset is not supported.
+// Values held by cursors are decoded into properties of the entity, so they keep types of their columns.
+func (r *packageRepositoryBase) findPageContext(ctx context.Context, c *packageCriteria, page pqt.CursorPage) (*packagePage, error) {
+	if c.Offset > 0 {
+		return nil, errors.New("package find page failure, offset is not supported")
+	}
+	if len(c.sortExpr) > 0 {
+		return nil, errors.New("package find page failure, sort expressions are not supported")
+	}
+	if c.Limit <= 0 {
+		return nil, errors.New("package find page failure, limit has to be positive")
+	}
+	if page.After != "" && page.Before != "" {
+		return nil, errors.New("package find page failure, after and before cursors cannot be used together")
+	}
 
-			fmt.Fprintf(b, "%s", insert.Key())
-		}
-		insert.Reset()
-		b.WriteString(") VALUES (")
-		for insert.Next() {
-			if !insert.First() {
-				b.WriteString(", ")
+SortLoop:
+	for cn := range c.sort {
+		for _, tcn := range tablePackageColumns {
+			if cn == tcn {
+				continue SortLoop
 			}
-
-			fmt.Fprintf(b, "%s", insert.PlaceHolder())
 		}
-		b.WriteString(")")
+		return nil, fmt.Errorf("pqt: unknown sort column %q", cn)
 	}
-	b.WriteString(" ON CONFLICT ")
-	if len(inf) > 0 && update.Len() > 0 {
-		b.WriteString(" (")
-		for j, i := range inf {
-			if j != 0 {
-				b.WriteString(", ")
-			}
-			b.WriteString(i)
-		}
-		b.WriteString(") ")
-		b.WriteString(" DO UPDATE SET ")
-		for update.Next() {
-			if !update.First() {
-				b.WriteString(", ")
-			}
 
-			b.WriteString(update.Key())
-			b.WriteString(" ")
-			b.WriteString(update.Oper())
-			b.WriteString(" ")
-			b.WriteString(update.PlaceHolder())
+	keys := pqtgo.Keyset(c.sort, tablePackageColumns, tablePackageColumnID)
+	if len(keys) == 0 {
+		return nil, errors.New("package find page failure, sort or primary key is required")
+	}
+	cursor := func(ent *packageEntity) (string, error) {
+		values := make(map[string]interface{}, len(keys))
+		for _, k := range keys {
+			values[k.Name], _ = ent.value(k.Name)
 		}
-	} else {
-		b.WriteString(" DO NOTHING ")
+		return pqt.EncodeCursor(values)
 	}
-	if insert.Len() > 0 {
-		if len(r.columns) > 0 {
-			b.WriteString(" RETURNING ")
-			b.WriteString(strings.Join(r.columns, ", "))
+
+	// Previous page is fetched by traversing rows in opposite order, starting from the cursor.
+	from, backward := page.After, page.Before != ""
+	order := keys
+	if backward {
+		from, order = page.Before, pqtgo.ReverseKeyset(keys)
+	}
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return nil, err
+	}
+	com := pqtgo.NewComposerAt(5, len(args)+1)
+	for _, arg := range args {
+		com.Add(arg)
+	}
+	if where != "" {
+		// Condition of the planner can consist of alternatives, keyset condition is added to all of them.
+		com.WriteString("(" + where + ")")
+		com.Dirty = true
+	}
+	buf := bytes.NewBufferString("SELECT ")
+	buf.WriteString(strings.Join(r.columns, ", "))
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+
+	if from != "" {
+		// Cursor values are decoded into properties of the entity, so they keep types of the columns.
+		var cur packageEntity
+		dest := make(map[string]interface{}, len(order))
+		for _, k := range order {
+			dest[k.Name], _ = cur.prop(k.Name)
+		}
+		if err := pqt.DecodeCursorTo(from, dest); err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, 0, len(order))
+		for _, k := range order {
+			v, _ := cur.value(k.Name)
+			values = append(values, v)
+		}
+		if com.Dirty {
+			com.WriteString(" AND ")
+		}
+		if err := pqtgo.WriteKeysetCondition(com, order, values); err != nil {
+			return nil, err
 		}
+		com.Dirty = true
+	}
+	if com.Dirty {
+		buf.WriteString(" WHERE ")
 	}
+	com.WriteString(" ORDER BY ")
+	com.WriteString(pqtgo.KeysetOrderBy(order))
+	com.WriteString(" LIMIT ")
+	if err := com.WritePlaceholder(); err != nil {
+		return nil, err
+	}
+	// One more row tells if there is another page in the direction of traversal.
+	com.Add(c.Limit + 1)
+	if c.lock != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.lock.String())
+	}
+	buf.ReadFrom(com)
 
 	if r.dbg {
-		if err := r.log.Log("msg", b.String(), "function", "Upsert"); err != nil {
+		if err := r.log.Log("msg", buf.String(), "function", "FindPage"); err != nil {
 			return nil, err
 		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), com.Args()); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	err := r.db.QueryRow(b.String(), insert.Args()...).Scan(
-		&e.content,
-		&e.cont,
-		&e.createdAt,
-		&e.id,
-		&e.lead,
-		&e.title,
-		&e.updatedAt,
-	)
+	rows, err := r.db.QueryContext(ctx, buf.String(), com.Args()...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return e, nil
-}
-func (r *newsRepositoryBase) updateOneByID(id int64, patch *newsPatch) (*newsEntity, error) {
-	update := pqcomp.New(1, 7)
-	update.AddArg(id)
-
-	update.AddExpr(tableNewsColumnContent, pqcomp.Equal, patch.content)
-	update.AddExpr(tableNewsColumnContinue, pqcomp.Equal, patch.cont)
-	if patch.createdAt != nil {
-		update.AddExpr(tableNewsColumnCreatedAt, pqcomp.Equal, patch.createdAt)
-
+	ents, err := scanPackageRows(rows)
+	if err != nil {
+		return nil, err
 	}
-	update.AddExpr(tableNewsColumnLead, pqcomp.Equal, patch.lead)
-	update.AddExpr(tableNewsColumnTitle, pqcomp.Equal, patch.title)
-	if patch.updatedAt != nil {
-		update.AddExpr(tableNewsColumnUpdatedAt, pqcomp.Equal, patch.updatedAt)
-	} else {
-		update.AddExpr(tableNewsColumnUpdatedAt, pqcomp.Equal, "NOW()")
+	more := int64(len(ents)) > c.Limit
+	if more {
+		ents = ents[:c.Limit]
+	}
+	if backward {
+		for i, j := 0, len(ents)-1; i < j; i, j = i+1, j-1 {
+			ents[i], ents[j] = ents[j], ents[i]
+		}
 	}
 
-	if update.Len() == 0 {
-		return nil, errors.New("news update failure, nothing to update")
+	res := &packagePage{edges: ents}
+	if backward {
+		res.hasNextPage, res.hasPrevPage = true, more
+	} else {
+		res.hasNextPage, res.hasPrevPage = more, page.After != ""
 	}
-	query := "UPDATE example.news SET "
-	for update.Next() {
-		if !update.First() {
-			query += ", "
+	if len(ents) > 0 {
+		if res.startCursor, err = cursor(ents[0]); err != nil {
+			return nil, err
+		}
+		if res.endCursor, err = cursor(ents[len(ents)-1]); err != nil {
+			return nil, err
 		}
-
-		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
-	}
-	query += " WHERE id = $1 RETURNING " + strings.Join(r.columns, ", ")
-	var e newsEntity
-	err := r.db.QueryRow(query, update.Args()...).Scan(
-		&e.content,
-		&e.cont,
-		&e.createdAt,
-		&e.id,
-		&e.lead,
-		&e.title,
-		&e.updatedAt,
-	)
-	if err != nil {
-		return nil, err
 	}
 
-	return &e, nil
+	return res, nil
 }
-func (r *newsRepositoryBase) updateOneByTitle(title string, patch *newsPatch) (*newsEntity, error) {
-	update := pqcomp.New(1, 7)
-	update.AddArg(title)
-	update.AddExpr(tableNewsColumnContent, pqcomp.Equal, patch.content)
-	update.AddExpr(tableNewsColumnContinue, pqcomp.Equal, patch.cont)
-	if patch.createdAt != nil {
-		update.AddExpr(tableNewsColumnCreatedAt, pqcomp.Equal, patch.createdAt)
+func (r *packageRepositoryBase) findPage(c *packageCriteria, page pqt.CursorPage) (*packagePage, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-	}
-	update.AddExpr(tableNewsColumnLead, pqcomp.Equal, patch.lead)
-	update.AddExpr(tableNewsColumnTitle, pqcomp.Equal, patch.title)
-	if patch.updatedAt != nil {
-		update.AddExpr(tableNewsColumnUpdatedAt, pqcomp.Equal, patch.updatedAt)
-	} else {
-		update.AddExpr(tableNewsColumnUpdatedAt, pqcomp.Equal, "NOW()")
-	}
+	return r.findPageContext(ctx, c, page)
+}
 
-	if update.Len() == 0 {
-		return nil, errors.New("news update failure, nothing to update")
+// findAndCountContext returns entities that match given criteria together with total number of matching entities, regardless of offset and limit.
+// Total is selected by the same query using COUNT(*) OVER() window function, which is evaluated before OFFSET and LIMIT are applied.
+// If the page is empty, but offset is set, total is obtained using countContext, so it is known even if the offset goes past the last entity.
+// Window functions cannot be combined with locking clause, so lock is not supported.
+func (r *packageRepositoryBase) findAndCountContext(ctx context.Context, c *packageCriteria) ([]*packageEntity, int64, error) {
+	if c.lock != pqt.LockNone {
+		return nil, 0, errors.New("package find and count failure, lock is not supported")
 	}
-	query := "UPDATE example.news SET "
-	for update.Next() {
-		if !update.First() {
-			query += ", "
-		}
 
-		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
+	rr := *r
+	rr.columns = append(r.columns[:len(r.columns):len(r.columns)], "COUNT(*) OVER()")
+	query, args, err := rr.findQuery(c)
+	if err != nil {
+		return nil, 0, err
 	}
-	query += " WHERE title = $1 RETURNING " + strings.Join(r.columns, ", ")
+
 	if r.dbg {
-		if err := r.log.Log("msg", query, "function", "UpdateOneByTitle"); err != nil {
-			return nil, err
+		if err := r.log.Log("msg", query, "function", "FindAndCount"); err != nil {
+			return nil, 0, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, 0, err
+			}
 		}
 	}
-	var e newsEntity
-	err := r.db.QueryRow(query, update.Args()...).Scan(
-		&e.content,
-		&e.cont,
-		&e.createdAt,
-		&e.id,
-		&e.lead,
-		&e.title,
-		&e.updatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	return &e, nil
-}
-func (r *newsRepositoryBase) updateOneByTitleAndLead(title string, lead string, patch *newsPatch) (*newsEntity, error) {
-	update := pqcomp.New(2, 7)
-	update.AddArg(title)
-	update.AddArg(lead)
-	update.AddExpr(tableNewsColumnContent, pqcomp.Equal, patch.content)
-	update.AddExpr(tableNewsColumnContinue, pqcomp.Equal, patch.cont)
-	if patch.createdAt != nil {
-		update.AddExpr(tableNewsColumnCreatedAt, pqcomp.Equal, patch.createdAt)
 
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
-	update.AddExpr(tableNewsColumnLead, pqcomp.Equal, patch.lead)
-	update.AddExpr(tableNewsColumnTitle, pqcomp.Equal, patch.title)
-	if patch.updatedAt != nil {
-		update.AddExpr(tableNewsColumnUpdatedAt, pqcomp.Equal, patch.updatedAt)
-	} else {
-		update.AddExpr(tableNewsColumnUpdatedAt, pqcomp.Equal, "NOW()")
-	}
+	defer rows.Close()
 
-	if update.Len() == 0 {
-		return nil, errors.New("news update failure, nothing to update")
-	}
-	query := "UPDATE example.news SET "
-	for update.Next() {
-		if !update.First() {
-			query += ", "
+	var (
+		entities []*packageEntity
+		total    int64
+	)
+	for rows.Next() {
+		var ent packageEntity
+		err = rows.Scan(
+			&ent.brk,
+			&ent.categoryID,
+			&ent.createdAt,
+			&ent.id,
+			&ent.updatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
 		}
 
-		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
+		entities = append(entities, &ent)
 	}
-	query += " WHERE title = $1 AND lead = $2 RETURNING " + strings.Join(r.columns, ", ")
-	if r.dbg {
-		if err := r.log.Log("msg", query, "function", "UpdateOneByTitleAndLead"); err != nil {
-			return nil, err
-		}
+	if rows.Err() != nil {
+		return nil, 0, rows.Err()
 	}
-	var e newsEntity
-	err := r.db.QueryRow(query, update.Args()...).Scan(
-		&e.content,
-		&e.cont,
-		&e.createdAt,
-		&e.id,
-		&e.lead,
-		&e.title,
-		&e.updatedAt,
-	)
-	if err != nil {
-		return nil, err
+	if len(entities) == 0 && c.Offset > 0 {
+		if total, err = r.countContext(ctx, c); err != nil {
+			return nil, 0, err
+		}
 	}
 
-	return &e, nil
+	return entities, total, nil
 }
+func (r *packageRepositoryBase) findAndCount(c *packageCriteria) ([]*packageEntity, int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-func (r *newsRepositoryBase) deleteOneByID(id int64) (int64, error) {
-	query := "DELETE FROM example.news WHERE id = $1"
-
-	res, err := r.db.Exec(query, id)
-	if err != nil {
-		return 0, err
-	}
-
-	return res.RowsAffected()
+	return r.findAndCountContext(ctx, c)
 }
 
-const (
-	tableComment                              = "example.comment"
-	tableCommentColumnContent                 = "content"
-	tableCommentColumnCreatedAt               = "created_at"
-	tableCommentColumnID                      = "id"
-	tableCommentColumnNewsID                  = "news_id"
-	tableCommentColumnNewsTitle               = "news_title"
-	tableCommentColumnUpdatedAt               = "updated_at"
-	tableCommentConstraintNewsIDForeignKey    = "example.comment_news_id_fkey"
-	tableCommentConstraintNewsTitleForeignKey = "example.comment_news_title_fkey"
-)
+// findOneContext returns single entity that matches given criteria.
+// pqt.ErrNotFound is returned if there is none, pqt.ErrMultipleRows if there is more than one.
+func (r *packageRepositoryBase) findOneContext(ctx context.Context, c *packageCriteria) (*packageEntity, error) {
+	cc := *c
+	cc.Limit = 2
 
-var (
-	tableCommentColumns = []string{
-		tableCommentColumnContent,
-		tableCommentColumnCreatedAt,
-		tableCommentColumnID,
-		tableCommentColumnNewsID,
-		tableCommentColumnNewsTitle,
-		tableCommentColumnUpdatedAt,
+	ents, err := r.findContext(ctx, &cc)
+	if err != nil {
+		return nil, err
 	}
-)
-
-type commentEntity struct {
-	// content ...
-	content string
-	// createdAt ...
-	createdAt time.Time
-	// id ...
-	id *ntypes.Int64
-	// newsID ...
-	newsID int64
-	// newsTitle ...
-	newsTitle string
-	// updatedAt ...
-	updatedAt *time.Time
-	// newsByTitle ...
-	newsByTitle *newsEntity
-	// newsByID ...
-	newsByID *newsEntity
-}
-
-func (e *commentEntity) prop(cn string) (interface{}, bool) {
-	switch cn {
-	case tableCommentColumnContent:
-		return &e.content, true
-	case tableCommentColumnCreatedAt:
-		return &e.createdAt, true
-	case tableCommentColumnID:
-		return &e.id, true
-	case tableCommentColumnNewsID:
-		return &e.newsID, true
-	case tableCommentColumnNewsTitle:
-		return &e.newsTitle, true
-	case tableCommentColumnUpdatedAt:
-		return &e.updatedAt, true
+	switch len(ents) {
+	case 0:
+		return nil, pqt.ErrNotFound
+	case 1:
+		return ents[0], nil
 	default:
-		return nil, false
-	}
-}
-func (e *commentEntity) props(cns ...string) ([]interface{}, error) {
-
-	res := make([]interface{}, 0, len(cns))
-	for _, cn := range cns {
-		if prop, ok := e.prop(cn); ok {
-			res = append(res, prop)
-		} else {
-			return nil, fmt.Errorf("unexpected column provided: %s", cn)
-		}
+		return nil, pqt.ErrMultipleRows
 	}
-	return res, nil
 }
+func (r *packageRepositoryBase) findOne(c *packageCriteria) (*packageEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-// commentIterator is not thread safe.
-type commentIterator struct {
-	rows *sql.Rows
-	cols []string
+	return r.findOneContext(ctx, c)
 }
 
-func (i *commentIterator) Next() bool {
-	return i.rows.Next()
-}
+// findWithCategoryContext works like findContext, but also loads related category entity using LEFT JOIN.
+func (r *packageRepositoryBase) findWithCategoryContext(ctx context.Context, c *packageCriteria) ([]*packageEntity, error) {
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBufferString("SELECT t0.break, t0.category_id, t0.created_at, t0.id, t0.updated_at, t1.content, t1.created_at, t1.deleted_at, t1.id, t1.name, t1.parent_id, t1.updated_at FROM (")
+	buf.WriteString(query)
+	buf.WriteString(") AS t0 LEFT JOIN example.category AS t1 ON t0.category_id = t1.id")
 
-func (i *commentIterator) Close() error {
-	return i.rows.Close()
-}
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindWithCategory"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-func (i *commentIterator) Err() error {
-	return i.rows.Err()
-}
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-// Columns is wrapper around sql.Rows.Columns method, that also cache outpu inside iterator.
-func (i *commentIterator) Columns() ([]string, error) {
-	if i.cols == nil {
-		cols, err := i.rows.Columns()
+	var entities []*packageEntity
+	for rows.Next() {
+		var ent packageEntity
+		var (
+			categoryContent   *string
+			categoryCreatedAt *time.Time
+			categoryDeletedAt **time.Time
+			categoryID        *int64
+			categoryName      *string
+			categoryParentID  **ntypes.Int64
+			categoryUpdatedAt **time.Time
+		)
+		err = rows.Scan(
+			&ent.brk,
+			&ent.categoryID,
+			&ent.createdAt,
+			&ent.id,
+			&ent.updatedAt,
+			&categoryContent,
+			&categoryCreatedAt,
+			&categoryDeletedAt,
+			&categoryID,
+			&categoryName,
+			&categoryParentID,
+			&categoryUpdatedAt,
+		)
 		if err != nil {
 			return nil, err
 		}
-		i.cols = cols
+		if categoryID != nil {
+			ent.category = &categoryEntity{}
+			if categoryContent != nil {
+				ent.category.content = *categoryContent
+			}
+			if categoryCreatedAt != nil {
+				ent.category.createdAt = *categoryCreatedAt
+			}
+			if categoryDeletedAt != nil {
+				ent.category.deletedAt = *categoryDeletedAt
+			}
+			if categoryID != nil {
+				ent.category.id = *categoryID
+			}
+			if categoryName != nil {
+				ent.category.name = *categoryName
+			}
+			if categoryParentID != nil {
+				ent.category.parentID = *categoryParentID
+			}
+			if categoryUpdatedAt != nil {
+				ent.category.updatedAt = *categoryUpdatedAt
+			}
+		}
+
+		entities = append(entities, &ent)
 	}
-	return i.cols, nil
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entities, nil
 }
+func (r *packageRepositoryBase) findWithCategory(c *packageCriteria) ([]*packageEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-// Ent is wrapper around comment method that makes iterator more generic.
-func (i *commentIterator) Ent() (interface{}, error) {
-	return i.Comment()
+	return r.findWithCategoryContext(ctx, c)
 }
 
-func (i *commentIterator) Comment() (*commentEntity, error) {
-	var ent commentEntity
-	cols, err := i.rows.Columns()
+// findLateralContext works like findContext, but also joins lateral sub-queries of the criteria using JOIN LATERAL (...) AS alias ON TRUE.
+// Each row of the result holds entity and values of columns of lateral joins, entity is repeated if sub-query returns multiple rows.
+func (r *packageRepositoryBase) findLateralContext(ctx context.Context, c *packageCriteria) ([]*packageLateral, error) {
+	lateral, err := pqtgo.LateralColumns(c.lateral)
 	if err != nil {
 		return nil, err
 	}
 
-	props, err := ent.props(cols...)
+	query, args, err := r.findQuery(c)
 	if err != nil {
 		return nil, err
 	}
-	if err := i.rows.Scan(props...); err != nil {
+	buf := bytes.NewBufferString("SELECT t0.break, t0.category_id, t0.created_at, t0.id, t0.updated_at")
+	for _, cn := range lateral {
+		buf.WriteString(", ")
+		buf.WriteString(cn)
+	}
+	buf.WriteString(" FROM (")
+	buf.WriteString(query)
+	buf.WriteString(") AS t0")
+
+	// Placeholders of lateral sub-queries follow those of the criteria.
+	com := pqtgo.NewComposerAt(int64(len(c.lateral)), len(args)+1)
+	if err := pqtgo.WriteLateralJoins(com, c.lateral); err != nil {
 		return nil, err
 	}
-	return &ent, nil
-}
+	buf.ReadFrom(com)
+	args = append(args, com.Args()...)
 
-type commentCriteria struct {
-	offset, limit int64
-	sort          map[string]bool
-	content       *qtypes.String
-	createdAt     *qtypes.Timestamp
-	id            *qtypes.Int64
-	newsID        *qtypes.Int64
-	newsTitle     *qtypes.String
-	updatedAt     *qtypes.Timestamp
+	// Joins do not preserve order of the sub-query, so it is repeated by the outer query.
+	// Columns are qualified, lateral joins can expose columns of the same names.
+	if keys := pqtgo.Keyset(c.sort, tablePackageColumns); len(keys) > 0 || len(c.sortExpr) > 0 {
+		orderBy, err := pqtgo.QualifiedOrderBy("t0", c.sortExpr, keys, tablePackageColumns)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(" ORDER BY ")
+		buf.WriteString(orderBy)
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindLateral"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*packageLateral
+	for rows.Next() {
+		var ent packageEntity
+		values := make([]interface{}, len(lateral))
+		dest := []interface{}{
+			&ent.brk,
+			&ent.categoryID,
+			&ent.createdAt,
+			&ent.id,
+			&ent.updatedAt,
+		}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := &packageLateral{entity: &ent, lateral: make(map[string]interface{}, len(lateral))}
+		for i, cn := range lateral {
+			row.lateral[cn] = values[i]
+		}
+		res = append(res, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
 }
+func (r *packageRepositoryBase) findLateral(c *packageCriteria) ([]*packageLateral, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-func (c *commentCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *pqtgo.CompositionOpts) (err error) {
+	return r.findLateralContext(ctx, c)
+}
 
-	if err = pqtgo.WriteCompositionQueryString(c.content, tableCommentColumnContent, com, pqtgo.And); err != nil {
-		return
+// findOneByIDQuery returns query and arguments used by findOneByIDContext to retrieve the entity, without executing it.
+func (r *packageRepositoryBase) findOneByIDQuery(id int64) (string, []interface{}, error) {
+	return `SELECT break, category_id, created_at, id, updated_at FROM example.package WHERE id = $1`, []interface{}{id}, nil
+}
+
+func (r *packageRepositoryBase) findOneByIDContext(ctx context.Context, id int64) (*packageEntity, error) {
+	query, args, err := r.findOneByIDQuery(id)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "FindOneByID"); err != nil {
+			return nil, err
+		}
 	}
 
-	if c.createdAt != nil && c.createdAt.Valid {
-		createdAtt1 := c.createdAt.Value()
-		if createdAtt1 != nil {
-			createdAt1, err := ptypes.Timestamp(createdAtt1)
-			if err != nil {
-				return err
+	var ent packageEntity
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&ent.brk,
+		&ent.categoryID,
+		&ent.createdAt,
+		&ent.id,
+		&ent.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+func (r *packageRepositoryBase) findOneByID(id int64) (*packageEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findOneByIDContext(ctx, id)
+}
+
+// insertQuery returns query and arguments used by insertContext to save given entity, without executing it.
+func (r *packageRepositoryBase) insertQuery(e *packageEntity) (string, []interface{}, error) {
+	if err := e.validate(); err != nil {
+		return "", nil, err
+	}
+
+	insert := pqcomp.New(0, 5)
+	insert.AddExpr(tablePackageColumnBreak, "", e.brk)
+	insert.AddExpr(tablePackageColumnCategoryID, "", e.categoryID)
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tablePackageColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tablePackageColumnUpdatedAt, "", e.updatedAt)
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() != 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
 			}
-			switch c.createdAt.Type {
-			case qtypes.QueryType_NULL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
 
-				com.WriteString(tableCommentColumnCreatedAt)
-				if c.createdAt.Negation {
-					com.WriteString(" IS NOT NULL ")
-				} else {
-					com.WriteString(" IS NULL ")
-				}
-			case qtypes.QueryType_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
 
-				com.WriteString(tableCommentColumnCreatedAt)
-				if c.createdAt.Negation {
-					com.WriteString(" <> ")
-				} else {
-					com.WriteString(" = ")
-				}
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_GREATER:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+	}
 
-				com.WriteString(tableCommentColumnCreatedAt)
-				com.WriteString(">")
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_GREATER_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	return b.String(), insert.Args(), nil
+}
 
-				com.WriteString(tableCommentColumnCreatedAt)
-				com.WriteString(">=")
+// insertContext saves given entity and returns it with values read back from the database, e.g. defaults.
+func (r *packageRepositoryBase) insertContext(ctx context.Context, e *packageEntity) (*packageEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := r.insertQuery(e)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Insert"); err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.brk,
+		&e.categoryID,
+		&e.createdAt,
+		&e.id,
+		&e.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if r.afterInsert != nil {
+		if err := r.afterInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+func (r *packageRepositoryBase) insert(e *packageEntity) (*packageEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.insertContext(ctx, e)
+}
+
+// insertReturningColumnsContext works like insertContext, but returns new entity with only given columns populated, unknown columns are rejected before execution.
+func (r *packageRepositoryBase) insertReturningColumnsContext(ctx context.Context, e *packageEntity, cols ...string) (*packageEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(cols) == 0 {
+		return nil, errors.New("package insert failure, no columns to return")
+	}
+	var ent packageEntity
+	props, err := ent.props(cols...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+
+	insert := pqcomp.New(0, 5)
+	insert.AddExpr(tablePackageColumnBreak, "", e.brk)
+	insert.AddExpr(tablePackageColumnCategoryID, "", e.categoryID)
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tablePackageColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tablePackageColumnUpdatedAt, "", e.updatedAt)
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() != 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+		b.WriteString(" RETURNING " + strings.Join(cols, ", "))
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", b.String(), "function", "InsertReturningColumns"); err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.db.QueryRowContext(ctx, b.String(), insert.Args()...).Scan(props...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+func (r *packageRepositoryBase) insertReturningColumns(e *packageEntity, cols ...string) (*packageEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.insertReturningColumnsContext(ctx, e, cols...)
+}
+
+// insertBatchContext saves given entities using multi-row statements and returns them with values read back from the database.
+// Column with default value gets DEFAULT in rows that do not hold explicit value, like in insertContext.
+// All entities are validated first, so none of them is sent if any is invalid.
+func (r *packageRepositoryBase) insertBatchContext(ctx context.Context, es []*packageEntity) ([]*packageEntity, error) {
+	for _, e := range es {
+		if r.beforeInsert != nil {
+			if err := r.beforeInsert(ctx, e); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := e.validate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, chunk := range pqtgo.Chunks(len(es), 4) {
+		batch := es[chunk[0]:chunk[1]]
+		com := pqtgo.NewComposer(int64(len(batch) * 4))
+		for i, e := range batch {
+			if i != 0 {
+				com.WriteString(", ")
+			}
+			com.WriteString("(")
+			com.WritePlaceholder()
+			com.Add(e.brk)
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.categoryID)
+			com.WriteString(", ")
+			if !e.createdAt.IsZero() {
 				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_LESS:
-				if com.Dirty {
-					com.WriteString(" AND ")
+				com.Add(e.createdAt)
+			} else {
+				com.WriteString("DEFAULT")
+			}
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.updatedAt)
+			com.WriteString(")")
+		}
+
+		b := bytes.NewBufferString("INSERT INTO " + r.table + " (")
+		b.WriteString(tablePackageColumnBreak)
+		b.WriteString(", ")
+		b.WriteString(tablePackageColumnCategoryID)
+		b.WriteString(", ")
+		b.WriteString(tablePackageColumnCreatedAt)
+		b.WriteString(", ")
+		b.WriteString(tablePackageColumnUpdatedAt)
+		b.WriteString(") VALUES ")
+		b.ReadFrom(com)
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+
+		if r.dbg {
+			if err := r.log.Log("msg", b.String(), "function", "InsertBatch"); err != nil {
+				return nil, err
+			}
+		}
+
+		rows, err := r.db.QueryContext(ctx, b.String(), com.Args()...)
+		if err != nil {
+			return nil, err
+		}
+		returned := 0
+		for ; returned < len(batch) && rows.Next(); returned++ {
+			err = rows.Scan(
+				&batch[returned].brk,
+				&batch[returned].categoryID,
+				&batch[returned].createdAt,
+				&batch[returned].id,
+				&batch[returned].updatedAt,
+			)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+		}
+		if err = rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+		if len(r.columns) > 0 && returned != len(batch) {
+			return nil, fmt.Errorf("package insert batch failure, %d rows inserted, but %d returned", len(batch), returned)
+		}
+		for _, e := range batch {
+			if r.afterInsert != nil {
+				if err := r.afterInsert(ctx, e); err != nil {
+					return nil, err
 				}
-				com.Dirty = true
+			}
+
+		}
+	}
+
+	return es, nil
+}
+func (r *packageRepositoryBase) insertBatch(es []*packageEntity) ([]*packageEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.insertBatchContext(ctx, es)
+}
+
+// bulkInsertContext loads given entities using COPY protocol and returns number of loaded rows.
+// Serial columns and columns with default value are omitted, they are populated by the database.
+// COPY does not support RETURNING clause, so generated values (e.g. ids) are not set on given entities.
+// All entities are validated first, so none of them is sent if any is invalid.
+// Database of the repository has to implement pqtgo.TxBeginner, like *sql.DB, or pqtgo.Preparer within a transaction, like *sql.Tx.
+func (r *packageRepositoryBase) bulkInsertContext(ctx context.Context, es []*packageEntity) (int64, error) {
+	for _, e := range es {
+		if r.beforeInsert != nil {
+			if err := r.beforeInsert(ctx, e); err != nil {
+				return 0, err
+			}
+		}
+
+		if err := e.validate(); err != nil {
+			return 0, err
+		}
+	}
+	query := pq.CopyInSchema("example", "package", tablePackageColumnBreak, tablePackageColumnCategoryID, tablePackageColumnUpdatedAt)
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "BulkInsert"); err != nil {
+			return 0, err
+		}
+	}
+
+	return pqtgo.CopyIn(ctx, r.db, query, len(es), r.bulkSize, func(i int) []interface{} {
+		return []interface{}{
+			es[i].brk,
+			es[i].categoryID,
+			es[i].updatedAt,
+		}
+	})
+}
+func (r *packageRepositoryBase) bulkInsert(es []*packageEntity) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.bulkInsertContext(ctx, es)
+}
+
+// upsertOnContext saves given entity, on conflict with given target it updates existing row using the patch, or values of the entity if patch is nil.
+// pqt.ErrUpsertIgnored is returned if conflicting row was not updated.
+func (r *packageRepositoryBase) upsertOnContext(ctx context.Context, e *packageEntity, p *packagePatch, ct pqt.UpsertConflictTarget) (*packageEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+	if p != nil {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	insert := pqcomp.New(0, 5)
+	update := insert.Compose(5)
+	insert.AddExpr(tablePackageColumnBreak, "", e.brk)
+	insert.AddExpr(tablePackageColumnCategoryID, "", e.categoryID)
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tablePackageColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tablePackageColumnUpdatedAt, "", e.updatedAt)
+	if p != nil && !ct.IsZero() {
+		update.AddExpr(tablePackageColumnBreak, "=", p.brk)
+		update.AddExpr(tablePackageColumnCategoryID, "=", p.categoryID)
+		update.AddExpr(tablePackageColumnCreatedAt, "=", p.createdAt)
+		update.AddExpr(tablePackageColumnUpdatedAt, "=", p.updatedAt)
+	}
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() > 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+	}
+	var excluded []string
+	if p == nil && !ct.IsZero() {
+		insert.Reset()
+		for insert.Next() {
+			if !ct.HasColumn(insert.Key()) {
+				excluded = append(excluded, insert.Key()+" = EXCLUDED."+insert.Key())
+			}
+		}
+	}
+
+	b.WriteString(" ON CONFLICT ")
+	if !ct.IsZero() {
+		b.WriteString(ct.String())
+	}
+	switch {
+	case len(excluded) > 0:
+		b.WriteString(" DO UPDATE SET ")
+		b.WriteString(strings.Join(excluded, ", "))
+	case !ct.IsZero() && update.Len() > 0:
+		b.WriteString(" DO UPDATE SET ")
+		for update.Next() {
+			if !update.First() {
+				b.WriteString(", ")
+			}
+
+			b.WriteString(update.Key())
+			b.WriteString(" ")
+			b.WriteString(update.Oper())
+			b.WriteString(" ")
+			b.WriteString(update.PlaceHolder())
+		}
+	default:
+		b.WriteString(" DO NOTHING ")
+	}
+	if insert.Len() > 0 {
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", b.String(), "function", "Upsert"); err != nil {
+			return nil, err
+		}
+	}
+
+	err := r.db.QueryRowContext(ctx, b.String(), insert.Args()...).Scan(
+		&e.brk,
+		&e.categoryID,
+		&e.createdAt,
+		&e.id,
+		&e.updatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, pqt.ErrUpsertIgnored
+		}
+		return nil, err
+	}
+
+	return e, nil
+}
+func (r *packageRepositoryBase) upsertOn(e *packageEntity, p *packagePatch, ct pqt.UpsertConflictTarget) (*packageEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.upsertOnContext(ctx, e, p, ct)
+}
+
+// upsertContext is a shorthand for upsertOnContext, given columns are used to infer the conflict target.
+func (r *packageRepositoryBase) upsertContext(ctx context.Context, e *packageEntity, p *packagePatch, inf ...string) (*packageEntity, error) {
+	return r.upsertOnContext(ctx, e, p, pqt.ConflictOnColumns(inf...))
+}
+func (r *packageRepositoryBase) upsert(e *packageEntity, p *packagePatch, inf ...string) (*packageEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.upsertContext(ctx, e, p, inf...)
+}
+
+// updateOneByIDQuery returns query and arguments used by updateOneByIDContext and its variants to modify the entity, without executing it.
+// Given columns are listed in RETURNING clause as they are, which is omitted if there are none.
+func (r *packageRepositoryBase) updateOneByIDQuery(id int64, patch *packagePatch, returning []string) (string, []interface{}, error) {
+	if err := patch.validate(); err != nil {
+		return "", nil, err
+	}
+	update := pqcomp.New(1, 5)
+	update.AddArg(id)
+
+	update.AddExpr(tablePackageColumnBreak, pqcomp.Equal, patch.brk)
+	update.AddExpr(tablePackageColumnCategoryID, pqcomp.Equal, patch.categoryID)
+	if patch.createdAt != nil {
+		update.AddExpr(tablePackageColumnCreatedAt, pqcomp.Equal, patch.createdAt)
+
+	}
+	if patch.updatedAt != nil {
+		update.AddExpr(tablePackageColumnUpdatedAt, pqcomp.Equal, patch.updatedAt)
+	} else {
+		update.AddExpr(tablePackageColumnUpdatedAt, pqcomp.Equal, "NOW()")
+	}
+
+	if update.Len() == 0 {
+		return "", nil, pqt.ErrNothingToUpdate
+	}
+	query := "UPDATE example.package SET "
+	for update.Next() {
+		if !update.First() {
+			query += ", "
+		}
+
+		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
+	}
+	query += " WHERE id = $1"
+	if len(returning) > 0 {
+		query += " RETURNING " + strings.Join(returning, ", ")
+	}
+	return query, update.Args(), nil
+}
+
+// updateOneByIDContext modifies the entity using values set in the patch and returns it.
+// pqt.ErrNothingToUpdate is returned if the patch holds no value.
+func (r *packageRepositoryBase) updateOneByIDContext(ctx context.Context, id int64, patch *packagePatch) (*packageEntity, error) {
+	if r.beforeUpdate != nil {
+		if err := r.beforeUpdate(ctx, id, patch); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := r.updateOneByIDQuery(id, patch, r.columns)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "UpdateOneByID"); err != nil {
+			return nil, err
+		}
+	}
+
+	var e packageEntity
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.brk,
+		&e.categoryID,
+		&e.createdAt,
+		&e.id,
+		&e.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if r.afterUpdate != nil {
+		if err := r.afterUpdate(ctx, &e); err != nil {
+			return nil, err
+		}
+	}
+
+	return &e, nil
+}
+func (r *packageRepositoryBase) updateOneByID(id int64, patch *packagePatch) (*packageEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.updateOneByIDContext(ctx, id, patch)
+}
+
+// updateOneByIDReturningColumnsContext works like updateOneByIDContext, but returns entity with only given columns populated, unknown columns are rejected before execution.
+func (r *packageRepositoryBase) updateOneByIDReturningColumnsContext(ctx context.Context, id int64, patch *packagePatch, cols ...string) (*packageEntity, error) {
+	if r.beforeUpdate != nil {
+		if err := r.beforeUpdate(ctx, id, patch); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(cols) == 0 {
+		return nil, errors.New("package update failure, no columns to return")
+	}
+	var ent packageEntity
+	props, err := ent.props(cols...)
+	if err != nil {
+		return nil, err
+	}
+	query, args, err := r.updateOneByIDQuery(id, patch, cols)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "UpdateOneByIDReturningColumns"); err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(props...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+func (r *packageRepositoryBase) updateOneByIDReturningColumns(id int64, patch *packagePatch, cols ...string) (*packageEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.updateOneByIDReturningColumnsContext(ctx, id, patch, cols...)
+}
+
+// patchOneByIDContext works like updateOneByIDContext, but returns number of affected rows instead of the entity.
+func (r *packageRepositoryBase) patchOneByIDContext(ctx context.Context, id int64, patch *packagePatch) (int64, error) {
+	if r.beforeUpdate != nil {
+		if err := r.beforeUpdate(ctx, id, patch); err != nil {
+			return 0, err
+		}
+	}
+	query, args, err := r.updateOneByIDQuery(id, patch, nil)
+	if err != nil {
+		return 0, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "PatchOneByID"); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+func (r *packageRepositoryBase) patchOneByID(id int64, patch *packagePatch) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.patchOneByIDContext(ctx, id, patch)
+}
+
+// deleteOneByIDQuery returns query and arguments used by deleteOneByIDContext to remove the entity, without executing it.
+func (r *packageRepositoryBase) deleteOneByIDQuery(id int64) (string, []interface{}, error) {
+	return "DELETE FROM example.package WHERE id = $1", []interface{}{id}, nil
+}
+
+// deleteOneByIDContext removes the entity and returns number of affected rows.
+func (r *packageRepositoryBase) deleteOneByIDContext(ctx context.Context, id int64) (int64, error) {
+	if r.beforeDelete != nil {
+		if err := r.beforeDelete(ctx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	query, args, err := r.deleteOneByIDQuery(id)
+	if err != nil {
+		return 0, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "DeleteOneByID"); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil || affected == 0 {
+		return affected, err
+	}
+	if r.afterDelete != nil {
+		if err := r.afterDelete(ctx, id); err != nil {
+			return affected, err
+		}
+	}
+
+	return affected, nil
+}
+func (r *packageRepositoryBase) deleteOneByID(id int64) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.deleteOneByIDContext(ctx, id)
+}
+
+// deleteAndReturnOneByIDContext removes the entity and returns it as it was right before removal.
+// pqt.ErrNotFound is returned if there is no such entity.
+func (r *packageRepositoryBase) deleteAndReturnOneByIDContext(ctx context.Context, id int64) (*packageEntity, error) {
+	if r.beforeDelete != nil {
+		if err := r.beforeDelete(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := r.deleteOneByIDQuery(id)
+	if err != nil {
+		return nil, err
+	}
+	query += " RETURNING " + strings.Join(r.columns, ", ")
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "DeleteAndReturnOneByID"); err != nil {
+			return nil, err
+		}
+	}
+
+	var e packageEntity
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.brk,
+		&e.categoryID,
+		&e.createdAt,
+		&e.id,
+		&e.updatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, pqt.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.afterDelete != nil {
+		if err := r.afterDelete(ctx, id); err != nil {
+			return &e, err
+		}
+	}
+
+	return &e, nil
+}
+func (r *packageRepositoryBase) deleteAndReturnOneByID(id int64) (*packageEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.deleteAndReturnOneByIDContext(ctx, id)
+}
+
+// deleteByCriteriaContext removes entities that match given criteria and returns their number.
+// Empty criteria is rejected with pqt.ErrDeleteWithoutCriteria unless full scan is allowed. Sort, offset, limit and lock are not supported.
+func (r *packageRepositoryBase) deleteByCriteriaContext(ctx context.Context, c *packageCriteria, allowFullScan bool) (int64, error) {
+	if len(c.sort) > 0 || len(c.sortExpr) > 0 || c.Offset > 0 || c.Limit > 0 || c.lock != pqt.LockNone {
+		return 0, errors.New("package delete failure, sort, offset, limit and lock are not supported")
+	}
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return 0, err
+	}
+	buf := bytes.NewBufferString("DELETE FROM ")
+	buf.WriteString(r.table)
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	} else if !allowFullScan {
+		return 0, pqt.ErrDeleteWithoutCriteria
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "DeleteByCriteria"); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := r.db.ExecContext(ctx, buf.String(), args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+func (r *packageRepositoryBase) deleteByCriteria(c *packageCriteria, allowFullScan bool) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.deleteByCriteriaContext(ctx, c, allowFullScan)
+}
+
+// truncateContext removes all rows from the table.
+// If cascade is true, tables that have foreign key references to the table are truncated as well.
+// If restartIdentity is true, sequences owned by columns of the table are restarted.
+func (r *packageRepositoryBase) truncateContext(ctx context.Context, cascade, restartIdentity bool) error {
+	buf := bytes.NewBufferString("TRUNCATE ")
+	buf.WriteString(r.table)
+	if restartIdentity {
+		buf.WriteString(" RESTART IDENTITY")
+	}
+	if cascade {
+		buf.WriteString(" CASCADE")
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Truncate"); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.db.ExecContext(ctx, buf.String())
+	return err
+}
+func (r *packageRepositoryBase) truncate(cascade, restartIdentity bool) error {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.truncateContext(ctx, cascade, restartIdentity)
+}
+
+const (
+	tableNews                          = "example.news"
+	tableNewsColumnContent             = "content"
+	tableNewsColumnContinue            = "continue"
+	tableNewsColumnCreatedAt           = "created_at"
+	tableNewsColumnID                  = "id"
+	tableNewsColumnLead                = "lead"
+	tableNewsColumnStatus              = "status"
+	tableNewsColumnTags                = "tags"
+	tableNewsColumnTitle               = "title"
+	tableNewsColumnUpdatedAt           = "updated_at"
+	tableNewsConstraintPrimaryKey      = "example.news_id_pkey"
+	tableNewsConstraintTitleUnique     = "example.news_title_key"
+	tableNewsConstraintTitleLeadUnique = "example.news_title_lead_key"
+)
+
+var (
+	tableNewsColumns = []string{
+		tableNewsColumnContent,
+		tableNewsColumnContinue,
+		tableNewsColumnCreatedAt,
+		tableNewsColumnID,
+		tableNewsColumnLead,
+		tableNewsColumnStatus,
+		tableNewsColumnTags,
+		tableNewsColumnTitle,
+		tableNewsColumnUpdatedAt,
+	}
+)
+
+type newsEntity struct {
+	// content ...
+	content string
+	// cont ...
+	cont bool
+	// createdAt ...
+	createdAt time.Time
+	// id ...
+	id int64
+	// Short summary displayed on the list of news.
+	lead *ntypes.String
+	// status ...
+	status *newsStatus
+	// tags ...
+	tags pqt.ArrayString
+	// title ...
+	title string
+	// updatedAt ...
+	updatedAt *time.Time
+	// commentsByNewsTitle ...
+	commentsByNewsTitle []*commentEntity
+	// comments ...
+	comments []*commentEntity
+}
+
+func (e *newsEntity) prop(cn string) (interface{}, bool) {
+	switch cn {
+	case tableNewsColumnContent:
+		return &e.content, true
+	case tableNewsColumnContinue:
+		return &e.cont, true
+	case tableNewsColumnCreatedAt:
+		return &e.createdAt, true
+	case tableNewsColumnID:
+		return &e.id, true
+	case tableNewsColumnLead:
+		return &e.lead, true
+	case tableNewsColumnStatus:
+		return &e.status, true
+	case tableNewsColumnTags:
+		return &e.tags, true
+	case tableNewsColumnTitle:
+		return &e.title, true
+	case tableNewsColumnUpdatedAt:
+		return &e.updatedAt, true
+	default:
+		return nil, false
+	}
+}
+func (e *newsEntity) props(cns ...string) ([]interface{}, error) {
+
+	res := make([]interface{}, 0, len(cns))
+	for _, cn := range cns {
+		if prop, ok := e.prop(cn); ok {
+			res = append(res, prop)
+		} else {
+			return nil, fmt.Errorf("unexpected column provided: %s", cn)
+		}
+	}
+	return res, nil
+}
+func (e *newsEntity) value(cn string) (interface{}, bool) {
+	switch cn {
+	case tableNewsColumnContent:
+		return e.content, true
+	case tableNewsColumnContinue:
+		return e.cont, true
+	case tableNewsColumnCreatedAt:
+		return e.createdAt, true
+	case tableNewsColumnID:
+		return e.id, true
+	case tableNewsColumnLead:
+		return e.lead, true
+	case tableNewsColumnStatus:
+		return e.status, true
+	case tableNewsColumnTags:
+		return e.tags, true
+	case tableNewsColumnTitle:
+		return e.title, true
+	case tableNewsColumnUpdatedAt:
+		return e.updatedAt, true
+	default:
+		return nil, false
+	}
+}
+func (e *newsEntity) values(cns ...string) ([]interface{}, error) {
+
+	res := make([]interface{}, 0, len(cns))
+	for _, cn := range cns {
+		if value, ok := e.value(cn); ok {
+			res = append(res, value)
+		} else {
+			return nil, fmt.Errorf("unexpected column provided: %s", cn)
+		}
+	}
+	return res, nil
+}
+
+// toMap returns values of the entity keyed by column names, nullable properties are either underlying value or nil.
+func (e *newsEntity) toMap() map[string]interface{} {
+	m := make(map[string]interface{}, 9)
+	m[tableNewsColumnContent] = e.content
+	m[tableNewsColumnContinue] = e.cont
+	m[tableNewsColumnCreatedAt] = e.createdAt
+	m[tableNewsColumnID] = e.id
+	if e.lead != nil && e.lead.Valid {
+		m[tableNewsColumnLead] = e.lead.String
+	} else {
+		m[tableNewsColumnLead] = nil
+	}
+	if e.status != nil {
+		m[tableNewsColumnStatus] = *e.status
+	} else {
+		m[tableNewsColumnStatus] = nil
+	}
+	m[tableNewsColumnTags] = e.tags
+	m[tableNewsColumnTitle] = e.title
+	if e.updatedAt != nil {
+		m[tableNewsColumnUpdatedAt] = *e.updatedAt
+	} else {
+		m[tableNewsColumnUpdatedAt] = nil
+	}
+
+	return m
+}
+
+// newsEntityFromMap returns entity built from given values keyed by column names, missing columns are left unset.
+// Error is returned if column is unknown or its value is not of the expected type, nil is accepted by nullable columns only.
+func newsEntityFromMap(m map[string]interface{}) (*newsEntity, error) {
+	e := &newsEntity{}
+	for cn, v := range m {
+		switch cn {
+		case tableNewsColumnContent:
+			if tv, ok := v.(string); ok {
+				e.content = tv
+				continue
+			}
+			return nil, fmt.Errorf("news from map failure, column %s expects value of type string, got %T", cn, v)
+		case tableNewsColumnContinue:
+			if tv, ok := v.(bool); ok {
+				e.cont = tv
+				continue
+			}
+			return nil, fmt.Errorf("news from map failure, column %s expects value of type bool, got %T", cn, v)
+		case tableNewsColumnCreatedAt:
+			if tv, ok := v.(time.Time); ok {
+				e.createdAt = tv
+				continue
+			}
+			return nil, fmt.Errorf("news from map failure, column %s expects value of type time.Time, got %T", cn, v)
+		case tableNewsColumnID:
+			if tv, ok := v.(int64); ok {
+				e.id = tv
+				continue
+			}
+			return nil, fmt.Errorf("news from map failure, column %s expects value of type int64, got %T", cn, v)
+		case tableNewsColumnLead:
+			if v == nil {
+				e.lead = nil
+				continue
+			}
+			if tv, ok := v.(string); ok {
+				e.lead = &ntypes.String{String: tv, Valid: true}
+				continue
+			}
+			return nil, fmt.Errorf("news from map failure, column %s expects value of type string, got %T", cn, v)
+		case tableNewsColumnStatus:
+			if v == nil {
+				e.status = nil
+				continue
+			}
+			if tv, ok := v.(newsStatus); ok {
+				e.status = &tv
+				continue
+			}
+			return nil, fmt.Errorf("news from map failure, column %s expects value of type newsStatus, got %T", cn, v)
+		case tableNewsColumnTags:
+			if tv, ok := v.(pqt.ArrayString); ok {
+				e.tags = tv
+				continue
+			}
+			return nil, fmt.Errorf("news from map failure, column %s expects value of type pqt.ArrayString, got %T", cn, v)
+		case tableNewsColumnTitle:
+			if tv, ok := v.(string); ok {
+				e.title = tv
+				continue
+			}
+			return nil, fmt.Errorf("news from map failure, column %s expects value of type string, got %T", cn, v)
+		case tableNewsColumnUpdatedAt:
+			if v == nil {
+				e.updatedAt = nil
+				continue
+			}
+			if tv, ok := v.(time.Time); ok {
+				e.updatedAt = &tv
+				continue
+			}
+			return nil, fmt.Errorf("news from map failure, column %s expects value of type time.Time, got %T", cn, v)
+		default:
+			return nil, fmt.Errorf("news from map failure, unexpected column provided: %s", cn)
+		}
+	}
+
+	return e, nil
+}
+
+// validate returns pqt.ValidationError if any of mandatory properties is not set.
+func (e *newsEntity) validate() error {
+	var violations []pqt.Violation
+	if e.content == "" {
+		violations = append(violations, pqt.Violation{Column: tableNewsColumnContent, Reason: "is required"})
+	}
+	if e.title == "" {
+		violations = append(violations, pqt.Violation{Column: tableNewsColumnTitle, Reason: "is required"})
+	}
+	if len(violations) > 0 {
+		return &pqt.ValidationError{Table: tableNews, Violations: violations}
+	}
+
+	return nil
+}
+
+// newsIterator is not thread safe.
+// It has to be closed once it is no longer needed, otherwise underlying connection is not released.
+type newsIterator struct {
+	rows *sql.Rows
+	cols []string
+	// cancel releases context the rows are read within, if the iterator owns it.
+	cancel context.CancelFunc
+}
+
+var _ pqtgo.Iterator = &newsIterator{}
+
+func (i *newsIterator) Next() bool {
+	return i.rows.Next()
+}
+
+func (i *newsIterator) Close() error {
+	if i.cancel != nil {
+		defer i.cancel()
+	}
+	return i.rows.Close()
+}
+
+func (i *newsIterator) Err() error {
+	return i.rows.Err()
+}
+
+// Columns is wrapper around sql.Rows.Columns method, that also cache outpu inside iterator.
+func (i *newsIterator) Columns() ([]string, error) {
+	if i.cols == nil {
+		cols, err := i.rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		i.cols = cols
+	}
+	return i.cols, nil
+}
+
+// Ent is wrapper around news method, it allows to use the same method name regardless of the table.
+func (i *newsIterator) Ent() (*newsEntity, error) {
+	return i.News()
+}
+
+func (i *newsIterator) News() (*newsEntity, error) {
+	var ent newsEntity
+	cols, err := i.rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := ent.props(cols...)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.rows.Scan(props...); err != nil {
+		return nil, err
+	}
+	return &ent, nil
+}
+
+// newsCriteria selects entities that queries of the repository operate on, its zero value selects all of them.
+// Offset and Limit are translated into OFFSET and LIMIT clauses if non-zero, lock appends locking clause that is effective within a transaction only.
+// Keys of sort have to be columns of the table.
+// Expressions of sortExpr precede sort, they can refer to columns of the table and call functions listed in pqt.SortFunctions.
+type newsCriteria struct {
+	Offset, Limit   int64
+	sort            map[string]bool
+	sortExpr        []pqt.SortExpr
+	countDistinct   string
+	lock            pqt.LockMode
+	lateral         []pqt.LateralJoin
+	content         *qtypes.String
+	cont            *ntypes.Bool
+	createdAt       *qtypes.Timestamp
+	createdAtBounds pqt.RangeBounds
+	id              *qtypes.Int64
+	lead            *qtypes.String
+	status          *newsStatus
+	tags            *qtypes.String
+	title           *qtypes.String
+	updatedAt       *qtypes.Timestamp
+	updatedAtBounds pqt.RangeBounds
+}
+
+func (c *newsCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *pqtgo.CompositionOpts) (err error) {
+
+	if err = pqtgo.WriteCompositionQueryString(c.content, tableNewsColumnContent, com, pqtgo.And); err != nil {
+		return
+	}
+	if c.cont != nil && c.cont.Valid {
+		if com.Dirty {
+			com.WriteString(" AND ")
+		}
+		com.Dirty = true
+		com.WriteString(tableNewsColumnContinue)
+		com.WriteString(" = ")
+		com.WritePlaceholder()
+		com.Add(c.cont)
+	}
+
+	if c.createdAt != nil && c.createdAt.Valid {
+		switch c.createdAt.Type {
+		case qtypes.QueryType_NULL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableNewsColumnCreatedAt)
+			if c.createdAt.Negation {
+				com.WriteString(" IS NOT NULL ")
+			} else {
+				com.WriteString(" IS NULL ")
+			}
+		case qtypes.QueryType_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableNewsColumnCreatedAt)
+			if c.createdAt.Negation {
+				com.WriteString(" <> ")
+			} else {
+				com.WriteString(" = ")
+			}
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_GREATER:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableNewsColumnCreatedAt)
+			com.WriteString(">")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_GREATER_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableNewsColumnCreatedAt)
+			com.WriteString(">=")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_LESS:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableNewsColumnCreatedAt)
+			com.WriteString(" < ")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_LESS_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableNewsColumnCreatedAt)
+			com.WriteString(" <= ")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_IN:
+			if len(c.createdAt.Values) > 0 {
+				if com.Dirty {
+					com.WriteString(" AND ")
+				}
+				com.Dirty = true
+
+				com.WriteString(tableNewsColumnCreatedAt)
+				com.WriteString(" IN (")
+				for i, v := range c.createdAt.Values {
+					if i != 0 {
+						com.WriteString(", ")
+					}
+					com.WritePlaceholder()
+					com.Add(v)
+				}
+				com.WriteString(") ")
+			}
+		case qtypes.QueryType_BETWEEN:
+			// Missing bound leaves the range open on that side.
+			if len(c.createdAt.Values) > 0 && c.createdAt.Values[0] != nil {
+				createdAt1, err := ptypes.Timestamp(c.createdAt.Values[0])
+				if err != nil {
+					return err
+				}
+				if com.Dirty {
+					com.WriteString(" AND ")
+				}
+				com.Dirty = true
+
+				com.WriteString(tableNewsColumnCreatedAt)
+				com.WriteString(" " + c.createdAtBounds.LowerOperator() + " ")
+				com.WritePlaceholder()
+				com.Add(createdAt1)
+			}
+			if len(c.createdAt.Values) > 1 && c.createdAt.Values[1] != nil {
+				createdAt2, err := ptypes.Timestamp(c.createdAt.Values[1])
+				if err != nil {
+					return err
+				}
+				if com.Dirty {
+					com.WriteString(" AND ")
+				}
+				com.Dirty = true
+
+				com.WriteString(tableNewsColumnCreatedAt)
+				com.WriteString(" " + c.createdAtBounds.UpperOperator() + " ")
+				com.WritePlaceholder()
+				com.Add(createdAt2)
+			}
+		}
+	}
+
+	if err = pqtgo.WriteCompositionQueryInt64(c.id, tableNewsColumnID, com, pqtgo.And); err != nil {
+		return
+	}
+
+	if err = pqtgo.WriteCompositionQueryString(c.lead, tableNewsColumnLead, com, pqtgo.And); err != nil {
+		return
+	}
+	if c.status != nil {
+		if com.Dirty {
+			com.WriteString(" AND ")
+		}
+		com.Dirty = true
+		if _, err = com.WriteString(tableNewsColumnStatus); err != nil {
+			return
+		}
+		if _, err = com.WriteString(" = "); err != nil {
+			return
+		}
+		if err = com.WritePlaceholder(); err != nil {
+			return
+		}
+
+		if com.Dirty {
+			if opt.Cast != "" {
+				if _, err = com.WriteString(opt.Cast); err != nil {
+					return
+				}
+			} else {
+				if _, err = com.WriteString(" "); err != nil {
+					return
+				}
+			}
+		}
+
+		com.Add(c.status)
+	}
+
+	if err = pqtgo.WriteCompositionQueryStringArray(c.tags, tableNewsColumnTags, com, pqtgo.And); err != nil {
+		return
+	}
+
+	if err = pqtgo.WriteCompositionQueryString(c.title, tableNewsColumnTitle, com, pqtgo.And); err != nil {
+		return
+	}
+
+	if c.updatedAt != nil && c.updatedAt.Valid {
+		switch c.updatedAt.Type {
+		case qtypes.QueryType_NULL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableNewsColumnUpdatedAt)
+			if c.updatedAt.Negation {
+				com.WriteString(" IS NOT NULL ")
+			} else {
+				com.WriteString(" IS NULL ")
+			}
+		case qtypes.QueryType_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableNewsColumnUpdatedAt)
+			if c.updatedAt.Negation {
+				com.WriteString(" <> ")
+			} else {
+				com.WriteString(" = ")
+			}
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_GREATER:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableNewsColumnUpdatedAt)
+			com.WriteString(">")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_GREATER_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableNewsColumnUpdatedAt)
+			com.WriteString(">=")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_LESS:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableNewsColumnUpdatedAt)
+			com.WriteString(" < ")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_LESS_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableNewsColumnUpdatedAt)
+			com.WriteString(" <= ")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_IN:
+			if len(c.updatedAt.Values) > 0 {
+				if com.Dirty {
+					com.WriteString(" AND ")
+				}
+				com.Dirty = true
+
+				com.WriteString(tableNewsColumnUpdatedAt)
+				com.WriteString(" IN (")
+				for i, v := range c.updatedAt.Values {
+					if i != 0 {
+						com.WriteString(", ")
+					}
+					com.WritePlaceholder()
+					com.Add(v)
+				}
+				com.WriteString(") ")
+			}
+		case qtypes.QueryType_BETWEEN:
+			// Missing bound leaves the range open on that side.
+			if len(c.updatedAt.Values) > 0 && c.updatedAt.Values[0] != nil {
+				updatedAt1, err := ptypes.Timestamp(c.updatedAt.Values[0])
+				if err != nil {
+					return err
+				}
+				if com.Dirty {
+					com.WriteString(" AND ")
+				}
+				com.Dirty = true
+
+				com.WriteString(tableNewsColumnUpdatedAt)
+				com.WriteString(" " + c.updatedAtBounds.LowerOperator() + " ")
+				com.WritePlaceholder()
+				com.Add(updatedAt1)
+			}
+			if len(c.updatedAt.Values) > 1 && c.updatedAt.Values[1] != nil {
+				updatedAt2, err := ptypes.Timestamp(c.updatedAt.Values[1])
+				if err != nil {
+					return err
+				}
+				if com.Dirty {
+					com.WriteString(" AND ")
+				}
+				com.Dirty = true
+
+				com.WriteString(tableNewsColumnUpdatedAt)
+				com.WriteString(" " + c.updatedAtBounds.UpperOperator() + " ")
+				com.WritePlaceholder()
+				com.Add(updatedAt2)
+			}
+		}
+	}
+
+	return c.writeSuffix(com)
+}
+
+// writeSuffix writes ORDER BY, OFFSET, LIMIT and locking clauses that follow the condition.
+func (c *newsCriteria) writeSuffix(com *pqtgo.Composer) (err error) {
+SortLoop:
+	for cn := range c.sort {
+		for _, tcn := range tableNewsColumns {
+			if cn == tcn {
+				continue SortLoop
+			}
+		}
+		return fmt.Errorf("pqt: unknown sort column %q", cn)
+	}
+
+	// Columns are ordered by name, map does not preserve the order and random one would make results unstable.
+	// Sort expressions, e.g. window functions, are ordered explicitly, so they come first.
+	if keys := pqtgo.Keyset(c.sort, tableNewsColumns); len(keys) > 0 || len(c.sortExpr) > 0 {
+		orderBy, err := pqtgo.OrderBy(c.sortExpr, keys, tableNewsColumns)
+		if err != nil {
+			return err
+		}
+		com.WriteString(" ORDER BY ")
+		com.WriteString(orderBy)
+		com.WriteString(" ")
+	}
+	if c.Offset > 0 {
+		if _, err = com.WriteString(" OFFSET "); err != nil {
+			return
+		}
+		if err = com.WritePlaceholder(); err != nil {
+			return
+		}
+		if _, err = com.WriteString(" "); err != nil {
+			return
+		}
+		com.Add(c.Offset)
+	}
+	if c.Limit > 0 {
+		if _, err = com.WriteString(" LIMIT "); err != nil {
+			return
+		}
+		if err = com.WritePlaceholder(); err != nil {
+			return
+		}
+		if _, err = com.WriteString(" "); err != nil {
+			return
+		}
+		com.Add(c.Limit)
+	}
+	if c.lock != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.lock.String())
+	}
+
+	return
+}
+
+// whereClause returns condition built from the criteria, without WHERE keyword, and its arguments in order.
+// Placeholders are numbered starting at given index, so the clause can be embedded into a custom query.
+// Sort, offset, limit and lock are ignored.
+func (c *newsCriteria) whereClause(startIdx int) (string, []interface{}, error) {
+	cc := *c
+	cc.sort, cc.sortExpr, cc.Offset, cc.Limit, cc.lock = nil, nil, 0, 0, pqt.LockNone
+
+	com := pqtgo.NewComposerAt(9, startIdx)
+	if err := cc.WriteComposition("", com, pqtgo.And); err != nil {
+		return "", nil, err
+	}
+
+	return com.String(), com.Args(), nil
+}
+
+type newsPatch struct {
+	content   *ntypes.String
+	cont      *ntypes.Bool
+	createdAt *time.Time
+	lead      *ntypes.String
+	status    *newsStatus
+	tags      pqt.ArrayString
+	title     *ntypes.String
+	updatedAt *time.Time
+}
+
+// validate returns pqt.ValidationError if patch sets NULL to any of NOT NULL columns.
+func (p *newsPatch) validate() error {
+	var violations []pqt.Violation
+	if p.content != nil && !p.content.Valid {
+		violations = append(violations, pqt.Violation{Column: tableNewsColumnContent, Reason: "cannot be null"})
+	}
+	if p.cont != nil && !p.cont.Valid {
+		violations = append(violations, pqt.Violation{Column: tableNewsColumnContinue, Reason: "cannot be null"})
+	}
+	if p.title != nil && !p.title.Valid {
+		violations = append(violations, pqt.Violation{Column: tableNewsColumnTitle, Reason: "cannot be null"})
+	}
+	if len(violations) > 0 {
+		return &pqt.ValidationError{Table: tableNews, Violations: violations}
+	}
+
+	return nil
+}
+
+type newsReturning struct {
+	id    int64
+	title string
+}
+
+// newsPage is a single page of entities returned by findPageContext method.
+// Cursors of the first and the last entity allow to request adjacent pages.
+type newsPage struct {
+	edges                    []*newsEntity
+	hasNextPage, hasPrevPage bool
+	startCursor, endCursor   string
+}
+
+// newsLateral is a single row returned by findLateralContext method.
+// Values of columns of lateral joins are keyed by alias.column.
+type newsLateral struct {
+	entity  *newsEntity
+	lateral map[string]interface{}
+}
+
+// newsBeforeInsertHook is called by insertContext, insertReturningContext, insertReturningColumnsContext, insertBatchContext, bulkInsertContext, upsertOnContext, upsertContext, updateOrInsertByTitleContext and updateOrInsertByTitleAndLeadContext before the entity is stored, it can modify the entity, e.g. set columns from the context.
+// Returned error aborts the insert.
+type newsBeforeInsertHook func(ctx context.Context, e *newsEntity) error
+
+// newsAfterInsertHook is called by insertContext, insertBatchContext, updateOrInsertByTitleContext and updateOrInsertByTitleAndLeadContext after the entity is stored, with values returned by the database.
+// It is not called by insertReturningContext, insertReturningColumnsContext and bulkInsertContext, they do not read back the whole entity.
+// It is not called by upsertOnContext and upsertContext either, they cannot tell inserted row from updated one.
+// Returned error is passed to the caller, but the entity is already stored, unless insert is executed within a transaction that is rolled back.
+type newsAfterInsertHook func(ctx context.Context, e *newsEntity) error
+
+// newsBeforeUpdateHook is called by updateOneByIDContext, updateOneByIDReturningContext, updateOneByIDReturningColumnsContext and patchOneByIDContext before the entity is modified, it can modify the patch.
+// It is not called by updateOneByTitleContext, updateOneByTitleAndLeadContext, upsertOnContext, upsertContext, updateOrInsertByTitleContext and updateOrInsertByTitleAndLeadContext, they do not know the primary key in advance.
+// Returned error aborts the update.
+type newsBeforeUpdateHook func(ctx context.Context, id int64, patch *newsPatch) error
+
+// newsAfterUpdateHook is called by updateOneByIDContext, updateOneByTitleContext, updateOneByTitleAndLeadContext, updateOrInsertByTitleContext and updateOrInsertByTitleAndLeadContext after the entity is modified, with values returned by the database.
+// It is not called by updateOneByIDReturningContext, updateOneByIDReturningColumnsContext and patchOneByIDContext, they do not read back the whole entity.
+// It is not called by upsertOnContext and upsertContext either, they cannot tell inserted row from updated one.
+// Returned error is passed to the caller, but the entity is already modified, unless update is executed within a transaction that is rolled back.
+type newsAfterUpdateHook func(ctx context.Context, e *newsEntity) error
+
+// newsBeforeDeleteHook is called by deleteOneByIDContext and deleteAndReturnOneByIDContext before the entity is removed.
+// It is not called by deleteByCriteriaContext.
+// Returned error aborts the delete.
+type newsBeforeDeleteHook func(ctx context.Context, id int64) error
+
+// newsAfterDeleteHook is called by deleteOneByIDContext and deleteAndReturnOneByIDContext after the entity is removed, it is not called if there was no such entity.
+// It is not called by deleteByCriteriaContext.
+// Returned error is passed to the caller, but the entity is already removed, unless delete is executed within a transaction that is rolled back.
+type newsAfterDeleteHook func(ctx context.Context, id int64) error
+
+// newsRepositoryBase is a data access layer of the example.news table.
+// Optional explain hook receives execution plans of read only queries in debug mode, optional planner builds conditions out of criteria instead of them.
+// Methods called without context are bounded by timeout, if it is set.
+type newsRepositoryBase struct {
+	table        string
+	columns      []string
+	db           pqtgo.Querier
+	dbg          bool
+	log          log.Logger
+	bulkSize     int
+	explain      pqt.ExplainHook
+	planner      pqt.Planner
+	beforeInsert newsBeforeInsertHook
+	afterInsert  newsAfterInsertHook
+	beforeUpdate newsBeforeUpdateHook
+	afterUpdate  newsAfterUpdateHook
+	beforeDelete newsBeforeDeleteHook
+	afterDelete  newsAfterDeleteHook
+	timeout      time.Duration
+}
+
+// withTx returns copy of the repository that executes all queries within given transaction.
+func (r *newsRepositoryBase) withTx(tx *sql.Tx) *newsRepositoryBase {
+	rt := *r
+	rt.db = tx
+
+	return &rt
+}
+
+// explainQuery runs given query using EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) and passes the plan to the explain hook.
+// As the query is executed, it is called in debug mode by read only methods only.
+func (r *newsRepositoryBase) explainQuery(ctx context.Context, query string, args []interface{}) error {
+	var plan []byte
+	if err := r.db.QueryRowContext(ctx, "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+query, args...).Scan(&plan); err != nil {
+		return err
+	}
+	r.explain(json.RawMessage(plan), query, args)
+
+	return nil
+}
+
+// plan returns condition of given criteria and its arguments, built by the planner if it is set.
+func (r *newsRepositoryBase) plan(c *newsCriteria) (string, []interface{}, error) {
+	if r.planner != nil {
+		return r.planner.Plan(c)
+	}
+	return c.whereClause(1)
+}
+
+// defaultContext returns context used by methods called without one, it is bounded by the timeout of the repository, if it is set.
+// Context given explicitly by the caller is used as it is.
+func (r *newsRepositoryBase) defaultContext() (context.Context, context.CancelFunc) {
+	if r.timeout > 0 {
+		return context.WithTimeout(context.Background(), r.timeout)
+	}
+	return context.Background(), func() {}
+}
+
+// scanNewsRows reads all rows into entities, each row has to consist of columns listed in tableNewsColumns, in the same order.
+func scanNewsRows(rows *sql.Rows) ([]*newsEntity, error) {
+	var (
+		entities []*newsEntity
+		err      error
+	)
+	for rows.Next() {
+		var ent newsEntity
+		err = rows.Scan(
+			&ent.content,
+			&ent.cont,
+			&ent.createdAt,
+			&ent.id,
+			&ent.lead,
+			&ent.status,
+			&ent.tags,
+			&ent.title,
+			&ent.updatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, &ent)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return entities, nil
+}
+
+// scanNewsRow reads single row into entity, the row has to consist of columns listed in tableNewsColumns, in the same order.
+// sql.ErrNoRows is returned if the query selected no rows.
+func scanNewsRow(row *sql.Row) (*newsEntity, error) {
+	var ent newsEntity
+	err := row.Scan(
+		&ent.content,
+		&ent.cont,
+		&ent.createdAt,
+		&ent.id,
+		&ent.lead,
+		&ent.status,
+		&ent.tags,
+		&ent.title,
+		&ent.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+
+// countQuery returns query and arguments used by countContext to count entities that match given criteria, without executing it.
+func (r *newsRepositoryBase) countQuery(c *newsCriteria) (string, []interface{}, error) {
+
+	buf := bytes.NewBufferString("SELECT ")
+	if c.countDistinct != "" {
+		var known bool
+		for _, cn := range tableNewsColumns {
+			if cn == c.countDistinct {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return "", nil, fmt.Errorf("news count failure, unknown column: %s", c.countDistinct)
+		}
+		buf.WriteString("COUNT(DISTINCT " + c.countDistinct + ")")
+	} else {
+		buf.WriteString("COUNT(*)")
+	}
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+
+	// The same condition as used by find, but without sort, offset and limit that make no sense for an aggregate.
+	where, args, err := r.plan(c)
+	if err != nil {
+		return "", nil, err
+	}
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+
+	return buf.String(), args, nil
+}
+
+// countContext returns number of entities that match given criteria. Sort, offset, limit and lock are ignored.
+func (r *newsRepositoryBase) countContext(ctx context.Context, c *newsCriteria) (int64, error) {
+	query, args, err := r.countQuery(c)
+	if err != nil {
+		return 0, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Count"); err != nil {
+			return 0, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+func (r *newsRepositoryBase) count(c *newsCriteria) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.countContext(ctx, c)
+}
+
+// countDistinctContext returns number of distinct values of given column among entities that match given criteria.
+func (r *newsRepositoryBase) countDistinctContext(ctx context.Context, cn string, c *newsCriteria) (int64, error) {
+	cc := *c
+	cc.countDistinct = cn
+
+	return r.countContext(ctx, &cc)
+}
+func (r *newsRepositoryBase) countDistinct(cn string, c *newsCriteria) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.countDistinctContext(ctx, cn, c)
+}
+
+// existsContext returns true if at least one entity matches given criteria, database stops at the first matching row.
+// Sort, offset and limit are ignored.
+func (r *newsRepositoryBase) existsContext(ctx context.Context, c *newsCriteria) (bool, error) {
+	buf := bytes.NewBufferString("SELECT EXISTS (SELECT 1 FROM ")
+	buf.WriteString(r.table)
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return false, err
+	}
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+	buf.WriteString(")")
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Exists"); err != nil {
+			return false, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, buf.String(), args...).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+func (r *newsRepositoryBase) exists(c *newsCriteria) (bool, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.existsContext(ctx, c)
+}
+
+// aggregateContext computes given aggregate function of the column among entities that match given criteria, grouped by values of groupBy column.
+// Column can be empty for pqt.AggregateCount, which then counts entities. If groupBy is empty, single result with nil group is returned.
+// Groups are ordered by their values. Sort, offset, limit and lock are ignored.
+// Unknown functions and columns are rejected, as well as SUM and AVG of non-numeric column, whose MIN and MAX are returned in Raw field.
+func (r *newsRepositoryBase) aggregateContext(ctx context.Context, c *newsCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error) {
+	if fn.String() == "" {
+		return nil, fmt.Errorf("news aggregate failure, unknown function: %d", fn)
+	}
+	if column == "" && fn != pqt.AggregateCount {
+		return nil, fmt.Errorf("news aggregate failure, column is required by %s", fn)
+	}
+	for _, cn := range []string{column, groupBy} {
+		if cn == "" {
+			continue
+		}
+		var known bool
+		for _, tc := range tableNewsColumns {
+			if tc == cn {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("news aggregate failure, unknown column: %s", cn)
+		}
+	}
+	// Result of COUNT and of any function of a numeric column is a number, other values are scanned as they are.
+	numeric := fn == pqt.AggregateCount
+	switch column {
+	case tableNewsColumnID:
+		numeric = true
+	}
+	if !numeric && (fn == pqt.AggregateSum || fn == pqt.AggregateAvg) {
+		return nil, fmt.Errorf("news aggregate failure, %s requires numeric column, got: %s", fn, column)
+	}
+
+	expr := "*"
+	if column != "" {
+		expr = column
+	}
+	buf := bytes.NewBufferString("SELECT ")
+	if groupBy != "" {
+		buf.WriteString(groupBy)
+		buf.WriteString(", ")
+	}
+	buf.WriteString(fn.String() + "(" + expr + ")")
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return nil, err
+	}
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+	if groupBy != "" {
+		buf.WriteString(" GROUP BY " + groupBy + " ORDER BY " + groupBy)
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Aggregate"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []pqt.AggregateResult
+	for rows.Next() {
+		var (
+			group, raw interface{}
+			// Aggregate of a group that has no values, e.g. SUM of nulls, is null.
+			value sql.NullFloat64
+		)
+		dest := []interface{}{&value}
+		if !numeric {
+			dest[0] = &raw
+		}
+		if groupBy != "" {
+			dest = append([]interface{}{&group}, dest...)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		res = append(res, pqt.AggregateResult{Group: group, Value: value.Float64, Raw: raw})
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return res, nil
+}
+func (r *newsRepositoryBase) aggregate(c *newsCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.aggregateContext(ctx, c, fn, column, groupBy)
+}
+
+// findQuery returns query and arguments used by findContext to retrieve entities that match given criteria, without executing it.
+func (r *newsRepositoryBase) findQuery(c *newsCriteria) (string, []interface{}, error) {
+	where, args, err := r.plan(c)
+	if err != nil {
+		return "", nil, err
+	}
+
+	buf := bytes.NewBufferString("SELECT ")
+	buf.WriteString(strings.Join(r.columns, ", "))
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+
+	com := pqtgo.NewComposerAt(2, len(args)+1)
+	if err := c.writeSuffix(com); err != nil {
+		return "", nil, err
+	}
+	buf.ReadFrom(com)
+
+	return buf.String(), append(args, com.Args()...), nil
+}
+
+// findContext returns entities that match given criteria.
+func (r *newsRepositoryBase) findContext(ctx context.Context, c *newsCriteria) ([]*newsEntity, error) {
+
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Find"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanNewsRows(rows)
+}
+func (r *newsRepositoryBase) find(c *newsCriteria) ([]*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findContext(ctx, c)
+}
+
+// findIterContext returns iterator over entities that match given criteria.
+// Caller is responsible for closing the iterator, also if the loop exits early.
+func (r *newsRepositoryBase) findIterContext(ctx context.Context, c *newsCriteria) (*newsIterator, error) {
+
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Find"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &newsIterator{rows: rows}, nil
+}
+func (r *newsRepositoryBase) findIter(c *newsCriteria) (*newsIterator, error) {
+	ctx, cancel := r.defaultContext()
+	it, err := r.findIterContext(ctx, c)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	it.cancel = cancel
+
+	return it, nil
+}
+
+// findPageContext returns page of entities that match given criteria using cursor based (keyset) pagination.
+// Rows are ordered by sort columns of the criteria, followed by primary key, so cursors remain valid if rows are inserted or deleted.
+// Limit of the criteria defines size of the page, offset is not supported.
+// Values held by cursors are decoded into properties of the entity, so they keep types of their columns.
+func (r *newsRepositoryBase) findPageContext(ctx context.Context, c *newsCriteria, page pqt.CursorPage) (*newsPage, error) {
+	if c.Offset > 0 {
+		return nil, errors.New("news find page failure, offset is not supported")
+	}
+	if len(c.sortExpr) > 0 {
+		return nil, errors.New("news find page failure, sort expressions are not supported")
+	}
+	if c.Limit <= 0 {
+		return nil, errors.New("news find page failure, limit has to be positive")
+	}
+	if page.After != "" && page.Before != "" {
+		return nil, errors.New("news find page failure, after and before cursors cannot be used together")
+	}
+
+SortLoop:
+	for cn := range c.sort {
+		for _, tcn := range tableNewsColumns {
+			if cn == tcn {
+				continue SortLoop
+			}
+		}
+		return nil, fmt.Errorf("pqt: unknown sort column %q", cn)
+	}
+
+	keys := pqtgo.Keyset(c.sort, tableNewsColumns, tableNewsColumnID)
+	if len(keys) == 0 {
+		return nil, errors.New("news find page failure, sort or primary key is required")
+	}
+	cursor := func(ent *newsEntity) (string, error) {
+		values := make(map[string]interface{}, len(keys))
+		for _, k := range keys {
+			values[k.Name], _ = ent.value(k.Name)
+		}
+		return pqt.EncodeCursor(values)
+	}
+
+	// Previous page is fetched by traversing rows in opposite order, starting from the cursor.
+	from, backward := page.After, page.Before != ""
+	order := keys
+	if backward {
+		from, order = page.Before, pqtgo.ReverseKeyset(keys)
+	}
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return nil, err
+	}
+	com := pqtgo.NewComposerAt(9, len(args)+1)
+	for _, arg := range args {
+		com.Add(arg)
+	}
+	if where != "" {
+		// Condition of the planner can consist of alternatives, keyset condition is added to all of them.
+		com.WriteString("(" + where + ")")
+		com.Dirty = true
+	}
+	buf := bytes.NewBufferString("SELECT ")
+	buf.WriteString(strings.Join(r.columns, ", "))
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+
+	if from != "" {
+		// Cursor values are decoded into properties of the entity, so they keep types of the columns.
+		var cur newsEntity
+		dest := make(map[string]interface{}, len(order))
+		for _, k := range order {
+			dest[k.Name], _ = cur.prop(k.Name)
+		}
+		if err := pqt.DecodeCursorTo(from, dest); err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, 0, len(order))
+		for _, k := range order {
+			v, _ := cur.value(k.Name)
+			values = append(values, v)
+		}
+		if com.Dirty {
+			com.WriteString(" AND ")
+		}
+		if err := pqtgo.WriteKeysetCondition(com, order, values); err != nil {
+			return nil, err
+		}
+		com.Dirty = true
+	}
+	if com.Dirty {
+		buf.WriteString(" WHERE ")
+	}
+	com.WriteString(" ORDER BY ")
+	com.WriteString(pqtgo.KeysetOrderBy(order))
+	com.WriteString(" LIMIT ")
+	if err := com.WritePlaceholder(); err != nil {
+		return nil, err
+	}
+	// One more row tells if there is another page in the direction of traversal.
+	com.Add(c.Limit + 1)
+	if c.lock != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.lock.String())
+	}
+	buf.ReadFrom(com)
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindPage"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), com.Args()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), com.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ents, err := scanNewsRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	more := int64(len(ents)) > c.Limit
+	if more {
+		ents = ents[:c.Limit]
+	}
+	if backward {
+		for i, j := 0, len(ents)-1; i < j; i, j = i+1, j-1 {
+			ents[i], ents[j] = ents[j], ents[i]
+		}
+	}
+
+	res := &newsPage{edges: ents}
+	if backward {
+		res.hasNextPage, res.hasPrevPage = true, more
+	} else {
+		res.hasNextPage, res.hasPrevPage = more, page.After != ""
+	}
+	if len(ents) > 0 {
+		if res.startCursor, err = cursor(ents[0]); err != nil {
+			return nil, err
+		}
+		if res.endCursor, err = cursor(ents[len(ents)-1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+func (r *newsRepositoryBase) findPage(c *newsCriteria, page pqt.CursorPage) (*newsPage, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findPageContext(ctx, c, page)
+}
+
+// findAndCountContext returns entities that match given criteria together with total number of matching entities, regardless of offset and limit.
+// Total is selected by the same query using COUNT(*) OVER() window function, which is evaluated before OFFSET and LIMIT are applied.
+// If the page is empty, but offset is set, total is obtained using countContext, so it is known even if the offset goes past the last entity.
+// Window functions cannot be combined with locking clause, so lock is not supported.
+func (r *newsRepositoryBase) findAndCountContext(ctx context.Context, c *newsCriteria) ([]*newsEntity, int64, error) {
+	if c.lock != pqt.LockNone {
+		return nil, 0, errors.New("news find and count failure, lock is not supported")
+	}
+
+	rr := *r
+	rr.columns = append(r.columns[:len(r.columns):len(r.columns)], "COUNT(*) OVER()")
+	query, args, err := rr.findQuery(c)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "FindAndCount"); err != nil {
+			return nil, 0, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		entities []*newsEntity
+		total    int64
+	)
+	for rows.Next() {
+		var ent newsEntity
+		err = rows.Scan(
+			&ent.content,
+			&ent.cont,
+			&ent.createdAt,
+			&ent.id,
+			&ent.lead,
+			&ent.status,
+			&ent.tags,
+			&ent.title,
+			&ent.updatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		entities = append(entities, &ent)
+	}
+	if rows.Err() != nil {
+		return nil, 0, rows.Err()
+	}
+	if len(entities) == 0 && c.Offset > 0 {
+		if total, err = r.countContext(ctx, c); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return entities, total, nil
+}
+func (r *newsRepositoryBase) findAndCount(c *newsCriteria) ([]*newsEntity, int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findAndCountContext(ctx, c)
+}
+
+// findOneContext returns single entity that matches given criteria.
+// pqt.ErrNotFound is returned if there is none, pqt.ErrMultipleRows if there is more than one.
+func (r *newsRepositoryBase) findOneContext(ctx context.Context, c *newsCriteria) (*newsEntity, error) {
+	cc := *c
+	cc.Limit = 2
+
+	ents, err := r.findContext(ctx, &cc)
+	if err != nil {
+		return nil, err
+	}
+	switch len(ents) {
+	case 0:
+		return nil, pqt.ErrNotFound
+	case 1:
+		return ents[0], nil
+	default:
+		return nil, pqt.ErrMultipleRows
+	}
+}
+func (r *newsRepositoryBase) findOne(c *newsCriteria) (*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findOneContext(ctx, c)
+}
+
+// findLateralContext works like findContext, but also joins lateral sub-queries of the criteria using JOIN LATERAL (...) AS alias ON TRUE.
+// Each row of the result holds entity and values of columns of lateral joins, entity is repeated if sub-query returns multiple rows.
+func (r *newsRepositoryBase) findLateralContext(ctx context.Context, c *newsCriteria) ([]*newsLateral, error) {
+	lateral, err := pqtgo.LateralColumns(c.lateral)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBufferString("SELECT t0.content, t0.continue, t0.created_at, t0.id, t0.lead, t0.status, t0.tags, t0.title, t0.updated_at")
+	for _, cn := range lateral {
+		buf.WriteString(", ")
+		buf.WriteString(cn)
+	}
+	buf.WriteString(" FROM (")
+	buf.WriteString(query)
+	buf.WriteString(") AS t0")
+
+	// Placeholders of lateral sub-queries follow those of the criteria.
+	com := pqtgo.NewComposerAt(int64(len(c.lateral)), len(args)+1)
+	if err := pqtgo.WriteLateralJoins(com, c.lateral); err != nil {
+		return nil, err
+	}
+	buf.ReadFrom(com)
+	args = append(args, com.Args()...)
+
+	// Joins do not preserve order of the sub-query, so it is repeated by the outer query.
+	// Columns are qualified, lateral joins can expose columns of the same names.
+	if keys := pqtgo.Keyset(c.sort, tableNewsColumns); len(keys) > 0 || len(c.sortExpr) > 0 {
+		orderBy, err := pqtgo.QualifiedOrderBy("t0", c.sortExpr, keys, tableNewsColumns)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(" ORDER BY ")
+		buf.WriteString(orderBy)
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindLateral"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*newsLateral
+	for rows.Next() {
+		var ent newsEntity
+		values := make([]interface{}, len(lateral))
+		dest := []interface{}{
+			&ent.content,
+			&ent.cont,
+			&ent.createdAt,
+			&ent.id,
+			&ent.lead,
+			&ent.status,
+			&ent.tags,
+			&ent.title,
+			&ent.updatedAt,
+		}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := &newsLateral{entity: &ent, lateral: make(map[string]interface{}, len(lateral))}
+		for i, cn := range lateral {
+			row.lateral[cn] = values[i]
+		}
+		res = append(res, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+func (r *newsRepositoryBase) findLateral(c *newsCriteria) ([]*newsLateral, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findLateralContext(ctx, c)
+}
+
+// findOneByIDQuery returns query and arguments used by findOneByIDContext to retrieve the entity, without executing it.
+func (r *newsRepositoryBase) findOneByIDQuery(id int64) (string, []interface{}, error) {
+	return `SELECT content, continue, created_at, id, lead, status, tags, title, updated_at FROM example.news WHERE id = $1`, []interface{}{id}, nil
+}
+
+func (r *newsRepositoryBase) findOneByIDContext(ctx context.Context, id int64) (*newsEntity, error) {
+	query, args, err := r.findOneByIDQuery(id)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "FindOneByID"); err != nil {
+			return nil, err
+		}
+	}
+
+	var ent newsEntity
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&ent.content,
+		&ent.cont,
+		&ent.createdAt,
+		&ent.id,
+		&ent.lead,
+		&ent.status,
+		&ent.tags,
+		&ent.title,
+		&ent.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+func (r *newsRepositoryBase) findOneByID(id int64) (*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findOneByIDContext(ctx, id)
+}
+
+// findOneByTitleContext retrieves single entity using example.news_title_key unique constraint, sql.ErrNoRows is returned if it does not exist.
+func (r *newsRepositoryBase) findOneByTitleContext(ctx context.Context, title string) (*newsEntity, error) {
+	var (
+		ent newsEntity
+	)
+	query := `SELECT content, continue, created_at, id, lead, status, tags, title, updated_at FROM example.news WHERE title = $1`
+	err := r.db.QueryRowContext(ctx, query, title).Scan(
+		&ent.content,
+		&ent.cont,
+		&ent.createdAt,
+		&ent.id,
+		&ent.lead,
+		&ent.status,
+		&ent.tags,
+		&ent.title,
+		&ent.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+func (r *newsRepositoryBase) findOneByTitle(title string) (*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findOneByTitleContext(ctx, title)
+}
+
+// findOneByTitleAndLeadContext retrieves single entity using example.news_title_lead_key unique constraint, sql.ErrNoRows is returned if it does not exist.
+func (r *newsRepositoryBase) findOneByTitleAndLeadContext(ctx context.Context, title string, lead string) (*newsEntity, error) {
+	var (
+		ent newsEntity
+	)
+	query := `SELECT content, continue, created_at, id, lead, status, tags, title, updated_at FROM example.news WHERE title = $1 AND lead = $2`
+	err := r.db.QueryRowContext(ctx, query, title, lead).Scan(
+		&ent.content,
+		&ent.cont,
+		&ent.createdAt,
+		&ent.id,
+		&ent.lead,
+		&ent.status,
+		&ent.tags,
+		&ent.title,
+		&ent.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+func (r *newsRepositoryBase) findOneByTitleAndLead(title string, lead string) (*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findOneByTitleAndLeadContext(ctx, title, lead)
+}
+
+// insertQuery returns query and arguments used by insertContext to save given entity, without executing it.
+func (r *newsRepositoryBase) insertQuery(e *newsEntity) (string, []interface{}, error) {
+	if err := e.validate(); err != nil {
+		return "", nil, err
+	}
+
+	insert := pqcomp.New(0, 9)
+	insert.AddExpr(tableNewsColumnContent, "", e.content)
+
+	if e.cont {
+		insert.AddExpr(tableNewsColumnContinue, "", e.cont)
+	}
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tableNewsColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tableNewsColumnLead, "", e.lead)
+	insert.AddExpr(tableNewsColumnStatus, "", e.status)
+	insert.AddExpr(tableNewsColumnTags, "", e.tags)
+	insert.AddExpr(tableNewsColumnTitle, "", e.title)
+	insert.AddExpr(tableNewsColumnUpdatedAt, "", e.updatedAt)
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() != 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+	}
+
+	return b.String(), insert.Args(), nil
+}
+
+// insertContext saves given entity and returns it with values read back from the database, e.g. defaults.
+func (r *newsRepositoryBase) insertContext(ctx context.Context, e *newsEntity) (*newsEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := r.insertQuery(e)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Insert"); err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.content,
+		&e.cont,
+		&e.createdAt,
+		&e.id,
+		&e.lead,
+		&e.status,
+		&e.tags,
+		&e.title,
+		&e.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if r.afterInsert != nil {
+		if err := r.afterInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+func (r *newsRepositoryBase) insert(e *newsEntity) (*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.insertContext(ctx, e)
+}
+
+// insertReturningContext works like insertContext, but reads back only columns given by pqt.WithReturning table option.
+func (r *newsRepositoryBase) insertReturningContext(ctx context.Context, e *newsEntity) (*newsReturning, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+
+	insert := pqcomp.New(0, 9)
+	insert.AddExpr(tableNewsColumnContent, "", e.content)
+
+	if e.cont {
+		insert.AddExpr(tableNewsColumnContinue, "", e.cont)
+	}
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tableNewsColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tableNewsColumnLead, "", e.lead)
+	insert.AddExpr(tableNewsColumnStatus, "", e.status)
+	insert.AddExpr(tableNewsColumnTags, "", e.tags)
+	insert.AddExpr(tableNewsColumnTitle, "", e.title)
+	insert.AddExpr(tableNewsColumnUpdatedAt, "", e.updatedAt)
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() != 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+		b.WriteString(" RETURNING " + tableNewsColumnID + ", " + tableNewsColumnTitle)
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", b.String(), "function", "InsertReturning"); err != nil {
+			return nil, err
+		}
+	}
+
+	var ret newsReturning
+	err := r.db.QueryRowContext(ctx, b.String(), insert.Args()...).Scan(
+		&ret.id,
+		&ret.title,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+func (r *newsRepositoryBase) insertReturning(e *newsEntity) (*newsReturning, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.insertReturningContext(ctx, e)
+}
+
+// insertReturningColumnsContext works like insertContext, but returns new entity with only given columns populated, unknown columns are rejected before execution.
+func (r *newsRepositoryBase) insertReturningColumnsContext(ctx context.Context, e *newsEntity, cols ...string) (*newsEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(cols) == 0 {
+		return nil, errors.New("news insert failure, no columns to return")
+	}
+	var ent newsEntity
+	props, err := ent.props(cols...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+
+	insert := pqcomp.New(0, 9)
+	insert.AddExpr(tableNewsColumnContent, "", e.content)
+
+	if e.cont {
+		insert.AddExpr(tableNewsColumnContinue, "", e.cont)
+	}
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tableNewsColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tableNewsColumnLead, "", e.lead)
+	insert.AddExpr(tableNewsColumnStatus, "", e.status)
+	insert.AddExpr(tableNewsColumnTags, "", e.tags)
+	insert.AddExpr(tableNewsColumnTitle, "", e.title)
+	insert.AddExpr(tableNewsColumnUpdatedAt, "", e.updatedAt)
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() != 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+		b.WriteString(" RETURNING " + strings.Join(cols, ", "))
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", b.String(), "function", "InsertReturningColumns"); err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.db.QueryRowContext(ctx, b.String(), insert.Args()...).Scan(props...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+func (r *newsRepositoryBase) insertReturningColumns(e *newsEntity, cols ...string) (*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.insertReturningColumnsContext(ctx, e, cols...)
+}
+
+// insertBatchContext saves given entities using multi-row statements and returns them with values read back from the database.
+// Column with default value gets DEFAULT in rows that do not hold explicit value, like in insertContext.
+// All entities are validated first, so none of them is sent if any is invalid.
+func (r *newsRepositoryBase) insertBatchContext(ctx context.Context, es []*newsEntity) ([]*newsEntity, error) {
+	for _, e := range es {
+		if r.beforeInsert != nil {
+			if err := r.beforeInsert(ctx, e); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := e.validate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, chunk := range pqtgo.Chunks(len(es), 8) {
+		batch := es[chunk[0]:chunk[1]]
+		com := pqtgo.NewComposer(int64(len(batch) * 8))
+		for i, e := range batch {
+			if i != 0 {
+				com.WriteString(", ")
+			}
+			com.WriteString("(")
+			com.WritePlaceholder()
+			com.Add(e.content)
+			com.WriteString(", ")
+			if e.cont {
+				com.WritePlaceholder()
+				com.Add(e.cont)
+			} else {
+				com.WriteString("DEFAULT")
+			}
+			com.WriteString(", ")
+			if !e.createdAt.IsZero() {
+				com.WritePlaceholder()
+				com.Add(e.createdAt)
+			} else {
+				com.WriteString("DEFAULT")
+			}
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.lead)
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.status)
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.tags)
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.title)
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.updatedAt)
+			com.WriteString(")")
+		}
+
+		b := bytes.NewBufferString("INSERT INTO " + r.table + " (")
+		b.WriteString(tableNewsColumnContent)
+		b.WriteString(", ")
+		b.WriteString(tableNewsColumnContinue)
+		b.WriteString(", ")
+		b.WriteString(tableNewsColumnCreatedAt)
+		b.WriteString(", ")
+		b.WriteString(tableNewsColumnLead)
+		b.WriteString(", ")
+		b.WriteString(tableNewsColumnStatus)
+		b.WriteString(", ")
+		b.WriteString(tableNewsColumnTags)
+		b.WriteString(", ")
+		b.WriteString(tableNewsColumnTitle)
+		b.WriteString(", ")
+		b.WriteString(tableNewsColumnUpdatedAt)
+		b.WriteString(") VALUES ")
+		b.ReadFrom(com)
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+
+		if r.dbg {
+			if err := r.log.Log("msg", b.String(), "function", "InsertBatch"); err != nil {
+				return nil, err
+			}
+		}
+
+		rows, err := r.db.QueryContext(ctx, b.String(), com.Args()...)
+		if err != nil {
+			return nil, err
+		}
+		returned := 0
+		for ; returned < len(batch) && rows.Next(); returned++ {
+			err = rows.Scan(
+				&batch[returned].content,
+				&batch[returned].cont,
+				&batch[returned].createdAt,
+				&batch[returned].id,
+				&batch[returned].lead,
+				&batch[returned].status,
+				&batch[returned].tags,
+				&batch[returned].title,
+				&batch[returned].updatedAt,
+			)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+		}
+		if err = rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+		if len(r.columns) > 0 && returned != len(batch) {
+			return nil, fmt.Errorf("news insert batch failure, %d rows inserted, but %d returned", len(batch), returned)
+		}
+		for _, e := range batch {
+			if r.afterInsert != nil {
+				if err := r.afterInsert(ctx, e); err != nil {
+					return nil, err
+				}
+			}
+
+		}
+	}
+
+	return es, nil
+}
+func (r *newsRepositoryBase) insertBatch(es []*newsEntity) ([]*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.insertBatchContext(ctx, es)
+}
+
+// bulkInsertContext loads given entities using COPY protocol and returns number of loaded rows.
+// Serial columns and columns with default value are omitted, they are populated by the database.
+// COPY does not support RETURNING clause, so generated values (e.g. ids) are not set on given entities.
+// All entities are validated first, so none of them is sent if any is invalid.
+// Database of the repository has to implement pqtgo.TxBeginner, like *sql.DB, or pqtgo.Preparer within a transaction, like *sql.Tx.
+func (r *newsRepositoryBase) bulkInsertContext(ctx context.Context, es []*newsEntity) (int64, error) {
+	for _, e := range es {
+		if r.beforeInsert != nil {
+			if err := r.beforeInsert(ctx, e); err != nil {
+				return 0, err
+			}
+		}
+
+		if err := e.validate(); err != nil {
+			return 0, err
+		}
+	}
+	query := pq.CopyInSchema("example", "news", tableNewsColumnContent, tableNewsColumnLead, tableNewsColumnStatus, tableNewsColumnTags, tableNewsColumnTitle, tableNewsColumnUpdatedAt)
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "BulkInsert"); err != nil {
+			return 0, err
+		}
+	}
+
+	return pqtgo.CopyIn(ctx, r.db, query, len(es), r.bulkSize, func(i int) []interface{} {
+		return []interface{}{
+			es[i].content,
+			es[i].lead,
+			es[i].status,
+			es[i].tags,
+			es[i].title,
+			es[i].updatedAt,
+		}
+	})
+}
+func (r *newsRepositoryBase) bulkInsert(es []*newsEntity) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.bulkInsertContext(ctx, es)
+}
+
+// upsertOnContext saves given entity, on conflict with given target it updates existing row using the patch, or values of the entity if patch is nil.
+// pqt.ErrUpsertIgnored is returned if conflicting row was not updated.
+func (r *newsRepositoryBase) upsertOnContext(ctx context.Context, e *newsEntity, p *newsPatch, ct pqt.UpsertConflictTarget) (*newsEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+	if p != nil {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	insert := pqcomp.New(0, 9)
+	update := insert.Compose(9)
+	insert.AddExpr(tableNewsColumnContent, "", e.content)
+
+	if e.cont {
+		insert.AddExpr(tableNewsColumnContinue, "", e.cont)
+	}
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tableNewsColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tableNewsColumnLead, "", e.lead)
+	insert.AddExpr(tableNewsColumnStatus, "", e.status)
+	insert.AddExpr(tableNewsColumnTags, "", e.tags)
+	insert.AddExpr(tableNewsColumnTitle, "", e.title)
+	insert.AddExpr(tableNewsColumnUpdatedAt, "", e.updatedAt)
+	if p != nil && !ct.IsZero() {
+		update.AddExpr(tableNewsColumnContent, "=", p.content)
+		update.AddExpr(tableNewsColumnContinue, "=", p.cont)
+		update.AddExpr(tableNewsColumnCreatedAt, "=", p.createdAt)
+		update.AddExpr(tableNewsColumnLead, "=", p.lead)
+		update.AddExpr(tableNewsColumnStatus, "=", p.status)
+		update.AddExpr(tableNewsColumnTags, "=", p.tags)
+		update.AddExpr(tableNewsColumnTitle, "=", p.title)
+		update.AddExpr(tableNewsColumnUpdatedAt, "=", p.updatedAt)
+	}
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() > 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+	}
+	var excluded []string
+	if p == nil && !ct.IsZero() {
+		insert.Reset()
+		for insert.Next() {
+			if !ct.HasColumn(insert.Key()) {
+				excluded = append(excluded, insert.Key()+" = EXCLUDED."+insert.Key())
+			}
+		}
+	}
+
+	b.WriteString(" ON CONFLICT ")
+	if !ct.IsZero() {
+		b.WriteString(ct.String())
+	}
+	switch {
+	case len(excluded) > 0:
+		b.WriteString(" DO UPDATE SET ")
+		b.WriteString(strings.Join(excluded, ", "))
+	case !ct.IsZero() && update.Len() > 0:
+		b.WriteString(" DO UPDATE SET ")
+		for update.Next() {
+			if !update.First() {
+				b.WriteString(", ")
+			}
+
+			b.WriteString(update.Key())
+			b.WriteString(" ")
+			b.WriteString(update.Oper())
+			b.WriteString(" ")
+			b.WriteString(update.PlaceHolder())
+		}
+	default:
+		b.WriteString(" DO NOTHING ")
+	}
+	if insert.Len() > 0 {
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", b.String(), "function", "Upsert"); err != nil {
+			return nil, err
+		}
+	}
+
+	err := r.db.QueryRowContext(ctx, b.String(), insert.Args()...).Scan(
+		&e.content,
+		&e.cont,
+		&e.createdAt,
+		&e.id,
+		&e.lead,
+		&e.status,
+		&e.tags,
+		&e.title,
+		&e.updatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, pqt.ErrUpsertIgnored
+		}
+		return nil, err
+	}
+
+	return e, nil
+}
+func (r *newsRepositoryBase) upsertOn(e *newsEntity, p *newsPatch, ct pqt.UpsertConflictTarget) (*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.upsertOnContext(ctx, e, p, ct)
+}
+
+// upsertContext is a shorthand for upsertOnContext, given columns are used to infer the conflict target.
+func (r *newsRepositoryBase) upsertContext(ctx context.Context, e *newsEntity, p *newsPatch, inf ...string) (*newsEntity, error) {
+	return r.upsertOnContext(ctx, e, p, pqt.ConflictOnColumns(inf...))
+}
+func (r *newsRepositoryBase) upsert(e *newsEntity, p *newsPatch, inf ...string) (*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.upsertContext(ctx, e, p, inf...)
+}
+
+// updateOneByIDQuery returns query and arguments used by updateOneByIDContext and its variants to modify the entity, without executing it.
+// Given columns are listed in RETURNING clause as they are, which is omitted if there are none.
+func (r *newsRepositoryBase) updateOneByIDQuery(id int64, patch *newsPatch, returning []string) (string, []interface{}, error) {
+	if err := patch.validate(); err != nil {
+		return "", nil, err
+	}
+	update := pqcomp.New(1, 9)
+	update.AddArg(id)
+
+	update.AddExpr(tableNewsColumnContent, pqcomp.Equal, patch.content)
+	update.AddExpr(tableNewsColumnContinue, pqcomp.Equal, patch.cont)
+	if patch.createdAt != nil {
+		update.AddExpr(tableNewsColumnCreatedAt, pqcomp.Equal, patch.createdAt)
+
+	}
+	update.AddExpr(tableNewsColumnLead, pqcomp.Equal, patch.lead)
+	update.AddExpr(tableNewsColumnStatus, pqcomp.Equal, patch.status)
+	update.AddExpr(tableNewsColumnTags, pqcomp.Equal, patch.tags)
+	update.AddExpr(tableNewsColumnTitle, pqcomp.Equal, patch.title)
+	if patch.updatedAt != nil {
+		update.AddExpr(tableNewsColumnUpdatedAt, pqcomp.Equal, patch.updatedAt)
+	} else {
+		update.AddExpr(tableNewsColumnUpdatedAt, pqcomp.Equal, "NOW()")
+	}
+
+	if update.Len() == 0 {
+		return "", nil, pqt.ErrNothingToUpdate
+	}
+	query := "UPDATE example.news SET "
+	for update.Next() {
+		if !update.First() {
+			query += ", "
+		}
+
+		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
+	}
+	query += " WHERE id = $1"
+	if len(returning) > 0 {
+		query += " RETURNING " + strings.Join(returning, ", ")
+	}
+	return query, update.Args(), nil
+}
+
+// updateOneByIDContext modifies the entity using values set in the patch and returns it.
+// pqt.ErrNothingToUpdate is returned if the patch holds no value.
+func (r *newsRepositoryBase) updateOneByIDContext(ctx context.Context, id int64, patch *newsPatch) (*newsEntity, error) {
+	if r.beforeUpdate != nil {
+		if err := r.beforeUpdate(ctx, id, patch); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := r.updateOneByIDQuery(id, patch, r.columns)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "UpdateOneByID"); err != nil {
+			return nil, err
+		}
+	}
+
+	var e newsEntity
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.content,
+		&e.cont,
+		&e.createdAt,
+		&e.id,
+		&e.lead,
+		&e.status,
+		&e.tags,
+		&e.title,
+		&e.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if r.afterUpdate != nil {
+		if err := r.afterUpdate(ctx, &e); err != nil {
+			return nil, err
+		}
+	}
+
+	return &e, nil
+}
+func (r *newsRepositoryBase) updateOneByID(id int64, patch *newsPatch) (*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.updateOneByIDContext(ctx, id, patch)
+}
+
+// updateOneByIDReturningContext works like updateOneByIDContext, but reads back only columns given by pqt.WithReturning table option.
+func (r *newsRepositoryBase) updateOneByIDReturningContext(ctx context.Context, id int64, patch *newsPatch) (*newsReturning, error) {
+	if r.beforeUpdate != nil {
+		if err := r.beforeUpdate(ctx, id, patch); err != nil {
+			return nil, err
+		}
+	}
+	query, args, err := r.updateOneByIDQuery(id, patch, []string{tableNewsColumnID, tableNewsColumnTitle})
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "UpdateOneByIDReturning"); err != nil {
+			return nil, err
+		}
+	}
+
+	var ret newsReturning
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&ret.id,
+		&ret.title,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+func (r *newsRepositoryBase) updateOneByIDReturning(id int64, patch *newsPatch) (*newsReturning, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.updateOneByIDReturningContext(ctx, id, patch)
+}
+
+// updateOneByIDReturningColumnsContext works like updateOneByIDContext, but returns entity with only given columns populated, unknown columns are rejected before execution.
+func (r *newsRepositoryBase) updateOneByIDReturningColumnsContext(ctx context.Context, id int64, patch *newsPatch, cols ...string) (*newsEntity, error) {
+	if r.beforeUpdate != nil {
+		if err := r.beforeUpdate(ctx, id, patch); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(cols) == 0 {
+		return nil, errors.New("news update failure, no columns to return")
+	}
+	var ent newsEntity
+	props, err := ent.props(cols...)
+	if err != nil {
+		return nil, err
+	}
+	query, args, err := r.updateOneByIDQuery(id, patch, cols)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "UpdateOneByIDReturningColumns"); err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(props...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+func (r *newsRepositoryBase) updateOneByIDReturningColumns(id int64, patch *newsPatch, cols ...string) (*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.updateOneByIDReturningColumnsContext(ctx, id, patch, cols...)
+}
+
+// patchOneByIDContext works like updateOneByIDContext, but returns number of affected rows instead of the entity.
+func (r *newsRepositoryBase) patchOneByIDContext(ctx context.Context, id int64, patch *newsPatch) (int64, error) {
+	if r.beforeUpdate != nil {
+		if err := r.beforeUpdate(ctx, id, patch); err != nil {
+			return 0, err
+		}
+	}
+	query, args, err := r.updateOneByIDQuery(id, patch, nil)
+	if err != nil {
+		return 0, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "PatchOneByID"); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+func (r *newsRepositoryBase) patchOneByID(id int64, patch *newsPatch) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.patchOneByIDContext(ctx, id, patch)
+}
+func (r *newsRepositoryBase) updateOneByTitleContext(ctx context.Context, title string, patch *newsPatch) (*newsEntity, error) {
+	if err := patch.validate(); err != nil {
+		return nil, err
+	}
+	update := pqcomp.New(1, 9)
+	update.AddArg(title)
+	update.AddExpr(tableNewsColumnContent, pqcomp.Equal, patch.content)
+	update.AddExpr(tableNewsColumnContinue, pqcomp.Equal, patch.cont)
+	if patch.createdAt != nil {
+		update.AddExpr(tableNewsColumnCreatedAt, pqcomp.Equal, patch.createdAt)
+
+	}
+	update.AddExpr(tableNewsColumnLead, pqcomp.Equal, patch.lead)
+	update.AddExpr(tableNewsColumnStatus, pqcomp.Equal, patch.status)
+	update.AddExpr(tableNewsColumnTags, pqcomp.Equal, patch.tags)
+	update.AddExpr(tableNewsColumnTitle, pqcomp.Equal, patch.title)
+	if patch.updatedAt != nil {
+		update.AddExpr(tableNewsColumnUpdatedAt, pqcomp.Equal, patch.updatedAt)
+	} else {
+		update.AddExpr(tableNewsColumnUpdatedAt, pqcomp.Equal, "NOW()")
+	}
+
+	if update.Len() == 0 {
+		return nil, pqt.ErrNothingToUpdate
+	}
+	query := "UPDATE example.news SET "
+	for update.Next() {
+		if !update.First() {
+			query += ", "
+		}
+
+		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
+	}
+	query += " WHERE title = $1 RETURNING " + strings.Join(r.columns, ", ")
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "UpdateOneByTitle"); err != nil {
+			return nil, err
+		}
+	}
+	var e newsEntity
+	err := r.db.QueryRowContext(ctx, query, update.Args()...).Scan(
+		&e.content,
+		&e.cont,
+		&e.createdAt,
+		&e.id,
+		&e.lead,
+		&e.status,
+		&e.tags,
+		&e.title,
+		&e.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if r.afterUpdate != nil {
+		if err := r.afterUpdate(ctx, &e); err != nil {
+			return nil, err
+		}
+	}
+
+	return &e, nil
+}
+func (r *newsRepositoryBase) updateOneByTitle(title string, patch *newsPatch) (*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.updateOneByTitleContext(ctx, title, patch)
+}
+func (r *newsRepositoryBase) updateOneByTitleAndLeadContext(ctx context.Context, title string, lead string, patch *newsPatch) (*newsEntity, error) {
+	if err := patch.validate(); err != nil {
+		return nil, err
+	}
+	update := pqcomp.New(2, 9)
+	update.AddArg(title)
+	update.AddArg(lead)
+	update.AddExpr(tableNewsColumnContent, pqcomp.Equal, patch.content)
+	update.AddExpr(tableNewsColumnContinue, pqcomp.Equal, patch.cont)
+	if patch.createdAt != nil {
+		update.AddExpr(tableNewsColumnCreatedAt, pqcomp.Equal, patch.createdAt)
+
+	}
+	update.AddExpr(tableNewsColumnLead, pqcomp.Equal, patch.lead)
+	update.AddExpr(tableNewsColumnStatus, pqcomp.Equal, patch.status)
+	update.AddExpr(tableNewsColumnTags, pqcomp.Equal, patch.tags)
+	update.AddExpr(tableNewsColumnTitle, pqcomp.Equal, patch.title)
+	if patch.updatedAt != nil {
+		update.AddExpr(tableNewsColumnUpdatedAt, pqcomp.Equal, patch.updatedAt)
+	} else {
+		update.AddExpr(tableNewsColumnUpdatedAt, pqcomp.Equal, "NOW()")
+	}
+
+	if update.Len() == 0 {
+		return nil, pqt.ErrNothingToUpdate
+	}
+	query := "UPDATE example.news SET "
+	for update.Next() {
+		if !update.First() {
+			query += ", "
+		}
+
+		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
+	}
+	query += " WHERE title = $1 AND lead = $2 RETURNING " + strings.Join(r.columns, ", ")
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "UpdateOneByTitleAndLead"); err != nil {
+			return nil, err
+		}
+	}
+	var e newsEntity
+	err := r.db.QueryRowContext(ctx, query, update.Args()...).Scan(
+		&e.content,
+		&e.cont,
+		&e.createdAt,
+		&e.id,
+		&e.lead,
+		&e.status,
+		&e.tags,
+		&e.title,
+		&e.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if r.afterUpdate != nil {
+		if err := r.afterUpdate(ctx, &e); err != nil {
+			return nil, err
+		}
+	}
+
+	return &e, nil
+}
+func (r *newsRepositoryBase) updateOneByTitleAndLead(title string, lead string, patch *newsPatch) (*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.updateOneByTitleAndLeadContext(ctx, title, lead, patch)
+}
+
+// updateOrInsertByTitleContext inserts given entity or updates existing one that has the same title.
+// Updated row gets all values that insert would set, except the key itself. Returned flag is true if row was inserted.
+func (r *newsRepositoryBase) updateOrInsertByTitleContext(ctx context.Context, e *newsEntity) (*newsEntity, bool, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := e.validate(); err != nil {
+		return nil, false, err
+	}
+
+	insert := pqcomp.New(0, 9)
+	insert.AddExpr(tableNewsColumnContent, "", e.content)
+
+	if e.cont {
+		insert.AddExpr(tableNewsColumnContinue, "", e.cont)
+	}
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tableNewsColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tableNewsColumnLead, "", e.lead)
+	insert.AddExpr(tableNewsColumnStatus, "", e.status)
+	insert.AddExpr(tableNewsColumnTags, "", e.tags)
+	insert.AddExpr(tableNewsColumnTitle, "", e.title)
+	insert.AddExpr(tableNewsColumnUpdatedAt, "", e.updatedAt)
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() != 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+		var excluded []string
+		insert.Reset()
+		for insert.Next() {
+			switch insert.Key() {
+			case tableNewsColumnTitle, tableNewsColumnID:
+			default:
+				excluded = append(excluded, insert.Key()+" = EXCLUDED."+insert.Key())
+			}
+		}
+		if len(excluded) == 0 {
+			// DO NOTHING would not return existing row.
+			excluded = append(excluded, "title = EXCLUDED.title")
+		}
+		b.WriteString(" ON CONFLICT (title) DO UPDATE SET ")
+		b.WriteString(strings.Join(excluded, ", "))
+		b.WriteString(" RETURNING ")
+		b.WriteString(strings.Join(r.columns, ", "))
+		b.WriteString(", (xmax = 0)")
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", b.String(), "function", "UpdateOrInsertByTitle"); err != nil {
+			return nil, false, err
+		}
+	}
+
+	var created bool
+	err := r.db.QueryRowContext(ctx, b.String(), insert.Args()...).Scan(
+		&e.content,
+		&e.cont,
+		&e.createdAt,
+		&e.id,
+		&e.lead,
+		&e.status,
+		&e.tags,
+		&e.title,
+		&e.updatedAt,
+		&created,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	if created {
+		if r.afterInsert != nil {
+			if err := r.afterInsert(ctx, e); err != nil {
+				return nil, false, err
+			}
+		}
+
+	} else {
+		if r.afterUpdate != nil {
+			if err := r.afterUpdate(ctx, e); err != nil {
+				return nil, false, err
+			}
+		}
+
+	}
+
+	return e, created, nil
+}
+func (r *newsRepositoryBase) updateOrInsertByTitle(e *newsEntity) (*newsEntity, bool, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.updateOrInsertByTitleContext(ctx, e)
+}
+
+// updateOrInsertByTitleAndLeadContext inserts given entity or updates existing one that has the same title and lead.
+// Updated row gets all values that insert would set, except the key itself. Returned flag is true if row was inserted.
+func (r *newsRepositoryBase) updateOrInsertByTitleAndLeadContext(ctx context.Context, e *newsEntity) (*newsEntity, bool, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := e.validate(); err != nil {
+		return nil, false, err
+	}
+
+	insert := pqcomp.New(0, 9)
+	insert.AddExpr(tableNewsColumnContent, "", e.content)
+
+	if e.cont {
+		insert.AddExpr(tableNewsColumnContinue, "", e.cont)
+	}
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tableNewsColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tableNewsColumnLead, "", e.lead)
+	insert.AddExpr(tableNewsColumnStatus, "", e.status)
+	insert.AddExpr(tableNewsColumnTags, "", e.tags)
+	insert.AddExpr(tableNewsColumnTitle, "", e.title)
+	insert.AddExpr(tableNewsColumnUpdatedAt, "", e.updatedAt)
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() != 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+		var excluded []string
+		insert.Reset()
+		for insert.Next() {
+			switch insert.Key() {
+			case tableNewsColumnTitle, tableNewsColumnLead, tableNewsColumnID:
+			default:
+				excluded = append(excluded, insert.Key()+" = EXCLUDED."+insert.Key())
+			}
+		}
+		if len(excluded) == 0 {
+			// DO NOTHING would not return existing row.
+			excluded = append(excluded, "title = EXCLUDED.title")
+		}
+		b.WriteString(" ON CONFLICT (title, lead) DO UPDATE SET ")
+		b.WriteString(strings.Join(excluded, ", "))
+		b.WriteString(" RETURNING ")
+		b.WriteString(strings.Join(r.columns, ", "))
+		b.WriteString(", (xmax = 0)")
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", b.String(), "function", "UpdateOrInsertByTitleAndLead"); err != nil {
+			return nil, false, err
+		}
+	}
+
+	var created bool
+	err := r.db.QueryRowContext(ctx, b.String(), insert.Args()...).Scan(
+		&e.content,
+		&e.cont,
+		&e.createdAt,
+		&e.id,
+		&e.lead,
+		&e.status,
+		&e.tags,
+		&e.title,
+		&e.updatedAt,
+		&created,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	if created {
+		if r.afterInsert != nil {
+			if err := r.afterInsert(ctx, e); err != nil {
+				return nil, false, err
+			}
+		}
+
+	} else {
+		if r.afterUpdate != nil {
+			if err := r.afterUpdate(ctx, e); err != nil {
+				return nil, false, err
+			}
+		}
+
+	}
+
+	return e, created, nil
+}
+func (r *newsRepositoryBase) updateOrInsertByTitleAndLead(e *newsEntity) (*newsEntity, bool, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.updateOrInsertByTitleAndLeadContext(ctx, e)
+}
+
+// deleteOneByIDQuery returns query and arguments used by deleteOneByIDContext to remove the entity, without executing it.
+func (r *newsRepositoryBase) deleteOneByIDQuery(id int64) (string, []interface{}, error) {
+	return "DELETE FROM example.news WHERE id = $1", []interface{}{id}, nil
+}
+
+// deleteOneByIDContext removes the entity and returns number of affected rows.
+func (r *newsRepositoryBase) deleteOneByIDContext(ctx context.Context, id int64) (int64, error) {
+	if r.beforeDelete != nil {
+		if err := r.beforeDelete(ctx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	query, args, err := r.deleteOneByIDQuery(id)
+	if err != nil {
+		return 0, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "DeleteOneByID"); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil || affected == 0 {
+		return affected, err
+	}
+	if r.afterDelete != nil {
+		if err := r.afterDelete(ctx, id); err != nil {
+			return affected, err
+		}
+	}
+
+	return affected, nil
+}
+func (r *newsRepositoryBase) deleteOneByID(id int64) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.deleteOneByIDContext(ctx, id)
+}
+
+// deleteAndReturnOneByIDContext removes the entity and returns it as it was right before removal.
+// pqt.ErrNotFound is returned if there is no such entity.
+func (r *newsRepositoryBase) deleteAndReturnOneByIDContext(ctx context.Context, id int64) (*newsEntity, error) {
+	if r.beforeDelete != nil {
+		if err := r.beforeDelete(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := r.deleteOneByIDQuery(id)
+	if err != nil {
+		return nil, err
+	}
+	query += " RETURNING " + strings.Join(r.columns, ", ")
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "DeleteAndReturnOneByID"); err != nil {
+			return nil, err
+		}
+	}
+
+	var e newsEntity
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.content,
+		&e.cont,
+		&e.createdAt,
+		&e.id,
+		&e.lead,
+		&e.status,
+		&e.tags,
+		&e.title,
+		&e.updatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, pqt.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.afterDelete != nil {
+		if err := r.afterDelete(ctx, id); err != nil {
+			return &e, err
+		}
+	}
+
+	return &e, nil
+}
+func (r *newsRepositoryBase) deleteAndReturnOneByID(id int64) (*newsEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.deleteAndReturnOneByIDContext(ctx, id)
+}
+
+// deleteByCriteriaContext removes entities that match given criteria and returns their number.
+// Empty criteria is rejected with pqt.ErrDeleteWithoutCriteria unless full scan is allowed. Sort, offset, limit and lock are not supported.
+func (r *newsRepositoryBase) deleteByCriteriaContext(ctx context.Context, c *newsCriteria, allowFullScan bool) (int64, error) {
+	if len(c.sort) > 0 || len(c.sortExpr) > 0 || c.Offset > 0 || c.Limit > 0 || c.lock != pqt.LockNone {
+		return 0, errors.New("news delete failure, sort, offset, limit and lock are not supported")
+	}
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return 0, err
+	}
+	buf := bytes.NewBufferString("DELETE FROM ")
+	buf.WriteString(r.table)
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	} else if !allowFullScan {
+		return 0, pqt.ErrDeleteWithoutCriteria
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "DeleteByCriteria"); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := r.db.ExecContext(ctx, buf.String(), args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+func (r *newsRepositoryBase) deleteByCriteria(c *newsCriteria, allowFullScan bool) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.deleteByCriteriaContext(ctx, c, allowFullScan)
+}
+
+// truncateContext removes all rows from the table.
+// If cascade is true, tables that have foreign key references to the table are truncated as well.
+// If restartIdentity is true, sequences owned by columns of the table are restarted.
+func (r *newsRepositoryBase) truncateContext(ctx context.Context, cascade, restartIdentity bool) error {
+	buf := bytes.NewBufferString("TRUNCATE ")
+	buf.WriteString(r.table)
+	if restartIdentity {
+		buf.WriteString(" RESTART IDENTITY")
+	}
+	if cascade {
+		buf.WriteString(" CASCADE")
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Truncate"); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.db.ExecContext(ctx, buf.String())
+	return err
+}
+func (r *newsRepositoryBase) truncate(cascade, restartIdentity bool) error {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.truncateContext(ctx, cascade, restartIdentity)
+}
+
+const (
+	tableComment                              = "example.comment"
+	tableCommentColumnContent                 = "content"
+	tableCommentColumnCreatedAt               = "created_at"
+	tableCommentColumnID                      = "id"
+	tableCommentColumnNewsID                  = "news_id"
+	tableCommentColumnNewsTitle               = "news_title"
+	tableCommentColumnUpdatedAt               = "updated_at"
+	tableCommentConstraintNewsIDForeignKey    = "example.comment_news_id_fkey"
+	tableCommentConstraintNewsTitleForeignKey = "example.comment_news_title_fkey"
+)
+
+var (
+	tableCommentColumns = []string{
+		tableCommentColumnContent,
+		tableCommentColumnCreatedAt,
+		tableCommentColumnID,
+		tableCommentColumnNewsID,
+		tableCommentColumnNewsTitle,
+		tableCommentColumnUpdatedAt,
+	}
+)
+
+type commentEntity struct {
+	// content ...
+	content string
+	// createdAt ...
+	createdAt time.Time
+	// id ...
+	id *ntypes.Int64
+	// newsID ...
+	newsID int64
+	// newsTitle ...
+	newsTitle string
+	// updatedAt ...
+	updatedAt *time.Time
+	// newsByTitle ...
+	newsByTitle *newsEntity
+	// newsByID ...
+	newsByID *newsEntity
+}
+
+func (e *commentEntity) prop(cn string) (interface{}, bool) {
+	switch cn {
+	case tableCommentColumnContent:
+		return &e.content, true
+	case tableCommentColumnCreatedAt:
+		return &e.createdAt, true
+	case tableCommentColumnID:
+		return &e.id, true
+	case tableCommentColumnNewsID:
+		return &e.newsID, true
+	case tableCommentColumnNewsTitle:
+		return &e.newsTitle, true
+	case tableCommentColumnUpdatedAt:
+		return &e.updatedAt, true
+	default:
+		return nil, false
+	}
+}
+func (e *commentEntity) props(cns ...string) ([]interface{}, error) {
+
+	res := make([]interface{}, 0, len(cns))
+	for _, cn := range cns {
+		if prop, ok := e.prop(cn); ok {
+			res = append(res, prop)
+		} else {
+			return nil, fmt.Errorf("unexpected column provided: %s", cn)
+		}
+	}
+	return res, nil
+}
+func (e *commentEntity) value(cn string) (interface{}, bool) {
+	switch cn {
+	case tableCommentColumnContent:
+		return e.content, true
+	case tableCommentColumnCreatedAt:
+		return e.createdAt, true
+	case tableCommentColumnID:
+		return e.id, true
+	case tableCommentColumnNewsID:
+		return e.newsID, true
+	case tableCommentColumnNewsTitle:
+		return e.newsTitle, true
+	case tableCommentColumnUpdatedAt:
+		return e.updatedAt, true
+	default:
+		return nil, false
+	}
+}
+func (e *commentEntity) values(cns ...string) ([]interface{}, error) {
+
+	res := make([]interface{}, 0, len(cns))
+	for _, cn := range cns {
+		if value, ok := e.value(cn); ok {
+			res = append(res, value)
+		} else {
+			return nil, fmt.Errorf("unexpected column provided: %s", cn)
+		}
+	}
+	return res, nil
+}
+
+// toMap returns values of the entity keyed by column names, nullable properties are either underlying value or nil.
+func (e *commentEntity) toMap() map[string]interface{} {
+	m := make(map[string]interface{}, 6)
+	m[tableCommentColumnContent] = e.content
+	m[tableCommentColumnCreatedAt] = e.createdAt
+	if e.id != nil && e.id.Valid {
+		m[tableCommentColumnID] = e.id.Int64
+	} else {
+		m[tableCommentColumnID] = nil
+	}
+	m[tableCommentColumnNewsID] = e.newsID
+	m[tableCommentColumnNewsTitle] = e.newsTitle
+	if e.updatedAt != nil {
+		m[tableCommentColumnUpdatedAt] = *e.updatedAt
+	} else {
+		m[tableCommentColumnUpdatedAt] = nil
+	}
+
+	return m
+}
+
+// commentEntityFromMap returns entity built from given values keyed by column names, missing columns are left unset.
+// Error is returned if column is unknown or its value is not of the expected type, nil is accepted by nullable columns only.
+func commentEntityFromMap(m map[string]interface{}) (*commentEntity, error) {
+	e := &commentEntity{}
+	for cn, v := range m {
+		switch cn {
+		case tableCommentColumnContent:
+			if tv, ok := v.(string); ok {
+				e.content = tv
+				continue
+			}
+			return nil, fmt.Errorf("comment from map failure, column %s expects value of type string, got %T", cn, v)
+		case tableCommentColumnCreatedAt:
+			if tv, ok := v.(time.Time); ok {
+				e.createdAt = tv
+				continue
+			}
+			return nil, fmt.Errorf("comment from map failure, column %s expects value of type time.Time, got %T", cn, v)
+		case tableCommentColumnID:
+			if v == nil {
+				e.id = nil
+				continue
+			}
+			if tv, ok := v.(int64); ok {
+				e.id = &ntypes.Int64{Int64: tv, Valid: true}
+				continue
+			}
+			return nil, fmt.Errorf("comment from map failure, column %s expects value of type int64, got %T", cn, v)
+		case tableCommentColumnNewsID:
+			if tv, ok := v.(int64); ok {
+				e.newsID = tv
+				continue
+			}
+			return nil, fmt.Errorf("comment from map failure, column %s expects value of type int64, got %T", cn, v)
+		case tableCommentColumnNewsTitle:
+			if tv, ok := v.(string); ok {
+				e.newsTitle = tv
+				continue
+			}
+			return nil, fmt.Errorf("comment from map failure, column %s expects value of type string, got %T", cn, v)
+		case tableCommentColumnUpdatedAt:
+			if v == nil {
+				e.updatedAt = nil
+				continue
+			}
+			if tv, ok := v.(time.Time); ok {
+				e.updatedAt = &tv
+				continue
+			}
+			return nil, fmt.Errorf("comment from map failure, column %s expects value of type time.Time, got %T", cn, v)
+		default:
+			return nil, fmt.Errorf("comment from map failure, unexpected column provided: %s", cn)
+		}
+	}
+
+	return e, nil
+}
+
+// validate returns pqt.ValidationError if any of mandatory properties is not set.
+func (e *commentEntity) validate() error {
+	var violations []pqt.Violation
+	if e.content == "" {
+		violations = append(violations, pqt.Violation{Column: tableCommentColumnContent, Reason: "is required"})
+	}
+	if e.newsTitle == "" {
+		violations = append(violations, pqt.Violation{Column: tableCommentColumnNewsTitle, Reason: "is required"})
+	}
+	if len(violations) > 0 {
+		return &pqt.ValidationError{Table: tableComment, Violations: violations}
+	}
+
+	return nil
+}
+
+// commentIterator is not thread safe.
+// It has to be closed once it is no longer needed, otherwise underlying connection is not released.
+type commentIterator struct {
+	rows *sql.Rows
+	cols []string
+	// cancel releases context the rows are read within, if the iterator owns it.
+	cancel context.CancelFunc
+}
+
+var _ pqtgo.Iterator = &commentIterator{}
+
+func (i *commentIterator) Next() bool {
+	return i.rows.Next()
+}
+
+func (i *commentIterator) Close() error {
+	if i.cancel != nil {
+		defer i.cancel()
+	}
+	return i.rows.Close()
+}
+
+func (i *commentIterator) Err() error {
+	return i.rows.Err()
+}
+
+// Columns is wrapper around sql.Rows.Columns method, that also cache outpu inside iterator.
+func (i *commentIterator) Columns() ([]string, error) {
+	if i.cols == nil {
+		cols, err := i.rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		i.cols = cols
+	}
+	return i.cols, nil
+}
+
+// Ent is wrapper around comment method, it allows to use the same method name regardless of the table.
+func (i *commentIterator) Ent() (*commentEntity, error) {
+	return i.Comment()
+}
+
+func (i *commentIterator) Comment() (*commentEntity, error) {
+	var ent commentEntity
+	cols, err := i.rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := ent.props(cols...)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.rows.Scan(props...); err != nil {
+		return nil, err
+	}
+	return &ent, nil
+}
+
+// commentCriteria selects entities that queries of the repository operate on, its zero value selects all of them.
+// Offset and Limit are translated into OFFSET and LIMIT clauses if non-zero, lock appends locking clause that is effective within a transaction only.
+// Keys of sort have to be columns of the table.
+// Expressions of sortExpr precede sort, they can refer to columns of the table and call functions listed in pqt.SortFunctions.
+type commentCriteria struct {
+	Offset, Limit   int64
+	sort            map[string]bool
+	sortExpr        []pqt.SortExpr
+	countDistinct   string
+	lock            pqt.LockMode
+	lateral         []pqt.LateralJoin
+	content         *qtypes.String
+	createdAt       *qtypes.Timestamp
+	createdAtBounds pqt.RangeBounds
+	id              *qtypes.Int64
+	newsID          *qtypes.Int64
+	newsTitle       *qtypes.String
+	updatedAt       *qtypes.Timestamp
+	updatedAtBounds pqt.RangeBounds
+}
+
+func (c *commentCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *pqtgo.CompositionOpts) (err error) {
+
+	if err = pqtgo.WriteCompositionQueryString(c.content, tableCommentColumnContent, com, pqtgo.And); err != nil {
+		return
+	}
+
+	if c.createdAt != nil && c.createdAt.Valid {
+		switch c.createdAt.Type {
+		case qtypes.QueryType_NULL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCommentColumnCreatedAt)
+			if c.createdAt.Negation {
+				com.WriteString(" IS NOT NULL ")
+			} else {
+				com.WriteString(" IS NULL ")
+			}
+		case qtypes.QueryType_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCommentColumnCreatedAt)
+			if c.createdAt.Negation {
+				com.WriteString(" <> ")
+			} else {
+				com.WriteString(" = ")
+			}
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_GREATER:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCommentColumnCreatedAt)
+			com.WriteString(">")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_GREATER_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCommentColumnCreatedAt)
+			com.WriteString(">=")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_LESS:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCommentColumnCreatedAt)
+			com.WriteString(" < ")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_LESS_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCommentColumnCreatedAt)
+			com.WriteString(" <= ")
+			com.WritePlaceholder()
+			com.Add(c.createdAt.Value())
+		case qtypes.QueryType_IN:
+			if len(c.createdAt.Values) > 0 {
+				if com.Dirty {
+					com.WriteString(" AND ")
+				}
+				com.Dirty = true
+
+				com.WriteString(tableCommentColumnCreatedAt)
+				com.WriteString(" IN (")
+				for i, v := range c.createdAt.Values {
+					if i != 0 {
+						com.WriteString(", ")
+					}
+					com.WritePlaceholder()
+					com.Add(v)
+				}
+				com.WriteString(") ")
+			}
+		case qtypes.QueryType_BETWEEN:
+			// Missing bound leaves the range open on that side.
+			if len(c.createdAt.Values) > 0 && c.createdAt.Values[0] != nil {
+				createdAt1, err := ptypes.Timestamp(c.createdAt.Values[0])
+				if err != nil {
+					return err
+				}
+				if com.Dirty {
+					com.WriteString(" AND ")
+				}
+				com.Dirty = true
+
+				com.WriteString(tableCommentColumnCreatedAt)
+				com.WriteString(" " + c.createdAtBounds.LowerOperator() + " ")
+				com.WritePlaceholder()
+				com.Add(createdAt1)
+			}
+			if len(c.createdAt.Values) > 1 && c.createdAt.Values[1] != nil {
+				createdAt2, err := ptypes.Timestamp(c.createdAt.Values[1])
+				if err != nil {
+					return err
+				}
+				if com.Dirty {
+					com.WriteString(" AND ")
+				}
+				com.Dirty = true
+
+				com.WriteString(tableCommentColumnCreatedAt)
+				com.WriteString(" " + c.createdAtBounds.UpperOperator() + " ")
+				com.WritePlaceholder()
+				com.Add(createdAt2)
+			}
+		}
+	}
+
+	if err = pqtgo.WriteCompositionQueryInt64(c.id, tableCommentColumnID, com, pqtgo.And); err != nil {
+		return
+	}
+
+	if err = pqtgo.WriteCompositionQueryInt64(c.newsID, tableCommentColumnNewsID, com, pqtgo.And); err != nil {
+		return
+	}
+
+	if err = pqtgo.WriteCompositionQueryString(c.newsTitle, tableCommentColumnNewsTitle, com, pqtgo.And); err != nil {
+		return
+	}
+
+	if c.updatedAt != nil && c.updatedAt.Valid {
+		switch c.updatedAt.Type {
+		case qtypes.QueryType_NULL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCommentColumnUpdatedAt)
+			if c.updatedAt.Negation {
+				com.WriteString(" IS NOT NULL ")
+			} else {
+				com.WriteString(" IS NULL ")
+			}
+		case qtypes.QueryType_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCommentColumnUpdatedAt)
+			if c.updatedAt.Negation {
+				com.WriteString(" <> ")
+			} else {
+				com.WriteString(" = ")
+			}
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_GREATER:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCommentColumnUpdatedAt)
+			com.WriteString(">")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_GREATER_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCommentColumnUpdatedAt)
+			com.WriteString(">=")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_LESS:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCommentColumnUpdatedAt)
+			com.WriteString(" < ")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_LESS_EQUAL:
+			if com.Dirty {
+				com.WriteString(" AND ")
+			}
+			com.Dirty = true
+
+			com.WriteString(tableCommentColumnUpdatedAt)
+			com.WriteString(" <= ")
+			com.WritePlaceholder()
+			com.Add(c.updatedAt.Value())
+		case qtypes.QueryType_IN:
+			if len(c.updatedAt.Values) > 0 {
+				if com.Dirty {
+					com.WriteString(" AND ")
+				}
+				com.Dirty = true
+
+				com.WriteString(tableCommentColumnUpdatedAt)
+				com.WriteString(" IN (")
+				for i, v := range c.updatedAt.Values {
+					if i != 0 {
+						com.WriteString(", ")
+					}
+					com.WritePlaceholder()
+					com.Add(v)
+				}
+				com.WriteString(") ")
+			}
+		case qtypes.QueryType_BETWEEN:
+			// Missing bound leaves the range open on that side.
+			if len(c.updatedAt.Values) > 0 && c.updatedAt.Values[0] != nil {
+				updatedAt1, err := ptypes.Timestamp(c.updatedAt.Values[0])
+				if err != nil {
+					return err
+				}
+				if com.Dirty {
+					com.WriteString(" AND ")
+				}
+				com.Dirty = true
+
+				com.WriteString(tableCommentColumnUpdatedAt)
+				com.WriteString(" " + c.updatedAtBounds.LowerOperator() + " ")
+				com.WritePlaceholder()
+				com.Add(updatedAt1)
+			}
+			if len(c.updatedAt.Values) > 1 && c.updatedAt.Values[1] != nil {
+				updatedAt2, err := ptypes.Timestamp(c.updatedAt.Values[1])
+				if err != nil {
+					return err
+				}
+				if com.Dirty {
+					com.WriteString(" AND ")
+				}
+				com.Dirty = true
+
+				com.WriteString(tableCommentColumnUpdatedAt)
+				com.WriteString(" " + c.updatedAtBounds.UpperOperator() + " ")
+				com.WritePlaceholder()
+				com.Add(updatedAt2)
+			}
+		}
+	}
+
+	return c.writeSuffix(com)
+}
+
+// writeSuffix writes ORDER BY, OFFSET, LIMIT and locking clauses that follow the condition.
+func (c *commentCriteria) writeSuffix(com *pqtgo.Composer) (err error) {
+SortLoop:
+	for cn := range c.sort {
+		for _, tcn := range tableCommentColumns {
+			if cn == tcn {
+				continue SortLoop
+			}
+		}
+		return fmt.Errorf("pqt: unknown sort column %q", cn)
+	}
+
+	// Columns are ordered by name, map does not preserve the order and random one would make results unstable.
+	// Sort expressions, e.g. window functions, are ordered explicitly, so they come first.
+	if keys := pqtgo.Keyset(c.sort, tableCommentColumns); len(keys) > 0 || len(c.sortExpr) > 0 {
+		orderBy, err := pqtgo.OrderBy(c.sortExpr, keys, tableCommentColumns)
+		if err != nil {
+			return err
+		}
+		com.WriteString(" ORDER BY ")
+		com.WriteString(orderBy)
+		com.WriteString(" ")
+	}
+	if c.Offset > 0 {
+		if _, err = com.WriteString(" OFFSET "); err != nil {
+			return
+		}
+		if err = com.WritePlaceholder(); err != nil {
+			return
+		}
+		if _, err = com.WriteString(" "); err != nil {
+			return
+		}
+		com.Add(c.Offset)
+	}
+	if c.Limit > 0 {
+		if _, err = com.WriteString(" LIMIT "); err != nil {
+			return
+		}
+		if err = com.WritePlaceholder(); err != nil {
+			return
+		}
+		if _, err = com.WriteString(" "); err != nil {
+			return
+		}
+		com.Add(c.Limit)
+	}
+	if c.lock != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.lock.String())
+	}
+
+	return
+}
+
+// whereClause returns condition built from the criteria, without WHERE keyword, and its arguments in order.
+// Placeholders are numbered starting at given index, so the clause can be embedded into a custom query.
+// Sort, offset, limit and lock are ignored.
+func (c *commentCriteria) whereClause(startIdx int) (string, []interface{}, error) {
+	cc := *c
+	cc.sort, cc.sortExpr, cc.Offset, cc.Limit, cc.lock = nil, nil, 0, 0, pqt.LockNone
+
+	com := pqtgo.NewComposerAt(6, startIdx)
+	if err := cc.WriteComposition("", com, pqtgo.And); err != nil {
+		return "", nil, err
+	}
+
+	return com.String(), com.Args(), nil
+}
+
+type commentPatch struct {
+	content   *ntypes.String
+	createdAt *time.Time
+	id        *ntypes.Int64
+	newsID    *ntypes.Int64
+	newsTitle *ntypes.String
+	updatedAt *time.Time
+}
+
+// validate returns pqt.ValidationError if patch sets NULL to any of NOT NULL columns.
+func (p *commentPatch) validate() error {
+	var violations []pqt.Violation
+	if p.content != nil && !p.content.Valid {
+		violations = append(violations, pqt.Violation{Column: tableCommentColumnContent, Reason: "cannot be null"})
+	}
+	if p.newsID != nil && !p.newsID.Valid {
+		violations = append(violations, pqt.Violation{Column: tableCommentColumnNewsID, Reason: "cannot be null"})
+	}
+	if p.newsTitle != nil && !p.newsTitle.Valid {
+		violations = append(violations, pqt.Violation{Column: tableCommentColumnNewsTitle, Reason: "cannot be null"})
+	}
+	if len(violations) > 0 {
+		return &pqt.ValidationError{Table: tableComment, Violations: violations}
+	}
+
+	return nil
+}
+
+// commentPage is a single page of entities returned by findPageContext method.
+// Cursors of the first and the last entity allow to request adjacent pages.
+type commentPage struct {
+	edges                    []*commentEntity
+	hasNextPage, hasPrevPage bool
+	startCursor, endCursor   string
+}
+
+// commentLateral is a single row returned by findLateralContext method.
+// Values of columns of lateral joins are keyed by alias.column.
+type commentLateral struct {
+	entity  *commentEntity
+	lateral map[string]interface{}
+}
+
+// commentBeforeInsertHook is called by insertContext, insertReturningColumnsContext, insertBatchContext, bulkInsertContext, upsertOnContext and upsertContext before the entity is stored, it can modify the entity, e.g. set columns from the context.
+// Returned error aborts the insert.
+type commentBeforeInsertHook func(ctx context.Context, e *commentEntity) error
+
+// commentAfterInsertHook is called by insertContext and insertBatchContext after the entity is stored, with values returned by the database.
+// It is not called by insertReturningColumnsContext and bulkInsertContext, they do not read back the whole entity.
+// It is not called by upsertOnContext and upsertContext either, they cannot tell inserted row from updated one.
+// Returned error is passed to the caller, but the entity is already stored, unless insert is executed within a transaction that is rolled back.
+type commentAfterInsertHook func(ctx context.Context, e *commentEntity) error
+
+// commentRepositoryBase is a data access layer of the example.comment table.
+// Optional explain hook receives execution plans of read only queries in debug mode, optional planner builds conditions out of criteria instead of them.
+// Methods called without context are bounded by timeout, if it is set.
+type commentRepositoryBase struct {
+	table        string
+	columns      []string
+	db           pqtgo.Querier
+	dbg          bool
+	log          log.Logger
+	bulkSize     int
+	explain      pqt.ExplainHook
+	planner      pqt.Planner
+	beforeInsert commentBeforeInsertHook
+	afterInsert  commentAfterInsertHook
+	timeout      time.Duration
+}
+
+// withTx returns copy of the repository that executes all queries within given transaction.
+func (r *commentRepositoryBase) withTx(tx *sql.Tx) *commentRepositoryBase {
+	rt := *r
+	rt.db = tx
+
+	return &rt
+}
+
+// explainQuery runs given query using EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) and passes the plan to the explain hook.
+// As the query is executed, it is called in debug mode by read only methods only.
+func (r *commentRepositoryBase) explainQuery(ctx context.Context, query string, args []interface{}) error {
+	var plan []byte
+	if err := r.db.QueryRowContext(ctx, "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+query, args...).Scan(&plan); err != nil {
+		return err
+	}
+	r.explain(json.RawMessage(plan), query, args)
+
+	return nil
+}
+
+// plan returns condition of given criteria and its arguments, built by the planner if it is set.
+func (r *commentRepositoryBase) plan(c *commentCriteria) (string, []interface{}, error) {
+	if r.planner != nil {
+		return r.planner.Plan(c)
+	}
+	return c.whereClause(1)
+}
+
+// defaultContext returns context used by methods called without one, it is bounded by the timeout of the repository, if it is set.
+// Context given explicitly by the caller is used as it is.
+func (r *commentRepositoryBase) defaultContext() (context.Context, context.CancelFunc) {
+	if r.timeout > 0 {
+		return context.WithTimeout(context.Background(), r.timeout)
+	}
+	return context.Background(), func() {}
+}
+
+// scanCommentRows reads all rows into entities, each row has to consist of columns listed in tableCommentColumns, in the same order.
+func scanCommentRows(rows *sql.Rows) ([]*commentEntity, error) {
+	var (
+		entities []*commentEntity
+		err      error
+	)
+	for rows.Next() {
+		var ent commentEntity
+		err = rows.Scan(
+			&ent.content,
+			&ent.createdAt,
+			&ent.id,
+			&ent.newsID,
+			&ent.newsTitle,
+			&ent.updatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, &ent)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return entities, nil
+}
+
+// scanCommentRow reads single row into entity, the row has to consist of columns listed in tableCommentColumns, in the same order.
+// sql.ErrNoRows is returned if the query selected no rows.
+func scanCommentRow(row *sql.Row) (*commentEntity, error) {
+	var ent commentEntity
+	err := row.Scan(
+		&ent.content,
+		&ent.createdAt,
+		&ent.id,
+		&ent.newsID,
+		&ent.newsTitle,
+		&ent.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+
+// countQuery returns query and arguments used by countContext to count entities that match given criteria, without executing it.
+func (r *commentRepositoryBase) countQuery(c *commentCriteria) (string, []interface{}, error) {
+
+	buf := bytes.NewBufferString("SELECT ")
+	if c.countDistinct != "" {
+		var known bool
+		for _, cn := range tableCommentColumns {
+			if cn == c.countDistinct {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return "", nil, fmt.Errorf("comment count failure, unknown column: %s", c.countDistinct)
+		}
+		buf.WriteString("COUNT(DISTINCT " + c.countDistinct + ")")
+	} else {
+		buf.WriteString("COUNT(*)")
+	}
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+
+	// The same condition as used by find, but without sort, offset and limit that make no sense for an aggregate.
+	where, args, err := r.plan(c)
+	if err != nil {
+		return "", nil, err
+	}
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+
+	return buf.String(), args, nil
+}
+
+// countContext returns number of entities that match given criteria. Sort, offset, limit and lock are ignored.
+func (r *commentRepositoryBase) countContext(ctx context.Context, c *commentCriteria) (int64, error) {
+	query, args, err := r.countQuery(c)
+	if err != nil {
+		return 0, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Count"); err != nil {
+			return 0, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+func (r *commentRepositoryBase) count(c *commentCriteria) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.countContext(ctx, c)
+}
+
+// countDistinctContext returns number of distinct values of given column among entities that match given criteria.
+func (r *commentRepositoryBase) countDistinctContext(ctx context.Context, cn string, c *commentCriteria) (int64, error) {
+	cc := *c
+	cc.countDistinct = cn
+
+	return r.countContext(ctx, &cc)
+}
+func (r *commentRepositoryBase) countDistinct(cn string, c *commentCriteria) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.countDistinctContext(ctx, cn, c)
+}
+
+// existsContext returns true if at least one entity matches given criteria, database stops at the first matching row.
+// Sort, offset and limit are ignored.
+func (r *commentRepositoryBase) existsContext(ctx context.Context, c *commentCriteria) (bool, error) {
+	buf := bytes.NewBufferString("SELECT EXISTS (SELECT 1 FROM ")
+	buf.WriteString(r.table)
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return false, err
+	}
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+	buf.WriteString(")")
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Exists"); err != nil {
+			return false, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, buf.String(), args...).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+func (r *commentRepositoryBase) exists(c *commentCriteria) (bool, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.existsContext(ctx, c)
+}
+
+// aggregateContext computes given aggregate function of the column among entities that match given criteria, grouped by values of groupBy column.
+// Column can be empty for pqt.AggregateCount, which then counts entities. If groupBy is empty, single result with nil group is returned.
+// Groups are ordered by their values. Sort, offset, limit and lock are ignored.
+// Unknown functions and columns are rejected, as well as SUM and AVG of non-numeric column, whose MIN and MAX are returned in Raw field.
+func (r *commentRepositoryBase) aggregateContext(ctx context.Context, c *commentCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error) {
+	if fn.String() == "" {
+		return nil, fmt.Errorf("comment aggregate failure, unknown function: %d", fn)
+	}
+	if column == "" && fn != pqt.AggregateCount {
+		return nil, fmt.Errorf("comment aggregate failure, column is required by %s", fn)
+	}
+	for _, cn := range []string{column, groupBy} {
+		if cn == "" {
+			continue
+		}
+		var known bool
+		for _, tc := range tableCommentColumns {
+			if tc == cn {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("comment aggregate failure, unknown column: %s", cn)
+		}
+	}
+	// Result of COUNT and of any function of a numeric column is a number, other values are scanned as they are.
+	numeric := fn == pqt.AggregateCount
+	switch column {
+	case tableCommentColumnID, tableCommentColumnNewsID:
+		numeric = true
+	}
+	if !numeric && (fn == pqt.AggregateSum || fn == pqt.AggregateAvg) {
+		return nil, fmt.Errorf("comment aggregate failure, %s requires numeric column, got: %s", fn, column)
+	}
+
+	expr := "*"
+	if column != "" {
+		expr = column
+	}
+	buf := bytes.NewBufferString("SELECT ")
+	if groupBy != "" {
+		buf.WriteString(groupBy)
+		buf.WriteString(", ")
+	}
+	buf.WriteString(fn.String() + "(" + expr + ")")
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return nil, err
+	}
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+	if groupBy != "" {
+		buf.WriteString(" GROUP BY " + groupBy + " ORDER BY " + groupBy)
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Aggregate"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []pqt.AggregateResult
+	for rows.Next() {
+		var (
+			group, raw interface{}
+			// Aggregate of a group that has no values, e.g. SUM of nulls, is null.
+			value sql.NullFloat64
+		)
+		dest := []interface{}{&value}
+		if !numeric {
+			dest[0] = &raw
+		}
+		if groupBy != "" {
+			dest = append([]interface{}{&group}, dest...)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		res = append(res, pqt.AggregateResult{Group: group, Value: value.Float64, Raw: raw})
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return res, nil
+}
+func (r *commentRepositoryBase) aggregate(c *commentCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.aggregateContext(ctx, c, fn, column, groupBy)
+}
+
+// findQuery returns query and arguments used by findContext to retrieve entities that match given criteria, without executing it.
+func (r *commentRepositoryBase) findQuery(c *commentCriteria) (string, []interface{}, error) {
+	where, args, err := r.plan(c)
+	if err != nil {
+		return "", nil, err
+	}
+
+	buf := bytes.NewBufferString("SELECT ")
+	buf.WriteString(strings.Join(r.columns, ", "))
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+
+	com := pqtgo.NewComposerAt(2, len(args)+1)
+	if err := c.writeSuffix(com); err != nil {
+		return "", nil, err
+	}
+	buf.ReadFrom(com)
+
+	return buf.String(), append(args, com.Args()...), nil
+}
+
+// findContext returns entities that match given criteria.
+func (r *commentRepositoryBase) findContext(ctx context.Context, c *commentCriteria) ([]*commentEntity, error) {
+
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Find"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanCommentRows(rows)
+}
+func (r *commentRepositoryBase) find(c *commentCriteria) ([]*commentEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findContext(ctx, c)
+}
+
+// findIterContext returns iterator over entities that match given criteria.
+// Caller is responsible for closing the iterator, also if the loop exits early.
+func (r *commentRepositoryBase) findIterContext(ctx context.Context, c *commentCriteria) (*commentIterator, error) {
+
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Find"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commentIterator{rows: rows}, nil
+}
+func (r *commentRepositoryBase) findIter(c *commentCriteria) (*commentIterator, error) {
+	ctx, cancel := r.defaultContext()
+	it, err := r.findIterContext(ctx, c)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	it.cancel = cancel
+
+	return it, nil
+}
+
+// findPageContext returns page of entities that match given criteria using cursor based (keyset) pagination.
+// Rows are ordered by sort columns of the criteria, followed by primary key, so cursors remain valid if rows are inserted or deleted.
+// Limit of the criteria defines size of the page, offset is not supported.
+// Values held by cursors are decoded into properties of the entity, so they keep types of their columns.
+func (r *commentRepositoryBase) findPageContext(ctx context.Context, c *commentCriteria, page pqt.CursorPage) (*commentPage, error) {
+	if c.Offset > 0 {
+		return nil, errors.New("comment find page failure, offset is not supported")
+	}
+	if len(c.sortExpr) > 0 {
+		return nil, errors.New("comment find page failure, sort expressions are not supported")
+	}
+	if c.Limit <= 0 {
+		return nil, errors.New("comment find page failure, limit has to be positive")
+	}
+	if page.After != "" && page.Before != "" {
+		return nil, errors.New("comment find page failure, after and before cursors cannot be used together")
+	}
+
+SortLoop:
+	for cn := range c.sort {
+		for _, tcn := range tableCommentColumns {
+			if cn == tcn {
+				continue SortLoop
+			}
+		}
+		return nil, fmt.Errorf("pqt: unknown sort column %q", cn)
+	}
+
+	keys := pqtgo.Keyset(c.sort, tableCommentColumns)
+	if len(keys) == 0 {
+		return nil, errors.New("comment find page failure, sort or primary key is required")
+	}
+	cursor := func(ent *commentEntity) (string, error) {
+		values := make(map[string]interface{}, len(keys))
+		for _, k := range keys {
+			values[k.Name], _ = ent.value(k.Name)
+		}
+		return pqt.EncodeCursor(values)
+	}
+
+	// Previous page is fetched by traversing rows in opposite order, starting from the cursor.
+	from, backward := page.After, page.Before != ""
+	order := keys
+	if backward {
+		from, order = page.Before, pqtgo.ReverseKeyset(keys)
+	}
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return nil, err
+	}
+	com := pqtgo.NewComposerAt(6, len(args)+1)
+	for _, arg := range args {
+		com.Add(arg)
+	}
+	if where != "" {
+		// Condition of the planner can consist of alternatives, keyset condition is added to all of them.
+		com.WriteString("(" + where + ")")
+		com.Dirty = true
+	}
+	buf := bytes.NewBufferString("SELECT ")
+	buf.WriteString(strings.Join(r.columns, ", "))
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+
+	if from != "" {
+		// Cursor values are decoded into properties of the entity, so they keep types of the columns.
+		var cur commentEntity
+		dest := make(map[string]interface{}, len(order))
+		for _, k := range order {
+			dest[k.Name], _ = cur.prop(k.Name)
+		}
+		if err := pqt.DecodeCursorTo(from, dest); err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, 0, len(order))
+		for _, k := range order {
+			v, _ := cur.value(k.Name)
+			values = append(values, v)
+		}
+		if com.Dirty {
+			com.WriteString(" AND ")
+		}
+		if err := pqtgo.WriteKeysetCondition(com, order, values); err != nil {
+			return nil, err
+		}
+		com.Dirty = true
+	}
+	if com.Dirty {
+		buf.WriteString(" WHERE ")
+	}
+	com.WriteString(" ORDER BY ")
+	com.WriteString(pqtgo.KeysetOrderBy(order))
+	com.WriteString(" LIMIT ")
+	if err := com.WritePlaceholder(); err != nil {
+		return nil, err
+	}
+	// One more row tells if there is another page in the direction of traversal.
+	com.Add(c.Limit + 1)
+	if c.lock != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.lock.String())
+	}
+	buf.ReadFrom(com)
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindPage"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), com.Args()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), com.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ents, err := scanCommentRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	more := int64(len(ents)) > c.Limit
+	if more {
+		ents = ents[:c.Limit]
+	}
+	if backward {
+		for i, j := 0, len(ents)-1; i < j; i, j = i+1, j-1 {
+			ents[i], ents[j] = ents[j], ents[i]
+		}
+	}
+
+	res := &commentPage{edges: ents}
+	if backward {
+		res.hasNextPage, res.hasPrevPage = true, more
+	} else {
+		res.hasNextPage, res.hasPrevPage = more, page.After != ""
+	}
+	if len(ents) > 0 {
+		if res.startCursor, err = cursor(ents[0]); err != nil {
+			return nil, err
+		}
+		if res.endCursor, err = cursor(ents[len(ents)-1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+func (r *commentRepositoryBase) findPage(c *commentCriteria, page pqt.CursorPage) (*commentPage, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findPageContext(ctx, c, page)
+}
+
+// findAndCountContext returns entities that match given criteria together with total number of matching entities, regardless of offset and limit.
+// Total is selected by the same query using COUNT(*) OVER() window function, which is evaluated before OFFSET and LIMIT are applied.
+// If the page is empty, but offset is set, total is obtained using countContext, so it is known even if the offset goes past the last entity.
+// Window functions cannot be combined with locking clause, so lock is not supported.
+func (r *commentRepositoryBase) findAndCountContext(ctx context.Context, c *commentCriteria) ([]*commentEntity, int64, error) {
+	if c.lock != pqt.LockNone {
+		return nil, 0, errors.New("comment find and count failure, lock is not supported")
+	}
+
+	rr := *r
+	rr.columns = append(r.columns[:len(r.columns):len(r.columns)], "COUNT(*) OVER()")
+	query, args, err := rr.findQuery(c)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "FindAndCount"); err != nil {
+			return nil, 0, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		entities []*commentEntity
+		total    int64
+	)
+	for rows.Next() {
+		var ent commentEntity
+		err = rows.Scan(
+			&ent.content,
+			&ent.createdAt,
+			&ent.id,
+			&ent.newsID,
+			&ent.newsTitle,
+			&ent.updatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		entities = append(entities, &ent)
+	}
+	if rows.Err() != nil {
+		return nil, 0, rows.Err()
+	}
+	if len(entities) == 0 && c.Offset > 0 {
+		if total, err = r.countContext(ctx, c); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return entities, total, nil
+}
+func (r *commentRepositoryBase) findAndCount(c *commentCriteria) ([]*commentEntity, int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findAndCountContext(ctx, c)
+}
+
+// findOneContext returns single entity that matches given criteria.
+// pqt.ErrNotFound is returned if there is none, pqt.ErrMultipleRows if there is more than one.
+func (r *commentRepositoryBase) findOneContext(ctx context.Context, c *commentCriteria) (*commentEntity, error) {
+	cc := *c
+	cc.Limit = 2
+
+	ents, err := r.findContext(ctx, &cc)
+	if err != nil {
+		return nil, err
+	}
+	switch len(ents) {
+	case 0:
+		return nil, pqt.ErrNotFound
+	case 1:
+		return ents[0], nil
+	default:
+		return nil, pqt.ErrMultipleRows
+	}
+}
+func (r *commentRepositoryBase) findOne(c *commentCriteria) (*commentEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findOneContext(ctx, c)
+}
+
+// findWithNewsByTitleContext works like findContext, but also loads related news entity using LEFT JOIN.
+func (r *commentRepositoryBase) findWithNewsByTitleContext(ctx context.Context, c *commentCriteria) ([]*commentEntity, error) {
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBufferString("SELECT t0.content, t0.created_at, t0.id, t0.news_id, t0.news_title, t0.updated_at, t1.content, t1.continue, t1.created_at, t1.id, t1.lead, t1.status, t1.tags, t1.title, t1.updated_at FROM (")
+	buf.WriteString(query)
+	buf.WriteString(") AS t0 LEFT JOIN example.news AS t1 ON t0.news_title = t1.title")
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindWithNewsByTitle"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entities []*commentEntity
+	for rows.Next() {
+		var ent commentEntity
+		var (
+			newsByTitleContent   *string
+			newsByTitleContinue  *bool
+			newsByTitleCreatedAt *time.Time
+			newsByTitleID        *int64
+			newsByTitleLead      **ntypes.String
+			newsByTitleStatus    **newsStatus
+			newsByTitleTags      *pqt.ArrayString
+			newsByTitleTitle     *string
+			newsByTitleUpdatedAt **time.Time
+		)
+		err = rows.Scan(
+			&ent.content,
+			&ent.createdAt,
+			&ent.id,
+			&ent.newsID,
+			&ent.newsTitle,
+			&ent.updatedAt,
+			&newsByTitleContent,
+			&newsByTitleContinue,
+			&newsByTitleCreatedAt,
+			&newsByTitleID,
+			&newsByTitleLead,
+			&newsByTitleStatus,
+			&newsByTitleTags,
+			&newsByTitleTitle,
+			&newsByTitleUpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if newsByTitleTitle != nil {
+			ent.newsByTitle = &newsEntity{}
+			if newsByTitleContent != nil {
+				ent.newsByTitle.content = *newsByTitleContent
+			}
+			if newsByTitleContinue != nil {
+				ent.newsByTitle.cont = *newsByTitleContinue
+			}
+			if newsByTitleCreatedAt != nil {
+				ent.newsByTitle.createdAt = *newsByTitleCreatedAt
+			}
+			if newsByTitleID != nil {
+				ent.newsByTitle.id = *newsByTitleID
+			}
+			if newsByTitleLead != nil {
+				ent.newsByTitle.lead = *newsByTitleLead
+			}
+			if newsByTitleStatus != nil {
+				ent.newsByTitle.status = *newsByTitleStatus
+			}
+			if newsByTitleTags != nil {
+				ent.newsByTitle.tags = *newsByTitleTags
+			}
+			if newsByTitleTitle != nil {
+				ent.newsByTitle.title = *newsByTitleTitle
+			}
+			if newsByTitleUpdatedAt != nil {
+				ent.newsByTitle.updatedAt = *newsByTitleUpdatedAt
+			}
+		}
+
+		entities = append(entities, &ent)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entities, nil
+}
+func (r *commentRepositoryBase) findWithNewsByTitle(c *commentCriteria) ([]*commentEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findWithNewsByTitleContext(ctx, c)
+}
+
+// findWithNewsByIDContext works like findContext, but also loads related news entity using LEFT JOIN.
+func (r *commentRepositoryBase) findWithNewsByIDContext(ctx context.Context, c *commentCriteria) ([]*commentEntity, error) {
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBufferString("SELECT t0.content, t0.created_at, t0.id, t0.news_id, t0.news_title, t0.updated_at, t1.content, t1.continue, t1.created_at, t1.id, t1.lead, t1.status, t1.tags, t1.title, t1.updated_at FROM (")
+	buf.WriteString(query)
+	buf.WriteString(") AS t0 LEFT JOIN example.news AS t1 ON t0.news_id = t1.id")
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindWithNewsByID"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entities []*commentEntity
+	for rows.Next() {
+		var ent commentEntity
+		var (
+			newsByIDContent   *string
+			newsByIDContinue  *bool
+			newsByIDCreatedAt *time.Time
+			newsByIDID        *int64
+			newsByIDLead      **ntypes.String
+			newsByIDStatus    **newsStatus
+			newsByIDTags      *pqt.ArrayString
+			newsByIDTitle     *string
+			newsByIDUpdatedAt **time.Time
+		)
+		err = rows.Scan(
+			&ent.content,
+			&ent.createdAt,
+			&ent.id,
+			&ent.newsID,
+			&ent.newsTitle,
+			&ent.updatedAt,
+			&newsByIDContent,
+			&newsByIDContinue,
+			&newsByIDCreatedAt,
+			&newsByIDID,
+			&newsByIDLead,
+			&newsByIDStatus,
+			&newsByIDTags,
+			&newsByIDTitle,
+			&newsByIDUpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if newsByIDID != nil {
+			ent.newsByID = &newsEntity{}
+			if newsByIDContent != nil {
+				ent.newsByID.content = *newsByIDContent
+			}
+			if newsByIDContinue != nil {
+				ent.newsByID.cont = *newsByIDContinue
+			}
+			if newsByIDCreatedAt != nil {
+				ent.newsByID.createdAt = *newsByIDCreatedAt
+			}
+			if newsByIDID != nil {
+				ent.newsByID.id = *newsByIDID
+			}
+			if newsByIDLead != nil {
+				ent.newsByID.lead = *newsByIDLead
+			}
+			if newsByIDStatus != nil {
+				ent.newsByID.status = *newsByIDStatus
+			}
+			if newsByIDTags != nil {
+				ent.newsByID.tags = *newsByIDTags
+			}
+			if newsByIDTitle != nil {
+				ent.newsByID.title = *newsByIDTitle
+			}
+			if newsByIDUpdatedAt != nil {
+				ent.newsByID.updatedAt = *newsByIDUpdatedAt
+			}
+		}
+
+		entities = append(entities, &ent)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entities, nil
+}
+func (r *commentRepositoryBase) findWithNewsByID(c *commentCriteria) ([]*commentEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findWithNewsByIDContext(ctx, c)
+}
+
+// findLateralContext works like findContext, but also joins lateral sub-queries of the criteria using JOIN LATERAL (...) AS alias ON TRUE.
+// Each row of the result holds entity and values of columns of lateral joins, entity is repeated if sub-query returns multiple rows.
+func (r *commentRepositoryBase) findLateralContext(ctx context.Context, c *commentCriteria) ([]*commentLateral, error) {
+	lateral, err := pqtgo.LateralColumns(c.lateral)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBufferString("SELECT t0.content, t0.created_at, t0.id, t0.news_id, t0.news_title, t0.updated_at")
+	for _, cn := range lateral {
+		buf.WriteString(", ")
+		buf.WriteString(cn)
+	}
+	buf.WriteString(" FROM (")
+	buf.WriteString(query)
+	buf.WriteString(") AS t0")
+
+	// Placeholders of lateral sub-queries follow those of the criteria.
+	com := pqtgo.NewComposerAt(int64(len(c.lateral)), len(args)+1)
+	if err := pqtgo.WriteLateralJoins(com, c.lateral); err != nil {
+		return nil, err
+	}
+	buf.ReadFrom(com)
+	args = append(args, com.Args()...)
+
+	// Joins do not preserve order of the sub-query, so it is repeated by the outer query.
+	// Columns are qualified, lateral joins can expose columns of the same names.
+	if keys := pqtgo.Keyset(c.sort, tableCommentColumns); len(keys) > 0 || len(c.sortExpr) > 0 {
+		orderBy, err := pqtgo.QualifiedOrderBy("t0", c.sortExpr, keys, tableCommentColumns)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(" ORDER BY ")
+		buf.WriteString(orderBy)
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindLateral"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*commentLateral
+	for rows.Next() {
+		var ent commentEntity
+		values := make([]interface{}, len(lateral))
+		dest := []interface{}{
+			&ent.content,
+			&ent.createdAt,
+			&ent.id,
+			&ent.newsID,
+			&ent.newsTitle,
+			&ent.updatedAt,
+		}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := &commentLateral{entity: &ent, lateral: make(map[string]interface{}, len(lateral))}
+		for i, cn := range lateral {
+			row.lateral[cn] = values[i]
+		}
+		res = append(res, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+func (r *commentRepositoryBase) findLateral(c *commentCriteria) ([]*commentLateral, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findLateralContext(ctx, c)
+}
+
+// insertQuery returns query and arguments used by insertContext to save given entity, without executing it.
+func (r *commentRepositoryBase) insertQuery(e *commentEntity) (string, []interface{}, error) {
+	if err := e.validate(); err != nil {
+		return "", nil, err
+	}
+
+	insert := pqcomp.New(0, 6)
+	insert.AddExpr(tableCommentColumnContent, "", e.content)
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tableCommentColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tableCommentColumnNewsID, "", e.newsID)
+	insert.AddExpr(tableCommentColumnNewsTitle, "", e.newsTitle)
+	insert.AddExpr(tableCommentColumnUpdatedAt, "", e.updatedAt)
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() != 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+	}
+
+	return b.String(), insert.Args(), nil
+}
+
+// insertContext saves given entity and returns it with values read back from the database, e.g. defaults.
+func (r *commentRepositoryBase) insertContext(ctx context.Context, e *commentEntity) (*commentEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := r.insertQuery(e)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Insert"); err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.content,
+		&e.createdAt,
+		&e.id,
+		&e.newsID,
+		&e.newsTitle,
+		&e.updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if r.afterInsert != nil {
+		if err := r.afterInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+func (r *commentRepositoryBase) insert(e *commentEntity) (*commentEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.insertContext(ctx, e)
+}
+
+// insertReturningColumnsContext works like insertContext, but returns new entity with only given columns populated, unknown columns are rejected before execution.
+func (r *commentRepositoryBase) insertReturningColumnsContext(ctx context.Context, e *commentEntity, cols ...string) (*commentEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(cols) == 0 {
+		return nil, errors.New("comment insert failure, no columns to return")
+	}
+	var ent commentEntity
+	props, err := ent.props(cols...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+
+	insert := pqcomp.New(0, 6)
+	insert.AddExpr(tableCommentColumnContent, "", e.content)
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tableCommentColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tableCommentColumnNewsID, "", e.newsID)
+	insert.AddExpr(tableCommentColumnNewsTitle, "", e.newsTitle)
+	insert.AddExpr(tableCommentColumnUpdatedAt, "", e.updatedAt)
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() != 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+		b.WriteString(" RETURNING " + strings.Join(cols, ", "))
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", b.String(), "function", "InsertReturningColumns"); err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.db.QueryRowContext(ctx, b.String(), insert.Args()...).Scan(props...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+func (r *commentRepositoryBase) insertReturningColumns(e *commentEntity, cols ...string) (*commentEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.insertReturningColumnsContext(ctx, e, cols...)
+}
+
+// insertBatchContext saves given entities using multi-row statements and returns them with values read back from the database.
+// Column with default value gets DEFAULT in rows that do not hold explicit value, like in insertContext.
+// All entities are validated first, so none of them is sent if any is invalid.
+func (r *commentRepositoryBase) insertBatchContext(ctx context.Context, es []*commentEntity) ([]*commentEntity, error) {
+	for _, e := range es {
+		if r.beforeInsert != nil {
+			if err := r.beforeInsert(ctx, e); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := e.validate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, chunk := range pqtgo.Chunks(len(es), 5) {
+		batch := es[chunk[0]:chunk[1]]
+		com := pqtgo.NewComposer(int64(len(batch) * 5))
+		for i, e := range batch {
+			if i != 0 {
+				com.WriteString(", ")
+			}
+			com.WriteString("(")
+			com.WritePlaceholder()
+			com.Add(e.content)
+			com.WriteString(", ")
+			if !e.createdAt.IsZero() {
+				com.WritePlaceholder()
+				com.Add(e.createdAt)
+			} else {
+				com.WriteString("DEFAULT")
+			}
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.newsID)
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.newsTitle)
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.updatedAt)
+			com.WriteString(")")
+		}
+
+		b := bytes.NewBufferString("INSERT INTO " + r.table + " (")
+		b.WriteString(tableCommentColumnContent)
+		b.WriteString(", ")
+		b.WriteString(tableCommentColumnCreatedAt)
+		b.WriteString(", ")
+		b.WriteString(tableCommentColumnNewsID)
+		b.WriteString(", ")
+		b.WriteString(tableCommentColumnNewsTitle)
+		b.WriteString(", ")
+		b.WriteString(tableCommentColumnUpdatedAt)
+		b.WriteString(") VALUES ")
+		b.ReadFrom(com)
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+
+		if r.dbg {
+			if err := r.log.Log("msg", b.String(), "function", "InsertBatch"); err != nil {
+				return nil, err
+			}
+		}
+
+		rows, err := r.db.QueryContext(ctx, b.String(), com.Args()...)
+		if err != nil {
+			return nil, err
+		}
+		returned := 0
+		for ; returned < len(batch) && rows.Next(); returned++ {
+			err = rows.Scan(
+				&batch[returned].content,
+				&batch[returned].createdAt,
+				&batch[returned].id,
+				&batch[returned].newsID,
+				&batch[returned].newsTitle,
+				&batch[returned].updatedAt,
+			)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+		}
+		if err = rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+		if len(r.columns) > 0 && returned != len(batch) {
+			return nil, fmt.Errorf("comment insert batch failure, %d rows inserted, but %d returned", len(batch), returned)
+		}
+		for _, e := range batch {
+			if r.afterInsert != nil {
+				if err := r.afterInsert(ctx, e); err != nil {
+					return nil, err
+				}
+			}
+
+		}
+	}
+
+	return es, nil
+}
+func (r *commentRepositoryBase) insertBatch(es []*commentEntity) ([]*commentEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.insertBatchContext(ctx, es)
+}
+
+// bulkInsertContext loads given entities using COPY protocol and returns number of loaded rows.
+// Serial columns and columns with default value are omitted, they are populated by the database.
+// COPY does not support RETURNING clause, so generated values (e.g. ids) are not set on given entities.
+// All entities are validated first, so none of them is sent if any is invalid.
+// Database of the repository has to implement pqtgo.TxBeginner, like *sql.DB, or pqtgo.Preparer within a transaction, like *sql.Tx.
+func (r *commentRepositoryBase) bulkInsertContext(ctx context.Context, es []*commentEntity) (int64, error) {
+	for _, e := range es {
+		if r.beforeInsert != nil {
+			if err := r.beforeInsert(ctx, e); err != nil {
+				return 0, err
+			}
+		}
+
+		if err := e.validate(); err != nil {
+			return 0, err
+		}
+	}
+	query := pq.CopyInSchema("example", "comment", tableCommentColumnContent, tableCommentColumnNewsID, tableCommentColumnNewsTitle, tableCommentColumnUpdatedAt)
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "BulkInsert"); err != nil {
+			return 0, err
+		}
+	}
+
+	return pqtgo.CopyIn(ctx, r.db, query, len(es), r.bulkSize, func(i int) []interface{} {
+		return []interface{}{
+			es[i].content,
+			es[i].newsID,
+			es[i].newsTitle,
+			es[i].updatedAt,
+		}
+	})
+}
+func (r *commentRepositoryBase) bulkInsert(es []*commentEntity) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.bulkInsertContext(ctx, es)
+}
+
+// upsertOnContext saves given entity, on conflict with given target it updates existing row using the patch, or values of the entity if patch is nil.
+// pqt.ErrUpsertIgnored is returned if conflicting row was not updated.
+func (r *commentRepositoryBase) upsertOnContext(ctx context.Context, e *commentEntity, p *commentPatch, ct pqt.UpsertConflictTarget) (*commentEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+	if p != nil {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	insert := pqcomp.New(0, 6)
+	update := insert.Compose(6)
+	insert.AddExpr(tableCommentColumnContent, "", e.content)
+
+	if !e.createdAt.IsZero() {
+		insert.AddExpr(tableCommentColumnCreatedAt, "", e.createdAt)
+	}
+
+	insert.AddExpr(tableCommentColumnNewsID, "", e.newsID)
+	insert.AddExpr(tableCommentColumnNewsTitle, "", e.newsTitle)
+	insert.AddExpr(tableCommentColumnUpdatedAt, "", e.updatedAt)
+	if p != nil && !ct.IsZero() {
+		update.AddExpr(tableCommentColumnContent, "=", p.content)
+		update.AddExpr(tableCommentColumnCreatedAt, "=", p.createdAt)
+		update.AddExpr(tableCommentColumnNewsID, "=", p.newsID)
+		update.AddExpr(tableCommentColumnNewsTitle, "=", p.newsTitle)
+		update.AddExpr(tableCommentColumnUpdatedAt, "=", p.updatedAt)
+	}
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() > 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+	}
+	var excluded []string
+	if p == nil && !ct.IsZero() {
+		insert.Reset()
+		for insert.Next() {
+			if !ct.HasColumn(insert.Key()) {
+				excluded = append(excluded, insert.Key()+" = EXCLUDED."+insert.Key())
+			}
+		}
+	}
+
+	b.WriteString(" ON CONFLICT ")
+	if !ct.IsZero() {
+		b.WriteString(ct.String())
+	}
+	switch {
+	case len(excluded) > 0:
+		b.WriteString(" DO UPDATE SET ")
+		b.WriteString(strings.Join(excluded, ", "))
+	case !ct.IsZero() && update.Len() > 0:
+		b.WriteString(" DO UPDATE SET ")
+		for update.Next() {
+			if !update.First() {
+				b.WriteString(", ")
+			}
+
+			b.WriteString(update.Key())
+			b.WriteString(" ")
+			b.WriteString(update.Oper())
+			b.WriteString(" ")
+			b.WriteString(update.PlaceHolder())
+		}
+	default:
+		b.WriteString(" DO NOTHING ")
+	}
+	if insert.Len() > 0 {
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", b.String(), "function", "Upsert"); err != nil {
+			return nil, err
+		}
+	}
+
+	err := r.db.QueryRowContext(ctx, b.String(), insert.Args()...).Scan(
+		&e.content,
+		&e.createdAt,
+		&e.id,
+		&e.newsID,
+		&e.newsTitle,
+		&e.updatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, pqt.ErrUpsertIgnored
+		}
+		return nil, err
+	}
+
+	return e, nil
+}
+func (r *commentRepositoryBase) upsertOn(e *commentEntity, p *commentPatch, ct pqt.UpsertConflictTarget) (*commentEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.upsertOnContext(ctx, e, p, ct)
+}
+
+// upsertContext is a shorthand for upsertOnContext, given columns are used to infer the conflict target.
+func (r *commentRepositoryBase) upsertContext(ctx context.Context, e *commentEntity, p *commentPatch, inf ...string) (*commentEntity, error) {
+	return r.upsertOnContext(ctx, e, p, pqt.ConflictOnColumns(inf...))
+}
+func (r *commentRepositoryBase) upsert(e *commentEntity, p *commentPatch, inf ...string) (*commentEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.upsertContext(ctx, e, p, inf...)
+}
+
+// deleteByCriteriaContext removes entities that match given criteria and returns their number.
+// Empty criteria is rejected with pqt.ErrDeleteWithoutCriteria unless full scan is allowed. Sort, offset, limit and lock are not supported.
+func (r *commentRepositoryBase) deleteByCriteriaContext(ctx context.Context, c *commentCriteria, allowFullScan bool) (int64, error) {
+	if len(c.sort) > 0 || len(c.sortExpr) > 0 || c.Offset > 0 || c.Limit > 0 || c.lock != pqt.LockNone {
+		return 0, errors.New("comment delete failure, sort, offset, limit and lock are not supported")
+	}
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return 0, err
+	}
+	buf := bytes.NewBufferString("DELETE FROM ")
+	buf.WriteString(r.table)
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	} else if !allowFullScan {
+		return 0, pqt.ErrDeleteWithoutCriteria
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "DeleteByCriteria"); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := r.db.ExecContext(ctx, buf.String(), args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+func (r *commentRepositoryBase) deleteByCriteria(c *commentCriteria, allowFullScan bool) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.deleteByCriteriaContext(ctx, c, allowFullScan)
+}
+
+// truncateContext removes all rows from the table.
+// If cascade is true, tables that have foreign key references to the table are truncated as well.
+// If restartIdentity is true, sequences owned by columns of the table are restarted.
+func (r *commentRepositoryBase) truncateContext(ctx context.Context, cascade, restartIdentity bool) error {
+	buf := bytes.NewBufferString("TRUNCATE ")
+	buf.WriteString(r.table)
+	if restartIdentity {
+		buf.WriteString(" RESTART IDENTITY")
+	}
+	if cascade {
+		buf.WriteString(" CASCADE")
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Truncate"); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.db.ExecContext(ctx, buf.String())
+	return err
+}
+func (r *commentRepositoryBase) truncate(cascade, restartIdentity bool) error {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.truncateContext(ctx, cascade, restartIdentity)
+}
+
+const (
+	tableNewsCategory                               = "example.news_category"
+	tableNewsCategoryColumnCategoryID               = "category_id"
+	tableNewsCategoryColumnNewsID                   = "news_id"
+	tableNewsCategoryConstraintPrimaryKey           = "example.news_category_news_id_category_id_pkey"
+	tableNewsCategoryConstraintNewsIDForeignKey     = "example.news_category_news_id_fkey"
+	tableNewsCategoryConstraintCategoryIDForeignKey = "example.news_category_category_id_fkey"
+)
+
+var (
+	tableNewsCategoryColumns = []string{
+		tableNewsCategoryColumnCategoryID,
+		tableNewsCategoryColumnNewsID,
+	}
+)
+
+type newsCategoryEntity struct {
+	// categoryID ...
+	categoryID int64
+	// newsID ...
+	newsID int64
+	// news ...
+	news *newsEntity
+	// category ...
+	category *categoryEntity
+}
+
+func (e *newsCategoryEntity) prop(cn string) (interface{}, bool) {
+	switch cn {
+	case tableNewsCategoryColumnCategoryID:
+		return &e.categoryID, true
+	case tableNewsCategoryColumnNewsID:
+		return &e.newsID, true
+	default:
+		return nil, false
+	}
+}
+func (e *newsCategoryEntity) props(cns ...string) ([]interface{}, error) {
+
+	res := make([]interface{}, 0, len(cns))
+	for _, cn := range cns {
+		if prop, ok := e.prop(cn); ok {
+			res = append(res, prop)
+		} else {
+			return nil, fmt.Errorf("unexpected column provided: %s", cn)
+		}
+	}
+	return res, nil
+}
+func (e *newsCategoryEntity) value(cn string) (interface{}, bool) {
+	switch cn {
+	case tableNewsCategoryColumnCategoryID:
+		return e.categoryID, true
+	case tableNewsCategoryColumnNewsID:
+		return e.newsID, true
+	default:
+		return nil, false
+	}
+}
+func (e *newsCategoryEntity) values(cns ...string) ([]interface{}, error) {
+
+	res := make([]interface{}, 0, len(cns))
+	for _, cn := range cns {
+		if value, ok := e.value(cn); ok {
+			res = append(res, value)
+		} else {
+			return nil, fmt.Errorf("unexpected column provided: %s", cn)
+		}
+	}
+	return res, nil
+}
+
+// toMap returns values of the entity keyed by column names, nullable properties are either underlying value or nil.
+func (e *newsCategoryEntity) toMap() map[string]interface{} {
+	m := make(map[string]interface{}, 2)
+	m[tableNewsCategoryColumnCategoryID] = e.categoryID
+	m[tableNewsCategoryColumnNewsID] = e.newsID
+
+	return m
+}
+
+// newsCategoryEntityFromMap returns entity built from given values keyed by column names, missing columns are left unset.
+// Error is returned if column is unknown or its value is not of the expected type, nil is accepted by nullable columns only.
+func newsCategoryEntityFromMap(m map[string]interface{}) (*newsCategoryEntity, error) {
+	e := &newsCategoryEntity{}
+	for cn, v := range m {
+		switch cn {
+		case tableNewsCategoryColumnCategoryID:
+			if tv, ok := v.(int64); ok {
+				e.categoryID = tv
+				continue
+			}
+			return nil, fmt.Errorf("newsCategory from map failure, column %s expects value of type int64, got %T", cn, v)
+		case tableNewsCategoryColumnNewsID:
+			if tv, ok := v.(int64); ok {
+				e.newsID = tv
+				continue
+			}
+			return nil, fmt.Errorf("newsCategory from map failure, column %s expects value of type int64, got %T", cn, v)
+		default:
+			return nil, fmt.Errorf("newsCategory from map failure, unexpected column provided: %s", cn)
+		}
+	}
+
+	return e, nil
+}
+
+// validate returns pqt.ValidationError if any of mandatory properties is not set.
+func (e *newsCategoryEntity) validate() error {
+	var violations []pqt.Violation
+	if len(violations) > 0 {
+		return &pqt.ValidationError{Table: tableNewsCategory, Violations: violations}
+	}
+
+	return nil
+}
+
+// newsCategoryIterator is not thread safe.
+// It has to be closed once it is no longer needed, otherwise underlying connection is not released.
+type newsCategoryIterator struct {
+	rows *sql.Rows
+	cols []string
+	// cancel releases context the rows are read within, if the iterator owns it.
+	cancel context.CancelFunc
+}
+
+var _ pqtgo.Iterator = &newsCategoryIterator{}
+
+func (i *newsCategoryIterator) Next() bool {
+	return i.rows.Next()
+}
+
+func (i *newsCategoryIterator) Close() error {
+	if i.cancel != nil {
+		defer i.cancel()
+	}
+	return i.rows.Close()
+}
+
+func (i *newsCategoryIterator) Err() error {
+	return i.rows.Err()
+}
+
+// Columns is wrapper around sql.Rows.Columns method, that also cache outpu inside iterator.
+func (i *newsCategoryIterator) Columns() ([]string, error) {
+	if i.cols == nil {
+		cols, err := i.rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		i.cols = cols
+	}
+	return i.cols, nil
+}
+
+// Ent is wrapper around newsCategory method, it allows to use the same method name regardless of the table.
+func (i *newsCategoryIterator) Ent() (*newsCategoryEntity, error) {
+	return i.NewsCategory()
+}
+
+func (i *newsCategoryIterator) NewsCategory() (*newsCategoryEntity, error) {
+	var ent newsCategoryEntity
+	cols, err := i.rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := ent.props(cols...)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.rows.Scan(props...); err != nil {
+		return nil, err
+	}
+	return &ent, nil
+}
+
+// newsCategoryCriteria selects entities that queries of the repository operate on, its zero value selects all of them.
+// Offset and Limit are translated into OFFSET and LIMIT clauses if non-zero, lock appends locking clause that is effective within a transaction only.
+// Keys of sort have to be columns of the table.
+// Expressions of sortExpr precede sort, they can refer to columns of the table and call functions listed in pqt.SortFunctions.
+type newsCategoryCriteria struct {
+	Offset, Limit int64
+	sort          map[string]bool
+	sortExpr      []pqt.SortExpr
+	countDistinct string
+	lock          pqt.LockMode
+	lateral       []pqt.LateralJoin
+	categoryID    *qtypes.Int64
+	newsID        *qtypes.Int64
+}
+
+func (c *newsCategoryCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *pqtgo.CompositionOpts) (err error) {
+
+	if err = pqtgo.WriteCompositionQueryInt64(c.categoryID, tableNewsCategoryColumnCategoryID, com, pqtgo.And); err != nil {
+		return
+	}
+
+	if err = pqtgo.WriteCompositionQueryInt64(c.newsID, tableNewsCategoryColumnNewsID, com, pqtgo.And); err != nil {
+		return
+	}
+
+	return c.writeSuffix(com)
+}
+
+// writeSuffix writes ORDER BY, OFFSET, LIMIT and locking clauses that follow the condition.
+func (c *newsCategoryCriteria) writeSuffix(com *pqtgo.Composer) (err error) {
+SortLoop:
+	for cn := range c.sort {
+		for _, tcn := range tableNewsCategoryColumns {
+			if cn == tcn {
+				continue SortLoop
+			}
+		}
+		return fmt.Errorf("pqt: unknown sort column %q", cn)
+	}
+
+	// Columns are ordered by name, map does not preserve the order and random one would make results unstable.
+	// Sort expressions, e.g. window functions, are ordered explicitly, so they come first.
+	if keys := pqtgo.Keyset(c.sort, tableNewsCategoryColumns); len(keys) > 0 || len(c.sortExpr) > 0 {
+		orderBy, err := pqtgo.OrderBy(c.sortExpr, keys, tableNewsCategoryColumns)
+		if err != nil {
+			return err
+		}
+		com.WriteString(" ORDER BY ")
+		com.WriteString(orderBy)
+		com.WriteString(" ")
+	}
+	if c.Offset > 0 {
+		if _, err = com.WriteString(" OFFSET "); err != nil {
+			return
+		}
+		if err = com.WritePlaceholder(); err != nil {
+			return
+		}
+		if _, err = com.WriteString(" "); err != nil {
+			return
+		}
+		com.Add(c.Offset)
+	}
+	if c.Limit > 0 {
+		if _, err = com.WriteString(" LIMIT "); err != nil {
+			return
+		}
+		if err = com.WritePlaceholder(); err != nil {
+			return
+		}
+		if _, err = com.WriteString(" "); err != nil {
+			return
+		}
+		com.Add(c.Limit)
+	}
+	if c.lock != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.lock.String())
+	}
+
+	return
+}
+
+// whereClause returns condition built from the criteria, without WHERE keyword, and its arguments in order.
+// Placeholders are numbered starting at given index, so the clause can be embedded into a custom query.
+// Sort, offset, limit and lock are ignored.
+func (c *newsCategoryCriteria) whereClause(startIdx int) (string, []interface{}, error) {
+	cc := *c
+	cc.sort, cc.sortExpr, cc.Offset, cc.Limit, cc.lock = nil, nil, 0, 0, pqt.LockNone
+
+	com := pqtgo.NewComposerAt(2, startIdx)
+	if err := cc.WriteComposition("", com, pqtgo.And); err != nil {
+		return "", nil, err
+	}
+
+	return com.String(), com.Args(), nil
+}
+
+type newsCategoryPatch struct {
+	categoryID *ntypes.Int64
+	newsID     *ntypes.Int64
+}
+
+// validate returns pqt.ValidationError if patch sets NULL to any of NOT NULL columns.
+func (p *newsCategoryPatch) validate() error {
+	var violations []pqt.Violation
+	if p.categoryID != nil && !p.categoryID.Valid {
+		violations = append(violations, pqt.Violation{Column: tableNewsCategoryColumnCategoryID, Reason: "cannot be null"})
+	}
+	if p.newsID != nil && !p.newsID.Valid {
+		violations = append(violations, pqt.Violation{Column: tableNewsCategoryColumnNewsID, Reason: "cannot be null"})
+	}
+	if len(violations) > 0 {
+		return &pqt.ValidationError{Table: tableNewsCategory, Violations: violations}
+	}
+
+	return nil
+}
+
+// newsCategoryPage is a single page of entities returned by findPageContext method.
+// Cursors of the first and the last entity allow to request adjacent pages.
+type newsCategoryPage struct {
+	edges                    []*newsCategoryEntity
+	hasNextPage, hasPrevPage bool
+	startCursor, endCursor   string
+}
+
+// newsCategoryLateral is a single row returned by findLateralContext method.
+// Values of columns of lateral joins are keyed by alias.column.
+type newsCategoryLateral struct {
+	entity  *newsCategoryEntity
+	lateral map[string]interface{}
+}
+
+// newsCategoryBeforeInsertHook is called by insertContext, insertReturningColumnsContext, insertBatchContext, bulkInsertContext, upsertOnContext and upsertContext before the entity is stored, it can modify the entity, e.g. set columns from the context.
+// Returned error aborts the insert.
+type newsCategoryBeforeInsertHook func(ctx context.Context, e *newsCategoryEntity) error
+
+// newsCategoryAfterInsertHook is called by insertContext and insertBatchContext after the entity is stored, with values returned by the database.
+// It is not called by insertReturningColumnsContext and bulkInsertContext, they do not read back the whole entity.
+// It is not called by upsertOnContext and upsertContext either, they cannot tell inserted row from updated one.
+// Returned error is passed to the caller, but the entity is already stored, unless insert is executed within a transaction that is rolled back.
+type newsCategoryAfterInsertHook func(ctx context.Context, e *newsCategoryEntity) error
+
+// newsCategoryBeforeUpdateHook is called by updateOneByNewsIDAndCategoryIDContext, updateOneByNewsIDAndCategoryIDReturningColumnsContext and patchOneByNewsIDAndCategoryIDContext before the entity is modified, it can modify the patch.
+// It is not called by upsertOnContext and upsertContext, they do not know the primary key in advance.
+// Returned error aborts the update.
+type newsCategoryBeforeUpdateHook func(ctx context.Context, newsID int64, categoryID int64, patch *newsCategoryPatch) error
+
+// newsCategoryAfterUpdateHook is called by updateOneByNewsIDAndCategoryIDContext after the entity is modified, with values returned by the database.
+// It is not called by updateOneByNewsIDAndCategoryIDReturningColumnsContext and patchOneByNewsIDAndCategoryIDContext, they do not read back the whole entity.
+// It is not called by upsertOnContext and upsertContext either, they cannot tell inserted row from updated one.
+// Returned error is passed to the caller, but the entity is already modified, unless update is executed within a transaction that is rolled back.
+type newsCategoryAfterUpdateHook func(ctx context.Context, e *newsCategoryEntity) error
+
+// newsCategoryBeforeDeleteHook is called by deleteOneByNewsIDAndCategoryIDContext and deleteAndReturnOneByNewsIDAndCategoryIDContext before the entity is removed.
+// It is not called by deleteByCriteriaContext.
+// Returned error aborts the delete.
+type newsCategoryBeforeDeleteHook func(ctx context.Context, newsID int64, categoryID int64) error
+
+// newsCategoryAfterDeleteHook is called by deleteOneByNewsIDAndCategoryIDContext and deleteAndReturnOneByNewsIDAndCategoryIDContext after the entity is removed, it is not called if there was no such entity.
+// It is not called by deleteByCriteriaContext.
+// Returned error is passed to the caller, but the entity is already removed, unless delete is executed within a transaction that is rolled back.
+type newsCategoryAfterDeleteHook func(ctx context.Context, newsID int64, categoryID int64) error
+
+// newsCategoryRepositoryBase is a data access layer of the example.news_category table.
+// Optional explain hook receives execution plans of read only queries in debug mode, optional planner builds conditions out of criteria instead of them.
+// Methods called without context are bounded by timeout, if it is set.
+type newsCategoryRepositoryBase struct {
+	table        string
+	columns      []string
+	db           pqtgo.Querier
+	dbg          bool
+	log          log.Logger
+	bulkSize     int
+	explain      pqt.ExplainHook
+	planner      pqt.Planner
+	beforeInsert newsCategoryBeforeInsertHook
+	afterInsert  newsCategoryAfterInsertHook
+	beforeUpdate newsCategoryBeforeUpdateHook
+	afterUpdate  newsCategoryAfterUpdateHook
+	beforeDelete newsCategoryBeforeDeleteHook
+	afterDelete  newsCategoryAfterDeleteHook
+	timeout      time.Duration
+}
+
+// withTx returns copy of the repository that executes all queries within given transaction.
+func (r *newsCategoryRepositoryBase) withTx(tx *sql.Tx) *newsCategoryRepositoryBase {
+	rt := *r
+	rt.db = tx
+
+	return &rt
+}
+
+// explainQuery runs given query using EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) and passes the plan to the explain hook.
+// As the query is executed, it is called in debug mode by read only methods only.
+func (r *newsCategoryRepositoryBase) explainQuery(ctx context.Context, query string, args []interface{}) error {
+	var plan []byte
+	if err := r.db.QueryRowContext(ctx, "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+query, args...).Scan(&plan); err != nil {
+		return err
+	}
+	r.explain(json.RawMessage(plan), query, args)
+
+	return nil
+}
+
+// plan returns condition of given criteria and its arguments, built by the planner if it is set.
+func (r *newsCategoryRepositoryBase) plan(c *newsCategoryCriteria) (string, []interface{}, error) {
+	if r.planner != nil {
+		return r.planner.Plan(c)
+	}
+	return c.whereClause(1)
+}
+
+// defaultContext returns context used by methods called without one, it is bounded by the timeout of the repository, if it is set.
+// Context given explicitly by the caller is used as it is.
+func (r *newsCategoryRepositoryBase) defaultContext() (context.Context, context.CancelFunc) {
+	if r.timeout > 0 {
+		return context.WithTimeout(context.Background(), r.timeout)
+	}
+	return context.Background(), func() {}
+}
+
+// scanNewsCategoryRows reads all rows into entities, each row has to consist of columns listed in tableNewsCategoryColumns, in the same order.
+func scanNewsCategoryRows(rows *sql.Rows) ([]*newsCategoryEntity, error) {
+	var (
+		entities []*newsCategoryEntity
+		err      error
+	)
+	for rows.Next() {
+		var ent newsCategoryEntity
+		err = rows.Scan(
+			&ent.categoryID,
+			&ent.newsID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, &ent)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return entities, nil
+}
+
+// scanNewsCategoryRow reads single row into entity, the row has to consist of columns listed in tableNewsCategoryColumns, in the same order.
+// sql.ErrNoRows is returned if the query selected no rows.
+func scanNewsCategoryRow(row *sql.Row) (*newsCategoryEntity, error) {
+	var ent newsCategoryEntity
+	err := row.Scan(
+		&ent.categoryID,
+		&ent.newsID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+
+// countQuery returns query and arguments used by countContext to count entities that match given criteria, without executing it.
+func (r *newsCategoryRepositoryBase) countQuery(c *newsCategoryCriteria) (string, []interface{}, error) {
+
+	buf := bytes.NewBufferString("SELECT ")
+	if c.countDistinct != "" {
+		var known bool
+		for _, cn := range tableNewsCategoryColumns {
+			if cn == c.countDistinct {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return "", nil, fmt.Errorf("newsCategory count failure, unknown column: %s", c.countDistinct)
+		}
+		buf.WriteString("COUNT(DISTINCT " + c.countDistinct + ")")
+	} else {
+		buf.WriteString("COUNT(*)")
+	}
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+
+	// The same condition as used by find, but without sort, offset and limit that make no sense for an aggregate.
+	where, args, err := r.plan(c)
+	if err != nil {
+		return "", nil, err
+	}
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+
+	return buf.String(), args, nil
+}
+
+// countContext returns number of entities that match given criteria. Sort, offset, limit and lock are ignored.
+func (r *newsCategoryRepositoryBase) countContext(ctx context.Context, c *newsCategoryCriteria) (int64, error) {
+	query, args, err := r.countQuery(c)
+	if err != nil {
+		return 0, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Count"); err != nil {
+			return 0, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+func (r *newsCategoryRepositoryBase) count(c *newsCategoryCriteria) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.countContext(ctx, c)
+}
+
+// countDistinctContext returns number of distinct values of given column among entities that match given criteria.
+func (r *newsCategoryRepositoryBase) countDistinctContext(ctx context.Context, cn string, c *newsCategoryCriteria) (int64, error) {
+	cc := *c
+	cc.countDistinct = cn
+
+	return r.countContext(ctx, &cc)
+}
+func (r *newsCategoryRepositoryBase) countDistinct(cn string, c *newsCategoryCriteria) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.countDistinctContext(ctx, cn, c)
+}
+
+// existsContext returns true if at least one entity matches given criteria, database stops at the first matching row.
+// Sort, offset and limit are ignored.
+func (r *newsCategoryRepositoryBase) existsContext(ctx context.Context, c *newsCategoryCriteria) (bool, error) {
+	buf := bytes.NewBufferString("SELECT EXISTS (SELECT 1 FROM ")
+	buf.WriteString(r.table)
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return false, err
+	}
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+	buf.WriteString(")")
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Exists"); err != nil {
+			return false, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, buf.String(), args...).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+func (r *newsCategoryRepositoryBase) exists(c *newsCategoryCriteria) (bool, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.existsContext(ctx, c)
+}
+
+// aggregateContext computes given aggregate function of the column among entities that match given criteria, grouped by values of groupBy column.
+// Column can be empty for pqt.AggregateCount, which then counts entities. If groupBy is empty, single result with nil group is returned.
+// Groups are ordered by their values. Sort, offset, limit and lock are ignored.
+// Unknown functions and columns are rejected, as well as SUM and AVG of non-numeric column, whose MIN and MAX are returned in Raw field.
+func (r *newsCategoryRepositoryBase) aggregateContext(ctx context.Context, c *newsCategoryCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error) {
+	if fn.String() == "" {
+		return nil, fmt.Errorf("newsCategory aggregate failure, unknown function: %d", fn)
+	}
+	if column == "" && fn != pqt.AggregateCount {
+		return nil, fmt.Errorf("newsCategory aggregate failure, column is required by %s", fn)
+	}
+	for _, cn := range []string{column, groupBy} {
+		if cn == "" {
+			continue
+		}
+		var known bool
+		for _, tc := range tableNewsCategoryColumns {
+			if tc == cn {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("newsCategory aggregate failure, unknown column: %s", cn)
+		}
+	}
+	// Result of COUNT and of any function of a numeric column is a number, other values are scanned as they are.
+	numeric := fn == pqt.AggregateCount
+	switch column {
+	case tableNewsCategoryColumnCategoryID, tableNewsCategoryColumnNewsID:
+		numeric = true
+	}
+	if !numeric && (fn == pqt.AggregateSum || fn == pqt.AggregateAvg) {
+		return nil, fmt.Errorf("newsCategory aggregate failure, %s requires numeric column, got: %s", fn, column)
+	}
+
+	expr := "*"
+	if column != "" {
+		expr = column
+	}
+	buf := bytes.NewBufferString("SELECT ")
+	if groupBy != "" {
+		buf.WriteString(groupBy)
+		buf.WriteString(", ")
+	}
+	buf.WriteString(fn.String() + "(" + expr + ")")
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return nil, err
+	}
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+	if groupBy != "" {
+		buf.WriteString(" GROUP BY " + groupBy + " ORDER BY " + groupBy)
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Aggregate"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []pqt.AggregateResult
+	for rows.Next() {
+		var (
+			group, raw interface{}
+			// Aggregate of a group that has no values, e.g. SUM of nulls, is null.
+			value sql.NullFloat64
+		)
+		dest := []interface{}{&value}
+		if !numeric {
+			dest[0] = &raw
+		}
+		if groupBy != "" {
+			dest = append([]interface{}{&group}, dest...)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		res = append(res, pqt.AggregateResult{Group: group, Value: value.Float64, Raw: raw})
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return res, nil
+}
+func (r *newsCategoryRepositoryBase) aggregate(c *newsCategoryCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.aggregateContext(ctx, c, fn, column, groupBy)
+}
+
+// findQuery returns query and arguments used by findContext to retrieve entities that match given criteria, without executing it.
+func (r *newsCategoryRepositoryBase) findQuery(c *newsCategoryCriteria) (string, []interface{}, error) {
+	where, args, err := r.plan(c)
+	if err != nil {
+		return "", nil, err
+	}
+
+	buf := bytes.NewBufferString("SELECT ")
+	buf.WriteString(strings.Join(r.columns, ", "))
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+
+	com := pqtgo.NewComposerAt(2, len(args)+1)
+	if err := c.writeSuffix(com); err != nil {
+		return "", nil, err
+	}
+	buf.ReadFrom(com)
+
+	return buf.String(), append(args, com.Args()...), nil
+}
+
+// findContext returns entities that match given criteria.
+func (r *newsCategoryRepositoryBase) findContext(ctx context.Context, c *newsCategoryCriteria) ([]*newsCategoryEntity, error) {
+
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Find"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanNewsCategoryRows(rows)
+}
+func (r *newsCategoryRepositoryBase) find(c *newsCategoryCriteria) ([]*newsCategoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findContext(ctx, c)
+}
+
+// findIterContext returns iterator over entities that match given criteria.
+// Caller is responsible for closing the iterator, also if the loop exits early.
+func (r *newsCategoryRepositoryBase) findIterContext(ctx context.Context, c *newsCategoryCriteria) (*newsCategoryIterator, error) {
+
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Find"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &newsCategoryIterator{rows: rows}, nil
+}
+func (r *newsCategoryRepositoryBase) findIter(c *newsCategoryCriteria) (*newsCategoryIterator, error) {
+	ctx, cancel := r.defaultContext()
+	it, err := r.findIterContext(ctx, c)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	it.cancel = cancel
+
+	return it, nil
+}
+
+// findPageContext returns page of entities that match given criteria using cursor based (keyset) pagination.
+// Rows are ordered by sort columns of the criteria, followed by primary key, so cursors remain valid if rows are inserted or deleted.
+// Limit of the criteria defines size of the page, offset is not supported.
+// Values held by cursors are decoded into properties of the entity, so they keep types of their columns.
+func (r *newsCategoryRepositoryBase) findPageContext(ctx context.Context, c *newsCategoryCriteria, page pqt.CursorPage) (*newsCategoryPage, error) {
+	if c.Offset > 0 {
+		return nil, errors.New("newsCategory find page failure, offset is not supported")
+	}
+	if len(c.sortExpr) > 0 {
+		return nil, errors.New("newsCategory find page failure, sort expressions are not supported")
+	}
+	if c.Limit <= 0 {
+		return nil, errors.New("newsCategory find page failure, limit has to be positive")
+	}
+	if page.After != "" && page.Before != "" {
+		return nil, errors.New("newsCategory find page failure, after and before cursors cannot be used together")
+	}
+
+SortLoop:
+	for cn := range c.sort {
+		for _, tcn := range tableNewsCategoryColumns {
+			if cn == tcn {
+				continue SortLoop
+			}
+		}
+		return nil, fmt.Errorf("pqt: unknown sort column %q", cn)
+	}
+
+	keys := pqtgo.Keyset(c.sort, tableNewsCategoryColumns, tableNewsCategoryColumnNewsID, tableNewsCategoryColumnCategoryID)
+	if len(keys) == 0 {
+		return nil, errors.New("newsCategory find page failure, sort or primary key is required")
+	}
+	cursor := func(ent *newsCategoryEntity) (string, error) {
+		values := make(map[string]interface{}, len(keys))
+		for _, k := range keys {
+			values[k.Name], _ = ent.value(k.Name)
+		}
+		return pqt.EncodeCursor(values)
+	}
+
+	// Previous page is fetched by traversing rows in opposite order, starting from the cursor.
+	from, backward := page.After, page.Before != ""
+	order := keys
+	if backward {
+		from, order = page.Before, pqtgo.ReverseKeyset(keys)
+	}
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return nil, err
+	}
+	com := pqtgo.NewComposerAt(2, len(args)+1)
+	for _, arg := range args {
+		com.Add(arg)
+	}
+	if where != "" {
+		// Condition of the planner can consist of alternatives, keyset condition is added to all of them.
+		com.WriteString("(" + where + ")")
+		com.Dirty = true
+	}
+	buf := bytes.NewBufferString("SELECT ")
+	buf.WriteString(strings.Join(r.columns, ", "))
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+
+	if from != "" {
+		// Cursor values are decoded into properties of the entity, so they keep types of the columns.
+		var cur newsCategoryEntity
+		dest := make(map[string]interface{}, len(order))
+		for _, k := range order {
+			dest[k.Name], _ = cur.prop(k.Name)
+		}
+		if err := pqt.DecodeCursorTo(from, dest); err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, 0, len(order))
+		for _, k := range order {
+			v, _ := cur.value(k.Name)
+			values = append(values, v)
+		}
+		if com.Dirty {
+			com.WriteString(" AND ")
+		}
+		if err := pqtgo.WriteKeysetCondition(com, order, values); err != nil {
+			return nil, err
+		}
+		com.Dirty = true
+	}
+	if com.Dirty {
+		buf.WriteString(" WHERE ")
+	}
+	com.WriteString(" ORDER BY ")
+	com.WriteString(pqtgo.KeysetOrderBy(order))
+	com.WriteString(" LIMIT ")
+	if err := com.WritePlaceholder(); err != nil {
+		return nil, err
+	}
+	// One more row tells if there is another page in the direction of traversal.
+	com.Add(c.Limit + 1)
+	if c.lock != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.lock.String())
+	}
+	buf.ReadFrom(com)
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindPage"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), com.Args()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), com.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ents, err := scanNewsCategoryRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	more := int64(len(ents)) > c.Limit
+	if more {
+		ents = ents[:c.Limit]
+	}
+	if backward {
+		for i, j := 0, len(ents)-1; i < j; i, j = i+1, j-1 {
+			ents[i], ents[j] = ents[j], ents[i]
+		}
+	}
+
+	res := &newsCategoryPage{edges: ents}
+	if backward {
+		res.hasNextPage, res.hasPrevPage = true, more
+	} else {
+		res.hasNextPage, res.hasPrevPage = more, page.After != ""
+	}
+	if len(ents) > 0 {
+		if res.startCursor, err = cursor(ents[0]); err != nil {
+			return nil, err
+		}
+		if res.endCursor, err = cursor(ents[len(ents)-1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+func (r *newsCategoryRepositoryBase) findPage(c *newsCategoryCriteria, page pqt.CursorPage) (*newsCategoryPage, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findPageContext(ctx, c, page)
+}
+
+// findAndCountContext returns entities that match given criteria together with total number of matching entities, regardless of offset and limit.
+// Total is selected by the same query using COUNT(*) OVER() window function, which is evaluated before OFFSET and LIMIT are applied.
+// If the page is empty, but offset is set, total is obtained using countContext, so it is known even if the offset goes past the last entity.
+// Window functions cannot be combined with locking clause, so lock is not supported.
+func (r *newsCategoryRepositoryBase) findAndCountContext(ctx context.Context, c *newsCategoryCriteria) ([]*newsCategoryEntity, int64, error) {
+	if c.lock != pqt.LockNone {
+		return nil, 0, errors.New("newsCategory find and count failure, lock is not supported")
+	}
+
+	rr := *r
+	rr.columns = append(r.columns[:len(r.columns):len(r.columns)], "COUNT(*) OVER()")
+	query, args, err := rr.findQuery(c)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "FindAndCount"); err != nil {
+			return nil, 0, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, query, args); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		entities []*newsCategoryEntity
+		total    int64
+	)
+	for rows.Next() {
+		var ent newsCategoryEntity
+		err = rows.Scan(
+			&ent.categoryID,
+			&ent.newsID,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		entities = append(entities, &ent)
+	}
+	if rows.Err() != nil {
+		return nil, 0, rows.Err()
+	}
+	if len(entities) == 0 && c.Offset > 0 {
+		if total, err = r.countContext(ctx, c); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return entities, total, nil
+}
+func (r *newsCategoryRepositoryBase) findAndCount(c *newsCategoryCriteria) ([]*newsCategoryEntity, int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findAndCountContext(ctx, c)
+}
+
+// findOneContext returns single entity that matches given criteria.
+// pqt.ErrNotFound is returned if there is none, pqt.ErrMultipleRows if there is more than one.
+func (r *newsCategoryRepositoryBase) findOneContext(ctx context.Context, c *newsCategoryCriteria) (*newsCategoryEntity, error) {
+	cc := *c
+	cc.Limit = 2
+
+	ents, err := r.findContext(ctx, &cc)
+	if err != nil {
+		return nil, err
+	}
+	switch len(ents) {
+	case 0:
+		return nil, pqt.ErrNotFound
+	case 1:
+		return ents[0], nil
+	default:
+		return nil, pqt.ErrMultipleRows
+	}
+}
+func (r *newsCategoryRepositoryBase) findOne(c *newsCategoryCriteria) (*newsCategoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findOneContext(ctx, c)
+}
+
+// findWithNewsContext works like findContext, but also loads related news entity using LEFT JOIN.
+func (r *newsCategoryRepositoryBase) findWithNewsContext(ctx context.Context, c *newsCategoryCriteria) ([]*newsCategoryEntity, error) {
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBufferString("SELECT t0.category_id, t0.news_id, t1.content, t1.continue, t1.created_at, t1.id, t1.lead, t1.status, t1.tags, t1.title, t1.updated_at FROM (")
+	buf.WriteString(query)
+	buf.WriteString(") AS t0 LEFT JOIN example.news AS t1 ON t0.news_id = t1.id")
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindWithNews"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entities []*newsCategoryEntity
+	for rows.Next() {
+		var ent newsCategoryEntity
+		var (
+			newsContent   *string
+			newsContinue  *bool
+			newsCreatedAt *time.Time
+			newsID        *int64
+			newsLead      **ntypes.String
+			newsStatus    **newsStatus
+			newsTags      *pqt.ArrayString
+			newsTitle     *string
+			newsUpdatedAt **time.Time
+		)
+		err = rows.Scan(
+			&ent.categoryID,
+			&ent.newsID,
+			&newsContent,
+			&newsContinue,
+			&newsCreatedAt,
+			&newsID,
+			&newsLead,
+			&newsStatus,
+			&newsTags,
+			&newsTitle,
+			&newsUpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if newsID != nil {
+			ent.news = &newsEntity{}
+			if newsContent != nil {
+				ent.news.content = *newsContent
+			}
+			if newsContinue != nil {
+				ent.news.cont = *newsContinue
+			}
+			if newsCreatedAt != nil {
+				ent.news.createdAt = *newsCreatedAt
+			}
+			if newsID != nil {
+				ent.news.id = *newsID
+			}
+			if newsLead != nil {
+				ent.news.lead = *newsLead
+			}
+			if newsStatus != nil {
+				ent.news.status = *newsStatus
+			}
+			if newsTags != nil {
+				ent.news.tags = *newsTags
+			}
+			if newsTitle != nil {
+				ent.news.title = *newsTitle
+			}
+			if newsUpdatedAt != nil {
+				ent.news.updatedAt = *newsUpdatedAt
+			}
+		}
+
+		entities = append(entities, &ent)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entities, nil
+}
+func (r *newsCategoryRepositoryBase) findWithNews(c *newsCategoryCriteria) ([]*newsCategoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findWithNewsContext(ctx, c)
+}
+
+// findWithCategoryContext works like findContext, but also loads related category entity using LEFT JOIN.
+func (r *newsCategoryRepositoryBase) findWithCategoryContext(ctx context.Context, c *newsCategoryCriteria) ([]*newsCategoryEntity, error) {
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBufferString("SELECT t0.category_id, t0.news_id, t1.content, t1.created_at, t1.deleted_at, t1.id, t1.name, t1.parent_id, t1.updated_at FROM (")
+	buf.WriteString(query)
+	buf.WriteString(") AS t0 LEFT JOIN example.category AS t1 ON t0.category_id = t1.id")
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindWithCategory"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entities []*newsCategoryEntity
+	for rows.Next() {
+		var ent newsCategoryEntity
+		var (
+			categoryContent   *string
+			categoryCreatedAt *time.Time
+			categoryDeletedAt **time.Time
+			categoryID        *int64
+			categoryName      *string
+			categoryParentID  **ntypes.Int64
+			categoryUpdatedAt **time.Time
+		)
+		err = rows.Scan(
+			&ent.categoryID,
+			&ent.newsID,
+			&categoryContent,
+			&categoryCreatedAt,
+			&categoryDeletedAt,
+			&categoryID,
+			&categoryName,
+			&categoryParentID,
+			&categoryUpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if categoryID != nil {
+			ent.category = &categoryEntity{}
+			if categoryContent != nil {
+				ent.category.content = *categoryContent
+			}
+			if categoryCreatedAt != nil {
+				ent.category.createdAt = *categoryCreatedAt
+			}
+			if categoryDeletedAt != nil {
+				ent.category.deletedAt = *categoryDeletedAt
+			}
+			if categoryID != nil {
+				ent.category.id = *categoryID
+			}
+			if categoryName != nil {
+				ent.category.name = *categoryName
+			}
+			if categoryParentID != nil {
+				ent.category.parentID = *categoryParentID
+			}
+			if categoryUpdatedAt != nil {
+				ent.category.updatedAt = *categoryUpdatedAt
+			}
+		}
+
+		entities = append(entities, &ent)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entities, nil
+}
+func (r *newsCategoryRepositoryBase) findWithCategory(c *newsCategoryCriteria) ([]*newsCategoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findWithCategoryContext(ctx, c)
+}
+
+// findLateralContext works like findContext, but also joins lateral sub-queries of the criteria using JOIN LATERAL (...) AS alias ON TRUE.
+// Each row of the result holds entity and values of columns of lateral joins, entity is repeated if sub-query returns multiple rows.
+func (r *newsCategoryRepositoryBase) findLateralContext(ctx context.Context, c *newsCategoryCriteria) ([]*newsCategoryLateral, error) {
+	lateral, err := pqtgo.LateralColumns(c.lateral)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBufferString("SELECT t0.category_id, t0.news_id")
+	for _, cn := range lateral {
+		buf.WriteString(", ")
+		buf.WriteString(cn)
+	}
+	buf.WriteString(" FROM (")
+	buf.WriteString(query)
+	buf.WriteString(") AS t0")
+
+	// Placeholders of lateral sub-queries follow those of the criteria.
+	com := pqtgo.NewComposerAt(int64(len(c.lateral)), len(args)+1)
+	if err := pqtgo.WriteLateralJoins(com, c.lateral); err != nil {
+		return nil, err
+	}
+	buf.ReadFrom(com)
+	args = append(args, com.Args()...)
+
+	// Joins do not preserve order of the sub-query, so it is repeated by the outer query.
+	// Columns are qualified, lateral joins can expose columns of the same names.
+	if keys := pqtgo.Keyset(c.sort, tableNewsCategoryColumns); len(keys) > 0 || len(c.sortExpr) > 0 {
+		orderBy, err := pqtgo.QualifiedOrderBy("t0", c.sortExpr, keys, tableNewsCategoryColumns)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(" ORDER BY ")
+		buf.WriteString(orderBy)
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindLateral"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(ctx, buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, buf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*newsCategoryLateral
+	for rows.Next() {
+		var ent newsCategoryEntity
+		values := make([]interface{}, len(lateral))
+		dest := []interface{}{
+			&ent.categoryID,
+			&ent.newsID,
+		}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := &newsCategoryLateral{entity: &ent, lateral: make(map[string]interface{}, len(lateral))}
+		for i, cn := range lateral {
+			row.lateral[cn] = values[i]
+		}
+		res = append(res, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+func (r *newsCategoryRepositoryBase) findLateral(c *newsCategoryCriteria) ([]*newsCategoryLateral, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findLateralContext(ctx, c)
+}
+
+// findOneByNewsIDAndCategoryIDQuery returns query and arguments used by findOneByNewsIDAndCategoryIDContext to retrieve the entity, without executing it.
+func (r *newsCategoryRepositoryBase) findOneByNewsIDAndCategoryIDQuery(newsID int64, categoryID int64) (string, []interface{}, error) {
+	return `SELECT category_id, news_id FROM example.news_category WHERE news_id = $1 AND category_id = $2`, []interface{}{newsID, categoryID}, nil
+}
+
+func (r *newsCategoryRepositoryBase) findOneByNewsIDAndCategoryIDContext(ctx context.Context, newsID int64, categoryID int64) (*newsCategoryEntity, error) {
+	query, args, err := r.findOneByNewsIDAndCategoryIDQuery(newsID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "FindOneByNewsIDAndCategoryID"); err != nil {
+			return nil, err
+		}
+	}
+
+	var ent newsCategoryEntity
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&ent.categoryID,
+		&ent.newsID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+func (r *newsCategoryRepositoryBase) findOneByNewsIDAndCategoryID(newsID int64, categoryID int64) (*newsCategoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.findOneByNewsIDAndCategoryIDContext(ctx, newsID, categoryID)
+}
+
+// insertQuery returns query and arguments used by insertContext to save given entity, without executing it.
+func (r *newsCategoryRepositoryBase) insertQuery(e *newsCategoryEntity) (string, []interface{}, error) {
+	if err := e.validate(); err != nil {
+		return "", nil, err
+	}
+
+	insert := pqcomp.New(0, 2)
+	insert.AddExpr(tableNewsCategoryColumnCategoryID, "", e.categoryID)
+	insert.AddExpr(tableNewsCategoryColumnNewsID, "", e.newsID)
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() != 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+	}
+
+	return b.String(), insert.Args(), nil
+}
+
+// insertContext saves given entity and returns it with values read back from the database, e.g. defaults.
+func (r *newsCategoryRepositoryBase) insertContext(ctx context.Context, e *newsCategoryEntity) (*newsCategoryEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := r.insertQuery(e)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Insert"); err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.categoryID,
+		&e.newsID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if r.afterInsert != nil {
+		if err := r.afterInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
 
-				com.WriteString(tableCommentColumnCreatedAt)
-				com.WriteString(" < ")
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_LESS_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	return e, nil
+}
+func (r *newsCategoryRepositoryBase) insert(e *newsCategoryEntity) (*newsCategoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-				com.WriteString(tableCommentColumnCreatedAt)
-				com.WriteString(" <= ")
-				com.WritePlaceholder()
-				com.Add(c.createdAt.Value())
-			case qtypes.QueryType_IN:
-				if len(c.createdAt.Values) > 0 {
-					if com.Dirty {
-						com.WriteString(" AND ")
-					}
-					com.Dirty = true
-
-					com.WriteString(tableCommentColumnCreatedAt)
-					com.WriteString(" IN (")
-					for i, v := range c.createdAt.Values {
-						if i != 0 {
-							com.WriteString(", ")
-						}
-						com.WritePlaceholder()
-						com.Add(v)
-					}
-					com.WriteString(") ")
-				}
-			case qtypes.QueryType_BETWEEN:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	return r.insertContext(ctx, e)
+}
 
-				createdAtt2 := c.createdAt.Values[1]
-				if createdAtt2 != nil {
-					createdAt2, err := ptypes.Timestamp(createdAtt2)
-					if err != nil {
-						return err
-					}
-					com.WriteString(tableCommentColumnCreatedAt)
-					com.WriteString(" > ")
-					com.WritePlaceholder()
-					com.Add(createdAt1)
-					com.WriteString(" AND ")
-					com.WriteString(tableCommentColumnCreatedAt)
-					com.WriteString(" < ")
-					com.WritePlaceholder()
-					com.Add(createdAt2)
-				}
-			}
+// insertReturningColumnsContext works like insertContext, but returns new entity with only given columns populated, unknown columns are rejected before execution.
+func (r *newsCategoryRepositoryBase) insertReturningColumnsContext(ctx context.Context, e *newsCategoryEntity, cols ...string) (*newsCategoryEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
 		}
 	}
 
-	if err = pqtgo.WriteCompositionQueryInt64(c.id, tableCommentColumnID, com, pqtgo.And); err != nil {
-		return
+	if len(cols) == 0 {
+		return nil, errors.New("newsCategory insert failure, no columns to return")
 	}
-
-	if err = pqtgo.WriteCompositionQueryInt64(c.newsID, tableCommentColumnNewsID, com, pqtgo.And); err != nil {
-		return
+	var ent newsCategoryEntity
+	props, err := ent.props(cols...)
+	if err != nil {
+		return nil, err
 	}
 
-	if err = pqtgo.WriteCompositionQueryString(c.newsTitle, tableCommentColumnNewsTitle, com, pqtgo.And); err != nil {
-		return
+	if err := e.validate(); err != nil {
+		return nil, err
 	}
 
-	if c.updatedAt != nil && c.updatedAt.Valid {
-		updatedAtt1 := c.updatedAt.Value()
-		if updatedAtt1 != nil {
-			updatedAt1, err := ptypes.Timestamp(updatedAtt1)
-			if err != nil {
-				return err
+	insert := pqcomp.New(0, 2)
+	insert.AddExpr(tableNewsCategoryColumnCategoryID, "", e.categoryID)
+	insert.AddExpr(tableNewsCategoryColumnNewsID, "", e.newsID)
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() != 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
 			}
-			switch c.updatedAt.Type {
-			case qtypes.QueryType_NULL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
 
-				com.WriteString(tableCommentColumnUpdatedAt)
-				if c.updatedAt.Negation {
-					com.WriteString(" IS NOT NULL ")
-				} else {
-					com.WriteString(" IS NULL ")
-				}
-			case qtypes.QueryType_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
 
-				com.WriteString(tableCommentColumnUpdatedAt)
-				if c.updatedAt.Negation {
-					com.WriteString(" <> ")
-				} else {
-					com.WriteString(" = ")
-				}
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_GREATER:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+		b.WriteString(" RETURNING " + strings.Join(cols, ", "))
+	}
 
-				com.WriteString(tableCommentColumnUpdatedAt)
-				com.WriteString(">")
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_GREATER_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	if r.dbg {
+		if err := r.log.Log("msg", b.String(), "function", "InsertReturningColumns"); err != nil {
+			return nil, err
+		}
+	}
 
-				com.WriteString(tableCommentColumnUpdatedAt)
-				com.WriteString(">=")
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_LESS:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	err = r.db.QueryRowContext(ctx, b.String(), insert.Args()...).Scan(props...)
+	if err != nil {
+		return nil, err
+	}
 
-				com.WriteString(tableCommentColumnUpdatedAt)
-				com.WriteString(" < ")
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_LESS_EQUAL:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	return &ent, nil
+}
+func (r *newsCategoryRepositoryBase) insertReturningColumns(e *newsCategoryEntity, cols ...string) (*newsCategoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-				com.WriteString(tableCommentColumnUpdatedAt)
-				com.WriteString(" <= ")
-				com.WritePlaceholder()
-				com.Add(c.updatedAt.Value())
-			case qtypes.QueryType_IN:
-				if len(c.updatedAt.Values) > 0 {
-					if com.Dirty {
-						com.WriteString(" AND ")
-					}
-					com.Dirty = true
-
-					com.WriteString(tableCommentColumnUpdatedAt)
-					com.WriteString(" IN (")
-					for i, v := range c.updatedAt.Values {
-						if i != 0 {
-							com.WriteString(", ")
-						}
-						com.WritePlaceholder()
-						com.Add(v)
-					}
-					com.WriteString(") ")
-				}
-			case qtypes.QueryType_BETWEEN:
-				if com.Dirty {
-					com.WriteString(" AND ")
-				}
-				com.Dirty = true
+	return r.insertReturningColumnsContext(ctx, e, cols...)
+}
 
-				updatedAtt2 := c.updatedAt.Values[1]
-				if updatedAtt2 != nil {
-					updatedAt2, err := ptypes.Timestamp(updatedAtt2)
-					if err != nil {
-						return err
-					}
-					com.WriteString(tableCommentColumnUpdatedAt)
-					com.WriteString(" > ")
-					com.WritePlaceholder()
-					com.Add(updatedAt1)
-					com.WriteString(" AND ")
-					com.WriteString(tableCommentColumnUpdatedAt)
-					com.WriteString(" < ")
-					com.WritePlaceholder()
-					com.Add(updatedAt2)
-				}
+// insertBatchContext saves given entities using multi-row statements and returns them with values read back from the database.
+// Column with default value gets DEFAULT in rows that do not hold explicit value, like in insertContext.
+// All entities are validated first, so none of them is sent if any is invalid.
+func (r *newsCategoryRepositoryBase) insertBatchContext(ctx context.Context, es []*newsCategoryEntity) ([]*newsCategoryEntity, error) {
+	for _, e := range es {
+		if r.beforeInsert != nil {
+			if err := r.beforeInsert(ctx, e); err != nil {
+				return nil, err
 			}
 		}
+
+		if err := e.validate(); err != nil {
+			return nil, err
+		}
 	}
+	for _, chunk := range pqtgo.Chunks(len(es), 2) {
+		batch := es[chunk[0]:chunk[1]]
+		com := pqtgo.NewComposer(int64(len(batch) * 2))
+		for i, e := range batch {
+			if i != 0 {
+				com.WriteString(", ")
+			}
+			com.WriteString("(")
+			com.WritePlaceholder()
+			com.Add(e.categoryID)
+			com.WriteString(", ")
+			com.WritePlaceholder()
+			com.Add(e.newsID)
+			com.WriteString(")")
+		}
 
-	if len(c.sort) > 0 {
-		i := 0
-		com.WriteString(" ORDER BY ")
+		b := bytes.NewBufferString("INSERT INTO " + r.table + " (")
+		b.WriteString(tableNewsCategoryColumnCategoryID)
+		b.WriteString(", ")
+		b.WriteString(tableNewsCategoryColumnNewsID)
+		b.WriteString(") VALUES ")
+		b.ReadFrom(com)
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
 
-		for cn, asc := range c.sort {
-			for _, tcn := range tableCommentColumns {
-				if cn == tcn {
-					if i > 0 {
-						com.WriteString(", ")
-					}
-					com.WriteString(cn)
-					if !asc {
-						com.WriteString(" DESC ")
-					}
-					i++
-					break
-				}
+		if r.dbg {
+			if err := r.log.Log("msg", b.String(), "function", "InsertBatch"); err != nil {
+				return nil, err
 			}
 		}
-	}
-	if c.offset > 0 {
-		if _, err = com.WriteString(" OFFSET "); err != nil {
-			return
+
+		rows, err := r.db.QueryContext(ctx, b.String(), com.Args()...)
+		if err != nil {
+			return nil, err
 		}
-		if err = com.WritePlaceholder(); err != nil {
-			return
+		returned := 0
+		for ; returned < len(batch) && rows.Next(); returned++ {
+			err = rows.Scan(
+				&batch[returned].categoryID,
+				&batch[returned].newsID,
+			)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
 		}
-		if _, err = com.WriteString(" "); err != nil {
-			return
+		if err = rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+		if len(r.columns) > 0 && returned != len(batch) {
+			return nil, fmt.Errorf("newsCategory insert batch failure, %d rows inserted, but %d returned", len(batch), returned)
+		}
+		for _, e := range batch {
+			if r.afterInsert != nil {
+				if err := r.afterInsert(ctx, e); err != nil {
+					return nil, err
+				}
+			}
+
 		}
-		com.Add(c.offset)
 	}
-	if c.limit > 0 {
-		if _, err = com.WriteString(" LIMIT "); err != nil {
-			return
+
+	return es, nil
+}
+func (r *newsCategoryRepositoryBase) insertBatch(es []*newsCategoryEntity) ([]*newsCategoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.insertBatchContext(ctx, es)
+}
+
+// bulkInsertContext loads given entities using COPY protocol and returns number of loaded rows.
+// Serial columns and columns with default value are omitted, they are populated by the database.
+// COPY does not support RETURNING clause, so generated values (e.g. ids) are not set on given entities.
+// All entities are validated first, so none of them is sent if any is invalid.
+// Database of the repository has to implement pqtgo.TxBeginner, like *sql.DB, or pqtgo.Preparer within a transaction, like *sql.Tx.
+func (r *newsCategoryRepositoryBase) bulkInsertContext(ctx context.Context, es []*newsCategoryEntity) (int64, error) {
+	for _, e := range es {
+		if r.beforeInsert != nil {
+			if err := r.beforeInsert(ctx, e); err != nil {
+				return 0, err
+			}
 		}
-		if err = com.WritePlaceholder(); err != nil {
-			return
+
+		if err := e.validate(); err != nil {
+			return 0, err
 		}
-		if _, err = com.WriteString(" "); err != nil {
-			return
+	}
+	query := pq.CopyInSchema("example", "news_category", tableNewsCategoryColumnCategoryID, tableNewsCategoryColumnNewsID)
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "BulkInsert"); err != nil {
+			return 0, err
 		}
-		com.Add(c.limit)
 	}
 
-	return
+	return pqtgo.CopyIn(ctx, r.db, query, len(es), r.bulkSize, func(i int) []interface{} {
+		return []interface{}{
+			es[i].categoryID,
+			es[i].newsID,
+		}
+	})
 }
+func (r *newsCategoryRepositoryBase) bulkInsert(es []*newsCategoryEntity) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-type commentPatch struct {
-	content   *ntypes.String
-	createdAt *time.Time
-	id        *ntypes.Int64
-	newsID    *ntypes.Int64
-	newsTitle *ntypes.String
-	updatedAt *time.Time
+	return r.bulkInsertContext(ctx, es)
 }
 
-type commentRepositoryBase struct {
-	table   string
-	columns []string
-	db      *sql.DB
-	dbg     bool
-	log     log.Logger
-}
+// upsertOnContext saves given entity, on conflict with given target it updates existing row using the patch, or values of the entity if patch is nil.
+// pqt.ErrUpsertIgnored is returned if conflicting row was not updated.
+func (r *newsCategoryRepositoryBase) upsertOnContext(ctx context.Context, e *newsCategoryEntity, p *newsCategoryPatch, ct pqt.UpsertConflictTarget) (*newsCategoryEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(ctx, e); err != nil {
+			return nil, err
+		}
+	}
 
-func scanCommentRows(rows *sql.Rows) ([]*commentEntity, error) {
-	var (
-		entities []*commentEntity
-		err      error
-	)
-	for rows.Next() {
-		var ent commentEntity
-		err = rows.Scan(
-			&ent.content,
-			&ent.createdAt,
-			&ent.id,
-			&ent.newsID,
-			&ent.newsTitle,
-			&ent.updatedAt,
-		)
-		if err != nil {
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+	if p != nil {
+		if err := p.validate(); err != nil {
 			return nil, err
 		}
+	}
+
+	insert := pqcomp.New(0, 2)
+	update := insert.Compose(2)
+	insert.AddExpr(tableNewsCategoryColumnCategoryID, "", e.categoryID)
+	insert.AddExpr(tableNewsCategoryColumnNewsID, "", e.newsID)
+	if p != nil && !ct.IsZero() {
+	}
+
+	b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+	if insert.Len() > 0 {
+		b.WriteString(" (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.Key())
+		}
+		insert.Reset()
+		b.WriteString(") VALUES (")
+		for insert.Next() {
+			if !insert.First() {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		}
+		b.WriteString(")")
+	}
+	var excluded []string
+	if p == nil && !ct.IsZero() {
+		insert.Reset()
+		for insert.Next() {
+			if !ct.HasColumn(insert.Key()) {
+				excluded = append(excluded, insert.Key()+" = EXCLUDED."+insert.Key())
+			}
+		}
+	}
+
+	b.WriteString(" ON CONFLICT ")
+	if !ct.IsZero() {
+		b.WriteString(ct.String())
+	}
+	switch {
+	case len(excluded) > 0:
+		b.WriteString(" DO UPDATE SET ")
+		b.WriteString(strings.Join(excluded, ", "))
+	case !ct.IsZero() && update.Len() > 0:
+		b.WriteString(" DO UPDATE SET ")
+		for update.Next() {
+			if !update.First() {
+				b.WriteString(", ")
+			}
+
+			b.WriteString(update.Key())
+			b.WriteString(" ")
+			b.WriteString(update.Oper())
+			b.WriteString(" ")
+			b.WriteString(update.PlaceHolder())
+		}
+	default:
+		b.WriteString(" DO NOTHING ")
+	}
+	if insert.Len() > 0 {
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+	}
 
-		entities = append(entities, &ent)
+	if r.dbg {
+		if err := r.log.Log("msg", b.String(), "function", "Upsert"); err != nil {
+			return nil, err
+		}
 	}
-	if rows.Err() != nil {
-		return nil, rows.Err()
+
+	err := r.db.QueryRowContext(ctx, b.String(), insert.Args()...).Scan(
+		&e.categoryID,
+		&e.newsID,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, pqt.ErrUpsertIgnored
+		}
+		return nil, err
 	}
 
-	return entities, nil
+	return e, nil
 }
+func (r *newsCategoryRepositoryBase) upsertOn(e *newsCategoryEntity, p *newsCategoryPatch, ct pqt.UpsertConflictTarget) (*newsCategoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-func (r *commentRepositoryBase) count(c *commentCriteria) (int64, error) {
+	return r.upsertOnContext(ctx, e, p, ct)
+}
 
-	com := pqtgo.NewComposer(6)
-	buf := bytes.NewBufferString("SELECT COUNT(*) FROM ")
-	buf.WriteString(r.table)
+// upsertContext is a shorthand for upsertOnContext, given columns are used to infer the conflict target.
+func (r *newsCategoryRepositoryBase) upsertContext(ctx context.Context, e *newsCategoryEntity, p *newsCategoryPatch, inf ...string) (*newsCategoryEntity, error) {
+	return r.upsertOnContext(ctx, e, p, pqt.ConflictOnColumns(inf...))
+}
+func (r *newsCategoryRepositoryBase) upsert(e *newsCategoryEntity, p *newsCategoryPatch, inf ...string) (*newsCategoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
-		return 0, err
+	return r.upsertContext(ctx, e, p, inf...)
+}
+
+// updateOneByNewsIDAndCategoryIDQuery returns query and arguments used by updateOneByNewsIDAndCategoryIDContext and its variants to modify the entity, without executing it.
+// Given columns are listed in RETURNING clause as they are, which is omitted if there are none.
+func (r *newsCategoryRepositoryBase) updateOneByNewsIDAndCategoryIDQuery(newsID int64, categoryID int64, patch *newsCategoryPatch, returning []string) (string, []interface{}, error) {
+	if err := patch.validate(); err != nil {
+		return "", nil, err
 	}
-	if com.Dirty {
-		buf.WriteString(" WHERE ")
+	update := pqcomp.New(2, 2)
+	update.AddArg(newsID)
+	update.AddArg(categoryID)
+
+	if update.Len() == 0 {
+		return "", nil, pqt.ErrNothingToUpdate
+	}
+	query := "UPDATE example.news_category SET "
+	for update.Next() {
+		if !update.First() {
+			query += ", "
+		}
+
+		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
+	query += " WHERE news_id = $1 AND category_id = $2"
+	if len(returning) > 0 {
+		query += " RETURNING " + strings.Join(returning, ", ")
+	}
+	return query, update.Args(), nil
+}
+
+// updateOneByNewsIDAndCategoryIDContext modifies the entity using values set in the patch and returns it.
+// pqt.ErrNothingToUpdate is returned if the patch holds no value.
+func (r *newsCategoryRepositoryBase) updateOneByNewsIDAndCategoryIDContext(ctx context.Context, newsID int64, categoryID int64, patch *newsCategoryPatch) (*newsCategoryEntity, error) {
+	if r.beforeUpdate != nil {
+		if err := r.beforeUpdate(ctx, newsID, categoryID, patch); err != nil {
+			return nil, err
+		}
 	}
 
+	query, args, err := r.updateOneByNewsIDAndCategoryIDQuery(newsID, categoryID, patch, r.columns)
+	if err != nil {
+		return nil, err
+	}
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Count"); err != nil {
-			return 0, err
+		if err := r.log.Log("msg", query, "function", "UpdateOneByNewsIDAndCategoryID"); err != nil {
+			return nil, err
 		}
 	}
 
-	var count int64
-	if err := r.db.QueryRow(buf.String(), com.Args()...).Scan(&count); err != nil {
-		return 0, err
+	var e newsCategoryEntity
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.categoryID,
+		&e.newsID,
+	)
+	if err != nil {
+		return nil, err
 	}
-	return count, nil
+	if r.afterUpdate != nil {
+		if err := r.afterUpdate(ctx, &e); err != nil {
+			return nil, err
+		}
+	}
+
+	return &e, nil
 }
+func (r *newsCategoryRepositoryBase) updateOneByNewsIDAndCategoryID(newsID int64, categoryID int64, patch *newsCategoryPatch) (*newsCategoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-func (r *commentRepositoryBase) find(c *commentCriteria) ([]*commentEntity, error) {
+	return r.updateOneByNewsIDAndCategoryIDContext(ctx, newsID, categoryID, patch)
+}
 
-	com := pqtgo.NewComposer(1)
-	buf := bytes.NewBufferString("SELECT ")
-	buf.WriteString(strings.Join(r.columns, ", "))
-	buf.WriteString(" FROM ")
-	buf.WriteString(r.table)
-	buf.WriteString(" ")
+// updateOneByNewsIDAndCategoryIDReturningColumnsContext works like updateOneByNewsIDAndCategoryIDContext, but returns entity with only given columns populated, unknown columns are rejected before execution.
+func (r *newsCategoryRepositoryBase) updateOneByNewsIDAndCategoryIDReturningColumnsContext(ctx context.Context, newsID int64, categoryID int64, patch *newsCategoryPatch, cols ...string) (*newsCategoryEntity, error) {
+	if r.beforeUpdate != nil {
+		if err := r.beforeUpdate(ctx, newsID, categoryID, patch); err != nil {
+			return nil, err
+		}
+	}
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
-		return nil, err
+	if len(cols) == 0 {
+		return nil, errors.New("newsCategory update failure, no columns to return")
 	}
-	if com.Dirty {
-		buf.WriteString(" WHERE ")
+	var ent newsCategoryEntity
+	props, err := ent.props(cols...)
+	if err != nil {
+		return nil, err
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
+	query, args, err := r.updateOneByNewsIDAndCategoryIDQuery(newsID, categoryID, patch, cols)
+	if err != nil {
+		return nil, err
 	}
-
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Find"); err != nil {
+		if err := r.log.Log("msg", query, "function", "UpdateOneByNewsIDAndCategoryIDReturningColumns"); err != nil {
 			return nil, err
 		}
 	}
 
-	rows, err := r.db.Query(buf.String(), com.Args()...)
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(props...)
 	if err != nil {
 		return nil, err
 	}
 
-	defer rows.Close()
-
-	return scanCommentRows(rows)
+	return &ent, nil
 }
-func (r *commentRepositoryBase) findIter(c *commentCriteria) (*commentIterator, error) {
+func (r *newsCategoryRepositoryBase) updateOneByNewsIDAndCategoryIDReturningColumns(newsID int64, categoryID int64, patch *newsCategoryPatch, cols ...string) (*newsCategoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-	com := pqtgo.NewComposer(1)
-	buf := bytes.NewBufferString("SELECT ")
-	buf.WriteString(strings.Join(r.columns, ", "))
-	buf.WriteString(" FROM ")
-	buf.WriteString(r.table)
-	buf.WriteString(" ")
+	return r.updateOneByNewsIDAndCategoryIDReturningColumnsContext(ctx, newsID, categoryID, patch, cols...)
+}
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
-		return nil, err
-	}
-	if com.Dirty {
-		buf.WriteString(" WHERE ")
+// patchOneByNewsIDAndCategoryIDContext works like updateOneByNewsIDAndCategoryIDContext, but returns number of affected rows instead of the entity.
+func (r *newsCategoryRepositoryBase) patchOneByNewsIDAndCategoryIDContext(ctx context.Context, newsID int64, categoryID int64, patch *newsCategoryPatch) (int64, error) {
+	if r.beforeUpdate != nil {
+		if err := r.beforeUpdate(ctx, newsID, categoryID, patch); err != nil {
+			return 0, err
+		}
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
+	query, args, err := r.updateOneByNewsIDAndCategoryIDQuery(newsID, categoryID, patch, nil)
+	if err != nil {
+		return 0, err
 	}
-
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Find"); err != nil {
-			return nil, err
+		if err := r.log.Log("msg", query, "function", "PatchOneByNewsIDAndCategoryID"); err != nil {
+			return 0, err
 		}
 	}
 
-	rows, err := r.db.Query(buf.String(), com.Args()...)
+	res, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	return &commentIterator{rows: rows}, nil
+	return res.RowsAffected()
 }
-func (r *commentRepositoryBase) insert(e *commentEntity) (*commentEntity, error) {
-	insert := pqcomp.New(0, 6)
-	insert.AddExpr(tableCommentColumnContent, "", e.content)
-	insert.AddExpr(tableCommentColumnCreatedAt, "", e.createdAt)
-	insert.AddExpr(tableCommentColumnNewsID, "", e.newsID)
-	insert.AddExpr(tableCommentColumnNewsTitle, "", e.newsTitle)
-	insert.AddExpr(tableCommentColumnUpdatedAt, "", e.updatedAt)
+func (r *newsCategoryRepositoryBase) patchOneByNewsIDAndCategoryID(newsID int64, categoryID int64, patch *newsCategoryPatch) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-	b := bytes.NewBufferString("INSERT INTO " + r.table)
+	return r.patchOneByNewsIDAndCategoryIDContext(ctx, newsID, categoryID, patch)
+}
 
-	if insert.Len() != 0 {
-		b.WriteString(" (")
-		for insert.Next() {
-			if !insert.First() {
-				b.WriteString(", ")
-			}
+// deleteOneByNewsIDAndCategoryIDQuery returns query and arguments used by deleteOneByNewsIDAndCategoryIDContext to remove the entity, without executing it.
+func (r *newsCategoryRepositoryBase) deleteOneByNewsIDAndCategoryIDQuery(newsID int64, categoryID int64) (string, []interface{}, error) {
+	return "DELETE FROM example.news_category WHERE news_id = $1 AND category_id = $2", []interface{}{newsID, categoryID}, nil
+}
 
-			fmt.Fprintf(b, "%s", insert.Key())
+// deleteOneByNewsIDAndCategoryIDContext removes the entity and returns number of affected rows.
+func (r *newsCategoryRepositoryBase) deleteOneByNewsIDAndCategoryIDContext(ctx context.Context, newsID int64, categoryID int64) (int64, error) {
+	if r.beforeDelete != nil {
+		if err := r.beforeDelete(ctx, newsID, categoryID); err != nil {
+			return 0, err
 		}
-		insert.Reset()
-		b.WriteString(") VALUES (")
-		for insert.Next() {
-			if !insert.First() {
-				b.WriteString(", ")
-			}
+	}
 
-			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+	query, args, err := r.deleteOneByNewsIDAndCategoryIDQuery(newsID, categoryID)
+	if err != nil {
+		return 0, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "DeleteOneByNewsIDAndCategoryID"); err != nil {
+			return 0, err
 		}
-		b.WriteString(")")
-		if len(r.columns) > 0 {
-			b.WriteString(" RETURNING ")
-			b.WriteString(strings.Join(r.columns, ", "))
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil || affected == 0 {
+		return affected, err
+	}
+	if r.afterDelete != nil {
+		if err := r.afterDelete(ctx, newsID, categoryID); err != nil {
+			return affected, err
+		}
+	}
+
+	return affected, nil
+}
+func (r *newsCategoryRepositoryBase) deleteOneByNewsIDAndCategoryID(newsID int64, categoryID int64) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.deleteOneByNewsIDAndCategoryIDContext(ctx, newsID, categoryID)
+}
+
+// deleteAndReturnOneByNewsIDAndCategoryIDContext removes the entity and returns it as it was right before removal.
+// pqt.ErrNotFound is returned if there is no such entity.
+func (r *newsCategoryRepositoryBase) deleteAndReturnOneByNewsIDAndCategoryIDContext(ctx context.Context, newsID int64, categoryID int64) (*newsCategoryEntity, error) {
+	if r.beforeDelete != nil {
+		if err := r.beforeDelete(ctx, newsID, categoryID); err != nil {
+			return nil, err
 		}
 	}
 
+	query, args, err := r.deleteOneByNewsIDAndCategoryIDQuery(newsID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	query += " RETURNING " + strings.Join(r.columns, ", ")
 	if r.dbg {
-		if err := r.log.Log("msg", b.String(), "function", "Insert"); err != nil {
+		if err := r.log.Log("msg", query, "function", "DeleteAndReturnOneByNewsIDAndCategoryID"); err != nil {
 			return nil, err
 		}
 	}
 
-	err := r.db.QueryRow(b.String(), insert.Args()...).Scan(
-		&e.content,
-		&e.createdAt,
-		&e.id,
+	var e newsCategoryEntity
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.categoryID,
 		&e.newsID,
-		&e.newsTitle,
-		&e.updatedAt,
 	)
+	if err == sql.ErrNoRows {
+		return nil, pqt.ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
+	if r.afterDelete != nil {
+		if err := r.afterDelete(ctx, newsID, categoryID); err != nil {
+			return &e, err
+		}
+	}
 
-	return e, nil
+	return &e, nil
 }
-func (r *commentRepositoryBase) upsert(e *commentEntity, p *commentPatch, inf ...string) (*commentEntity, error) {
-	insert := pqcomp.New(0, 6)
-	update := insert.Compose(6)
-	insert.AddExpr(tableCommentColumnContent, "", e.content)
-	insert.AddExpr(tableCommentColumnCreatedAt, "", e.createdAt)
-	insert.AddExpr(tableCommentColumnNewsID, "", e.newsID)
-	insert.AddExpr(tableCommentColumnNewsTitle, "", e.newsTitle)
-	insert.AddExpr(tableCommentColumnUpdatedAt, "", e.updatedAt)
-	if len(inf) > 0 {
-		update.AddExpr(tableCommentColumnContent, "=", p.content)
-		update.AddExpr(tableCommentColumnCreatedAt, "=", p.createdAt)
-		update.AddExpr(tableCommentColumnNewsID, "=", p.newsID)
-		update.AddExpr(tableCommentColumnNewsTitle, "=", p.newsTitle)
-		update.AddExpr(tableCommentColumnUpdatedAt, "=", p.updatedAt)
-	}
+func (r *newsCategoryRepositoryBase) deleteAndReturnOneByNewsIDAndCategoryID(newsID int64, categoryID int64) (*newsCategoryEntity, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-	b := bytes.NewBufferString("INSERT INTO " + r.table)
+	return r.deleteAndReturnOneByNewsIDAndCategoryIDContext(ctx, newsID, categoryID)
+}
 
-	if insert.Len() > 0 {
-		b.WriteString(" (")
-		for insert.Next() {
-			if !insert.First() {
-				b.WriteString(", ")
-			}
+// deleteByCriteriaContext removes entities that match given criteria and returns their number.
+// Empty criteria is rejected with pqt.ErrDeleteWithoutCriteria unless full scan is allowed. Sort, offset, limit and lock are not supported.
+func (r *newsCategoryRepositoryBase) deleteByCriteriaContext(ctx context.Context, c *newsCategoryCriteria, allowFullScan bool) (int64, error) {
+	if len(c.sort) > 0 || len(c.sortExpr) > 0 || c.Offset > 0 || c.Limit > 0 || c.lock != pqt.LockNone {
+		return 0, errors.New("newsCategory delete failure, sort, offset, limit and lock are not supported")
+	}
 
-			fmt.Fprintf(b, "%s", insert.Key())
-		}
-		insert.Reset()
-		b.WriteString(") VALUES (")
-		for insert.Next() {
-			if !insert.First() {
-				b.WriteString(", ")
-			}
+	where, args, err := r.plan(c)
+	if err != nil {
+		return 0, err
+	}
+	buf := bytes.NewBufferString("DELETE FROM ")
+	buf.WriteString(r.table)
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	} else if !allowFullScan {
+		return 0, pqt.ErrDeleteWithoutCriteria
+	}
 
-			fmt.Fprintf(b, "%s", insert.PlaceHolder())
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "DeleteByCriteria"); err != nil {
+			return 0, err
 		}
-		b.WriteString(")")
 	}
-	b.WriteString(" ON CONFLICT ")
-	if len(inf) > 0 && update.Len() > 0 {
-		b.WriteString(" (")
-		for j, i := range inf {
-			if j != 0 {
-				b.WriteString(", ")
-			}
-			b.WriteString(i)
-		}
-		b.WriteString(") ")
-		b.WriteString(" DO UPDATE SET ")
-		for update.Next() {
-			if !update.First() {
-				b.WriteString(", ")
-			}
 
-			b.WriteString(update.Key())
-			b.WriteString(" ")
-			b.WriteString(update.Oper())
-			b.WriteString(" ")
-			b.WriteString(update.PlaceHolder())
-		}
-	} else {
-		b.WriteString(" DO NOTHING ")
+	res, err := r.db.ExecContext(ctx, buf.String(), args...)
+	if err != nil {
+		return 0, err
 	}
-	if insert.Len() > 0 {
-		if len(r.columns) > 0 {
-			b.WriteString(" RETURNING ")
-			b.WriteString(strings.Join(r.columns, ", "))
-		}
+
+	return res.RowsAffected()
+}
+func (r *newsCategoryRepositoryBase) deleteByCriteria(c *newsCategoryCriteria, allowFullScan bool) (int64, error) {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
+
+	return r.deleteByCriteriaContext(ctx, c, allowFullScan)
+}
+
+// truncateContext removes all rows from the table.
+// If cascade is true, tables that have foreign key references to the table are truncated as well.
+// If restartIdentity is true, sequences owned by columns of the table are restarted.
+func (r *newsCategoryRepositoryBase) truncateContext(ctx context.Context, cascade, restartIdentity bool) error {
+	buf := bytes.NewBufferString("TRUNCATE ")
+	buf.WriteString(r.table)
+	if restartIdentity {
+		buf.WriteString(" RESTART IDENTITY")
+	}
+	if cascade {
+		buf.WriteString(" CASCADE")
 	}
 
 	if r.dbg {
-		if err := r.log.Log("msg", b.String(), "function", "Upsert"); err != nil {
-			return nil, err
+		if err := r.log.Log("msg", buf.String(), "function", "Truncate"); err != nil {
+			return err
 		}
 	}
 
-	err := r.db.QueryRow(b.String(), insert.Args()...).Scan(
-		&e.content,
-		&e.createdAt,
-		&e.id,
-		&e.newsID,
-		&e.newsTitle,
-		&e.updatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
+	_, err := r.db.ExecContext(ctx, buf.String())
+	return err
+}
+func (r *newsCategoryRepositoryBase) truncate(cascade, restartIdentity bool) error {
+	ctx, cancel := r.defaultContext()
+	defer cancel()
 
-	return e, nil
+	return r.truncateContext(ctx, cascade, restartIdentity)
 }
 
 /// SQL ...
 const SQL = `
 -- do not modify, generated by pqt
 
-CREATE SCHEMA IF NOT EXISTS example; 
+CREATE SCHEMA IF NOT EXISTS example;
+
+DO $$
+BEGIN
+	CREATE TYPE example.news_status AS ENUM ('draft', 'published');
+EXCEPTION
+	WHEN duplicate_object THEN NULL;
+END
+$$;
+ALTER TYPE example.news_status ADD VALUE IF NOT EXISTS 'draft';
+ALTER TYPE example.news_status ADD VALUE IF NOT EXISTS 'published';
 
 CREATE TABLE IF NOT EXISTS example.category (
 	content TEXT NOT NULL,
 	created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+	deleted_at TIMESTAMPTZ,
 	id BIGSERIAL,
 	name TEXT NOT NULL,
 	parent_id BIGINT,
@@ -3335,6 +12246,8 @@ CREATE TABLE IF NOT EXISTS example.news (
 	created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
 	id BIGSERIAL,
 	lead TEXT,
+	status example.news_status,
+	tags TEXT[],
 	title TEXT NOT NULL,
 	updated_at TIMESTAMPTZ,
 
@@ -3342,6 +12255,7 @@ CREATE TABLE IF NOT EXISTS example.news (
 	CONSTRAINT "example.news_title_key" UNIQUE (title),
 	CONSTRAINT "example.news_title_lead_key" UNIQUE (title, lead)
 );
+COMMENT ON COLUMN example.news.lead IS 'Short summary displayed on the list of news.';
 
 CREATE TABLE IF NOT EXISTS example.comment (
 	content TEXT NOT NULL,
@@ -3355,4 +12269,13 @@ CREATE TABLE IF NOT EXISTS example.comment (
 	CONSTRAINT "example.comment_news_title_fkey" FOREIGN KEY (news_title) REFERENCES example.news (title)
 );
 
+CREATE TABLE IF NOT EXISTS example.news_category (
+	category_id BIGINT NOT NULL,
+	news_id BIGINT NOT NULL,
+
+	CONSTRAINT "example.news_category_news_id_category_id_pkey" PRIMARY KEY (news_id, category_id),
+	CONSTRAINT "example.news_category_news_id_fkey" FOREIGN KEY (news_id) REFERENCES example.news (id),
+	CONSTRAINT "example.news_category_category_id_fkey" FOREIGN KEY (category_id) REFERENCES example.category (id)
+);
+
 `
@@ -40,6 +40,8 @@ func main() {
 		SetPostgresVersion(9.5).
 		SetAcronyms(acronyms).
 		SetVisibility(pqtgo.Private).
+		SetContext(true).
+		SetJoins(true).
 		GenerateTo(sch, file)
 	if err != nil {
 		log.Fatal(err)
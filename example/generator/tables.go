@@ -4,14 +4,17 @@ import "github.com/piotrkowalczuk/pqt"
 
 func schema(sn string) *pqt.Schema {
 	title := pqt.NewColumn("title", pqt.TypeText(), pqt.WithNotNull(), pqt.WithUnique())
-	lead := pqt.NewColumn("lead", pqt.TypeText())
+	lead := pqt.NewColumn("lead", pqt.TypeText(), pqt.WithComment("Short summary displayed on the list of news."))
+	id := pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())
 
-	news := pqt.NewTable("news", pqt.WithTableIfNotExists()).
-		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+	news := pqt.NewTable("news", pqt.WithTableIfNotExists(), pqt.WithReturning(id, title)).
+		AddColumn(id).
 		AddColumn(title).
 		AddColumn(lead).
 		AddColumn(pqt.NewColumn("continue", pqt.TypeBool(), pqt.WithNotNull(), pqt.WithDefault("false"))).
 		AddColumn(pqt.NewColumn("content", pqt.TypeText(), pqt.WithNotNull())).
+		AddColumn(pqt.NewColumn("tags", pqt.TypeTextArray(0))).
+		AddColumn(pqt.NewColumn("status", pqt.TypeEnumerated(sn+".news_status", "draft", "published"))).
 		AddUnique(title, lead)
 
 	comment := pqt.NewTable("comment", pqt.WithTableIfNotExists()).
@@ -24,7 +27,7 @@ func schema(sn string) *pqt.Schema {
 			pqt.WithReference(title, pqt.WithBidirectional(), pqt.WithOwnerName("comments_by_news_title"), pqt.WithInversedName("news_by_title")),
 		))
 
-	category := pqt.NewTable("category", pqt.WithTableIfNotExists()).
+	category := pqt.NewTable("category", pqt.WithTableIfNotExists(), pqt.WithSoftDelete()).
 		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
 		AddColumn(pqt.NewColumn("name", pqt.TypeText(), pqt.WithNotNull())).
 		AddColumn(pqt.NewColumn("content", pqt.TypeText(), pqt.WithNotNull())).
@@ -54,11 +57,20 @@ func schema(sn string) *pqt.Schema {
 
 	pqt.ManyToMany(category, news, pqt.WithBidirectional())
 
+	newsID, _ := news.PrimaryKey()
+	categoryID, _ := category.PrimaryKey()
+	newsCategoryNewsID := pqt.NewColumn("news_id", pqt.TypeIntegerBig(), pqt.WithNotNull(), pqt.WithReference(newsID))
+	newsCategoryCategoryID := pqt.NewColumn("category_id", pqt.TypeIntegerBig(), pqt.WithNotNull(), pqt.WithReference(categoryID))
+	newsCategory := pqt.NewTable("news_category", pqt.WithTableIfNotExists(), pqt.WithTablePrimaryKey(newsCategoryNewsID, newsCategoryCategoryID)).
+		AddColumn(newsCategoryNewsID).
+		AddColumn(newsCategoryCategoryID)
+
 	return pqt.NewSchema(sn, pqt.WithSchemaIfNotExists()).
 		AddTable(category).
 		AddTable(pkg).
 		AddTable(news).
-		AddTable(comment)
+		AddTable(comment).
+		AddTable(newsCategory)
 }
 
 func timestampable(t *pqt.Table) {
@@ -0,0 +1,8 @@
+package pqt
+
+import "encoding/json"
+
+// ExplainHook receives execution plan of a query, as returned by EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON).
+// Generated repositories call it in debug mode only, before read only queries are executed,
+// plan is obtained by executing the query, so it is meant for development use.
+type ExplainHook func(plan json.RawMessage, query string, args []interface{})
@@ -0,0 +1,65 @@
+package pqt
+
+import "strings"
+
+// reservedKeywords are keywords that cannot be used as table or column names unless they are quoted,
+// see https://www.postgresql.org/docs/current/sql-keywords-appendix.html.
+var reservedKeywords = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true, "array": true, "as": true, "asc": true,
+	"asymmetric": true, "authorization": true, "binary": true, "both": true, "case": true, "cast": true, "check": true,
+	"collate": true, "collation": true, "column": true, "concurrently": true, "constraint": true, "create": true,
+	"cross": true, "current_catalog": true, "current_date": true, "current_role": true, "current_schema": true,
+	"current_time": true, "current_timestamp": true, "current_user": true, "default": true, "deferrable": true,
+	"desc": true, "distinct": true, "do": true, "else": true, "end": true, "except": true, "false": true, "fetch": true,
+	"for": true, "foreign": true, "freeze": true, "from": true, "full": true, "grant": true, "group": true,
+	"having": true, "ilike": true, "in": true, "initially": true, "inner": true, "intersect": true, "into": true,
+	"is": true, "isnull": true, "join": true, "lateral": true, "leading": true, "left": true, "like": true,
+	"limit": true, "localtime": true, "localtimestamp": true, "natural": true, "not": true, "notnull": true,
+	"null": true, "offset": true, "on": true, "only": true, "or": true, "order": true, "outer": true,
+	"overlaps": true, "placing": true, "primary": true, "references": true, "returning": true, "right": true,
+	"select": true, "session_user": true, "similar": true, "some": true, "symmetric": true, "table": true,
+	"tablesample": true, "then": true, "to": true, "trailing": true, "true": true, "union": true, "unique": true,
+	"user": true, "using": true, "variadic": true, "verbose": true, "when": true, "where": true, "window": true,
+	"with": true,
+}
+
+// QuoteIdentifier returns name that can be used as an identifier in a query.
+// Name is quoted only if it is necessary, that is if it is a reserved keyword
+// or if it contains characters other than lower case letters, digits, underscore and dollar sign.
+// Unlike pq.QuoteIdentifier, plain names are left untouched, so that generated queries stay readable.
+func QuoteIdentifier(name string) string {
+	if needsQuoting(name) {
+		return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+	}
+
+	return name
+}
+
+func needsQuoting(name string) bool {
+	if name == "" || reservedKeywords[name] {
+		return true
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r == '_':
+		case (r >= '0' && r <= '9') || r == '$':
+			if i == 0 {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+
+	return false
+}
+
+// QuoteColumns works like JoinColumns, but quotes names of the columns if necessary, see QuoteIdentifier.
+func QuoteColumns(columns Columns, sep string) string {
+	tmp := make([]string, 0, len(columns))
+	for _, c := range columns {
+		tmp = append(tmp, QuoteIdentifier(c.Name))
+	}
+
+	return strings.Join(tmp, sep)
+}
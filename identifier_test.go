@@ -0,0 +1,50 @@
+package pqt_test
+
+import (
+	"testing"
+
+	"github.com/piotrkowalczuk/pqt"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"news":         "news",
+		"news_2":       "news_2",
+		"_news$":       "_news$",
+		"user":         `"user"`,
+		"order":        `"order"`,
+		"News":         `"News"`,
+		"2news":        `"2news"`,
+		"news-archive": `"news-archive"`,
+		`a"b`:          `"a""b"`,
+		"":             `""`,
+	}
+	for given, expected := range cases {
+		t.Run(given, func(t *testing.T) {
+			if got := pqt.QuoteIdentifier(given); got != expected {
+				t.Errorf("wrong identifier, expected %s but got %s", expected, got)
+			}
+		})
+	}
+}
+
+func TestQuoteColumns(t *testing.T) {
+	got := pqt.QuoteColumns(pqt.Columns{pqt.NewColumn("id", pqt.TypeInteger()), pqt.NewColumn("user", pqt.TypeInteger())}, ", ")
+	if got != `id, "user"` {
+		t.Errorf("wrong columns, got %s", got)
+	}
+}
+
+func TestTable_QuotedName(t *testing.T) {
+	tbl := pqt.NewTable("user")
+	if got := tbl.QuotedName(); got != `"user"` {
+		t.Errorf("wrong name, got %s", got)
+	}
+	pqt.NewSchema("Content").AddTable(tbl)
+	if got := tbl.QuotedName(); got != `"Content"."user"` {
+		t.Errorf("wrong name, got %s", got)
+	}
+	if got := tbl.FullName(); got != "Content.user" {
+		t.Errorf("full name should stay unquoted, got %s", got)
+	}
+}
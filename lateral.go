@@ -0,0 +1,59 @@
+package pqt
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+)
+
+// LateralJoin is a correlated sub-query joined using JOIN LATERAL (...) AS alias ON TRUE,
+// e.g. to retrieve latest N comments of each news:
+//
+//	pqt.LateralJoin{
+//		Query:   "SELECT id, content FROM comment WHERE comment.news_id = t0.id ORDER BY created_at DESC LIMIT $1",
+//		Args:    []interface{}{3},
+//		Alias:   "latest",
+//		Columns: []string{"id", "content"},
+//	}
+//
+// Query can refer to columns of the main table using t0 alias, its placeholders are numbered from $1
+// and renumbered when the query is embedded. It is embedded as it is, so it should never come from user input.
+type LateralJoin struct {
+	Query string
+	Args  []interface{}
+	Alias string
+	// Columns of the sub-query that are selected next to columns of the main table, as alias.column.
+	Columns []string
+}
+
+// Validate returns an error if alias or any of the columns is not a plain identifier, or the query is empty.
+func (lj LateralJoin) Validate() error {
+	if lj.Query == "" {
+		return errors.New("pqt: lateral join query is empty")
+	}
+	if !isPlainIdentifier(lj.Alias) || lj.Alias == "t0" {
+		return fmt.Errorf("pqt: invalid lateral join alias %q", lj.Alias)
+	}
+	if len(lj.Columns) == 0 {
+		return fmt.Errorf("pqt: lateral join %q has no columns", lj.Alias)
+	}
+	for _, c := range lj.Columns {
+		if !isPlainIdentifier(c) {
+			return fmt.Errorf("pqt: invalid column %q of lateral join %q", c, lj.Alias)
+		}
+	}
+
+	return nil
+}
+
+func isPlainIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package pqt
+
+import (
+	"testing"
+)
+
+func TestLateralJoin_Validate(t *testing.T) {
+	valid := LateralJoin{Query: "SELECT id FROM comment WHERE news_id = t0.id LIMIT $1", Args: []interface{}{3}, Alias: "latest", Columns: []string{"id", "content_2"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	invalid := map[string]LateralJoin{
+		"empty-query":     {Alias: "latest", Columns: []string{"id"}},
+		"empty-alias":     {Query: "SELECT 1 AS id", Columns: []string{"id"}},
+		"reserved-alias":  {Query: "SELECT 1 AS id", Alias: "t0", Columns: []string{"id"}},
+		"alias-injection": {Query: "SELECT 1 AS id", Alias: "a ON TRUE; DROP TABLE news", Columns: []string{"id"}},
+		"no-columns":      {Query: "SELECT 1 AS id", Alias: "latest"},
+		"column-digit":    {Query: "SELECT 1 AS id", Alias: "latest", Columns: []string{"1id"}},
+		"column-expr":     {Query: "SELECT 1 AS id", Alias: "latest", Columns: []string{"id, pg_sleep(1)"}},
+	}
+	for hint, lj := range invalid {
+		t.Run(hint, func(t *testing.T) {
+			if err := lj.Validate(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package pqt
+
+// LockMode represents row level locking clause appended to SELECT statements by generated find methods.
+// Locking is effective only within a transaction, it is caller's responsibility to use it that way.
+type LockMode int
+
+const (
+	// LockNone does not lock selected rows.
+	LockNone LockMode = iota
+	// LockUpdate locks selected rows as if for update (FOR UPDATE).
+	LockUpdate
+	// LockShare acquires shared lock on selected rows (FOR SHARE).
+	LockShare
+	// LockUpdateSkipLocked works like LockUpdate, but rows that cannot be locked immediately are skipped.
+	LockUpdateSkipLocked
+	// LockUpdateNoWait works like LockUpdate, but query fails instead of waiting if any row cannot be locked immediately.
+	LockUpdateNoWait
+	// LockShareSkipLocked works like LockShare, but rows that cannot be locked immediately are skipped.
+	LockShareSkipLocked
+	// LockShareNoWait works like LockShare, but query fails instead of waiting if any row cannot be locked immediately.
+	LockShareNoWait
+)
+
+// String returns locking clause, empty string for LockNone.
+func (lm LockMode) String() string {
+	switch lm {
+	case LockUpdate:
+		return "FOR UPDATE"
+	case LockShare:
+		return "FOR SHARE"
+	case LockUpdateSkipLocked:
+		return "FOR UPDATE SKIP LOCKED"
+	case LockUpdateNoWait:
+		return "FOR UPDATE NOWAIT"
+	case LockShareSkipLocked:
+		return "FOR SHARE SKIP LOCKED"
+	case LockShareNoWait:
+		return "FOR SHARE NOWAIT"
+	default:
+		return ""
+	}
+}
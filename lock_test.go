@@ -0,0 +1,22 @@
+package pqt
+
+import (
+	"testing"
+)
+
+func TestLockMode_String(t *testing.T) {
+	cases := map[LockMode]string{
+		LockNone:             "",
+		LockUpdate:           "FOR UPDATE",
+		LockShare:            "FOR SHARE",
+		LockUpdateSkipLocked: "FOR UPDATE SKIP LOCKED",
+		LockUpdateNoWait:     "FOR UPDATE NOWAIT",
+		LockShareSkipLocked:  "FOR SHARE SKIP LOCKED",
+		LockShareNoWait:      "FOR SHARE NOWAIT",
+	}
+	for lm, expected := range cases {
+		if got := lm.String(); got != expected {
+			t.Errorf("wrong clause, expected %q but got %q", expected, got)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package pqt
+
+// Planner builds condition of queries executed by generated repositories from given criteria.
+// Generated repository uses it instead of the condition built by the criteria itself, if it is set.
+// It allows e.g. to log, to restrict rows visible to the current user or to support custom operators.
+// Given criteria is a pointer to the criteria of the repository, its WhereClause method builds the default condition.
+type Planner interface {
+	// Plan returns condition without WHERE keyword and its arguments in order, placeholders are numbered from $1.
+	// Empty condition matches all rows.
+	Plan(c interface{}) (string, []interface{}, error)
+}
@@ -0,0 +1,32 @@
+package pqtgo
+
+// MaxPlaceholders is a maximum number of positional parameters postgres accepts within single statement.
+const MaxPlaceholders = 65535
+
+// Chunks splits n rows, each of them made of given number of columns, into ranges of [start, end).
+// Each range is small enough to be written within single statement without exceeding MaxPlaceholders limit.
+func Chunks(n, columns int) [][2]int {
+	if n <= 0 {
+		return nil
+	}
+
+	size := n
+	if columns > 0 && n*columns > MaxPlaceholders {
+		size = MaxPlaceholders / columns
+		// Row wider than the limit gets chunk of its own, it is up to the database to reject it.
+		if size == 0 {
+			size = 1
+		}
+	}
+
+	chunks := make([][2]int, 0, n/size+1)
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		chunks = append(chunks, [2]int{start, end})
+	}
+
+	return chunks
+}
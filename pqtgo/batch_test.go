@@ -0,0 +1,61 @@
+package pqtgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChunks(t *testing.T) {
+	cases := map[string]struct {
+		rows, columns int
+		exp           [][2]int
+	}{
+		"empty": {
+			rows:    0,
+			columns: 3,
+			exp:     nil,
+		},
+		"single": {
+			rows:    20,
+			columns: 3,
+			exp:     [][2]int{{0, 20}},
+		},
+		"exact": {
+			rows:    21845,
+			columns: 3,
+			exp:     [][2]int{{0, 21845}},
+		},
+		"chunked": {
+			rows:    30000,
+			columns: 3,
+			exp:     [][2]int{{0, 21845}, {21845, 30000}},
+		},
+	}
+
+	for hint, c := range cases {
+		t.Run(hint, func(t *testing.T) {
+			got := Chunks(c.rows, c.columns)
+			if !reflect.DeepEqual(c.exp, got) {
+				t.Errorf("wrong chunks, expected %v but got %v", c.exp, got)
+			}
+			placeholders := 0
+			for _, chunk := range got {
+				if (chunk[1]-chunk[0])*c.columns > MaxPlaceholders {
+					t.Errorf("chunk %v exceeds placeholders limit", chunk)
+				}
+				placeholders += (chunk[1] - chunk[0]) * c.columns
+			}
+			if placeholders != c.rows*c.columns {
+				t.Errorf("wrong number of placeholders, expected %d but got %d", c.rows*c.columns, placeholders)
+			}
+		})
+	}
+}
+
+func TestChunks_wideRow(t *testing.T) {
+	got := Chunks(2, MaxPlaceholders+1)
+	exp := [][2]int{{0, 1}, {1, 2}}
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("wrong chunks, expected %v but got %v", exp, got)
+	}
+}
@@ -56,6 +56,17 @@ func NewComposer(size int64) *Composer {
 	}
 }
 
+// NewComposerAt works like NewComposer, but placeholders are numbered starting at given index.
+// It allows to embed composed expression into a query that already has some arguments.
+func NewComposerAt(size int64, placeholder int) *Composer {
+	com := NewComposer(size)
+	if placeholder > 1 {
+		com.counter = placeholder
+	}
+
+	return com
+}
+
 // WriteString appends the contents of s to the query buffer, growing the buffer as
 // needed. The return value n is the length of s; err is always nil. If the
 // buffer becomes too large, WriteString will panic with bytes ErrTooLarge.
@@ -60,6 +60,24 @@ func TestComposer_WritePlaceholder(t *testing.T) {
 	}
 }
 
+func TestNewComposerAt(t *testing.T) {
+	cases := map[int]string{
+		0: "$1$2",
+		1: "$1$2",
+		3: "$3$4",
+	}
+
+	for start, expected := range cases {
+		com := NewComposerAt(0, start)
+		com.WritePlaceholder()
+		com.WritePlaceholder()
+
+		if com.String() != expected {
+			t.Errorf("unexpected buffer output for %d, expected %s but got %s", start, expected, com.String())
+		}
+	}
+}
+
 func TestComposer(t *testing.T) {
 	com := NewComposer(0)
 	expected := 100
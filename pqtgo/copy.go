@@ -0,0 +1,77 @@
+package pqtgo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// CopyIn loads given number of rows using COPY FROM STDIN statement, for example one produced by pq.CopyIn.
+// Values of each row are retrieved using given function.
+// Rows are sent in batches of given size, each batch is a separate COPY statement. Non positive size means single batch.
+// If db is a TxBeginner, like *sql.DB, new transaction is started and committed once all rows are loaded.
+// Otherwise it has to be a Preparer that already runs within a transaction, like *sql.Tx.
+// Interfaces are matched instead of concrete types, so queriers that wrap them, e.g. for tracing, are supported as well.
+func CopyIn(ctx context.Context, db Querier, query string, rows, size int, values func(i int) []interface{}) (int64, error) {
+	var (
+		tx  *sql.Tx
+		prp Preparer
+		err error
+	)
+	switch q := db.(type) {
+	case TxBeginner:
+		if tx, err = q.BeginTx(ctx, nil); err != nil {
+			return 0, err
+		}
+		prp = tx
+	case Preparer:
+		prp = q
+	default:
+		return 0, fmt.Errorf("pqtgo: copy in requires querier that implements BeginTx or PrepareContext, got %T", db)
+	}
+	fail := func(err error) (int64, error) {
+		if tx != nil {
+			tx.Rollback()
+		}
+		return 0, err
+	}
+
+	if size <= 0 {
+		size = rows
+	}
+	var affected int64
+	for from := 0; from < rows; from += size {
+		to := from + size
+		if to > rows {
+			to = rows
+		}
+
+		stmt, err := prp.PrepareContext(ctx, query)
+		if err != nil {
+			return fail(err)
+		}
+		for i := from; i < to; i++ {
+			if _, err = stmt.ExecContext(ctx, values(i)...); err != nil {
+				stmt.Close()
+				return fail(err)
+			}
+		}
+		// Exec without arguments flushes buffered data.
+		if _, err = stmt.ExecContext(ctx); err != nil {
+			stmt.Close()
+			return fail(err)
+		}
+		if err = stmt.Close(); err != nil {
+			return fail(err)
+		}
+		affected += int64(to - from)
+	}
+
+	if tx != nil {
+		if err = tx.Commit(); err != nil {
+			return 0, err
+		}
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,211 @@
+package pqtgo
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type querierMock struct {
+	Querier
+}
+
+func TestCopyIn_unsupportedQuerier(t *testing.T) {
+	_, err := CopyIn(context.Background(), querierMock{}, "COPY", 1, 0, func(int) []interface{} {
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if err.Error() != "pqtgo: copy in requires querier that implements BeginTx or PrepareContext, got pqtgo.querierMock" {
+		t.Errorf("wrong error: %s", err.Error())
+	}
+}
+
+// tracingBeginner wraps *sql.DB the way instrumentation libraries do, without exposing the concrete type.
+type tracingBeginner struct {
+	Querier
+	db    *sql.DB
+	began int
+}
+
+func (tb *tracingBeginner) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	tb.began++
+	return tb.db.BeginTx(ctx, opts)
+}
+
+// tracingPreparer wraps *sql.Tx the way instrumentation libraries do, without exposing the concrete type.
+type tracingPreparer struct {
+	Querier
+	tx       *sql.Tx
+	prepared int
+}
+
+func (tp *tracingPreparer) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	tp.prepared++
+	return tp.tx.PrepareContext(ctx, query)
+}
+
+func TestCopyIn_wrappedQuerier(t *testing.T) {
+	values := func(i int) []interface{} {
+		return []interface{}{int64(i)}
+	}
+
+	t.Run("beginner", func(t *testing.T) {
+		db, rec := openCopyDB(t)
+		q := &tracingBeginner{Querier: db, db: db}
+		affected, err := CopyIn(context.Background(), q, "COPY", 3, 2, values)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if affected != 3 {
+			t.Errorf("wrong number of affected rows, expected 3 but got %d", affected)
+		}
+		if q.began != 1 {
+			t.Errorf("transaction should be started once, got %d", q.began)
+		}
+		rec.assert(t, []int64{0, 1, 2}, 1, 0)
+	})
+	t.Run("preparer", func(t *testing.T) {
+		db, rec := openCopyDB(t)
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		q := &tracingPreparer{Querier: tx, tx: tx}
+		affected, err := CopyIn(context.Background(), q, "COPY", 3, 2, values)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if affected != 3 {
+			t.Errorf("wrong number of affected rows, expected 3 but got %d", affected)
+		}
+		if q.prepared != 2 {
+			t.Errorf("each batch should be prepared, expected 2 but got %d", q.prepared)
+		}
+		// Transaction given by the caller is not committed by CopyIn.
+		rec.assert(t, []int64{0, 1, 2}, 0, 0)
+		if err := tx.Rollback(); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+	})
+	t.Run("rollback", func(t *testing.T) {
+		db, rec := openCopyDB(t)
+		q := &tracingBeginner{Querier: db, db: db}
+		rec.fail = 1
+		if _, err := CopyIn(context.Background(), q, "COPY", 3, 0, values); err == nil {
+			t.Fatal("expected error")
+		}
+		rec.assert(t, []int64{0}, 0, 1)
+	})
+}
+
+// copyRecorder holds rows and transaction outcomes recorded by copyDriver, fake database/sql driver of COPY statements.
+type copyRecorder struct {
+	mu        sync.Mutex
+	rows      []int64
+	commits   int
+	rollbacks int
+	// fail is value of the row that is rejected, -1 accepts all of them.
+	fail int64
+}
+
+var copyRecorders sync.Map
+
+func init() {
+	sql.Register("pqtgo-copy", copyDriver{})
+}
+
+func openCopyDB(t *testing.T) (*sql.DB, *copyRecorder) {
+	rec := &copyRecorder{fail: -1}
+	copyRecorders.Store(t.Name(), rec)
+	db, err := sql.Open("pqtgo-copy", t.Name())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+	return db, rec
+}
+
+func (r *copyRecorder) assert(t *testing.T, rows []int64, commits, rollbacks int) {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !reflect.DeepEqual(rows, r.rows) {
+		t.Errorf("wrong rows, expected %v but got %v", rows, r.rows)
+	}
+	if r.commits != commits {
+		t.Errorf("wrong number of commits, expected %d but got %d", commits, r.commits)
+	}
+	if r.rollbacks != rollbacks {
+		t.Errorf("wrong number of rollbacks, expected %d but got %d", rollbacks, r.rollbacks)
+	}
+}
+
+type copyDriver struct{}
+
+func (copyDriver) Open(name string) (driver.Conn, error) {
+	rec, ok := copyRecorders.Load(name)
+	if !ok {
+		return nil, errors.New("unknown recorder")
+	}
+	return &copyConn{rec: rec.(*copyRecorder)}, nil
+}
+
+type copyConn struct {
+	rec *copyRecorder
+}
+
+func (c *copyConn) Prepare(string) (driver.Stmt, error) { return &copyStmt{rec: c.rec}, nil }
+func (c *copyConn) Close() error                        { return nil }
+func (c *copyConn) Begin() (driver.Tx, error)           { return &copyTx{rec: c.rec}, nil }
+
+type copyTx struct {
+	rec *copyRecorder
+}
+
+func (tx *copyTx) Commit() error {
+	tx.rec.mu.Lock()
+	defer tx.rec.mu.Unlock()
+	tx.rec.commits++
+	return nil
+}
+
+func (tx *copyTx) Rollback() error {
+	tx.rec.mu.Lock()
+	defer tx.rec.mu.Unlock()
+	tx.rec.rollbacks++
+	return nil
+}
+
+type copyStmt struct {
+	rec *copyRecorder
+}
+
+func (s *copyStmt) Close() error  { return nil }
+func (s *copyStmt) NumInput() int { return -1 }
+
+func (s *copyStmt) Exec(args []driver.Value) (driver.Result, error) {
+	// Exec without arguments flushes buffered rows.
+	if len(args) == 0 {
+		return driver.RowsAffected(0), nil
+	}
+	s.rec.mu.Lock()
+	defer s.rec.mu.Unlock()
+	v := args[0].(int64)
+	if v == s.rec.fail {
+		return nil, errors.New("copy failure")
+	}
+	s.rec.rows = append(s.rec.rows, v)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *copyStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
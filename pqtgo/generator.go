@@ -2,15 +2,19 @@ package pqtgo
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"go/types"
 	"io"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/huandu/xstrings"
 	"github.com/piotrkowalczuk/pqt"
+	"github.com/piotrkowalczuk/pqt/pqtsql"
 )
 
 const (
@@ -36,6 +40,17 @@ const (
 	Private Visibility = "private"
 )
 
+// Driver is a database driver that generated code is built on top of.
+type Driver string
+
+const (
+	// DriverSQL makes generated code use database/sql, it is the default.
+	DriverSQL Driver = "database/sql"
+	// DriverPgx makes generated code use native interface of github.com/jackc/pgx/v5,
+	// repositories accept *pgxpool.Pool, *pgxpool.Conn, *pgx.Conn or pgx.Tx.
+	DriverPgx Driver = "pgx"
+)
+
 var keywords = map[string]string{
 	"break":       "brk",
 	"default":     "def",
@@ -71,14 +86,38 @@ type Generator struct {
 	imports  []string
 	pkg      string
 	vis      Visibility
+	driver   Driver
+	ctx      bool
+	joins    bool
+	// strictSort makes generated code reject unknown sort columns instead of ignoring them.
+	strictSort bool
+	// prepared makes methods that execute queries of fixed shape use cached prepared statements.
+	prepared bool
+	// interfaces makes each repository get interface and in-memory mock that implements it.
+	interfaces bool
+	// cache makes each repository get decorator that caches results using pqt.Cache.
+	cache bool
+	// ddl makes each table get function that returns its DDL.
+	ddl bool
+	// methods collects signatures of repository methods of the table being generated.
+	methods []repositoryMethod
+	// fieldTags lists struct tag keys, e.g. json or db, that entity properties mapped from columns are tagged with.
+	fieldTags []string
+}
+
+// repositoryMethod is a signature of generated repository method, name is given without Context suffix.
+type repositoryMethod struct {
+	name, params, args, results string
 }
 
 // NewGenerator allocates new Generator.
 func NewGenerator() *Generator {
 	return &Generator{
-		ver: 9.5,
-		pkg: "main",
-		vis: Private,
+		ver:        9.5,
+		pkg:        "main",
+		vis:        Private,
+		driver:     DriverSQL,
+		strictSort: true,
 	}
 }
 
@@ -102,6 +141,82 @@ func (g *Generator) SetVisibility(v Visibility) *Generator {
 	return g
 }
 
+// SetDriver sets database driver that generated code uses, DriverSQL by default.
+// Signatures of generated methods do not depend on the driver, except WithTx that accepts transaction of the driver.
+// DriverPgx requires context aware methods and cannot be combined with prepared statements,
+// pgx prepares and caches statements on its own.
+func (g *Generator) SetDriver(d Driver) *Generator {
+	g.driver = d
+
+	return g
+}
+
+// SetContext enables generation of context aware repository methods.
+// Each method gets its <name>Context counterpart, original one is delegating to it using context.Background().
+func (g *Generator) SetContext(ctx bool) *Generator {
+	g.ctx = ctx
+
+	return g
+}
+
+// SetJoins enables generation of repository methods that eager load related entities.
+// For each many to one and one to one relationship table owns, findWith<relationship> method is generated.
+func (g *Generator) SetJoins(joins bool) *Generator {
+	g.joins = joins
+
+	return g
+}
+
+// SetStrictSort controls how generated code handles sort keys of the criteria that are not columns of the table.
+// If strict, which is the default, such key produces an error, otherwise it is ignored.
+func (g *Generator) SetStrictSort(strict bool) *Generator {
+	g.strictSort = strict
+
+	return g
+}
+
+// SetPreparedStatements enables prepared statement caching for methods that execute queries of fixed shape:
+// insert, findOneBy<primary-key>, updateOneBy<primary-key>, deleteOneBy<primary-key> and count without criteria.
+// Generated repository gets stmts property that has to be set using pqtgo.NewStatementCache and Close method that releases statements.
+func (g *Generator) SetPreparedStatements(prepared bool) *Generator {
+	g.prepared = prepared
+
+	return g
+}
+
+// SetInterfaces enables generation of <table>Repository interface that lists all methods of the repository,
+// together with mock<Table>Repository, in-memory implementation of the interface meant for unit tests.
+func (g *Generator) SetInterfaces(interfaces bool) *Generator {
+	g.interfaces = interfaces
+
+	return g
+}
+
+// SetCache enables generation of cached<Table>Repository, decorator of the repository that caches results
+// of count and find by primary key using pqt.Cache, results are invalidated by every method that modifies the table.
+func (g *Generator) SetCache(cache bool) *Generator {
+	g.cache = cache
+
+	return g
+}
+
+// SetDDL enables generation of table<Table>DDL function that returns CREATE TABLE statement of the table,
+// together with its indexes and comments, exactly as generated by pqtsql. It allows to create tables in integration tests
+// without external SQL files. Schema, enumerated and domain types the table depends on are not part of it.
+func (g *Generator) SetDDL(ddl bool) *Generator {
+	g.ddl = ddl
+
+	return g
+}
+
+// SetFieldTags makes entity properties that are mapped from columns tagged with given keys, e.g. "json" and "db".
+// Each tag holds name of the column, json tag of nullable column is additionally marked as omitempty.
+func (g *Generator) SetFieldTags(keys ...string) *Generator {
+	g.fieldTags = keys
+
+	return g
+}
+
 // SetImports ...
 func (g *Generator) SetImports(imports ...string) *Generator {
 	g.imports = imports
@@ -119,7 +234,8 @@ func (g *Generator) AddImport(i string) *Generator {
 	return g
 }
 
-// SetPackage ...
+// SetPackage sets name of the package generated code belongs to, "main" by default.
+// Generated types refer to each other without qualifier, so output can be placed in any package, separate from the generator itself.
 func (g *Generator) SetPackage(pkg string) *Generator {
 	g.pkg = pkg
 
@@ -148,21 +264,69 @@ func (g *Generator) GenerateTo(s *pqt.Schema, w io.Writer) error {
 }
 
 func (g *Generator) generate(s *pqt.Schema) (*bytes.Buffer, error) {
+	switch g.driver {
+	case DriverSQL:
+	case DriverPgx:
+		if !g.ctx {
+			return nil, errors.New("pqtgo: pgx driver requires context aware methods, see SetContext")
+		}
+		if g.prepared {
+			return nil, errors.New("pqtgo: pgx driver does not support prepared statements, pgx caches them on its own")
+		}
+	default:
+		return nil, fmt.Errorf("pqtgo: unknown driver: %s", g.driver)
+	}
+
 	b := bytes.NewBuffer(nil)
 
 	g.generatePackage(b)
 	g.generateImports(b, s)
+	g.generatePgxQuerier(b)
+	for _, et := range s.EnumeratedTypes() {
+		g.generateEnum(b, et)
+	}
+	for _, dt := range s.DomainTypes() {
+		g.generateDomain(b, dt)
+	}
 	for _, t := range s.Tables {
+		// Partition is accessible through repository of the partitioned (parent) table.
+		if t.IsPartition() {
+			continue
+		}
 		g.generateConstants(b, t)
+		if err := g.generateDDL(b, t); err != nil {
+			return nil, err
+		}
 		g.generateColumns(b, t)
 		g.generateEntity(b, t)
 		g.generateEntityProp(b, t)
 		g.generateEntityProps(b, t)
+		g.generateEntityValue(b, t)
+		g.generateEntityValues(b, t)
+		g.generateEntityToMap(b, t)
+		g.generateEntityFromMap(b, t)
+		// Materialized view is read only, so types used to modify it are not generated.
+		if !t.IsMaterializedView() {
+			g.generateEntityValidate(b, t)
+		}
 		g.generateIterator(b, t)
 		g.generateCriteria(b, t)
 		g.generateCriteriaWriteComposition(b, t)
-		g.generatePatch(b, t)
+		g.generateCriteriaWhereClause(b, t)
+		if !t.IsMaterializedView() {
+			g.generatePatch(b, t)
+			g.generatePatchValidate(b, t)
+			g.generateReturning(b, t)
+		}
+		g.generatePage(b, t)
+		g.generateLateral(b, t)
+		if !t.IsMaterializedView() {
+			g.generateHooks(b, t)
+		}
 		g.generateRepository(b, t)
+		g.generateRepositoryInterface(b, t)
+		g.generateRepositoryMock(b, t)
+		g.generateRepositoryCache(b, t)
 	}
 
 	return b, nil
@@ -177,6 +341,9 @@ func (g *Generator) generateImports(code *bytes.Buffer, schema *pqt.Schema) {
 		"github.com/go-kit/kit/log",
 		"github.com/m4rw3r/uuid",
 	}
+	if g.driver == DriverPgx {
+		imports = append(imports, "github.com/jackc/pgx/v5", "github.com/jackc/pgx/v5/pgconn")
+	}
 	imports = append(imports, g.imports...)
 	for _, t := range schema.Tables {
 		for _, c := range t.Columns {
@@ -185,11 +352,23 @@ func (g *Generator) generateImports(code *bytes.Buffer, schema *pqt.Schema) {
 				imports = append(imports, ct.mandatoryTypeOf.PkgPath())
 				imports = append(imports, ct.mandatoryTypeOf.PkgPath())
 			}
+			if mt, ok := c.Type.(pqt.MappableType); ok {
+				for _, mapto := range mt.Mapping {
+					if ct, ok := mapto.(CustomType); ok && ct.json {
+						imports = append(imports, ct.pkgPaths()...)
+					}
+				}
+			}
 		}
 	}
 
+	seen := make(map[string]struct{}, len(imports))
 	code.WriteString("import (\n")
 	for _, imp := range imports {
+		if _, ok := seen[imp]; ok || imp == "" {
+			continue
+		}
+		seen[imp] = struct{}{}
 		code.WriteRune('"')
 		fmt.Fprint(code, imp)
 		code.WriteRune('"')
@@ -198,10 +377,40 @@ func (g *Generator) generateImports(code *bytes.Buffer, schema *pqt.Schema) {
 	code.WriteString(")\n")
 }
 
+// generatePgxQuerier generates interface that generated repositories use to access the database if pgx driver is used.
+func (g *Generator) generatePgxQuerier(w io.Writer) {
+	if g.driver != DriverPgx {
+		return
+	}
+	fmt.Fprintf(w, `
+// %s is a common subset of *pgxpool.Pool, *pgxpool.Conn, *pgx.Conn and pgx.Tx methods that generated repositories rely on.
+type %s interface {
+	Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row
+	CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error)
+}
+`, g.name("pgxQuerier"), g.name("pgxQuerier"))
+}
+
 func (g *Generator) generateEntity(w io.Writer, t *pqt.Table) {
-	fmt.Fprintf(w, "type %sEntity struct{\n", g.name(t.Name))
+	if t.Comment != "" {
+		generateComment(w, t.Comment)
+	}
+	// Durability of such tables differs from ordinary ones, which is not obvious at the call site.
+	switch {
+	case t.Temporary:
+		io.WriteString(w, "// Table is temporary, rows are visible only within the database session that created them and dropped when it ends.\n")
+	case t.Unlogged:
+		io.WriteString(w, "// Table is unlogged, rows are not written to the write-ahead log, are not replicated and are lost after a crash.\n")
+	}
+	fmt.Fprintf(w, "type %sEntity struct{\n", g.name(tableIdent(t)))
 	for prop := range g.entityPropertiesGenerator(t) {
-		fmt.Fprintf(w, "// %s ...\n", g.name(prop.Name))
+		if prop.Comment != "" {
+			generateComment(w, prop.Comment)
+		} else {
+			fmt.Fprintf(w, "// %s ...\n", g.name(prop.Name))
+		}
 		if prop.Tags != "" {
 			fmt.Fprintf(w, "%s %s %s\n", g.name(prop.Name), prop.Type, prop.Tags)
 		} else {
@@ -219,32 +428,32 @@ func (g *Generator) entityPropertiesGenerator(t *pqt.Table) chan structField {
 	go func(out chan structField) {
 		for _, c := range t.Columns {
 			if t := g.generateColumnTypeString(c, modeDefault); t != "<nil>" {
-				out <- structField{Name: g.propertyName(c.Name), Type: t}
+				out <- structField{Name: g.propertyName(c.Name), Type: t, Tags: g.fieldTagsOf(c), Comment: c.Comment}
 			}
 		}
 
 		for _, r := range t.OwnedRelationships {
 			switch r.Type {
 			case pqt.RelationshipTypeOneToMany:
-				out <- structField{Name: g.propertyName(or(r.InversedName, r.InversedTable.Name+"s")), Type: fmt.Sprintf("[]*%sEntity", g.name(r.InversedTable.Name))}
+				out <- structField{Name: g.propertyName(or(r.InversedName, r.InversedTable.Name+"s")), Type: fmt.Sprintf("[]*%sEntity", g.name(tableIdent(r.InversedTable)))}
 			case pqt.RelationshipTypeOneToOne:
-				out <- structField{Name: g.propertyName(or(r.InversedName, r.InversedTable.Name)), Type: fmt.Sprintf("*%sEntity", g.name(r.InversedTable.Name))}
+				out <- structField{Name: g.propertyName(or(r.InversedName, r.InversedTable.Name)), Type: fmt.Sprintf("*%sEntity", g.name(tableIdent(r.InversedTable)))}
 			case pqt.RelationshipTypeManyToOne:
-				out <- structField{Name: g.propertyName(or(r.InversedName, r.InversedTable.Name)), Type: fmt.Sprintf("*%sEntity", g.name(r.InversedTable.Name))}
+				out <- structField{Name: g.propertyName(or(r.InversedName, r.InversedTable.Name)), Type: fmt.Sprintf("*%sEntity", g.name(tableIdent(r.InversedTable)))}
 			case pqt.RelationshipTypeManyToMany:
-				out <- structField{Name: g.propertyName(or(r.OwnerName, r.OwnerTable.Name)), Type: fmt.Sprintf("*%sEntity", g.name(r.OwnerTable.Name))}
-				out <- structField{Name: g.propertyName(or(r.InversedName, r.InversedTable.Name)), Type: fmt.Sprintf("*%sEntity", g.name(r.InversedTable.Name))}
+				out <- structField{Name: g.propertyName(or(r.OwnerName, r.OwnerTable.Name)), Type: fmt.Sprintf("*%sEntity", g.name(tableIdent(r.OwnerTable)))}
+				out <- structField{Name: g.propertyName(or(r.InversedName, r.InversedTable.Name)), Type: fmt.Sprintf("*%sEntity", g.name(tableIdent(r.InversedTable)))}
 			}
 		}
 
 		for _, r := range t.InversedRelationships {
 			switch r.Type {
 			case pqt.RelationshipTypeOneToMany:
-				out <- structField{Name: g.propertyName(or(r.OwnerName, r.OwnerTable.Name)), Type: fmt.Sprintf("*%sEntity", g.name(r.OwnerTable.Name))}
+				out <- structField{Name: g.propertyName(or(r.OwnerName, r.OwnerTable.Name)), Type: fmt.Sprintf("*%sEntity", g.name(tableIdent(r.OwnerTable)))}
 			case pqt.RelationshipTypeOneToOne:
-				out <- structField{Name: g.propertyName(or(r.OwnerName, r.OwnerTable.Name)), Type: fmt.Sprintf("*%sEntity", g.name(r.OwnerTable.Name))}
+				out <- structField{Name: g.propertyName(or(r.OwnerName, r.OwnerTable.Name)), Type: fmt.Sprintf("*%sEntity", g.name(tableIdent(r.OwnerTable)))}
 			case pqt.RelationshipTypeManyToOne:
-				out <- structField{Name: g.propertyName(or(r.OwnerName, r.OwnerTable.Name+"s")), Type: fmt.Sprintf("[]*%sEntity", g.name(r.OwnerTable.Name))}
+				out <- structField{Name: g.propertyName(or(r.OwnerName, r.OwnerTable.Name+"s")), Type: fmt.Sprintf("[]*%sEntity", g.name(tableIdent(r.OwnerTable)))}
 			}
 		}
 
@@ -255,9 +464,9 @@ func (g *Generator) entityPropertiesGenerator(t *pqt.Table) chan structField {
 
 			switch {
 			case r.OwnerTable == t:
-				out <- structField{Name: g.propertyName(or(r.InversedName, r.InversedTable.Name+"s")), Type: fmt.Sprintf("[]*%sEntity", g.name(r.InversedTable.Name))}
+				out <- structField{Name: g.propertyName(or(r.InversedName, r.InversedTable.Name+"s")), Type: fmt.Sprintf("[]*%sEntity", g.name(tableIdent(r.InversedTable)))}
 			case r.InversedTable == t:
-				out <- structField{Name: g.propertyName(or(r.OwnerName, r.OwnerTable.Name+"s")), Type: fmt.Sprintf("[]*%sEntity", g.name(r.OwnerTable.Name))}
+				out <- structField{Name: g.propertyName(or(r.OwnerName, r.OwnerTable.Name+"s")), Type: fmt.Sprintf("[]*%sEntity", g.name(tableIdent(r.OwnerTable)))}
 			}
 		}
 
@@ -268,14 +477,14 @@ func (g *Generator) entityPropertiesGenerator(t *pqt.Table) chan structField {
 }
 
 func (g *Generator) generateEntityProp(w io.Writer, t *pqt.Table) {
-	fmt.Fprintf(w, "func (e *%sEntity) %s(cn string) (interface{}, bool) {\n", g.name(t.Name), g.name("Prop"))
+	fmt.Fprintf(w, "func (e *%sEntity) %s(cn string) (interface{}, bool) {\n", g.name(tableIdent(t)), g.name("Prop"))
 	fmt.Fprintln(w, "switch cn {")
 	for _, c := range t.Columns {
-		fmt.Fprintf(w, "case %s:\n", g.columnNameWithTableName(t.Name, c.Name))
+		fmt.Fprintf(w, "case %s:\n", g.columnNameWithTableName(tableIdent(t), c.Name))
 		if g.canBeNil(c, modeDefault) {
 			fmt.Fprintf(w, "return e.%s, true\n", g.propertyName(c.Name))
 		} else {
-			fmt.Fprintf(w, "return &e.%s, true\n", g.propertyName(c.Name))
+			fmt.Fprintf(w, "return %s, true\n", g.scanTarget("e", c))
 		}
 	}
 	fmt.Fprint(w, "default:\n")
@@ -284,7 +493,7 @@ func (g *Generator) generateEntityProp(w io.Writer, t *pqt.Table) {
 }
 
 func (g *Generator) generateEntityProps(w io.Writer, t *pqt.Table) {
-	fmt.Fprintf(w, "func (e *%sEntity) %s(cns ...string) ([]interface{}, error) {\n", g.name(t.Name), g.name("Props"))
+	fmt.Fprintf(w, "func (e *%sEntity) %s(cns ...string) ([]interface{}, error) {\n", g.name(tableIdent(t)), g.name("Props"))
 	fmt.Fprintf(w, `
 		res := make([]interface{}, 0, len(cns))
 		for _, cn := range cns {
@@ -297,48 +506,252 @@ func (g *Generator) generateEntityProps(w io.Writer, t *pqt.Table) {
 		return res, nil`, g.name("prop"))
 	fmt.Fprint(w, "\n}\n")
 }
+
+// generateEntityValue generates counterpart of the prop method that returns value of the property instead of pointer to it,
+// so it can be passed as a query argument.
+func (g *Generator) generateEntityValue(w io.Writer, t *pqt.Table) {
+	fmt.Fprintf(w, "func (e *%sEntity) %s(cn string) (interface{}, bool) {\n", g.name(tableIdent(t)), g.name("Value"))
+	fmt.Fprintln(w, "switch cn {")
+	for _, c := range t.Columns {
+		fmt.Fprintf(w, "case %s:\n", g.columnNameWithTableName(tableIdent(t), c.Name))
+		fmt.Fprintf(w, "return %s, true\n", g.argument("e", c))
+	}
+	fmt.Fprint(w, "default:\n")
+	fmt.Fprint(w, "return nil, false\n")
+	fmt.Fprint(w, "}\n}\n")
+}
+
+func (g *Generator) generateEntityValues(w io.Writer, t *pqt.Table) {
+	fmt.Fprintf(w, "func (e *%sEntity) %s(cns ...string) ([]interface{}, error) {\n", g.name(tableIdent(t)), g.name("Values"))
+	fmt.Fprintf(w, `
+		res := make([]interface{}, 0, len(cns))
+		for _, cn := range cns {
+			if value, ok := e.%s(cn); ok {
+				res = append(res, value)
+			} else {
+				return nil, fmt.Errorf("unexpected column provided: %%s", cn)
+			}
+		}
+		return res, nil`, g.name("value"))
+	fmt.Fprint(w, "\n}\n\n")
+}
+
+// generateEntityToMap generates method that returns values of the entity keyed by column names.
+// Nullable properties are dereferenced, so the map holds either underlying value or nil.
+func (g *Generator) generateEntityToMap(w io.Writer, t *pqt.Table) {
+	fmt.Fprintf(w, `// %s returns values of the entity keyed by column names, nullable properties are either underlying value or nil.
+func (e *%sEntity) %s() map[string]interface{} {
+	m := make(map[string]interface{}, %d)
+`, g.name("toMap"), g.name(tableIdent(t)), g.name("toMap"), len(t.Columns))
+	for _, c := range t.Columns {
+		typ := g.generateColumnTypeString(c, modeDefault)
+		if typ == "<nil>" || typ == "" {
+			continue
+		}
+		prop, cn := g.propertyName(c.Name), g.columnNameWithTableName(tableIdent(t), c.Name)
+		switch {
+		case strings.HasPrefix(typ, "*ntypes."):
+			fmt.Fprintf(w, `if e.%s != nil && e.%s.Valid {
+		m[%s] = e.%s.%s
+	} else {
+		m[%s] = nil
+	}
+`, prop, prop, cn, prop, strings.TrimPrefix(typ, "*ntypes."), cn)
+		case strings.HasPrefix(typ, "*"):
+			fmt.Fprintf(w, `if e.%s != nil {
+		m[%s] = *e.%s
+	} else {
+		m[%s] = nil
+	}
+`, prop, cn, prop, cn)
+		default:
+			fmt.Fprintf(w, "m[%s] = e.%s\n", cn, prop)
+		}
+	}
+	fmt.Fprint(w, "\nreturn m\n}\n\n")
+}
+
+// generateEntityFromMap generates counterpart of the toMap method that builds entity from values keyed by column names.
+func (g *Generator) generateEntityFromMap(w io.Writer, t *pqt.Table) {
+	entityName := g.name(tableIdent(t))
+	fmt.Fprintf(w, `// %sEntityFromMap returns entity built from given values keyed by column names, missing columns are left unset.
+// Error is returned if column is unknown or its value is not of the expected type, nil is accepted by nullable columns only.
+func %sEntityFromMap(m map[string]interface{}) (*%sEntity, error) {
+	e := &%sEntity{}
+	for cn, v := range m {
+		switch cn {
+`, entityName, entityName, entityName, entityName)
+	for _, c := range t.Columns {
+		typ := g.generateColumnTypeString(c, modeDefault)
+		if typ == "<nil>" || typ == "" {
+			continue
+		}
+		prop := g.propertyName(c.Name)
+		fmt.Fprintf(w, "case %s:\n", g.columnNameWithTableName(tableIdent(t), c.Name))
+		switch {
+		case strings.HasPrefix(typ, "*ntypes."):
+			name := strings.TrimPrefix(typ, "*ntypes.")
+			fmt.Fprintf(w, `if v == nil {
+				e.%s = nil
+				continue
+			}
+			if tv, ok := v.(%s); ok {
+				e.%s = &ntypes.%s{%s: tv, Valid: true}
+				continue
+			}
+			return nil, fmt.Errorf("%s from map failure, column %%s expects value of type %s, got %%T", cn, v)
+`, prop, strings.ToLower(name), prop, name, name, entityName, strings.ToLower(name))
+		case strings.HasPrefix(typ, "*"):
+			fmt.Fprintf(w, `if v == nil {
+				e.%s = nil
+				continue
+			}
+			if tv, ok := v.(%s); ok {
+				e.%s = &tv
+				continue
+			}
+			return nil, fmt.Errorf("%s from map failure, column %%s expects value of type %s, got %%T", cn, v)
+`, prop, typ[1:], prop, entityName, typ[1:])
+		default:
+			fmt.Fprintf(w, `if tv, ok := v.(%s); ok {
+				e.%s = tv
+				continue
+			}
+			return nil, fmt.Errorf("%s from map failure, column %%s expects value of type %s, got %%T", cn, v)
+`, typ, prop, entityName, typ)
+		}
+	}
+	fmt.Fprintf(w, `default:
+			return nil, fmt.Errorf("%s from map failure, unexpected column provided: %%s", cn)
+		}
+	}
+
+	return e, nil
+}
+
+`, entityName)
+}
+
+// generateEntityValidate generates method that checks if mandatory properties of the entity are set.
+// Column is mandatory if it is NOT NULL and the database does not provide its value.
+// Boolean and numeric properties are not checked, zero is a legitimate value of those.
+func (g *Generator) generateEntityValidate(w io.Writer, t *pqt.Table) {
+	fmt.Fprintf(w, `// %s returns pqt.ValidationError if any of mandatory properties is not set.
+func (e *%sEntity) %s() error {
+	var violations []pqt.Violation
+`, g.name("validate"), g.name(tableIdent(t)), g.name("validate"))
+	for _, c := range t.Columns {
+		if !c.NotNull || c.PrimaryKey || c.Generated != "" || c.Identity != "" {
+			continue
+		}
+		if _, ok := c.DefaultOn(pqt.EventInsert); ok {
+			continue
+		}
+		cond := g.zeroCondition("e", c)
+		if cond == "" {
+			continue
+		}
+		fmt.Fprintf(w, `if %s {
+		violations = append(violations, pqt.Violation{Column: %s, Reason: "is required"})
+	}
+`, cond, g.columnNameWithTableName(tableIdent(t), c.Name))
+	}
+	g.generateValidationResult(w, t)
+}
+
+// generatePatchValidate generates method that checks if patch does not set NULL to any of NOT NULL columns.
+func (g *Generator) generatePatchValidate(w io.Writer, t *pqt.Table) {
+	fmt.Fprintf(w, `// %s returns pqt.ValidationError if patch sets NULL to any of NOT NULL columns.
+func (p *%sPatch) %s() error {
+	var violations []pqt.Violation
+`, g.name("validate"), g.name(tableIdent(t)), g.name("validate"))
+	for _, c := range t.Columns {
+		if !c.NotNull || c.Generated != "" {
+			continue
+		}
+		if !strings.HasPrefix(g.generateColumnTypeString(c, modeOptional), "*ntypes.") {
+			continue
+		}
+		fmt.Fprintf(w, `if p.%s != nil && !p.%s.Valid {
+		violations = append(violations, pqt.Violation{Column: %s, Reason: "cannot be null"})
+	}
+`, g.propertyName(c.Name), g.propertyName(c.Name), g.columnNameWithTableName(tableIdent(t), c.Name))
+	}
+	g.generateValidationResult(w, t)
+}
+
+func (g *Generator) generateValidationResult(w io.Writer, t *pqt.Table) {
+	fmt.Fprintf(w, `if len(violations) > 0 {
+		return &pqt.ValidationError{Table: %s%s, Violations: violations}
+	}
+
+	return nil
+}
+
+`, g.name("table"), g.public(tableIdent(t)))
+}
+
 func (g *Generator) generateIterator(w io.Writer, t *pqt.Table) {
-	entityName := g.name(t.Name)
+	entityName := g.name(tableIdent(t))
+	closeRows, wrapped, source := "return i.rows.Close()", "sql.Rows.Columns", "rows.Columns"
+	columns := `cols, err := i.rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		i.cols = cols`
+	if g.driver == DriverPgx {
+		closeRows = `i.rows.Close()
+	return i.rows.Err()`
+		// Rows of pgx expose field descriptions only, so names are collected by Columns.
+		wrapped, source = "pgx.Rows.FieldDescriptions", "Columns"
+		columns = `for _, fd := range i.rows.FieldDescriptions() {
+			i.cols = append(i.cols, fd.Name)
+		}`
+	}
 	fmt.Fprintf(w, `
 
 // %sIterator is not thread safe.
+// It has to be closed once it is no longer needed, otherwise underlying connection is not released.
 type %sIterator struct {
-	rows *sql.Rows
+	rows %s
 	cols []string
+	// cancel releases context the rows are read within, if the iterator owns it.
+	cancel context.CancelFunc
 }
 
+var _ pqtgo.Iterator = &%sIterator{}
+
 func (i *%sIterator) Next() bool {
 	return i.rows.Next()
 }
 
 func (i *%sIterator) Close() error {
-	return i.rows.Close()
+	if i.cancel != nil {
+		defer i.cancel()
+	}
+	%s
 }
 
 func (i *%sIterator) Err() error {
 	return i.rows.Err()
 }
 
-// Columns is wrapper around sql.Rows.Columns method, that also cache outpu inside iterator.
+// Columns is wrapper around %s method, that also cache outpu inside iterator.
 func (i *%sIterator) Columns() ([]string, error) {
 	if i.cols == nil {
-		cols, err := i.rows.Columns()
-		if err != nil {
-			return nil, err
-		}
-		i.cols = cols
+		%s
 	}
 	return i.cols, nil
 }
 
-// Ent is wrapper around %s method that makes iterator more generic.
-func (i *%sIterator) Ent() (interface{}, error) {
+// Ent is wrapper around %s method, it allows to use the same method name regardless of the table.
+func (i *%sIterator) Ent() (*%sEntity, error) {
 	return i.%s()
 }
 
 func (i *%sIterator) %s() (*%sEntity, error) {
 	var ent %sEntity
-	cols, err := i.rows.Columns()
+	cols, err := i.%s()
 	if err != nil {
 		return nil, err
 	}
@@ -352,13 +765,32 @@ func (i *%sIterator) %s() (*%sEntity, error) {
 	}
 	return &ent, nil
 }
-`, entityName, entityName, entityName, entityName, entityName, entityName, entityName, entityName, g.public(t.Name), entityName, g.public(t.Name), entityName, entityName, g.name("props"))
+`, entityName, entityName, g.rowsType(), entityName, entityName, entityName, closeRows, entityName, wrapped, entityName, columns, entityName, entityName, entityName, g.public(tableIdent(t)), entityName, g.public(tableIdent(t)), entityName, entityName, source, g.name("props"))
 }
 
 func (g *Generator) generateCriteria(w io.Writer, t *pqt.Table) {
-	fmt.Fprintf(w, "type %sCriteria struct {\n", g.name(t.Name))
-	fmt.Fprintf(w, "%s, %s int64\n", g.name("offset"), g.name("limit"))
+	fmt.Fprintf(w, `// %sCriteria selects entities that queries of the repository operate on, its zero value selects all of them.
+// %s and %s are translated into OFFSET and LIMIT clauses if non-zero, %s appends locking clause that is effective within a transaction only.
+`, g.name(tableIdent(t)), g.public("offset"), g.public("limit"), g.name("lock"))
+	if g.strictSort {
+		fmt.Fprintf(w, "// Keys of %s have to be columns of the table.\n", g.name("sort"))
+	}
+	fmt.Fprintf(w, "// Expressions of %s precede %s, they can refer to columns of the table and call functions listed in pqt.SortFunctions.\n", g.name("sortExpr"), g.name("sort"))
+	if t.SoftDelete {
+		fmt.Fprintf(w, "// Entities marked as deleted are skipped unless %s is set.\n", g.name("includeDeleted"))
+	}
+	fmt.Fprintf(w, "type %sCriteria struct {\n", g.name(tableIdent(t)))
+	fmt.Fprintf(w, "%s, %s int64\n", g.public("offset"), g.public("limit"))
 	fmt.Fprintf(w, "%s map[string]bool\n", g.name("sort"))
+	fmt.Fprintf(w, "%s []pqt.SortExpr\n", g.name("sortExpr"))
+	fmt.Fprintf(w, "%s string\n", g.name("countDistinct"))
+	fmt.Fprintf(w, "%s pqt.LockMode\n", g.name("lock"))
+	if g.joins {
+		fmt.Fprintf(w, "%s []pqt.LateralJoin\n", g.name("lateral"))
+	}
+	if t.SoftDelete {
+		fmt.Fprintf(w, "%s bool\n", g.name("includeDeleted"))
+	}
 
 ColumnLoop:
 	for _, c := range t.Columns {
@@ -368,17 +800,20 @@ ColumnLoop:
 
 		if t := g.generateColumnTypeString(c, modeCriteria); t != "<nil>" {
 			fmt.Fprintf(w, "%s %s\n", g.propertyName(c.Name), t)
+			if t == "*qtypes.Timestamp" {
+				fmt.Fprintf(w, "%s pqt.RangeBounds\n", g.propertyName(c.Name+"_bounds"))
+			}
 		}
 	}
 	fmt.Fprint(w, "}\n\n")
 }
 
 func (g *Generator) generatePatch(w io.Writer, t *pqt.Table) {
-	fmt.Fprintf(w, "type %sPatch struct {\n", g.name(t.Name))
+	fmt.Fprintf(w, "type %sPatch struct {\n", g.name(tableIdent(t)))
 
 ArgumentsLoop:
 	for _, c := range t.Columns {
-		if c.PrimaryKey {
+		if c.PrimaryKey || c.Generated != "" || c.Identity == pqt.IdentityAlways {
 			continue ArgumentsLoop
 		}
 
@@ -389,6 +824,208 @@ ArgumentsLoop:
 	fmt.Fprint(w, "}\n\n")
 }
 
+func (g *Generator) generatePage(w io.Writer, t *pqt.Table) {
+	fmt.Fprintf(w, `// %sPage is a single page of entities returned by %s method.
+// Cursors of the first and the last entity allow to request adjacent pages.
+type %sPage struct {
+	%s []*%sEntity
+	%s, %s bool
+	%s, %s string
+}
+
+`, g.name(tableIdent(t)), g.methodName("FindPage"), g.name(tableIdent(t)),
+		g.name("Edges"), g.name(tableIdent(t)),
+		g.name("HasNextPage"), g.name("HasPrevPage"),
+		g.name("StartCursor"), g.name("EndCursor"))
+}
+
+func (g *Generator) generateLateral(w io.Writer, t *pqt.Table) {
+	if !g.joins {
+		return
+	}
+	fmt.Fprintf(w, `// %sLateral is a single row returned by %s method.
+// Values of columns of lateral joins are keyed by alias.column.
+type %sLateral struct {
+	%s *%sEntity
+	%s map[string]interface{}
+}
+
+`, g.name(tableIdent(t)), g.methodName("FindLateral"), g.name(tableIdent(t)),
+		g.name("Entity"), g.name(tableIdent(t)),
+		g.name("Lateral"))
+}
+
+// generateHooks generates function types of hooks that repository calls around insert, update and delete of a single entity.
+// Update and delete hooks are generated only for tables with primary key, as only such tables get these methods.
+func (g *Generator) generateHooks(w io.Writer, t *pqt.Table) {
+	entityName := g.name(tableIdent(t))
+
+	// Hooks get the primary key or the whole entity, so methods that have neither of them at hand do not call them.
+	var updateByUnique, updateOrInsert, conflicts []string
+	for _, u := range tableConstraints(t) {
+		if u.Type != pqt.ConstraintTypeUnique || u.Where != "" {
+			continue
+		}
+		var suffix string
+		for i, c := range u.Columns {
+			if i != 0 {
+				suffix += "And"
+			}
+			suffix += g.public(c.Name)
+		}
+		updateByUnique = append(updateByUnique, "UpdateOneBy"+suffix)
+		if g.ver >= 9.5 {
+			updateOrInsert = append(updateOrInsert, "UpdateOrInsertBy"+suffix)
+		}
+	}
+	if g.ver >= 9.5 {
+		conflicts = append([]string{"UpsertOn", "Upsert"}, updateOrInsert...)
+	}
+
+	beforeInsert := []string{"Insert"}
+	afterInsert := []string{"Insert"}
+	var skipInsert []string
+	if len(t.Returning) > 0 {
+		beforeInsert = append(beforeInsert, "InsertReturning")
+		skipInsert = append(skipInsert, "InsertReturning")
+	}
+	beforeInsert = append(beforeInsert, "InsertReturningColumns")
+	skipInsert = append(skipInsert, "InsertReturningColumns")
+	if len(insertBatchColumns(t)) > 0 {
+		beforeInsert = append(beforeInsert, "InsertBatch")
+		afterInsert = append(afterInsert, "InsertBatch")
+	}
+	if len(batchColumns(t)) > 0 {
+		beforeInsert = append(beforeInsert, "BulkInsert")
+		skipInsert = append(skipInsert, "BulkInsert")
+	}
+	beforeInsert = append(beforeInsert, conflicts...)
+	afterInsert = append(afterInsert, updateOrInsert...)
+
+	fmt.Fprintf(w, `// %sBeforeInsertHook is called by %s before the entity is stored, it can modify the entity, e.g. set columns from the context.
+// Returned error aborts the insert.
+type %sBeforeInsertHook func(ctx context.Context, e *%sEntity) error
+
+// %sAfterInsertHook is called by %s after the entity is stored, with values returned by the database.
+`, entityName, g.methodList(beforeInsert), entityName, entityName,
+		entityName, g.methodList(afterInsert))
+	if len(skipInsert) > 0 {
+		fmt.Fprintf(w, "// It is not called by %s, they do not read back the whole entity.\n", g.methodList(skipInsert))
+	}
+	if len(conflicts) > 0 {
+		fmt.Fprintf(w, "// It is not called by %s either, they cannot tell inserted row from updated one.\n", g.methodList(conflicts[:2]))
+	}
+	fmt.Fprintf(w, `// Returned error is passed to the caller, but the entity is already stored, unless insert is executed within a transaction that is rolled back.
+type %sAfterInsertHook func(ctx context.Context, e *%sEntity) error
+
+`, entityName, entityName)
+
+	pk, ok := primaryKey(t)
+	if !ok {
+		return
+	}
+	suffix, arguments, _, _ := g.keyArguments(pk)
+
+	beforeUpdate := []string{"UpdateOneBy" + suffix}
+	if len(t.Returning) > 0 {
+		beforeUpdate = append(beforeUpdate, "UpdateOneBy"+suffix+"Returning")
+	}
+	beforeUpdate = append(beforeUpdate, "UpdateOneBy"+suffix+"ReturningColumns", "PatchOneBy"+suffix)
+	afterUpdate := append(append([]string{"UpdateOneBy" + suffix}, updateByUnique...), updateOrInsert...)
+	skipUpdate := beforeUpdate[1:]
+	skipBeforeUpdate := append(append([]string(nil), updateByUnique...), conflicts...)
+	deletes := []string{deleteMethod(t, suffix), deleteAndReturnMethod(t, suffix)}
+	if t.SoftDelete {
+		deletes = append(deletes, "SoftDeleteOneBy"+suffix)
+	}
+
+	fmt.Fprintf(w, "// %sBeforeUpdateHook is called by %s before the entity is modified, it can modify the patch.\n", entityName, g.methodList(beforeUpdate))
+	if len(skipBeforeUpdate) > 0 {
+		fmt.Fprintf(w, "// It is not called by %s, they do not know the primary key in advance.\n", g.methodList(skipBeforeUpdate))
+	}
+	fmt.Fprintf(w, `// Returned error aborts the update.
+type %sBeforeUpdateHook func(ctx context.Context, %s, patch *%sPatch) error
+
+// %sAfterUpdateHook is called by %s after the entity is modified, with values returned by the database.
+// It is not called by %s, they do not read back the whole entity.
+`, entityName, arguments, entityName,
+		entityName, g.methodList(afterUpdate), g.methodList(skipUpdate))
+	if len(conflicts) > 0 {
+		fmt.Fprintf(w, "// It is not called by %s either, they cannot tell inserted row from updated one.\n", g.methodList(conflicts[:2]))
+	}
+	fmt.Fprintf(w, `// Returned error is passed to the caller, but the entity is already modified, unless update is executed within a transaction that is rolled back.
+type %sAfterUpdateHook func(ctx context.Context, e *%sEntity) error
+
+`, entityName, entityName)
+
+	fmt.Fprintf(w, `// %sBeforeDeleteHook is called by %s before the entity is removed.
+// It is not called by %s.
+// Returned error aborts the delete.
+type %sBeforeDeleteHook func(ctx context.Context, %s) error
+
+// %sAfterDeleteHook is called by %s after the entity is removed, it is not called if there was no such entity.
+// It is not called by %s.
+// Returned error is passed to the caller, but the entity is already removed, unless delete is executed within a transaction that is rolled back.
+type %sAfterDeleteHook func(ctx context.Context, %s) error
+
+`, entityName, g.methodList(deletes), g.methodName("DeleteByCriteria"), entityName, arguments,
+		entityName, g.methodList(deletes), g.methodName("DeleteByCriteria"), entityName, arguments)
+}
+
+// methodList returns names of given repository methods as an enumeration, e.g. "insert, insertBatch and upsert".
+func (g *Generator) methodList(methods []string) string {
+	names := make([]string, 0, len(methods))
+	for _, m := range methods {
+		names = append(names, g.methodName(m))
+	}
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// deleteMethod returns name of the method that removes entity by primary key.
+// If soft delete is enabled, name makes it clear that the row is removed for good.
+func deleteMethod(t *pqt.Table, suffix string) string {
+	if t.SoftDelete {
+		return "HardDeleteOneBy" + suffix
+	}
+	return "DeleteOneBy" + suffix
+}
+
+// deleteAndReturnMethod returns name of the method that removes entity by primary key and returns it.
+func deleteAndReturnMethod(t *pqt.Table, suffix string) string {
+	return strings.Replace(deleteMethod(t, suffix), "OneBy", "AndReturnOneBy", 1)
+}
+
+// hookCall returns code that calls hook of given name, if it is set, ret is return statement prefix used to return an error.
+func (g *Generator) hookCall(hook, args, ret string) string {
+	ctx := "context.Background()"
+	if g.ctx {
+		ctx = "ctx"
+	}
+	return fmt.Sprintf(`if r.%s != nil {
+		if err := r.%s(%s, %s); err != nil {
+			%s err
+		}
+	}
+`, g.name(hook), g.name(hook), ctx, args, ret)
+}
+
+func (g *Generator) generateReturning(w io.Writer, t *pqt.Table) {
+	if len(t.Returning) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "type %sReturning struct {\n", g.name(tableIdent(t)))
+	for _, c := range t.Returning {
+		if typ := g.generateColumnTypeString(c, modeDefault); typ != "<nil>" {
+			fmt.Fprintf(w, "%s %s\n", g.propertyName(c.Name), typ)
+		}
+	}
+	fmt.Fprint(w, "}\n\n")
+}
+
 func (g *Generator) generateColumnTypeString(c *pqt.Column, m int32) string {
 	switch m {
 	case modeCriteria:
@@ -416,44 +1053,189 @@ func (g *Generator) generateType(t pqt.Type, m int32) string {
 		return generateBaseType(tt, m)
 	case CustomType:
 		return generateCustomType(tt, m)
+	case pqt.EnumeratedType:
+		name := g.enumName(tt)
+		return chooseType(name, "*"+name, "*"+name, m)
+	case pqt.DomainType:
+		// Criteria reuses type of the base, so that the same operators are available.
+		if !isDomainNamed(g.generateType(tt.Base, modeMandatory)) || m == modeCriteria {
+			return g.generateType(tt.Base, m)
+		}
+		name := g.domainName(tt)
+		return chooseType(name, "*"+name, "*"+name, m)
 	default:
 		return ""
 	}
 }
 
-func (g *Generator) generateConstants(code *bytes.Buffer, table *pqt.Table) {
-	code.WriteString("const (\n")
-	g.generateConstantsColumns(code, table)
-	g.generateConstantsConstraints(code, table)
-	code.WriteString(")\n")
+// generateEnum generates string based type for given enumerated type, together with constant for each of its values.
+func (g *Generator) generateEnum(w io.Writer, et pqt.EnumeratedType) {
+	name := g.enumName(et)
+
+	fmt.Fprintf(w, "type %s string\n\n", name)
+	consts := make([]string, 0, len(et.Enums))
+	fmt.Fprint(w, "const (\n")
+	for _, e := range et.Enums {
+		consts = append(consts, name+g.public(identifier(e)))
+		fmt.Fprintf(w, "%s %s = %q\n", consts[len(consts)-1], name, e)
+	}
+	fmt.Fprint(w, ")\n\n")
+	fmt.Fprintf(w, `// Value implements driver.Valuer interface.
+func (e %s) Value() (driver.Value, error) {
+	return string(e), nil
 }
 
-func (g *Generator) generateConstantsColumns(w io.Writer, table *pqt.Table) {
-	fmt.Fprintf(w, `%s%s = "%s"
-	`, g.name("table"), g.public(table.Name), table.FullName())
+// Scan implements sql.Scanner interface.
+func (e *%s) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = %s(s)
+	case string:
+		*e = %s(s)
+	default:
+		return fmt.Errorf("%s: expected slice of bytes or string as a source argument in Scan, not %%T", src)
+	}
 
-	for _, name := range sortedColumns(table.Columns) {
-		fmt.Fprintf(w, `%s%sColumn%s = "%s"
-		`, g.name("table"), g.public(table.Name), g.public(name), name)
+	return nil
+}
+
+// %s returns true if value is one of the values of the enumerated type.
+func (e %s) %s() bool {
+	switch e {
+	case %s:
+		return true
+	default:
+		return false
 	}
 }
 
-func (g *Generator) generateConstantsConstraints(w io.Writer, table *pqt.Table) {
-	for _, c := range tableConstraints(table) {
-		name := fmt.Sprintf("%s", pqt.JoinColumns(c.Columns, "_"))
-		switch c.Type {
-		case pqt.ConstraintTypeCheck:
-			fmt.Fprintf(w, `%s%sConstraint%sCheck = "%s"`, g.name("table"), g.public(table.Name), g.public(name), c.String())
-		case pqt.ConstraintTypePrimaryKey:
-			fmt.Fprintf(w, `%s%sConstraintPrimaryKey = "%s"`, g.name("table"), g.public(table.Name), c.String())
-		case pqt.ConstraintTypeForeignKey:
-			fmt.Fprintf(w, `%s%sConstraint%sForeignKey = "%s"`, g.name("table"), g.public(table.Name), g.public(name), c.String())
-		case pqt.ConstraintTypeExclusion:
-			fmt.Fprintf(w, `%s%sConstraint%sExclusion = "%s"`, g.name("table"), g.public(table.Name), g.public(name), c.String())
+`, name, name, name, name, name, g.name("Valid"), name, g.name("Valid"), strings.Join(consts, ", "))
+}
+
+// generateDomain generates named type for given domain type, if its base is represented by a basic Go type.
+// Such type is handled by database/sql like its underlying type, so no Value or Scan method is needed.
+func (g *Generator) generateDomain(w io.Writer, dt pqt.DomainType) {
+	base := g.generateType(dt.Base, modeMandatory)
+	if !isDomainNamed(base) {
+		return
+	}
+
+	fmt.Fprintf(w, "// %s represents value of %s domain", g.domainName(dt), dt.String())
+	if dt.Check != "" {
+		fmt.Fprintf(w, ", it has to satisfy CHECK (%s)", dt.Check)
+	}
+	fmt.Fprintf(w, ".\ntype %s %s\n\n", g.domainName(dt), base)
+}
+
+// domainName returns name of the Go type generated for given domain type, schema prefix is omitted.
+func (g *Generator) domainName(dt pqt.DomainType) string {
+	name := dt.String()
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return g.name(identifier(name))
+}
+
+// isDomainNamed returns true if domain based on type represented by given Go type gets its own named type.
+func isDomainNamed(base string) bool {
+	switch base {
+	case "string", "bool", "int", "int16", "int32", "int64", "uint", "uint32", "uint64", "float32", "float64":
+		return true
+	default:
+		return false
+	}
+}
+
+// enumName returns name of the Go type generated for given enumerated type, schema prefix is omitted.
+func (g *Generator) enumName(et pqt.EnumeratedType) string {
+	name := et.String()
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return g.name(identifier(name))
+}
+
+// identifier replaces characters that are not allowed in Go identifier with underscores.
+func identifier(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+func (g *Generator) generateConstants(code *bytes.Buffer, table *pqt.Table) {
+	code.WriteString("const (\n")
+	g.generateConstantsColumns(code, table)
+	g.generateConstantsConstraints(code, table)
+	code.WriteString(")\n")
+}
+
+func (g *Generator) generateDDL(code *bytes.Buffer, table *pqt.Table) error {
+	if !g.ddl {
+		return nil
+	}
+	ddl, err := pqtsql.NewGenerator().GenerateTable(table)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(code, `
+// %s%sDDL returns DDL of the %s table, CREATE statement together with its indexes and comments.
+func %s%sDDL() string {
+	return %s
+}
+`, g.name("table"), g.public(tableIdent(table)), table.FullName(), g.name("table"), g.public(tableIdent(table)), quoteDDL(string(ddl)))
+
+	return nil
+}
+
+// queryName returns name of the table, quoted if necessary, that can be placed within interpreted string literal of a query.
+func queryName(t *pqt.Table) string {
+	q := strconv.Quote(t.QuotedName())
+	return q[1 : len(q)-1]
+}
+
+// quoteDDL returns raw string literal of the given DDL, unless it contains backquote that cannot be part of it.
+func quoteDDL(ddl string) string {
+	if strings.Contains(ddl, "`") {
+		return strconv.Quote(ddl)
+	}
+	return "`" + ddl + "`"
+}
+
+func (g *Generator) generateConstantsColumns(w io.Writer, table *pqt.Table) {
+	fmt.Fprintf(w, `%s%s = %s
+	`, g.name("table"), g.public(tableIdent(table)), strconv.Quote(table.QuotedName()))
+
+	for _, name := range sortedColumns(table.Columns) {
+		fmt.Fprintf(w, `%s%sColumn%s = "%s"
+		`, g.name("table"), g.public(tableIdent(table)), g.public(name), name)
+	}
+}
+
+func (g *Generator) generateConstantsConstraints(w io.Writer, table *pqt.Table) {
+	for _, c := range tableConstraints(table) {
+		name := fmt.Sprintf("%s", pqt.JoinColumns(c.Columns, "_"))
+		if c.Identifier != "" {
+			name = c.Identifier
+		}
+		switch c.Type {
+		case pqt.ConstraintTypeCheck:
+			fmt.Fprintf(w, `%s%sConstraint%sCheck = "%s"`, g.name("table"), g.public(tableIdent(table)), g.public(name), c.String())
+		case pqt.ConstraintTypePrimaryKey:
+			fmt.Fprintf(w, `%s%sConstraintPrimaryKey = "%s"`, g.name("table"), g.public(tableIdent(table)), c.String())
+		case pqt.ConstraintTypeForeignKey:
+			fmt.Fprintf(w, `%s%sConstraint%sForeignKey = "%s"`, g.name("table"), g.public(tableIdent(table)), g.public(name), c.String())
+		case pqt.ConstraintTypeExclusion:
+			fmt.Fprintf(w, `%s%sConstraint%sExclusion = "%s"`, g.name("table"), g.public(tableIdent(table)), g.public(name), c.String())
 		case pqt.ConstraintTypeUnique:
-			fmt.Fprintf(w, `%s%sConstraint%sUnique = "%s"`, g.name("table"), g.public(table.Name), g.public(name), c.String())
+			fmt.Fprintf(w, `%s%sConstraint%sUnique = "%s"`, g.name("table"), g.public(tableIdent(table)), g.public(name), c.String())
 		case pqt.ConstraintTypeIndex:
-			fmt.Fprintf(w, `%s%sConstraint%sIndex = "%s"`, g.name("table"), g.public(table.Name), g.public(name), c.String())
+			fmt.Fprintf(w, `%s%sConstraint%sIndex = "%s"`, g.name("table"), g.public(tableIdent(table)), g.public(name), c.String())
 		}
 
 		io.WriteString(w, "\n")
@@ -463,11 +1245,11 @@ func (g *Generator) generateConstantsConstraints(w io.Writer, table *pqt.Table)
 func (g *Generator) generateColumns(code *bytes.Buffer, table *pqt.Table) {
 	code.WriteString("var (\n")
 	code.WriteString(g.name("table"))
-	code.WriteString(g.public(table.Name))
+	code.WriteString(g.public(tableIdent(table)))
 	code.WriteString("Columns = []string{\n")
 
 	for _, name := range sortedColumns(table.Columns) {
-		g.writeTableNameColumnNameTo(code, table.Name, name)
+		g.writeTableNameColumnNameTo(code, tableIdent(table), name)
 		code.WriteRune(',')
 		code.WriteRune('\n')
 	}
@@ -477,42 +1259,235 @@ func (g *Generator) generateColumns(code *bytes.Buffer, table *pqt.Table) {
 
 func (g *Generator) generateRepository(b *bytes.Buffer, t *pqt.Table) {
 	fmt.Fprintf(b, `
-		type %sRepositoryBase struct {
+// %sRepositoryBase is a data access layer of the %s table.
+// Optional %s hook receives execution plans of read only queries in debug mode, optional planner builds conditions out of criteria instead of them.
+`, g.name(tableIdent(t)), t.FullName(), g.name("explain"))
+	if g.ctx {
+		b.WriteString("// Methods called without context are bounded by timeout, if it is set.\n")
+	}
+	fmt.Fprintf(b, `type %sRepositoryBase struct {
 			table string
 			columns []string
-			db *sql.DB
+			db %s
 			dbg bool
 			log log.Logger
+			bulkSize int
+			%s pqt.ExplainHook
+			planner pqt.Planner
+	`, g.name(tableIdent(t)), g.querierType(), g.name("explain"))
+	if !t.IsMaterializedView() {
+		fmt.Fprintf(b, "%s %sBeforeInsertHook\n%s %sAfterInsertHook\n", g.name("beforeInsert"), g.name(tableIdent(t)), g.name("afterInsert"), g.name(tableIdent(t)))
+		if _, ok := primaryKey(t); ok {
+			fmt.Fprintf(b, "%s %sBeforeUpdateHook\n%s %sAfterUpdateHook\n", g.name("beforeUpdate"), g.name(tableIdent(t)), g.name("afterUpdate"), g.name(tableIdent(t)))
+			fmt.Fprintf(b, "%s %sBeforeDeleteHook\n%s %sAfterDeleteHook\n", g.name("beforeDelete"), g.name(tableIdent(t)), g.name("afterDelete"), g.name(tableIdent(t)))
 		}
-	`, g.name(t.Name))
+	}
+	if g.prepared {
+		b.WriteString("stmts *pqtgo.StatementCache\n")
+	}
+	if g.ctx {
+		b.WriteString("timeout time.Duration\n")
+	}
+	b.WriteString("\t}\n\t")
+	g.methods = nil
+	g.generateRepositoryWithTx(b, t)
+	g.generateRepositoryExplain(b, t)
+	g.generateRepositoryPlan(b, t)
+	g.generateRepositoryDefaultContext(b, t)
+	g.generateRepositoryStatements(b, t)
 	g.generateRepositoryScanRows(b, t)
 	g.generateRepositoryCount(b, t)
+	g.generateRepositoryCountDistinct(b, t)
+	g.generateRepositoryExists(b, t)
+	g.generateRepositoryAggregate(b, t)
 	g.generateRepositoryFind(b, t)
 	g.generateRepositoryFindIter(b, t)
+	g.generateRepositoryFindPage(b, t)
+	g.generateRepositoryFindAndCount(b, t)
+	g.generateRepositoryFindIncludingDeleted(b, t)
+	g.generateRepositoryFindOne(b, t)
+	g.generateRepositoryFindWith(b, t)
+	g.generateRepositoryFindLateral(b, t)
 	g.generateRepositoryFindOneByPrimaryKey(b, t)
 	g.generateRepositoryFindOneByUniqueConstraint(b, t)
+	if t.IsMaterializedView() {
+		g.generateRepositoryRefresh(b, t)
+		return
+	}
 	g.generateRepositoryInsert(b, t)
+	g.generateRepositoryInsertReturning(b, t)
+	g.generateRepositoryInsertReturningColumns(b, t)
+	g.generateRepositoryInsertBatch(b, t)
+	g.generateRepositoryBulkInsert(b, t)
 	g.generateRepositoryUpsert(b, t)
 	g.generateRepositoryUpdateOneByPrimaryKey(b, t)
+	g.generateRepositoryUpdateOneByPrimaryKeyReturning(b, t)
+	g.generateRepositoryUpdateOneByPrimaryKeyReturningColumns(b, t)
+	g.generateRepositoryPatchOneByPrimaryKey(b, t)
 	g.generateRepositoryUpdateOneByUniqueConstraint(b, t)
+	g.generateRepositoryUpdateOrInsertByUniqueConstraint(b, t)
 	g.generateRepositoryDeleteOneByPrimaryKey(b, t)
+	g.generateRepositoryDeleteAndReturnOneByPrimaryKey(b, t)
+	g.generateRepositoryDeleteByCriteria(b, t)
+	g.generateRepositorySoftDelete(b, t)
+	g.generateRepositoryTruncate(b, t)
+}
+
+func (g *Generator) generateRepositoryWithTx(w io.Writer, t *pqt.Table) {
+	fmt.Fprintf(w, `// %s returns copy of the repository that executes all queries within given transaction.
+func (r *%sRepositoryBase) %s(tx %s) *%sRepositoryBase {
+	rt := *r
+	rt.db = tx
+`, g.name("WithTx"), g.name(tableIdent(t)), g.name("WithTx"), g.txType(), g.name(tableIdent(t)))
+	if g.prepared {
+		fmt.Fprint(w, `	// Cached statements are prepared outside of the transaction.
+	rt.stmts = nil
+`)
+	}
+	fmt.Fprint(w, `
+	return &rt
+}
+`)
+}
+
+// generateRepositoryRefresh generates method that refreshes materialized view.
+func (g *Generator) generateRepositoryRefresh(w io.Writer, t *pqt.Table) {
+	fmt.Fprintf(w, `// %s replaces content of the materialized view with fresh result of its query.
+// Concurrent refresh does not block reads, but requires unique index on the view.
+func (r *%sRepositoryBase) %s(%sconcurrent bool) error {
+	query := "REFRESH MATERIALIZED VIEW "
+	if concurrent {
+		query += "CONCURRENTLY "
+	}
+	query += r.table
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Refresh"); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.db.%squery)
+	return err
+}
+`, g.methodName("refresh"), g.name(tableIdent(t)), g.methodName("refresh"), g.contextArg(), g.dbCall("Exec"))
+	g.generateRepositoryContextFree(w, t, "refresh", "concurrent bool", "concurrent", "error")
+}
+
+// generateRepositoryExplain generates method that passes execution plan of given query to the explain hook, see pqt.ExplainHook.
+func (g *Generator) generateRepositoryExplain(w io.Writer, t *pqt.Table) {
+	fmt.Fprintf(w, `// %s runs given query using EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) and passes the plan to the explain hook.
+// As the query is executed, it is called in debug mode by read only methods only.
+func (r *%sRepositoryBase) %s(%squery string, args []interface{}) error {
+	var plan []byte
+	if err := r.db.%s"EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+query, args...).Scan(&plan); err != nil {
+		return err
+	}
+	r.%s(json.RawMessage(plan), query, args)
+
+	return nil
+}
+
+`, g.name("explainQuery"), g.name(tableIdent(t)), g.name("explainQuery"), g.contextArg(), g.dbCall("QueryRow"), g.name("explain"))
+}
+
+// explainCall returns code that passes plan of given query to the explain hook, if it is set.
+// It is meant to be placed within debug block, ret is the zero value returned on failure alongside the error.
+func (g *Generator) explainCall(query, args, ret string) string {
+	return fmt.Sprintf(`		if r.%s != nil {
+			if err := r.%s(%s%s, %s); err != nil {
+				return %s, err
+			}
+		}
+`, g.name("explain"), g.name("explainQuery"), g.contextParam(), query, args, ret)
+}
+
+// generateRepositoryDefaultContext generates method that returns context used by methods called without one.
+// It does nothing if context support is disabled.
+func (g *Generator) generateRepositoryDefaultContext(w io.Writer, t *pqt.Table) {
+	if !g.ctx {
+		return
+	}
+	fmt.Fprintf(w, `// %s returns context used by methods called without one, it is bounded by the timeout of the repository, if it is set.
+// Context given explicitly by the caller is used as it is.
+func (r *%sRepositoryBase) %s() (context.Context, context.CancelFunc) {
+	if r.timeout > 0 {
+		return context.WithTimeout(context.Background(), r.timeout)
+	}
+	return context.Background(), func() {}
+}
+
+`, g.name("defaultContext"), g.name(tableIdent(t)), g.name("defaultContext"))
+}
+
+// generateRepositoryPlan generates method that builds condition of the criteria using the planner, if it is set.
+func (g *Generator) generateRepositoryPlan(w io.Writer, t *pqt.Table) {
+	fmt.Fprintf(w, `// %s returns condition of given criteria and its arguments, built by the planner if it is set.
+func (r *%sRepositoryBase) %s(c *%sCriteria) (string, []interface{}, error) {
+	if r.planner != nil {
+		return r.planner.Plan(c)
+	}
+	return c.%s(1)
+}
+
+`, g.name("plan"), g.name(tableIdent(t)), g.name("plan"), g.name(tableIdent(t)), g.name("whereClause"))
+}
+
+// generateRepositoryStatements generates methods that give access to the prepared statement cache, if it is enabled.
+func (g *Generator) generateRepositoryStatements(w io.Writer, t *pqt.Table) {
+	if !g.prepared {
+		return
+	}
+	fmt.Fprintf(w, `// %s returns cache of prepared statements if it is set, otherwise the database itself.
+func (r *%sRepositoryBase) %s() pqtgo.Querier {
+	if r.stmts != nil {
+		return r.stmts
+	}
+	return r.db
+}
+
+// %s closes all cached prepared statements.
+func (r *%sRepositoryBase) %s() error {
+	if r.stmts == nil {
+		return nil
+	}
+	return r.stmts.Close()
+}
+`, g.private("querier"), g.name(tableIdent(t)), g.private("querier"),
+		g.name("Close"), g.name(tableIdent(t)), g.name("Close"))
 }
 
 func (g *Generator) generateRepositoryFindPropertyQuery(w io.Writer, c *pqt.Column) {
 	columnName := g.propertyName(c.Name)
-	columnNameWithTable := g.columnNameWithTableName(c.Table.Name, c.Name)
+	columnNameWithTable := g.columnNameWithTableName(tableIdent(c.Table), c.Name)
 
 	t := g.generateColumnTypeString(c, modeCriteria)
 	if t == "<nil>" {
 		return
 	}
+	if isArray(c.Type) {
+		switch t {
+		case "*qtypes.Int64":
+			fmt.Fprintf(w, `
+		if err = pqtgo.WriteCompositionQueryInt64Array(c.%s, %s, com, pqtgo.And); err != nil {
+			return
+		}`, columnName, columnNameWithTable)
+			return
+		case "*qtypes.String":
+			fmt.Fprintf(w, `
+		if err = pqtgo.WriteCompositionQueryStringArray(c.%s, %s, com, pqtgo.And); err != nil {
+			return
+		}`, columnName, columnNameWithTable)
+			return
+		}
+	}
 	if !g.generateRepositoryFindPropertyQueryByGoType(w, c, t, columnName, columnNameWithTable) {
 		fmt.Fprintf(w, " if c.%s != nil {", g.propertyName(c.Name))
 		fmt.Fprintf(w, dirtyAnd)
 		fmt.Fprintf(w, `if _, err = com.WriteString(%s); err != nil {
 			return
 		}
-		`, g.columnNameWithTableName(c.Table.Name, c.Name))
+		`, g.columnNameWithTableName(tableIdent(c.Table), c.Name))
 		fmt.Fprint(w, `if _, err = com.WriteString(" = "); err != nil {
 			return
 		}
@@ -557,99 +1532,100 @@ func (g *Generator) generateRepositoryFindPropertyQueryByGoType(w io.Writer, col
 			}
 		`, columnName, dirtyAnd, columnNameWithTable, columnName)
 	case "*qtypes.Timestamp":
+		// Properties of JSON columns have no bounds of their own, so their ranges stay exclusive.
+		bounds := "pqt.RangeBounds{}"
+		if !strings.Contains(columnName, ".") {
+			bounds = "c." + g.propertyName(col.Name+"_bounds")
+		}
 		fmt.Fprintf(w, `
 				if c.%s != nil && c.%s.Valid {
-					%st1 := c.%s.Value()
-					if %st1 != nil {
-						%s1, err := ptypes.Timestamp(%st1)
-						if err != nil {
-							return err
+					switch c.%s.Type {
+					case qtypes.QueryType_NULL:
+						%s
+						com.WriteString(%s)
+						if c.%s.Negation {
+							com.WriteString(" IS NOT NULL ")
+						} else {
+							com.WriteString(" IS NULL ")
+						}
+					case qtypes.QueryType_EQUAL:
+						%s
+						com.WriteString(%s)
+						if c.%s.Negation {
+							com.WriteString(" <> ")
+						} else {
+							com.WriteString(" = ")
 						}
-						switch c.%s.Type {
-						case qtypes.QueryType_NULL:
+						com.WritePlaceholder()
+						com.Add(c.%s.Value())
+					case qtypes.QueryType_GREATER:
+						%s
+						com.WriteString(%s)
+						com.WriteString(">")
+						com.WritePlaceholder()
+						com.Add(c.%s.Value())
+					case qtypes.QueryType_GREATER_EQUAL:
+						%s
+						com.WriteString(%s)
+						com.WriteString(">=")
+						com.WritePlaceholder()
+						com.Add(c.%s.Value())
+					case qtypes.QueryType_LESS:
+						%s
+						com.WriteString(%s)
+						com.WriteString(" < ")
+						com.WritePlaceholder()
+						com.Add(c.%s.Value())
+					case qtypes.QueryType_LESS_EQUAL:
+						%s
+						com.WriteString(%s)
+						com.WriteString(" <= ")
+						com.WritePlaceholder()
+						com.Add(c.%s.Value())
+					case qtypes.QueryType_IN:
+						if len(c.%s.Values) >0 {
 							%s
 							com.WriteString(%s)
-							if c.%s.Negation {
-								com.WriteString(" IS NOT NULL ")
-							} else {
-								com.WriteString(" IS NULL ")
+							com.WriteString(" IN (")
+							for i, v := range c.%s.Values {
+								if i != 0 {
+									com.WriteString(", ")
+								}
+								com.WritePlaceholder()
+								com.Add(v)
 							}
-						case qtypes.QueryType_EQUAL:
-							%s
-							com.WriteString(%s)
-							if c.%s.Negation {
-								com.WriteString(" <> ")
-							} else {
-								com.WriteString(" = ")
+							com.WriteString(") ")
+						}
+					case qtypes.QueryType_BETWEEN:
+						// Missing bound leaves the range open on that side.
+						if len(c.%s.Values) > 0 && c.%s.Values[0] != nil {
+							%s1, err := ptypes.Timestamp(c.%s.Values[0])
+							if err != nil {
+								return err
 							}
-							com.WritePlaceholder()
-							com.Add(c.%s.Value())
-						case qtypes.QueryType_GREATER:
-							%s
-							com.WriteString(%s)
-							com.WriteString(">")
-							com.WritePlaceholder()
-							com.Add(c.%s.Value())
-						case qtypes.QueryType_GREATER_EQUAL:
 							%s
 							com.WriteString(%s)
-							com.WriteString(">=")
+							com.WriteString(" " + %s.LowerOperator() + " ")
 							com.WritePlaceholder()
-							com.Add(c.%s.Value())
-						case qtypes.QueryType_LESS:
-							%s
-							com.WriteString(%s)
-							com.WriteString(" < ")
-							com.WritePlaceholder()
-							com.Add(c.%s.Value())
-						case qtypes.QueryType_LESS_EQUAL:
+							com.Add(%s1)
+						}
+						if len(c.%s.Values) > 1 && c.%s.Values[1] != nil {
+							%s2, err := ptypes.Timestamp(c.%s.Values[1])
+							if err != nil {
+								return err
+							}
 							%s
 							com.WriteString(%s)
-							com.WriteString(" <= ")
+							com.WriteString(" " + %s.UpperOperator() + " ")
 							com.WritePlaceholder()
-							com.Add(c.%s.Value())
-						case qtypes.QueryType_IN:
-							if len(c.%s.Values) >0 {
-								%s
-								com.WriteString(%s)
-								com.WriteString(" IN (")
-								for i, v := range c.%s.Values {
-									if i != 0 {
-										com.WriteString(", ")
-									}
-									com.WritePlaceholder()
-									com.Add(v)
-								}
-								com.WriteString(") ")
-							}
-						case qtypes.QueryType_BETWEEN:
-							%s
-							%st2 := c.%s.Values[1]
-							if %st2 != nil {
-								%s2, err := ptypes.Timestamp(%st2)
-								if err != nil {
-									return err
-								}
-								com.WriteString(%s)
-								com.WriteString(" > ")
-								com.WritePlaceholder()
-								com.Add(%s1)
-								com.WriteString(" AND ")
-								com.WriteString(%s)
-								com.WriteString(" < ")
-								com.WritePlaceholder()
-								com.Add(%s2)
-							}
+							com.Add(%s2)
 						}
 					}
 				}
 `,
-			columnName, columnName,
 			columnName, columnName,
 			columnName,
-			columnName,
-			columnName, columnName,
-			// NOT A NUMBER
+			// NULL
 			dirtyAnd,
 			columnNameWithTable,
 			columnName,
@@ -673,12 +1649,14 @@ func (g *Generator) generateRepositoryFindPropertyQueryByGoType(w io.Writer, col
 			dirtyAnd,
 			columnNameWithTable, columnName,
 			// BETWEEN
+			columnName, columnName,
+			columnName, columnName,
 			dirtyAnd,
+			columnNameWithTable, bounds, columnName,
 			columnName, columnName,
 			columnName, columnName,
-			columnName,
-			columnNameWithTable, columnName,
-			columnNameWithTable, columnName,
+			dirtyAnd,
+			columnNameWithTable, bounds, columnName,
 		)
 	case "*qtypes.Int64":
 		fmt.Fprintf(w, `
@@ -861,7 +1839,7 @@ func (g *Generator) generateRepositoryFindSingleExpression(w io.Writer, c *pqt.C
 				}
 
 				columnName := g.propertyName(c.Name)
-				columnNameWithTable := g.columnNameWithTableName(c.Table.Name, c.Name)
+				columnNameWithTable := g.columnNameWithTableName(tableIdent(c.Table), c.Name)
 				zero := reflect.Zero(mtt.criteriaTypeOf)
 				// Checks if custom type implements Criterion interface.
 				// If it's true then just use it.
@@ -882,6 +1860,24 @@ func (g *Generator) generateRepositoryFindSingleExpression(w io.Writer, c *pqt.C
 					}
 				}
 
+				// JSON values are matched using containment operator, so only given properties have to match.
+				if mtt.json && mtt.canBeNil(mtt.criteriaTypeOf) {
+					fmt.Fprintf(w, " if c.%s != nil {", columnName)
+					fmt.Fprint(w, dirtyAnd)
+					fmt.Fprintf(w, `if _, err = com.WriteString(%s); err != nil {
+							return
+						}
+						if _, err = com.WriteString(" @> "); err != nil {
+							return
+						}
+						if err = com.WritePlaceholder(); err != nil {
+							return
+						}
+						com.Add(pqtgo.JSON(c.%s))
+					}`, columnNameWithTable, columnName)
+					break MappingLoop
+				}
+
 				switch zero.Kind() {
 				case reflect.Map:
 					// TODO: implement
@@ -911,7 +1907,7 @@ func (g *Generator) generateRepositoryFindSingleExpression(w io.Writer, c *pqt.C
 }
 
 func (g *Generator) generateCriteriaWriteComposition(w io.Writer, t *pqt.Table) {
-	entityName := g.name(t.Name)
+	entityName := g.name(tableIdent(t))
 	fmt.Fprintf(w, `func (c *%sCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *pqtgo.CompositionOpts) (err error) {
 	`, entityName) // It's probably not enough but its good start.
 	for _, c := range t.Columns {
@@ -921,26 +1917,36 @@ func (g *Generator) generateCriteriaWriteComposition(w io.Writer, t *pqt.Table)
 
 		g.generateRepositoryFindSingleExpression(w, c)
 	}
+	if t.SoftDelete {
+		fmt.Fprintf(w, `
+	if !c.%s {
+		if com.Dirty {
+			com.WriteString(" AND ")
+		}
+		com.Dirty = true
+		com.WriteString(%s)
+		com.WriteString(" IS NULL")
+	}
+`, g.name("includeDeleted"), g.columnNameWithTableName(tableIdent(t), softDeleteColumn(t)))
+	}
 	fmt.Fprintf(w, `
-	if len(c.%s) > 0 {
-		i:=0
-		com.WriteString(" ORDER BY ")
+	return c.%s(com)
+}
 
-		for cn, asc := range c.%s {
-			for _, tcn := range table%sColumns {
-				if cn == tcn {
-					if i > 0 {
-						com.WriteString(", ")
-					}
-					com.WriteString(cn)
-					if !asc {
-						com.WriteString(" DESC ")
-					}
-					i++
-					break
-				}
-			}
+// %s writes ORDER BY, OFFSET, LIMIT and locking clauses that follow the condition.
+func (c *%sCriteria) %s(com *pqtgo.Composer) (err error) {`, g.name("writeSuffix"), g.name("writeSuffix"), entityName, g.name("writeSuffix"))
+	g.generateSortValidation(w, t, "return")
+	fmt.Fprintf(w, `
+	// Columns are ordered by name, map does not preserve the order and random one would make results unstable.
+	// Sort expressions, e.g. window functions, are ordered explicitly, so they come first.
+	if keys := pqtgo.Keyset(c.%s, %s%sColumns); len(keys) > 0 || len(c.%s) > 0 {
+		orderBy, err := pqtgo.OrderBy(c.%s, keys, %s%sColumns)
+		if err != nil {
+			return err
 		}
+		com.WriteString(" ORDER BY ")
+		com.WriteString(orderBy)
+		com.WriteString(" ")
 	}
 	if c.%s > 0 {
 		if _, err = com.WriteString(" OFFSET "); err != nil {
@@ -966,19 +1972,51 @@ func (g *Generator) generateCriteriaWriteComposition(w io.Writer, t *pqt.Table)
 		}
 		com.Add(c.%s)
 	}
+	if c.%s != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.%s.String())
+	}
 
 	return
 }
-`, g.name("sort"), g.name("sort"),
-		g.public(t.Name),
-		g.name("offset"), g.name("offset"),
-		g.name("limit"), g.name("limit"))
+`, g.name("sort"), g.name("table"), g.public(tableIdent(t)), g.name("sortExpr"),
+		g.name("sortExpr"), g.name("table"), g.public(tableIdent(t)),
+		g.public("offset"), g.public("offset"),
+		g.public("limit"), g.public("limit"),
+		g.name("lock"), g.name("lock"))
+}
+
+// generateCriteriaWhereClause generates method that exposes condition built by WriteComposition,
+// so it can be reused by hand-written queries.
+func (g *Generator) generateCriteriaWhereClause(w io.Writer, t *pqt.Table) {
+	entityName := g.name(tableIdent(t))
+	fmt.Fprintf(w, `// %s returns condition built from the criteria, without WHERE keyword, and its arguments in order.
+// Placeholders are numbered starting at given index, so the clause can be embedded into a custom query.
+// Sort, offset, limit and lock are ignored.
+func (c *%sCriteria) %s(startIdx int) (string, []interface{}, error) {
+	cc := *c
+	cc.%s, cc.%s, cc.%s, cc.%s, cc.%s = nil, nil, 0, 0, pqt.LockNone
+
+	com := pqtgo.NewComposerAt(%d, startIdx)
+	if err := cc.WriteComposition("", com, pqtgo.And); err != nil {
+		return "", nil, err
+	}
+
+	return com.String(), com.Args(), nil
+}
+
+`, g.name("whereClause"), entityName, g.name("whereClause"),
+		g.name("sort"), g.name("sortExpr"), g.public("offset"), g.public("limit"), g.name("lock"),
+		len(t.Columns))
 }
 
+// generateRepositoryScanRows generates functions that scan all columns of the table, in order of table<Table>Columns,
+// from rows and row of the driver respectively, so they can be reused by hand-written queries.
 func (g *Generator) generateRepositoryScanRows(w io.Writer, t *pqt.Table) {
-	entityName := g.name(t.Name)
-	fmt.Fprintf(w, `func %s%sRows(rows *sql.Rows) ([]*%sEntity, error) {
-	`, g.name("Scan"), g.public(t.Name), entityName)
+	entityName := g.name(tableIdent(t))
+	fmt.Fprintf(w, `// %s%sRows reads all rows into entities, each row has to consist of columns listed in %s, in the same order.
+func %s%sRows(rows %s) ([]*%sEntity, error) {
+	`, g.name("Scan"), g.public(tableIdent(t)), g.name("Table"+g.public(tableIdent(t))+"Columns"), g.name("Scan"), g.public(tableIdent(t)), g.rowsType(), entityName)
 	fmt.Fprintf(w, `var (
 		entities []*%sEntity
 		err error
@@ -988,7 +2026,7 @@ func (g *Generator) generateRepositoryScanRows(w io.Writer, t *pqt.Table) {
 		err = rows.Scan(
 	`, entityName, entityName)
 	for _, c := range t.Columns {
-		fmt.Fprintf(w, "&ent.%s,\n", g.propertyName(c.Name))
+		fmt.Fprintf(w, "%s,\n", g.scanTarget("ent", c))
 	}
 	fmt.Fprint(w, `)
 			if err != nil {
@@ -1005,662 +2043,2855 @@ func (g *Generator) generateRepositoryScanRows(w io.Writer, t *pqt.Table) {
 	}
 
 	`)
+
+	fmt.Fprintf(w, `// %s%sRow reads single row into entity, the row has to consist of columns listed in %s, in the same order.
+// %s is returned if the query selected no rows.
+func %s%sRow(row %s) (*%sEntity, error) {
+	var ent %sEntity
+	err := row.Scan(
+	`, g.name("Scan"), g.public(tableIdent(t)), g.name("Table"+g.public(tableIdent(t))+"Columns"), g.errNoRows(), g.name("Scan"), g.public(tableIdent(t)), g.rowType(), entityName, entityName)
+	for _, c := range t.Columns {
+		fmt.Fprintf(w, "%s,\n", g.scanTarget("ent", c))
+	}
+	fmt.Fprint(w, `)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
 }
 
-func (g *Generator) generateRepositoryFindBody(w io.Writer, t *pqt.Table) {
-	fmt.Fprint(w, `
-	com := pqtgo.NewComposer(1)
+`)
+}
+
+// generateRepositoryFindQuery generates method that builds query used by find methods, without executing it.
+func (g *Generator) generateRepositoryFindQuery(w io.Writer, t *pqt.Table) {
+	fmt.Fprintf(w, `// %s returns query and arguments used by %s to retrieve entities that match given criteria, without executing it.
+func (r *%sRepositoryBase) %s(c *%sCriteria) (string, []interface{}, error) {
+	where, args, err := r.%s(c)
+	if err != nil {
+		return "", nil, err
+	}
+
 	buf := bytes.NewBufferString("SELECT ")
 	buf.WriteString(strings.Join(r.columns, ", "))
 	buf.WriteString(" FROM ")
 	buf.WriteString(r.table)
-	buf.WriteString(" ")
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
-		return nil, err
-	}
-	if com.Dirty {
-		buf.WriteString(" WHERE ")
+	com := pqtgo.NewComposerAt(2, len(args)+1)
+	if err := c.%s(com); err != nil {
+		return "", nil, err
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
+	buf.ReadFrom(com)
+
+	return buf.String(), append(args, com.Args()...), nil
+}
+
+`, g.name("findQuery"), g.methodName("Find"), g.name(tableIdent(t)), g.name("findQuery"), g.name(tableIdent(t)), g.name("plan"), g.name("writeSuffix"))
+}
+
+func (g *Generator) generateRepositoryFindBody(w io.Writer, t *pqt.Table) {
+	fmt.Fprintf(w, `
+	query, args, err := r.%s(c)
+	if err != nil {
+		return nil, err
 	}
 
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Find"); err != nil {
+		if err := r.log.Log("msg", query, "function", "Find"); err != nil {
 			return nil, err
 		}
-	}
+`+g.explainCall("query", "args", "nil")+`	}
 
-	rows, err := r.db.Query(buf.String(), com.Args()...)
+	rows, err := r.db.%squery, args...)
 	if err != nil {
 		return nil, err
 	}
-`)
+`, g.name("findQuery"), g.dbCall("Query"))
 }
 
 func (g *Generator) generateRepositoryFind(w io.Writer, t *pqt.Table) {
-	entityName := g.name(t.Name)
+	entityName := g.name(tableIdent(t))
+	g.generateRepositoryFindQuery(w, t)
 
 	fmt.Fprintf(w, `
-func (r *%sRepositoryBase) %s(c *%sCriteria) ([]*%sEntity, error) {
-`, entityName, g.name("Find"), entityName, entityName)
+// %s returns entities that match given criteria.
+func (r *%sRepositoryBase) %s(%sc *%sCriteria) ([]*%sEntity, error) {
+`, g.methodName("Find"), entityName, g.methodName("Find"), g.contextArg(), entityName, entityName)
 	g.generateRepositoryFindBody(w, t)
 	fmt.Fprintf(w, `
 	defer rows.Close()
 
 	return %s%sRows(rows)
 }
-`, g.name("Scan"), g.public(t.Name))
+`, g.name("Scan"), g.public(tableIdent(t)))
+	g.generateRepositoryContextFree(w, t, "Find", "c *"+entityName+"Criteria", "c", "([]*"+entityName+"Entity, error)")
 }
 
 func (g *Generator) generateRepositoryFindIter(w io.Writer, t *pqt.Table) {
-	entityName := g.name(t.Name)
+	entityName := g.name(tableIdent(t))
 
-	fmt.Fprintf(w, `func (r *%sRepositoryBase) %s(c *%sCriteria) (*%sIterator, error) {
-`, entityName, g.name("FindIter"), entityName, entityName)
+	fmt.Fprintf(w, `// %s returns iterator over entities that match given criteria.
+// Caller is responsible for closing the iterator, also if the loop exits early.
+func (r *%sRepositoryBase) %s(%sc *%sCriteria) (*%sIterator, error) {
+`, g.methodName("FindIter"), entityName, g.methodName("FindIter"), g.contextArg(), entityName, entityName)
 	g.generateRepositoryFindBody(w, t)
 	fmt.Fprintf(w, `
 
 	return &%sIterator{rows: rows}, nil
 }
-`, g.name(t.Name))
+`, g.name(tableIdent(t)))
+	g.generateRepositoryContextFree(w, t, "FindIter", "c *"+entityName+"Criteria", "c", "(*"+entityName+"Iterator, error)")
 }
 
-func (g *Generator) generateRepositoryCount(w io.Writer, t *pqt.Table) {
-	entityName := g.name(t.Name)
+func (g *Generator) generateRepositoryFindPage(w io.Writer, t *pqt.Table) {
+	entityName := g.name(tableIdent(t))
 
-	fmt.Fprintf(w, `func (r *%sRepositoryBase) %s(c *%sCriteria) (int64, error) {
-`, entityName, g.name("count"), entityName)
+	var unique []string
+	if pk, ok := primaryKey(t); ok {
+		for _, c := range pk {
+			unique = append(unique, g.columnNameWithTableName(tableIdent(t), c.Name))
+		}
+	}
+
+	fmt.Fprintf(w, `// %s returns page of entities that match given criteria using cursor based (keyset) pagination.
+// Rows are ordered by sort columns of the criteria, followed by primary key, so cursors remain valid if rows are inserted or deleted.
+// Limit of the criteria defines size of the page, offset is not supported.
+// Values held by cursors are decoded into properties of the entity, so they keep types of their columns.
+func (r *%sRepositoryBase) %s(%sc *%sCriteria, page pqt.CursorPage) (*%sPage, error) {
+	if c.%s > 0 {
+		return nil, errors.New("%s find page failure, offset is not supported")
+	}
+	if len(c.%s) > 0 {
+		return nil, errors.New("%s find page failure, sort expressions are not supported")
+	}
+	if c.%s <= 0 {
+		return nil, errors.New("%s find page failure, limit has to be positive")
+	}
+	if page.After != "" && page.Before != "" {
+		return nil, errors.New("%s find page failure, after and before cursors cannot be used together")
+	}
+`,
+		g.methodName("FindPage"), entityName, g.methodName("FindPage"), g.contextArg(), entityName, entityName,
+		g.public("offset"), entityName,
+		g.name("sortExpr"), entityName,
+		g.public("limit"), entityName,
+		entityName,
+	)
+	g.generateSortValidation(w, t, "return nil,")
 	fmt.Fprintf(w, `
-	com := pqtgo.NewComposer(%d)
-	buf := bytes.NewBufferString("SELECT COUNT(*) FROM ")
+	keys := pqtgo.Keyset(c.%s, %s%sColumns%s)
+	if len(keys) == 0 {
+		return nil, errors.New("%s find page failure, sort or primary key is required")
+	}
+	cursor := func(ent *%sEntity) (string, error) {
+		values := make(map[string]interface{}, len(keys))
+		for _, k := range keys {
+			values[k.Name], _ = ent.%s(k.Name)
+		}
+		return pqt.EncodeCursor(values)
+	}
+
+	// Previous page is fetched by traversing rows in opposite order, starting from the cursor.
+	from, backward := page.After, page.Before != ""
+	order := keys
+	if backward {
+		from, order = page.Before, pqtgo.ReverseKeyset(keys)
+	}
+
+	where, args, err := r.%s(c)
+	if err != nil {
+		return nil, err
+	}
+	com := pqtgo.NewComposerAt(%d, len(args)+1)
+	for _, arg := range args {
+		com.Add(arg)
+	}
+	if where != "" {
+		// Condition of the planner can consist of alternatives, keyset condition is added to all of them.
+		com.WriteString("(" + where + ")")
+		com.Dirty = true
+	}
+	buf := bytes.NewBufferString("SELECT ")
+	buf.WriteString(strings.Join(r.columns, ", "))
+	buf.WriteString(" FROM ")
 	buf.WriteString(r.table)
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
-		return 0, err
+	if from != "" {
+		// Cursor values are decoded into properties of the entity, so they keep types of the columns.
+		var cur %sEntity
+		dest := make(map[string]interface{}, len(order))
+		for _, k := range order {
+			dest[k.Name], _ = cur.%s(k.Name)
+		}
+		if err := pqt.DecodeCursorTo(from, dest); err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, 0, len(order))
+		for _, k := range order {
+			v, _ := cur.%s(k.Name)
+			values = append(values, v)
+		}
+		if com.Dirty {
+			com.WriteString(" AND ")
+		}
+		if err := pqtgo.WriteKeysetCondition(com, order, values); err != nil {
+			return nil, err
+		}
+		com.Dirty = true
 	}
 	if com.Dirty {
 		buf.WriteString(" WHERE ")
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
+	com.WriteString(" ORDER BY ")
+	com.WriteString(pqtgo.KeysetOrderBy(order))
+	com.WriteString(" LIMIT ")
+	if err := com.WritePlaceholder(); err != nil {
+		return nil, err
+	}
+	// One more row tells if there is another page in the direction of traversal.
+	com.Add(c.%s + 1)
+	if c.%s != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.%s.String())
 	}
+	buf.ReadFrom(com)
 
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Count"); err != nil {
-			return 0, err
+		if err := r.log.Log("msg", buf.String(), "function", "FindPage"); err != nil {
+			return nil, err
 		}
+`+g.explainCall("buf.String()", "com.Args()", "nil")+`	}
+
+	rows, err := r.db.%sbuf.String(), com.Args()...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	var count int64
-	if err := r.db.QueryRow(buf.String(), com.Args()...).Scan(&count); err != nil {
-		return 0, err
+	ents, err := %s%sRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	more := int64(len(ents)) > c.%s
+	if more {
+		ents = ents[:c.%s]
+	}
+	if backward {
+		for i, j := 0, len(ents)-1; i < j; i, j = i+1, j-1 {
+			ents[i], ents[j] = ents[j], ents[i]
+		}
 	}
-	return count, nil
-}
-`, len(t.Columns))
-}
 
-func (g *Generator) generateRepositoryFindOneByPrimaryKey(code *bytes.Buffer, table *pqt.Table) {
-	entityName := g.name(table.Name)
-	pk, ok := table.PrimaryKey()
-	if !ok {
-		return
+	res := &%sPage{%s: ents}
+	if backward {
+		res.%s, res.%s = true, more
+	} else {
+		res.%s, res.%s = more, page.After != ""
+	}
+	if len(ents) > 0 {
+		if res.%s, err = cursor(ents[0]); err != nil {
+			return nil, err
+		}
+		if res.%s, err = cursor(ents[len(ents)-1]); err != nil {
+			return nil, err
+		}
 	}
 
-	fmt.Fprintf(code, `func (r *%sRepositoryBase) %s%s(%s %s) (*%sEntity, error) {`,
+	return res, nil
+}
+`,
+		g.name("sort"), g.name("table"), g.public(tableIdent(t)), prefixEach(", ", unique),
 		entityName,
-		g.name("FindOneBy"),
-		g.public(pk.Name),
-		g.private(pk.Name),
-		g.generateColumnTypeString(pk, modeMandatory),
 		entityName,
+		g.name("value"),
+		g.name("plan"),
+		len(t.Columns),
+		entityName, g.name("prop"), g.name("value"),
+		g.public("limit"), g.name("lock"), g.name("lock"),
+		g.dbCall("Query"),
+		g.name("Scan"), g.public(tableIdent(t)),
+		g.public("limit"), g.public("limit"),
+		entityName, g.name("Edges"),
+		g.name("HasNextPage"), g.name("HasPrevPage"),
+		g.name("HasNextPage"), g.name("HasPrevPage"),
+		g.name("StartCursor"),
+		g.name("EndCursor"),
 	)
-	fmt.Fprintf(code, `var (
-		ent %sEntity
-	)`, entityName)
-	code.WriteRune('\n')
-	fmt.Fprint(code, "query := `SELECT ")
-	for i, c := range table.Columns {
-		fmt.Fprintf(code, "%s", c.Name)
-		if i != len(table.Columns)-1 {
-			code.WriteRune(',')
+	g.generateRepositoryContextFree(w, t, "FindPage", "c *"+entityName+"Criteria, page pqt.CursorPage", "c, page", "(*"+entityName+"Page, error)")
+}
+
+// generateRepositoryFindAndCount generates find method that returns also total number of entities that match the criteria.
+// Both come from a single query, total is selected as an additional COUNT(*) OVER() column, so the condition cannot drift.
+func (g *Generator) generateRepositoryFindAndCount(w io.Writer, t *pqt.Table) {
+	entityName := g.name(tableIdent(t))
+
+	fmt.Fprintf(w, `// %s returns entities that match given criteria together with total number of matching entities, regardless of offset and limit.
+// Total is selected by the same query using COUNT(*) OVER() window function, which is evaluated before OFFSET and LIMIT are applied.
+// If the page is empty, but offset is set, total is obtained using %s, so it is known even if the offset goes past the last entity.
+// Window functions cannot be combined with locking clause, so lock is not supported.
+func (r *%sRepositoryBase) %s(%sc *%sCriteria) ([]*%sEntity, int64, error) {
+	if c.%s != pqt.LockNone {
+		return nil, 0, errors.New("%s find and count failure, lock is not supported")
+	}
+
+	rr := *r
+	rr.columns = append(r.columns[:len(r.columns):len(r.columns)], "COUNT(*) OVER()")
+	query, args, err := rr.%s(c)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "FindAndCount"); err != nil {
+			return nil, 0, err
 		}
-		code.WriteRune('\n')
+`+g.explainCall("query", "args", "nil, 0")+`	}
+
+	rows, err := r.db.%squery, args...)
+	if err != nil {
+		return nil, 0, err
 	}
-	fmt.Fprintf(code, " FROM %s WHERE %s = $1`", table.FullName(), pk.Name)
+	defer rows.Close()
 
-	fmt.Fprintf(code, `
-	err := r.db.QueryRow(query, %s).Scan(
-	`, g.private(pk.Name))
-	for _, c := range table.Columns {
-		fmt.Fprintf(code, "&ent.%s,\n", g.propertyName(c.Name))
+	var (
+		entities []*%sEntity
+		total int64
+	)
+	for rows.Next() {
+		var ent %sEntity
+		err = rows.Scan(
+`, g.methodName("FindAndCount"), g.methodName("count"), entityName, g.methodName("FindAndCount"), g.contextArg(), entityName, entityName,
+		g.name("lock"), entityName,
+		g.name("findQuery"),
+		g.dbCall("Query"),
+		entityName, entityName)
+	for _, c := range t.Columns {
+		fmt.Fprintf(w, "%s,\n", g.scanTarget("ent", c))
 	}
-	fmt.Fprint(code, `)
+	fmt.Fprintf(w, `&total,
+		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
-		return &ent, nil
+		entities = append(entities, &ent)
+	}
+	if rows.Err() != nil {
+		return nil, 0, rows.Err()
+	}
+	if len(entities) == 0 && c.%s > 0 {
+		if total, err = r.%s(%sc); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return entities, total, nil
 }
-`)
+`, g.public("offset"), g.methodName("count"), g.contextParam())
+	g.generateRepositoryContextFree(w, t, "FindAndCount", "c *"+entityName+"Criteria", "c", "([]*"+entityName+"Entity, int64, error)")
 }
 
-func (g *Generator) generateRepositoryFindOneByUniqueConstraint(code *bytes.Buffer, table *pqt.Table) {
-	entityName := g.name(table.Name)
-	var unique []*pqt.Constraint
-	for _, c := range tableConstraints(table) {
-		if c.Type == pqt.ConstraintTypeUnique {
-			unique = append(unique, c)
-		}
-	}
-	if len(unique) < 1 {
+func (g *Generator) generateRepositoryFindIncludingDeleted(w io.Writer, t *pqt.Table) {
+	if !t.SoftDelete {
 		return
 	}
+	entityName := g.name(tableIdent(t))
 
-	for _, u := range unique {
-		arguments := ""
-		methodName := "FindOneBy"
-		for i, c := range u.Columns {
-			if i != 0 {
-				methodName += "And"
-				arguments += ", "
-			}
-			methodName += g.public(c.Name)
-			arguments += fmt.Sprintf("%s %s", g.private(c.Name), g.generateColumnTypeString(c, modeMandatory))
-		}
-		fmt.Fprintf(code, `func (r *%sRepositoryBase) %s(%s) (*%sEntity, error) {`, entityName, g.name(methodName), arguments, entityName)
-		fmt.Fprintf(code, `var (
-			ent %sEntity
-		)`, entityName)
-		code.WriteRune('\n')
-		fmt.Fprint(code, "query := `SELECT ")
-		for i, c := range table.Columns {
-			if i != 0 {
-				code.WriteString(", ")
-			}
-			fmt.Fprintf(code, "%s", c.Name)
-		}
-		fmt.Fprintf(code, " FROM %s WHERE ", table.FullName())
-		for i, c := range u.Columns {
-			if i != 0 {
-				fmt.Fprint(code, " AND ")
-			}
-			fmt.Fprintf(code, "%s = $%d", c.Name, i+1)
-		}
-		fmt.Fprintln(code, "`")
+	fmt.Fprintf(w, `// %s works like %s, but returns also entities marked as deleted.
+func (r *%sRepositoryBase) %s(%sc *%sCriteria) ([]*%sEntity, error) {
+	cc := *c
+	cc.%s = true
 
-		fmt.Fprint(code, "err := r.db.QueryRow(query, ")
-		for i, c := range u.Columns {
-			if i != 0 {
-				fmt.Fprint(code, ", ")
-			}
-			fmt.Fprintf(code, "%s", g.private(c.Name))
-		}
-		fmt.Fprint(code, ").Scan(\n")
-		for _, c := range table.Columns {
-			fmt.Fprintf(code, "&ent.%s,\n", g.propertyName(c.Name))
-		}
-		fmt.Fprint(code, `)
-			if err != nil {
-				return nil, err
-			}
+	return r.%s(%s&cc)
+}
+`, g.methodName("FindIncludingDeleted"), g.methodName("Find"), entityName, g.methodName("FindIncludingDeleted"), g.contextArg(), entityName, entityName,
+		g.name("includeDeleted"),
+		g.methodName("Find"), g.contextParam())
+	g.generateRepositoryContextFree(w, t, "FindIncludingDeleted", "c *"+entityName+"Criteria", "c", "([]*"+entityName+"Entity, error)")
+}
 
-			return &ent, nil
-	}
-	`)
+func (g *Generator) generateRepositoryFindOne(w io.Writer, t *pqt.Table) {
+	entityName := g.name(tableIdent(t))
 
+	fmt.Fprintf(w, `// %s returns single entity that matches given criteria.
+// pqt.ErrNotFound is returned if there is none, pqt.ErrMultipleRows if there is more than one.
+func (r *%sRepositoryBase) %s(%sc *%sCriteria) (*%sEntity, error) {
+	cc := *c
+	cc.%s = 2
+
+	ents, err := r.%s(%s&cc)
+	if err != nil {
+		return nil, err
+	}
+	switch len(ents) {
+	case 0:
+		return nil, pqt.ErrNotFound
+	case 1:
+		return ents[0], nil
+	default:
+		return nil, pqt.ErrMultipleRows
 	}
 }
+`, g.methodName("FindOne"), entityName, g.methodName("FindOne"), g.contextArg(), entityName, entityName, g.public("limit"), g.methodName("Find"), g.contextParam())
+	g.generateRepositoryContextFree(w, t, "FindOne", "c *"+entityName+"Criteria", "c", "(*"+entityName+"Entity, error)")
+}
 
-func (g *Generator) generateRepositoryInsert(w io.Writer,
-	table *pqt.Table) {
-	entityName := g.name(table.Name)
-
-	fmt.Fprintf(w, `func (r *%sRepositoryBase) %s(e *%sEntity) (*%sEntity, error) {`, entityName, g.name("Insert"), entityName, entityName)
-	fmt.Fprintf(w, `
-		insert := pqcomp.New(0, %d)
-	`, len(table.Columns))
+// generateRepositoryFindWith generates find method for each relationship that points to the single entity.
+// Related table is joined using LEFT JOIN, criteria is applied within sub-query so column names are not ambiguous.
+func (g *Generator) generateRepositoryFindWith(w io.Writer, t *pqt.Table) {
+	if !g.joins {
+		return
+	}
 
-ColumnsLoop:
-	for _, c := range table.Columns {
-		switch c.Type {
-		case pqt.TypeSerial(), pqt.TypeSerialBig(), pqt.TypeSerialSmall():
-			continue ColumnsLoop
+	entityName := g.name(tableIdent(t))
+	for _, r := range t.OwnedRelationships {
+		switch r.Type {
+		case pqt.RelationshipTypeManyToOne, pqt.RelationshipTypeOneToOne:
 		default:
-			if g.canBeNil(c, modeOptional) {
-				fmt.Fprintf(w, `
-					if e.%s != nil {
-						insert.AddExpr(%s, "", e.%s)
-					}
-				`,
-					g.propertyName(c.Name),
-					g.columnNameWithTableName(table.Name, c.Name),
-					g.propertyName(c.Name),
-				)
-			} else {
-				fmt.Fprintf(
-					w,
-					`insert.AddExpr(%s, "", e.%s)`,
-					g.columnNameWithTableName(table.Name, c.Name),
-					g.propertyName(c.Name),
-				)
-			}
-			fmt.Fprintln(w, "")
+			continue
+		}
+		if r.OwnerTable != t || r.OwnerForeignKey == nil || len(r.OwnerForeignKey.Columns) == 0 {
+			continue
 		}
-	}
-	fmt.Fprint(w, `
-		b := bytes.NewBufferString("INSERT INTO " + r.table)
-
-		if insert.Len() != 0 {
-			b.WriteString(" (")
-			for insert.Next() {
-				if !insert.First() {
-					b.WriteString(", ")
-				}
 
-				fmt.Fprintf(b, "%s", insert.Key())
-			}
-			insert.Reset()
-			b.WriteString(") VALUES (")
-			for insert.Next() {
-				if !insert.First() {
-					b.WriteString(", ")
-				}
+		fk := r.OwnerForeignKey
+		relationName := or(r.InversedName, r.InversedTable.Name)
+		propertyName := g.propertyName(relationName)
+		methodName := "FindWith" + g.public(relationName)
 
-				fmt.Fprintf(b, "%s", insert.PlaceHolder())
-			}
-			b.WriteString(")")
-			if len(r.columns) > 0 {
-				b.WriteString(" RETURNING ")
-				b.WriteString(strings.Join(r.columns, ", "))
-			}
+		selects := make([]string, 0, len(t.Columns)+len(r.InversedTable.Columns))
+		for _, c := range t.Columns {
+			selects = append(selects, "t0."+c.Name)
 		}
-
-		if r.dbg {
-			if err := r.log.Log("msg", b.String(), "function", "Insert"); err != nil {
-				return nil, err
-			}
+		for _, c := range r.InversedTable.Columns {
+			selects = append(selects, "t1."+c.Name)
+		}
+		joins := make([]string, 0, len(fk.Columns))
+		for i, c := range fk.Columns {
+			joins = append(joins, fmt.Sprintf("t0.%s = t1.%s", c.Name, fk.ReferenceColumns[i].Name))
 		}
 
-		err := r.db.QueryRow(b.String(), insert.Args()...).Scan(
-	`)
+		fmt.Fprintf(w, "// %s works like %s, but also loads related %s entity using LEFT JOIN.\n", g.methodName(methodName), g.methodName("Find"), g.name(tableIdent(r.InversedTable)))
+		fmt.Fprintf(w, `func (r *%sRepositoryBase) %s(%sc *%sCriteria) ([]*%sEntity, error) {
+	query, args, err := r.%s(c)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBufferString("SELECT %s FROM (")
+	buf.WriteString(query)
+	buf.WriteString(") AS t0 LEFT JOIN %s AS t1 ON %s")
 
-	for _, c := range table.Columns {
-		fmt.Fprintf(w, "&e.%s,\n", g.propertyName(c.Name))
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "%s"); err != nil {
+			return nil, err
+		}
+`+g.explainCall("buf.String()", "args", "nil")+`	}
+
+	rows, err := r.db.%sbuf.String(), args...)
+	if err != nil {
+		return nil, err
 	}
-	fmt.Fprint(w, `)
+	defer rows.Close()
+
+	var entities []*%sEntity
+	for rows.Next() {
+		var ent %sEntity
+		var (
+`,
+			entityName, g.methodName(methodName), g.contextArg(), entityName, entityName,
+			g.name("findQuery"),
+			strings.Join(selects, ", "),
+			queryName(r.InversedTable), strings.Join(joins, " AND "),
+			methodName,
+			g.dbCall("Query"),
+			entityName, entityName,
+		)
+		for _, c := range r.InversedTable.Columns {
+			fmt.Fprintf(w, "%s%s *%s\n", propertyName, g.public(c.Name), g.generateColumnTypeString(c, modeDefault))
+		}
+		fmt.Fprint(w, `)
+		err = rows.Scan(
+`)
+		for _, c := range t.Columns {
+			fmt.Fprintf(w, "%s,\n", g.scanTarget("ent", c))
+		}
+		for _, c := range r.InversedTable.Columns {
+			if g.isJSON(c) {
+				fmt.Fprintf(w, "pqtgo.JSON(&%s%s),\n", propertyName, g.public(c.Name))
+			} else {
+				fmt.Fprintf(w, "&%s%s,\n", propertyName, g.public(c.Name))
+			}
+		}
+		fmt.Fprintf(w, `)
 		if err != nil {
 			return nil, err
 		}
+		if %s%s != nil {
+			ent.%s = &%sEntity{}
+`, propertyName, g.public(fk.ReferenceColumns[0].Name), propertyName, g.name(tableIdent(r.InversedTable)))
+		for _, c := range r.InversedTable.Columns {
+			fmt.Fprintf(w, `if %s%s != nil {
+				ent.%s.%s = *%s%s
+			}
+`, propertyName, g.public(c.Name), propertyName, g.propertyName(c.Name), propertyName, g.public(c.Name))
+		}
+		fmt.Fprint(w, `}
 
-		return e, nil
+		entities = append(entities, &ent)
 	}
-`)
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entities, nil
+}
+`)
+		g.generateRepositoryContextFree(w, t, methodName, "c *"+entityName+"Criteria", "c", "([]*"+entityName+"Entity, error)")
+	}
+}
+
+// generateRepositoryFindLateral generates find method that joins lateral sub-queries given by the criteria.
+// Criteria is applied within sub-query aliased as t0, so lateral sub-queries can refer to its columns.
+func (g *Generator) generateRepositoryFindLateral(w io.Writer, t *pqt.Table) {
+	if !g.joins {
+		return
+	}
+	entityName := g.name(tableIdent(t))
+
+	selects := make([]string, 0, len(t.Columns))
+	for _, c := range t.Columns {
+		selects = append(selects, "t0."+c.Name)
+	}
+
+	fmt.Fprintf(w, `// %s works like %s, but also joins lateral sub-queries of the criteria using JOIN LATERAL (...) AS alias ON TRUE.
+// Each row of the result holds entity and values of columns of lateral joins, entity is repeated if sub-query returns multiple rows.
+func (r *%sRepositoryBase) %s(%sc *%sCriteria) ([]*%sLateral, error) {
+	lateral, err := pqtgo.LateralColumns(c.%s)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := r.%s(c)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBufferString("SELECT %s")
+	for _, cn := range lateral {
+		buf.WriteString(", ")
+		buf.WriteString(cn)
+	}
+	buf.WriteString(" FROM (")
+	buf.WriteString(query)
+	buf.WriteString(") AS t0")
+
+	// Placeholders of lateral sub-queries follow those of the criteria.
+	com := pqtgo.NewComposerAt(int64(len(c.%s)), len(args)+1)
+	if err := pqtgo.WriteLateralJoins(com, c.%s); err != nil {
+		return nil, err
+	}
+	buf.ReadFrom(com)
+	args = append(args, com.Args()...)
+
+	// Joins do not preserve order of the sub-query, so it is repeated by the outer query.
+	// Columns are qualified, lateral joins can expose columns of the same names.
+	if keys := pqtgo.Keyset(c.%s, %s%sColumns); len(keys) > 0 || len(c.%s) > 0 {
+		orderBy, err := pqtgo.QualifiedOrderBy("t0", c.%s, keys, %s%sColumns)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(" ORDER BY ")
+		buf.WriteString(orderBy)
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindLateral"); err != nil {
+			return nil, err
+		}
+`+g.explainCall("buf.String()", "args", "nil")+`	}
+
+	rows, err := r.db.%sbuf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*%sLateral
+	for rows.Next() {
+		var ent %sEntity
+		values := make([]interface{}, len(lateral))
+		dest := []interface{}{
+`,
+		g.methodName("FindLateral"), g.methodName("Find"),
+		entityName, g.methodName("FindLateral"), g.contextArg(), entityName, entityName,
+		g.name("lateral"),
+		g.name("findQuery"),
+		strings.Join(selects, ", "),
+		g.name("lateral"), g.name("lateral"),
+		g.name("sort"), g.name("table"), g.public(tableIdent(t)), g.name("sortExpr"),
+		g.name("sortExpr"), g.name("table"), g.public(tableIdent(t)),
+		g.dbCall("Query"),
+		entityName, entityName,
+	)
+	for _, c := range t.Columns {
+		fmt.Fprintf(w, "%s,\n", g.scanTarget("ent", c))
+	}
+	fmt.Fprintf(w, `}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := &%sLateral{%s: &ent, %s: make(map[string]interface{}, len(lateral))}
+		for i, cn := range lateral {
+			row.%s[cn] = values[i]
+		}
+		res = append(res, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+`, entityName, g.name("Entity"), g.name("Lateral"), g.name("Lateral"))
+	g.generateRepositoryContextFree(w, t, "FindLateral", "c *"+entityName+"Criteria", "c", "([]*"+entityName+"Lateral, error)")
+}
+
+func (g *Generator) generateRepositoryCount(w io.Writer, t *pqt.Table) {
+	entityName := g.name(tableIdent(t))
+
+	fmt.Fprintf(w, `// %s returns query and arguments used by %s to count entities that match given criteria, without executing it.
+func (r *%sRepositoryBase) %s(c *%sCriteria) (string, []interface{}, error) {
+`, g.name("countQuery"), g.methodName("count"), entityName, g.name("countQuery"), entityName)
+	fmt.Fprintf(w, `
+	buf := bytes.NewBufferString("SELECT ")
+	if c.%s != "" {
+		var known bool
+		for _, cn := range %sColumns {
+			if cn == c.%s {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return "", nil, fmt.Errorf("%s count failure, unknown column: %%s", c.%s)
+		}
+		buf.WriteString("COUNT(DISTINCT " + c.%s + ")")
+	} else {
+		buf.WriteString("COUNT(*)")
+	}
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+
+	// The same condition as used by find, but without sort, offset and limit that make no sense for an aggregate.
+	where, args, err := r.%s(c)
+	if err != nil {
+		return "", nil, err
+	}
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+
+	return buf.String(), args, nil
+}
+
+// %s returns number of entities that match given criteria. Sort, offset, limit and lock are ignored.
+func (r *%sRepositoryBase) %s(%sc *%sCriteria) (int64, error) {
+	query, args, err := r.%s(c)
+	if err != nil {
+		return 0, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Count"); err != nil {
+			return 0, err
+		}
+`+g.explainCall("query", "args", "0")+`	}
+
+%s	var count int64
+	if err := %s.%squery, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+`, g.name("countDistinct"), g.name("table")+g.public(tableIdent(t)), g.name("countDistinct"),
+		entityName, g.name("countDistinct"), g.name("countDistinct"),
+		g.name("plan"),
+		g.methodName("count"),
+		entityName, g.methodName("count"), g.contextArg(), entityName,
+		g.name("countQuery"),
+		g.countQuerier(), g.countQuerierName(),
+		g.dbCall("QueryRow"))
+	g.generateRepositoryContextFree(w, t, "count", "c *"+entityName+"Criteria", "c", "(int64, error)")
+}
+
+// generateRepositoryCountDistinct generates shorthand for count method that counts distinct values of given column.
+func (g *Generator) generateRepositoryCountDistinct(w io.Writer, t *pqt.Table) {
+	entityName := g.name(tableIdent(t))
+
+	fmt.Fprintf(w, `// %s returns number of distinct values of given column among entities that match given criteria.
+func (r *%sRepositoryBase) %s(%scn string, c *%sCriteria) (int64, error) {
+	cc := *c
+	cc.%s = cn
+
+	return r.%s(%s&cc)
+}
+`, g.methodName("countDistinct"), entityName, g.methodName("countDistinct"), g.contextArg(), entityName,
+		g.name("countDistinct"),
+		g.methodName("count"), g.contextParam())
+	g.generateRepositoryContextFree(w, t, "countDistinct", "cn string, c *"+entityName+"Criteria", "cn, c", "(int64, error)")
+}
+
+// generateRepositoryAggregate generates method that computes single aggregate function, optionally grouped by single column.
+// Function is one of pqt.Aggregate, names of columns are validated against columns of the table.
+func (g *Generator) generateRepositoryAggregate(w io.Writer, t *pqt.Table) {
+	entityName := g.name(tableIdent(t))
+
+	fmt.Fprintf(w, `// %s computes given aggregate function of the column among entities that match given criteria, grouped by values of groupBy column.
+// Column can be empty for pqt.AggregateCount, which then counts entities. If groupBy is empty, single result with nil group is returned.
+// Groups are ordered by their values. Sort, offset, limit and lock are ignored.
+// Unknown functions and columns are rejected, as well as SUM and AVG of non-numeric column, whose MIN and MAX are returned in Raw field.
+func (r *%sRepositoryBase) %s(%sc *%sCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error) {
+	if fn.String() == "" {
+		return nil, fmt.Errorf("%s aggregate failure, unknown function: %%d", fn)
+	}
+	if column == "" && fn != pqt.AggregateCount {
+		return nil, fmt.Errorf("%s aggregate failure, column is required by %%s", fn)
+	}
+	for _, cn := range []string{column, groupBy} {
+		if cn == "" {
+			continue
+		}
+		var known bool
+		for _, tc := range %sColumns {
+			if tc == cn {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("%s aggregate failure, unknown column: %%s", cn)
+		}
+	}
+	// Result of COUNT and of any function of a numeric column is a number, other values are scanned as they are.
+	numeric := fn == pqt.AggregateCount
+`+g.numericColumnsCase(t)+`	if !numeric && (fn == pqt.AggregateSum || fn == pqt.AggregateAvg) {
+		return nil, fmt.Errorf("%s aggregate failure, %%s requires numeric column, got: %%s", fn, column)
+	}
+
+	expr := "*"
+	if column != "" {
+		expr = column
+	}
+	buf := bytes.NewBufferString("SELECT ")
+	if groupBy != "" {
+		buf.WriteString(groupBy)
+		buf.WriteString(", ")
+	}
+	buf.WriteString(fn.String() + "(" + expr + ")")
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+
+	where, args, err := r.%s(c)
+	if err != nil {
+		return nil, err
+	}
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+	if groupBy != "" {
+		buf.WriteString(" GROUP BY " + groupBy + " ORDER BY " + groupBy)
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Aggregate"); err != nil {
+			return nil, err
+		}
+`+g.explainCall("buf.String()", "args", "nil")+`	}
+
+	rows, err := r.db.%sbuf.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []pqt.AggregateResult
+	for rows.Next() {
+		var (
+			group, raw interface{}
+			// Aggregate of a group that has no values, e.g. SUM of nulls, is null.
+			value sql.NullFloat64
+		)
+		dest := []interface{}{&value}
+		if !numeric {
+			dest[0] = &raw
+		}
+		if groupBy != "" {
+			dest = append([]interface{}{&group}, dest...)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		res = append(res, pqt.AggregateResult{Group: group, Value: value.Float64, Raw: raw})
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return res, nil
+}
+`, g.methodName("aggregate"), entityName, g.methodName("aggregate"), g.contextArg(), entityName,
+		entityName,
+		entityName,
+		g.name("table")+g.public(tableIdent(t)),
+		entityName,
+		entityName,
+		g.name("plan"),
+		g.dbCall("Query"),
+	)
+	g.generateRepositoryContextFree(w, t, "aggregate", "c *"+entityName+"Criteria, fn pqt.Aggregate, column, groupBy string", "c, fn, column, groupBy", "([]pqt.AggregateResult, error)")
+}
+
+// numericColumnsCase generates statement that marks column given to the aggregate method as numeric,
+// nothing if the table has no numeric columns.
+func (g *Generator) numericColumnsCase(t *pqt.Table) string {
+	var columns []string
+	for _, c := range t.Columns {
+		if numericType(c.Type) {
+			columns = append(columns, g.columnNameWithTableName(tableIdent(t), c.Name))
+		}
+	}
+	if len(columns) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("	switch column {\n	case %s:\n		numeric = true\n	}\n", strings.Join(columns, ", "))
+}
+
+// numericType returns true if values of given type are numbers in the database, e.g. they can be summed up.
+func numericType(t pqt.Type) bool {
+	switch tt := t.(type) {
+	case pqt.MappableType:
+		return numericType(tt.From)
+	case pqt.DomainType:
+		return numericType(tt.Base)
+	case pqt.BaseType:
+		for _, name := range []string{"SMALLINT", "INTEGER", "BIGINT", "SMALLSERIAL", "SERIAL", "BIGSERIAL", "REAL", "DOUBLE PRECISION", "NUMERIC", "DECIMAL"} {
+			if tt.String() == name || strings.HasPrefix(tt.String(), name+"(") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// generateRepositoryExists generates method that checks if any entity matches the criteria using EXISTS subquery.
+func (g *Generator) generateRepositoryExists(w io.Writer, t *pqt.Table) {
+	entityName := g.name(tableIdent(t))
+
+	fmt.Fprintf(w, `// %s returns true if at least one entity matches given criteria, database stops at the first matching row.
+// Sort, offset and limit are ignored.
+func (r *%sRepositoryBase) %s(%sc *%sCriteria) (bool, error) {
+	buf := bytes.NewBufferString("SELECT EXISTS (SELECT 1 FROM ")
+	buf.WriteString(r.table)
+
+	where, args, err := r.%s(c)
+	if err != nil {
+		return false, err
+	}
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+	buf.WriteString(")")
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Exists"); err != nil {
+			return false, err
+		}
+`+g.explainCall("buf.String()", "args", "false")+`	}
+
+	var exists bool
+	if err := r.db.%sbuf.String(), args...).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+`, g.methodName("exists"), entityName, g.methodName("exists"), g.contextArg(), entityName,
+		g.name("plan"),
+		g.dbCall("QueryRow"),
+	)
+	g.generateRepositoryContextFree(w, t, "exists", "c *"+entityName+"Criteria", "c", "(bool, error)")
+}
+
+func (g *Generator) generateRepositoryFindOneByPrimaryKey(code *bytes.Buffer, table *pqt.Table) {
+	entityName := g.name(tableIdent(table))
+	pk, ok := primaryKey(table)
+	if !ok {
+		return
+	}
+	suffix, arguments, values, where := g.keyArguments(pk)
+
+	columns := make([]string, 0, len(table.Columns))
+	for _, c := range table.Columns {
+		columns = append(columns, c.Name)
+	}
+
+	fmt.Fprintf(code, `// %s returns query and arguments used by %s to retrieve the entity, without executing it.
+func (r *%sRepositoryBase) %s(%s) (string, []interface{}, error) {
+	return `+"`"+`SELECT %s FROM %s WHERE %s%s`+"`"+`, []interface{}{%s}, nil
+}
+
+func (r *%sRepositoryBase) %s(%s%s) (*%sEntity, error) {
+	query, args, err := r.%s(%s)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "FindOneBy%s"); err != nil {
+			return nil, err
+		}
+	}
+
+	var ent %sEntity
+	err = %s.%squery, args...).Scan(
+	`, g.name("FindOneBy"+suffix+"Query"), g.methodName("FindOneBy"+suffix), entityName, g.name("FindOneBy"+suffix+"Query"), arguments,
+		strings.Join(columns, ", "), table.QuotedName(), where, softDeleteCondition(table), values,
+		entityName, g.methodName("FindOneBy"+suffix), g.contextArg(), arguments, entityName,
+		g.name("FindOneBy"+suffix+"Query"), values,
+		suffix,
+		entityName, g.querier(), g.dbCall("QueryRow"))
+	for _, c := range table.Columns {
+		fmt.Fprintf(code, "%s,\n", g.scanTarget("ent", c))
+	}
+	fmt.Fprint(code, `)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ent, nil
+}
+`)
+	g.generateRepositoryContextFree(code, table, "FindOneBy"+suffix,
+		arguments,
+		values,
+		"(*"+entityName+"Entity, error)",
+	)
+}
+
+func (g *Generator) generateRepositoryFindOneByUniqueConstraint(code *bytes.Buffer, table *pqt.Table) {
+	entityName := g.name(tableIdent(table))
+	var unique []*pqt.Constraint
+	for _, c := range tableConstraints(table) {
+		// Conditional unique constraint does not guarantee that single row matches the key.
+		if c.Type == pqt.ConstraintTypeUnique && c.Where == "" {
+			unique = append(unique, c)
+		}
+	}
+	if len(unique) < 1 {
+		return
+	}
+
+	for _, u := range unique {
+		arguments := ""
+		values := ""
+		methodName := "FindOneBy"
+		for i, c := range u.Columns {
+			if i != 0 {
+				methodName += "And"
+				arguments += ", "
+				values += ", "
+			}
+			methodName += g.public(c.Name)
+			arguments += fmt.Sprintf("%s %s", g.private(c.Name), g.generateColumnTypeString(c, modeMandatory))
+			values += g.private(c.Name)
+		}
+		fmt.Fprintf(code, "// %s retrieves single entity using %s unique constraint, %s is returned if it does not exist.\n", g.methodName(methodName), u.Name(), g.errNoRows())
+		fmt.Fprintf(code, `func (r *%sRepositoryBase) %s(%s%s) (*%sEntity, error) {`, entityName, g.methodName(methodName), g.contextArg(), arguments, entityName)
+		fmt.Fprintf(code, `var (
+			ent %sEntity
+		)`, entityName)
+		code.WriteRune('\n')
+		fmt.Fprint(code, "query := `SELECT ")
+		for i, c := range table.Columns {
+			if i != 0 {
+				code.WriteString(", ")
+			}
+			fmt.Fprintf(code, "%s", c.Name)
+		}
+		fmt.Fprintf(code, " FROM %s WHERE ", table.QuotedName())
+		for i, c := range u.Columns {
+			if i != 0 {
+				fmt.Fprint(code, " AND ")
+			}
+			fmt.Fprintf(code, "%s = $%d", c.Name, i+1)
+		}
+		fmt.Fprintf(code, "%s`\n", softDeleteCondition(table))
+
+		fmt.Fprintf(code, "err := r.db.%squery, %s).Scan(\n", g.dbCall("QueryRow"), values)
+		for _, c := range table.Columns {
+			fmt.Fprintf(code, "%s,\n", g.scanTarget("ent", c))
+		}
+		fmt.Fprint(code, `)
+			if err != nil {
+				return nil, err
+			}
+
+			return &ent, nil
+	}
+	`)
+		g.generateRepositoryContextFree(code, table, methodName, arguments, values, "(*"+entityName+"Entity, error)")
+	}
+}
+
+func (g *Generator) generateRepositoryInsert(w io.Writer,
+	table *pqt.Table) {
+	entityName := g.name(tableIdent(table))
+
+	fmt.Fprintf(w, `// %s returns query and arguments used by %s to save given entity, without executing it.
+func (r *%sRepositoryBase) %s(e *%sEntity) (string, []interface{}, error) {`, g.name("insertQuery"), g.methodName("Insert"), entityName, g.name("insertQuery"), entityName)
+	g.generateRepositoryInsertQuery(w, table, "", `return "", nil,`, `
+			if len(r.columns) > 0 {
+				b.WriteString(" RETURNING ")
+				b.WriteString(strings.Join(r.columns, ", "))
+			}`)
+	fmt.Fprint(w, `return b.String(), insert.Args(), nil
+}
+
+`)
+
+	fmt.Fprintf(w, "// %s saves given entity and returns it with values read back from the database, e.g. defaults.\n", g.methodName("Insert"))
+	fmt.Fprintf(w, `func (r *%sRepositoryBase) %s(%se *%sEntity) (*%sEntity, error) {
+	`+g.hookCall("beforeInsert", "e", "return nil,")+`
+	query, args, err := r.%s(e)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Insert"); err != nil {
+			return nil, err
+		}
+	}
+
+	`, entityName, g.methodName("Insert"), g.contextArg(), entityName, entityName, g.name("insertQuery"))
+	fmt.Fprintf(w, "err = %s.%squery, args...).Scan(\n", g.querier(), g.dbCall("QueryRow"))
+
+	for _, c := range table.Columns {
+		fmt.Fprintf(w, "%s,\n", g.scanTarget("e", c))
+	}
+	fmt.Fprint(w, `)
+		if err != nil {
+			return nil, err
+		}
+		`+g.hookCall("afterInsert", "e", "return nil,")+`
+		return e, nil
+	}
+`)
+	g.generateRepositoryContextFree(w, table, "Insert", "e *"+entityName+"Entity", "e", "(*"+entityName+"Entity, error)")
+}
+
+// generateRepositoryInsertReturning generates insert method that returns only columns listed by pqt.WithReturning table option.
+func (g *Generator) generateRepositoryInsertReturning(w io.Writer, table *pqt.Table) {
+	if len(table.Returning) == 0 {
+		return
+	}
+	entityName := g.name(tableIdent(table))
+
+	fmt.Fprintf(w, "// %s works like %s, but reads back only columns given by pqt.WithReturning table option.\n", g.methodName("InsertReturning"), g.methodName("Insert"))
+	fmt.Fprintf(w, `func (r *%sRepositoryBase) %s(%se *%sEntity) (*%sReturning, error) {
+	`+g.hookCall("beforeInsert", "e", "return nil,"), entityName, g.methodName("InsertReturning"), g.contextArg(), entityName, entityName)
+	g.generateRepositoryInsertQuery(w, table, "InsertReturning", "return nil,", `
+			b.WriteString(" RETURNING " + `+g.returningColumns(table)+`)`)
+	fmt.Fprintf(w, "var ret %sReturning\n", entityName)
+	fmt.Fprintf(w, "err := r.db.%sb.String(), insert.Args()...).Scan(\n", g.dbCall("QueryRow"))
+	for _, c := range table.Returning {
+		fmt.Fprintf(w, "%s,\n", g.scanTarget("ret", c))
+	}
+	fmt.Fprint(w, `)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	}
+`)
+	g.generateRepositoryContextFree(w, table, "InsertReturning", "e *"+entityName+"Entity", "e", "(*"+entityName+"Returning, error)")
+}
+
+// generateRepositoryInsertReturningColumns generates insert method that returns only columns given at runtime.
+// Column names are validated before the query is executed.
+func (g *Generator) generateRepositoryInsertReturningColumns(w io.Writer, table *pqt.Table) {
+	entityName := g.name(tableIdent(table))
+
+	fmt.Fprintf(w, "// %s works like %s, but returns new entity with only given columns populated, unknown columns are rejected before execution.\n", g.methodName("InsertReturningColumns"), g.methodName("Insert"))
+	fmt.Fprintf(w, `func (r *%sRepositoryBase) %s(%se *%sEntity, cols ...string) (*%sEntity, error) {
+	`+g.hookCall("beforeInsert", "e", "return nil,"), entityName, g.methodName("InsertReturningColumns"), g.contextArg(), entityName, entityName)
+	g.generateRepositoryReturningColumnsProps(w, table, "insert")
+	g.generateRepositoryInsertQuery(w, table, "InsertReturningColumns", "return nil,", `
+			b.WriteString(" RETURNING " + strings.Join(cols, ", "))`)
+	fmt.Fprintf(w, `err = r.db.%sb.String(), insert.Args()...).Scan(props...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ent, nil
+	}
+`, g.dbCall("QueryRow"))
+	g.generateRepositoryContextFree(w, table, "InsertReturningColumns", "e *"+entityName+"Entity, cols ...string", "e, cols...", "(*"+entityName+"Entity, error)")
+}
+
+// generateRepositoryReturningColumnsProps generates part of the method that collects properties of a new entity for given columns.
+func (g *Generator) generateRepositoryReturningColumnsProps(w io.Writer, table *pqt.Table, action string) {
+	fmt.Fprintf(w, `
+		if len(cols) == 0 {
+			return nil, errors.New("%s %s failure, no columns to return")
+		}
+		var ent %sEntity
+		props, err := ent.%s(cols...)
+		if err != nil {
+			return nil, err
+		}
+	`, g.name(tableIdent(table)), action, g.name(tableIdent(table)), g.name("props"))
+}
+
+// generateRepositoryInsertQuery generates part of the insert method that builds the query.
+// Given returning code is placed right after the VALUES clause, ret is return statement prefix used to return an error.
+// Query is logged in debug mode on behalf of given function, unless it is empty.
+func (g *Generator) generateRepositoryInsertQuery(w io.Writer, table *pqt.Table, function, ret, returning string) {
+	fmt.Fprintf(w, `
+		if err := e.%s(); err != nil {
+			%s err
+		}
+
+		insert := pqcomp.New(0, %d)
+	`, g.name("validate"), ret, len(table.Columns))
+
+	// Identity column is set only if entity holds explicit value, which for GENERATED ALWAYS requires OVERRIDING SYSTEM VALUE.
+	values := `b.WriteString(") VALUES (")`
+ColumnsLoop:
+	for _, c := range table.Columns {
+		if c.Generated != "" {
+			continue ColumnsLoop
+		}
+		if c.Identity != "" {
+			cond := g.setCondition("e", c)
+			if cond == "" {
+				continue ColumnsLoop
+			}
+			if c.Identity == pqt.IdentityAlways {
+				if !strings.Contains(values, "OVERRIDING") {
+					fmt.Fprintln(w, "overriding := false")
+					values = `b.WriteString(")")
+			if overriding {
+				b.WriteString(" OVERRIDING SYSTEM VALUE")
+			}
+			b.WriteString(" VALUES (")`
+				}
+				fmt.Fprintf(w, `
+					if %s {
+						insert.AddExpr(%s, "", %s)
+						overriding = true
+					}
+				`, cond, g.columnNameWithTableName(tableIdent(table), c.Name), g.argument("e", c))
+			} else {
+				fmt.Fprintf(w, `
+					if %s {
+						insert.AddExpr(%s, "", %s)
+					}
+				`, cond, g.columnNameWithTableName(tableIdent(table), c.Name), g.argument("e", c))
+			}
+			continue ColumnsLoop
+		}
+		switch c.Type {
+		case pqt.TypeSerial(), pqt.TypeSerialBig(), pqt.TypeSerialSmall():
+			continue ColumnsLoop
+		default:
+			if g.canBeNil(c, modeOptional) {
+				fmt.Fprintf(w, `
+					if e.%s != nil {
+						insert.AddExpr(%s, "", %s)
+					}
+				`,
+					g.propertyName(c.Name),
+					g.columnNameWithTableName(tableIdent(table), c.Name),
+					g.argument("e", c),
+				)
+			} else if cond := g.defaultCondition("e", c); cond != "" {
+				fmt.Fprintf(w, `
+					if %s {
+						insert.AddExpr(%s, "", %s)
+					}
+				`,
+					cond,
+					g.columnNameWithTableName(tableIdent(table), c.Name),
+					g.argument("e", c),
+				)
+			} else {
+				fmt.Fprintf(
+					w,
+					`insert.AddExpr(%s, "", %s)`,
+					g.columnNameWithTableName(tableIdent(table), c.Name),
+					g.argument("e", c),
+				)
+			}
+			fmt.Fprintln(w, "")
+		}
+	}
+	fmt.Fprintf(w, `
+		b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+		if insert.Len() != 0 {
+			b.WriteString(" (")
+			for insert.Next() {
+				if !insert.First() {
+					b.WriteString(", ")
+				}
+
+				fmt.Fprintf(b, "%%s", insert.Key())
+			}
+			insert.Reset()
+			%s
+			for insert.Next() {
+				if !insert.First() {
+					b.WriteString(", ")
+				}
+
+				fmt.Fprintf(b, "%%s", insert.PlaceHolder())
+			}
+			b.WriteString(")")%s
+		}
+
+	`, values, returning)
+	if function == "" {
+		return
+	}
+	fmt.Fprintf(w, `if r.dbg {
+			if err := r.log.Log("msg", b.String(), "function", "%s"); err != nil {
+				%s err
+			}
+		}
+
+	`, function, ret)
+}
+
+// returningColumns returns Go expression that concatenates names of the columns listed by pqt.WithReturning table option.
+func (g *Generator) returningColumns(table *pqt.Table) string {
+	columns := make([]string, 0, len(table.Returning))
+	for _, c := range table.Returning {
+		columns = append(columns, g.columnNameWithTableName(tableIdent(table), c.Name))
+	}
+
+	return strings.Join(columns, ` + ", " + `)
+}
+
+// generateRepositoryInsertBatch generates method that inserts multiple entities using multi-row INSERT statement.
+func (g *Generator) generateRepositoryInsertBatch(w io.Writer, table *pqt.Table) {
+	entityName := g.name(tableIdent(table))
+
+	columns := insertBatchColumns(table)
+	if len(columns) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, `// %s saves given entities using multi-row statements and returns them with values read back from the database.
+// Column with default value gets DEFAULT in rows that do not hold explicit value, like in %s.
+// All entities are validated first, so none of them is sent if any is invalid.
+`, g.methodName("InsertBatch"), g.methodName("Insert"))
+	fmt.Fprintf(w, `func (r *%sRepositoryBase) %s(%ses []*%sEntity) ([]*%sEntity, error) {
+	for _, e := range es {
+		`+g.hookCall("beforeInsert", "e", "return nil,")+`
+		if err := e.`+g.name("validate")+`(); err != nil {
+			return nil, err
+		}
+	}
+	for _, chunk := range pqtgo.Chunks(len(es), %d) {
+		batch := es[chunk[0]:chunk[1]]
+		com := pqtgo.NewComposer(int64(len(batch) * %d))
+		for i, e := range batch {
+			if i != 0 {
+				com.WriteString(", ")
+			}
+			com.WriteString("(")
+`, entityName, g.methodName("InsertBatch"), g.contextArg(), entityName, entityName, len(columns), len(columns))
+	for i, c := range columns {
+		if i != 0 {
+			fmt.Fprintln(w, `com.WriteString(", ")`)
+		}
+		// Column with default value is set the same way insert sets it, row that does not hold explicit value gets DEFAULT.
+		if cond := g.insertBatchCondition(c); cond != "" {
+			fmt.Fprintf(w, `if %s {
+				com.WritePlaceholder()
+				com.Add(%s)
+			} else {
+				com.WriteString("DEFAULT")
+			}
+`, cond, g.argument("e", c))
+			continue
+		}
+		fmt.Fprintln(w, "com.WritePlaceholder()")
+		fmt.Fprintf(w, "com.Add(%s)\n", g.argument("e", c))
+	}
+	fmt.Fprint(w, `com.WriteString(")")
+		}
+
+		b := bytes.NewBufferString("INSERT INTO " + r.table + " (")
+`)
+	for i, c := range columns {
+		if i != 0 {
+			fmt.Fprintln(w, `b.WriteString(", ")`)
+		}
+		fmt.Fprintf(w, "b.WriteString(%s)\n", g.columnNameWithTableName(tableIdent(table), c.Name))
+	}
+	fmt.Fprintf(w, `b.WriteString(") VALUES ")
+		b.ReadFrom(com)
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+
+		if r.dbg {
+			if err := r.log.Log("msg", b.String(), "function", "InsertBatch"); err != nil {
+				return nil, err
+			}
+		}
+
+		rows, err := r.db.%sb.String(), com.Args()...)
+		if err != nil {
+			return nil, err
+		}
+		returned := 0
+		for ; returned < len(batch) && rows.Next(); returned++ {
+			err = rows.Scan(
+`, g.dbCall("Query"))
+	for _, c := range table.Columns {
+		fmt.Fprintf(w, "%s,\n", g.scanTarget("batch[returned]", c))
+	}
+	fmt.Fprintf(w, `)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+		}
+		if err = rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+		if len(r.columns) > 0 && returned != len(batch) {
+			return nil, fmt.Errorf("%s insert batch failure, %%d rows inserted, but %%d returned", len(batch), returned)
+		}
+		for _, e := range batch {
+			`+g.hookCall("afterInsert", "e", "return nil,")+`
+		}
+	}
+
+	return es, nil
+}
+`, entityName)
+	g.generateRepositoryContextFree(w, table, "InsertBatch", "es []*"+entityName+"Entity", "es", "([]*"+entityName+"Entity, error)")
+}
+
+// generateRepositoryBulkInsert generates method that loads multiple entities using COPY FROM statement.
+// Unlike insertBatch, it does not return inserted rows. Number of rows sent by single COPY is controlled by bulkSize repository property.
+func (g *Generator) generateRepositoryBulkInsert(w io.Writer, table *pqt.Table) {
+	entityName := g.name(tableIdent(table))
+
+	columns := batchColumns(table)
+	if len(columns) == 0 {
+		return
+	}
+	names := make([]string, 0, len(columns))
+	for _, c := range columns {
+		names = append(names, g.columnNameWithTableName(tableIdent(table), c.Name))
+	}
+	if g.driver == DriverPgx {
+		g.generateRepositoryBulkInsertPgx(w, table, columns, names)
+		return
+	}
+	copyIn := fmt.Sprintf(`pq.CopyIn("%s", %s)`, table.Name, strings.Join(names, ", "))
+	if schema := table.SchemaName(); schema != "" {
+		copyIn = fmt.Sprintf(`pq.CopyInSchema("%s", "%s", %s)`, schema, table.Name, strings.Join(names, ", "))
+	}
+	ctx := "context.Background()"
+	if g.ctx {
+		ctx = "ctx"
+	}
+
+	fmt.Fprintf(w, `// %s loads given entities using COPY protocol and returns number of loaded rows.
+// Serial columns and columns with default value are omitted, they are populated by the database.
+// COPY does not support RETURNING clause, so generated values (e.g. ids) are not set on given entities.
+// All entities are validated first, so none of them is sent if any is invalid.
+// Database of the repository has to implement pqtgo.TxBeginner, like *sql.DB, or pqtgo.Preparer within a transaction, like *sql.Tx.
+func (r *%sRepositoryBase) %s(%ses []*%sEntity) (int64, error) {
+	for _, e := range es {
+		`+g.hookCall("beforeInsert", "e", "return 0,")+`
+		if err := e.`+g.name("validate")+`(); err != nil {
+			return 0, err
+		}
+	}
+	query := %s
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "BulkInsert"); err != nil {
+			return 0, err
+		}
+	}
+
+	return pqtgo.CopyIn(%s, r.db, query, len(es), r.bulkSize, func(i int) []interface{} {
+		return []interface{}{
+`, g.methodName("BulkInsert"), entityName, g.methodName("BulkInsert"), g.contextArg(), entityName, copyIn, ctx)
+	for _, c := range columns {
+		fmt.Fprintf(w, "%s,\n", g.argument("es[i]", c))
+	}
+	fmt.Fprint(w, `}
+	})
+}
+`)
+	g.generateRepositoryContextFree(w, table, "BulkInsert", "es []*"+entityName+"Entity", "es", "(int64, error)")
+}
+
+// generateRepositoryBulkInsertPgx generates bulkInsert method on top of CopyFrom of pgx.
+// Rows are streamed by single COPY, so bulkSize repository property is not used.
+func (g *Generator) generateRepositoryBulkInsertPgx(w io.Writer, table *pqt.Table, columns []*pqt.Column, names []string) {
+	entityName := g.name(tableIdent(table))
+	identifier := strconv.Quote(table.Name)
+	if schema := table.SchemaName(); schema != "" {
+		identifier = strconv.Quote(schema) + ", " + identifier
+	}
+
+	fmt.Fprintf(w, `// %s loads given entities using COPY protocol and returns number of loaded rows.
+// Serial columns and columns with default value are omitted, they are populated by the database.
+// COPY does not support RETURNING clause, so generated values (e.g. ids) are not set on given entities.
+// All entities are validated first, so none of them is sent if any is invalid.
+func (r *%sRepositoryBase) %s(%ses []*%sEntity) (int64, error) {
+	for _, e := range es {
+		`+g.hookCall("beforeInsert", "e", "return 0,")+`
+		if err := e.`+g.name("validate")+`(); err != nil {
+			return 0, err
+		}
+	}
+	columns := []string{%s}
+	if r.dbg {
+		if err := r.log.Log("msg", "COPY "+r.table, "function", "BulkInsert"); err != nil {
+			return 0, err
+		}
+	}
+
+	return r.db.CopyFrom(ctx, pgx.Identifier{%s}, columns, pgx.CopyFromSlice(len(es), func(i int) ([]interface{}, error) {
+		return []interface{}{
+`, g.methodName("BulkInsert"), entityName, g.methodName("BulkInsert"), g.contextArg(), entityName, strings.Join(names, ", "), identifier)
+	for _, c := range columns {
+		fmt.Fprintf(w, "%s,\n", g.argument("es[i]", c))
+	}
+	fmt.Fprint(w, `}, nil
+	}))
+}
+`)
+	g.generateRepositoryContextFree(w, table, "BulkInsert", "es []*"+entityName+"Entity", "es", "(int64, error)")
+}
+
+func (g *Generator) generateRepositoryUpsert(code *bytes.Buffer, table *pqt.Table) {
+	if g.ver < 9.5 {
+		return
+	}
+	entityName := g.name(tableIdent(table))
+
+	fmt.Fprintf(code, `// %s saves given entity, on conflict with given target it updates existing row using the patch, or values of the entity if patch is nil.
+// pqt.ErrUpsertIgnored is returned if conflicting row was not updated.
+`, g.methodName("UpsertOn"))
+	fmt.Fprintf(code, `func (r *%sRepositoryBase) %s(%se *%sEntity, p *%sPatch, ct pqt.UpsertConflictTarget) (*%sEntity, error) {`,
+		entityName, g.methodName("UpsertOn"), g.contextArg(),
+		entityName, entityName, entityName,
+	)
+	fmt.Fprintf(code, `
+		`+g.hookCall("beforeInsert", "e", "return nil,")+`
+		if err := e.%s(); err != nil {
+			return nil, err
+		}
+		if p != nil {
+			if err := p.%s(); err != nil {
+				return nil, err
+			}
+		}
+
+		insert := pqcomp.New(0, %d)
+		update := insert.Compose(%d)
+	`, g.name("validate"), g.name("validate"), len(table.Columns), len(table.Columns))
+
+InsertLoop:
+	for _, c := range table.Columns {
+		if c.Generated != "" || c.Identity != "" {
+			continue InsertLoop
+		}
+		switch c.Type {
+		case pqt.TypeSerial(), pqt.TypeSerialBig(), pqt.TypeSerialSmall():
+			continue InsertLoop
+		default:
+			if g.canBeNil(c, modeOptional) {
+				fmt.Fprintf(code, `
+					if e.%s != nil {
+						insert.AddExpr(%s, "", %s)
+					}
+				`,
+					g.propertyName(c.Name),
+					g.columnNameWithTableName(tableIdent(table), c.Name), g.argument("e", c),
+				)
+			} else if cond := g.defaultCondition("e", c); cond != "" {
+				fmt.Fprintf(code, `
+					if %s {
+						insert.AddExpr(%s, "", %s)
+					}
+				`,
+					cond,
+					g.columnNameWithTableName(tableIdent(table), c.Name), g.argument("e", c),
+				)
+			} else {
+				fmt.Fprintf(code, `insert.AddExpr(%s, "", %s)`,
+					g.columnNameWithTableName(tableIdent(table), c.Name),
+					g.argument("e", c),
+				)
+			}
+			fmt.Fprintln(code, "")
+		}
+	}
+	vc := versionColumn(table)
+	pk, _ := primaryKey(table)
+	fmt.Fprintln(code, "if p != nil && !ct.IsZero() {")
+UpdateLoop:
+	for _, c := range table.Columns {
+		if pk.Contains(c) || c.Generated != "" || c.Identity != "" || c == vc {
+			continue UpdateLoop
+		}
+		switch c.Type {
+		case pqt.TypeSerial(), pqt.TypeSerialBig(), pqt.TypeSerialSmall():
+			continue UpdateLoop
+		default:
+			if g.canBeNil(c, modeOptional) {
+				fmt.Fprintf(code, `
+					if p.%s != nil {
+						update.AddExpr(%s, "=", %s)
+					}
+				`,
+					g.propertyName(c.Name),
+					g.columnNameWithTableName(tableIdent(table), c.Name),
+					g.argument("p", c),
+				)
+			} else {
+				fmt.Fprintf(code, `update.AddExpr(%s, "=", %s)`, g.columnNameWithTableName(tableIdent(table), c.Name), g.argument("p", c))
+			}
+			fmt.Fprintln(code, "")
+		}
+	}
+	fmt.Fprintln(code, "}")
+
+	fmt.Fprint(code, `
+		b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+		if insert.Len() > 0 {
+			b.WriteString(" (")
+			for insert.Next() {
+				if !insert.First() {
+					b.WriteString(", ")
+				}
+
+				fmt.Fprintf(b, "%s", insert.Key())
+			}
+			insert.Reset()
+			b.WriteString(") VALUES (")
+			for insert.Next() {
+				if !insert.First() {
+					b.WriteString(", ")
+				}
+
+				fmt.Fprintf(b, "%s", insert.PlaceHolder())
+			}
+			b.WriteString(")")
+		}
+		var excluded []string
+		if p == nil && !ct.IsZero() {
+			insert.Reset()
+			for insert.Next() {
+				if !ct.HasColumn(insert.Key())`)
+	if vc != nil {
+		fmt.Fprintf(code, " && insert.Key() != %s", g.columnNameWithTableName(tableIdent(table), vc.Name))
+	}
+	fmt.Fprint(code, ` {
+					excluded = append(excluded, insert.Key()+" = EXCLUDED."+insert.Key())
+				}
+			}
+		}
+
+		b.WriteString(" ON CONFLICT ")
+		if !ct.IsZero() {
+			b.WriteString(ct.String())
+		}
+		switch {
+		case len(excluded) > 0:
+			b.WriteString(" DO UPDATE SET ")
+			b.WriteString(strings.Join(excluded, ", "))
+`)
+	g.generateUpsertVersionIncrement(code, table)
+	fmt.Fprint(code, `		case !ct.IsZero() && update.Len() > 0:
+			b.WriteString(" DO UPDATE SET ")
+			for update.Next() {
+				if !update.First() {
+					b.WriteString(", ")
+				}
+
+				b.WriteString(update.Key())
+				b.WriteString(" ")
+				b.WriteString(update.Oper())
+				b.WriteString(" ")
+				b.WriteString(update.PlaceHolder())
+			}
+`)
+	g.generateUpsertVersionIncrement(code, table)
+	fmt.Fprint(code, `		default:
+			b.WriteString(" DO NOTHING ")
+		}
+		if insert.Len() > 0 {
+			if len(r.columns) > 0 {
+				b.WriteString(" RETURNING ")
+				b.WriteString(strings.Join(r.columns, ", "))
+			}
+		}
+
+		if r.dbg {
+			if err := r.log.Log("msg", b.String(), "function", "Upsert"); err != nil {
+				return nil, err
+			}
+		}
+
+	`)
+	fmt.Fprintf(code, "\terr := r.db.%sb.String(), insert.Args()...).Scan(\n\t", g.dbCall("QueryRow"))
+
+	for _, c := range table.Columns {
+		fmt.Fprintf(code, "%s,\n", g.scanTarget("e", c))
+	}
+	fmt.Fprint(code, `)
+		if err != nil {
+			if err == `+g.errNoRows()+` {
+				return nil, pqt.ErrUpsertIgnored
+			}
+			return nil, err
+		}
+
+		return e, nil
+	}
+`)
+	g.generateRepositoryContextFree(code, table, "UpsertOn",
+		"e *"+entityName+"Entity, p *"+entityName+"Patch, ct pqt.UpsertConflictTarget",
+		"e, p, ct",
+		"(*"+entityName+"Entity, error)",
+	)
+
+	fmt.Fprintf(code, `
+// %s is a shorthand for %s, given columns are used to infer the conflict target.
+func (r *%sRepositoryBase) %s(%se *%sEntity, p *%sPatch, inf ...string) (*%sEntity, error) {
+	return r.%s(%se, p, pqt.ConflictOnColumns(inf...))
+}
+`,
+		g.methodName("Upsert"), g.methodName("UpsertOn"),
+		entityName, g.methodName("Upsert"), g.contextArg(), entityName, entityName, entityName,
+		g.methodName("UpsertOn"), g.contextParam(),
+	)
+	g.generateRepositoryContextFree(code, table, "Upsert",
+		"e *"+entityName+"Entity, p *"+entityName+"Patch, inf ...string",
+		"e, p, inf...",
+		"(*"+entityName+"Entity, error)",
+	)
+}
+
+func (g *Generator) generateRepositoryUpdateOneByUniqueConstraint(w io.Writer, table *pqt.Table) {
+	entityName := g.name(tableIdent(table))
+	var unique []*pqt.Constraint
+	for _, c := range tableConstraints(table) {
+		// Conditional unique constraint does not guarantee that single row matches the key.
+		if c.Type == pqt.ConstraintTypeUnique && c.Where == "" {
+			unique = append(unique, c)
+		}
+	}
+	if len(unique) < 1 {
+		return
+	}
+
+	for _, u := range unique {
+		arguments := ""
+		values := ""
+		methodName := "UpdateOneBy"
+		for i, c := range u.Columns {
+			if i != 0 {
+				methodName += "And"
+				arguments += ", "
+				values += ", "
+			}
+			methodName += g.public(c.Name)
+			arguments += fmt.Sprintf("%s %s", g.private(c.Name), g.generateColumnTypeString(c, modeMandatory))
+			values += g.private(c.Name)
+		}
+		fmt.Fprintf(w, `func (r *%sRepositoryBase) %s(%s%s, patch *%sPatch) (*%sEntity, error) {
+		`, entityName, g.methodName(methodName), g.contextArg(), arguments, entityName, entityName)
+		fmt.Fprintf(w, `if err := patch.%s(); err != nil {
+			return nil, err
+		}
+		`, g.name("validate"))
+		fmt.Fprintf(w, "update := pqcomp.New(%d, %d)\n", len(u.Columns), len(table.Columns))
+		for _, c := range u.Columns {
+			fmt.Fprintf(w, "update.AddArg(%s)\n", g.private(c.Name))
+		}
+		pk, _ := primaryKey(table)
+	ColumnsLoop:
+		for _, c := range table.Columns {
+			if pk.Contains(c) || c.Generated != "" || c.Identity == pqt.IdentityAlways || c == versionColumn(table) {
+				continue ColumnsLoop
+			}
+			for _, uc := range u.Columns {
+				if c == uc {
+					continue
+				}
+			}
+			if _, ok := c.DefaultOn(pqt.EventInsert, pqt.EventUpdate); ok {
+				switch c.Type {
+				case pqt.TypeTimestamp(), pqt.TypeTimestampTZ():
+					fmt.Fprintf(w, "if patch.%s != nil {\n", g.propertyName(c.Name))
+
+				}
+			} else if g.canBeNil(c, modeOptional) {
+				fmt.Fprintf(w, "if patch.%s != nil {\n", g.propertyName(c.Name))
+			}
+
+			fmt.Fprint(w, "update.AddExpr(")
+			g.writeTableNameColumnNameTo(w, tableIdent(c.Table), c.Name)
+			fmt.Fprintf(w, ", pqcomp.Equal, %s)\n", g.argument("patch", c))
+
+			if d, ok := c.DefaultOn(pqt.EventUpdate); ok {
+				switch c.Type {
+				case pqt.TypeTimestamp(), pqt.TypeTimestampTZ():
+					fmt.Fprint(w, `} else {`)
+					fmt.Fprint(w, "update.AddExpr(")
+					g.writeTableNameColumnNameTo(w, tableIdent(c.Table), c.Name)
+					fmt.Fprintf(w, `, pqcomp.Equal, "%s")`, d)
+				}
+			}
+			if _, ok := c.DefaultOn(pqt.EventInsert, pqt.EventUpdate); ok {
+				switch c.Type {
+				case pqt.TypeTimestamp(), pqt.TypeTimestampTZ():
+					fmt.Fprint(w, "\n}\n")
+				}
+			} else if g.canBeNil(c, modeOptional) {
+				fmt.Fprint(w, "\n}\n")
+			}
+		}
+
+		fmt.Fprint(w, `
+	if update.Len() == 0 {
+		return nil, pqt.ErrNothingToUpdate
+	}`)
+
+		fmt.Fprintf(w, `
+	query := "UPDATE %s SET "
+	for update.Next() {
+		if !update.First() {
+			query += ", "
+		}
+
+		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
+	}
+`, queryName(table))
+		g.generateVersionIncrement(w, table)
+		fmt.Fprint(w, `query += " WHERE `)
+		for i, c := range u.Columns {
+			if i != 0 {
+				fmt.Fprint(w, " AND ")
+			}
+			fmt.Fprintf(w, "%s = $%d", c.Name, i+1)
+		}
+		fmt.Fprintf(w, ` RETURNING " + strings.Join(r.columns, ", ")
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "%s"); err != nil {
+			return nil, err
+		}
+	}
+	var e %sEntity
+	err := r.db.%squery, update.Args()...).Scan(
+	`, methodName, entityName, g.dbCall("QueryRow"))
+		for _, c := range table.Columns {
+			fmt.Fprintf(w, "%s,\n", g.scanTarget("e", c))
+		}
+		fmt.Fprint(w, `)
+if err != nil {
+	return nil, err
 }
+`)
+		if _, ok := primaryKey(table); ok {
+			fmt.Fprint(w, g.hookCall("afterUpdate", "&e", "return nil,"))
+		}
+		fmt.Fprint(w, `
 
-func (g *Generator) generateRepositoryUpsert(code *bytes.Buffer, table *pqt.Table) {
+return &e, nil
+}
+`)
+		g.generateRepositoryContextFree(w, table, methodName,
+			arguments+", patch *"+entityName+"Patch",
+			values+", patch",
+			"(*"+entityName+"Entity, error)",
+		)
+	}
+}
+
+// generateRepositoryUpdateOrInsertByUniqueConstraint generates method for each unique constraint,
+// that inserts entity or, if row with the same key already exists, updates it using values of the entity.
+// It is a single INSERT ... ON CONFLICT statement, so it is free of races, xmax system column tells if row was inserted.
+func (g *Generator) generateRepositoryUpdateOrInsertByUniqueConstraint(w io.Writer, table *pqt.Table) {
 	if g.ver < 9.5 {
 		return
 	}
-	entityName := g.name(table.Name)
+	entityName := g.name(tableIdent(table))
+	vc := versionColumn(table)
 
-	fmt.Fprintf(code, `func (r *%sRepositoryBase) %s(e *%sEntity, p *%sPatch, inf ...string) (*%sEntity, error) {`,
-		entityName, g.name("Upsert"),
-		entityName, entityName, entityName,
-	)
-	fmt.Fprintf(code, `
-		insert := pqcomp.New(0, %d)
-		update := insert.Compose(%d)
-	`, len(table.Columns), len(table.Columns))
+	for _, u := range tableConstraints(table) {
+		// Conditional unique constraint cannot be used as a conflict target without its predicate.
+		if u.Type != pqt.ConstraintTypeUnique || u.Where != "" {
+			continue
+		}
 
-InsertLoop:
-	for _, c := range table.Columns {
-		switch c.Type {
-		case pqt.TypeSerial(), pqt.TypeSerialBig(), pqt.TypeSerialSmall():
-			continue InsertLoop
-		default:
-			if g.canBeNil(c, modeOptional) {
-				fmt.Fprintf(code, `
-					if e.%s != nil {
-						insert.AddExpr(%s, "", e.%s)
-					}
-				`,
-					g.propertyName(c.Name),
-					g.columnNameWithTableName(table.Name, c.Name), g.propertyName(c.Name),
-				)
-			} else {
-				fmt.Fprintf(code, `insert.AddExpr(%s, "", e.%s)`,
-					g.columnNameWithTableName(table.Name, c.Name),
-					g.propertyName(c.Name),
-				)
+		methodName := "UpdateOrInsertBy"
+		keys := make([]string, 0, len(u.Columns))
+		names := make([]string, 0, len(u.Columns))
+		for i, c := range u.Columns {
+			if i != 0 {
+				methodName += "And"
 			}
-			fmt.Fprintln(code, "")
+			methodName += g.public(c.Name)
+			keys = append(keys, g.columnNameWithTableName(tableIdent(table), c.Name))
+			names = append(names, c.Name)
+		}
+		// Neither primary key nor column that is always generated by the database is overwritten.
+		pk, _ := primaryKey(table)
+		for _, c := range table.Columns {
+			if (pk.Contains(c) || c.Identity == pqt.IdentityAlways || c == vc) && !u.Columns.Contains(c) {
+				keys = append(keys, g.columnNameWithTableName(tableIdent(table), c.Name))
+			}
+		}
+
+		fmt.Fprintf(w, `// %s inserts given entity or updates existing one that has the same %s.
+// Updated row gets all values that insert would set, except the key itself. Returned flag is true if row was inserted.
+func (r *%sRepositoryBase) %s(%se *%sEntity) (*%sEntity, bool, error) {
+	`+g.hookCall("beforeInsert", "e", "return nil, false,"),
+			g.methodName(methodName), strings.Join(names, " and "),
+			entityName, g.methodName(methodName), g.contextArg(), entityName, entityName,
+		)
+		returning := new(bytes.Buffer)
+		fmt.Fprintf(returning, `
+			var excluded []string
+			insert.Reset()
+			for insert.Next() {
+				switch insert.Key() {
+				case %s:
+				default:
+					excluded = append(excluded, insert.Key()+" = EXCLUDED."+insert.Key())
+				}
+			}
+			if len(excluded) == 0 {
+				// DO NOTHING would not return existing row.
+				excluded = append(excluded, %q)
+			}
+			b.WriteString(" ON CONFLICT (%s) DO UPDATE SET ")
+			b.WriteString(strings.Join(excluded, ", "))
+`, strings.Join(keys, ", "), names[0]+" = EXCLUDED."+names[0], strings.Join(names, ", "))
+		g.generateUpsertVersionIncrement(returning, table)
+		fmt.Fprint(returning, `			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+			b.WriteString(", (xmax = 0)")`)
+		g.generateRepositoryInsertQuery(w, table, methodName, "return nil, false,", strings.TrimRight(returning.String(), "\n"))
+		fmt.Fprintf(w, `var created bool
+		err := %s.%sb.String(), insert.Args()...).Scan(
+`, g.querier(), g.dbCall("QueryRow"))
+		for _, c := range table.Columns {
+			fmt.Fprintf(w, "%s,\n", g.scanTarget("e", c))
+		}
+		fmt.Fprint(w, `&created,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+		if created {
+			`+g.hookCall("afterInsert", "e", "return nil, false,")+`
+		}`)
+		if _, ok := primaryKey(table); ok {
+			fmt.Fprint(w, ` else {
+			`+g.hookCall("afterUpdate", "e", "return nil, false,")+`
+		}`)
+		}
+		fmt.Fprint(w, `
+
+		return e, created, nil
+	}
+`)
+		g.generateRepositoryContextFree(w, table, methodName, "e *"+entityName+"Entity", "e", "(*"+entityName+"Entity, bool, error)")
+	}
+}
+
+func (g *Generator) generateRepositoryUpdateOneByPrimaryKey(w io.Writer, table *pqt.Table) {
+	entityName := g.name(tableIdent(table))
+	pk, ok := primaryKey(table)
+	if !ok {
+		return
+	}
+	suffix, arguments, values, where := g.keyArguments(pk)
+
+	fmt.Fprintf(w, `// %s returns query and arguments used by %s and its variants to modify the entity, without executing it.
+// Given columns are listed in RETURNING clause as they are, which is omitted if there are none.
+func (r *%sRepositoryBase) %s(%s, patch *%sPatch, returning []string) (string, []interface{}, error) {
+`, g.name("UpdateOneBy"+suffix+"Query"), g.methodName("UpdateOneBy"+suffix), entityName, g.name("UpdateOneBy"+suffix+"Query"), arguments, entityName)
+	g.generateRepositoryUpdateOneByPrimaryKeyQuery(w, table, pk, where)
+	fmt.Fprint(w, `return query, update.Args(), nil
+}
+
+`)
+
+	fmt.Fprintf(w, `// %s modifies the entity using values set in the patch and returns it.
+// pqt.ErrNothingToUpdate is returned if the patch holds no value.
+`, g.methodName("UpdateOneBy"+suffix))
+	if vc := versionColumn(table); vc != nil {
+		fmt.Fprintf(w, "// Patch has to hold current value of %s column, which is incremented, pqt.ErrVersionConflict is returned if it does not match.\n", vc.Name)
+	}
+	fmt.Fprintf(w, `func (r *%sRepositoryBase) %s(%s%s, patch *%sPatch) (*%sEntity, error) {
+	`+g.hookCall("beforeUpdate", values+", patch", "return nil,")+`
+	query, args, err := r.%s(%s, patch, r.columns)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "UpdateOneBy%s"); err != nil {
+			return nil, err
 		}
 	}
-	fmt.Fprintln(code, "if len(inf) > 0 {")
-UpdateLoop:
+
+	var e %sEntity
+	err = %s.%squery, args...).Scan(
+	`, entityName, g.methodName("UpdateOneBy"+suffix), g.contextArg(), arguments, entityName, entityName,
+		g.name("UpdateOneBy"+suffix+"Query"), values,
+		suffix,
+		entityName, g.querier(), g.dbCall("QueryRow"))
 	for _, c := range table.Columns {
-		switch c.Type {
-		case pqt.TypeSerial(), pqt.TypeSerialBig(), pqt.TypeSerialSmall():
-			continue UpdateLoop
-		default:
-			if g.canBeNil(c, modeOptional) {
-				fmt.Fprintf(code, `
-					if p.%s != nil {
-						update.AddExpr(%s, "=", p.%s)
-					}
-				`,
-					g.propertyName(c.Name),
-					g.columnNameWithTableName(table.Name, c.Name),
-					g.propertyName(c.Name),
-				)
-			} else {
-				fmt.Fprintf(code, `update.AddExpr(%s, "=", p.%s)`, g.columnNameWithTableName(table.Name, c.Name), g.propertyName(c.Name))
+		fmt.Fprintf(w, "%s,\n", g.scanTarget("e", c))
+	}
+	fmt.Fprint(w, `)
+if err != nil {
+`)
+	g.generateVersionConflict(w, table)
+	fmt.Fprint(w, `	return nil, err
+}
+`+g.hookCall("afterUpdate", "&e", "return nil,")+`
+
+return &e, nil
+}
+`)
+	g.generateRepositoryContextFree(w, table, "UpdateOneBy"+suffix,
+		arguments+", patch *"+entityName+"Patch",
+		values+", patch",
+		"(*"+entityName+"Entity, error)",
+	)
+}
+
+// generateRepositoryUpdateOneByPrimaryKeyReturning generates update method that returns only columns listed by pqt.WithReturning table option.
+func (g *Generator) generateRepositoryUpdateOneByPrimaryKeyReturning(w io.Writer, table *pqt.Table) {
+	entityName := g.name(tableIdent(table))
+	pk, ok := primaryKey(table)
+	if !ok || len(table.Returning) == 0 {
+		return
+	}
+	suffix, arguments, values, _ := g.keyArguments(pk)
+	returning := make([]string, 0, len(table.Returning))
+	for _, c := range table.Returning {
+		returning = append(returning, g.columnNameWithTableName(tableIdent(table), c.Name))
+	}
+
+	fmt.Fprintf(w, "// %s works like %s, but reads back only columns given by pqt.WithReturning table option.\n", g.methodName("UpdateOneBy"+suffix+"Returning"), g.methodName("UpdateOneBy"+suffix))
+	fmt.Fprintf(w, "func (r *%sRepositoryBase) %s(%s%s, patch *%sPatch) (*%sReturning, error) {\n", entityName, g.methodName("UpdateOneBy"+suffix+"Returning"), g.contextArg(), arguments, entityName, entityName)
+	fmt.Fprint(w, g.hookCall("beforeUpdate", values+", patch", "return nil,"))
+	g.generateRepositoryUpdateOneByPrimaryKeyCall(w, table, suffix, values, "[]string{"+strings.Join(returning, ", ")+"}", "UpdateOneBy"+suffix+"Returning", "return nil,")
+	fmt.Fprintf(w, `var ret %sReturning
+	err = r.db.%squery, args...).Scan(
+	`, entityName, g.dbCall("QueryRow"))
+	for _, c := range table.Returning {
+		fmt.Fprintf(w, "%s,\n", g.scanTarget("ret", c))
+	}
+	fmt.Fprint(w, `)
+if err != nil {
+`)
+	g.generateVersionConflict(w, table)
+	fmt.Fprint(w, `	return nil, err
+}
+
+return &ret, nil
+}
+`)
+	g.generateRepositoryContextFree(w, table, "UpdateOneBy"+suffix+"Returning",
+		arguments+", patch *"+entityName+"Patch",
+		values+", patch",
+		"(*"+entityName+"Returning, error)",
+	)
+}
+
+// generateRepositoryUpdateOneByPrimaryKeyReturningColumns generates update method that returns only columns given at runtime.
+// Column names are validated before the query is executed.
+func (g *Generator) generateRepositoryUpdateOneByPrimaryKeyReturningColumns(w io.Writer, table *pqt.Table) {
+	entityName := g.name(tableIdent(table))
+	pk, ok := primaryKey(table)
+	if !ok {
+		return
+	}
+	suffix, arguments, values, _ := g.keyArguments(pk)
+
+	fmt.Fprintf(w, "// %s works like %s, but returns entity with only given columns populated, unknown columns are rejected before execution.\n", g.methodName("UpdateOneBy"+suffix+"ReturningColumns"), g.methodName("UpdateOneBy"+suffix))
+	fmt.Fprintf(w, "func (r *%sRepositoryBase) %s(%s%s, patch *%sPatch, cols ...string) (*%sEntity, error) {\n", entityName, g.methodName("UpdateOneBy"+suffix+"ReturningColumns"), g.contextArg(), arguments, entityName, entityName)
+	fmt.Fprint(w, g.hookCall("beforeUpdate", values+", patch", "return nil,"))
+	g.generateRepositoryReturningColumnsProps(w, table, "update")
+	g.generateRepositoryUpdateOneByPrimaryKeyCall(w, table, suffix, values, "cols", "UpdateOneBy"+suffix+"ReturningColumns", "return nil,")
+	fmt.Fprintf(w, `err = r.db.%squery, args...).Scan(props...)
+if err != nil {
+`, g.dbCall("QueryRow"))
+	g.generateVersionConflict(w, table)
+	fmt.Fprint(w, `	return nil, err
+}
+
+return &ent, nil
+}
+`)
+	g.generateRepositoryContextFree(w, table, "UpdateOneBy"+suffix+"ReturningColumns",
+		arguments+", patch *"+entityName+"Patch, cols ...string",
+		values+", patch, cols...",
+		"(*"+entityName+"Entity, error)",
+	)
+}
+
+// generateRepositoryPatchOneByPrimaryKey generates update method that returns number of affected rows instead of the entity.
+// Only columns set in the patch are modified, so nothing has to be read back from the database.
+func (g *Generator) generateRepositoryPatchOneByPrimaryKey(w io.Writer, table *pqt.Table) {
+	entityName := g.name(tableIdent(table))
+	pk, ok := primaryKey(table)
+	if !ok {
+		return
+	}
+	suffix, arguments, values, _ := g.keyArguments(pk)
+
+	fmt.Fprintf(w, "// %s works like %s, but returns number of affected rows instead of the entity.\n", g.methodName("PatchOneBy"+suffix), g.methodName("UpdateOneBy"+suffix))
+	fmt.Fprintf(w, "func (r *%sRepositoryBase) %s(%s%s, patch *%sPatch) (int64, error) {\n", entityName, g.methodName("PatchOneBy"+suffix), g.contextArg(), arguments, entityName)
+	fmt.Fprint(w, g.hookCall("beforeUpdate", values+", patch", "return 0,"))
+	g.generateRepositoryUpdateOneByPrimaryKeyCall(w, table, suffix, values, "nil", "PatchOneBy"+suffix, "return 0,")
+	fmt.Fprintf(w, `res, err := r.db.%squery, args...)
+if err != nil {
+	return 0, err
+}
+
+`, g.dbCall("Exec"))
+	if versionColumn(table) != nil {
+		fmt.Fprint(w, `affected, err := `+g.rowsAffected()+`
+if err != nil {
+	return 0, err
+}
+if affected == 0 {
+	return 0, pqt.ErrVersionConflict
+}
+
+return affected, nil
+}
+`)
+	} else {
+		fmt.Fprint(w, `return `+g.rowsAffected()+`
+}
+`)
+	}
+	g.generateRepositoryContextFree(w, table, "PatchOneBy"+suffix,
+		arguments+", patch *"+entityName+"Patch",
+		values+", patch",
+		"(int64, error)",
+	)
+}
+
+// generateRepositoryUpdateOneByPrimaryKeyCall generates part of the update method that obtains the query from its builder
+// and logs it in debug mode on behalf of given function. Returning is Go expression of the columns listed in RETURNING clause,
+// ret is return statement prefix used to return an error.
+func (g *Generator) generateRepositoryUpdateOneByPrimaryKeyCall(w io.Writer, table *pqt.Table, suffix, values, returning, function, ret string) {
+	fmt.Fprintf(w, `query, args, err := r.%s(%s, patch, %s)
+	if err != nil {
+		%s err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "%s"); err != nil {
+			%s err
+		}
+	}
+
+	`, g.name("UpdateOneBy"+suffix+"Query"), values, returning, ret, function, ret)
+}
+
+// generateRepositoryUpdateOneByPrimaryKeyQuery generates body of the update query builder,
+// RETURNING clause lists columns given by returning argument of the builder, if there are any.
+func (g *Generator) generateRepositoryUpdateOneByPrimaryKeyQuery(w io.Writer, table *pqt.Table, pk pqt.Columns, where string) {
+	entityName := g.name(tableIdent(table))
+	ret := `return "", nil,`
+
+	fmt.Fprintf(w, `if err := patch.%s(); err != nil {
+		%s err
+	}
+	`, g.name("validate"), ret)
+	vc := versionColumn(table)
+	if vc != nil {
+		fmt.Fprintf(w, `if patch.%s == nil {
+		%s errors.New("%s update failure, version is required")
+	}
+	`, g.propertyName(vc.Name), ret, entityName)
+		where += fmt.Sprintf(" AND %s = $%d", vc.Name, len(pk)+1)
+		fmt.Fprintf(w, "update := pqcomp.New(%d, %d)\n", len(pk)+1, len(table.Columns))
+	} else {
+		fmt.Fprintf(w, "update := pqcomp.New(%d, %d)\n", len(pk), len(table.Columns))
+	}
+	for _, c := range pk {
+		fmt.Fprintf(w, "update.AddArg(%s)\n", g.private(c.Name))
+	}
+	if vc != nil {
+		fmt.Fprintf(w, "update.AddArg(patch.%s)\n", g.propertyName(vc.Name))
+	}
+	fmt.Fprintln(w, "")
+
+ColumnsLoop:
+	for _, c := range table.Columns {
+		if pk.Contains(c) || c.Generated != "" || c.Identity == pqt.IdentityAlways || c == vc {
+			continue ColumnsLoop
+		}
+		if _, ok := c.DefaultOn(pqt.EventInsert, pqt.EventUpdate); ok {
+			switch c.Type {
+			case pqt.TypeTimestamp(), pqt.TypeTimestampTZ():
+				fmt.Fprintf(w, "if patch.%s != nil {\n", g.propertyName(c.Name))
+
 			}
-			fmt.Fprintln(code, "")
+		} else if g.canBeNil(c, modeOptional) {
+			fmt.Fprintf(w, "if patch.%s != nil {\n", g.propertyName(c.Name))
+		}
+
+		fmt.Fprint(w, "update.AddExpr(")
+		g.writeTableNameColumnNameTo(w, tableIdent(c.Table), c.Name)
+		fmt.Fprintf(w, ", pqcomp.Equal, %s)\n", g.argument("patch", c))
+
+		if d, ok := c.DefaultOn(pqt.EventUpdate); ok {
+			switch c.Type {
+			case pqt.TypeTimestamp(), pqt.TypeTimestampTZ():
+				fmt.Fprint(w, `} else {`)
+				fmt.Fprint(w, "update.AddExpr(")
+				g.writeTableNameColumnNameTo(w, tableIdent(c.Table), c.Name)
+				fmt.Fprintf(w, `, pqcomp.Equal, "%s")`, d)
+			}
+		}
+		if _, ok := c.DefaultOn(pqt.EventInsert, pqt.EventUpdate); ok {
+			switch c.Type {
+			case pqt.TypeTimestamp(), pqt.TypeTimestampTZ():
+				fmt.Fprint(w, "\n}\n")
+			}
+		} else if g.canBeNil(c, modeOptional) {
+			fmt.Fprint(w, "\n}\n")
+		}
+	}
+	fmt.Fprintf(w, `
+	if update.Len() == 0 {
+		%s pqt.ErrNothingToUpdate
+	}`, ret)
+
+	fmt.Fprintf(w, `
+	query := "UPDATE %s SET "
+	for update.Next() {
+		if !update.First() {
+			query += ", "
 		}
+
+		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
+	}
+	`, queryName(table))
+	g.generateVersionIncrement(w, table)
+	fmt.Fprintf(w, `query += " WHERE %s"
+	if len(returning) > 0 {
+		query += " RETURNING " + strings.Join(returning, ", ")
+	}
+	`, where)
+}
+
+// generateVersionIncrement generates statement that appends increment of the version column to the SET clause.
+func (g *Generator) generateVersionIncrement(w io.Writer, table *pqt.Table) {
+	if vc := versionColumn(table); vc != nil {
+		fmt.Fprintf(w, `query += ", %s = %s + 1"
+	`, vc.Name, vc.Name)
+	}
+}
+
+// generateUpsertVersionIncrement generates statement that appends increment of the version column to the DO UPDATE clause.
+// Column has to be qualified, otherwise it would be ambiguous with the one of the EXCLUDED row.
+func (g *Generator) generateUpsertVersionIncrement(w io.Writer, table *pqt.Table) {
+	if vc := versionColumn(table); vc != nil {
+		fmt.Fprintf(w, `			b.WriteString(", %s = %s.%s + 1")
+`, vc.Name, table.Name, vc.Name)
 	}
-	fmt.Fprintln(code, "}")
+}
 
-	fmt.Fprint(code, `
-		b := bytes.NewBufferString("INSERT INTO " + r.table)
+// generateVersionConflict generates handling of the error returned by update query that reads back the row.
+// If table has version column, no row means that the version did not match.
+func (g *Generator) generateVersionConflict(w io.Writer, table *pqt.Table) {
+	if versionColumn(table) != nil {
+		fmt.Fprint(w, `if err == `+g.errNoRows()+` {
+	return nil, pqt.ErrVersionConflict
+}
+`)
+	}
+}
 
-		if insert.Len() > 0 {
-			b.WriteString(" (")
-			for insert.Next() {
-				if !insert.First() {
-					b.WriteString(", ")
-				}
+func (g *Generator) generateRepositoryDeleteOneByPrimaryKey(code *bytes.Buffer,
+	table *pqt.Table) {
+	entityName := g.name(tableIdent(table))
+	pk, ok := primaryKey(table)
+	if !ok {
+		return
+	}
+	suffix, arguments, values, where := g.keyArguments(pk)
+	method := deleteMethod(table, suffix)
 
-				fmt.Fprintf(b, "%s", insert.Key())
-			}
-			insert.Reset()
-			b.WriteString(") VALUES (")
-			for insert.Next() {
-				if !insert.First() {
-					b.WriteString(", ")
-				}
+	fmt.Fprintf(code, `
+		// %s returns query and arguments used by %s to remove the entity, without executing it.
+		func (r *%sRepositoryBase) %s(%s) (string, []interface{}, error) {
+			return "DELETE FROM %s WHERE %s", []interface{}{%s}, nil
+		}
 
-				fmt.Fprintf(b, "%s", insert.PlaceHolder())
+		// %s removes the entity and returns number of affected rows.
+		func (r *%sRepositoryBase) %s(%s%s) (int64, error) {
+			`+g.hookCall("beforeDelete", values, "return 0,")+`
+			query, args, err := r.%s(%s)
+			if err != nil {
+				return 0, err
 			}
-			b.WriteString(")")
-		}
-		b.WriteString(" ON CONFLICT ")
-		if len(inf) > 0 && update.Len() > 0 {
-			b.WriteString(" (")
-			for j, i := range inf {
-				if j != 0 {
-					b.WriteString(", ")
+			if r.dbg {
+				if err := r.log.Log("msg", query, "function", "%s"); err != nil {
+					return 0, err
 				}
-				b.WriteString(i)
 			}
-			b.WriteString(") ")
-			b.WriteString(" DO UPDATE SET ")
-			for update.Next() {
-				if !update.First() {
-					b.WriteString(", ")
-				}
 
-				b.WriteString(update.Key())
-				b.WriteString(" ")
-				b.WriteString(update.Oper())
-				b.WriteString(" ")
-				b.WriteString(update.PlaceHolder())
+			res, err := %s.%squery, args...)
+			if err != nil {
+				return 0, err
 			}
-		} else {
-			b.WriteString(" DO NOTHING ")
-		}
-		if insert.Len() > 0 {
-			if len(r.columns) > 0 {
-				b.WriteString(" RETURNING ")
-				b.WriteString(strings.Join(r.columns, ", "))
+			affected, err := `+g.rowsAffected()+`
+			if err != nil || affected == 0 {
+				return affected, err
 			}
+			`+g.hookCall("afterDelete", values, "return affected,")+`
+			return affected, nil
 		}
+`, g.name(method+"Query"), g.methodName(method), entityName, g.name(method+"Query"), arguments, queryName(table), where, values,
+		g.methodName(method),
+		entityName, g.methodName(method), g.contextArg(), arguments,
+		g.name(method+"Query"), values,
+		method,
+		g.querier(), g.dbCall("Exec"))
+	g.generateRepositoryContextFree(code, table, method,
+		arguments,
+		values,
+		"(int64, error)",
+	)
+}
 
-		if r.dbg {
-			if err := r.log.Log("msg", b.String(), "function", "Upsert"); err != nil {
-				return nil, err
-			}
+// generateRepositoryDeleteAndReturnOneByPrimaryKey generates delete method that returns removed entity using RETURNING clause,
+// so that caller, e.g. audit log, does not need to select it first.
+func (g *Generator) generateRepositoryDeleteAndReturnOneByPrimaryKey(w io.Writer, table *pqt.Table) {
+	entityName := g.name(tableIdent(table))
+	pk, ok := primaryKey(table)
+	if !ok {
+		return
+	}
+	suffix, arguments, values, _ := g.keyArguments(pk)
+	query, method := deleteMethod(table, suffix), deleteAndReturnMethod(table, suffix)
+
+	fmt.Fprintf(w, `// %s removes the entity and returns it as it was right before removal.
+// pqt.ErrNotFound is returned if there is no such entity.
+func (r *%sRepositoryBase) %s(%s%s) (*%sEntity, error) {
+	`+g.hookCall("beforeDelete", values, "return nil,")+`
+	query, args, err := r.%s(%s)
+	if err != nil {
+		return nil, err
+	}
+	query += " RETURNING " + strings.Join(r.columns, ", ")
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "%s"); err != nil {
+			return nil, err
 		}
+	}
 
-		err := r.db.QueryRow(b.String(), insert.Args()...).Scan(
-	`)
-
+	var e %sEntity
+	err = %s.%squery, args...).Scan(
+`, g.methodName(method), entityName, g.methodName(method), g.contextArg(), arguments, entityName,
+		g.name(query+"Query"), values,
+		method,
+		entityName, g.querier(), g.dbCall("QueryRow"))
 	for _, c := range table.Columns {
-		fmt.Fprintf(code, "&e.%s,\n", g.propertyName(c.Name))
+		fmt.Fprintf(w, "%s,\n", g.scanTarget("e", c))
 	}
-	fmt.Fprint(code, `)
-		if err != nil {
-			return nil, err
+	fmt.Fprint(w, `)
+	if err == `+g.errNoRows()+` {
+		return nil, pqt.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	`+g.hookCall("afterDelete", values, "return &e,")+`
+	return &e, nil
+}
+`)
+	g.generateRepositoryContextFree(w, table, method,
+		arguments,
+		values,
+		"(*"+entityName+"Entity, error)",
+	)
+}
+
+func (g *Generator) generateRepositoryDeleteByCriteria(w io.Writer, t *pqt.Table) {
+	entityName := g.name(tableIdent(t))
+
+	fmt.Fprintf(w, `// %s removes entities that match given criteria and returns their number.
+// Empty criteria is rejected with pqt.ErrDeleteWithoutCriteria unless full scan is allowed. Sort, offset, limit and lock are not supported.
+`, g.methodName("DeleteByCriteria"))
+	fmt.Fprintf(w, `func (r *%sRepositoryBase) %s(%sc *%sCriteria, allowFullScan bool) (int64, error) {
+	if len(c.%s) > 0 || len(c.%s) > 0 || c.%s > 0 || c.%s > 0 || c.%s != pqt.LockNone {
+		return 0, errors.New("%s delete failure, sort, offset, limit and lock are not supported")
+	}
+
+%s	where, args, err := r.%s(c)
+	if err != nil {
+		return 0, err
+	}
+	buf := bytes.NewBufferString("DELETE FROM ")
+	buf.WriteString(r.table)
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	} else if !allowFullScan {
+		return 0, pqt.ErrDeleteWithoutCriteria
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "DeleteByCriteria"); err != nil {
+			return 0, err
 		}
+	}
 
-		return e, nil
+	res, err := r.db.%sbuf.String(), args...)
+	if err != nil {
+		return 0, err
 	}
-`)
+
+	return %s
+}
+`, entityName, g.methodName("DeleteByCriteria"), g.contextArg(), entityName,
+		g.name("sort"), g.name("sortExpr"), g.public("offset"), g.public("limit"), g.name("lock"), entityName,
+		g.deleteByCriteriaSoftDeleteGuard(t),
+		g.name("plan"),
+		g.dbCall("Exec"), g.rowsAffected())
+	g.generateRepositoryContextFree(w, t, "DeleteByCriteria", "c *"+entityName+"Criteria, allowFullScan bool", "c, allowFullScan", "(int64, error)")
 }
 
-func (g *Generator) generateRepositoryUpdateOneByUniqueConstraint(w io.Writer, table *pqt.Table) {
-	entityName := g.name(table.Name)
-	var unique []*pqt.Constraint
-	for _, c := range tableConstraints(table) {
-		if c.Type == pqt.ConstraintTypeUnique {
-			unique = append(unique, c)
+// deleteByCriteriaSoftDeleteGuard returns code that rejects criteria which hold no condition other than the implicit one,
+// which skips entities marked as deleted, so that it does not turn into a full scan unnoticed.
+func (g *Generator) deleteByCriteriaSoftDeleteGuard(t *pqt.Table) string {
+	if !t.SoftDelete {
+		return ""
+	}
+	return fmt.Sprintf(`	if !allowFullScan {
+		cc := *c
+		cc.%s = true
+		if where, _, err := r.%s(&cc); err != nil {
+			return 0, err
+		} else if where == "" {
+			return 0, pqt.ErrDeleteWithoutCriteria
 		}
 	}
-	if len(unique) < 1 {
+
+`, g.name("includeDeleted"), g.name("plan"))
+}
+
+func (g *Generator) generateRepositorySoftDelete(w io.Writer, t *pqt.Table) {
+	if !t.SoftDelete {
 		return
 	}
+	pk, ok := primaryKey(t)
+	if !ok {
+		return
+	}
+	entityName := g.name(tableIdent(t))
+	suffix, arguments, values, where := g.keyArguments(pk)
 
-	for _, u := range unique {
-		arguments := ""
-		methodName := "UpdateOneBy"
-		for i, c := range u.Columns {
-			if i != 0 {
-				methodName += "And"
-				arguments += ", "
-			}
-			methodName += g.public(c.Name)
-			arguments += fmt.Sprintf("%s %s", g.private(c.Name), g.generateColumnTypeString(c, modeMandatory))
+	fmt.Fprintf(w, `// %s marks entity as deleted instead of removing it, %s is returned if there is no such entity or it is already deleted.
+func (r *%sRepositoryBase) %s(%s%s) error {
+	`+g.hookCall("beforeDelete", values, "return")+`
+	query := "UPDATE %s SET %s = NOW() WHERE %s%s"
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "SoftDeleteOneBy%s"); err != nil {
+			return err
 		}
-		fmt.Fprintf(w, `func (r *%sRepositoryBase) %s(%s, patch *%sPatch) (*%sEntity, error) {
-		`, entityName, g.name(methodName), arguments, entityName, entityName)
-		fmt.Fprintf(w, "update := pqcomp.New(%d, %d)\n", len(u.Columns), len(table.Columns))
-		for _, c := range u.Columns {
-			fmt.Fprintf(w, "update.AddArg(%s)\n", g.private(c.Name))
+	}
+
+	res, err := r.db.%squery, %s)
+	if err != nil {
+		return err
+	}
+	affected, err := %s
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return %s
+	}
+	`+g.hookCall("afterDelete", values, "return")+`
+	return nil
+}
+`, g.methodName("SoftDeleteOneBy"+suffix), g.errNoRows(), entityName, g.methodName("SoftDeleteOneBy"+suffix), g.contextArg(), arguments,
+		queryName(t), softDeleteColumn(t), where, softDeleteCondition(t),
+		suffix,
+		g.dbCall("Exec"), values, g.rowsAffected(), g.errNoRows())
+	g.generateRepositoryContextFree(w, t, "SoftDeleteOneBy"+suffix, arguments, values, "error")
+}
+
+func (g *Generator) generateRepositoryTruncate(w io.Writer, t *pqt.Table) {
+	entityName := g.name(tableIdent(t))
+
+	fmt.Fprintf(w, `// %s removes all rows from the table.
+// If cascade is true, tables that have foreign key references to the table are truncated as well.
+// If restartIdentity is true, sequences owned by columns of the table are restarted.
+func (r *%sRepositoryBase) %s(%scascade, restartIdentity bool) error {
+	buf := bytes.NewBufferString("TRUNCATE ")
+	buf.WriteString(r.table)
+	if restartIdentity {
+		buf.WriteString(" RESTART IDENTITY")
+	}
+	if cascade {
+		buf.WriteString(" CASCADE")
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Truncate"); err != nil {
+			return err
 		}
-		pk, pkOK := table.PrimaryKey()
-	ColumnsLoop:
-		for _, c := range table.Columns {
-			if pkOK && c == pk {
-				continue ColumnsLoop
-			}
-			for _, uc := range u.Columns {
-				if c == uc {
-					continue
-				}
-			}
-			if _, ok := c.DefaultOn(pqt.EventInsert, pqt.EventUpdate); ok {
-				switch c.Type {
-				case pqt.TypeTimestamp(), pqt.TypeTimestampTZ():
-					fmt.Fprintf(w, "if patch.%s != nil {\n", g.propertyName(c.Name))
+	}
 
-				}
-			} else if g.canBeNil(c, modeOptional) {
-				fmt.Fprintf(w, "if patch.%s != nil {\n", g.propertyName(c.Name))
-			}
+	_, err := r.db.%sbuf.String())
+	return err
+}
+`, g.methodName("Truncate"), entityName, g.methodName("Truncate"), g.contextArg(), g.dbCall("Exec"))
+	g.generateRepositoryContextFree(w, t, "Truncate", "cascade, restartIdentity bool", "cascade, restartIdentity", "error")
+}
 
-			fmt.Fprint(w, "update.AddExpr(")
-			g.writeTableNameColumnNameTo(w, c.Table.Name, c.Name)
-			fmt.Fprintf(w, ", pqcomp.Equal, patch.%s)\n", g.propertyName(c.Name))
+// generateRepositoryInterface generates interface that consists of all methods collected while the repository was generated.
+func (g *Generator) generateRepositoryInterface(w io.Writer, t *pqt.Table) {
+	if !g.interfaces {
+		return
+	}
+	entityName := g.name(tableIdent(t))
 
-			if d, ok := c.DefaultOn(pqt.EventUpdate); ok {
-				switch c.Type {
-				case pqt.TypeTimestamp(), pqt.TypeTimestampTZ():
-					fmt.Fprint(w, `} else {`)
-					fmt.Fprint(w, "update.AddExpr(")
-					g.writeTableNameColumnNameTo(w, c.Table.Name, c.Name)
-					fmt.Fprintf(w, `, pqcomp.Equal, "%s")`, d)
-				}
-			}
-			if _, ok := c.DefaultOn(pqt.EventInsert, pqt.EventUpdate); ok {
-				switch c.Type {
-				case pqt.TypeTimestamp(), pqt.TypeTimestampTZ():
-					fmt.Fprint(w, "\n}\n")
-				}
-			} else if g.canBeNil(c, modeOptional) {
-				fmt.Fprint(w, "\n}\n")
-			}
+	fmt.Fprintf(w, "// %sRepository is implemented by %sRepositoryBase and %s.\n", entityName, entityName, g.name("Mock"+g.public(tableIdent(t))+"Repository"))
+	fmt.Fprintf(w, "type %sRepository interface {\n", entityName)
+	for _, m := range g.methods {
+		if g.ctx {
+			fmt.Fprintf(w, "%s(%s) %s\n", g.methodName(m.name), strings.TrimSuffix(g.contextArg()+m.params, ", "), m.results)
 		}
+		fmt.Fprintf(w, "%s(%s) %s\n", g.name(m.name), m.params, m.results)
+	}
+	fmt.Fprintf(w, `}
 
-		fmt.Fprintf(w, `
-	if update.Len() == 0 {
-		return nil, errors.New("%s update failure, nothing to update")
-	}`, entityName)
+var _ %sRepository = &%sRepositoryBase{}
 
-		fmt.Fprintf(w, `
-	query := "UPDATE %s SET "
-	for update.Next() {
-		if !update.First() {
-			query += ", "
+`, entityName, entityName)
+}
+
+// generateRepositoryMock generates in-memory implementation of the repository interface.
+// Methods that do not depend on criteria (insert, lookup and removal by primary key) operate on the stored entities,
+// find and count ignore criteria, remaining ones report an error. Each call is recorded and can be made to fail.
+func (g *Generator) generateRepositoryMock(w io.Writer, t *pqt.Table) {
+	if !g.interfaces {
+		return
+	}
+	entityName := g.name(tableIdent(t))
+	mockName := g.name("Mock" + g.public(tableIdent(t)) + "Repository")
+
+	fmt.Fprintf(w, `// %s is in-memory implementation of %sRepository meant for unit tests, it is safe for concurrent use.
+// Entities are kept in insertion order, criteria are not evaluated, so find, count and exists operate on all of them.
+// Each call is recorded by method name (without Context suffix), error set for the method name is returned instead of the result.
+type %s struct {
+	mu sync.Mutex
+	%s []*%sEntity
+	%s []string
+	%s map[string]error
+}
+
+var _ %sRepository = &%s{}
+
+// %s allocates new %s with empty store.
+func %s() *%s {
+	return &%s{%s: make(map[string]error)}
+}
+
+`, mockName, entityName, mockName,
+		g.name("Entities"), entityName,
+		g.name("Calls"),
+		g.name("Errors"),
+		entityName, mockName,
+		g.name("NewMock"+g.public(tableIdent(t))+"Repository"), mockName,
+		g.name("NewMock"+g.public(tableIdent(t))+"Repository"), mockName,
+		mockName, g.name("Errors"),
+	)
+
+	var pkSuffix, pkCondition string
+	if pk, ok := primaryKey(t); ok {
+		pkSuffix, _, _, _ = g.keyArguments(pk)
+		conditions := make([]string, 0, len(pk))
+		for _, c := range pk {
+			conditions = append(conditions, fmt.Sprintf("e.%s == %s", g.propertyName(c.Name), g.private(c.Name)))
+		}
+		pkCondition = strings.Join(conditions, " && ")
+	}
+
+	for _, m := range g.methods {
+		results := strings.Split(strings.Trim(m.results, "()"), ", ")
+		zeros := make([]string, 0, len(results))
+		for _, r := range results[:len(results)-1] {
+			zeros = append(zeros, mockZeroValue(r))
 		}
+		fail := strings.Join(append(zeros, "err"), ", ")
 
-		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
+		fmt.Fprintf(w, `func (m *%s) %s(%s) %s {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.%s = append(m.%s, %q)
+	if err := m.%s[%q]; err != nil {
+		return %s
 	}
-`, table.FullName())
-		fmt.Fprint(w, `query += " WHERE `)
-		for i, c := range u.Columns {
-			if i != 0 {
-				fmt.Fprint(w, " AND ")
-			}
-			fmt.Fprintf(w, "%s = $%d", c.Name, i+1)
+`, mockName, g.methodName(m.name), strings.TrimSuffix(g.contextArg()+m.params, ", "), m.results,
+			g.name("Calls"), g.name("Calls"), g.name(m.name),
+			g.name("Errors"), g.name(m.name),
+			fail,
+		)
+
+		entities := g.name("Entities")
+		switch {
+		case m.name == "Insert":
+			fmt.Fprintf(w, "m.%s = append(m.%s, e)\n\nreturn e, nil\n}\n", entities, entities)
+		case m.name == "InsertBatch":
+			fmt.Fprintf(w, "m.%s = append(m.%s, es...)\n\nreturn es, nil\n}\n", entities, entities)
+		case m.name == "BulkInsert":
+			fmt.Fprintf(w, "m.%s = append(m.%s, es...)\n\nreturn int64(len(es)), nil\n}\n", entities, entities)
+		case m.name == "Find" || m.name == "FindIncludingDeleted":
+			fmt.Fprintf(w, "return append([]*%sEntity(nil), m.%s...), nil\n}\n", entityName, entities)
+		case m.name == "FindAndCount":
+			fmt.Fprintf(w, "return append([]*%sEntity(nil), m.%s...), int64(len(m.%s)), nil\n}\n", entityName, entities, entities)
+		case m.name == "count":
+			fmt.Fprintf(w, "return int64(len(m.%s)), nil\n}\n", entities)
+		case m.name == "exists":
+			fmt.Fprintf(w, "return len(m.%s) > 0, nil\n}\n", entities)
+		case pkCondition != "" && m.name == "FindOneBy"+pkSuffix:
+			fmt.Fprintf(w, `for _, e := range m.%s {
+		if %s {
+			return e, nil
 		}
-		fmt.Fprintf(w, ` RETURNING " + strings.Join(r.columns, ", ")
-	if r.dbg {
-		if err := r.log.Log("msg", query, "function", "%s"); err != nil {
-			return nil, err
+	}
+
+	return nil, %s
+}
+`, entities, pkCondition, g.errNoRows())
+		case pkCondition != "" && (m.name == "DeleteOneBy"+pkSuffix || m.name == "HardDeleteOneBy"+pkSuffix):
+			fmt.Fprintf(w, `for i, e := range m.%s {
+		if %s {
+			m.%s = append(m.%s[:i], m.%s[i+1:]...)
+			return 1, nil
 		}
 	}
-	var e %sEntity
-	err := r.db.QueryRow(query, update.Args()...).Scan(
-	`, methodName, entityName)
-		for _, c := range table.Columns {
-			fmt.Fprintf(w, "&e.%s,\n", g.propertyName(c.Name))
+
+	return 0, nil
+}
+`, entities, pkCondition, entities, entities, entities)
+		default:
+			fmt.Fprintf(w, "return %s\n}\n", strings.Join(append(zeros, fmt.Sprintf(`errors.New("%s: %s is not supported")`, mockName, g.name(m.name))), ", "))
 		}
-		fmt.Fprint(w, `)
-if err != nil {
-	return nil, err
+
+		if g.ctx {
+			fmt.Fprintf(w, `func (m *%s) %s(%s) %s {
+	return m.%s(context.Background(), %s)
+}
+`, mockName, g.name(m.name), m.params, m.results, g.methodName(m.name), m.args)
+		}
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// generateRepositoryCache generates decorator of the repository that caches results of count and find by primary key.
+// Every other method that does not start with find, count, exists or aggregate is considered to modify the table,
+// so it invalidates all results of the table by bumping its version that is part of each cache key.
+func (g *Generator) generateRepositoryCache(w io.Writer, t *pqt.Table) {
+	if !g.cache {
+		return
+	}
+	entityName := g.name(tableIdent(t))
+	baseName := entityName + "RepositoryBase"
+	entryName := entityName + "CacheEntry"
+	cachedName := g.name("Cached" + g.public(tableIdent(t)) + "Repository")
+	constructorName := g.name("NewCached" + g.public(tableIdent(t)) + "Repository")
+	invalidate := g.name("invalidate")
+
+	fmt.Fprintf(w, "// %s mirrors properties of %sEntity that are mapped from columns, they are exported so entry can be encoded as JSON.\n", entryName, entityName)
+	fmt.Fprintf(w, "type %s struct {\n", entryName)
+	var columns []*pqt.Column
+	for _, c := range t.Columns {
+		if typ := g.generateColumnTypeString(c, modeDefault); typ != "<nil>" {
+			columns = append(columns, c)
+			fmt.Fprintf(w, "%s %s `json:%q`\n", g.public(c.Name), typ, c.Name)
+		}
+	}
+	fmt.Fprintf(w, "}\n\nfunc new%s(e *%sEntity) *%s {\nreturn &%s{\n", g.public(entryName), entityName, entryName, entryName)
+	for _, c := range columns {
+		fmt.Fprintf(w, "%s: e.%s,\n", g.public(c.Name), g.propertyName(c.Name))
+	}
+	fmt.Fprintf(w, "}\n}\n\nfunc (ce *%s) entity() *%sEntity {\nreturn &%sEntity{\n", entryName, entityName, entityName)
+	for _, c := range columns {
+		fmt.Fprintf(w, "%s: ce.%s,\n", g.propertyName(c.Name), g.public(c.Name))
+	}
+	fmt.Fprint(w, "}\n}\n\n")
+
+	fmt.Fprintf(w, `// %s decorates %s with cache of count and find by primary key results.
+// Every method that modifies the table invalidates all cached results of the table.
+// Modifications made using withTx or outside of the repository are not noticed, %s has to be called after them.
+type %s struct {
+	*%s
+	cache pqt.Cache
+	ttl   time.Duration
 }
 
-
-return &e, nil
-}
-`)
+// %s allocates %s that keeps results in given cache for given time.
+// Version of the table is seeded if the cache does not hold it yet.
+func %s(base *%s, cache pqt.Cache, ttl time.Duration) *%s {
+	r := &%s{%s: base, cache: cache, ttl: ttl}
+	if _, ok := cache.Get(r.table + ":version"); !ok {
+		r.%s()
 	}
+
+	return r
 }
 
-func (g *Generator) generateRepositoryUpdateOneByPrimaryKey(w io.Writer, table *pqt.Table) {
-	entityName := g.name(table.Name)
-	pk, ok := table.PrimaryKey()
+// cacheKey returns key under which result of the method called with given arguments is stored.
+// Key includes current version of the table, so results cached before the last modification are no longer reachable.
+// Version can be evicted like any other entry, then new one is seeded and the call is not cached,
+// otherwise results cached under the previous empty version would become reachable again.
+func (r *%s) cacheKey(method string, args ...interface{}) (string, bool) {
+	b, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	version, ok := r.cache.Get(r.table + ":version")
 	if !ok {
-		return
+		r.%s()
+		return "", false
 	}
 
-	fmt.Fprintf(w, "func (r *%sRepositoryBase) %s%s(%s %s, patch *%sPatch) (*%sEntity, error) {\n", entityName, g.name("UpdateOneBy"), g.public(pk.Name), g.private(pk.Name), g.generateColumnTypeString(pk, modeMandatory), entityName, entityName)
-	fmt.Fprintf(w, "update := pqcomp.New(1, %d)\n", len(table.Columns))
-	fmt.Fprintf(w, "update.AddArg(%s)\n", g.private(pk.Name))
-	fmt.Fprintln(w, "")
+	return r.table + ":" + string(version) + ":" + method + ":" + string(b), true
+}
 
-ColumnsLoop:
-	for _, c := range table.Columns {
-		if c == pk {
-			continue ColumnsLoop
-		}
-		if _, ok := c.DefaultOn(pqt.EventInsert, pqt.EventUpdate); ok {
-			switch c.Type {
-			case pqt.TypeTimestamp(), pqt.TypeTimestampTZ():
-				fmt.Fprintf(w, "if patch.%s != nil {\n", g.propertyName(c.Name))
+// %s makes all cached results of the table unreachable.
+func (r *%s) %s() {
+	r.cache.Set(r.table+":version", []byte(strconv.FormatInt(time.Now().UnixNano(), 10)), 0)
+}
 
-			}
-		} else if g.canBeNil(c, modeOptional) {
-			fmt.Fprintf(w, "if patch.%s != nil {\n", g.propertyName(c.Name))
-		}
+`, cachedName, baseName, invalidate,
+		cachedName, baseName,
+		constructorName, cachedName,
+		constructorName, baseName, cachedName,
+		cachedName, baseName, invalidate,
+		cachedName, invalidate,
+		invalidate, cachedName, invalidate,
+	)
 
-		fmt.Fprint(w, "update.AddExpr(")
-		g.writeTableNameColumnNameTo(w, c.Table.Name, c.Name)
-		fmt.Fprintf(w, ", pqcomp.Equal, patch.%s)\n", g.propertyName(c.Name))
+	var pkMethod string
+	if pk, ok := primaryKey(t); ok {
+		suffix, _, _, _ := g.keyArguments(pk)
+		pkMethod = "FindOneBy" + suffix
+	}
+	for _, m := range g.methods {
+		lower := strings.ToLower(m.name)
+		var cached string
+		switch {
+		case m.name == pkMethod:
+			cached = entityName + "Entity"
+		case m.name == "count":
+			cached = "int64"
+		case strings.HasPrefix(lower, "find"), strings.HasPrefix(lower, "count"), strings.HasPrefix(lower, "exists"), strings.HasPrefix(lower, "aggregate"):
+			// Read only methods are promoted from the base repository.
+			continue
+		}
 
-		if d, ok := c.DefaultOn(pqt.EventUpdate); ok {
-			switch c.Type {
-			case pqt.TypeTimestamp(), pqt.TypeTimestampTZ():
-				fmt.Fprint(w, `} else {`)
-				fmt.Fprint(w, "update.AddExpr(")
-				g.writeTableNameColumnNameTo(w, c.Table.Name, c.Name)
-				fmt.Fprintf(w, `, pqcomp.Equal, "%s")`, d)
+		fmt.Fprintf(w, "func (r *%s) %s(%s) %s {\n", cachedName, g.methodName(m.name), strings.TrimSuffix(g.contextArg()+m.params, ", "), m.results)
+		call := fmt.Sprintf("r.%s.%s(%s)", baseName, g.methodName(m.name), strings.TrimSuffix(g.contextParam()+m.args, ", "))
+		if cached == "" {
+			fmt.Fprintf(w, "defer r.%s()\n\nreturn %s\n}\n\n", invalidate, call)
+		} else {
+			keyArgs := m.args
+			if m.name == "count" {
+				fmt.Fprintf(w, `where, args, err := r.%s(c)
+	if err != nil {
+		return 0, err
+	}
+`, g.name("plan"))
+				keyArgs = "where, args"
 			}
-		}
-		if _, ok := c.DefaultOn(pqt.EventInsert, pqt.EventUpdate); ok {
-			switch c.Type {
-			case pqt.TypeTimestamp(), pqt.TypeTimestampTZ():
-				fmt.Fprint(w, "\n}\n")
+			decode, value := "var res "+cached, "res"
+			if m.name != "count" {
+				decode, value = "var res "+entryName, "res.entity()"
 			}
-		} else if g.canBeNil(c, modeOptional) {
-			fmt.Fprint(w, "\n}\n")
+			fmt.Fprintf(w, `key, ok := r.cacheKey(%q, %s)
+	if ok {
+		if b, hit := r.cache.Get(key); hit {
+			%s
+			if err := json.Unmarshal(b, &res); err == nil {
+				return %s, nil
+			}
+			r.cache.Delete(key)
 		}
 	}
-	fmt.Fprintf(w, `
-	if update.Len() == 0 {
-		return nil, errors.New("%s update failure, nothing to update")
-	}`, entityName)
 
-	fmt.Fprintf(w, `
-	query := "UPDATE %s SET "
-	for update.Next() {
-		if !update.First() {
-			query += ", "
+	res, err := %s
+	if err != nil || !ok {
+		return res, err
+	}
+`, g.name(m.name), keyArgs, decode, value, call)
+			if m.name == "count" {
+				fmt.Fprint(w, "if b, err := json.Marshal(res); err == nil {\n")
+			} else {
+				fmt.Fprintf(w, "if b, err := json.Marshal(new%s(res)); err == nil {\n", g.public(entryName))
+			}
+			fmt.Fprintf(w, `r.cache.Set(key, b, r.ttl)
+	}
+
+	return res, nil
+}
+
+`)
 		}
 
-		query += update.Key() + " " + update.Oper() + " " + update.PlaceHolder()
+		if g.ctx {
+			g.generateContextFree(w, cachedName, m)
+			fmt.Fprint(w, "\n")
+		}
 	}
-	query += " WHERE %s = $1 RETURNING " + strings.Join(r.columns, ", ")
-	var e %sEntity
-	err := r.db.QueryRow(query, update.Args()...).Scan(
-	`, table.FullName(), pk.Name, entityName)
-	for _, c := range table.Columns {
-		fmt.Fprintf(w, "&e.%s,\n", g.propertyName(c.Name))
+	if g.interfaces {
+		fmt.Fprintf(w, "\nvar _ %sRepository = &%s{}\n", entityName, cachedName)
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// mockZeroValue returns zero value of given result type of a repository method.
+func mockZeroValue(typ string) string {
+	switch {
+	case strings.HasPrefix(typ, "*"), strings.HasPrefix(typ, "[]"), strings.HasPrefix(typ, "map["):
+		return "nil"
+	case typ == "bool":
+		return "false"
+	case typ == "string":
+		return `""`
+	default:
+		return "0"
 	}
-	fmt.Fprint(w, `)
-if err != nil {
-	return nil, err
 }
 
+// generateSortValidation generates code that rejects sort keys of the criteria that are not columns of the table.
+// Given return statement prefix is used to return the error. It does nothing if strict sort is disabled.
+func (g *Generator) generateSortValidation(w io.Writer, t *pqt.Table, ret string) {
+	if !g.strictSort {
+		return
+	}
 
-return &e, nil
+	fmt.Fprintf(w, `
+SortLoop:
+	for cn := range c.%s {
+		for _, tcn := range %s%sColumns {
+			if cn == tcn {
+				continue SortLoop
+			}
+		}
+		%s fmt.Errorf("pqt: unknown sort column %%q", cn)
+	}
+`, g.name("sort"), g.name("table"), g.public(tableIdent(t)), ret)
 }
-`)
+
+// keyArguments returns method name suffix, arguments definition, arguments values and WHERE clause for given key columns.
+func (g *Generator) keyArguments(columns pqt.Columns) (string, string, string, string) {
+	var suffix, arguments, values, where string
+	for i, c := range columns {
+		if i != 0 {
+			suffix += "And"
+			arguments += ", "
+			values += ", "
+			where += " AND "
+		}
+		suffix += g.public(c.Name)
+		arguments += fmt.Sprintf("%s %s", g.private(c.Name), g.generateColumnTypeString(c, modeMandatory))
+		values += g.private(c.Name)
+		where += fmt.Sprintf("%s = $%d", c.Name, i+1)
+	}
+
+	return suffix, arguments, values, where
 }
 
-func (g *Generator) generateRepositoryDeleteOneByPrimaryKey(code *bytes.Buffer,
-	table *pqt.Table) {
-	entityName := g.name(table.Name)
-	pk, ok := table.PrimaryKey()
-	if !ok {
+// generateRepositoryContextFree generates method that delegates to its context aware counterpart using default context of the repository.
+// It does nothing if context support is disabled.
+func (g *Generator) generateRepositoryContextFree(w io.Writer, t *pqt.Table, method, params, args, results string) {
+	m := repositoryMethod{name: method, params: params, args: args, results: results}
+	g.methods = append(g.methods, m)
+	if !g.ctx {
 		return
 	}
 
-	fmt.Fprintf(code, `
-		func (r *%sRepositoryBase) %s%s(%s %s) (int64, error) {
-			query := "DELETE FROM %s WHERE %s = $1"
+	g.generateContextFree(w, g.name(tableIdent(t))+"RepositoryBase", m)
+}
 
-			res, err := r.db.Exec(query, %s)
-			if err != nil {
-				return 0, err
-			}
+// generateContextFree generates method of given receiver type that delegates to its context aware counterpart,
+// using context bounded by the repository timeout.
+func (g *Generator) generateContextFree(w io.Writer, receiver string, m repositoryMethod) {
+	// Iterator reads rows after the method returns, so its context is cancelled once the iterator is closed.
+	if strings.HasSuffix(m.results, "Iterator, error)") {
+		fmt.Fprintf(w, `func (r *%s) %s(%s) %s {
+	ctx, cancel := r.%s()
+	it, err := r.%s(ctx, %s)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	it.cancel = cancel
 
-			return res.RowsAffected()
-		}
-`, entityName, g.name("DeleteOneBy"), g.public(pk.Name), g.private(pk.Name), g.generateColumnTypeString(pk, 1), table.FullName(), pk.Name, g.private(pk.Name))
+	return it, nil
+}
+`, receiver, g.name(m.name), m.params, m.results, g.name("defaultContext"), g.methodName(m.name), m.args)
+		return
+	}
+
+	fmt.Fprintf(w, `func (r *%s) %s(%s) %s {
+	ctx, cancel := r.%s()
+	defer cancel()
+
+	return r.%s(ctx, %s)
+}
+`, receiver, g.name(m.name), m.params, m.results, g.name("defaultContext"), g.methodName(m.name), m.args)
 }
 
 func sortedColumns(columns []*pqt.Column) []string {
@@ -1702,6 +4933,124 @@ func snake(s string, private bool, acronyms map[string]string) string {
 	return strings.Join(parts, "")
 }
 
+// methodName returns name of the repository method, if context support is enabled it gets Context suffix.
+func (g *Generator) methodName(s string) string {
+	if g.ctx {
+		return g.name(s) + "Context"
+	}
+
+	return g.name(s)
+}
+
+// contextArg returns context argument definition if context support is enabled.
+func (g *Generator) contextArg() string {
+	if g.ctx {
+		return "ctx context.Context, "
+	}
+
+	return ""
+}
+
+// contextParam returns context argument that is passed down if context support is enabled.
+func (g *Generator) contextParam() string {
+	if g.ctx {
+		return "ctx, "
+	}
+
+	return ""
+}
+
+// querier returns expression that gives access to the database for methods that execute queries of fixed shape.
+// If prepared statements are enabled, such queries go through the statement cache.
+// querierType returns type of the repository db property.
+func (g *Generator) querierType() string {
+	if g.driver == DriverPgx {
+		return g.name("pgxQuerier")
+	}
+	return "pqtgo.Querier"
+}
+
+// rowsType returns type of the result set of query that returns multiple rows.
+func (g *Generator) rowsType() string {
+	if g.driver == DriverPgx {
+		return "pgx.Rows"
+	}
+	return "*sql.Rows"
+}
+
+// rowType returns type of the result of query that returns single row.
+func (g *Generator) rowType() string {
+	if g.driver == DriverPgx {
+		return "pgx.Row"
+	}
+	return "*sql.Row"
+}
+
+// txType returns type of the transaction accepted by WithTx method.
+func (g *Generator) txType() string {
+	if g.driver == DriverPgx {
+		return "pgx.Tx"
+	}
+	return "*sql.Tx"
+}
+
+// errNoRows returns error that the driver returns if query selected no rows.
+func (g *Generator) errNoRows() string {
+	if g.driver == DriverPgx {
+		return "pgx.ErrNoRows"
+	}
+	return "sql.ErrNoRows"
+}
+
+// rowsAffected returns expression that evaluates to number of rows affected by execution result held by res and an error.
+// Command tag of pgx always holds it, so error is nil.
+func (g *Generator) rowsAffected() string {
+	if g.driver == DriverPgx {
+		return "res.RowsAffected(), nil"
+	}
+	return "res.RowsAffected()"
+}
+
+func (g *Generator) querier() string {
+	if g.prepared {
+		return "r." + g.private("querier") + "()"
+	}
+	return "r.db"
+}
+
+// countQuerier returns code that picks the database used by count method,
+// query has fixed shape only if criteria is empty, which is also the case if query has no arguments.
+func (g *Generator) countQuerier() string {
+	if !g.prepared {
+		return ""
+	}
+	return fmt.Sprintf(`	db := r.db
+	if len(args) == 0 {
+		db = %s
+	}
+`, g.querier())
+}
+
+// countQuerierName returns name of the database picked by countQuerier.
+func (g *Generator) countQuerierName() string {
+	if !g.prepared {
+		return "r.db"
+	}
+	return "db"
+}
+
+// dbCall returns opening part of a call to given database method, context aware counterpart is used if context support is enabled.
+func (g *Generator) dbCall(fn string) string {
+	if g.driver == DriverPgx {
+		return fn + "(ctx, "
+	}
+	if g.ctx {
+		return fn + "Context(ctx, "
+	}
+
+	return fn + "("
+}
+
 func (g *Generator) propertyName(s string) string {
 	n := g.name(s)
 	if r, ok := keywords[n]; ok {
@@ -1749,11 +5098,11 @@ func (g *Generator) canBeNil(c *pqt.Column, m int) bool {
 			if ct, ok := mapto.(CustomType); ok {
 				switch m {
 				case modeMandatory:
-					return ct.mandatoryTypeOf.Kind() == reflect.Ptr || ct.mandatoryTypeOf.Kind() == reflect.Map
+					return ct.canBeNil(ct.mandatoryTypeOf)
 				case modeOptional:
-					return ct.optionalTypeOf.Kind() == reflect.Ptr || ct.optionalTypeOf.Kind() == reflect.Map
+					return ct.canBeNil(ct.optionalTypeOf)
 				case modeCriteria:
-					return ct.criteriaTypeOf.Kind() == reflect.Ptr || ct.criteriaTypeOf.Kind() == reflect.Map
+					return ct.canBeNil(ct.criteriaTypeOf)
 				default:
 					return false
 				}
@@ -1763,6 +5112,34 @@ func (g *Generator) canBeNil(c *pqt.Column, m int) bool {
 	return false
 }
 
+// isJSON returns true if given column is mapped using TypeCustomJSON.
+func (g *Generator) isJSON(c *pqt.Column) bool {
+	if tp, ok := c.Type.(pqt.MappableType); ok {
+		for _, mapto := range tp.Mapping {
+			if ct, ok := mapto.(CustomType); ok {
+				return ct.json
+			}
+		}
+	}
+	return false
+}
+
+// scanTarget returns expression that is passed to Scan in order to populate property of given variable.
+func (g *Generator) scanTarget(v string, c *pqt.Column) string {
+	if g.isJSON(c) {
+		return fmt.Sprintf("pqtgo.JSON(&%s.%s)", v, g.propertyName(c.Name))
+	}
+	return fmt.Sprintf("&%s.%s", v, g.propertyName(c.Name))
+}
+
+// argument returns expression that is passed as a query argument for property of given variable.
+func (g *Generator) argument(v string, c *pqt.Column) string {
+	if g.isJSON(c) {
+		return fmt.Sprintf("pqtgo.JSON(%s.%s)", v, g.propertyName(c.Name))
+	}
+	return fmt.Sprintf("%s.%s", v, g.propertyName(c.Name))
+}
+
 func chooseType(tm, to, tc string, mode int32) string {
 	switch mode {
 	case modeCriteria:
@@ -1778,6 +5155,124 @@ func chooseType(tm, to, tc string, mode int32) string {
 	}
 }
 
+// defaultCondition returns condition that is true if property of given variable is set,
+// for a column that has default value on insert. Otherwise, zero value would override the default.
+// Empty string is returned if column has no default or it is not possible to tell if property is set.
+func (g *Generator) defaultCondition(v string, c *pqt.Column) string {
+	if _, ok := c.DefaultOn(pqt.EventInsert); !ok {
+		return ""
+	}
+
+	return g.setCondition(v, c)
+}
+
+// setCondition returns condition that is true if property of given variable holds non-zero value.
+// Empty string is returned for types that cannot be checked that way.
+func (g *Generator) setCondition(v string, c *pqt.Column) string {
+	prop := v + "." + g.propertyName(c.Name)
+	switch gt := g.generateColumnTypeString(c, modeDefault); {
+	case strings.HasPrefix(gt, "*"), strings.HasPrefix(gt, "[]"), strings.HasPrefix(gt, "pqt.Array"):
+		return prop + " != nil"
+	case gt == "time.Time":
+		return "!" + prop + ".IsZero()"
+	case gt == "uuid.UUID":
+		return prop + " != (uuid.UUID{})"
+	case gt == "string":
+		return prop + ` != ""`
+	case gt == "bool":
+		return prop
+	case strings.HasPrefix(gt, "int"), strings.HasPrefix(gt, "uint"), strings.HasPrefix(gt, "float"):
+		return prop + " != 0"
+	default:
+		return ""
+	}
+}
+
+// zeroCondition returns condition that is true if property of given variable holds zero value.
+// Empty string is returned for types for which zero value is a legitimate one, like booleans and numbers.
+func (g *Generator) zeroCondition(v string, c *pqt.Column) string {
+	prop := v + "." + g.propertyName(c.Name)
+	switch gt := g.generateColumnTypeString(c, modeDefault); {
+	case strings.HasPrefix(gt, "*"), strings.HasPrefix(gt, "[]"), strings.HasPrefix(gt, "pqt.Array"):
+		return prop + " == nil"
+	case gt == "time.Time":
+		return prop + ".IsZero()"
+	case gt == "uuid.UUID":
+		return prop + " == (uuid.UUID{})"
+	case gt == "string":
+		return prop + ` == ""`
+	default:
+		return ""
+	}
+}
+
+// insertBatchColumns returns columns that are listed by statement that inserts multiple rows at once.
+// Serial, identity and generated columns are omitted, columns with default value are not, see insertBatchCondition.
+func insertBatchColumns(t *pqt.Table) pqt.Columns {
+	var columns pqt.Columns
+	for _, c := range t.Columns {
+		if c.Generated != "" || c.Identity != "" {
+			continue
+		}
+		switch c.Type {
+		case pqt.TypeSerial(), pqt.TypeSerialBig(), pqt.TypeSerialSmall():
+			continue
+		}
+		columns = append(columns, c)
+	}
+
+	return columns
+}
+
+// insertBatchCondition returns condition that is true if entity e holds explicit value of given column with default value.
+// Empty string is returned if column has no default or value cannot be checked, then it is always provided, like by insert.
+func (g *Generator) insertBatchCondition(c *pqt.Column) string {
+	if _, ok := c.DefaultOn(pqt.EventInsert); !ok {
+		return ""
+	}
+	if g.canBeNil(c, modeOptional) {
+		return "e." + g.propertyName(c.Name) + " != nil"
+	}
+
+	return g.defaultCondition("e", c)
+}
+
+// batchColumns returns columns that are provided explicitly by COPY, which cannot fall back to default value per row.
+// Serial, identity, generated and columns with default value are omitted, otherwise each row would have to provide them.
+func batchColumns(t *pqt.Table) pqt.Columns {
+	var columns pqt.Columns
+	for _, c := range t.Columns {
+		if c.Generated != "" || c.Identity != "" {
+			continue
+		}
+		if _, ok := c.DefaultOn(pqt.EventInsert); ok {
+			continue
+		}
+		switch c.Type {
+		case pqt.TypeSerial(), pqt.TypeSerialBig(), pqt.TypeSerialSmall():
+			continue
+		}
+		columns = append(columns, c)
+	}
+
+	return columns
+}
+
+// primaryKey returns columns that primary key of given table consist of.
+// It handles both single column primary key and composite one defined as a table constraint.
+func primaryKey(t *pqt.Table) (pqt.Columns, bool) {
+	if pk, ok := t.PrimaryKey(); ok {
+		return pqt.Columns{pk}, true
+	}
+	for _, c := range t.Constraints {
+		if c.Type == pqt.ConstraintTypePrimaryKey && len(c.Columns) > 0 {
+			return c.Columns, true
+		}
+	}
+
+	return nil, false
+}
+
 func tableConstraints(t *pqt.Table) []*pqt.Constraint {
 	var constraints []*pqt.Constraint
 	for _, c := range t.Columns {
@@ -1787,6 +5282,20 @@ func tableConstraints(t *pqt.Table) []*pqt.Constraint {
 	return append(constraints, t.Constraints...)
 }
 
+// isArray returns true if given type is one of the array types pqt provides.
+func isArray(t pqt.Type) bool {
+	if _, ok := t.(pqt.BaseType); !ok {
+		return false
+	}
+	for _, prefix := range []string{"SMALLINT[", "INTEGER[", "BIGINT[", "DOUBLE PRECISION[", "TEXT["} {
+		if strings.HasPrefix(t.String(), prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func generateBaseType(t pqt.Type, m int32) string {
 	switch t {
 	case pqt.TypeText():
@@ -1827,7 +5336,7 @@ func generateBaseType(t pqt.Type, m int32) string {
 		case strings.HasPrefix(gt, "DOUBLE PRECISION["):
 			return chooseType("pqt.ArrayFloat64", "pqt.ArrayFloat64", "*qtypes.Float64", m)
 		case strings.HasPrefix(gt, "TEXT["):
-			return "pqt.ArrayString"
+			return chooseType("pqt.ArrayString", "pqt.ArrayString", "*qtypes.String", m)
 		case strings.HasPrefix(gt, "DECIMAL"), strings.HasPrefix(gt, "NUMERIC"):
 			return chooseType("float64", "*ntypes.Float64", "*qtypes.Float64", m)
 		case strings.HasPrefix(gt, "VARCHAR"):
@@ -1901,6 +5410,15 @@ func (g *Generator) writeTableNameColumnNameTo(w io.Writer, tableName, columnNam
 	fmt.Fprintf(w, "%s%sColumn%s", g.name("table"), g.public(tableName), g.public(columnName))
 }
 
+// tableIdent returns name that Go identifiers of the table are derived from.
+// Name of the table placed in other schema using pqt.WithSchema is prefixed with the schema name, so identifiers do not collide.
+func tableIdent(t *pqt.Table) string {
+	if t.Namespace != "" {
+		return t.Namespace + "_" + t.Name
+	}
+	return t.Name
+}
+
 func (g *Generator) columnNameWithTableName(tableName, columnName string) string {
 	return fmt.Sprintf("%s%sColumn%s", g.name("table"), g.public(tableName), g.public(columnName))
 }
@@ -1931,10 +5449,73 @@ func (g *Generator) shouldBeColumnIgnoredForCriteria(c *pqt.Column) bool {
 	//return false
 }
 
+// fieldTagsOf returns struct tag of entity property that column is mapped to, see SetFieldTags.
+func (g *Generator) fieldTagsOf(c *pqt.Column) reflect.StructTag {
+	tags := make([]string, 0, len(g.fieldTags))
+	for _, key := range g.fieldTags {
+		value := c.Name
+		if key == "json" && !c.NotNull && !c.PrimaryKey {
+			value += ",omitempty"
+		}
+		tags = append(tags, fmt.Sprintf(`%s:"%s"`, key, value))
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	return reflect.StructTag("`" + strings.Join(tags, " ") + "`")
+}
+
 type structField struct {
-	Name string
-	Type string
-	Tags reflect.StructTag
+	Name    string
+	Type    string
+	Tags    reflect.StructTag
+	Comment string
+}
+
+// generateComment writes given text as a Go comment, line by line.
+func generateComment(w io.Writer, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		fmt.Fprintf(w, "// %s\n", strings.TrimSpace(line))
+	}
+}
+
+// softDeleteCondition returns condition that excludes rows marked as deleted, if soft delete is enabled for the table.
+func softDeleteCondition(t *pqt.Table) string {
+	if !t.SoftDelete {
+		return ""
+	}
+	return " AND " + softDeleteColumn(t) + " IS NULL"
+}
+
+// softDeleteColumn returns name of the column that marks rows as deleted.
+// versionColumn returns column set by pqt.WithVersionColumn table option, if any.
+func versionColumn(t *pqt.Table) *pqt.Column {
+	if t.VersionColumnName == "" {
+		return nil
+	}
+	for _, c := range t.Columns {
+		if c.Name == t.VersionColumnName {
+			return c
+		}
+	}
+	return nil
+}
+
+func softDeleteColumn(t *pqt.Table) string {
+	if t.SoftDeleteColumnName != "" {
+		return t.SoftDeleteColumnName
+	}
+	return pqt.SoftDeleteColumn
+}
+
+// prefixEach returns given strings concatenated, each preceded by given prefix.
+func prefixEach(prefix string, s []string) string {
+	var res string
+	for _, ss := range s {
+		res += prefix + ss
+	}
+
+	return res
 }
 
 func or(s1, s2 string) string {
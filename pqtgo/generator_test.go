@@ -3,12 +3,20 @@ package pqtgo_test
 import (
 	"bytes"
 	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/aryann/difflib"
 	"github.com/piotrkowalczuk/pqt"
 	"github.com/piotrkowalczuk/pqt/pqtgo"
+	"github.com/piotrkowalczuk/pqt/pqtsql"
 )
 
 func TestGenerator_Generate(t *testing.T) {
@@ -96,19 +104,111 @@ func (e *firstEntity) props(cns ...string) ([]interface{}, error) {
 		}
 		return res, nil
 }
+func (e *firstEntity) value(cn string) (interface{}, bool) {
+switch cn {
+case tableFirstColumnId:
+return e.id, true
+case tableFirstColumnName:
+return e.name, true
+default:
+return nil, false
+}
+}
+func (e *firstEntity) values(cns ...string) ([]interface{}, error) {
+
+		res := make([]interface{}, 0, len(cns))
+		for _, cn := range cns {
+			if value, ok := e.value(cn); ok {
+				res = append(res, value)
+			} else {
+				return nil, fmt.Errorf("unexpected column provided: %s", cn)
+			}
+		}
+		return res, nil
+}
+
+// toMap returns values of the entity keyed by column names, nullable properties are either underlying value or nil.
+func (e *firstEntity) toMap() map[string]interface{} {
+	m := make(map[string]interface{}, 2)
+if e.id != nil && e.id.Valid {
+		m[tableFirstColumnId] = e.id.Int64
+	} else {
+		m[tableFirstColumnId] = nil
+	}
+if e.name != nil && e.name.Valid {
+		m[tableFirstColumnName] = e.name.String
+	} else {
+		m[tableFirstColumnName] = nil
+	}
+
+return m
+}
+
+// firstEntityFromMap returns entity built from given values keyed by column names, missing columns are left unset.
+// Error is returned if column is unknown or its value is not of the expected type, nil is accepted by nullable columns only.
+func firstEntityFromMap(m map[string]interface{}) (*firstEntity, error) {
+	e := &firstEntity{}
+	for cn, v := range m {
+		switch cn {
+case tableFirstColumnId:
+if v == nil {
+				e.id = nil
+				continue
+			}
+			if tv, ok := v.(int64); ok {
+				e.id = &ntypes.Int64{Int64: tv, Valid: true}
+				continue
+			}
+			return nil, fmt.Errorf("first from map failure, column %s expects value of type int64, got %T", cn, v)
+case tableFirstColumnName:
+if v == nil {
+				e.name = nil
+				continue
+			}
+			if tv, ok := v.(string); ok {
+				e.name = &ntypes.String{String: tv, Valid: true}
+				continue
+			}
+			return nil, fmt.Errorf("first from map failure, column %s expects value of type string, got %T", cn, v)
+default:
+			return nil, fmt.Errorf("first from map failure, unexpected column provided: %s", cn)
+		}
+	}
+
+	return e, nil
+}
+
+// validate returns pqt.ValidationError if any of mandatory properties is not set.
+func (e *firstEntity) validate() error {
+	var violations []pqt.Violation
+if len(violations) > 0 {
+		return &pqt.ValidationError{Table: tableFirst, Violations: violations}
+	}
+
+	return nil
+}
+
 
 
 // firstIterator is not thread safe.
+// It has to be closed once it is no longer needed, otherwise underlying connection is not released.
 type firstIterator struct {
 	rows *sql.Rows
 	cols []string
+	// cancel releases context the rows are read within, if the iterator owns it.
+	cancel context.CancelFunc
 }
 
+var _ pqtgo.Iterator = &firstIterator{}
+
 func (i *firstIterator) Next() bool {
 	return i.rows.Next()
 }
 
 func (i *firstIterator) Close() error {
+	if i.cancel != nil {
+		defer i.cancel()
+	}
 	return i.rows.Close()
 }
 
@@ -128,8 +228,8 @@ func (i *firstIterator) Columns() ([]string, error) {
 	return i.cols, nil
 }
 
-// Ent is wrapper around first method that makes iterator more generic.
-func (i *firstIterator) Ent() (interface{}, error) {
+// Ent is wrapper around first method, it allows to use the same method name regardless of the table.
+func (i *firstIterator) Ent() (*firstEntity, error) {
 	return i.First()
 }
 
@@ -149,9 +249,16 @@ func (i *firstIterator) First() (*firstEntity, error) {
 	}
 	return &ent, nil
 }
+// firstCriteria selects entities that queries of the repository operate on, its zero value selects all of them.
+// Offset and Limit are translated into OFFSET and LIMIT clauses if non-zero, lock appends locking clause that is effective within a transaction only.
+// Keys of sort have to be columns of the table.
+// Expressions of sortExpr precede sort, they can refer to columns of the table and call functions listed in pqt.SortFunctions.
 type firstCriteria struct {
-offset, limit int64
+Offset, Limit int64
 sort map[string]bool
+sortExpr []pqt.SortExpr
+countDistinct string
+lock pqt.LockMode
 id *qtypes.Int64
 name *qtypes.String
 }
@@ -166,27 +273,33 @@ func (c *firstCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *p
 			return
 		}
 
-	if len(c.sort) > 0 {
-		i:=0
-		com.WriteString(" ORDER BY ")
+	return c.writeSuffix(com)
+}
 
-		for cn, asc := range c.sort {
-			for _, tcn := range tableFirstColumns {
-				if cn == tcn {
-					if i > 0 {
-						com.WriteString(", ")
-					}
-					com.WriteString(cn)
-					if !asc {
-						com.WriteString(" DESC ")
-					}
-					i++
-					break
-				}
+// writeSuffix writes ORDER BY, OFFSET, LIMIT and locking clauses that follow the condition.
+func (c *firstCriteria) writeSuffix(com *pqtgo.Composer) (err error) {
+SortLoop:
+	for cn := range c.sort {
+		for _, tcn := range tableFirstColumns {
+			if cn == tcn {
+				continue SortLoop
 			}
 		}
+		return fmt.Errorf("pqt: unknown sort column %q", cn)
+	}
+
+	// Columns are ordered by name, map does not preserve the order and random one would make results unstable.
+	// Sort expressions, e.g. window functions, are ordered explicitly, so they come first.
+	if keys := pqtgo.Keyset(c.sort, tableFirstColumns); len(keys) > 0 || len(c.sortExpr) > 0 {
+		orderBy, err := pqtgo.OrderBy(c.sortExpr, keys, tableFirstColumns)
+		if err != nil {
+			return err
+		}
+		com.WriteString(" ORDER BY ")
+		com.WriteString(orderBy)
+		com.WriteString(" ")
 	}
-	if c.offset > 0 {
+	if c.Offset > 0 {
 		if _, err = com.WriteString(" OFFSET "); err != nil {
 			return
 		}
@@ -196,9 +309,9 @@ func (c *firstCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *p
 		if _, err = com.WriteString(" "); err != nil {
 			return
 		}
-		com.Add(c.offset)
+		com.Add(c.Offset)
 	}
-	if c.limit > 0 {
+	if c.Limit > 0 {
 		if _, err = com.WriteString(" LIMIT "); err != nil {
 			return
 		}
@@ -208,25 +321,107 @@ func (c *firstCriteria) WriteComposition(sel string, com *pqtgo.Composer, opt *p
 		if _, err = com.WriteString(" "); err != nil {
 			return
 		}
-		com.Add(c.limit)
+		com.Add(c.Limit)
+	}
+	if c.lock != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.lock.String())
 	}
 
 	return
 }
+// whereClause returns condition built from the criteria, without WHERE keyword, and its arguments in order.
+// Placeholders are numbered starting at given index, so the clause can be embedded into a custom query.
+// Sort, offset, limit and lock are ignored.
+func (c *firstCriteria) whereClause(startIdx int) (string, []interface{}, error) {
+	cc := *c
+	cc.sort, cc.sortExpr, cc.Offset, cc.Limit, cc.lock = nil, nil, 0, 0, pqt.LockNone
+
+	com := pqtgo.NewComposerAt(2, startIdx)
+	if err := cc.WriteComposition("", com, pqtgo.And); err != nil {
+		return "", nil, err
+	}
+
+	return com.String(), com.Args(), nil
+}
+
 type firstPatch struct {
 id *ntypes.Int64
 name *ntypes.String
 }
 
+// validate returns pqt.ValidationError if patch sets NULL to any of NOT NULL columns.
+func (p *firstPatch) validate() error {
+	var violations []pqt.Violation
+if len(violations) > 0 {
+		return &pqt.ValidationError{Table: tableFirst, Violations: violations}
+	}
+
+	return nil
+}
+
+// firstPage is a single page of entities returned by findPage method.
+// Cursors of the first and the last entity allow to request adjacent pages.
+type firstPage struct {
+	edges []*firstEntity
+	hasNextPage, hasPrevPage bool
+	startCursor, endCursor string
+}
+
+// firstBeforeInsertHook is called by insert, insertReturningColumns, insertBatch, bulkInsert, upsertOn and upsert before the entity is stored, it can modify the entity, e.g. set columns from the context.
+// Returned error aborts the insert.
+type firstBeforeInsertHook func(ctx context.Context, e *firstEntity) error
+
+// firstAfterInsertHook is called by insert and insertBatch after the entity is stored, with values returned by the database.
+// It is not called by insertReturningColumns and bulkInsert, they do not read back the whole entity.
+// It is not called by upsertOn and upsert either, they cannot tell inserted row from updated one.
+// Returned error is passed to the caller, but the entity is already stored, unless insert is executed within a transaction that is rolled back.
+type firstAfterInsertHook func(ctx context.Context, e *firstEntity) error
+
 
-		type firstRepositoryBase struct {
+// firstRepositoryBase is a data access layer of the text.first table.
+// Optional explain hook receives execution plans of read only queries in debug mode, optional planner builds conditions out of criteria instead of them.
+type firstRepositoryBase struct {
 			table string
 			columns []string
-			db *sql.DB
+			db pqtgo.Querier
 			dbg bool
 			log log.Logger
-		}
-	func scanFirstRows(rows *sql.Rows) ([]*firstEntity, error) {
+			bulkSize int
+			explain pqt.ExplainHook
+			planner pqt.Planner
+	beforeInsert firstBeforeInsertHook
+afterInsert firstAfterInsertHook
+	}
+	// withTx returns copy of the repository that executes all queries within given transaction.
+func (r *firstRepositoryBase) withTx(tx *sql.Tx) *firstRepositoryBase {
+	rt := *r
+	rt.db = tx
+
+	return &rt
+}
+// explainQuery runs given query using EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) and passes the plan to the explain hook.
+// As the query is executed, it is called in debug mode by read only methods only.
+func (r *firstRepositoryBase) explainQuery(query string, args []interface{}) error {
+	var plan []byte
+	if err := r.db.QueryRow("EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+query, args...).Scan(&plan); err != nil {
+		return err
+	}
+	r.explain(json.RawMessage(plan), query, args)
+
+	return nil
+}
+
+// plan returns condition of given criteria and its arguments, built by the planner if it is set.
+func (r *firstRepositoryBase) plan(c *firstCriteria) (string, []interface{}, error) {
+	if r.planner != nil {
+		return r.planner.Plan(c)
+	}
+	return c.whereClause(1)
+}
+
+// scanFirstRows reads all rows into entities, each row has to consist of columns listed in tableFirstColumns, in the same order.
+func scanFirstRows(rows *sql.Rows) ([]*firstEntity, error) {
 	var (
 		entities []*firstEntity
 		err error
@@ -250,189 +445,845 @@ name *ntypes.String
 		return entities, nil
 	}
 
-	func (r *firstRepositoryBase) count(c *firstCriteria) (int64, error) {
+	// scanFirstRow reads single row into entity, the row has to consist of columns listed in tableFirstColumns, in the same order.
+// sql.ErrNoRows is returned if the query selected no rows.
+func scanFirstRow(row *sql.Row) (*firstEntity, error) {
+	var ent firstEntity
+	err := row.Scan(
+	&ent.id,
+&ent.name,
+)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ent, nil
+}
+
+// countQuery returns query and arguments used by count to count entities that match given criteria, without executing it.
+func (r *firstRepositoryBase) countQuery(c *firstCriteria) (string, []interface{}, error) {
 
-	com := pqtgo.NewComposer(2)
-	buf := bytes.NewBufferString("SELECT COUNT(*) FROM ")
+	buf := bytes.NewBufferString("SELECT ")
+	if c.countDistinct != "" {
+		var known bool
+		for _, cn := range tableFirstColumns {
+			if cn == c.countDistinct {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return "", nil, fmt.Errorf("first count failure, unknown column: %s", c.countDistinct)
+		}
+		buf.WriteString("COUNT(DISTINCT " + c.countDistinct + ")")
+	} else {
+		buf.WriteString("COUNT(*)")
+	}
+	buf.WriteString(" FROM ")
 	buf.WriteString(r.table)
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
-		return 0, err
+	// The same condition as used by find, but without sort, offset and limit that make no sense for an aggregate.
+	where, args, err := r.plan(c)
+	if err != nil {
+		return "", nil, err
 	}
-	if com.Dirty {
+	if where != "" {
 		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
+
+	return buf.String(), args, nil
+}
+
+// count returns number of entities that match given criteria. Sort, offset, limit and lock are ignored.
+func (r *firstRepositoryBase) count(c *firstCriteria) (int64, error) {
+	query, args, err := r.countQuery(c)
+	if err != nil {
+		return 0, err
 	}
 
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Count"); err != nil {
+		if err := r.log.Log("msg", query, "function", "Count"); err != nil {
 			return 0, err
 		}
+		if r.explain != nil {
+			if err := r.explainQuery(query, args); err != nil {
+				return 0, err
+			}
+		}
 	}
 
 	var count int64
-	if err := r.db.QueryRow(buf.String(), com.Args()...).Scan(&count); err != nil {
+	if err := r.db.QueryRow(query, args...).Scan(&count); err != nil {
 		return 0, err
 	}
 	return count, nil
 }
+// countDistinct returns number of distinct values of given column among entities that match given criteria.
+func (r *firstRepositoryBase) countDistinct(cn string, c *firstCriteria) (int64, error) {
+	cc := *c
+	cc.countDistinct = cn
 
-func (r *firstRepositoryBase) find(c *firstCriteria) ([]*firstEntity, error) {
+	return r.count(&cc)
+}
+// exists returns true if at least one entity matches given criteria, database stops at the first matching row.
+// Sort, offset and limit are ignored.
+func (r *firstRepositoryBase) exists(c *firstCriteria) (bool, error) {
+	buf := bytes.NewBufferString("SELECT EXISTS (SELECT 1 FROM ")
+	buf.WriteString(r.table)
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return false, err
+	}
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	}
+	buf.WriteString(")")
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Exists"); err != nil {
+			return false, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(buf.String(), args); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	var exists bool
+	if err := r.db.QueryRow(buf.String(), args...).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+// aggregate computes given aggregate function of the column among entities that match given criteria, grouped by values of groupBy column.
+// Column can be empty for pqt.AggregateCount, which then counts entities. If groupBy is empty, single result with nil group is returned.
+// Groups are ordered by their values. Sort, offset, limit and lock are ignored.
+// Unknown functions and columns are rejected, as well as SUM and AVG of non-numeric column, whose MIN and MAX are returned in Raw field.
+func (r *firstRepositoryBase) aggregate(c *firstCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error) {
+	if fn.String() == "" {
+		return nil, fmt.Errorf("first aggregate failure, unknown function: %d", fn)
+	}
+	if column == "" && fn != pqt.AggregateCount {
+		return nil, fmt.Errorf("first aggregate failure, column is required by %s", fn)
+	}
+	for _, cn := range []string{column, groupBy} {
+		if cn == "" {
+			continue
+		}
+		var known bool
+		for _, tc := range tableFirstColumns {
+			if tc == cn {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("first aggregate failure, unknown column: %s", cn)
+		}
+	}
+	// Result of COUNT and of any function of a numeric column is a number, other values are scanned as they are.
+	numeric := fn == pqt.AggregateCount
+	switch column {
+	case tableFirstColumnId:
+		numeric = true
+	}
+	if !numeric && (fn == pqt.AggregateSum || fn == pqt.AggregateAvg) {
+		return nil, fmt.Errorf("first aggregate failure, %s requires numeric column, got: %s", fn, column)
+	}
 
-	com := pqtgo.NewComposer(1)
+	expr := "*"
+	if column != "" {
+		expr = column
+	}
 	buf := bytes.NewBufferString("SELECT ")
-	buf.WriteString(strings.Join(r.columns, ", "))
+	if groupBy != "" {
+		buf.WriteString(groupBy)
+		buf.WriteString(", ")
+	}
+	buf.WriteString(fn.String() + "(" + expr + ")")
 	buf.WriteString(" FROM ")
 	buf.WriteString(r.table)
-	buf.WriteString(" ")
 
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
+	where, args, err := r.plan(c)
+	if err != nil {
 		return nil, err
 	}
-	if com.Dirty {
+	if where != "" {
 		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
+	if groupBy != "" {
+		buf.WriteString(" GROUP BY " + groupBy + " ORDER BY " + groupBy)
 	}
 
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Find"); err != nil {
+		if err := r.log.Log("msg", buf.String(), "function", "Aggregate"); err != nil {
 			return nil, err
 		}
+		if r.explain != nil {
+			if err := r.explainQuery(buf.String(), args); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	rows, err := r.db.Query(buf.String(), com.Args()...)
+	rows, err := r.db.Query(buf.String(), args...)
 	if err != nil {
 		return nil, err
 	}
-
 	defer rows.Close()
 
-	return scanFirstRows(rows)
+	var res []pqt.AggregateResult
+	for rows.Next() {
+		var (
+			group, raw interface{}
+			// Aggregate of a group that has no values, e.g. SUM of nulls, is null.
+			value sql.NullFloat64
+		)
+		dest := []interface{}{&value}
+		if !numeric {
+			dest[0] = &raw
+		}
+		if groupBy != "" {
+			dest = append([]interface{}{&group}, dest...)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		res = append(res, pqt.AggregateResult{Group: group, Value: value.Float64, Raw: raw})
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return res, nil
 }
-func (r *firstRepositoryBase) findIter(c *firstCriteria) (*firstIterator, error) {
+// findQuery returns query and arguments used by find to retrieve entities that match given criteria, without executing it.
+func (r *firstRepositoryBase) findQuery(c *firstCriteria) (string, []interface{}, error) {
+	where, args, err := r.plan(c)
+	if err != nil {
+		return "", nil, err
+	}
 
-	com := pqtgo.NewComposer(1)
 	buf := bytes.NewBufferString("SELECT ")
 	buf.WriteString(strings.Join(r.columns, ", "))
 	buf.WriteString(" FROM ")
 	buf.WriteString(r.table)
-	buf.WriteString(" ")
-
-	if err := c.WriteComposition("", com, pqtgo.And); err != nil {
-		return nil, err
-	}
-	if com.Dirty {
+	if where != "" {
 		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
 	}
-	if com.Len() > 0 {
-		buf.ReadFrom(com)
+
+	com := pqtgo.NewComposerAt(2, len(args)+1)
+	if err := c.writeSuffix(com); err != nil {
+		return "", nil, err
+	}
+	buf.ReadFrom(com)
+
+	return buf.String(), append(args, com.Args()...), nil
+}
+
+
+// find returns entities that match given criteria.
+func (r *firstRepositoryBase) find(c *firstCriteria) ([]*firstEntity, error) {
+
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
 	}
 
 	if r.dbg {
-		if err := r.log.Log("msg", buf.String(), "function", "Find"); err != nil {
+		if err := r.log.Log("msg", query, "function", "Find"); err != nil {
 			return nil, err
 		}
+		if r.explain != nil {
+			if err := r.explainQuery(query, args); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	rows, err := r.db.Query(buf.String(), com.Args()...)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 
+	defer rows.Close()
 
-	return &firstIterator{rows: rows}, nil
+	return scanFirstRows(rows)
 }
-func (r *firstRepositoryBase) insert(e *firstEntity) (*firstEntity, error) {
-		insert := pqcomp.New(0, 2)
-	insert.AddExpr(tableFirstColumnName, "", e.name)
-
-		b := bytes.NewBufferString("INSERT INTO " + r.table)
-
-		if insert.Len() != 0 {
-			b.WriteString(" (")
-			for insert.Next() {
-				if !insert.First() {
-					b.WriteString(", ")
-				}
+// findIter returns iterator over entities that match given criteria.
+// Caller is responsible for closing the iterator, also if the loop exits early.
+func (r *firstRepositoryBase) findIter(c *firstCriteria) (*firstIterator, error) {
 
-				fmt.Fprintf(b, "%s", insert.Key())
-			}
-			insert.Reset()
-			b.WriteString(") VALUES (")
-			for insert.Next() {
-				if !insert.First() {
-					b.WriteString(", ")
-				}
+	query, args, err := r.findQuery(c)
+	if err != nil {
+		return nil, err
+	}
 
-				fmt.Fprintf(b, "%s", insert.PlaceHolder())
-			}
-			b.WriteString(")")
-			if len(r.columns) > 0 {
-				b.WriteString(" RETURNING ")
-				b.WriteString(strings.Join(r.columns, ", "))
-			}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Find"); err != nil {
+			return nil, err
 		}
-
-		if r.dbg {
-			if err := r.log.Log("msg", b.String(), "function", "Insert"); err != nil {
+		if r.explain != nil {
+			if err := r.explainQuery(query, args); err != nil {
 				return nil, err
 			}
 		}
+	}
 
-		err := r.db.QueryRow(b.String(), insert.Args()...).Scan(
-	&e.id,
-&e.name,
-)
-		if err != nil {
-			return nil, err
-		}
-
-		return e, nil
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
 	}
-func (r *firstRepositoryBase) upsert(e *firstEntity, p *firstPatch, inf ...string) (*firstEntity, error) {
-		insert := pqcomp.New(0, 2)
-		update := insert.Compose(2)
-	insert.AddExpr(tableFirstColumnName, "", e.name)
-if len(inf) > 0 {
-update.AddExpr(tableFirstColumnName, "=", p.name)
-}
 
-		b := bytes.NewBufferString("INSERT INTO " + r.table)
 
-		if insert.Len() > 0 {
-			b.WriteString(" (")
-			for insert.Next() {
-				if !insert.First() {
-					b.WriteString(", ")
-				}
+	return &firstIterator{rows: rows}, nil
+}
+// findPage returns page of entities that match given criteria using cursor based (keyset) pagination.
+// Rows are ordered by sort columns of the criteria, followed by primary key, so cursors remain valid if rows are inserted or deleted.
+// Limit of the criteria defines size of the page, offset is not supported.
+// Values held by cursors are decoded into properties of the entity, so they keep types of their columns.
+func (r *firstRepositoryBase) findPage(c *firstCriteria, page pqt.CursorPage) (*firstPage, error) {
+	if c.Offset > 0 {
+		return nil, errors.New("first find page failure, offset is not supported")
+	}
+	if len(c.sortExpr) > 0 {
+		return nil, errors.New("first find page failure, sort expressions are not supported")
+	}
+	if c.Limit <= 0 {
+		return nil, errors.New("first find page failure, limit has to be positive")
+	}
+	if page.After != "" && page.Before != "" {
+		return nil, errors.New("first find page failure, after and before cursors cannot be used together")
+	}
 
-				fmt.Fprintf(b, "%s", insert.Key())
+SortLoop:
+	for cn := range c.sort {
+		for _, tcn := range tableFirstColumns {
+			if cn == tcn {
+				continue SortLoop
 			}
-			insert.Reset()
-			b.WriteString(") VALUES (")
-			for insert.Next() {
-				if !insert.First() {
-					b.WriteString(", ")
-				}
+		}
+		return nil, fmt.Errorf("pqt: unknown sort column %q", cn)
+	}
 
-				fmt.Fprintf(b, "%s", insert.PlaceHolder())
-			}
-			b.WriteString(")")
+	keys := pqtgo.Keyset(c.sort, tableFirstColumns)
+	if len(keys) == 0 {
+		return nil, errors.New("first find page failure, sort or primary key is required")
+	}
+	cursor := func(ent *firstEntity) (string, error) {
+		values := make(map[string]interface{}, len(keys))
+		for _, k := range keys {
+			values[k.Name], _ = ent.value(k.Name)
 		}
-		b.WriteString(" ON CONFLICT ")
-		if len(inf) > 0 && update.Len() > 0 {
-			b.WriteString(" (")
-			for j, i := range inf {
-				if j != 0 {
-					b.WriteString(", ")
-				}
-				b.WriteString(i)
+		return pqt.EncodeCursor(values)
+	}
+
+	// Previous page is fetched by traversing rows in opposite order, starting from the cursor.
+	from, backward := page.After, page.Before != ""
+	order := keys
+	if backward {
+		from, order = page.Before, pqtgo.ReverseKeyset(keys)
+	}
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return nil, err
+	}
+	com := pqtgo.NewComposerAt(2, len(args)+1)
+	for _, arg := range args {
+		com.Add(arg)
+	}
+	if where != "" {
+		// Condition of the planner can consist of alternatives, keyset condition is added to all of them.
+		com.WriteString("(" + where + ")")
+		com.Dirty = true
+	}
+	buf := bytes.NewBufferString("SELECT ")
+	buf.WriteString(strings.Join(r.columns, ", "))
+	buf.WriteString(" FROM ")
+	buf.WriteString(r.table)
+
+	if from != "" {
+		// Cursor values are decoded into properties of the entity, so they keep types of the columns.
+		var cur firstEntity
+		dest := make(map[string]interface{}, len(order))
+		for _, k := range order {
+			dest[k.Name], _ = cur.prop(k.Name)
+		}
+		if err := pqt.DecodeCursorTo(from, dest); err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, 0, len(order))
+		for _, k := range order {
+			v, _ := cur.value(k.Name)
+			values = append(values, v)
+		}
+		if com.Dirty {
+			com.WriteString(" AND ")
+		}
+		if err := pqtgo.WriteKeysetCondition(com, order, values); err != nil {
+			return nil, err
+		}
+		com.Dirty = true
+	}
+	if com.Dirty {
+		buf.WriteString(" WHERE ")
+	}
+	com.WriteString(" ORDER BY ")
+	com.WriteString(pqtgo.KeysetOrderBy(order))
+	com.WriteString(" LIMIT ")
+	if err := com.WritePlaceholder(); err != nil {
+		return nil, err
+	}
+	// One more row tells if there is another page in the direction of traversal.
+	com.Add(c.Limit + 1)
+	if c.lock != pqt.LockNone {
+		com.WriteString(" ")
+		com.WriteString(c.lock.String())
+	}
+	buf.ReadFrom(com)
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "FindPage"); err != nil {
+			return nil, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(buf.String(), com.Args()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := r.db.Query(buf.String(), com.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ents, err := scanFirstRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	more := int64(len(ents)) > c.Limit
+	if more {
+		ents = ents[:c.Limit]
+	}
+	if backward {
+		for i, j := 0, len(ents)-1; i < j; i, j = i+1, j-1 {
+			ents[i], ents[j] = ents[j], ents[i]
+		}
+	}
+
+	res := &firstPage{edges: ents}
+	if backward {
+		res.hasNextPage, res.hasPrevPage = true, more
+	} else {
+		res.hasNextPage, res.hasPrevPage = more, page.After != ""
+	}
+	if len(ents) > 0 {
+		if res.startCursor, err = cursor(ents[0]); err != nil {
+			return nil, err
+		}
+		if res.endCursor, err = cursor(ents[len(ents)-1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+// findAndCount returns entities that match given criteria together with total number of matching entities, regardless of offset and limit.
+// Total is selected by the same query using COUNT(*) OVER() window function, which is evaluated before OFFSET and LIMIT are applied.
+// If the page is empty, but offset is set, total is obtained using count, so it is known even if the offset goes past the last entity.
+// Window functions cannot be combined with locking clause, so lock is not supported.
+func (r *firstRepositoryBase) findAndCount(c *firstCriteria) ([]*firstEntity, int64, error) {
+	if c.lock != pqt.LockNone {
+		return nil, 0, errors.New("first find and count failure, lock is not supported")
+	}
+
+	rr := *r
+	rr.columns = append(r.columns[:len(r.columns):len(r.columns)], "COUNT(*) OVER()")
+	query, args, err := rr.findQuery(c)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "FindAndCount"); err != nil {
+			return nil, 0, err
+		}
+		if r.explain != nil {
+			if err := r.explainQuery(query, args); err != nil {
+				return nil, 0, err
 			}
-			b.WriteString(") ")
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		entities []*firstEntity
+		total int64
+	)
+	for rows.Next() {
+		var ent firstEntity
+		err = rows.Scan(
+&ent.id,
+&ent.name,
+&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		entities = append(entities, &ent)
+	}
+	if rows.Err() != nil {
+		return nil, 0, rows.Err()
+	}
+	if len(entities) == 0 && c.Offset > 0 {
+		if total, err = r.count(c); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return entities, total, nil
+}
+// findOne returns single entity that matches given criteria.
+// pqt.ErrNotFound is returned if there is none, pqt.ErrMultipleRows if there is more than one.
+func (r *firstRepositoryBase) findOne(c *firstCriteria) (*firstEntity, error) {
+	cc := *c
+	cc.Limit = 2
+
+	ents, err := r.find(&cc)
+	if err != nil {
+		return nil, err
+	}
+	switch len(ents) {
+	case 0:
+		return nil, pqt.ErrNotFound
+	case 1:
+		return ents[0], nil
+	default:
+		return nil, pqt.ErrMultipleRows
+	}
+}
+// insertQuery returns query and arguments used by insert to save given entity, without executing it.
+func (r *firstRepositoryBase) insertQuery(e *firstEntity) (string, []interface{}, error) {
+		if err := e.validate(); err != nil {
+			return "", nil, err
+		}
+
+		insert := pqcomp.New(0, 2)
+	insert.AddExpr(tableFirstColumnName, "", e.name)
+
+		b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+		if insert.Len() != 0 {
+			b.WriteString(" (")
+			for insert.Next() {
+				if !insert.First() {
+					b.WriteString(", ")
+				}
+
+				fmt.Fprintf(b, "%s", insert.Key())
+			}
+			insert.Reset()
+			b.WriteString(") VALUES (")
+			for insert.Next() {
+				if !insert.First() {
+					b.WriteString(", ")
+				}
+
+				fmt.Fprintf(b, "%s", insert.PlaceHolder())
+			}
+			b.WriteString(")")
+			if len(r.columns) > 0 {
+				b.WriteString(" RETURNING ")
+				b.WriteString(strings.Join(r.columns, ", "))
+			}
+		}
+
+	return b.String(), insert.Args(), nil
+}
+
+// insert saves given entity and returns it with values read back from the database, e.g. defaults.
+func (r *firstRepositoryBase) insert(e *firstEntity) (*firstEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(context.Background(), e); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := r.insertQuery(e)
+	if err != nil {
+		return nil, err
+	}
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "Insert"); err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.db.QueryRow(query, args...).Scan(
+&e.id,
+&e.name,
+)
+		if err != nil {
+			return nil, err
+		}
+		if r.afterInsert != nil {
+		if err := r.afterInsert(context.Background(), e); err != nil {
+			return nil, err
+		}
+	}
+
+		return e, nil
+	}
+// insertReturningColumns works like insert, but returns new entity with only given columns populated, unknown columns are rejected before execution.
+func (r *firstRepositoryBase) insertReturningColumns(e *firstEntity, cols ...string) (*firstEntity, error) {
+	if r.beforeInsert != nil {
+		if err := r.beforeInsert(context.Background(), e); err != nil {
+			return nil, err
+		}
+	}
+
+		if len(cols) == 0 {
+			return nil, errors.New("first insert failure, no columns to return")
+		}
+		var ent firstEntity
+		props, err := ent.props(cols...)
+		if err != nil {
+			return nil, err
+		}
+	
+		if err := e.validate(); err != nil {
+			return nil, err
+		}
+
+		insert := pqcomp.New(0, 2)
+	insert.AddExpr(tableFirstColumnName, "", e.name)
+
+		b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+		if insert.Len() != 0 {
+			b.WriteString(" (")
+			for insert.Next() {
+				if !insert.First() {
+					b.WriteString(", ")
+				}
+
+				fmt.Fprintf(b, "%s", insert.Key())
+			}
+			insert.Reset()
+			b.WriteString(") VALUES (")
+			for insert.Next() {
+				if !insert.First() {
+					b.WriteString(", ")
+				}
+
+				fmt.Fprintf(b, "%s", insert.PlaceHolder())
+			}
+			b.WriteString(")")
+			b.WriteString(" RETURNING " + strings.Join(cols, ", "))
+		}
+
+	if r.dbg {
+			if err := r.log.Log("msg", b.String(), "function", "InsertReturningColumns"); err != nil {
+				return nil, err
+			}
+		}
+
+	err = r.db.QueryRow(b.String(), insert.Args()...).Scan(props...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ent, nil
+	}
+// insertBatch saves given entities using multi-row statements and returns them with values read back from the database.
+// Column with default value gets DEFAULT in rows that do not hold explicit value, like in insert.
+// All entities are validated first, so none of them is sent if any is invalid.
+func (r *firstRepositoryBase) insertBatch(es []*firstEntity) ([]*firstEntity, error) {
+	for _, e := range es {
+		if r.beforeInsert != nil {
+		if err := r.beforeInsert(context.Background(), e); err != nil {
+			return nil, err
+		}
+	}
+
+		if err := e.validate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, chunk := range pqtgo.Chunks(len(es), 1) {
+		batch := es[chunk[0]:chunk[1]]
+		com := pqtgo.NewComposer(int64(len(batch) * 1))
+		for i, e := range batch {
+			if i != 0 {
+				com.WriteString(", ")
+			}
+			com.WriteString("(")
+com.WritePlaceholder()
+com.Add(e.name)
+com.WriteString(")")
+		}
+
+		b := bytes.NewBufferString("INSERT INTO " + r.table + " (")
+b.WriteString(tableFirstColumnName)
+b.WriteString(") VALUES ")
+		b.ReadFrom(com)
+		if len(r.columns) > 0 {
+			b.WriteString(" RETURNING ")
+			b.WriteString(strings.Join(r.columns, ", "))
+		}
+
+		if r.dbg {
+			if err := r.log.Log("msg", b.String(), "function", "InsertBatch"); err != nil {
+				return nil, err
+			}
+		}
+
+		rows, err := r.db.Query(b.String(), com.Args()...)
+		if err != nil {
+			return nil, err
+		}
+		returned := 0
+		for ; returned < len(batch) && rows.Next(); returned++ {
+			err = rows.Scan(
+&batch[returned].id,
+&batch[returned].name,
+)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+		}
+		if err = rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+		if len(r.columns) > 0 && returned != len(batch) {
+			return nil, fmt.Errorf("first insert batch failure, %d rows inserted, but %d returned", len(batch), returned)
+		}
+		for _, e := range batch {
+			if r.afterInsert != nil {
+		if err := r.afterInsert(context.Background(), e); err != nil {
+			return nil, err
+		}
+	}
+
+		}
+	}
+
+	return es, nil
+}
+// bulkInsert loads given entities using COPY protocol and returns number of loaded rows.
+// Serial columns and columns with default value are omitted, they are populated by the database.
+// COPY does not support RETURNING clause, so generated values (e.g. ids) are not set on given entities.
+// All entities are validated first, so none of them is sent if any is invalid.
+// Database of the repository has to implement pqtgo.TxBeginner, like *sql.DB, or pqtgo.Preparer within a transaction, like *sql.Tx.
+func (r *firstRepositoryBase) bulkInsert(es []*firstEntity) (int64, error) {
+	for _, e := range es {
+		if r.beforeInsert != nil {
+		if err := r.beforeInsert(context.Background(), e); err != nil {
+			return 0, err
+		}
+	}
+
+		if err := e.validate(); err != nil {
+			return 0, err
+		}
+	}
+	query := pq.CopyInSchema("text", "first", tableFirstColumnName)
+	if r.dbg {
+		if err := r.log.Log("msg", query, "function", "BulkInsert"); err != nil {
+			return 0, err
+		}
+	}
+
+	return pqtgo.CopyIn(context.Background(), r.db, query, len(es), r.bulkSize, func(i int) []interface{} {
+		return []interface{}{
+es[i].name,
+}
+	})
+}
+// upsertOn saves given entity, on conflict with given target it updates existing row using the patch, or values of the entity if patch is nil.
+// pqt.ErrUpsertIgnored is returned if conflicting row was not updated.
+func (r *firstRepositoryBase) upsertOn(e *firstEntity, p *firstPatch, ct pqt.UpsertConflictTarget) (*firstEntity, error) {
+		if r.beforeInsert != nil {
+		if err := r.beforeInsert(context.Background(), e); err != nil {
+			return nil, err
+		}
+	}
+
+		if err := e.validate(); err != nil {
+			return nil, err
+		}
+		if p != nil {
+			if err := p.validate(); err != nil {
+				return nil, err
+			}
+		}
+
+		insert := pqcomp.New(0, 2)
+		update := insert.Compose(2)
+	insert.AddExpr(tableFirstColumnName, "", e.name)
+if p != nil && !ct.IsZero() {
+update.AddExpr(tableFirstColumnName, "=", p.name)
+}
+
+		b := bytes.NewBufferString("INSERT INTO " + r.table)
+
+		if insert.Len() > 0 {
+			b.WriteString(" (")
+			for insert.Next() {
+				if !insert.First() {
+					b.WriteString(", ")
+				}
+
+				fmt.Fprintf(b, "%s", insert.Key())
+			}
+			insert.Reset()
+			b.WriteString(") VALUES (")
+			for insert.Next() {
+				if !insert.First() {
+					b.WriteString(", ")
+				}
+
+				fmt.Fprintf(b, "%s", insert.PlaceHolder())
+			}
+			b.WriteString(")")
+		}
+		var excluded []string
+		if p == nil && !ct.IsZero() {
+			insert.Reset()
+			for insert.Next() {
+				if !ct.HasColumn(insert.Key()) {
+					excluded = append(excluded, insert.Key()+" = EXCLUDED."+insert.Key())
+				}
+			}
+		}
+
+		b.WriteString(" ON CONFLICT ")
+		if !ct.IsZero() {
+			b.WriteString(ct.String())
+		}
+		switch {
+		case len(excluded) > 0:
+			b.WriteString(" DO UPDATE SET ")
+			b.WriteString(strings.Join(excluded, ", "))
+		case !ct.IsZero() && update.Len() > 0:
 			b.WriteString(" DO UPDATE SET ")
 			for update.Next() {
 				if !update.First() {
@@ -445,7 +1296,7 @@ update.AddExpr(tableFirstColumnName, "=", p.name)
 				b.WriteString(" ")
 				b.WriteString(update.PlaceHolder())
 			}
-		} else {
+		default:
 			b.WriteString(" DO NOTHING ")
 		}
 		if insert.Len() > 0 {
@@ -455,22 +1306,85 @@ update.AddExpr(tableFirstColumnName, "=", p.name)
 			}
 		}
 
-		if r.dbg {
-			if err := r.log.Log("msg", b.String(), "function", "Upsert"); err != nil {
-				return nil, err
-			}
+		if r.dbg {
+			if err := r.log.Log("msg", b.String(), "function", "Upsert"); err != nil {
+				return nil, err
+			}
+		}
+
+		err := r.db.QueryRow(b.String(), insert.Args()...).Scan(
+	&e.id,
+&e.name,
+)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, pqt.ErrUpsertIgnored
+			}
+			return nil, err
+		}
+
+		return e, nil
+	}
+
+// upsert is a shorthand for upsertOn, given columns are used to infer the conflict target.
+func (r *firstRepositoryBase) upsert(e *firstEntity, p *firstPatch, inf ...string) (*firstEntity, error) {
+	return r.upsertOn(e, p, pqt.ConflictOnColumns(inf...))
+}
+// deleteByCriteria removes entities that match given criteria and returns their number.
+// Empty criteria is rejected with pqt.ErrDeleteWithoutCriteria unless full scan is allowed. Sort, offset, limit and lock are not supported.
+func (r *firstRepositoryBase) deleteByCriteria(c *firstCriteria, allowFullScan bool) (int64, error) {
+	if len(c.sort) > 0 || len(c.sortExpr) > 0 || c.Offset > 0 || c.Limit > 0 || c.lock != pqt.LockNone {
+		return 0, errors.New("first delete failure, sort, offset, limit and lock are not supported")
+	}
+
+	where, args, err := r.plan(c)
+	if err != nil {
+		return 0, err
+	}
+	buf := bytes.NewBufferString("DELETE FROM ")
+	buf.WriteString(r.table)
+	if where != "" {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(where)
+	} else if !allowFullScan {
+		return 0, pqt.ErrDeleteWithoutCriteria
+	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "DeleteByCriteria"); err != nil {
+			return 0, err
 		}
+	}
 
-		err := r.db.QueryRow(b.String(), insert.Args()...).Scan(
-	&e.id,
-&e.name,
-)
-		if err != nil {
-			return nil, err
-		}
+	res, err := r.db.Exec(buf.String(), args...)
+	if err != nil {
+		return 0, err
+	}
 
-		return e, nil
+	return res.RowsAffected()
+}
+// truncate removes all rows from the table.
+// If cascade is true, tables that have foreign key references to the table are truncated as well.
+// If restartIdentity is true, sequences owned by columns of the table are restarted.
+func (r *firstRepositoryBase) truncate(cascade, restartIdentity bool) error {
+	buf := bytes.NewBufferString("TRUNCATE ")
+	buf.WriteString(r.table)
+	if restartIdentity {
+		buf.WriteString(" RESTART IDENTITY")
+	}
+	if cascade {
+		buf.WriteString(" CASCADE")
 	}
+
+	if r.dbg {
+		if err := r.log.Log("msg", buf.String(), "function", "Truncate"); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.db.Exec(buf.String())
+	return err
+}
 `,
 		},
 	}
@@ -485,6 +1399,1277 @@ update.AddExpr(tableFirstColumnName, "=", p.name)
 	}
 }
 
+func TestGenerator_Generate_features(t *testing.T) {
+	newsSchema := func(opts ...pqt.TableOption) *pqt.Schema {
+		return pqt.NewSchema("text").AddTable(
+			pqt.NewTable("news", opts...).
+				AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+				AddColumn(pqt.NewColumn("title", pqt.TypeText(), pqt.WithNotNull())),
+		)
+	}
+	personSchema := func(opts ...pqt.TableOption) *pqt.Schema {
+		return pqt.NewSchema("text").AddTable(
+			pqt.NewTable("person", opts...).
+				AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())),
+		)
+	}
+	firstSchema := func(name *pqt.Column) *pqt.Schema {
+		return pqt.NewSchema("text").AddTable(
+			pqt.NewTable("first").
+				AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+				AddColumn(name),
+		)
+	}
+	joinsSchema := func() *pqt.Schema {
+		user := pqt.NewTable("user").
+			AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+			AddColumn(pqt.NewColumn("name", pqt.TypeText(), pqt.WithNotNull()))
+		comment := pqt.NewTable("comment").
+			AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+			AddRelationship(pqt.ManyToOne(user, pqt.WithInversedName("author")))
+		return pqt.NewSchema("text").AddTable(user).AddTable(comment)
+	}
+	hooksSchema := func() *pqt.Schema {
+		return newsSchema().AddTable(
+			pqt.NewTable("log").
+				AddColumn(pqt.NewColumn("message", pqt.TypeText(), pqt.WithNotNull())),
+		)
+	}
+	aggregateSchema := func() *pqt.Schema {
+		return pqt.NewSchema("text").AddTable(
+			pqt.NewTable("order").
+				AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+				AddColumn(pqt.NewColumn("status", pqt.TypeText(), pqt.WithNotNull())).
+				AddColumn(pqt.NewColumn("amount", pqt.TypeNumeric(12, 2))).
+				AddColumn(pqt.NewColumn("placed_at", pqt.TypeTimestampTZ())).
+				AddColumn(pqt.NewColumn("quantity", pqt.TypeDomain("text.quantity", pqt.TypeInteger(), "VALUE > 0"))).
+				AddColumn(pqt.NewColumn("tags", pqt.TypeIntegerArray(0))),
+		)
+	}
+	ddlSchema := func() *pqt.Schema {
+		return pqt.NewSchema("text").AddTable(
+			pqt.NewTable("news").
+				AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+				AddColumn(pqt.NewColumn("title", pqt.TypeText(), pqt.WithNotNull(), pqt.WithColumnComment("it's a `title`"))),
+		)
+	}
+	ddl, err := pqtsql.NewGenerator().GenerateTable(ddlSchema().Tables[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	prepared := []string{
+		"stmts *pqtgo.StatementCache\n",
+		"rt.stmts = nil",
+		"func (r *personRepositoryBase) querier() pqtgo.Querier {",
+		"func (r *personRepositoryBase) close() error {",
+		"err = r.querier().QueryRow(query, args...).Scan(",
+		"res, err := r.querier().Exec(query, args...)",
+		"db = r.querier()",
+	}
+
+	cases := map[string]struct {
+		schema    *pqt.Schema
+		generator *pqtgo.Generator
+		contains  []string
+		excludes  []string
+		// assert, if set, runs additional checks against output without tabs.
+		assert func(t *testing.T, out string)
+	}{
+		"context": {
+			schema:    firstSchema(pqt.NewColumn("name", pqt.TypeText())),
+			generator: pqtgo.NewGenerator().SetContext(true),
+			contains: []string{
+				"func (r *firstRepositoryBase) countContext(ctx context.Context, c *firstCriteria) (int64, error) {",
+				"func (r *firstRepositoryBase) count(c *firstCriteria) (int64, error) {\n\tctx, cancel := r.defaultContext()\n\tdefer cancel()\n\n\treturn r.countContext(ctx, c)\n}",
+				"func (r *firstRepositoryBase) findIterContext(ctx context.Context, c *firstCriteria) (*firstIterator, error) {",
+				"func (r *firstRepositoryBase) insertContext(ctx context.Context, e *firstEntity) (*firstEntity, error) {",
+				"func (r *firstRepositoryBase) upsertOn(e *firstEntity, p *firstPatch, ct pqt.UpsertConflictTarget) (*firstEntity, error) {\n\tctx, cancel := r.defaultContext()\n\tdefer cancel()\n\n\treturn r.upsertOnContext(ctx, e, p, ct)\n}",
+				"func (r *firstRepositoryBase) upsertContext(ctx context.Context, e *firstEntity, p *firstPatch, inf ...string) (*firstEntity, error) {\n\treturn r.upsertOnContext(ctx, e, p, pqt.ConflictOnColumns(inf...))\n}",
+				"func (r *firstRepositoryBase) upsert(e *firstEntity, p *firstPatch, inf ...string) (*firstEntity, error) {\n\tctx, cancel := r.defaultContext()\n\tdefer cancel()\n\n\treturn r.upsertContext(ctx, e, p, inf...)\n}",
+				"func (r *firstRepositoryBase) deleteOneById(id int64) (int64, error) {\n\tctx, cancel := r.defaultContext()\n\tdefer cancel()\n\n\treturn r.deleteOneByIdContext(ctx, id)\n}",
+				"func (r *firstRepositoryBase) findIter(c *firstCriteria) (*firstIterator, error) {\n\tctx, cancel := r.defaultContext()\n\tit, err := r.findIterContext(ctx, c)\n\tif err != nil {\n\t\tcancel()\n\t\treturn nil, err\n\t}\n\tit.cancel = cancel\n\n\treturn it, nil\n}",
+				"func (i *firstIterator) Close() error {\n\tif i.cancel != nil {\n\t\tdefer i.cancel()\n\t}\n\treturn i.rows.Close()\n}",
+				"timeout time.Duration\n",
+				"if r.timeout > 0 {\n\t\treturn context.WithTimeout(context.Background(), r.timeout)\n\t}",
+				"rows, err := r.db.QueryContext(ctx, buf.String(), com.Args()...)",
+				"res, err := r.db.ExecContext(ctx, query, args...)",
+			},
+			excludes: []string{
+				// Context aware methods should not call context free database methods.
+				"r.db.Query(",
+			},
+		},
+		"driver-pgx": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("first", pqt.WithSoftDelete()).
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("name", pqt.TypeText())),
+			),
+			generator: pqtgo.NewGenerator().SetContext(true).SetDriver(pqtgo.DriverPgx),
+			contains: []string{
+				`"github.com/jackc/pgx/v5"`,
+				`"github.com/jackc/pgx/v5/pgconn"`,
+				"type pgxQuerier interface {",
+				"db pgxQuerier\n",
+				"rows pgx.Rows\n",
+				"func (r *firstRepositoryBase) withTx(tx pgx.Tx) *firstRepositoryBase {",
+				"func scanFirstRows(rows pgx.Rows) ([]*firstEntity, error) {",
+				"func scanFirstRow(row pgx.Row) (*firstEntity, error) {",
+				"for _, fd := range i.rows.FieldDescriptions() {",
+				"rows, err := r.db.Query(ctx, buf.String(), com.Args()...)",
+				"affected, err := res.RowsAffected(), nil",
+				"return pgx.ErrNoRows",
+				"if err == pgx.ErrNoRows {",
+				`return r.db.CopyFrom(ctx, pgx.Identifier{"text", "first"}, columns, pgx.CopyFromSlice(len(es), func(i int) ([]interface{}, error) {`,
+				"func (r *firstRepositoryBase) count(c *firstCriteria) (int64, error) {",
+			},
+			excludes: []string{"sql.ErrNoRows", "*sql.", "QueryContext(", "QueryRowContext(", "ExecContext(", "pqtgo.Querier", "pq.CopyIn"},
+		},
+		"generated-column": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("person").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("first_name", pqt.TypeText(), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("full_name", pqt.TypeText(), pqt.WithGenerated("first_name || '!'", pqt.GeneratedStored))),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains:  []string{"fullName *ntypes.String"},
+			// Generated column is read, but never written.
+			excludes: []string{"AddExpr(tablePersonColumnFullName"},
+		},
+		"array-column": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("post").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("tags", pqt.TypeArray(pqt.TypeText()))).
+					AddColumn(pqt.NewColumn("scores", pqt.TypeArray(pqt.TypeIntegerBig()))),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"tags pqt.ArrayString",
+				"scores pqt.ArrayInt64",
+				"pqtgo.WriteCompositionQueryStringArray",
+				"pqtgo.WriteCompositionQueryInt64Array",
+			},
+		},
+		"returning": {
+			schema: func() *pqt.Schema {
+				id := pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())
+				updatedAt := pqt.NewColumn("updated_at", pqt.TypeTimestampTZ())
+				return pqt.NewSchema("text").AddTable(
+					pqt.NewTable("person", pqt.WithReturning(id, updatedAt)).
+						AddColumn(id).
+						AddColumn(pqt.NewColumn("name", pqt.TypeText(), pqt.WithNotNull())).
+						AddColumn(updatedAt),
+				)
+			}(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"type personReturning struct {\nid int64\nupdatedAt *time.Time\n}",
+				"func (r *personRepositoryBase) insertReturning(e *personEntity) (*personReturning, error) {",
+				"func (r *personRepositoryBase) updateOneByIdReturning(id int64, patch *personPatch) (*personReturning, error) {",
+				`b.WriteString(" RETURNING " + tablePersonColumnId + ", " + tablePersonColumnUpdatedAt)`,
+				"query, args, err := r.updateOneByIdQuery(id, patch, []string{tablePersonColumnId, tablePersonColumnUpdatedAt})",
+			},
+		},
+		"returning-columns": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("person").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("name", pqt.TypeText(), pqt.WithNotNull())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"func (r *personRepositoryBase) insertReturningColumns(e *personEntity, cols ...string) (*personEntity, error) {",
+				"func (r *personRepositoryBase) updateOneByIdReturningColumns(id int64, patch *personPatch, cols ...string) (*personEntity, error) {",
+				"props, err := ent.props(cols...)",
+				`b.WriteString(" RETURNING " + strings.Join(cols, ", "))`,
+				"query, args, err := r.updateOneByIdQuery(id, patch, cols)",
+			},
+		},
+		"custom-json": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("document").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("metadata", pqt.TypeJSONB(), pqt.WithTypeMapping(pqtgo.TypeCustomJSON(jsonMeta{}, jsonMeta{}, nil)))),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				`"github.com/piotrkowalczuk/pqt/pqtgo_test"`,
+				"metadata *pqtgo_test.jsonMeta",
+				"pqtgo.JSON(&ent.metadata),",
+				`insert.AddExpr(tableDocumentColumnMetadata, "", pqtgo.JSON(e.metadata))`,
+				"if patch.metadata != nil {",
+				"update.AddExpr(tableDocumentColumnMetadata, pqcomp.Equal, pqtgo.JSON(patch.metadata))",
+			},
+		},
+		"jsonb": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("document").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("metadata", pqtgo.TypeJSONB(jsonMeta{}))).
+					AddColumn(pqt.NewColumn("published", pqt.TypeBool())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"metadata *pqtgo_test.jsonMeta",
+				"if c.metadata != nil {",
+				`com.WriteString(" @> ")`,
+				"com.Add(pqtgo.JSON(c.metadata))",
+			},
+		},
+		"insert-batch-default": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("token", pqt.WithUUIDPrimaryKey()).
+					AddColumn(pqt.NewColumn("name", pqt.TypeText(), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("created_at", pqt.TypeTimestampTZ(), pqt.WithNotNull(), pqt.WithDefault("NOW()"))),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				// Entities are validated before the first chunk is sent.
+				"if err := e.validate(); err != nil {\nreturn nil, err\n}\n}\nfor _, chunk := range pqtgo.Chunks(len(es), 3) {",
+				"if e.id != (uuid.UUID{}) {\ncom.WritePlaceholder()\ncom.Add(e.id)\n} else {\ncom.WriteString(\"DEFAULT\")\n}",
+				"com.WritePlaceholder()\ncom.Add(e.name)\n",
+				"if !e.createdAt.IsZero() {\ncom.WritePlaceholder()\ncom.Add(e.createdAt)\n} else {\ncom.WriteString(\"DEFAULT\")\n}",
+				"b.WriteString(tableTokenColumnCreatedAt)\nb.WriteString(\", \")\nb.WriteString(tableTokenColumnId)\nb.WriteString(\", \")\nb.WriteString(tableTokenColumnName)",
+				`return nil, fmt.Errorf("token insert batch failure, %d rows inserted, but %d returned", len(batch), returned)`,
+				// COPY cannot fall back to default per row, so such columns are omitted.
+				`query := pq.CopyInSchema("text", "token", tableTokenColumnName)`,
+			},
+		},
+		"bulk-insert": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("person").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("name", pqt.TypeText(), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("created_at", pqt.TypeTimestampTZ(), pqt.WithNotNull(), pqt.WithDefault("NOW()"))),
+			),
+			generator: pqtgo.NewGenerator().SetContext(true),
+			contains: []string{
+				"// Database of the repository has to implement pqtgo.TxBeginner, like *sql.DB, or pqtgo.Preparer within a transaction, like *sql.Tx.\nfunc (r *personRepositoryBase) bulkInsertContext(ctx context.Context, es []*personEntity) (int64, error) {",
+				"if err := e.validate(); err != nil {\nreturn 0, err\n}\n}\nquery := pq.CopyInSchema(\"text\", \"person\", tablePersonColumnName)",
+				"return pqtgo.CopyIn(ctx, r.db, query, len(es), r.bulkSize, func(i int) []interface{} {",
+				"es[i].name,\n}",
+			},
+		},
+		"numeric": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("product").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("price", pqtgo.TypeNumeric(12, 2, decimal{}, nullDecimal{}, nullDecimal{}), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("discount", pqtgo.TypeNumeric(12, 2, decimal{}, nullDecimal{}, nullDecimal{}))),
+			),
+			// Package of numeric types is not imported automatically.
+			generator: pqtgo.NewGenerator().AddImport("github.com/piotrkowalczuk/pqt/pqtgo_test"),
+			contains: []string{
+				"price *pqtgo_test.decimal\n",
+				"discount *pqtgo_test.nullDecimal\n",
+				"&ent.price,\n",
+			},
+			// Numeric column should not be mapped to float64.
+			excludes: []string{"float64"},
+		},
+		"enum": {
+			schema: func() *pqt.Schema {
+				status := pqt.TypeEnumerated("text.user_status", "active", "can't login")
+				return pqt.NewSchema("text").AddTable(
+					pqt.NewTable("user").
+						AddColumn(pqt.NewColumn("status", status, pqt.WithNotNull())).
+						AddColumn(pqt.NewColumn("previous_status", status)),
+				)
+			}(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"type userStatus string",
+				`userStatusActive userStatus = "active"`,
+				`userStatusCanTLogin userStatus = "can't login"`,
+				"func (e userStatus) Value() (driver.Value, error) {",
+				"func (e *userStatus) Scan(src interface{}) error {",
+				"func (e userStatus) valid() bool {",
+				"case userStatusActive, userStatusCanTLogin:\n\t\treturn true",
+				"status userStatus\n",
+				"previousStatus *userStatus\n",
+			},
+			assert: func(t *testing.T, out string) {
+				if strings.Count(out, "type userStatus string") != 1 {
+					t.Error("enum type should be generated once")
+				}
+			},
+		},
+		"domain": {
+			schema: func() *pqt.Schema {
+				email := pqt.TypeDomain("text.email", pqt.TypeText(), "VALUE ~ '@'")
+				return pqt.NewSchema("text").AddTable(
+					pqt.NewTable("user").
+						AddColumn(pqt.NewColumn("login", email, pqt.WithNotNull())).
+						AddColumn(pqt.NewColumn("backup", email)).
+						AddColumn(pqt.NewColumn("born_at", pqt.TypeDomain("text.birthday", pqt.TypeTimestampTZ(), ""))),
+				)
+			}(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"// email represents value of text.email domain, it has to satisfy CHECK (VALUE ~ '@').\ntype email string\n",
+				"login email\n",
+				"backup *email\n",
+				"login *qtypes.String\n",
+				"bornAt *time.Time\n",
+			},
+			// Domain based on type that is not basic should not get named type.
+			excludes: []string{"type birthday"},
+			assert: func(t *testing.T, out string) {
+				if strings.Count(out, "type email string") != 1 {
+					t.Error("domain type should be generated once")
+				}
+			},
+		},
+		"comment": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("user", pqt.WithTableComment("Registered user.")).
+					AddColumn(pqt.NewColumn("name", pqt.TypeText(), pqt.WithComment("Display name,\nnot unique."))).
+					AddColumn(pqt.NewColumn("age", pqt.TypeInteger())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"// Registered user.\ntype userEntity struct{\n",
+				"// Display name,\n// not unique.\nname *ntypes.String\n",
+				"// age ...\nage *ntypes.Int32\n",
+			},
+		},
+		"conditional-unique": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("post").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("slug", pqt.TypeText(), pqt.WithNotNull(), pqt.WithConditionalUnique("deleted_at IS NULL"))).
+					AddColumn(pqt.NewColumn("deleted_at", pqt.TypeTimestampTZ())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains:  []string{`tablePostConstraintSlugUnique = "text.post_slug_key"`},
+			excludes:  []string{"findOneBySlug("},
+		},
+		"named-check": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("product", pqt.WithTableCheck("positive_price", "price > 0")).
+					AddColumn(pqt.NewColumn("price", pqt.TypeDecimal(10, 2), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("stock", pqt.TypeInteger(), pqt.WithNamedCheck("non_negative_stock", "stock >= 0"))),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				`tableProductConstraintPositivePriceCheck = "positive_price"`,
+				`tableProductConstraintNonNegativeStockCheck = "non_negative_stock"`,
+			},
+		},
+		"find-one-by-unique-constraint": {
+			schema: func() *pqt.Schema {
+				title := pqt.NewColumn("title", pqt.TypeText(), pqt.WithNotNull(), pqt.WithUnique())
+				lead := pqt.NewColumn("lead", pqt.TypeText())
+				return pqt.NewSchema("text").AddTable(
+					pqt.NewTable("news", pqt.WithUniqueConstraint("", title, lead)).
+						AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+						AddColumn(title).
+						AddColumn(lead),
+				)
+			}(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"// findOneByTitle retrieves single entity using text.news_title_key unique constraint, sql.ErrNoRows is returned if it does not exist.",
+				"func (r *newsRepositoryBase) findOneByTitle(title string) (*newsEntity, error) {",
+				"FROM text.news WHERE title = $1`",
+				"func (r *newsRepositoryBase) findOneByTitleAndLead(title string, lead string) (*newsEntity, error) {",
+				"FROM text.news WHERE title = $1 AND lead = $2`",
+			},
+		},
+		"field-tags": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("news").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("title", pqt.TypeText(), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("lead", pqt.TypeText())),
+			),
+			generator: pqtgo.NewGenerator().SetVisibility(pqtgo.Public).SetFieldTags("json", "db"),
+			contains: []string{
+				"Id int64 `json:\"id\" db:\"id\"`",
+				"Title string `json:\"title\" db:\"title\"`",
+				"Lead *ntypes.String `json:\"lead,omitempty\" db:\"lead\"`",
+			},
+		},
+		"explain": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator().SetContext(true),
+			contains: []string{
+				"explain pqt.ExplainHook",
+				"func (r *newsRepositoryBase) explainQuery(ctx context.Context, query string, args []interface{}) error {",
+				`r.db.QueryRowContext(ctx, "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+query, args...).Scan(&plan)`,
+				"r.explain(json.RawMessage(plan), query, args)",
+				"if r.explain != nil {\n\t\t\tif err := r.explainQuery(ctx, buf.String(), com.Args()); err != nil {\n\t\t\t\treturn nil, err",
+				"if r.explain != nil {\n\t\t\tif err := r.explainQuery(ctx, query, args); err != nil {\n\t\t\t\treturn 0, err",
+			},
+		},
+		"explain-public": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator().SetContext(true).SetVisibility(pqtgo.Public),
+			contains: []string{
+				"Explain pqt.ExplainHook",
+				"if r.Explain != nil {",
+			},
+		},
+		"update-or-insert-by-unique-constraint": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("news", pqt.WithVersionColumn("version")).
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("title", pqt.TypeText(), pqt.WithNotNull(), pqt.WithUnique())).
+					AddColumn(pqt.NewColumn("lead", pqt.TypeText())).
+					AddColumn(pqt.NewColumn("slug", pqt.TypeText(), pqt.WithConditionalUnique("slug <> ''"))),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"func (r *newsRepositoryBase) updateOrInsertByTitle(e *newsEntity) (*newsEntity, bool, error) {",
+				"case tableNewsColumnTitle, tableNewsColumnId, tableNewsColumnVersion:",
+				`excluded = append(excluded, "title = EXCLUDED.title")`,
+				`b.WriteString(" ON CONFLICT (title) DO UPDATE SET ")`,
+				`b.WriteString(", version = news.version + 1")`,
+				`b.WriteString(", (xmax = 0)")`,
+				"&created,\n)",
+				"return e, created, nil",
+			},
+			// Conditional unique constraint should not get update or insert method.
+			excludes: []string{"updateOrInsertBySlug"},
+		},
+		"cache": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator().SetCache(true).SetInterfaces(true),
+			contains: []string{
+				"type newsCacheEntry struct {\nId int64 `json:\"id\"`\nTitle string `json:\"title\"`\n}",
+				"func newCachedNewsRepository(base *newsRepositoryBase, cache pqt.Cache, ttl time.Duration) *cachedNewsRepository {",
+				`key, ok := r.cacheKey("findOneById", id)`,
+				`key, ok := r.cacheKey("count", where, args)`,
+				"func (r *cachedNewsRepository) insert(e *newsEntity) (*newsEntity, error) {\ndefer r.invalidate()",
+				"var _ newsRepository = &cachedNewsRepository{}",
+			},
+			// Find is not cached.
+			excludes: []string{"func (r *cachedNewsRepository) find("},
+		},
+		"cache-context": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator().SetCache(true).SetContext(true),
+			contains: []string{
+				"r := &cachedNewsRepository{newsRepositoryBase: base, cache: cache, ttl: ttl}\nif _, ok := cache.Get(r.table + \":version\"); !ok {\nr.invalidate()\n}",
+				"version, ok := r.cache.Get(r.table + \":version\")\nif !ok {\nr.invalidate()\nreturn \"\", false\n}",
+				"func (r *cachedNewsRepository) insert(e *newsEntity) (*newsEntity, error) {\nctx, cancel := r.defaultContext()\ndefer cancel()\n\nreturn r.insertContext(ctx, e)\n}",
+				"func (r *cachedNewsRepository) findOneById(id int64) (*newsEntity, error) {\nctx, cancel := r.defaultContext()\ndefer cancel()\n\nreturn r.findOneByIdContext(ctx, id)\n}",
+			},
+			// Context free method of cached repository should not bypass repository timeout.
+			excludes: []string{"func (r *cachedNewsRepository) insert(e *newsEntity) (*newsEntity, error) {\nreturn r.insertContext(context.Background(), e)"},
+		},
+		"hooks": {
+			schema:    hooksSchema(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"type newsBeforeInsertHook func(ctx context.Context, e *newsEntity) error",
+				"type newsAfterInsertHook func(ctx context.Context, e *newsEntity) error",
+				"type newsBeforeUpdateHook func(ctx context.Context, id int64, patch *newsPatch) error",
+				"type newsAfterUpdateHook func(ctx context.Context, e *newsEntity) error",
+				"type newsBeforeDeleteHook func(ctx context.Context, id int64) error",
+				"type newsAfterDeleteHook func(ctx context.Context, id int64) error",
+				"beforeInsert newsBeforeInsertHook\nafterInsert newsAfterInsertHook\n",
+				"func (r *newsRepositoryBase) insert(e *newsEntity) (*newsEntity, error) {\nif r.beforeInsert != nil {\nif err := r.beforeInsert(context.Background(), e); err != nil {\nreturn nil, err",
+				"if r.afterInsert != nil {\nif err := r.afterInsert(context.Background(), e); err != nil {",
+				"if err := r.beforeUpdate(context.Background(), id, patch); err != nil {",
+				"if err := r.afterUpdate(context.Background(), &e); err != nil {",
+				"if err := r.beforeDelete(context.Background(), id); err != nil {\nreturn 0, err",
+				"if err != nil || affected == 0 {\nreturn affected, err\n}\nif r.afterDelete != nil {\nif err := r.afterDelete(context.Background(), id); err != nil {\nreturn affected, err",
+				"type logBeforeInsertHook func(ctx context.Context, e *logEntity) error",
+			},
+			// Update hooks are not generated for table without primary key.
+			excludes: []string{"logBeforeUpdateHook"},
+		},
+		"hooks-context": {
+			schema:    hooksSchema(),
+			generator: pqtgo.NewGenerator().SetContext(true),
+			// Hook gets context of the call.
+			contains: []string{"r.beforeInsert(ctx, e)"},
+		},
+		"hooks-coverage": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("news", pqt.WithSoftDelete()).
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("slug", pqt.TypeText(), pqt.WithNotNull(), pqt.WithUnique())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"insertReturningColumns(e *newsEntity, cols ...string) (*newsEntity, error) {\nif r.beforeInsert != nil {",
+				"insertBatch(es []*newsEntity) ([]*newsEntity, error) {\nfor _, e := range es {\nif r.beforeInsert != nil {",
+				"bulkInsert(es []*newsEntity) (int64, error) {\nfor _, e := range es {\nif r.beforeInsert != nil {",
+				"updateOneByIdReturningColumns(id int64, patch *newsPatch, cols ...string) (*newsEntity, error) {\nif r.beforeUpdate != nil {",
+				"patchOneById(id int64, patch *newsPatch) (int64, error) {\nif r.beforeUpdate != nil {",
+				"updateOrInsertBySlug(e *newsEntity) (*newsEntity, bool, error) {\nif r.beforeInsert != nil {",
+				"softDeleteOneById(id int64) error {\nif r.beforeDelete != nil {",
+				"// newsAfterInsertHook is called by insert, insertBatch and updateOrInsertBySlug",
+				"// It is not called by insertReturningColumns and bulkInsert, they do not read back the whole entity.",
+				"// It is not called by upsertOn and upsert either, they cannot tell inserted row from updated one.",
+				"// newsAfterUpdateHook is called by updateOneById, updateOneBySlug and updateOrInsertBySlug",
+				"// newsAfterDeleteHook is called by hardDeleteOneById, hardDeleteAndReturnOneById and softDeleteOneById",
+				"// It is not called by deleteByCriteria.",
+			},
+		},
+		"aggregate": {
+			schema:    aggregateSchema(),
+			generator: pqtgo.NewGenerator().SetInterfaces(true).SetCache(true),
+			contains: []string{
+				"func (r *orderRepositoryBase) aggregate(c *orderCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error) {",
+				`return nil, fmt.Errorf("order aggregate failure, unknown function: %d", fn)`,
+				"for _, tc := range tableOrderColumns {",
+				`return nil, fmt.Errorf("order aggregate failure, unknown column: %s", cn)`,
+				`buf.WriteString(" GROUP BY " + groupBy + " ORDER BY " + groupBy)`,
+				"numeric := fn == pqt.AggregateCount\nswitch column {\ncase tableOrderColumnAmount, tableOrderColumnId, tableOrderColumnQuantity:\nnumeric = true\n}\n",
+				`return nil, fmt.Errorf("order aggregate failure, %s requires numeric column, got: %s", fn, column)`,
+				"if !numeric {\ndest[0] = &raw\n}",
+				"res = append(res, pqt.AggregateResult{Group: group, Value: value.Float64, Raw: raw})",
+				"aggregate(c *orderCriteria, fn pqt.Aggregate, column, groupBy string) ([]pqt.AggregateResult, error)\n",
+			},
+			// Aggregate does not invalidate cache.
+			excludes: []string{"func (r *cachedOrderRepository) aggregate("},
+		},
+		"aggregate-public": {
+			schema:    aggregateSchema(),
+			generator: pqtgo.NewGenerator().SetVisibility(pqtgo.Public),
+			// Aggregate validates columns against exported list of columns.
+			contains: []string{"for _, tc := range TableOrderColumns {"},
+		},
+		"delete-and-return": {
+			schema: newsSchema().AddTable(
+				pqt.NewTable("comment", pqt.WithSoftDelete()).
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"func (r *newsRepositoryBase) deleteAndReturnOneById(id int64) (*newsEntity, error) {",
+				"query, args, err := r.deleteOneByIdQuery(id)",
+				`query += " RETURNING " + strings.Join(r.columns, ", ")`,
+				"&e.title,\n)\nif err == sql.ErrNoRows {\nreturn nil, pqt.ErrNotFound\n}",
+				"func (r *commentRepositoryBase) hardDeleteAndReturnOneById(id int64) (*commentEntity, error) {",
+			},
+		},
+		"find-and-count": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator().SetContext(true).SetInterfaces(true),
+			contains: []string{
+				"func (r *newsRepositoryBase) findAndCountContext(ctx context.Context, c *newsCriteria) ([]*newsEntity, int64, error) {",
+				`rr.columns = append(r.columns[:len(r.columns):len(r.columns)], "COUNT(*) OVER()")`,
+				"query, args, err := rr.findQuery(c)",
+				"&ent.title,\n&total,\n)",
+				"if len(entities) == 0 && c.Offset > 0 {\nif total, err = r.countContext(ctx, c); err != nil {",
+				"func (r *newsRepositoryBase) findAndCount(c *newsCriteria) ([]*newsEntity, int64, error) {",
+				"findAndCount(c *newsCriteria) ([]*newsEntity, int64, error)\n",
+				"return append([]*newsEntity(nil), m.entities...), int64(len(m.entities)), nil",
+			},
+		},
+		"durability": {
+			schema: pqt.NewSchema("text").
+				AddTable(pqt.NewTable("event", pqt.WithUnlogged()).AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey()))).
+				AddTable(pqt.NewTable("scratch", pqt.WithTemp()).AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey()))),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"// Table is unlogged, rows are not written to the write-ahead log, are not replicated and are lost after a crash.\ntype eventEntity struct{",
+				"// Table is temporary, rows are visible only within the database session that created them and dropped when it ends.\ntype scratchEntity struct{",
+			},
+		},
+		"ddl": {
+			schema:    ddlSchema(),
+			generator: pqtgo.NewGenerator().SetDDL(true),
+			contains: []string{
+				"func tableNewsDDL() string {",
+				"return " + strconv.Quote(string(ddl)),
+			},
+		},
+		"ddl-disabled": {
+			schema:    ddlSchema(),
+			generator: pqtgo.NewGenerator(),
+			excludes:  []string{"DDL() string"},
+		},
+		"query-builders": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator().SetContext(true),
+			contains: []string{
+				"func (r *newsRepositoryBase) insertQuery(e *newsEntity) (string, []interface{}, error) {",
+				"return b.String(), insert.Args(), nil",
+				"query, args, err := r.insertQuery(e)",
+				"func (r *newsRepositoryBase) findQuery(c *newsCriteria) (string, []interface{}, error) {",
+				"query, args, err := r.findQuery(c)",
+				"func (r *newsRepositoryBase) countQuery(c *newsCriteria) (string, []interface{}, error) {",
+				"query, args, err := r.countQuery(c)",
+				"func (r *newsRepositoryBase) updateOneByIdQuery(id int64, patch *newsPatch, returning []string) (string, []interface{}, error) {",
+				"query, args, err := r.updateOneByIdQuery(id, patch, r.columns)",
+				"if len(returning) > 0 {\nquery += \" RETURNING \" + strings.Join(returning, \", \")\n}",
+				"func (r *newsRepositoryBase) findOneByIdQuery(id int64) (string, []interface{}, error) {\nreturn `SELECT id, title FROM text.news WHERE id = $1`, []interface{}{id}, nil",
+				"query, args, err := r.findOneByIdQuery(id)",
+				"func (r *newsRepositoryBase) deleteOneByIdQuery(id int64) (string, []interface{}, error) {\nreturn \"DELETE FROM text.news WHERE id = $1\", []interface{}{id}, nil",
+				"if err := r.log.Log(\"msg\", query, \"function\", \"DeleteOneById\"); err != nil {",
+			},
+		},
+		"table-schema": {
+			schema: pqt.NewSchema("text").
+				AddTable(pqt.NewTable("news").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey()))).
+				AddTable(pqt.NewTable("news", pqt.WithSchema("reporting")).
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey()))).
+				AddTable(pqt.NewTable("order", pqt.WithSchema("Archive")).
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey()))),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				`tableNews = "text.news"`,
+				`tableReportingNews = "reporting.news"`,
+				"tableReportingNewsColumnId = \"id\"",
+				"type reportingNewsEntity struct{",
+				"func (r *reportingNewsRepositoryBase) findOneById(id int64) (*reportingNewsEntity, error) {",
+				`return "DELETE FROM reporting.news WHERE id = $1", []interface{}{id}, nil`,
+				`tableArchiveOrder = "\"Archive\".\"order\""`,
+				`return "DELETE FROM \"Archive\".\"order\" WHERE id = $1", []interface{}{id}, nil`,
+				"FROM \"Archive\".\"order\" WHERE id = $1`",
+			},
+		},
+		"planner": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"planner pqt.Planner\n",
+				"func (r *newsRepositoryBase) plan(c *newsCriteria) (string, []interface{}, error) {\nif r.planner != nil {\nreturn r.planner.Plan(c)\n}\nreturn c.whereClause(1)\n}",
+				"return c.writeSuffix(com)\n}",
+				"func (c *newsCriteria) writeSuffix(com *pqtgo.Composer) (err error) {",
+				"com := pqtgo.NewComposerAt(2, len(args)+1)\nif err := c.writeSuffix(com); err != nil {",
+				"com.WriteString(\"(\" + where + \")\")",
+			},
+			assert: func(t *testing.T, out string) {
+				// Every query built from the criteria goes through the planner.
+				if got := strings.Count(out, "where, args, err := r.plan(c)"); got != 6 {
+					t.Errorf("find, find page, count, exists, aggregate and delete by criteria should use the planner, got %d", got)
+				}
+			},
+		},
+		"identity": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("news").
+					AddColumn(pqt.NewColumn("id", pqt.TypeIntegerBig(), pqt.WithPrimaryKey(), pqt.WithIdentity(pqt.IdentityAlways))).
+					AddColumn(pqt.NewColumn("position", pqt.TypeIntegerBig(), pqt.WithNotNull(), pqt.WithIdentity(pqt.IdentityByDefault))).
+					AddColumn(pqt.NewColumn("title", pqt.TypeText(), pqt.WithNotNull())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"overriding := false",
+				"if e.id != 0 {\ninsert.AddExpr(tableNewsColumnId, \"\", e.id)\noverriding = true\n}",
+				"if e.position != 0 {\ninsert.AddExpr(tableNewsColumnPosition, \"\", e.position)\n}",
+				"b.WriteString(\")\")\nif overriding {\nb.WriteString(\" OVERRIDING SYSTEM VALUE\")\n}\nb.WriteString(\" VALUES (\")",
+				"type newsPatch struct {\nposition *ntypes.Int64\ntitle *ntypes.String\n}",
+			},
+			// Batch insert omits identity columns.
+			excludes: []string{"es[i].id", "es[i].position"},
+		},
+		"scan-row": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("news").
+					AddColumn(pqt.NewColumn("title", pqt.TypeText(), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"tableNewsColumns = []string{\ntableNewsColumnId,\ntableNewsColumnTitle,\n",
+				"// scanNewsRows reads all rows into entities, each row has to consist of columns listed in tableNewsColumns, in the same order.\nfunc scanNewsRows(rows *sql.Rows) ([]*newsEntity, error) {",
+				"// scanNewsRow reads single row into entity, the row has to consist of columns listed in tableNewsColumns, in the same order.",
+				"func scanNewsRow(row *sql.Row) (*newsEntity, error) {\nvar ent newsEntity\nerr := row.Scan(\n&ent.id,\n&ent.title,\n)",
+			},
+		},
+		"between": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("news").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("created_at", pqt.TypeTimestampTZ(), pqt.WithNotNull())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"createdAt *qtypes.Timestamp\ncreatedAtBounds pqt.RangeBounds\n",
+				// Each bound is guarded separately, so range with only one of them set emits single comparison.
+				"if len(c.createdAt.Values) > 0 && c.createdAt.Values[0] != nil {",
+				"com.WriteString(\" \" + c.createdAtBounds.LowerOperator() + \" \")\ncom.WritePlaceholder()\ncom.Add(createdAt1)",
+				"if len(c.createdAt.Values) > 1 && c.createdAt.Values[1] != nil {",
+				"com.WriteString(\" \" + c.createdAtBounds.UpperOperator() + \" \")\ncom.WritePlaceholder()\ncom.Add(createdAt2)",
+			},
+		},
+		"materialized-view": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewMaterializedView("news_stats", "SELECT title, count(*) AS total FROM text.news GROUP BY title",
+					pqt.NewColumn("title", pqt.TypeText(), pqt.WithNotNull()),
+					pqt.NewColumn("total", pqt.TypeIntegerBig(), pqt.WithNotNull()),
+				),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"func (r *newsStatsRepositoryBase) find(c *newsStatsCriteria) ([]*newsStatsEntity, error) {",
+				"func (r *newsStatsRepositoryBase) findIter(c *newsStatsCriteria) (*newsStatsIterator, error) {",
+				"func (r *newsStatsRepositoryBase) count(c *newsStatsCriteria) (int64, error) {",
+				"func (r *newsStatsRepositoryBase) refresh(concurrent bool) error {",
+				`query += "CONCURRENTLY "`,
+			},
+			excludes: []string{
+				"func (r *newsStatsRepositoryBase) insert(",
+				"func (r *newsStatsRepositoryBase) upsert(",
+				"func (r *newsStatsRepositoryBase) deleteByCriteria(",
+				"type newsStatsPatch struct",
+			},
+		},
+		"exists": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("news", pqt.WithSoftDelete()).
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"func (r *newsRepositoryBase) exists(c *newsCriteria) (bool, error) {",
+				`buf := bytes.NewBufferString("SELECT EXISTS (SELECT 1 FROM ")`,
+				"where, args, err := r.plan(c)",
+				"var exists bool\n\tif err := r.db.QueryRow(buf.String(), args...).Scan(&exists); err != nil {",
+			},
+		},
+		"lock": {
+			schema:    personSchema(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"lock pqt.LockMode\n",
+				"if c.lock != pqt.LockNone {\n\t\tcom.WriteString(\" \")\n\t\tcom.WriteString(c.lock.String())\n\t}",
+				"cc.sort, cc.sortExpr, cc.Offset, cc.Limit, cc.lock = nil, nil, 0, 0, pqt.LockNone",
+				"c.Limit > 0 || c.lock != pqt.LockNone {",
+			},
+			assert: func(t *testing.T, out string) {
+				// Locking clause has to follow ORDER BY, OFFSET and LIMIT, otherwise query is invalid.
+				orderBy, limit, lock := strings.Index(out, `com.WriteString(" ORDER BY ")`), strings.Index(out, `com.WriteString(" LIMIT ")`), strings.Index(out, "com.WriteString(c.lock.String())")
+				if !(orderBy != -1 && orderBy < limit && limit < lock) {
+					t.Errorf("wrong order of clauses, ORDER BY at %d, LIMIT at %d, lock at %d", orderBy, limit, lock)
+				}
+			},
+		},
+		"interfaces-disabled": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator(),
+			excludes:  []string{"type newsRepository interface"},
+		},
+		"interfaces": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator().SetContext(true).SetInterfaces(true),
+			contains: []string{
+				"type newsRepository interface {\ncountContext(ctx context.Context, c *newsCriteria) (int64, error)\ncount(c *newsCriteria) (int64, error)\n",
+				"findOneByIdContext(ctx context.Context, id int64) (*newsEntity, error)\nfindOneById(id int64) (*newsEntity, error)\n",
+				"var _ newsRepository = &newsRepositoryBase{}",
+				"var _ newsRepository = &mockNewsRepository{}",
+				"func newMockNewsRepository() *mockNewsRepository {",
+				"func (m *mockNewsRepository) insertContext(ctx context.Context, e *newsEntity) (*newsEntity, error) {",
+				"m.calls = append(m.calls, \"insert\")\n\tif err := m.errors[\"insert\"]; err != nil {\n\t\treturn nil, err\n\t}\nm.entities = append(m.entities, e)",
+				"if e.id == id {\n\t\t\treturn e, nil",
+				"m.entities = append(m.entities[:i], m.entities[i+1:]...)",
+				`return nil, errors.New("mockNewsRepository: findPage is not supported")`,
+				"return len(m.entities) > 0, nil",
+				"func (m *mockNewsRepository) insert(e *newsEntity) (*newsEntity, error) {\n\treturn m.insertContext(context.Background(), e)\n}",
+			},
+		},
+		"interfaces-public": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator().SetVisibility(pqtgo.Public).SetInterfaces(true),
+			contains: []string{
+				"type NewsRepository interface {\nCount(c *NewsCriteria) (int64, error)\n",
+				"Insert(e *NewsEntity) (*NewsEntity, error)\n",
+				"var _ NewsRepository = &NewsRepositoryBase{}",
+				"var _ NewsRepository = &MockNewsRepository{}",
+			},
+		},
+		"version-column": {
+			schema:    newsSchema(pqt.WithVersionColumn("version")),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				`return "", nil, errors.New("news update failure, version is required")`,
+				"update := pqcomp.New(2, 3)\nupdate.AddArg(id)\nupdate.AddArg(patch.version)\n",
+				`query += ", version = version + 1"`,
+				`query += " WHERE id = $1 AND version = $2"`,
+				"if err == sql.ErrNoRows {\n\treturn nil, pqt.ErrVersionConflict\n}",
+				"if affected == 0 {\n\treturn 0, pqt.ErrVersionConflict\n}",
+				"if !ct.HasColumn(insert.Key()) && insert.Key() != tableNewsColumnVersion {",
+				`b.WriteString(", version = news.version + 1")`,
+				"// Patch has to hold current value of version column, which is incremented, pqt.ErrVersionConflict is returned if it does not match.\nfunc (r *newsRepositoryBase) updateOneById(",
+			},
+			excludes: []string{"AddExpr(tableNewsColumnVersion, \"=\""},
+		},
+		"where-clause": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("news").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"func (c *newsCriteria) whereClause(startIdx int) (string, []interface{}, error) {",
+				"com := pqtgo.NewComposerAt(1, startIdx)",
+				"cc.sort, cc.sortExpr, cc.Offset, cc.Limit, cc.lock = nil, nil, 0, 0, pqt.LockNone",
+			},
+		},
+		"patch": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"func (r *newsRepositoryBase) patchOneById(id int64, patch *newsPatch) (int64, error) {",
+				"query, args, err := r.updateOneByIdQuery(id, patch, nil)",
+				"res, err := r.db.Exec(query, args...)",
+			},
+		},
+		"default": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("token").
+					AddColumn(pqt.NewColumn("id", pqt.TypeUUID(), pqt.WithPrimaryKey(), pqt.WithDefault("gen_random_uuid()"))).
+					AddColumn(pqt.NewColumn("created_at", pqt.TypeTimestampTZ(), pqt.WithNotNull(), pqt.WithDefault("NOW()"))).
+					AddColumn(pqt.NewColumn("name", pqt.TypeText(), pqt.WithNotNull())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"if e.id != (uuid.UUID{}) {",
+				"if !e.createdAt.IsZero() {",
+				`insert.AddExpr(tableTokenColumnName, "", e.name)`,
+			},
+		},
+		"uuid-primary-key": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("token", pqt.WithUUIDPrimaryKey()).
+					AddColumn(pqt.NewColumn("name", pqt.TypeText(), pqt.WithNotNull())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"id uuid.UUID",
+				"if e.id != (uuid.UUID{}) {",
+				`insert.AddExpr(tableTokenColumnId, "", e.id)`,
+				"findOneById(id uuid.UUID) (*tokenEntity, error)",
+			},
+		},
+		"validate": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("user").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("username", pqt.TypeText(), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("active", pqt.TypeBool(), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("created_at", pqt.TypeTimestampTZ(), pqt.WithNotNull(), pqt.WithDefault("NOW()"))).
+					AddColumn(pqt.NewColumn("bio", pqt.TypeText())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"func (e *userEntity) validate() error {",
+				`if e.username == "" {`,
+				`violations = append(violations, pqt.Violation{Column: tableUserColumnUsername, Reason: "is required"})`,
+				"func (p *userPatch) validate() error {",
+				"if p.username != nil && !p.username.Valid {",
+				"return &pqt.ValidationError{Table: tableUser, Violations: violations}",
+				"if err := e.validate(); err != nil {",
+				"if err := patch.validate(); err != nil {",
+			},
+			excludes: []string{
+				"Column: tableUserColumnActive, Reason: \"is required\"",
+				"Column: tableUserColumnCreatedAt, Reason: \"is required\"",
+				"Column: tableUserColumnBio,",
+			},
+		},
+		"prepared-statements": {
+			schema:    personSchema(),
+			generator: pqtgo.NewGenerator().SetPreparedStatements(true),
+			contains:  prepared,
+		},
+		"prepared-statements-disabled": {
+			schema:    personSchema(),
+			generator: pqtgo.NewGenerator(),
+			excludes:  prepared,
+		},
+		"values": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"func (e *newsEntity) value(cn string) (interface{}, bool) {",
+				"case tableNewsColumnTitle:\nreturn e.title, true\n",
+				"func (e *newsEntity) values(cns ...string) ([]interface{}, error) {",
+				"if value, ok := e.value(cn); ok {",
+				`return nil, fmt.Errorf("unexpected column provided: %s", cn)`,
+			},
+		},
+		"to-map": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("news").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("title", pqt.TypeText(), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("lead", pqt.TypeText())).
+					AddColumn(pqt.NewColumn("published_at", pqt.TypeTimestampTZ())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"func (e *newsEntity) toMap() map[string]interface{} {",
+				"m[tableNewsColumnTitle] = e.title\n",
+				"if e.lead != nil && e.lead.Valid {\n\t\tm[tableNewsColumnLead] = e.lead.String\n",
+				"m[tableNewsColumnPublishedAt] = *e.publishedAt\n",
+				"func newsEntityFromMap(m map[string]interface{}) (*newsEntity, error) {",
+				"e.lead = &ntypes.String{String: tv, Valid: true}",
+				"e.publishedAt = &tv",
+				`return nil, fmt.Errorf("news from map failure, column %s expects value of type string, got %T", cn, v)`,
+				`return nil, fmt.Errorf("news from map failure, unexpected column provided: %s", cn)`,
+			},
+		},
+		"count": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"where, args, err := r.plan(c)",
+				"if err := r.db.QueryRow(query, args...).Scan(&count); err != nil {",
+				"func (r *newsRepositoryBase) countDistinct(cn string, c *newsCriteria) (int64, error) {",
+				"cc.countDistinct = cn",
+			},
+		},
+		"count-public": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator().SetVisibility(pqtgo.Public),
+			// Count distinct validates column against exported list of columns.
+			contains: []string{"for _, cn := range TableNewsColumns {"},
+		},
+		"offset-limit": {
+			schema:    newsSchema(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"type newsCriteria struct {\nOffset, Limit int64\n",
+				"if c.Offset > 0 {\nif _, err = com.WriteString(\" OFFSET \"); err != nil {",
+				"com.Add(c.Offset)",
+				"if c.Limit > 0 {\nif _, err = com.WriteString(\" LIMIT \"); err != nil {",
+				"com.Add(c.Limit)",
+				"cc.sort, cc.sortExpr, cc.Offset, cc.Limit, cc.lock = nil, nil, 0, 0, pqt.LockNone",
+			},
+			assert: func(t *testing.T, out string) {
+				countQuery := generatedFunc(t, out, "func (r *newsRepositoryBase) countQuery(")
+				if !strings.Contains(countQuery, "where, args, err := r.plan(c)") {
+					t.Error("count should build its condition using plan, that ignores offset and limit")
+				}
+				for _, unexpected := range []string{"c.Offset", "c.Limit", "OFFSET", "LIMIT"} {
+					if strings.Contains(countQuery, unexpected) {
+						t.Errorf("count should ignore offset and limit, but contains %s", unexpected)
+					}
+				}
+			},
+		},
+		"truncate": {
+			schema:    personSchema(),
+			generator: pqtgo.NewGenerator().SetContext(true),
+			contains: []string{
+				"func (r *personRepositoryBase) truncateContext(ctx context.Context, cascade, restartIdentity bool) error {",
+				`buf.WriteString(" RESTART IDENTITY")`,
+				`buf.WriteString(" CASCADE")`,
+				"_, err := r.db.ExecContext(ctx, buf.String())",
+				"func (r *personRepositoryBase) truncate(cascade, restartIdentity bool) error {",
+			},
+		},
+		"find-page": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("person").
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+					AddColumn(pqt.NewColumn("name", pqt.TypeText())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"type personPage struct {",
+				"func (r *personRepositoryBase) findPage(c *personCriteria, page pqt.CursorPage) (*personPage, error) {",
+				"keys := pqtgo.Keyset(c.sort, tablePersonColumns, tablePersonColumnId)",
+				"from, order = page.Before, pqtgo.ReverseKeyset(keys)",
+				"pqtgo.WriteKeysetCondition(com, order, values)",
+				"com.WriteString(pqtgo.KeysetOrderBy(order))",
+				"com.Add(c.Limit + 1)",
+				"values[k.Name], _ = ent.value(k.Name)",
+			},
+		},
+		"soft-delete": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("post", pqt.WithSoftDelete()).
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"includeDeleted bool\n",
+				"if !c.includeDeleted {",
+				"com.WriteString(tablePostColumnDeletedAt)",
+				"FROM text.post WHERE id = $1 AND deleted_at IS NULL`",
+				"func (r *postRepositoryBase) findIncludingDeleted(c *postCriteria) ([]*postEntity, error) {",
+				"func (r *postRepositoryBase) softDeleteOneById(id int64) error {",
+				`query := "UPDATE text.post SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL"`,
+				"func (r *postRepositoryBase) hardDeleteOneById(id int64) (int64, error) {",
+				"if !allowFullScan {\n\t\tcc := *c\n\t\tcc.includeDeleted = true\n\t\tif where, _, err := r.plan(&cc); err != nil {\n\t\t\treturn 0, err\n\t\t} else if where == \"\" {\n\t\t\treturn 0, pqt.ErrDeleteWithoutCriteria\n\t\t}\n\t}",
+			},
+			excludes: []string{"func (r *postRepositoryBase) deleteOneById("},
+		},
+		"soft-delete-column": {
+			schema: pqt.NewSchema("text").AddTable(
+				pqt.NewTable("post", pqt.WithSoftDelete("removed_at")).
+					AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())),
+			),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"com.WriteString(tablePostColumnRemovedAt)",
+				"FROM text.post WHERE id = $1 AND removed_at IS NULL`",
+				`query := "UPDATE text.post SET removed_at = NOW() WHERE id = $1 AND removed_at IS NULL"`,
+			},
+		},
+		"strict-sort": {
+			schema:    personSchema(),
+			generator: pqtgo.NewGenerator(),
+			// Strict sort is enabled by default.
+			contains: []string{`return fmt.Errorf("pqt: unknown sort column %q", cn)`},
+		},
+		"strict-sort-disabled": {
+			schema:    personSchema(),
+			generator: pqtgo.NewGenerator().SetStrictSort(false),
+			excludes:  []string{"unknown sort column"},
+		},
+		"order-by": {
+			schema:    personSchema(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"sortExpr []pqt.SortExpr",
+				"if keys := pqtgo.Keyset(c.sort, tablePersonColumns); len(keys) > 0 || len(c.sortExpr) > 0 {",
+				"orderBy, err := pqtgo.OrderBy(c.sortExpr, keys, tablePersonColumns)",
+				`return nil, errors.New("person find page failure, sort expressions are not supported")`,
+				"if len(c.sort) > 0 || len(c.sortExpr) > 0 || c.Offset > 0 || c.Limit > 0 || c.lock != pqt.LockNone {",
+			},
+		},
+		"unique-constraint": {
+			schema: func() *pqt.Schema {
+				newsID := pqt.NewColumn("news_id", pqt.TypeIntegerBig(), pqt.WithNotNull())
+				userID := pqt.NewColumn("user_id", pqt.TypeIntegerBig(), pqt.WithNotNull())
+				return pqt.NewSchema("text").AddTable(
+					pqt.NewTable("vote", pqt.WithUniqueConstraint("vote_news_user_key", newsID, userID)).
+						AddColumn(newsID).
+						AddColumn(userID),
+				)
+			}(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				`tableVoteConstraintVoteNewsUserKeyUnique = "vote_news_user_key"`,
+				"func (r *voteRepositoryBase) findOneByNewsIdAndUserId(",
+			},
+		},
+		"with-tx": {
+			schema:    personSchema(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"db pqtgo.Querier\n",
+				"func (r *personRepositoryBase) withTx(tx *sql.Tx) *personRepositoryBase {\n\trt := *r\n\trt.db = tx\n",
+			},
+		},
+		"partition": {
+			schema: func() *pqt.Schema {
+				parent := pqt.NewTable("measurement", pqt.WithPartitionBy(pqt.PartitionStrategyRange, "created_at")).
+					AddColumn(pqt.NewColumn("created_at", pqt.TypeTimestampTZ(), pqt.WithNotNull()))
+				return pqt.NewSchema("text").
+					AddTable(parent).
+					AddTable(pqt.NewPartition(parent, "measurement_2017", pqt.PartitionBoundsRange("'2017-01-01'", "'2018-01-01'")))
+			}(),
+			generator: pqtgo.NewGenerator(),
+			// Partitioned table has repository, its partitions do not.
+			contains: []string{"type measurementRepositoryBase struct"},
+			excludes: []string{"measurement2017"},
+		},
+		"find-one-by-primary-key": {
+			schema:    firstSchema(pqt.NewColumn("name", pqt.TypeText())),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"return `SELECT id, name FROM text.first WHERE id = $1`, []interface{}{id}, nil",
+			},
+			assert: func(t *testing.T, out string) {
+				method := generatedFunc(t, out, "func (r *firstRepositoryBase) findOneById(id int64) (*firstEntity, error) {")
+				if !strings.Contains(method, "query, args, err := r.findOneByIdQuery(id)") {
+					t.Error("find one by primary key method should use its query builder")
+				}
+				if strings.Contains(method, "Criteria") || strings.Contains(method, "Composer") {
+					t.Error("find one by primary key method should not use criteria")
+				}
+			},
+		},
+		"update-one-by-primary-key": {
+			schema:    firstSchema(pqt.NewColumn("name", pqt.TypeText(), pqt.WithNotNull())),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"type firstPatch struct {\nname *ntypes.String\n}",
+				"func (r *firstRepositoryBase) updateOneById(id int64, patch *firstPatch) (*firstEntity, error) {",
+				"update := pqcomp.New(1, 2)\nupdate.AddArg(id)",
+				"update.AddExpr(tableFirstColumnName, pqcomp.Equal, patch.name)",
+				`return "", nil, pqt.ErrNothingToUpdate`,
+				`query += " WHERE id = $1"`,
+				"query, args, err := r.updateOneByIdQuery(id, patch, r.columns)",
+			},
+		},
+		"joins-disabled": {
+			schema:    joinsSchema(),
+			generator: pqtgo.NewGenerator(),
+			excludes:  []string{"findWithAuthor", "findLateral", "lateral []pqt.LateralJoin"},
+		},
+		"joins": {
+			schema:    joinsSchema(),
+			generator: pqtgo.NewGenerator().SetJoins(true),
+			contains: []string{
+				"func (r *commentRepositoryBase) findWithAuthor(c *commentCriteria) ([]*commentEntity, error) {",
+				`buf := bytes.NewBufferString("SELECT t0.id, t0.user_id, t1.id, t1.name FROM (")`,
+				`buf.WriteString(") AS t0 LEFT JOIN text.\"user\" AS t1 ON t0.user_id = t1.id")`,
+				"authorName *string\n",
+				"if authorId != nil {\n\t\t\tent.author = &userEntity{}",
+				"lateral []pqt.LateralJoin\n",
+				"func (r *userRepositoryBase) findLateral(c *userCriteria) ([]*userLateral, error) {",
+				`buf := bytes.NewBufferString("SELECT t0.id, t0.name")`,
+				"if err := pqtgo.WriteLateralJoins(com, c.lateral); err != nil {",
+				"if keys := pqtgo.Keyset(c.sort, tableUserColumns); len(keys) > 0 || len(c.sortExpr) > 0 {\n\t\torderBy, err := pqtgo.QualifiedOrderBy(\"t0\", c.sortExpr, keys, tableUserColumns)",
+				"buf.WriteString(\" ORDER BY \")\n\t\tbuf.WriteString(orderBy)\n\t}\n\n\tif r.dbg {",
+				"row := &userLateral{entity: &ent, lateral: make(map[string]interface{}, len(lateral))}",
+			},
+			// Table without owned relationships does not get find with method.
+			excludes: []string{"func (r *userRepositoryBase) findWith"},
+		},
+		"composite-primary-key": {
+			schema: func() *pqt.Schema {
+				first := pqt.NewColumn("first_id", pqt.TypeIntegerBig(), pqt.WithNotNull())
+				second := pqt.NewColumn("second_id", pqt.TypeIntegerBig(), pqt.WithNotNull())
+				tbl := pqt.NewTable("join").AddColumn(first).AddColumn(second)
+				tbl.AddConstraint(pqt.PrimaryKey(tbl, first, second))
+				return pqt.NewSchema("text").AddTable(tbl)
+			}(),
+			generator: pqtgo.NewGenerator(),
+			contains: []string{
+				"func (r *joinRepositoryBase) findOneByFirstIdAndSecondId(firstId int64, secondId int64) (*joinEntity, error) {",
+				"FROM text.\"join\" WHERE first_id = $1 AND second_id = $2`",
+				"func (r *joinRepositoryBase) updateOneByFirstIdAndSecondId(firstId int64, secondId int64, patch *joinPatch) (*joinEntity, error) {",
+				"func (r *joinRepositoryBase) deleteOneByFirstIdAndSecondId(firstId int64, secondId int64) (int64, error) {",
+				`return "DELETE FROM text.\"join\" WHERE first_id = $1 AND second_id = $2", []interface{}{firstId, secondId}, nil`,
+				`tableJoinConstraintPrimaryKey = "text.join_first_id_second_id_pkey"`,
+			},
+		},
+	}
+
+	for hint, c := range cases {
+		t.Run(hint, func(t *testing.T) {
+			b, err := c.generator.Generate(c.schema)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			// Indentation is not compared, only tokens and line breaks are.
+			out := strings.Replace(string(b), "\t", "", -1)
+			for _, exp := range c.contains {
+				if !strings.Contains(out, strings.Replace(exp, "\t", "", -1)) {
+					t.Errorf("output should contain:\n%s", exp)
+				}
+			}
+			for _, unexp := range c.excludes {
+				if strings.Contains(out, strings.Replace(unexp, "\t", "", -1)) {
+					t.Errorf("output should not contain:\n%s", unexp)
+				}
+			}
+			if c.assert != nil {
+				c.assert(t, out)
+			}
+			assertTypeCheck(t, b)
+		})
+	}
+}
+
+func TestGenerator_SetDriver_unsupported(t *testing.T) {
+	s := pqt.NewSchema("text").AddTable(
+		pqt.NewTable("first").AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())),
+	)
+	cases := map[string]*pqtgo.Generator{
+		"without-context":     pqtgo.NewGenerator().SetDriver(pqtgo.DriverPgx),
+		"prepared-statements": pqtgo.NewGenerator().SetDriver(pqtgo.DriverPgx).SetContext(true).SetPreparedStatements(true),
+		"unknown":             pqtgo.NewGenerator().SetDriver("mysql"),
+	}
+	for hint, g := range cases {
+		t.Run(hint, func(t *testing.T) {
+			if _, err := g.Generate(s); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+// generatedFunc returns source of the generated function that starts with given signature, up to the next one.
+func generatedFunc(t *testing.T, out, signature string) string {
+	t.Helper()
+	start := strings.Index(out, signature)
+	if start == -1 {
+		t.Fatalf("output should contain %s", signature)
+	}
+	end := strings.Index(out[start:], "\nfunc ")
+	if end == -1 {
+		return out[start:]
+	}
+	return out[start : start+end]
+}
+
+// allOptionsSchema returns schema that makes use of every table and column option the generator handles.
+func allOptionsSchema() *pqt.Schema {
+	status := pqt.TypeEnumerated("text.user_status", "active", "banned")
+	email := pqt.TypeDomain("text.email", pqt.TypeText(), "VALUE ~ '@'")
+	name := pqt.NewColumn("name", pqt.TypeText(), pqt.WithNotNull(), pqt.WithUnique())
+	updatedAt := pqt.NewColumn("updated_at", pqt.TypeTimestampTZ(), pqt.WithDefault("NOW()", pqt.EventUpdate))
+	user := pqt.NewTable("user", pqt.WithVersionColumn("version"), pqt.WithReturning(updatedAt), pqt.WithTableComment("Users of the application."), pqt.WithTableCheck("positive_balance", "balance >= 0")).
+		AddColumn(pqt.NewColumn("id", pqt.TypeIntegerBig(), pqt.WithPrimaryKey(), pqt.WithIdentity(pqt.IdentityAlways))).
+		AddColumn(name).
+		AddColumn(pqt.NewColumn("email", email, pqt.WithNotNull())).
+		AddColumn(pqt.NewColumn("status", status, pqt.WithNotNull())).
+		AddColumn(pqt.NewColumn("position", pqt.TypeIntegerBig(), pqt.WithIdentity(pqt.IdentityByDefault))).
+		AddColumn(pqt.NewColumn("display_name", pqt.TypeText(), pqt.WithGenerated("name || '!'", pqt.GeneratedStored))).
+		AddColumn(pqt.NewColumn("tags", pqt.TypeArray(pqt.TypeText()))).
+		AddColumn(pqt.NewColumn("scores", pqt.TypeArray(pqt.TypeIntegerBig()))).
+		AddColumn(pqt.NewColumn("settings", pqt.TypeJSONB())).
+		AddColumn(pqt.NewColumn("balance", pqt.TypeNumeric(12, 2))).
+		AddColumn(pqt.NewColumn("active", pqt.TypeBool(), pqt.WithNotNull(), pqt.WithDefault("TRUE"))).
+		AddColumn(pqt.NewColumn("created_at", pqt.TypeTimestampTZ(), pqt.WithNotNull(), pqt.WithDefault("NOW()"))).
+		AddColumn(updatedAt)
+	comment := pqt.NewTable("comment", pqt.WithUUIDPrimaryKey(), pqt.WithSoftDelete(), pqt.WithSchema("reporting")).
+		AddColumn(pqt.NewColumn("content", pqt.TypeText(), pqt.WithNotNull())).
+		AddColumn(pqt.NewColumn("slug", pqt.TypeText(), pqt.WithConditionalUnique("deleted_at IS NULL"))).
+		AddRelationship(pqt.ManyToOne(user, pqt.WithInversedName("author")))
+	measurement := pqt.NewTable("measurement", pqt.WithUnlogged(), pqt.WithPartitionBy(pqt.PartitionStrategyRange, "created_at")).
+		AddColumn(pqt.NewColumn("created_at", pqt.TypeTimestampTZ(), pqt.WithNotNull())).
+		AddColumn(pqt.NewColumn("reading", pqt.TypeDoublePrecision()))
+
+	return pqt.NewSchema("text").
+		AddTable(user).
+		AddTable(comment).
+		AddTable(measurement).
+		AddTable(pqt.NewPartition(measurement, "measurement_2017", pqt.PartitionBoundsRange("'2017-01-01'", "'2018-01-01'"))).
+		AddTable(pqt.NewMaterializedView("user_stats", "SELECT status, count(*) AS total FROM text.user GROUP BY status",
+			pqt.NewColumn("status", status, pqt.WithNotNull()),
+			pqt.NewColumn("total", pqt.TypeIntegerBig(), pqt.WithNotNull()),
+		))
+}
+
+// TestGenerator_Generate_allOptions type checks code generated for every supported combination of generator settings.
+func TestGenerator_Generate_allOptions(t *testing.T) {
+	for _, v := range []pqtgo.Visibility{pqtgo.Private, pqtgo.Public} {
+		for _, d := range []pqtgo.Driver{pqtgo.DriverSQL, pqtgo.DriverPgx} {
+			for _, ctx := range []bool{false, true} {
+				if d == pqtgo.DriverPgx && !ctx {
+					continue // pgx driver requires context aware methods
+				}
+				t.Run(fmt.Sprintf("%s-%s-context-%t", v, strings.TrimPrefix(string(d), "database/"), ctx), func(t *testing.T) {
+					g := pqtgo.NewGenerator().
+						SetVisibility(v).
+						SetDriver(d).
+						SetContext(ctx).
+						SetJoins(true).
+						SetStrictSort(true).
+						SetPreparedStatements(d == pqtgo.DriverSQL).
+						SetInterfaces(true).
+						SetCache(true).
+						SetDDL(true).
+						SetFieldTags("json", "db")
+					b, err := g.Generate(allOptionsSchema())
+					if err != nil {
+						t.Fatalf("unexpected error: %s", err.Error())
+					}
+					assertTypeCheck(t, b)
+				})
+			}
+		}
+	}
+}
+
 func assertGoCode(t *testing.T, s1, s2, msg string, com ...interface{}) {
 	s1 = fmt.Sprintf("%s", s1)
 	s2 = fmt.Sprintf("%s", s2)
@@ -506,3 +2691,110 @@ func assertGoCode(t *testing.T, s1, s2, msg string, com ...interface{}) {
 		t.Errorf(b.String())
 	}
 }
+
+// generatedImports maps package names that generated code refers to on their import paths.
+// Generated files are expected to be processed by goimports, so most of them are not imported explicitly.
+var generatedImports = map[string]string{
+	"bytes":   "bytes",
+	"context": "context",
+	"driver":  "database/sql/driver",
+	"errors":  "errors",
+	"fmt":     "fmt",
+	"json":    "encoding/json",
+	"sort":    "sort",
+	"sql":     "database/sql",
+	"strconv": "strconv",
+	"strings": "strings",
+	"sync":    "sync",
+	"time":    "time",
+	"ntypes":  "github.com/piotrkowalczuk/ntypes",
+	"pq":      "github.com/lib/pq",
+	"pqcomp":  "github.com/piotrkowalczuk/pqcomp",
+	"pqt":     "github.com/piotrkowalczuk/pqt",
+	"pqtgo":   "github.com/piotrkowalczuk/pqt/pqtgo",
+	"ptypes":  "github.com/golang/protobuf/ptypes",
+	"qtypes":  "github.com/piotrkowalczuk/qtypes",
+}
+
+var (
+	stdFileSet  = token.NewFileSet()
+	stdImporter = importer.ForCompiler(stdFileSet, "source", nil)
+	versionPath = regexp.MustCompile(`/v[0-9]+$`)
+)
+
+// assertTypeCheck type checks generated code.
+// Standard library packages and packages of this repository are loaded from source, every other package is replaced by an empty one.
+// Because of that, only errors that do not involve third party identifiers are reported.
+func assertTypeCheck(t *testing.T, b []byte) {
+	t.Helper()
+
+	f, err := parser.ParseFile(stdFileSet, "schema.pqt.go", b, parser.ImportsOnly)
+	if err != nil {
+		t.Fatalf("generated code does not parse: %s", err.Error())
+	}
+	imported := make(map[string]struct{}, len(f.Imports))
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		imported[packageName(path)] = struct{}{}
+	}
+	var missing []string
+	for name, path := range generatedImports {
+		if _, ok := imported[name]; ok {
+			continue
+		}
+		if regexp.MustCompile(`\b` + name + `\.`).Match(b) {
+			missing = append(missing, strconv.Quote(path))
+		}
+	}
+	src := bytes.Replace(b, []byte("import (\n"), []byte("import (\n"+strings.Join(missing, "\n")+"\n"), 1)
+	if f, err = parser.ParseFile(stdFileSet, "schema.pqt.go", src, 0); err != nil {
+		t.Fatalf("generated code does not parse: %s", err.Error())
+	}
+
+	stubbed := make(map[string]struct{})
+	conf := types.Config{
+		Importer: importerFunc(func(path string) (*types.Package, error) {
+			if !strings.Contains(strings.Split(path, "/")[0], ".") || path == generatedImports["pqt"] || path == generatedImports["pqtgo"] {
+				return stdImporter.Import(path)
+			}
+			pkg := types.NewPackage(path, packageName(path))
+			pkg.MarkComplete()
+			stubbed[pkg.Name()] = struct{}{}
+			return pkg, nil
+		}),
+		Error: func(err error) {
+			msg := err.(types.Error).Msg
+			if strings.Contains(msg, "imported and not used") {
+				return
+			}
+			for name := range stubbed {
+				if strings.Contains(msg, name+".") {
+					return
+				}
+			}
+			t.Errorf("generated code does not type check: %s", err.Error())
+		},
+	}
+	conf.Check(f.Name.Name, stdFileSet, []*ast.File{f}, nil)
+}
+
+func packageName(path string) string {
+	path = versionPath.ReplaceAllString(path, "")
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+// decimal and nullDecimal stand for decimal types of a third party package, e.g. github.com/shopspring/decimal.
+type decimal struct {
+	value string
+}
+
+type nullDecimal struct {
+	decimal
+	valid bool
+}
@@ -0,0 +1,27 @@
+package pqtgo
+
+// Iterator is a common subset of methods implemented by generated iterators.
+// Each generated iterator additionally implements Ent method, that returns entity of the current row.
+type Iterator interface {
+	Next() bool
+	Err() error
+	Close() error
+}
+
+// ForEach calls given function for each row of the iterator, until rows are exhausted or function returns an error.
+// Iterator is always closed, even if the loop exits early, so the underlying connection is released.
+func ForEach(it Iterator, fn func() error) (err error) {
+	defer func() {
+		if cerr := it.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	for it.Next() {
+		if err = fn(); err != nil {
+			return err
+		}
+	}
+
+	return it.Err()
+}
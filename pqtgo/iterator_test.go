@@ -0,0 +1,125 @@
+package pqtgo_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/piotrkowalczuk/pqt/pqtgo"
+)
+
+// iteratorDriver is a minimal driver that returns given number of rows for any query and tracks if rows were closed.
+type iteratorDriver struct {
+	rows   int
+	closed bool
+}
+
+func (d *iteratorDriver) Open(string) (driver.Conn, error) { return &iteratorConn{d: d}, nil }
+
+type iteratorConn struct{ d *iteratorDriver }
+
+func (c *iteratorConn) Prepare(string) (driver.Stmt, error) { return &iteratorStmt{d: c.d}, nil }
+func (c *iteratorConn) Close() error                        { return nil }
+func (c *iteratorConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+type iteratorStmt struct{ d *iteratorDriver }
+
+func (s *iteratorStmt) Close() error  { return nil }
+func (s *iteratorStmt) NumInput() int { return -1 }
+func (s *iteratorStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s *iteratorStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &iteratorRows{d: s.d}, nil
+}
+
+type iteratorRows struct {
+	d *iteratorDriver
+	i int
+}
+
+func (r *iteratorRows) Columns() []string { return []string{"id"} }
+func (r *iteratorRows) Close() error {
+	r.d.closed = true
+	return nil
+}
+func (r *iteratorRows) Next(dest []driver.Value) error {
+	if r.i >= r.d.rows {
+		return io.EOF
+	}
+	r.i++
+	dest[0] = int64(r.i)
+	return nil
+}
+
+func TestForEach_earlyExit(t *testing.T) {
+	drv := &iteratorDriver{rows: 10}
+	sql.Register("pqtgo-iterator-early-exit", drv)
+	db, err := sql.Open("pqtgo-iterator-early-exit", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT id FROM example")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	stop := errors.New("stop")
+	var got int
+	err = pqtgo.ForEach(rows, func() error {
+		if got++; got == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("wrong error, expected %v but got %v", stop, err)
+	}
+	if got != 3 {
+		t.Errorf("wrong number of iterations, expected 3 but got %d", got)
+	}
+	if !drv.closed {
+		t.Error("rows should be closed if loop exits early")
+	}
+	if stats := db.Stats(); stats.InUse != 0 {
+		t.Errorf("connection should be released, but %d are in use", stats.InUse)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	drv := &iteratorDriver{rows: 5}
+	sql.Register("pqtgo-iterator", drv)
+	db, err := sql.Open("pqtgo-iterator", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT id FROM example")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	var sum int64
+	err = pqtgo.ForEach(rows, func() error {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		sum += id
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if sum != 15 {
+		t.Errorf("wrong sum, expected 15 but got %d", sum)
+	}
+	if !drv.closed {
+		t.Error("rows should be closed")
+	}
+}
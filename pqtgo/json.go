@@ -0,0 +1,60 @@
+package pqtgo
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// JSONValue is an adapter that marshals underlying value to JSON while it is written into the database,
+// and unmarshals it while it is scanned.
+type JSONValue struct {
+	v interface{}
+}
+
+// JSON wraps given value using JSONValue.
+// It is used by generated code for columns mapped using TypeCustomJSON.
+// To scan, given value has to be a pointer to the destination, for example pointer to the entity property.
+func JSON(v interface{}) *JSONValue {
+	return &JSONValue{v: v}
+}
+
+// Value implements driver.Valuer interface.
+// Nil pointer, map or slice is written as NULL.
+func (jv *JSONValue) Value() (driver.Value, error) {
+	if jv.v == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(jv.v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+	}
+
+	return json.Marshal(jv.v)
+}
+
+// Scan implements sql.Scanner interface.
+// NULL sets destination to its zero value, so pointer stays nil.
+func (jv *JSONValue) Scan(src interface{}) error {
+	rv := reflect.ValueOf(jv.v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("pqtgo: expected non nil pointer as a destination in Scan")
+	}
+
+	switch s := src.(type) {
+	case nil:
+		rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+		return nil
+	case []byte:
+		return json.Unmarshal(s, jv.v)
+	case string:
+		return json.Unmarshal([]byte(s), jv.v)
+	default:
+		return fmt.Errorf("pqtgo: expected slice of bytes or string as a source argument in Scan, not %T", src)
+	}
+}
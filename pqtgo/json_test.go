@@ -0,0 +1,69 @@
+package pqtgo_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/piotrkowalczuk/pqt/pqtgo"
+)
+
+type jsonMeta struct {
+	Author string   `json:"author"`
+	Tags   []string `json:"tags"`
+}
+
+func TestJSONValue_Value(t *testing.T) {
+	var empty *jsonMeta
+	cases := map[string]struct {
+		given    interface{}
+		expected interface{}
+	}{
+		"nil": {
+			given:    nil,
+			expected: nil,
+		},
+		"nil-pointer": {
+			given:    empty,
+			expected: nil,
+		},
+		"struct": {
+			given:    &jsonMeta{Author: "john", Tags: []string{"a", "b"}},
+			expected: []byte(`{"author":"john","tags":["a","b"]}`),
+		},
+	}
+
+	for hint, c := range cases {
+		got, err := pqtgo.JSON(c.given).Value()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", hint, err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(c.expected, got) {
+			t.Errorf("%s: wrong output, expected %s but got %s", hint, c.expected, got)
+		}
+	}
+}
+
+func TestJSONValue_Scan(t *testing.T) {
+	got := &jsonMeta{Author: "previous"}
+	if err := pqtgo.JSON(&got).Scan([]byte(`{"author":"john","tags":["a","b"]}`)); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if expected := (&jsonMeta{Author: "john", Tags: []string{"a", "b"}}); !reflect.DeepEqual(expected, got) {
+		t.Errorf("wrong output, expected %v but got %v", expected, got)
+	}
+
+	if err := pqtgo.JSON(&got).Scan(nil); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if got != nil {
+		t.Errorf("null should be scanned as nil, got %v", got)
+	}
+
+	if err := pqtgo.JSON(&got).Scan(int64(1)); err == nil {
+		t.Error("expected error")
+	}
+	if err := pqtgo.JSON(got).Scan([]byte(`{}`)); err == nil {
+		t.Error("expected error for nil destination")
+	}
+}
@@ -0,0 +1,177 @@
+package pqtgo
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/piotrkowalczuk/pqt"
+)
+
+// KeysetColumn is a single column of the keyset used by keyset (seek) pagination.
+type KeysetColumn struct {
+	Name string
+	Asc  bool
+}
+
+// Keyset returns columns that define order of the page.
+// Columns present in given sort map are taken in order of given columns, because map does not preserve order.
+// Unique columns (usually primary key) that are not part of the sort are appended in ascending order,
+// so that the order is deterministic and no row is skipped or returned twice.
+func Keyset(sort map[string]bool, columns []string, unique ...string) []KeysetColumn {
+	keys := make([]KeysetColumn, 0, len(sort)+len(unique))
+	for _, cn := range columns {
+		if asc, ok := sort[cn]; ok {
+			keys = append(keys, KeysetColumn{Name: cn, Asc: asc})
+		}
+	}
+	for _, cn := range unique {
+		if _, ok := sort[cn]; !ok {
+			keys = append(keys, KeysetColumn{Name: cn, Asc: true})
+		}
+	}
+
+	return keys
+}
+
+// ReverseKeyset returns copy of given keys with opposite directions, which allows to traverse pages backwards.
+func ReverseKeyset(keys []KeysetColumn) []KeysetColumn {
+	reversed := make([]KeysetColumn, 0, len(keys))
+	for _, k := range keys {
+		reversed = append(reversed, KeysetColumn{Name: k.Name, Asc: !k.Asc})
+	}
+
+	return reversed
+}
+
+// WriteKeysetCondition writes condition that matches rows placed after the row with given values in order defined by the keys.
+// If all keys have the same direction, row constructor comparison is used, e.g. (a, b) > ($1, $2).
+// Otherwise condition is expanded, e.g. (a > $1 OR (a = $2 AND b < $3)).
+func WriteKeysetCondition(com *Composer, keys []KeysetColumn, values []interface{}) error {
+	if len(keys) == 0 {
+		return errors.New("pqtgo: keyset requires at least one column")
+	}
+	if len(keys) != len(values) {
+		return errors.New("pqtgo: keyset requires value for each column")
+	}
+
+	mixed := false
+	for _, k := range keys[1:] {
+		if k.Asc != keys[0].Asc {
+			mixed = true
+			break
+		}
+	}
+
+	if !mixed {
+		com.WriteString("(")
+		for i, k := range keys {
+			if i != 0 {
+				com.WriteString(", ")
+			}
+			com.WriteString(k.Name)
+		}
+		com.WriteString(") ")
+		com.WriteString(keysetOperator(keys[0]))
+		com.WriteString(" (")
+		for i := range keys {
+			if i != 0 {
+				com.WriteString(", ")
+			}
+			if err := com.WritePlaceholder(); err != nil {
+				return err
+			}
+			com.Add(values[i])
+		}
+		com.WriteString(")")
+		return nil
+	}
+
+	com.WriteString("(")
+	for i, k := range keys {
+		if i != 0 {
+			com.WriteString(" OR ")
+		}
+		com.WriteString("(")
+		for j := 0; j < i; j++ {
+			com.WriteString(keys[j].Name)
+			com.WriteString(" = ")
+			if err := com.WritePlaceholder(); err != nil {
+				return err
+			}
+			com.Add(values[j])
+			com.WriteString(" AND ")
+		}
+		com.WriteString(k.Name)
+		com.WriteString(" ")
+		com.WriteString(keysetOperator(k))
+		com.WriteString(" ")
+		if err := com.WritePlaceholder(); err != nil {
+			return err
+		}
+		com.Add(values[i])
+		com.WriteString(")")
+	}
+	com.WriteString(")")
+
+	return nil
+}
+
+// KeysetOrderBy returns list of expressions for ORDER BY clause that corresponds to given keys.
+func KeysetOrderBy(keys []KeysetColumn) string {
+	buf := bytes.NewBuffer(nil)
+	for i, k := range keys {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(k.Name)
+		if !k.Asc {
+			buf.WriteString(" DESC")
+		}
+	}
+
+	return buf.String()
+}
+
+// OrderBy returns list of ORDER BY elements, given sort expressions come first, followed by keyset columns.
+// Each expression is validated against given columns first, see pqt.SortExpr.Validate.
+func OrderBy(exprs []pqt.SortExpr, keys []KeysetColumn, columns []string) (string, error) {
+	return QualifiedOrderBy("", exprs, keys, columns)
+}
+
+// QualifiedOrderBy works like OrderBy, but columns of both expressions and keys are qualified with given alias, unless it is empty.
+// It is meant for queries that join other relations, which can expose columns of the same names.
+func QualifiedOrderBy(alias string, exprs []pqt.SortExpr, keys []KeysetColumn, columns []string) (string, error) {
+	buf := bytes.NewBuffer(nil)
+	for i, se := range exprs {
+		if err := se.Validate(columns); err != nil {
+			return "", err
+		}
+		if alias != "" {
+			se = se.Qualify(alias)
+		}
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(se.String())
+	}
+	if len(exprs) > 0 && len(keys) > 0 {
+		buf.WriteString(", ")
+	}
+	if alias != "" {
+		qualified := make([]KeysetColumn, 0, len(keys))
+		for _, k := range keys {
+			qualified = append(qualified, KeysetColumn{Name: alias + "." + k.Name, Asc: k.Asc})
+		}
+		keys = qualified
+	}
+	buf.WriteString(KeysetOrderBy(keys))
+
+	return buf.String(), nil
+}
+
+func keysetOperator(k KeysetColumn) string {
+	if k.Asc {
+		return ">"
+	}
+	return "<"
+}
@@ -0,0 +1,153 @@
+package pqtgo_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/piotrkowalczuk/pqt"
+	"github.com/piotrkowalczuk/pqt/pqtgo"
+)
+
+func TestKeyset(t *testing.T) {
+	got := pqtgo.Keyset(map[string]bool{"name": false, "age": true, "unknown": true}, []string{"age", "id", "name"}, "id")
+	expected := []pqtgo.KeysetColumn{
+		{Name: "age", Asc: true},
+		{Name: "name", Asc: false},
+		{Name: "id", Asc: true},
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("wrong keyset, expected %v but got %v", expected, got)
+	}
+
+	got = pqtgo.Keyset(map[string]bool{"id": false}, []string{"id", "name"}, "id")
+	expected = []pqtgo.KeysetColumn{{Name: "id", Asc: false}}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("primary key should not be duplicated, expected %v but got %v", expected, got)
+	}
+}
+
+func TestWriteKeysetCondition(t *testing.T) {
+	cases := map[string]struct {
+		keys    []pqtgo.KeysetColumn
+		values  []interface{}
+		query   string
+		args    []interface{}
+		orderBy string
+	}{
+		"ascending": {
+			keys:    []pqtgo.KeysetColumn{{Name: "age", Asc: true}, {Name: "id", Asc: true}},
+			values:  []interface{}{10, 1},
+			query:   "(age, id) > ($1, $2)",
+			args:    []interface{}{10, 1},
+			orderBy: "age, id",
+		},
+		"descending": {
+			keys:    []pqtgo.KeysetColumn{{Name: "age", Asc: false}, {Name: "id", Asc: false}},
+			values:  []interface{}{10, 1},
+			query:   "(age, id) < ($1, $2)",
+			args:    []interface{}{10, 1},
+			orderBy: "age DESC, id DESC",
+		},
+		"mixed": {
+			keys:    []pqtgo.KeysetColumn{{Name: "age", Asc: false}, {Name: "id", Asc: true}},
+			values:  []interface{}{10, 1},
+			query:   "((age < $1) OR (age = $2 AND id > $3))",
+			args:    []interface{}{10, 10, 1},
+			orderBy: "age DESC, id",
+		},
+	}
+
+	for hint, c := range cases {
+		com := pqtgo.NewComposer(0)
+		if err := pqtgo.WriteKeysetCondition(com, c.keys, c.values); err != nil {
+			t.Errorf("%s: unexpected error: %s", hint, err.Error())
+			continue
+		}
+		if com.String() != c.query {
+			t.Errorf("%s: wrong query, expected %s but got %s", hint, c.query, com.String())
+		}
+		if !reflect.DeepEqual(c.args, com.Args()) {
+			t.Errorf("%s: wrong arguments, expected %v but got %v", hint, c.args, com.Args())
+		}
+		if got := pqtgo.KeysetOrderBy(c.keys); got != c.orderBy {
+			t.Errorf("%s: wrong order by, expected %s but got %s", hint, c.orderBy, got)
+		}
+	}
+}
+
+func TestWriteKeysetCondition_missingValue(t *testing.T) {
+	err := pqtgo.WriteKeysetCondition(pqtgo.NewComposer(0), []pqtgo.KeysetColumn{{Name: "id", Asc: true}}, nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestKeysetOrderBy_deterministic(t *testing.T) {
+	sort := map[string]bool{"name": true, "created_at": false, "id": true, "age": false}
+	columns := []string{"age", "created_at", "id", "name", "updated_at"}
+	expected := "age DESC, created_at DESC, id, name"
+
+	for i := 0; i < 100; i++ {
+		if got := pqtgo.KeysetOrderBy(pqtgo.Keyset(sort, columns)); got != expected {
+			t.Fatalf("build #%d: wrong order by, expected %s but got %s", i, expected, got)
+		}
+	}
+}
+
+func TestReverseKeyset(t *testing.T) {
+	keys := []pqtgo.KeysetColumn{{Name: "name", Asc: false}, {Name: "id", Asc: true}}
+	got := pqtgo.ReverseKeyset(keys)
+	expected := []pqtgo.KeysetColumn{{Name: "name", Asc: true}, {Name: "id", Asc: false}}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("wrong keyset, expected %v but got %v", expected, got)
+	}
+	if keys[0].Asc || !keys[1].Asc {
+		t.Error("given keys should not be modified")
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	columns := []string{"category_id", "created_at", "id"}
+	keys := []pqtgo.KeysetColumn{{Name: "id", Asc: true}}
+	exprs := []pqt.SortExpr{
+		{Expr: "ROW_NUMBER() OVER (PARTITION BY category_id ORDER BY created_at)", Desc: true},
+		{Column: "created_at"},
+	}
+
+	got, err := pqtgo.OrderBy(exprs, keys, columns)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if expected := "ROW_NUMBER() OVER (PARTITION BY category_id ORDER BY created_at) DESC, created_at, id"; got != expected {
+		t.Errorf("wrong order by, expected %q but got %q", expected, got)
+	}
+	if got, err = pqtgo.OrderBy(nil, keys, columns); err != nil || got != "id" {
+		t.Errorf("wrong order by without expressions, got %q, %v", got, err)
+	}
+	if _, err = pqtgo.OrderBy([]pqt.SortExpr{{Expr: "pg_sleep(1)"}}, keys, columns); err == nil {
+		t.Error("expected error for function that is not allowed")
+	}
+}
+
+func TestQualifiedOrderBy(t *testing.T) {
+	columns := []string{"category_id", "created_at", "id"}
+	keys := []pqtgo.KeysetColumn{{Name: "id", Asc: true}}
+	exprs := []pqt.SortExpr{
+		{Expr: "ROW_NUMBER() OVER (PARTITION BY category_id ORDER BY created_at)", Desc: true},
+		{Column: "created_at"},
+	}
+
+	got, err := pqtgo.QualifiedOrderBy("t0", exprs, keys, columns)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if expected := "ROW_NUMBER() OVER (PARTITION BY t0.category_id ORDER BY t0.created_at) DESC, t0.created_at, t0.id"; got != expected {
+		t.Errorf("wrong order by, expected %q but got %q", expected, got)
+	}
+	if keys[0].Name != "id" {
+		t.Error("given keys should not be modified")
+	}
+	if _, err = pqtgo.QualifiedOrderBy("t0", []pqt.SortExpr{{Expr: "t1.id"}}, keys, columns); err == nil {
+		t.Error("expected error for expression that refers to other relation")
+	}
+}
@@ -0,0 +1,93 @@
+package pqtgo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/piotrkowalczuk/pqt"
+)
+
+// LateralColumns validates given lateral joins and returns their columns qualified with the alias,
+// in the order they are selected by queries that use WriteLateralJoins.
+func LateralColumns(joins []pqt.LateralJoin) ([]string, error) {
+	var columns []string
+	for _, lj := range joins {
+		if err := lj.Validate(); err != nil {
+			return nil, err
+		}
+		for _, c := range lj.Columns {
+			columns = append(columns, lj.Alias+"."+c)
+		}
+	}
+
+	return columns, nil
+}
+
+// WriteLateralJoins writes JOIN LATERAL clause for each of given joins and adds their arguments to the composer.
+// Placeholders of each query are renumbered to follow those already written by the composer.
+func WriteLateralJoins(com *Composer, joins []pqt.LateralJoin) error {
+	for _, lj := range joins {
+		if err := lj.Validate(); err != nil {
+			return err
+		}
+		query, err := renumberPlaceholders(lj.Query, com.counter-1, len(lj.Args))
+		if err != nil {
+			return fmt.Errorf("pqtgo: lateral join %q: %s", lj.Alias, err.Error())
+		}
+		com.WriteString(" JOIN LATERAL (")
+		com.WriteString(query)
+		com.WriteString(") AS ")
+		com.WriteString(lj.Alias)
+		com.WriteString(" ON TRUE")
+		for _, arg := range lj.Args {
+			com.Add(arg)
+		}
+		com.counter += len(lj.Args)
+	}
+
+	return nil
+}
+
+// renumberPlaceholders shifts each $n placeholder of the query by given offset.
+// Placeholders within quoted literals and identifiers are left untouched.
+// An error is returned if placeholder refers to an argument beyond given number of arguments.
+func renumberPlaceholders(query string, offset, args int) (string, error) {
+	var (
+		b     strings.Builder
+		quote byte
+	)
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '$' && i+1 < len(query) && query[i+1] >= '0' && query[i+1] <= '9':
+			j := i + 1
+			for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+				j++
+			}
+			n, err := strconv.Atoi(query[i+1 : j])
+			if err != nil {
+				return "", err
+			}
+			if n < 1 || n > args {
+				return "", fmt.Errorf("placeholder $%d has no corresponding argument", n)
+			}
+			b.WriteString("$")
+			b.WriteString(strconv.Itoa(n + offset))
+			i = j - 1
+			continue
+		}
+		b.WriteByte(ch)
+	}
+	if quote != 0 {
+		return "", fmt.Errorf("unterminated quote %q", quote)
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,99 @@
+package pqtgo_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/piotrkowalczuk/pqt"
+	"github.com/piotrkowalczuk/pqt/pqtgo"
+)
+
+func TestWriteLateralJoins(t *testing.T) {
+	joins := []pqt.LateralJoin{
+		{
+			Query:   "SELECT id, content FROM comment WHERE news_id = t0.id AND content <> '$1' AND score > $2 LIMIT $1",
+			Args:    []interface{}{3, 10},
+			Alias:   "latest",
+			Columns: []string{"id", "content"},
+		},
+		{
+			Query:   "SELECT count(*) AS total FROM comment WHERE news_id = t0.id",
+			Alias:   "stats",
+			Columns: []string{"total"},
+		},
+	}
+	columns, err := pqtgo.LateralColumns(joins)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if expected := []string{"latest.id", "latest.content", "stats.total"}; !reflect.DeepEqual(expected, columns) {
+		t.Errorf("wrong columns, expected %v but got %v", expected, columns)
+	}
+
+	com := pqtgo.NewComposer(3)
+	com.WriteString("title = ")
+	com.WritePlaceholder()
+	com.Add("example")
+	if err := pqtgo.WriteLateralJoins(com, joins); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	com.WriteString(" LIMIT ")
+	com.WritePlaceholder()
+	com.Add(5)
+
+	expected := "title = $1" +
+		" JOIN LATERAL (SELECT id, content FROM comment WHERE news_id = t0.id AND content <> '$1' AND score > $3 LIMIT $2) AS latest ON TRUE" +
+		" JOIN LATERAL (SELECT count(*) AS total FROM comment WHERE news_id = t0.id) AS stats ON TRUE" +
+		" LIMIT $4"
+	if com.String() != expected {
+		t.Errorf("wrong output, expected:\n	%s\nbut got:\n	%s", expected, com.String())
+	}
+	if expected := []interface{}{"example", 3, 10, 5}; !reflect.DeepEqual(expected, com.Args()) {
+		t.Errorf("wrong arguments, expected %v but got %v", expected, com.Args())
+	}
+}
+
+func TestWriteLateralJoins_orderBy(t *testing.T) {
+	// Lateral join exposes id column, the same as the outer relation.
+	joins := []pqt.LateralJoin{{
+		Query:   "SELECT id, created_at FROM comment WHERE news_id = t0.id ORDER BY created_at DESC LIMIT 1",
+		Alias:   "latest",
+		Columns: []string{"id", "created_at"},
+	}}
+	exprs := []pqt.SortExpr{{Expr: "coalesce(created_at, id)", Desc: true}}
+	keys := []pqtgo.KeysetColumn{{Name: "id", Asc: true}}
+
+	com := pqtgo.NewComposer(0)
+	com.WriteString("SELECT t0.id, latest.id, latest.created_at FROM (SELECT id, created_at FROM news) AS t0")
+	if err := pqtgo.WriteLateralJoins(com, joins); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	orderBy, err := pqtgo.QualifiedOrderBy("t0", exprs, keys, []string{"created_at", "id"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	com.WriteString(" ORDER BY ")
+	com.WriteString(orderBy)
+
+	expected := "SELECT t0.id, latest.id, latest.created_at FROM (SELECT id, created_at FROM news) AS t0" +
+		" JOIN LATERAL (SELECT id, created_at FROM comment WHERE news_id = t0.id ORDER BY created_at DESC LIMIT 1) AS latest ON TRUE" +
+		" ORDER BY coalesce(t0.created_at, t0.id) DESC, t0.id"
+	if com.String() != expected {
+		t.Errorf("wrong output, expected:\n	%s\nbut got:\n	%s", expected, com.String())
+	}
+}
+
+func TestWriteLateralJoins_invalid(t *testing.T) {
+	cases := map[string]pqt.LateralJoin{
+		"missing-argument":   {Query: "SELECT id FROM comment LIMIT $2", Args: []interface{}{1}, Alias: "a", Columns: []string{"id"}},
+		"unterminated-quote": {Query: "SELECT 'id FROM comment", Alias: "a", Columns: []string{"id"}},
+		"invalid-alias":      {Query: "SELECT id FROM comment", Alias: "a b", Columns: []string{"id"}},
+	}
+	for hint, lj := range cases {
+		t.Run(hint, func(t *testing.T) {
+			if err := pqtgo.WriteLateralJoins(pqtgo.NewComposer(1), []pqt.LateralJoin{lj}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
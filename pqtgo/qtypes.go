@@ -261,40 +261,7 @@ func WriteCompositionQueryInt64(i *qtypes.Int64, sel string, com *Composer, opt
 			com.Dirty = true
 		}
 	case qtypes.QueryType_IN:
-		if len(i.Values) > 0 && len(i.Values) > 0 {
-			if com.Dirty {
-				if _, err = com.WriteString(opt.Joint); err != nil {
-					return
-				}
-			}
-			if _, err = com.WriteString(sel); err != nil {
-				return
-			}
-			if i.Negation {
-				if _, err = com.WriteString(" NOT IN ("); err != nil {
-					return
-				}
-			} else {
-				if _, err = com.WriteString(" IN ("); err != nil {
-					return
-				}
-			}
-			for i, v := range i.Values {
-				if i != 0 {
-					if _, err = com.WriteString(","); err != nil {
-						return
-					}
-				}
-				if err = com.WritePlaceholder(); err != nil {
-					return
-				}
-				com.Add(v)
-				com.Dirty = true
-			}
-			if _, err = com.WriteString(")"); err != nil {
-				return
-			}
-		}
+		return writeCompositionQueryIn(i.Negation, len(i.Values), pqt.ArrayInt64(i.Values), sel, com, opt)
 	case qtypes.QueryType_BETWEEN:
 		if com.Dirty {
 			if _, err = com.WriteString(opt.Joint); err != nil {
@@ -409,6 +376,8 @@ func WriteCompositionQueryString(s *qtypes.String, sel string, com *Composer, op
 		}
 		com.Add(s.Value())
 		com.Dirty = true
+	case qtypes.QueryType_IN:
+		return writeCompositionQueryIn(s.Negation, len(s.Values), pqt.ArrayString(s.Values), sel, com, opt)
 	case qtypes.QueryType_SUBSTRING:
 		if com.Dirty {
 			if _, err = com.WriteString(opt.Joint); err != nil {
@@ -661,3 +630,140 @@ func WriteCompositionQueryString(s *qtypes.String, sel string, com *Composer, op
 	}
 	return
 }
+
+// WriteCompositionQueryInt64Array works like WriteCompositionQueryInt64 but for columns of an array type, like BIGINT[].
+// Single value queries (EQUAL, HAS_ELEMENT) are checking if value is an element of an array using ANY.
+func WriteCompositionQueryInt64Array(i *qtypes.Int64, sel string, com *Composer, opt *CompositionOpts) error {
+	if i == nil || !i.Valid {
+		return nil
+	}
+
+	return writeCompositionQueryArray(i.Type, i.Negation, i.Value(), pqt.ArrayInt64(i.Values), sel, com, opt)
+}
+
+// WriteCompositionQueryStringArray works like WriteCompositionQueryString but for columns of an array type, like TEXT[].
+// Single value queries (EQUAL, HAS_ELEMENT) are checking if value is an element of an array using ANY.
+func WriteCompositionQueryStringArray(s *qtypes.String, sel string, com *Composer, opt *CompositionOpts) error {
+	if s == nil || !s.Valid {
+		return nil
+	}
+
+	return writeCompositionQueryArray(s.Type, s.Negation, s.Value(), pqt.ArrayString(s.Values), sel, com, opt)
+}
+
+// writeCompositionQueryIn writes membership test as a comparison with an array, so that single placeholder is used regardless of number of values.
+// Empty set matches nothing, while its negation does not restrict the result.
+func writeCompositionQueryIn(neg bool, n int, values interface{}, sel string, com *Composer, opt *CompositionOpts) (err error) {
+	if n == 0 && neg {
+		return nil
+	}
+	if com.Dirty {
+		if _, err = com.WriteString(opt.Joint); err != nil {
+			return
+		}
+	}
+	com.Dirty = true
+	if n == 0 {
+		_, err = com.WriteString("FALSE")
+		return
+	}
+	if _, err = com.WriteString(sel); err != nil {
+		return
+	}
+	if neg {
+		_, err = com.WriteString(" <> ALL(")
+	} else {
+		_, err = com.WriteString(" = ANY(")
+	}
+	if err != nil {
+		return
+	}
+	if err = com.WritePlaceholder(); err != nil {
+		return
+	}
+	if _, err = com.WriteString(")"); err != nil {
+		return
+	}
+	com.Add(values)
+	return
+}
+
+func writeCompositionQueryArray(qt qtypes.QueryType, neg bool, value, values interface{}, sel string, com *Composer, opt *CompositionOpts) (err error) {
+	var oper string
+	switch qt {
+	case qtypes.QueryType_NULL:
+		if com.Dirty {
+			if _, err = com.WriteString(opt.Joint); err != nil {
+				return
+			}
+		}
+		if _, err = com.WriteString(sel); err != nil {
+			return
+		}
+		if neg {
+			_, err = com.WriteString(" IS NOT NULL")
+		} else {
+			_, err = com.WriteString(" IS NULL")
+		}
+		com.Dirty = true
+		return
+	case qtypes.QueryType_EQUAL, qtypes.QueryType_HAS_ELEMENT:
+		if com.Dirty {
+			if _, err = com.WriteString(opt.Joint); err != nil {
+				return
+			}
+		}
+		if err = com.WritePlaceholder(); err != nil {
+			return
+		}
+		if neg {
+			_, err = com.WriteString(" <> ALL(")
+		} else {
+			_, err = com.WriteString(" = ANY(")
+		}
+		if err != nil {
+			return
+		}
+		if _, err = com.WriteString(sel); err != nil {
+			return
+		}
+		if _, err = com.WriteString(")"); err != nil {
+			return
+		}
+		com.Add(value)
+		com.Dirty = true
+		return
+	case qtypes.QueryType_CONTAINS, qtypes.QueryType_HAS_ALL_ELEMENTS:
+		oper = " @> "
+	case qtypes.QueryType_IS_CONTAINED_BY:
+		oper = " <@ "
+	case qtypes.QueryType_OVERLAP, qtypes.QueryType_HAS_ANY_ELEMENT:
+		oper = " && "
+	default:
+		return fmt.Errorf("pqtgo: unsupported array query type %s", qt.String())
+	}
+
+	if com.Dirty {
+		if _, err = com.WriteString(opt.Joint); err != nil {
+			return
+		}
+	}
+	if neg {
+		if _, err = com.WriteString("NOT "); err != nil {
+			return
+		}
+	}
+	if _, err = com.WriteString(sel); err != nil {
+		return
+	}
+	if _, err = com.WriteString(oper); err != nil {
+		return
+	}
+	if err = com.WritePlaceholder(); err != nil {
+		return
+	}
+	com.Add(values)
+	com.Dirty = true
+
+	return
+}
@@ -1,9 +1,11 @@
 package pqtgo
 
 import (
+	"database/sql/driver"
 	"reflect"
 	"testing"
 
+	"github.com/piotrkowalczuk/pqt"
 	"github.com/piotrkowalczuk/qtypes"
 )
 
@@ -57,6 +59,34 @@ func TestWriteCompositionQueryInt64(t *testing.T) {
 			exp:  " AND age <= $1",
 			args: []interface{}{int64(1)},
 		},
+		"in": {
+			sel:  "id",
+			obj:  qtypes.InInt64(1, 2, 3),
+			opt:  And,
+			exp:  " AND id = ANY($1)",
+			args: []interface{}{pqt.ArrayInt64{1, 2, 3}},
+		},
+		"not-in": {
+			sel:  "id",
+			obj:  &qtypes.Int64{Values: []int64{1, 2}, Type: qtypes.QueryType_IN, Negation: true, Valid: true},
+			opt:  And,
+			exp:  " AND id <> ALL($1)",
+			args: []interface{}{pqt.ArrayInt64{1, 2}},
+		},
+		"in-empty": {
+			sel:  "id",
+			obj:  qtypes.InInt64(),
+			opt:  And,
+			exp:  " AND FALSE",
+			args: []interface{}{},
+		},
+		"not-in-empty": {
+			sel:  "id",
+			obj:  &qtypes.Int64{Type: qtypes.QueryType_IN, Negation: true, Valid: true},
+			opt:  And,
+			exp:  "",
+			args: []interface{}{},
+		},
 	}
 
 	for hint, c := range cases {
@@ -121,6 +151,20 @@ func TestWriteCompositionQueryString(t *testing.T) {
 			exp:  " AND name IS NULL",
 			args: []interface{}{},
 		},
+		"in": {
+			sel:  "name",
+			obj:  qtypes.InString("John", "Jane"),
+			opt:  And,
+			exp:  " AND name = ANY($1)",
+			args: []interface{}{pqt.ArrayString{"John", "Jane"}},
+		},
+		"in-empty": {
+			sel:  "name",
+			obj:  qtypes.InString(),
+			opt:  And,
+			exp:  " AND FALSE",
+			args: []interface{}{},
+		},
 	}
 
 	for hint, c := range cases {
@@ -141,3 +185,119 @@ func TestWriteCompositionQueryString(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteCompositionQueryString_inValue(t *testing.T) {
+	com := NewComposer(0)
+	if err := WriteCompositionQueryString(qtypes.InString("a,b", `say "hi"`, "", "NULL"), "name", com, And); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(com.Args()) != 1 {
+		t.Fatalf("expected single argument, got %d", len(com.Args()))
+	}
+	v, err := com.Args()[0].(driver.Valuer).Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if exp := `{"a,b","say \"hi\"","","NULL"}`; string(v.([]byte)) != exp {
+		t.Errorf("wrong driver value, expected %s but got %s", exp, v)
+	}
+}
+
+func TestWriteCompositionQueryInt64Array(t *testing.T) {
+	cases := map[string]struct {
+		obj  *qtypes.Int64
+		exp  string
+		args []interface{}
+	}{
+		"null": {
+			obj:  qtypes.NullInt64(),
+			exp:  " AND x IS NULL",
+			args: []interface{}{},
+		},
+		"equal": {
+			obj:  qtypes.EqualInt64(1),
+			exp:  " AND $1 = ANY(x)",
+			args: []interface{}{int64(1)},
+		},
+		"not-has-element": {
+			obj:  &qtypes.Int64{Values: []int64{1}, Type: qtypes.QueryType_HAS_ELEMENT, Negation: true, Valid: true},
+			exp:  " AND $1 <> ALL(x)",
+			args: []interface{}{int64(1)},
+		},
+		"contains": {
+			obj:  &qtypes.Int64{Values: []int64{1, 2}, Type: qtypes.QueryType_CONTAINS, Valid: true},
+			exp:  " AND x @> $1",
+			args: []interface{}{pqt.ArrayInt64{1, 2}},
+		},
+		"is-contained-by": {
+			obj:  &qtypes.Int64{Values: []int64{1, 2}, Type: qtypes.QueryType_IS_CONTAINED_BY, Valid: true},
+			exp:  " AND x <@ $1",
+			args: []interface{}{pqt.ArrayInt64{1, 2}},
+		},
+		"not-overlap": {
+			obj:  &qtypes.Int64{Values: []int64{1, 2}, Type: qtypes.QueryType_OVERLAP, Negation: true, Valid: true},
+			exp:  " AND NOT x && $1",
+			args: []interface{}{pqt.ArrayInt64{1, 2}},
+		},
+	}
+
+	for hint, c := range cases {
+		t.Run(hint, func(t *testing.T) {
+			com := NewComposer(0)
+			com.Dirty = true
+			err := WriteCompositionQueryInt64Array(c.obj, "x", com, And)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			got := com.String()
+			if c.exp != got {
+				t.Errorf("wrong query, expected '%s' but got '%s'", c.exp, got)
+			}
+			if !reflect.DeepEqual(c.args, com.Args()) {
+				t.Errorf("wrong arguments, expected %v but got %v", c.args, com.Args())
+			}
+		})
+	}
+}
+
+func TestWriteCompositionQueryStringArray(t *testing.T) {
+	cases := map[string]struct {
+		obj  *qtypes.String
+		exp  string
+		args []interface{}
+	}{
+		"equal": {
+			obj:  qtypes.EqualString("a"),
+			exp:  " AND $1 = ANY(x)",
+			args: []interface{}{"a"},
+		},
+		"contains": {
+			obj:  &qtypes.String{Values: []string{"a", "b"}, Type: qtypes.QueryType_CONTAINS, Valid: true},
+			exp:  " AND x @> $1",
+			args: []interface{}{pqt.ArrayString{"a", "b"}},
+		},
+		"has-any-element": {
+			obj:  &qtypes.String{Values: []string{"a", "b"}, Type: qtypes.QueryType_HAS_ANY_ELEMENT, Valid: true},
+			exp:  " AND x && $1",
+			args: []interface{}{pqt.ArrayString{"a", "b"}},
+		},
+	}
+
+	for hint, c := range cases {
+		t.Run(hint, func(t *testing.T) {
+			com := NewComposer(0)
+			com.Dirty = true
+			err := WriteCompositionQueryStringArray(c.obj, "x", com, And)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			got := com.String()
+			if c.exp != got {
+				t.Errorf("wrong query, expected '%s' but got '%s'", c.exp, got)
+			}
+			if !reflect.DeepEqual(c.args, com.Args()) {
+				t.Errorf("wrong arguments, expected %v but got %v", c.args, com.Args())
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package pqtgo
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Querier is a common subset of *sql.DB and *sql.Tx methods that generated repositories rely on.
+// It allows the same repository to be used both with and without a transaction.
+type Querier interface {
+	Exec(string, ...interface{}) (sql.Result, error)
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	Query(string, ...interface{}) (*sql.Rows, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRow(string, ...interface{}) *sql.Row
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+// TxBeginner is a Querier that is also able to start a transaction, like *sql.DB.
+type TxBeginner interface {
+	Querier
+	BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+}
+
+var (
+	_ Querier    = &sql.DB{}
+	_ Querier    = &sql.Tx{}
+	_ TxBeginner = &sql.DB{}
+	_ Preparer   = &sql.Tx{}
+)
@@ -0,0 +1,107 @@
+package pqtgo
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// Preparer is a Querier that is also able to prepare statements, like *sql.DB.
+type Preparer interface {
+	Querier
+	PrepareContext(context.Context, string) (*sql.Stmt, error)
+}
+
+// StatementCache is a Querier that prepares each query once and reuses the statement afterwards.
+// Queries are used as keys, so it is meant for queries of fixed shape only, otherwise it grows unbounded.
+// It is safe for concurrent use.
+type StatementCache struct {
+	db    Preparer
+	stmts sync.Map
+}
+
+var _ Querier = &StatementCache{}
+
+// NewStatementCache allocates new StatementCache that prepares statements using given database.
+func NewStatementCache(db Preparer) *StatementCache {
+	return &StatementCache{db: db}
+}
+
+// Stmt returns prepared statement for given query, statement is prepared if it is not cached yet.
+func (c *StatementCache) Stmt(ctx context.Context, query string) (*sql.Stmt, error) {
+	if stmt, ok := c.stmts.Load(query); ok {
+		return stmt.(*sql.Stmt), nil
+	}
+
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	// Another goroutine could prepare the same query in the meantime, only one statement is kept.
+	if cached, loaded := c.stmts.LoadOrStore(query, stmt); loaded {
+		stmt.Close()
+		return cached.(*sql.Stmt), nil
+	}
+
+	return stmt, nil
+}
+
+// Exec implements Querier interface.
+func (c *StatementCache) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext implements Querier interface.
+func (c *StatementCache) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	stmt, err := c.Stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt.ExecContext(ctx, args...)
+}
+
+// Query implements Querier interface.
+func (c *StatementCache) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext implements Querier interface.
+func (c *StatementCache) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := c.Stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt.QueryContext(ctx, args...)
+}
+
+// QueryRow implements Querier interface.
+func (c *StatementCache) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext implements Querier interface.
+// If statement cannot be prepared, query is executed directly, so that the error is reported by the returned row.
+func (c *StatementCache) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	stmt, err := c.Stmt(ctx, query)
+	if err != nil {
+		return c.db.QueryRowContext(ctx, query, args...)
+	}
+
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+// Close closes all cached statements and removes them from the cache.
+// The first error encountered is returned.
+func (c *StatementCache) Close() (err error) {
+	c.stmts.Range(func(query, stmt interface{}) bool {
+		if cerr := stmt.(*sql.Stmt).Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		c.stmts.Delete(query)
+		return true
+	})
+
+	return err
+}
@@ -0,0 +1,120 @@
+package pqtgo_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/piotrkowalczuk/pqt/pqtgo"
+)
+
+// statementDriver is a minimal driver that counts prepared and closed statements, each query returns single row.
+type statementDriver struct {
+	prepared, closed int64
+}
+
+func (d *statementDriver) Open(string) (driver.Conn, error) { return &statementConn{d: d}, nil }
+
+type statementConn struct{ d *statementDriver }
+
+func (c *statementConn) Prepare(string) (driver.Stmt, error) {
+	atomic.AddInt64(&c.d.prepared, 1)
+	return &statementStmt{d: c.d}, nil
+}
+func (c *statementConn) Close() error              { return nil }
+func (c *statementConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type statementStmt struct{ d *statementDriver }
+
+func (s *statementStmt) Close() error {
+	atomic.AddInt64(&s.d.closed, 1)
+	return nil
+}
+func (s *statementStmt) NumInput() int { return -1 }
+func (s *statementStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+func (s *statementStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &statementRows{}, nil
+}
+
+type statementRows struct{ done bool }
+
+func (r *statementRows) Columns() []string { return []string{"id"} }
+func (r *statementRows) Close() error      { return nil }
+func (r *statementRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+func openStatementDB(t testing.TB, name string) (*sql.DB, *statementDriver) {
+	drv := &statementDriver{}
+	sql.Register(name, drv)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	return db, drv
+}
+
+func TestStatementCache(t *testing.T) {
+	db, drv := openStatementDB(t, "pqtgo-statement-cache")
+	defer db.Close()
+
+	cache := pqtgo.NewStatementCache(db)
+	for i := 0; i < 10; i++ {
+		var id int64
+		if err := cache.QueryRow("SELECT id FROM example WHERE id = $1", 1).Scan(&id); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if _, err := cache.Exec("DELETE FROM example WHERE id = $1", 1); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+	}
+	if drv.prepared != 2 {
+		t.Errorf("each query should be prepared once, but %d statements were prepared", drv.prepared)
+	}
+
+	if err := cache.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if drv.closed != 2 {
+		t.Errorf("all statements should be closed, but %d were closed", drv.closed)
+	}
+
+	// Cache is still usable after close, statements are prepared again.
+	if _, err := cache.Exec("DELETE FROM example WHERE id = $1", 1); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if drv.prepared != 3 {
+		t.Errorf("statement should be prepared again, but %d statements were prepared in total", drv.prepared)
+	}
+}
+
+func BenchmarkStatementCache_QueryRow(b *testing.B) {
+	db, _ := openStatementDB(b, "pqtgo-statement-cache-bench")
+	defer db.Close()
+
+	benchmarks := map[string]pqtgo.Querier{
+		"db":    db,
+		"cache": pqtgo.NewStatementCache(db),
+	}
+	for name, q := range benchmarks {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			var id int64
+			for n := 0; n < b.N; n++ {
+				if err := q.QueryRow("SELECT id FROM example WHERE id = $1", 1).Scan(&id); err != nil {
+					b.Fatalf("unexpected error: %s", err.Error())
+				}
+			}
+		})
+	}
+}
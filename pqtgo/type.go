@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"go/types"
 	"reflect"
+
+	"github.com/piotrkowalczuk/pqt"
 )
 
 // BuiltinType ...
@@ -58,6 +60,7 @@ type CustomType struct {
 	src                                             interface{}
 	mandatory, optional, criteria                   interface{}
 	mandatoryTypeOf, optionalTypeOf, criteriaTypeOf reflect.Type
+	json                                            bool
 }
 
 // String implements Stringer interface.
@@ -70,6 +73,36 @@ func (ct CustomType) Fingerprint() string {
 	return fmt.Sprintf("gocustomtype: %v", ct)
 }
 
+func (ct CustomType) canBeNil(tp reflect.Type) bool {
+	if tp == nil {
+		return false
+	}
+	switch tp.Kind() {
+	case reflect.Ptr, reflect.Map:
+		return true
+	case reflect.Struct, reflect.Slice:
+		// Struct is always generated as a pointer.
+		return ct.json
+	default:
+		return false
+	}
+}
+
+// pkgPaths returns import paths of the packages that define mandatory, optional and criteria types.
+func (ct CustomType) pkgPaths() []string {
+	var paths []string
+	for _, tp := range []reflect.Type{ct.mandatoryTypeOf, ct.optionalTypeOf, ct.criteriaTypeOf} {
+		for tp != nil && (tp.Kind() == reflect.Ptr || tp.Kind() == reflect.Slice) {
+			tp = tp.Elem()
+		}
+		if tp != nil && tp.PkgPath() != "" {
+			paths = append(paths, tp.PkgPath())
+		}
+	}
+
+	return paths
+}
+
 // TypeCustom ...
 func TypeCustom(m, o, c interface{}) CustomType {
 	var mandatoryTypeOf, optionalTypeOf, criteriaTypeOf reflect.Type
@@ -93,6 +126,29 @@ func TypeCustom(m, o, c interface{}) CustomType {
 	}
 }
 
+// TypeCustomJSON works like TypeCustom, but it is meant to be used as a mapping of JSON or JSONB column.
+// Generated code marshals and unmarshals values using encoding/json, so given types do not need to implement sql.Scanner nor driver.Valuer.
+// NULL is represented by nil.
+func TypeCustomJSON(m, o, c interface{}) CustomType {
+	ct := TypeCustom(m, o, c)
+	ct.json = true
+
+	return ct
+}
+
+// TypeJSONB returns JSONB column type mapped to the type of given value.
+// Value is also used as a criteria, which matches rows using containment operator (@>).
+func TypeJSONB(v interface{}) pqt.MappableType {
+	return pqt.TypeMappable(pqt.TypeJSONB(), TypeCustomJSON(v, v, v))
+}
+
+// TypeNumeric returns NUMERIC column type with given precision and scale, mapped to given mandatory, optional and criteria types,
+// e.g. decimal.Decimal, decimal.NullDecimal and decimal.NullDecimal of github.com/shopspring/decimal, instead of float64 that loses precision.
+// Types have to implement sql.Scanner and driver.Valuer, package that defines them has to be imported using SetImports.
+func TypeNumeric(precision, scale int, m, o, c interface{}) pqt.MappableType {
+	return pqt.TypeMappable(pqt.TypeNumeric(precision, scale), TypeCustom(m, o, c))
+}
+
 // TypeMapOfStrings ....
 func TypeMapOfStrings() CustomType {
 	return TypeCustom(
@@ -5,18 +5,31 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/piotrkowalczuk/pqt"
 )
 
 // Generator ...
-type Generator struct{}
+type Generator struct {
+	// dropIfExists makes generated script drop existing objects first, so that it can be run repeatedly.
+	dropIfExists bool
+}
 
 // NewGenerator ...
 func NewGenerator() *Generator {
 	return &Generator{}
 }
 
+// SetDropIfExists makes generated script drop tables, domains and enumerated types of the schema before creating them,
+// so that it can be run repeatedly, e.g. in local development or tests. Schema itself is created only if it does not exist.
+// Existing data is lost, it should never be enabled for production deployments.
+func (g *Generator) SetDropIfExists(drop bool) *Generator {
+	g.dropIfExists = drop
+
+	return g
+}
+
 // Generate ...
 func (g *Generator) Generate(s *pqt.Schema) ([]byte, error) {
 	code, err := g.generate(s)
@@ -38,14 +51,46 @@ func (g *Generator) GenerateTo(s *pqt.Schema, w io.Writer) error {
 	return err
 }
 
+// GenerateTable generates DDL of a single table, the same that is part of the schema script: CREATE TABLE statement
+// followed by its indexes and comments. Schema of the table, enumerated and domain types it uses are not created.
+func (g *Generator) GenerateTable(t *pqt.Table) ([]byte, error) {
+	if t == nil {
+		return nil, errors.New("pqt: missing table")
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := g.generateCreateTable(buf, t); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
 func (g *Generator) generate(s *pqt.Schema) (*bytes.Buffer, error) {
 	code := bytes.NewBufferString("-- do not modify, generated by pqt\n\n")
+	if g.dropIfExists {
+		g.generateDrop(code, s)
+	}
+	// Schemas can be shared with other scripts, e.g. migrations, so they are created only if they do not exist.
 	if s.Name != "" {
-		fmt.Fprint(code, "CREATE SCHEMA ")
-		if s.IfNotExists {
-			fmt.Fprint(code, "IF NOT EXISTS ")
+		fmt.Fprintf(code, "CREATE SCHEMA IF NOT EXISTS %s;\n\n", pqt.QuoteIdentifier(s.Name))
+	}
+	namespaces := map[string]bool{s.Name: true}
+	for _, t := range s.Tables {
+		if t != nil && t.Namespace != "" && !namespaces[t.Namespace] {
+			namespaces[t.Namespace] = true
+			fmt.Fprintf(code, "CREATE SCHEMA IF NOT EXISTS %s;\n\n", pqt.QuoteIdentifier(t.Namespace))
+		}
+	}
+	for _, et := range s.EnumeratedTypes() {
+		if err := g.generateCreateEnum(code, et, s.IfNotExists); err != nil {
+			return nil, err
+		}
+	}
+	// Domains are created after enumerated types, which they can be based on.
+	for _, dt := range s.DomainTypes() {
+		if err := g.generateCreateDomain(code, dt, s.IfNotExists); err != nil {
+			return nil, err
 		}
-		fmt.Fprintf(code, "%s; \n\n", s.Name)
 	}
 	for _, t := range s.Tables {
 		if err := g.generateCreateTable(code, t); err != nil {
@@ -56,6 +101,30 @@ func (g *Generator) generate(s *pqt.Schema) (*bytes.Buffer, error) {
 	return code, nil
 }
 
+// generateDrop drops objects of the schema in reverse order of their creation, so that dependent objects go first.
+// CASCADE removes also objects that depend on them from outside of the schema, e.g. foreign keys of other tables.
+func (g *Generator) generateDrop(buf *bytes.Buffer, s *pqt.Schema) {
+	for i := len(s.Tables) - 1; i >= 0; i-- {
+		if s.Tables[i] == nil {
+			continue
+		}
+		kind := "TABLE"
+		if s.Tables[i].IsMaterializedView() {
+			kind = "MATERIALIZED VIEW"
+		}
+		fmt.Fprintf(buf, "DROP %s IF EXISTS %s CASCADE;\n", kind, s.Tables[i].QuotedName())
+	}
+	domains := s.DomainTypes()
+	for i := len(domains) - 1; i >= 0; i-- {
+		fmt.Fprintf(buf, "DROP DOMAIN IF EXISTS %s CASCADE;\n", domains[i].String())
+	}
+	enums := s.EnumeratedTypes()
+	for i := len(enums) - 1; i >= 0; i-- {
+		fmt.Fprintf(buf, "DROP TYPE IF EXISTS %s CASCADE;\n", enums[i].String())
+	}
+	buf.WriteRune('\n')
+}
+
 func (g *Generator) generateCreateTable(buf *bytes.Buffer, t *pqt.Table) error {
 	if t == nil {
 		return nil
@@ -64,38 +133,69 @@ func (g *Generator) generateCreateTable(buf *bytes.Buffer, t *pqt.Table) error {
 	if t.Name == "" {
 		return errors.New("pqt: missing table name")
 	}
+	if t.IsPartition() {
+		return g.generateCreatePartition(buf, t)
+	}
+	if t.IsMaterializedView() {
+		return g.generateCreateMaterializedView(buf, t)
+	}
 	if len(t.Columns) == 0 {
 		return fmt.Errorf("pqt: table %s has no columns", t.Name)
 	}
 
-	constraints := tableConstraints(t)
+	// Conditional constraints cannot be part of the table definition, they are created as partial indexes instead.
+	var constraints, indexes []*pqt.Constraint
+	for _, c := range tableConstraints(t) {
+		if c.Where != "" || c.Type == pqt.ConstraintTypeIndex {
+			indexes = append(indexes, c)
+			continue
+		}
+		constraints = append(constraints, c)
+	}
+
+	if t.Temporary && t.Unlogged {
+		return fmt.Errorf("pqt: table %s cannot be both temporary and unlogged", t.Name)
+	}
 
 	buf.WriteString("CREATE ")
 	if t.Temporary {
 		buf.WriteString("TEMPORARY ")
 	}
+	if t.Unlogged {
+		buf.WriteString("UNLOGGED ")
+	}
 	buf.WriteString("TABLE ")
 	if t.IfNotExists {
 		buf.WriteString("IF NOT EXISTS ")
 	}
-	if t.Schema != nil {
-		buf.WriteString(t.Schema.Name)
-		buf.WriteRune('.')
-		buf.WriteString(t.Name)
-	} else {
-		buf.WriteString(t.Name)
-	}
+	buf.WriteString(t.QuotedName())
 	buf.WriteString(" (\n")
 	for i, c := range t.Columns {
 		buf.WriteRune('	')
-		buf.WriteString(c.Name)
+		buf.WriteString(pqt.QuoteIdentifier(c.Name))
 		buf.WriteRune(' ')
 		buf.WriteString(c.Type.String())
 		if c.Collate != "" {
 			buf.WriteRune(' ')
 			buf.WriteString(c.Collate)
 		}
-		if d, ok := c.DefaultOn(pqt.EventInsert); ok {
+		if c.Generated != "" {
+			buf.WriteString(" GENERATED ALWAYS AS (")
+			buf.WriteString(c.Generated)
+			buf.WriteString(") ")
+			if c.Generation != "" {
+				buf.WriteString(string(c.Generation))
+			} else {
+				buf.WriteString(string(pqt.GeneratedStored))
+			}
+		} else if c.Identity != "" {
+			switch c.Type {
+			case pqt.TypeIntegerSmall(), pqt.TypeInteger(), pqt.TypeIntegerBig():
+			default:
+				return fmt.Errorf("pqt: identity column %s.%s has to be of integer type, got %s", t.Name, c.Name, c.Type.String())
+			}
+			fmt.Fprintf(buf, " GENERATED %s AS IDENTITY", c.Identity)
+		} else if d, ok := c.DefaultOn(pqt.EventInsert); ok {
 			buf.WriteString(" DEFAULT ")
 			buf.WriteString(d)
 		}
@@ -124,7 +224,140 @@ func (g *Generator) generateCreateTable(buf *bytes.Buffer, t *pqt.Table) error {
 		buf.WriteRune('\n')
 	}
 
-	buf.WriteString(");\n\n")
+	buf.WriteString(")")
+	if err := partitionByQuery(buf, t); err != nil {
+		return err
+	}
+	storageParametersQuery(buf, t)
+	buf.WriteString(";\n")
+	for _, c := range indexes {
+		if c.Type == pqt.ConstraintTypeIndex {
+			if err := indexQuery(buf, t, c); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := partialUniqueIndexQuery(buf, t, c); err != nil {
+			return err
+		}
+	}
+	commentQuery(buf, t)
+	buf.WriteRune('\n')
+
+	return nil
+}
+
+// generateCreateMaterializedView generates CREATE MATERIALIZED VIEW statement, followed by indexes of the view.
+// Columns of the view are defined by the query, so only their comments are generated.
+func (g *Generator) generateCreateMaterializedView(buf *bytes.Buffer, t *pqt.Table) error {
+	buf.WriteString("CREATE MATERIALIZED VIEW ")
+	if t.IfNotExists {
+		buf.WriteString("IF NOT EXISTS ")
+	}
+	buf.WriteString(t.QuotedName())
+	storageParametersQuery(buf, t)
+	fmt.Fprintf(buf, " AS\n%s;\n", strings.TrimSuffix(strings.TrimSpace(t.Query), ";"))
+	for _, c := range tableConstraints(t) {
+		if c.Type != pqt.ConstraintTypeIndex {
+			return fmt.Errorf("pqt: materialized view %s cannot have %s constraint", t.Name, c.Type)
+		}
+		if err := indexQuery(buf, t, c); err != nil {
+			return err
+		}
+	}
+	commentQuery(buf, t)
+	buf.WriteRune('\n')
+
+	return nil
+}
+
+// generateCreateEnum generates CREATE TYPE statement for given enumerated type.
+// If ifNotExists is true, statement is safe to run again. Existing type is left untouched, but missing values are added.
+// Note that ALTER TYPE ... ADD VALUE cannot be executed inside a transaction block prior to PostgreSQL 12.
+func (g *Generator) generateCreateEnum(buf *bytes.Buffer, et pqt.EnumeratedType, ifNotExists bool) error {
+	if et.String() == "" {
+		return errors.New("pqt: missing enumerated type name")
+	}
+	if len(et.Enums) == 0 {
+		return fmt.Errorf("pqt: enumerated type %s has no values", et.String())
+	}
+
+	values := make([]string, 0, len(et.Enums))
+	for _, e := range et.Enums {
+		values = append(values, quoteLiteral(e))
+	}
+
+	if !ifNotExists {
+		fmt.Fprintf(buf, "CREATE TYPE %s AS ENUM (%s);\n\n", et.String(), strings.Join(values, ", "))
+		return nil
+	}
+
+	fmt.Fprintf(buf, "DO $$\nBEGIN\n\tCREATE TYPE %s AS ENUM (%s);\nEXCEPTION\n\tWHEN duplicate_object THEN NULL;\nEND\n$$;\n", et.String(), strings.Join(values, ", "))
+	for _, v := range values {
+		fmt.Fprintf(buf, "ALTER TYPE %s ADD VALUE IF NOT EXISTS %s;\n", et.String(), v)
+	}
+	buf.WriteRune('\n')
+
+	return nil
+}
+
+// generateCreateDomain generates CREATE DOMAIN statement for given domain type.
+// If ifNotExists is true, statement is safe to run again, existing domain is left untouched.
+func (g *Generator) generateCreateDomain(buf *bytes.Buffer, dt pqt.DomainType, ifNotExists bool) error {
+	if dt.String() == "" {
+		return errors.New("pqt: missing domain type name")
+	}
+	if dt.Base == nil {
+		return fmt.Errorf("pqt: domain type %s has no base type", dt.String())
+	}
+
+	query := fmt.Sprintf("CREATE DOMAIN %s AS %s", dt.String(), dt.Base.String())
+	if dt.Check != "" {
+		query += fmt.Sprintf(" CHECK (%s)", dt.Check)
+	}
+
+	if !ifNotExists {
+		fmt.Fprintf(buf, "%s;\n\n", query)
+		return nil
+	}
+
+	fmt.Fprintf(buf, "DO $$\nBEGIN\n\t%s;\nEXCEPTION\n\tWHEN duplicate_object THEN NULL;\nEND\n$$;\n\n", query)
+
+	return nil
+}
+
+func (g *Generator) generateCreatePartition(buf *bytes.Buffer, t *pqt.Table) error {
+	if t.PartitionBounds == "" {
+		return fmt.Errorf("pqt: partition %s has no bounds", t.Name)
+	}
+
+	if t.Temporary && t.Unlogged {
+		return fmt.Errorf("pqt: table %s cannot be both temporary and unlogged", t.Name)
+	}
+
+	buf.WriteString("CREATE ")
+	if t.Temporary {
+		buf.WriteString("TEMPORARY ")
+	}
+	if t.Unlogged {
+		buf.WriteString("UNLOGGED ")
+	}
+	buf.WriteString("TABLE ")
+	if t.IfNotExists {
+		buf.WriteString("IF NOT EXISTS ")
+	}
+	buf.WriteString(t.QuotedName())
+	buf.WriteString(" PARTITION OF ")
+	buf.WriteString(t.PartitionOf.QuotedName())
+	buf.WriteRune(' ')
+	buf.WriteString(string(t.PartitionBounds))
+	if err := partitionByQuery(buf, t); err != nil {
+		return err
+	}
+	storageParametersQuery(buf, t)
+	buf.WriteString(";\n")
+	commentQuery(buf, t)
+	buf.WriteRune('\n')
 
 	return nil
 }
@@ -136,22 +369,99 @@ func (g *Generator) generateConstraint(buf *bytes.Buffer, c *pqt.Constraint) err
 	case pqt.ConstraintTypePrimaryKey:
 		primaryKeyConstraintQuery(buf, c)
 	case pqt.ConstraintTypeForeignKey:
-		return foreignKeyConstraintQuery(buf, c)
+		if err := foreignKeyConstraintQuery(buf, c); err != nil {
+			return err
+		}
 	case pqt.ConstraintTypeCheck:
 		checkConstraintQuery(buf, c)
+	case pqt.ConstraintTypeExclusion:
+		if err := exclusionConstraintQuery(buf, c); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("pqt: unknown constraint type: %s", c.Type)
 	}
+	if d := c.Deferrable(); d != "" {
+		buf.WriteRune(' ')
+		buf.WriteString(d)
+	}
 
 	return nil
 }
 
 func uniqueConstraintQuery(buf *bytes.Buffer, c *pqt.Constraint) {
-	fmt.Fprintf(buf, `CONSTRAINT "%s" UNIQUE (%s)`, c.Name(), pqt.JoinColumns(c.Columns, ", "))
+	fmt.Fprintf(buf, `CONSTRAINT "%s" UNIQUE (%s)`, c.Name(), pqt.QuoteColumns(c.Columns, ", "))
+}
+
+// partialUniqueIndexQuery generates CREATE UNIQUE INDEX statement for unique constraint with a predicate.
+// Index is named after the constraint, so violations are reported using the same name.
+func partialUniqueIndexQuery(buf *bytes.Buffer, t *pqt.Table, c *pqt.Constraint) error {
+	if c.Type != pqt.ConstraintTypeUnique {
+		return fmt.Errorf("pqt: only unique constraint can be conditional, got: %s", c.Type)
+	}
+
+	buf.WriteString("CREATE UNIQUE INDEX ")
+	if t.IfNotExists {
+		buf.WriteString("IF NOT EXISTS ")
+	}
+	fmt.Fprintf(buf, `"%s" ON %s (%s) WHERE %s;`+"\n", c.Name(), t.QuotedName(), pqt.QuoteColumns(c.Columns, ", "), c.Where)
+
+	return nil
+}
+
+// indexQuery generates CREATE INDEX statement for given index, see pqt.NewIndex.
+func indexQuery(buf *bytes.Buffer, t *pqt.Table, c *pqt.Constraint) error {
+	if len(c.Columns) == 0 {
+		return fmt.Errorf("pqt: index %s requires at least one column", c.Name())
+	}
+
+	buf.WriteString("CREATE ")
+	if c.UniqueIndex {
+		buf.WriteString("UNIQUE ")
+	}
+	buf.WriteString("INDEX ")
+	if c.Concurrently {
+		buf.WriteString("CONCURRENTLY ")
+	}
+	if t.IfNotExists {
+		buf.WriteString("IF NOT EXISTS ")
+	}
+	fmt.Fprintf(buf, `"%s" ON %s`, c.Name(), t.QuotedName())
+	if c.Method != "" {
+		fmt.Fprintf(buf, " USING %s", c.Method)
+	}
+	fmt.Fprintf(buf, " (%s)", pqt.QuoteColumns(c.Columns, ", "))
+	if c.Where != "" {
+		fmt.Fprintf(buf, " WHERE %s", c.Where)
+	}
+	buf.WriteString(";\n")
+
+	return nil
 }
 
 func primaryKeyConstraintQuery(buf *bytes.Buffer, c *pqt.Constraint) {
-	fmt.Fprintf(buf, `CONSTRAINT "%s" PRIMARY KEY (%s)`, c.Name(), pqt.JoinColumns(c.Columns, ", "))
+	fmt.Fprintf(buf, `CONSTRAINT "%s" PRIMARY KEY (%s)`, c.Name(), pqt.QuoteColumns(c.Columns, ", "))
+}
+
+func exclusionConstraintQuery(buf *bytes.Buffer, c *pqt.Constraint) error {
+	if len(c.Exclude) == 0 {
+		return errors.New("pqt: exclusion constraint require at least one element")
+	}
+
+	fmt.Fprintf(buf, `CONSTRAINT "%s" EXCLUDE `, c.Name())
+	if c.Method != "" {
+		fmt.Fprintf(buf, "USING %s ", c.Method)
+	}
+	buf.WriteString("(")
+	for i, e := range c.Exclude {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(e.String())
+	}
+	buf.WriteString(")")
+
+	return nil
 }
 
 func foreignKeyConstraintQuery(buf *bytes.Buffer, c *pqt.Constraint) error {
@@ -166,9 +476,9 @@ func foreignKeyConstraintQuery(buf *bytes.Buffer, c *pqt.Constraint) error {
 
 	fmt.Fprintf(buf, `CONSTRAINT "%s" FOREIGN KEY (%s) REFERENCES %s (%s)`,
 		c.Name(),
-		pqt.JoinColumns(c.Columns, ", "),
-		c.ReferenceTable.FullName(),
-		pqt.JoinColumns(c.ReferenceColumns, ", "),
+		pqt.QuoteColumns(c.Columns, ", "),
+		c.ReferenceTable.QuotedName(),
+		pqt.QuoteColumns(c.ReferenceColumns, ", "),
 	)
 
 	switch c.OnDelete {
@@ -180,6 +490,8 @@ func foreignKeyConstraintQuery(buf *bytes.Buffer, c *pqt.Constraint) error {
 		buf.WriteString(" ON DELETE SET NULL")
 	case pqt.SetDefault:
 		buf.WriteString(" ON DELETE SET DEFAULT")
+	case pqt.NoAction:
+		buf.WriteString(" ON DELETE NO ACTION")
 	}
 
 	switch c.OnUpdate {
@@ -191,15 +503,62 @@ func foreignKeyConstraintQuery(buf *bytes.Buffer, c *pqt.Constraint) error {
 		buf.WriteString(" ON UPDATE SET NULL")
 	case pqt.SetDefault:
 		buf.WriteString(" ON UPDATE SET DEFAULT")
+	case pqt.NoAction:
+		buf.WriteString(" ON UPDATE NO ACTION")
+	}
+
+	return nil
+}
+
+func partitionByQuery(buf *bytes.Buffer, t *pqt.Table) error {
+	if t.PartitionStrategy == "" {
+		return nil
+	}
+	if len(t.PartitionColumns) == 0 {
+		return fmt.Errorf("pqt: partitioned table %s has no partition key", t.Name)
 	}
 
+	fmt.Fprintf(buf, " PARTITION BY %s (%s)", t.PartitionStrategy, strings.Join(t.PartitionColumns, ", "))
 	return nil
 }
 
+// storageParametersQuery generates WITH clause that sets storage parameters of the table, if any.
+func storageParametersQuery(buf *bytes.Buffer, t *pqt.Table) {
+	if len(t.StorageParameters) == 0 {
+		return
+	}
+
+	params := make([]string, 0, len(t.StorageParameters))
+	for _, p := range t.StorageParameters {
+		params = append(params, p.Key+"="+p.Value)
+	}
+	fmt.Fprintf(buf, " WITH (%s)", strings.Join(params, ", "))
+}
+
 func checkConstraintQuery(buf *bytes.Buffer, c *pqt.Constraint) {
 	fmt.Fprintf(buf, `CONSTRAINT "%s" CHECK (%s)`, c.Name(), c.Check)
 }
 
+// commentQuery generates COMMENT ON statements for the table and its columns, if any comment is defined.
+func commentQuery(buf *bytes.Buffer, t *pqt.Table) {
+	if t.Comment != "" {
+		kind := "TABLE"
+		if t.IsMaterializedView() {
+			kind = "MATERIALIZED VIEW"
+		}
+		fmt.Fprintf(buf, "COMMENT ON %s %s IS %s;\n", kind, t.QuotedName(), quoteLiteral(t.Comment))
+	}
+	for _, c := range t.Columns {
+		if c.Comment != "" {
+			fmt.Fprintf(buf, "COMMENT ON COLUMN %s.%s IS %s;\n", t.QuotedName(), pqt.QuoteIdentifier(c.Name), quoteLiteral(c.Comment))
+		}
+	}
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
 func tableConstraints(t *pqt.Table) []*pqt.Constraint {
 	constraints := make([]*pqt.Constraint, 0, len(t.Constraints)+len(t.Columns))
 	for _, c := range t.Columns {
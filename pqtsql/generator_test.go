@@ -1,6 +1,7 @@
 package pqtsql_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/piotrkowalczuk/pqt"
@@ -15,7 +16,7 @@ func TestGenerator_Generate(t *testing.T) {
 		{
 			expected: `-- do not modify, generated by pqt
 
-CREATE TEMPORARY TABLE schema.user (
+CREATE TEMPORARY TABLE schema."user" (
 	created_at TIMESTAMPTZ,
 	password TEXT,
 	username TEXT NOT NULL
@@ -78,6 +79,208 @@ CREATE TABLE IF NOT EXISTS table_name (
 					AddCheck("(start_at IS NULL AND end_at IS NULL) OR start_at < end_at", startAt, endAt)
 			}(),
 		},
+		{
+			expected: `-- do not modify, generated by pqt
+
+CREATE TABLE person (
+	first_name TEXT NOT NULL,
+	full_name TEXT GENERATED ALWAYS AS (first_name || ' ' || last_name) STORED,
+	last_name TEXT NOT NULL
+);
+
+`,
+			given: func() *pqt.Table {
+				return pqt.NewTable("person").
+					AddColumn(pqt.NewColumn("first_name", pqt.TypeText(), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("last_name", pqt.TypeText(), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("full_name", pqt.TypeText(), pqt.WithGenerated("first_name || ' ' || last_name", pqt.GeneratedStored)))
+			}(),
+		},
+		{
+			expected: `-- do not modify, generated by pqt
+
+CREATE TABLE measurement (
+	created_at TIMESTAMPTZ NOT NULL,
+	value INTEGER
+) PARTITION BY RANGE (created_at);
+
+`,
+			given: func() *pqt.Table {
+				return pqt.NewTable("measurement", pqt.WithPartitionBy(pqt.PartitionStrategyRange, "created_at")).
+					AddColumn(pqt.NewColumn("created_at", pqt.TypeTimestampTZ(), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("value", pqt.TypeInteger()))
+			}(),
+		},
+		{
+			expected: `-- do not modify, generated by pqt
+
+CREATE TABLE measurement_2017 PARTITION OF measurement FOR VALUES FROM ('2017-01-01') TO ('2018-01-01');
+
+`,
+			given: func() *pqt.Table {
+				parent := pqt.NewTable("measurement", pqt.WithPartitionBy(pqt.PartitionStrategyRange, "created_at")).
+					AddColumn(pqt.NewColumn("created_at", pqt.TypeTimestampTZ(), pqt.WithNotNull()))
+
+				return pqt.NewPartition(parent, "measurement_2017", pqt.PartitionBoundsRange("'2017-01-01'", "'2018-01-01'"))
+			}(),
+		},
+		{
+			expected: `-- do not modify, generated by pqt
+
+CREATE TABLE account_0 PARTITION OF account FOR VALUES WITH (MODULUS 2, REMAINDER 0) PARTITION BY LIST (region);
+
+`,
+			given: func() *pqt.Table {
+				parent := pqt.NewTable("account", pqt.WithPartitionBy(pqt.PartitionStrategyHash, "id")).
+					AddColumn(pqt.NewColumn("id", pqt.TypeIntegerBig(), pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("region", pqt.TypeText(), pqt.WithNotNull()))
+
+				return pqt.NewPartition(parent, "account_0", pqt.PartitionBoundsHash(2, 0), pqt.WithPartitionBy(pqt.PartitionStrategyList, "region"))
+			}(),
+		},
+		{
+			expected: `-- do not modify, generated by pqt
+
+CREATE TABLE "user" (
+	id BIGINT,
+	name TEXT
+);
+COMMENT ON TABLE "user" IS 'Registered user.';
+COMMENT ON COLUMN "user".name IS 'User''s display name.';
+
+`,
+			given: pqt.NewTable("user", pqt.WithTableComment("Registered user.")).
+				AddColumn(pqt.NewColumn("id", pqt.TypeIntegerBig())).
+				AddColumn(pqt.NewColumn("name", pqt.TypeText(), pqt.WithColumnComment("User's display name."))),
+		},
+		{
+			expected: `-- do not modify, generated by pqt
+
+CREATE TABLE IF NOT EXISTS post (
+	deleted_at TIMESTAMPTZ,
+	slug TEXT NOT NULL,
+	title TEXT NOT NULL,
+
+	CONSTRAINT "public.post_title_key" UNIQUE (title)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "public.post_slug_key" ON post (slug) WHERE deleted_at IS NULL;
+
+`,
+			given: pqt.NewTable("post", pqt.WithTableIfNotExists()).
+				AddColumn(pqt.NewColumn("slug", pqt.TypeText(), pqt.WithNotNull(), pqt.WithConditionalUnique("deleted_at IS NULL"))).
+				AddColumn(pqt.NewColumn("title", pqt.TypeText(), pqt.WithNotNull(), pqt.WithUnique())).
+				AddColumn(pqt.NewColumn("deleted_at", pqt.TypeTimestampTZ())),
+		},
+		{
+			expected: `-- do not modify, generated by pqt
+
+CREATE TABLE product (
+	price DECIMAL(10,2) NOT NULL,
+	stock INTEGER,
+
+	CONSTRAINT "non_negative_stock" CHECK (stock >= 0),
+	CONSTRAINT "positive_price" CHECK (price > 0)
+);
+
+`,
+			given: pqt.NewTable("product", pqt.WithTableCheck("positive_price", "price > 0")).
+				AddColumn(pqt.NewColumn("price", pqt.TypeDecimal(10, 2), pqt.WithNotNull())).
+				AddColumn(pqt.NewColumn("stock", pqt.TypeInteger(), pqt.WithNamedCheck("non_negative_stock", "stock >= 0"))),
+		},
+		{
+			expected: `-- do not modify, generated by pqt
+
+CREATE TABLE reservation (
+	period TSTZRANGE NOT NULL,
+	room_id BIGINT NOT NULL,
+
+	CONSTRAINT "reservation_overlap" EXCLUDE USING gist (room_id WITH =, period WITH &&)
+);
+
+`,
+			given: func() *pqt.Table {
+				roomID := pqt.NewColumn("room_id", pqt.TypeIntegerBig(), pqt.WithNotNull())
+				period := pqt.NewColumn("period", pqt.TypePseudo("TSTZRANGE"), pqt.WithNotNull())
+
+				return pqt.NewTable("reservation", pqt.WithExcludeConstraint("reservation_overlap", "gist",
+					pqt.ExcludeElement{Column: roomID, Operator: "="},
+					pqt.ExcludeElement{Column: period, Operator: "&&"},
+				)).
+					AddColumn(roomID).
+					AddColumn(period)
+			}(),
+		},
+		{
+			expected: `-- do not modify, generated by pqt
+
+CREATE TABLE token (
+	id UUID DEFAULT gen_random_uuid(),
+
+	CONSTRAINT "public.token_id_pkey" PRIMARY KEY (id)
+);
+
+`,
+			given: pqt.NewTable("token").
+				AddColumn(pqt.NewColumn("id", pqt.TypeUUID(), pqt.WithPrimaryKey(), pqt.WithDefault("gen_random_uuid()"))),
+		},
+		{
+			expected: `-- do not modify, generated by pqt
+
+CREATE TABLE vote (
+	news_id BIGINT NOT NULL,
+	user_id BIGINT NOT NULL,
+
+	CONSTRAINT "vote_news_user_key" UNIQUE (news_id, user_id)
+);
+
+`,
+			given: func() *pqt.Table {
+				newsID := pqt.NewColumn("news_id", pqt.TypeIntegerBig(), pqt.WithNotNull())
+				userID := pqt.NewColumn("user_id", pqt.TypeIntegerBig(), pqt.WithNotNull())
+
+				return pqt.NewTable("vote", pqt.WithUniqueConstraint("vote_news_user_key", newsID, userID)).
+					AddColumn(newsID).
+					AddColumn(userID)
+			}(),
+		},
+		{
+			expected: `-- do not modify, generated by pqt
+
+CREATE TYPE user_status AS ENUM ('active', 'inactive', 'can''t login');
+
+CREATE TABLE "user" (
+	previous_status user_status,
+	status user_status NOT NULL
+);
+
+`,
+			given: func() *pqt.Table {
+				status := pqt.TypeEnumerated("user_status", "active", "inactive", "can't login")
+
+				return pqt.NewTable("user").
+					AddColumn(pqt.NewColumn("status", status, pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("previous_status", status))
+			}(),
+		},
+		{
+			expected: `-- do not modify, generated by pqt
+
+CREATE DOMAIN email AS TEXT CHECK (VALUE ~ '^[^@]+@[^@]+$');
+
+CREATE TABLE "user" (
+	backup_email email,
+	email email NOT NULL
+);
+
+`,
+			given: func() *pqt.Table {
+				email := pqt.TypeDomain("email", pqt.TypeText(), "VALUE ~ '^[^@]+@[^@]+$'")
+
+				return pqt.NewTable("user").
+					AddColumn(pqt.NewColumn("email", email, pqt.WithNotNull())).
+					AddColumn(pqt.NewColumn("backup_email", email))
+			}(),
+		},
 	}
 
 	for i, data := range success {
@@ -95,3 +298,613 @@ CREATE TABLE IF NOT EXISTS table_name (
 		}
 	}
 }
+
+func TestGenerator_Generate_crossSchemaReference(t *testing.T) {
+	userID := pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())
+	pqt.NewSchema("auth").AddTable(pqt.NewTable("user").AddColumn(userID))
+	content := pqt.NewSchema("content").AddTable(
+		pqt.NewTable("news").
+			AddColumn(pqt.NewColumn("author_id", pqt.TypeIntegerBig(), pqt.WithReference(userID))),
+	)
+
+	q, err := pqtsql.NewGenerator().Generate(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := `-- do not modify, generated by pqt
+
+CREATE SCHEMA IF NOT EXISTS content;
+
+CREATE TABLE content.news (
+	author_id BIGINT,
+
+	CONSTRAINT "content.news_author_id_fkey" FOREIGN KEY (author_id) REFERENCES auth."user" (id)
+);
+
+`
+	if string(q) != expected {
+		t.Errorf("wrong query, expected:\n'%s'\nbut got:\n'%s'", expected, q)
+	}
+}
+
+func TestGenerator_Generate_quotedIdentifiers(t *testing.T) {
+	userID := pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())
+	pqt.NewSchema("Auth").AddTable(pqt.NewTable("user").AddColumn(userID))
+	content := pqt.NewSchema("content").AddTable(
+		pqt.NewTable("News").
+			AddColumn(pqt.NewColumn("order", pqt.TypeInteger(), pqt.WithNotNull(), pqt.WithUnique())).
+			AddColumn(pqt.NewColumn("Title", pqt.TypeText(), pqt.WithComment("Title of the news."))).
+			AddColumn(pqt.NewColumn("author_id", pqt.TypeIntegerBig(), pqt.WithReference(userID))),
+	)
+
+	q, err := pqtsql.NewGenerator().Generate(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := `-- do not modify, generated by pqt
+
+CREATE SCHEMA IF NOT EXISTS content;
+
+CREATE TABLE content."News" (
+	"Title" TEXT,
+	author_id BIGINT,
+	"order" INTEGER NOT NULL,
+
+	CONSTRAINT "content.News_order_key" UNIQUE ("order"),
+	CONSTRAINT "content.News_author_id_fkey" FOREIGN KEY (author_id) REFERENCES "Auth"."user" (id)
+);
+COMMENT ON COLUMN content."News"."Title" IS 'Title of the news.';
+
+`
+	if string(q) != expected {
+		t.Errorf("wrong query, expected:\n'%s'\nbut got:\n'%s'", expected, q)
+	}
+}
+
+// TestGenerator_Generate_roundTrip parses identifiers back from the generated script,
+// the same way PostgreSQL does, and compares them with names of the schema.
+func TestGenerator_Generate_roundTrip(t *testing.T) {
+	userID := pqt.NewColumn("ID", pqt.TypeSerialBig(), pqt.WithPrimaryKey())
+	pqt.NewSchema("Auth").AddTable(pqt.NewTable("user").AddColumn(userID))
+	s := pqt.NewSchema("content").AddTable(
+		pqt.NewTable("select").
+			AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+			AddColumn(pqt.NewColumn("Group", pqt.TypeText(), pqt.WithNotNull())).
+			AddColumn(pqt.NewColumn("some \"quoted\" name", pqt.TypeText())).
+			AddColumn(pqt.NewColumn("user_id", pqt.TypeIntegerBig(), pqt.WithReference(userID))),
+	).AddTable(
+		pqt.NewTable("news", pqt.WithSchema("Reporting")).
+			AddColumn(pqt.NewColumn("total", pqt.TypeIntegerBig())),
+	)
+
+	q, err := pqtsql.NewGenerator().Generate(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	got := parseDDL(t, string(q))
+
+	expected := ddl{
+		schemas: []string{"content", "Reporting"},
+		tables: map[string][]string{
+			"content.select": {"Group", "id", `some "quoted" name`, "user_id"},
+			"Reporting.news": {"total"},
+		},
+		references: []string{"Auth.user(ID)"},
+	}
+	if strings.Join(got.schemas, ",") != strings.Join(expected.schemas, ",") {
+		t.Errorf("wrong schemas, expected %v but got %v", expected.schemas, got.schemas)
+	}
+	if len(got.tables) != len(expected.tables) {
+		t.Errorf("wrong number of tables, expected %d but got %d", len(expected.tables), len(got.tables))
+	}
+	for name, columns := range expected.tables {
+		if strings.Join(got.tables[name], ",") != strings.Join(columns, ",") {
+			t.Errorf("wrong columns of table %s, expected %v but got %v", name, columns, got.tables[name])
+		}
+	}
+	if strings.Join(got.references, ",") != strings.Join(expected.references, ",") {
+		t.Errorf("wrong references, expected %v but got %v", expected.references, got.references)
+	}
+}
+
+type ddl struct {
+	schemas    []string
+	tables     map[string][]string
+	references []string
+}
+
+// parseDDL extracts schemas, tables with their columns and foreign key targets from the script generated by pqtsql.
+// It understands only statements produced by the generator.
+func parseDDL(t *testing.T, script string) ddl {
+	t.Helper()
+
+	tokens := tokenizeDDL(script)
+	res := ddl{tables: map[string][]string{}}
+	// name reads possibly qualified identifier starting at i and returns index of the following token.
+	name := func(i int) (string, int) {
+		n := tokens[i].name()
+		for i+2 < len(tokens) && tokens[i+1].value == "." && !tokens[i+1].ident {
+			n += "." + tokens[i+2].name()
+			i += 2
+		}
+		return n, i + 1
+	}
+	keyword := func(i int, words ...string) bool {
+		for j, w := range words {
+			if i+j >= len(tokens) || tokens[i+j].ident || strings.ToUpper(tokens[i+j].value) != w {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		switch {
+		case keyword(i, "CREATE", "SCHEMA", "IF", "NOT", "EXISTS"):
+			res.schemas = append(res.schemas, tokens[i+5].name())
+			i += 5
+		case keyword(i, "CREATE", "TABLE"):
+			table, j := name(i + 2)
+			if !keyword(j, "(") {
+				t.Fatalf("table %s: expected opening parenthesis, got %s", table, tokens[j].value)
+			}
+			columns := []string{}
+			depth, expectColumn := 0, true
+			for j++; depth >= 0; j++ {
+				tok := tokens[j]
+				switch {
+				case keyword(j, "("):
+					depth++
+				case keyword(j, ")"):
+					depth--
+				case keyword(j, ",") && depth == 0:
+					expectColumn = true
+					continue
+				case expectColumn && depth == 0 && !keyword(j, "CONSTRAINT"):
+					columns = append(columns, tok.name())
+				case keyword(j, "REFERENCES"):
+					target, k := name(j + 1)
+					column, _ := name(k + 1)
+					res.references = append(res.references, target+"("+column+")")
+				}
+				expectColumn = false
+			}
+			res.tables[table] = columns
+			i = j
+		}
+	}
+
+	return res
+}
+
+type ddlToken struct {
+	value string
+	// ident is true if token is an identifier, either quoted or not.
+	ident bool
+}
+
+// name returns the token as an identifier, keywords are folded to lower case like any other unquoted name.
+func (t ddlToken) name() string {
+	if t.ident {
+		return t.value
+	}
+	return strings.ToLower(t.value)
+}
+
+// tokenizeDDL splits script into tokens, quoted identifiers are unquoted and unquoted ones are folded to lower case.
+// Keywords are reported as identifiers as well, callers distinguish them by position. String literals and comments are skipped.
+func tokenizeDDL(script string) []ddlToken {
+	var tokens []ddlToken
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case strings.HasPrefix(script[i:], "--"):
+			for i < len(script) && script[i] != '\n' {
+				i++
+			}
+		case c == '\'':
+			for i++; i < len(script); i++ {
+				if script[i] == '\'' {
+					if i+1 < len(script) && script[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			i++
+		case c == '"':
+			var b strings.Builder
+			for i++; i < len(script); i++ {
+				if script[i] == '"' {
+					if i+1 < len(script) && script[i+1] == '"' {
+						b.WriteByte('"')
+						i++
+						continue
+					}
+					break
+				}
+				b.WriteByte(script[i])
+			}
+			i++
+			tokens = append(tokens, ddlToken{value: b.String(), ident: true})
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			j := i
+			for j < len(script) && (script[j] == '_' || script[j] == '$' || (script[j] >= 'a' && script[j] <= 'z') || (script[j] >= 'A' && script[j] <= 'Z') || (script[j] >= '0' && script[j] <= '9')) {
+				j++
+			}
+			word := script[i:j]
+			// Keywords are recognized by the caller in upper case, names are folded to lower case.
+			if word == strings.ToUpper(word) {
+				tokens = append(tokens, ddlToken{value: word})
+			} else {
+				tokens = append(tokens, ddlToken{value: strings.ToLower(word), ident: true})
+			}
+			i = j
+		default:
+			tokens = append(tokens, ddlToken{value: string(c)})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+func TestGenerator_Generate_tableSchema(t *testing.T) {
+	s := pqt.NewSchema("content").
+		AddTable(pqt.NewTable("news", pqt.WithSchema("reporting")).
+			AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey()))).
+		AddTable(pqt.NewTable("views", pqt.WithSchema("reporting")).
+			AddColumn(pqt.NewColumn("total", pqt.TypeIntegerBig())))
+
+	q, err := pqtsql.NewGenerator().Generate(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := `-- do not modify, generated by pqt
+
+CREATE SCHEMA IF NOT EXISTS content;
+
+CREATE SCHEMA IF NOT EXISTS reporting;
+
+CREATE TABLE reporting.news (
+	id BIGSERIAL,
+
+	CONSTRAINT "reporting.news_id_pkey" PRIMARY KEY (id)
+);
+
+CREATE TABLE reporting.views (
+	total BIGINT
+);
+
+`
+	if string(q) != expected {
+		t.Errorf("wrong query, expected:\n'%s'\nbut got:\n'%s'", expected, q)
+	}
+}
+
+func TestGenerator_Generate_referentialActions(t *testing.T) {
+	userID := pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())
+	s := pqt.NewSchema("example").
+		AddTable(pqt.NewTable("user").AddColumn(userID)).
+		AddTable(pqt.NewTable("news").
+			AddColumn(pqt.NewColumn("author_id", pqt.TypeIntegerBig(), pqt.WithReference(userID), pqt.WithOnDelete(pqt.Cascade), pqt.WithOnUpdate(pqt.NoAction))).
+			AddColumn(pqt.NewColumn("editor_id", pqt.TypeIntegerBig(), pqt.WithReference(userID), pqt.WithOnDelete(pqt.SetNull), pqt.WithOnUpdate(pqt.Restrict))).
+			AddColumn(pqt.NewColumn("reviewer_id", pqt.TypeIntegerBig(), pqt.WithReference(userID), pqt.WithOnDelete(pqt.SetDefault))),
+		)
+
+	q, err := pqtsql.NewGenerator().Generate(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	for _, expected := range []string{
+		`CONSTRAINT "example.news_author_id_fkey" FOREIGN KEY (author_id) REFERENCES example."user" (id) ON DELETE CASCADE ON UPDATE NO ACTION`,
+		`CONSTRAINT "example.news_editor_id_fkey" FOREIGN KEY (editor_id) REFERENCES example."user" (id) ON DELETE SET NULL ON UPDATE RESTRICT`,
+		`CONSTRAINT "example.news_reviewer_id_fkey" FOREIGN KEY (reviewer_id) REFERENCES example."user" (id) ON DELETE SET DEFAULT`,
+	} {
+		if !strings.Contains(string(q), expected) {
+			t.Errorf("query should contain:\n%s\nbut got:\n%s", expected, q)
+		}
+	}
+}
+
+func TestGenerator_Generate_deferrable(t *testing.T) {
+	nodeID := pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())
+	parentID := pqt.NewColumn("parent_id", pqt.TypeIntegerBig())
+	position := pqt.NewColumn("position", pqt.TypeInteger())
+	node := pqt.NewTable("node").AddColumn(nodeID).AddColumn(parentID).AddColumn(position)
+	node.AddConstraint(pqt.ForeignKey(node, pqt.Columns{parentID}, pqt.Columns{nodeID}, pqt.WithDeferrable(pqt.DeferrableDeferred)))
+	pqt.WithDeferrableUniqueConstraint("node_parent_position_key", pqt.DeferrableImmediate, parentID, position)(node)
+	s := pqt.NewSchema("example").AddTable(node)
+
+	q, err := pqtsql.NewGenerator().Generate(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	for _, expected := range []string{
+		`CONSTRAINT "example.node_parent_id_fkey" FOREIGN KEY (parent_id) REFERENCES example.node (id) DEFERRABLE INITIALLY DEFERRED`,
+		`CONSTRAINT "node_parent_position_key" UNIQUE (parent_id, position) DEFERRABLE INITIALLY IMMEDIATE`,
+		`CONSTRAINT "example.node_id_pkey" PRIMARY KEY (id),`,
+	} {
+		if !strings.Contains(string(q), expected) {
+			t.Errorf("query should contain:\n%s\nbut got:\n%s", expected, q)
+		}
+	}
+}
+
+func TestGenerator_Generate_unlogged(t *testing.T) {
+	tbl := pqt.NewTable("event", pqt.WithUnlogged()).
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey()))
+
+	q, err := pqtsql.NewGenerator().Generate(pqt.NewSchema("example").AddTable(tbl))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if expected := "CREATE UNLOGGED TABLE example.event (\n"; !strings.Contains(string(q), expected) {
+		t.Errorf("query should contain:\n%s\nbut got:\n%s", expected, q)
+	}
+
+	tbl = pqt.NewTable("event", pqt.WithUnlogged(), pqt.WithTemp()).
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey()))
+	if _, err = pqtsql.NewGenerator().Generate(pqt.NewSchema("example").AddTable(tbl)); err == nil {
+		t.Error("expected error for table that is both temporary and unlogged")
+	}
+}
+
+func TestGenerator_Generate_deferrableReference(t *testing.T) {
+	category := pqt.NewTable("category").AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey()))
+	news := pqt.NewTable("news").
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+		AddColumn(pqt.NewColumn("category_id", pqt.TypeIntegerBig(), pqt.WithReference(category.Columns[0]), pqt.WithOnDelete(pqt.Cascade), pqt.WithDeferrableReference(pqt.DeferrableDeferred)))
+	s := pqt.NewSchema("example").AddTable(category).AddTable(news)
+
+	q, err := pqtsql.NewGenerator().Generate(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := `CONSTRAINT "example.news_category_id_fkey" FOREIGN KEY (category_id) REFERENCES example.category (id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED`
+	if !strings.Contains(string(q), expected) {
+		t.Errorf("query should contain:\n%s\nbut got:\n%s", expected, q)
+	}
+	if news.Columns[1].DeferrableInitiallyDeferred {
+		t.Error("column should not keep deferrable flag once constraint is created")
+	}
+}
+
+func TestGenerator_Generate_deferrableRelationship(t *testing.T) {
+	category := pqt.NewTable("category").AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey()))
+	news := pqt.NewTable("news").
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+		AddRelationship(pqt.ManyToOne(category), pqt.WithOnDelete(pqt.Cascade), pqt.WithDeferrableReference(pqt.DeferrableImmediate))
+	s := pqt.NewSchema("example").AddTable(category).AddTable(news)
+
+	q, err := pqtsql.NewGenerator().Generate(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := `CONSTRAINT "example.news_category_id_fkey" FOREIGN KEY (category_id) REFERENCES example.category (id) ON DELETE CASCADE DEFERRABLE INITIALLY IMMEDIATE`
+	if !strings.Contains(string(q), expected) {
+		t.Errorf("query should contain:\n%s\nbut got:\n%s", expected, q)
+	}
+}
+
+func TestGenerator_Generate_identity(t *testing.T) {
+	tbl := pqt.NewTable("news").
+		AddColumn(pqt.NewColumn("id", pqt.TypeIntegerBig(), pqt.WithPrimaryKey(), pqt.WithIdentity(pqt.IdentityAlways))).
+		AddColumn(pqt.NewColumn("position", pqt.TypeInteger(), pqt.WithIdentity(pqt.IdentityByDefault)))
+
+	q, err := pqtsql.NewGenerator().Generate(pqt.NewSchema("example").AddTable(tbl))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := `-- do not modify, generated by pqt
+
+CREATE SCHEMA IF NOT EXISTS example;
+
+CREATE TABLE example.news (
+	id BIGINT GENERATED ALWAYS AS IDENTITY,
+	position INTEGER GENERATED BY DEFAULT AS IDENTITY,
+
+	CONSTRAINT "example.news_id_pkey" PRIMARY KEY (id)
+);
+
+`
+	if string(q) != expected {
+		t.Errorf("wrong query, expected:\n'%s'\nbut got:\n'%s'", expected, q)
+	}
+
+	invalid := pqt.NewTable("news").AddColumn(pqt.NewColumn("id", pqt.TypeText(), pqt.WithIdentity(pqt.IdentityAlways)))
+	if _, err := pqtsql.NewGenerator().Generate(pqt.NewSchema("example").AddTable(invalid)); err == nil {
+		t.Error("expected error, identity column has to be of integer type")
+	}
+}
+
+func TestGenerator_Generate_storageParameters(t *testing.T) {
+	tbl := pqt.NewTable("news",
+		pqt.WithStorageParam("fillfactor", "70"),
+		pqt.WithStorageParam("autovacuum_enabled", "false"),
+		pqt.WithStorageParam("toast.autovacuum_vacuum_cost_delay", "20"),
+	).AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig()))
+
+	q, err := pqtsql.NewGenerator().Generate(pqt.NewSchema("example").AddTable(tbl))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := `-- do not modify, generated by pqt
+
+CREATE SCHEMA IF NOT EXISTS example;
+
+CREATE TABLE example.news (
+	id BIGSERIAL
+) WITH (fillfactor=70, autovacuum_enabled=false, toast.autovacuum_vacuum_cost_delay=20);
+
+`
+	if string(q) != expected {
+		t.Errorf("wrong query, expected:\n'%s'\nbut got:\n'%s'", expected, q)
+	}
+}
+
+func TestGenerator_Generate_dropIfExists(t *testing.T) {
+	userID := pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())
+	s := pqt.NewSchema("example").
+		AddTable(pqt.NewTable("user").AddColumn(userID)).
+		AddTable(pqt.NewTable("news").
+			AddColumn(pqt.NewColumn("author_id", pqt.TypeIntegerBig(), pqt.WithReference(userID))),
+		)
+	s.Types = append(s.Types, pqt.TypeEnumerated("example.status", "active", "inactive"), pqt.TypeDomain("example.email", pqt.TypeText(), ""))
+
+	q, err := pqtsql.NewGenerator().SetDropIfExists(true).Generate(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := `-- do not modify, generated by pqt
+
+DROP TABLE IF EXISTS example.news CASCADE;
+DROP TABLE IF EXISTS example."user" CASCADE;
+DROP DOMAIN IF EXISTS example.email CASCADE;
+DROP TYPE IF EXISTS example.status CASCADE;
+
+CREATE SCHEMA IF NOT EXISTS example;
+
+CREATE TYPE example.status AS ENUM ('active', 'inactive');
+`
+	if !strings.HasPrefix(string(q), expected) {
+		t.Errorf("wrong query, expected to start with:\n'%s'\nbut got:\n'%s'", expected, q)
+	}
+}
+
+func TestGenerator_Generate_index(t *testing.T) {
+	title := pqt.NewColumn("title", pqt.TypeText())
+	tags := pqt.NewColumn("tags", pqt.TypeTextArray(0))
+	deletedAt := pqt.NewColumn("deleted_at", pqt.TypeTimestampTZ())
+	news := pqt.NewTable("news").AddColumn(title).AddColumn(tags).AddColumn(deletedAt)
+	news.AddConstraint(pqt.NewIndex(news, "", pqt.Columns{title}))
+	news.AddConstraint(pqt.NewIndex(news, "news_tags_idx", pqt.Columns{tags}, pqt.WithIndexMethod("gin"), pqt.WithIndexConcurrently()))
+	news.AddConstraint(pqt.NewIndex(news, "news_title_active_idx", pqt.Columns{title}, pqt.WithIndexUnique(), pqt.WithIndexWhere("deleted_at IS NULL")))
+
+	q, err := pqtsql.NewGenerator().Generate(pqt.NewSchema("example").AddTable(news))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := `-- do not modify, generated by pqt
+
+CREATE SCHEMA IF NOT EXISTS example;
+
+CREATE TABLE example.news (
+	deleted_at TIMESTAMPTZ,
+	tags TEXT[],
+	title TEXT
+);
+CREATE INDEX "example.news_title_idx" ON example.news (title);
+CREATE INDEX CONCURRENTLY "news_tags_idx" ON example.news USING gin (tags);
+CREATE UNIQUE INDEX "news_title_active_idx" ON example.news (title) WHERE deleted_at IS NULL;
+
+`
+	if string(q) != expected {
+		t.Errorf("wrong query, expected:\n'%s'\nbut got:\n'%s'", expected, q)
+	}
+}
+
+func TestGenerator_Generate_materializedView(t *testing.T) {
+	name := pqt.NewColumn("name", pqt.TypeText(), pqt.WithComment("Name of the author."))
+	stats := pqt.NewMaterializedView("author_stats", "SELECT name, count(*) AS total FROM example.news GROUP BY name;",
+		name,
+		pqt.NewColumn("total", pqt.TypeIntegerBig()),
+	)
+	stats.Comment = "Number of news per author."
+	stats.AddConstraint(pqt.NewIndex(stats, "author_stats_name_idx", pqt.Columns{name}, pqt.WithIndexUnique()))
+
+	q, err := pqtsql.NewGenerator().Generate(pqt.NewSchema("example").AddTable(stats))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := `-- do not modify, generated by pqt
+
+CREATE SCHEMA IF NOT EXISTS example;
+
+CREATE MATERIALIZED VIEW example.author_stats AS
+SELECT name, count(*) AS total FROM example.news GROUP BY name;
+CREATE UNIQUE INDEX "author_stats_name_idx" ON example.author_stats (name);
+COMMENT ON MATERIALIZED VIEW example.author_stats IS 'Number of news per author.';
+COMMENT ON COLUMN example.author_stats.name IS 'Name of the author.';
+
+`
+	if string(q) != expected {
+		t.Errorf("wrong query, expected:\n'%s'\nbut got:\n'%s'", expected, q)
+	}
+
+	stats.AddConstraint(pqt.Unique(stats, name))
+	if _, err := pqtsql.NewGenerator().Generate(pqt.NewSchema("example").AddTable(stats)); err == nil {
+		t.Error("expected error, materialized view cannot have unique constraint")
+	}
+}
+
+func TestGenerator_Generate_enumIfNotExists(t *testing.T) {
+	s := pqt.NewSchema("example", pqt.WithSchemaIfNotExists())
+	s.Types = append(s.Types, pqt.TypeEnumerated("example.status", "active", "inactive"))
+
+	q, err := pqtsql.NewGenerator().Generate(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := `-- do not modify, generated by pqt
+
+CREATE SCHEMA IF NOT EXISTS example;
+
+DO $$
+BEGIN
+	CREATE TYPE example.status AS ENUM ('active', 'inactive');
+EXCEPTION
+	WHEN duplicate_object THEN NULL;
+END
+$$;
+ALTER TYPE example.status ADD VALUE IF NOT EXISTS 'active';
+ALTER TYPE example.status ADD VALUE IF NOT EXISTS 'inactive';
+
+`
+	if string(q) != expected {
+		t.Errorf("wrong query, expected:\n'%s'\nbut got:\n'%s'", expected, q)
+	}
+}
+
+func TestGenerator_Generate_domainIfNotExists(t *testing.T) {
+	s := pqt.NewSchema("example", pqt.WithSchemaIfNotExists())
+	s.Types = append(s.Types, pqt.TypeDomain("example.quantity", pqt.TypeInteger(), ""))
+
+	q, err := pqtsql.NewGenerator().Generate(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := `-- do not modify, generated by pqt
+
+CREATE SCHEMA IF NOT EXISTS example;
+
+DO $$
+BEGIN
+	CREATE DOMAIN example.quantity AS INTEGER;
+EXCEPTION
+	WHEN duplicate_object THEN NULL;
+END
+$$;
+
+`
+	if string(q) != expected {
+		t.Errorf("wrong query, expected:\n'%s'\nbut got:\n'%s'", expected, q)
+	}
+}
@@ -0,0 +1,23 @@
+package pqt
+
+// RangeBounds tells which bounds of BETWEEN criteria are inclusive, by default both are exclusive.
+// Generated criteria hold it next to each timestamp column, so every query can choose between [a,b], [a,b), (a,b] and (a,b).
+type RangeBounds struct {
+	LowerInclusive, UpperInclusive bool
+}
+
+// LowerOperator returns operator that compares value with the lower bound, ">=" if it is inclusive.
+func (rb RangeBounds) LowerOperator() string {
+	if rb.LowerInclusive {
+		return ">="
+	}
+	return ">"
+}
+
+// UpperOperator returns operator that compares value with the upper bound, "<=" if it is inclusive.
+func (rb RangeBounds) UpperOperator() string {
+	if rb.UpperInclusive {
+		return "<="
+	}
+	return "<"
+}
@@ -0,0 +1,27 @@
+package pqt
+
+import (
+	"testing"
+)
+
+func TestRangeBounds(t *testing.T) {
+	cases := map[string]struct {
+		bounds       RangeBounds
+		lower, upper string
+	}{
+		"(a,b)": {bounds: RangeBounds{}, lower: ">", upper: "<"},
+		"[a,b)": {bounds: RangeBounds{LowerInclusive: true}, lower: ">=", upper: "<"},
+		"(a,b]": {bounds: RangeBounds{UpperInclusive: true}, lower: ">", upper: "<="},
+		"[a,b]": {bounds: RangeBounds{LowerInclusive: true, UpperInclusive: true}, lower: ">=", upper: "<="},
+	}
+	for hint, c := range cases {
+		t.Run(hint, func(t *testing.T) {
+			if got := c.bounds.LowerOperator(); got != c.lower {
+				t.Errorf("wrong lower operator, expected %q but got %q", c.lower, got)
+			}
+			if got := c.bounds.UpperOperator(); got != c.upper {
+				t.Errorf("wrong upper operator, expected %q but got %q", c.upper, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package pqt
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// BrokenReference describes foreign key that cannot be created, because it points to a table or column that does not exist.
+type BrokenReference struct {
+	Table, Constraint, Reason string
+}
+
+// SchemaValidationError is returned by SchemaRegistry.Validate, it lists every broken reference found, not only the first one.
+type SchemaValidationError struct {
+	References []BrokenReference
+}
+
+// Error implements error interface.
+func (e *SchemaValidationError) Error() string {
+	buf := bytes.NewBufferString("pqt: schema validation failure: ")
+	for i, r := range e.References {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(r.Constraint)
+		buf.WriteString(" of ")
+		buf.WriteString(r.Table)
+		buf.WriteString(" ")
+		buf.WriteString(r.Reason)
+	}
+
+	return buf.String()
+}
+
+// SchemaRegistry accumulates tables, possibly defined in different places, and validates references between them.
+// It allows to catch modelling mistakes while the code is generated, instead of when the database rejects the DDL.
+type SchemaRegistry struct {
+	tables []*Table
+}
+
+// NewSchemaRegistry allocates new empty SchemaRegistry.
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{}
+}
+
+// AddTable registers given tables.
+func (r *SchemaRegistry) AddTable(tables ...*Table) *SchemaRegistry {
+	r.tables = append(r.tables, tables...)
+
+	return r
+}
+
+// Tables returns registered tables in order of registration.
+func (r *SchemaRegistry) Tables() []*Table {
+	return r.tables
+}
+
+// Validate checks that each foreign key of registered tables points to a registered table,
+// that referenced columns exist in that table and that their types match types of referencing columns.
+// Serial types are compatible with corresponding integer types, the same way they are mapped by relationships.
+// If any reference is broken, *SchemaValidationError is returned.
+func (r *SchemaRegistry) Validate() error {
+	registered := make(map[*Table]bool, len(r.tables))
+	for _, t := range r.tables {
+		registered[t] = true
+	}
+
+	var broken []BrokenReference
+	for _, t := range r.tables {
+		// Foreign keys of columns created by relationships are not part of table constraints.
+		constraints := append([]*Constraint(nil), t.Constraints...)
+		for _, col := range t.Columns {
+			constraints = append(constraints, col.Constraints()...)
+		}
+		for _, c := range constraints {
+			if c.Type != ConstraintTypeForeignKey {
+				continue
+			}
+			for _, reason := range referenceViolations(c, registered) {
+				broken = append(broken, BrokenReference{
+					Table:      t.FullName(),
+					Constraint: c.String(),
+					Reason:     reason,
+				})
+			}
+		}
+	}
+	if len(broken) > 0 {
+		return &SchemaValidationError{References: broken}
+	}
+
+	return nil
+}
+
+func referenceViolations(c *Constraint, registered map[*Table]bool) []string {
+	switch {
+	case c.ReferenceTable == nil:
+		return []string{"references no table"}
+	case !registered[c.ReferenceTable]:
+		return []string{fmt.Sprintf("references table %s that is not registered", c.ReferenceTable.FullName())}
+	case len(c.Columns) != len(c.ReferenceColumns):
+		return []string{fmt.Sprintf("has %d columns, but references %d", len(c.Columns), len(c.ReferenceColumns))}
+	}
+
+	var reasons []string
+	for i, ref := range c.ReferenceColumns {
+		var found *Column
+		for _, col := range c.ReferenceTable.Columns {
+			if col.Name == ref.Name {
+				found = col
+				break
+			}
+		}
+		if found == nil {
+			reasons = append(reasons, fmt.Sprintf("references column %s that does not exist in table %s", ref.Name, c.ReferenceTable.FullName()))
+			continue
+		}
+		col := c.Columns[i]
+		if col.Type == nil || found.Type == nil {
+			continue
+		}
+		if fkType(col.Type).Fingerprint() != fkType(found.Type).Fingerprint() {
+			reasons = append(reasons, fmt.Sprintf("column %s of type %s references column %s.%s of type %s", col.Name, col.Type, c.ReferenceTable.FullName(), found.Name, found.Type))
+		}
+	}
+
+	return reasons
+}
@@ -0,0 +1,67 @@
+package pqt_test
+
+import (
+	"testing"
+
+	"github.com/piotrkowalczuk/pqt"
+)
+
+func TestSchemaRegistry_Validate(t *testing.T) {
+	category := pqt.NewTable("category").
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+		AddColumn(pqt.NewColumn("name", pqt.TypeText()))
+	news := pqt.NewTable("news").
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+		AddColumn(pqt.NewColumn("category_id", pqt.TypeIntegerBig(), pqt.WithReference(category.Columns[0]))).
+		AddRelationship(pqt.ManyToOne(pqt.SelfReference(), pqt.WithColumnName("parent_id")))
+
+	if err := pqt.NewSchemaRegistry().AddTable(category, news).Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+}
+
+func TestSchemaRegistry_Validate_broken(t *testing.T) {
+	user := pqt.NewTable("user").
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+		AddColumn(pqt.NewColumn("username", pqt.TypeText()))
+	category := pqt.NewTable("category").
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey()))
+	news := pqt.NewTable("news").
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+		AddColumn(pqt.NewColumn("author", pqt.TypeInteger(), pqt.WithReference(user.Columns[1]))).
+		AddRelationship(pqt.ManyToOne(category))
+
+	err := pqt.NewSchemaRegistry().AddTable(user, news).Validate()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	verr, ok := err.(*pqt.SchemaValidationError)
+	if !ok {
+		t.Fatalf("wrong error type: %T", err)
+	}
+	expected := []pqt.BrokenReference{
+		{Table: "news", Constraint: "public.news_author_fkey", Reason: "column author of type INTEGER references column user.username of type TEXT"},
+		{Table: "news", Constraint: "public.news_category_id_fkey", Reason: "references table category that is not registered"},
+	}
+	if len(verr.References) != len(expected) {
+		t.Fatalf("wrong number of broken references, expected %d but got %d: %s", len(expected), len(verr.References), err.Error())
+	}
+	for i, r := range expected {
+		if verr.References[i] != r {
+			t.Errorf("wrong broken reference at %d, expected %#v but got %#v", i, r, verr.References[i])
+		}
+	}
+}
+
+func TestSchemaValidationError_Error(t *testing.T) {
+	err := &pqt.SchemaValidationError{
+		References: []pqt.BrokenReference{
+			{Table: "news", Constraint: "public.news_category_id_fkey", Reason: "references table category that is not registered"},
+			{Table: "news", Constraint: "public.news_author_fkey", Reason: "references column uid that does not exist in table user"},
+		},
+	}
+	expected := "pqt: schema validation failure: public.news_category_id_fkey of news references table category that is not registered, public.news_author_fkey of news references column uid that does not exist in table user"
+	if got := err.Error(); got != expected {
+		t.Errorf("wrong message, expected %s but got %s", expected, got)
+	}
+}
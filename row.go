@@ -10,15 +10,36 @@ const (
 	EventInsert Event = "INSERT"
 	// EventUpdate ...
 	EventUpdate Event = "UPDATE"
+
+	// SoftDeleteColumn is the name of the column added by WithSoftDelete table option.
+	SoftDeleteColumn = "deleted_at"
+
+	// GeneratedStored is computed when it is written (inserted or updated) and occupies storage as if it were a normal column.
+	GeneratedStored Generation = "STORED"
+
+	// IdentityAlways makes database always generate the value, explicit one is accepted only with OVERRIDING SYSTEM VALUE.
+	IdentityAlways Identity = "ALWAYS"
+	// IdentityByDefault makes database generate the value unless explicit one is given.
+	IdentityByDefault Identity = "BY DEFAULT"
 )
 
 // Event ...
 type Event string
 
+// Generation describes how generated column is computed.
+type Generation string
+
+// Identity describes when identity column gets its value from the implicit sequence.
+type Identity string
+
 // Column ...
 type Column struct {
-	Name, ShortName, Collate, Check                                      string
+	Name, ShortName, Collate, Check, CheckName, RenamedFrom, Comment     string
+	UniqueWhere                                                          string
 	Default                                                              map[Event]string
+	Generated                                                            string
+	Generation                                                           Generation
+	Identity                                                             Identity
 	NotNull, Unique, PrimaryKey                                          bool
 	Type                                                                 Type
 	Table                                                                *Table
@@ -57,14 +78,16 @@ func (c *Column) Constraints() []*Constraint {
 			Type:    ConstraintTypeUnique,
 			Columns: Columns{c},
 			Table:   c.Table,
+			Where:   c.UniqueWhere,
 		})
 	}
 	if c.Check != "" {
 		cs = append(cs, &Constraint{
-			Type:    ConstraintTypeCheck,
-			Check:   c.Check,
-			Columns: Columns{c},
-			Table:   c.Table,
+			Type:       ConstraintTypeCheck,
+			Check:      c.Check,
+			Identifier: c.CheckName,
+			Columns:    Columns{c},
+			Table:      c.Table,
 		})
 	}
 	if c.Reference != nil {
@@ -74,6 +97,12 @@ func (c *Column) Constraints() []*Constraint {
 			ReferenceColumns: Columns{c.Reference},
 			ReferenceTable:   c.Reference.Table,
 			Table:            c.Table,
+			OnDelete:         c.OnDelete,
+			OnUpdate:         c.OnUpdate,
+			Match:            c.Match,
+
+			DeferrableInitiallyDeferred:  c.DeferrableInitiallyDeferred,
+			DeferrableInitiallyImmediate: c.DeferrableInitiallyImmediate,
 		})
 	}
 
@@ -126,6 +155,17 @@ func (c Columns) String() string {
 	return b.String()
 }
 
+// Contains returns true if given column is part of the slice.
+func (c Columns) Contains(col *Column) bool {
+	for _, cc := range c {
+		if cc == col {
+			return true
+		}
+	}
+
+	return false
+}
+
 // JoinColumns ...
 func JoinColumns(columns Columns, sep string) string {
 	tmp := make([]string, 0, len(columns))
@@ -193,6 +233,15 @@ func WithCheck(ch string) ColumnOption {
 	}
 }
 
+// WithNamedCheck works like WithCheck, but the constraint is created using given name instead of generated one,
+// so that violation can be recognized using constant generated by pqtgo.
+func WithNamedCheck(name, expression string) ColumnOption {
+	return func(c *Column) {
+		c.Check = expression
+		c.CheckName = name
+	}
+}
+
 // WithUnique ...
 func WithUnique() ColumnOption {
 	return func(c *Column) {
@@ -200,6 +249,15 @@ func WithUnique() ColumnOption {
 	}
 }
 
+// WithConditionalUnique works like WithUnique, but uniqueness is enforced only for rows that satisfy given predicate.
+// For example, "deleted_at IS NULL" makes the column unique among rows that are not soft-deleted.
+func WithConditionalUnique(predicate string) ColumnOption {
+	return func(c *Column) {
+		c.Unique = true
+		c.UniqueWhere = predicate
+	}
+}
+
 // WithPrimaryKey ...
 func WithPrimaryKey() ColumnOption {
 	return func(c *Column) {
@@ -231,6 +289,22 @@ func WithDefault(d string, e ...Event) ColumnOption {
 	}
 }
 
+// WithGenerated marks column as generated, it can not be written to, instead its value is computed from given expression.
+func WithGenerated(expr string, g Generation) ColumnOption {
+	return func(c *Column) {
+		c.Generated = expr
+		c.Generation = g
+	}
+}
+
+// WithIdentity marks column as identity column (GENERATED ... AS IDENTITY), modern alternative to serial types.
+// Column has to be of integer type, its value is generated by the database unless given explicitly.
+func WithIdentity(i Identity) ColumnOption {
+	return func(c *Column) {
+		c.Identity = i
+	}
+}
+
 // WithNotNull ...
 func WithNotNull() ColumnOption {
 	return func(c *Column) {
@@ -247,6 +321,7 @@ func WithReference(r *Column, opts ...RelationshipOption) ColumnOption {
 }
 
 // WithOnDelete add ON DELETE clause that specifies the action to perform when a referenced row in the referenced table is being deleted
+// Accepted actions are NoAction, Restrict, Cascade, SetNull and SetDefault, if not set the clause is omitted.
 func WithOnDelete(on int32) ColumnOption {
 	return func(c *Column) {
 		c.OnDelete = on
@@ -260,6 +335,36 @@ func WithOnUpdate(on int32) ColumnOption {
 	}
 }
 
+// WithDeferrableReference makes the foreign key constraint created from the column reference deferrable.
+// It is checked as given mode says, see WithDeferrable.
+func WithDeferrableReference(initially DeferrableMode) ColumnOption {
+	return func(c *Column) {
+		c.DeferrableInitiallyDeferred = initially == DeferrableDeferred
+		c.DeferrableInitiallyImmediate = initially == DeferrableImmediate
+	}
+}
+
+// WithColumnComment sets comment stored in the database using COMMENT ON COLUMN statement.
+// Generated Go code uses it as a doc comment of the entity property.
+func WithColumnComment(text string) ColumnOption {
+	return func(c *Column) {
+		c.Comment = text
+	}
+}
+
+// WithComment is an alias of WithColumnComment.
+func WithComment(text string) ColumnOption {
+	return WithColumnComment(text)
+}
+
+// WithRenamedFrom marks column as a renamed version of the column with given name.
+// It is used by Diff, that otherwise would not be able to distinguish rename from drop and add of the column.
+func WithRenamedFrom(name string) ColumnOption {
+	return func(c *Column) {
+		c.RenamedFrom = name
+	}
+}
+
 // WithColumnShortName ...
 func WithColumnShortName(s string) ColumnOption {
 	return func(c *Column) {
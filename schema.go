@@ -1,5 +1,14 @@
 package pqt
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
 // Schema ...
 type Schema struct {
 	Name        string
@@ -46,3 +55,145 @@ func WithSchemaIfNotExists() SchemaOption {
 		s.IfNotExists = true
 	}
 }
+
+// EnumeratedTypes returns enumerated types used by the schema, both registered explicitly and used by columns.
+// Each type is returned once, in order of appearance.
+func (s *Schema) EnumeratedTypes() []EnumeratedType {
+	var (
+		enums []EnumeratedType
+		seen  = make(map[string]struct{})
+	)
+	add := func(t Type) {
+		if mt, ok := t.(MappableType); ok {
+			t = mt.From
+		}
+		if et, ok := t.(EnumeratedType); ok {
+			if _, ok := seen[et.String()]; !ok {
+				seen[et.String()] = struct{}{}
+				enums = append(enums, et)
+			}
+		}
+	}
+
+	for _, t := range s.Types {
+		add(t)
+	}
+	for _, t := range s.Tables {
+		for _, c := range t.Columns {
+			add(c.Type)
+		}
+	}
+
+	return enums
+}
+
+// DomainTypes returns domain types used by the schema, both registered explicitly and used by columns.
+// Each type is returned once, in order of appearance.
+func (s *Schema) DomainTypes() []DomainType {
+	var (
+		domains []DomainType
+		seen    = make(map[string]struct{})
+	)
+	add := func(t Type) {
+		if mt, ok := t.(MappableType); ok {
+			t = mt.From
+		}
+		if dt, ok := t.(DomainType); ok {
+			if _, ok := seen[dt.String()]; !ok {
+				seen[dt.String()] = struct{}{}
+				domains = append(domains, dt)
+			}
+		}
+	}
+
+	for _, t := range s.Types {
+		add(t)
+	}
+	for _, t := range s.Tables {
+		for _, c := range t.Columns {
+			add(c.Type)
+		}
+	}
+
+	return domains
+}
+
+// Fingerprint returns hash of canonical definition of the schema, that includes tables, columns, constraints and enumerated types.
+// Order in which tables and columns are defined does not matter, Go specific type mappings are ignored.
+// It can be used to detect that schema definition changed, for example by comparing it with value stored during deployment.
+func (s *Schema) Fingerprint() string {
+	h := sha256.New()
+
+	fmt.Fprintf(h, "schema %s\n", s.Name)
+	for _, et := range s.EnumeratedTypes() {
+		fmt.Fprintf(h, "enum %s (%s)\n", et.String(), strings.Join(et.Enums, ", "))
+	}
+	for _, dt := range s.DomainTypes() {
+		fmt.Fprintf(h, "domain %s %s check (%s)\n", dt.String(), dt.Base.String(), dt.Check)
+	}
+
+	tables := make([]*Table, len(s.Tables))
+	copy(tables, s.Tables)
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].FullName() < tables[j].FullName()
+	})
+	for _, t := range tables {
+		fingerprintTable(h, t)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func fingerprintTable(w io.Writer, t *Table) {
+	fmt.Fprintf(w, "table %s temporary=%t\n", t.FullName(), t.Temporary)
+	// Written only if set, so that fingerprints of existing schemas do not change.
+	if t.Unlogged {
+		fmt.Fprint(w, "\tunlogged\n")
+	}
+	if t.IsPartition() {
+		fmt.Fprintf(w, "\tpartition of %s %s\n", t.PartitionOf.FullName(), t.PartitionBounds)
+	}
+	if t.PartitionStrategy != "" {
+		fmt.Fprintf(w, "\tpartition by %s (%s)\n", t.PartitionStrategy, strings.Join(t.PartitionColumns, ", "))
+	}
+
+	columns := make(Columns, len(t.Columns))
+	copy(columns, t.Columns)
+	sort.Sort(columns)
+	for _, c := range columns {
+		typ := c.Type
+		if mt, ok := typ.(MappableType); ok {
+			typ = mt.From
+		}
+		fmt.Fprintf(w, "\tcolumn %s %s not_null=%t primary_key=%t unique=%t", c.Name, typ.Fingerprint(), c.NotNull, c.PrimaryKey, c.Unique)
+		if d, ok := c.DefaultOn(EventInsert); ok {
+			fmt.Fprintf(w, " default=%s", d)
+		}
+		if c.Generated != "" {
+			fmt.Fprintf(w, " generated=%s %s", c.Generated, c.Generation)
+		}
+		if c.Check != "" {
+			fmt.Fprintf(w, " check=%s", c.Check)
+		}
+		if c.Collate != "" {
+			fmt.Fprintf(w, " collate=%s", c.Collate)
+		}
+		if c.Reference != nil && c.Reference.Table != nil {
+			fmt.Fprintf(w, " references=%s.%s", c.Reference.Table.FullName(), c.Reference.Name)
+		}
+		fmt.Fprintln(w)
+	}
+
+	constraints := make([]string, 0, len(t.Constraints))
+	for _, c := range t.Constraints {
+		constraint := fmt.Sprintf("\tconstraint %s %s (%s) check=%s where=%s", c.Name(), c.Type, JoinColumns(c.Columns, ", "), c.Check, c.Where)
+		if c.Type == ConstraintTypeExclusion {
+			constraint += fmt.Sprintf(" using=%s exclude=%v", c.Method, c.Exclude)
+		}
+		constraints = append(constraints, constraint)
+	}
+	sort.Strings(constraints)
+	for _, c := range constraints {
+		fmt.Fprintln(w, c)
+	}
+}
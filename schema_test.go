@@ -0,0 +1,59 @@
+package pqt_test
+
+import (
+	"testing"
+
+	"github.com/piotrkowalczuk/pqt"
+)
+
+func TestSchema_Fingerprint(t *testing.T) {
+	build := func(reverse bool, titleType pqt.Type) *pqt.Schema {
+		user := pqt.NewTable("user").
+			AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+			AddColumn(pqt.NewColumn("name", pqt.TypeText(), pqt.WithNotNull()))
+		news := pqt.NewTable("news").
+			AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())).
+			AddColumn(pqt.NewColumn("title", titleType))
+
+		if reverse {
+			return pqt.NewSchema("example").AddTable(news).AddTable(user)
+		}
+		return pqt.NewSchema("example").AddTable(user).AddTable(news)
+	}
+
+	expected := build(false, pqt.TypeText()).Fingerprint()
+	if expected == "" {
+		t.Fatal("fingerprint should not be empty")
+	}
+	if got := build(false, pqt.TypeText()).Fingerprint(); got != expected {
+		t.Errorf("fingerprint should be stable, expected %s but got %s", expected, got)
+	}
+	if got := build(true, pqt.TypeText()).Fingerprint(); got != expected {
+		t.Errorf("fingerprint should not depend on tables order, expected %s but got %s", expected, got)
+	}
+	if got := build(false, pqt.TypeVarchar(100)).Fingerprint(); got == expected {
+		t.Error("fingerprint should change if column type changes")
+	}
+	domain := build(false, pqt.TypeDomain("title", pqt.TypeText(), "")).Fingerprint()
+	if got := build(false, pqt.TypeDomain("title", pqt.TypeText(), "length(VALUE) > 0")).Fingerprint(); got == domain {
+		t.Error("fingerprint should change if domain check changes")
+	}
+}
+
+func TestSchema_DomainTypes(t *testing.T) {
+	email := pqt.TypeDomain("email", pqt.TypeText(), "VALUE ~ '@'")
+	s := pqt.NewSchema("example").AddTable(
+		pqt.NewTable("user").
+			AddColumn(pqt.NewColumn("email", email)).
+			AddColumn(pqt.NewColumn("backup_email", email)),
+	)
+	s.Types = append(s.Types, pqt.TypeDomain("quantity", pqt.TypeInteger(), ""))
+
+	got := s.DomainTypes()
+	if len(got) != 2 {
+		t.Fatalf("wrong number of domains, expected 2 but got %d", len(got))
+	}
+	if got[0].String() != "quantity" || got[1].String() != "email" {
+		t.Errorf("wrong domains: %v", got)
+	}
+}
@@ -0,0 +1,187 @@
+package pqt
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SortFunctions lists functions that can be called by SortExpr expression, keyed by lower case name.
+// It can be extended, but only with functions that have no side effects.
+var SortFunctions = map[string]bool{
+	"row_number":   true,
+	"rank":         true,
+	"dense_rank":   true,
+	"percent_rank": true,
+	"cume_dist":    true,
+	"ntile":        true,
+	"lag":          true,
+	"lead":         true,
+	"first_value":  true,
+	"last_value":   true,
+	"nth_value":    true,
+	"count":        true,
+	"sum":          true,
+	"avg":          true,
+	"min":          true,
+	"max":          true,
+	"abs":          true,
+	"lower":        true,
+	"upper":        true,
+	"length":       true,
+	"coalesce":     true,
+	"nullif":       true,
+	"greatest":     true,
+	"least":        true,
+}
+
+// sortKeywords lists keywords that can be used by SortExpr expression, e.g. to define a window.
+var sortKeywords = map[string]bool{
+	"over": true, "partition": true, "by": true, "order": true, "asc": true, "desc": true,
+	"nulls": true, "first": true, "last": true, "distinct": true,
+	"rows": true, "range": true, "groups": true, "between": true, "unbounded": true,
+	"preceding": true, "following": true, "current": true, "row": true,
+	"case": true, "when": true, "then": true, "else": true, "end": true,
+	"and": true, "or": true, "not": true, "is": true, "null": true, "true": true, "false": true,
+}
+
+// SortExpr is a single element of ORDER BY clause, either a column or an SQL expression,
+// e.g. ROW_NUMBER() OVER (PARTITION BY category_id ORDER BY created_at).
+type SortExpr struct {
+	// Column is a name of the column to sort by, it is ignored if Expr is set.
+	Column string
+	// Expr is an SQL expression to sort by, see Validate for what it can consist of.
+	Expr string
+	Desc bool
+}
+
+// Validate returns an error if sort expression is not safe to be embedded into a query.
+// Column has to be one of given columns. Expression can consist of those columns, numbers, operators,
+// parentheses, commas, keywords required to define a window or a condition, and calls of SortFunctions.
+// Anything else, e.g. string literals, comments, semicolons or sub-queries, is rejected.
+func (se SortExpr) Validate(columns []string) error {
+	if se.Expr == "" {
+		if !contains(columns, se.Column) {
+			return fmt.Errorf("pqt: unknown sort column %q", se.Column)
+		}
+		return nil
+	}
+	if strings.Contains(se.Expr, "--") || strings.Contains(se.Expr, "/*") {
+		return fmt.Errorf("pqt: sort expression %q cannot contain comments", se.Expr)
+	}
+
+	var depth int
+	expr := []rune(se.Expr)
+	for i := 0; i < len(expr); i++ {
+		r := expr[i]
+		switch {
+		case unicode.IsSpace(r):
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(expr) && (expr[j] == '_' || unicode.IsLetter(expr[j]) || unicode.IsDigit(expr[j])) {
+				j++
+			}
+			ident := string(expr[i:j])
+			next := j
+			for next < len(expr) && unicode.IsSpace(expr[next]) {
+				next++
+			}
+			switch {
+			case sortKeywords[strings.ToLower(ident)]:
+			case next < len(expr) && expr[next] == '(':
+				if !SortFunctions[strings.ToLower(ident)] {
+					return fmt.Errorf("pqt: sort function %q is not allowed", ident)
+				}
+			case !contains(columns, ident):
+				return fmt.Errorf("pqt: unknown sort identifier %q", ident)
+			}
+			i = j - 1
+		case unicode.IsDigit(r):
+			for i+1 < len(expr) && (unicode.IsDigit(expr[i+1]) || expr[i+1] == '.') {
+				i++
+			}
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth--; depth < 0 {
+				return fmt.Errorf("pqt: sort expression %q has unbalanced parentheses", se.Expr)
+			}
+		case strings.ContainsRune(",+-*/%<>=", r):
+		default:
+			return fmt.Errorf("pqt: sort expression %q contains not allowed character %q", se.Expr, r)
+		}
+	}
+	if depth != 0 {
+		return fmt.Errorf("pqt: sort expression %q has unbalanced parentheses", se.Expr)
+	}
+
+	return nil
+}
+
+// Qualify returns copy of the sort expression whose column identifiers are prefixed with given alias,
+// so that they are not ambiguous with columns of joined relations. Keywords and function names are left as they are.
+// Expression is expected to be validated first, qualified one no longer passes Validate.
+func (se SortExpr) Qualify(alias string) SortExpr {
+	if se.Expr == "" {
+		se.Column = alias + "." + se.Column
+		return se
+	}
+
+	var buf strings.Builder
+	expr := []rune(se.Expr)
+	for i := 0; i < len(expr); i++ {
+		r := expr[i]
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(expr) && (expr[j] == '_' || unicode.IsLetter(expr[j]) || unicode.IsDigit(expr[j])) {
+				j++
+			}
+			ident := string(expr[i:j])
+			next := j
+			for next < len(expr) && unicode.IsSpace(expr[next]) {
+				next++
+			}
+			if !sortKeywords[strings.ToLower(ident)] && (next == len(expr) || expr[next] != '(') {
+				buf.WriteString(alias)
+				buf.WriteRune('.')
+			}
+			buf.WriteString(ident)
+			i = j - 1
+		case unicode.IsDigit(r):
+			// Digits that follow are part of the number, not an identifier.
+			for i < len(expr) && (unicode.IsDigit(expr[i]) || expr[i] == '.') {
+				buf.WriteRune(expr[i])
+				i++
+			}
+			i--
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	se.Expr = buf.String()
+
+	return se
+}
+
+// String returns ORDER BY element, without validation.
+func (se SortExpr) String() string {
+	s := se.Column
+	if se.Expr != "" {
+		s = se.Expr
+	}
+	if se.Desc {
+		return s + " DESC"
+	}
+
+	return s
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package pqt
+
+import (
+	"testing"
+)
+
+func TestSortExpr_Validate(t *testing.T) {
+	columns := []string{"id", "category_id", "created_at"}
+	valid := map[string]SortExpr{
+		"column":         {Column: "created_at"},
+		"window":         {Expr: "ROW_NUMBER() OVER (PARTITION BY category_id ORDER BY created_at DESC)"},
+		"window-frame":   {Expr: "sum(id) OVER (ORDER BY created_at ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)"},
+		"arithmetic":     {Expr: "abs(id - 10) * 2.5"},
+		"case":           {Expr: "CASE WHEN category_id IS NULL THEN 1 ELSE 0 END"},
+		"function-space": {Expr: "coalesce (category_id, 0)"},
+	}
+	for hint, se := range valid {
+		t.Run(hint, func(t *testing.T) {
+			if err := se.Validate(columns); err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+			}
+		})
+	}
+
+	invalid := map[string]SortExpr{
+		"unknown-column":   {Column: "password"},
+		"unknown-function": {Expr: "pg_sleep(10)"},
+		"unknown-ident":    {Expr: "password"},
+		"sub-query":        {Expr: "(SELECT id FROM secret LIMIT 1)"},
+		"literal":          {Expr: "coalesce(category_id, '1')"},
+		"statement":        {Expr: "id; DROP TABLE news"},
+		"comment":          {Expr: "id -- comment"},
+		"block-comment":    {Expr: "id /* comment */"},
+		"qualified":        {Expr: "news.id"},
+		"cast":             {Expr: "id::text"},
+		"unbalanced":       {Expr: "count(id"},
+		"unbalanced-close": {Expr: "count(id))"},
+	}
+	for hint, se := range invalid {
+		t.Run(hint, func(t *testing.T) {
+			if err := se.Validate(columns); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestSortExpr_Qualify(t *testing.T) {
+	cases := map[string]SortExpr{
+		"t0.created_at": {Column: "created_at"},
+		"t0.id DESC":    {Column: "id", Desc: true},
+		"ROW_NUMBER() OVER (PARTITION BY t0.category_id ORDER BY t0.created_at DESC)": {Expr: "ROW_NUMBER() OVER (PARTITION BY category_id ORDER BY created_at DESC)"},
+		"abs(t0.id - 10) * 2.5":                              {Expr: "abs(id - 10) * 2.5"},
+		"CASE WHEN t0.category_id IS NULL THEN 1 ELSE 0 END": {Expr: "CASE WHEN category_id IS NULL THEN 1 ELSE 0 END"},
+		"coalesce (t0.category_id, 0) DESC":                  {Expr: "coalesce (category_id, 0)", Desc: true},
+	}
+	for expected, se := range cases {
+		if got := se.Qualify("t0").String(); got != expected {
+			t.Errorf("wrong qualified sort element, expected %q but got %q", expected, got)
+		}
+	}
+}
+
+func TestSortExpr_String(t *testing.T) {
+	cases := map[string]SortExpr{
+		"id":                   {Column: "id"},
+		"id DESC":              {Column: "id", Desc: true},
+		"ROW_NUMBER() OVER ()": {Column: "id", Expr: "ROW_NUMBER() OVER ()"},
+		"rank() OVER () DESC":  {Expr: "rank() OVER ()", Desc: true},
+	}
+	for expected, se := range cases {
+		if got := se.String(); got != expected {
+			t.Errorf("wrong sort element, expected %q but got %q", expected, got)
+		}
+	}
+}
@@ -1,18 +1,67 @@
 package pqt
 
-import "sort"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// PartitionStrategyRange partitions table into ranges defined by a key column or set of columns.
+	PartitionStrategyRange PartitionStrategy = "RANGE"
+	// PartitionStrategyList partitions table by explicitly listing which key values appear in each partition.
+	PartitionStrategyList PartitionStrategy = "LIST"
+	// PartitionStrategyHash partitions table by specifying a modulus and a remainder for each partition.
+	PartitionStrategyHash PartitionStrategy = "HASH"
+
+	// PartitionBoundsDefault creates default partition, that holds rows that do not fit into any other partition.
+	PartitionBoundsDefault PartitionBounds = "DEFAULT"
+)
+
+// PartitionStrategy represents method of table partitioning.
+type PartitionStrategy string
+
+// PartitionBounds represents partition bound specification of a child table, for example "FOR VALUES IN ('a', 'b')".
+type PartitionBounds string
+
+// PartitionBoundsRange returns bounds for a partition of a range partitioned table.
+// Lower bound is inclusive, upper bound is exclusive.
+func PartitionBoundsRange(from, to string) PartitionBounds {
+	return PartitionBounds(fmt.Sprintf("FOR VALUES FROM (%s) TO (%s)", from, to))
+}
+
+// PartitionBoundsList returns bounds for a partition of a list partitioned table.
+func PartitionBoundsList(values ...string) PartitionBounds {
+	return PartitionBounds(fmt.Sprintf("FOR VALUES IN (%s)", strings.Join(values, ", ")))
+}
+
+// PartitionBoundsHash returns bounds for a partition of a hash partitioned table.
+func PartitionBoundsHash(modulus, remainder int) PartitionBounds {
+	return PartitionBounds(fmt.Sprintf("FOR VALUES WITH (MODULUS %d, REMAINDER %d)", modulus, remainder))
+}
 
 // Table is partially implemented postgres table synopsis.
 type Table struct {
-	self                                 bool
-	Name, ShortName, Collate, TableSpace string
-	IfNotExists, Temporary               bool
-	Schema                               *Schema
-	Columns                              Columns
-	Constraints                          []*Constraint
-	OwnedRelationships                   []*Relationship
-	InversedRelationships                []*Relationship
-	ManyToManyRelationships              []*Relationship
+	self                                          bool
+	Name, ShortName, Collate, TableSpace, Comment string
+	IfNotExists, Temporary, Unlogged, SoftDelete  bool
+	SoftDeleteColumnName                          string
+	VersionColumnName                             string
+	Schema                                        *Schema
+	Namespace                                     string
+	PartitionStrategy                             PartitionStrategy
+	PartitionColumns                              []string
+	PartitionOf                                   *Table
+	PartitionBounds                               PartitionBounds
+	StorageParameters                             []StorageParameter
+	// Query is not empty if table is a materialized view, see NewMaterializedView.
+	Query                   string
+	Columns                 Columns
+	Returning               Columns
+	Constraints             []*Constraint
+	OwnedRelationships      []*Relationship
+	InversedRelationships   []*Relationship
+	ManyToManyRelationships []*Relationship
 }
 
 // NewTable allocates new table using given name and options.
@@ -33,6 +82,39 @@ func NewTable(name string, opts ...TableOption) *Table {
 	return t
 }
 
+// NewPartition allocates new table that is a partition of given parent table.
+// Partition inherits columns and constraints of the parent, so it does not define its own.
+func NewPartition(parent *Table, name string, bounds PartitionBounds, opts ...TableOption) *Table {
+	t := NewTable(name, opts...)
+	t.PartitionOf = parent
+	t.PartitionBounds = bounds
+
+	return t
+}
+
+// IsPartition returns true if table is a partition of another table.
+func (t *Table) IsPartition() bool {
+	return t.PartitionOf != nil
+}
+
+// NewMaterializedView allocates materialized view that holds result of given query, until it is refreshed.
+// Columns describe output of the query, they are not part of the view definition, but generated code relies on them.
+// Materialized view cannot have constraints, but it can have indexes, see NewIndex.
+func NewMaterializedView(name, query string, columns ...*Column) *Table {
+	t := NewTable(name)
+	t.Query = query
+	for _, c := range columns {
+		t.AddColumn(c)
+	}
+
+	return t
+}
+
+// IsMaterializedView returns true if table is a materialized view.
+func (t *Table) IsMaterializedView() bool {
+	return t.Query != ""
+}
+
 // SelfReference returns almost empty table that express self reference.
 // Should be used with relationships.
 func SelfReference() *Table {
@@ -43,13 +125,35 @@ func SelfReference() *Table {
 
 // FullName if schema is defined returns name in format <schema>.<name> or just <name> if not set.
 func (t *Table) FullName() string {
-	if t.Schema != nil && t.Schema.Name != "" {
-		return t.Schema.Name + "." + t.Name
+	if name := t.SchemaName(); name != "" {
+		return name + "." + t.Name
 	}
 
 	return t.Name
 }
 
+// QuotedName works like FullName, but quotes schema and table names if necessary, see QuoteIdentifier.
+func (t *Table) QuotedName() string {
+	if name := t.SchemaName(); name != "" {
+		return QuoteIdentifier(name) + "." + QuoteIdentifier(t.Name)
+	}
+
+	return QuoteIdentifier(t.Name)
+}
+
+// SchemaName returns name of the database schema the table is placed in,
+// which is namespace set using WithSchema or, if it is not set, name of the schema the table belongs to.
+func (t *Table) SchemaName() string {
+	if t.Namespace != "" {
+		return t.Namespace
+	}
+	if t.Schema != nil {
+		return t.Schema.Name
+	}
+
+	return ""
+}
+
 // AddColumn adds column to the table.
 func (t *Table) AddColumn(c *Column) *Table {
 	if c.Reference != nil {
@@ -59,7 +163,7 @@ func (t *Table) AddColumn(c *Column) *Table {
 			r.InversedTable.InversedRelationships = append(r.InversedTable.InversedRelationships, r)
 		}
 
-		t.AddConstraint(&Constraint{
+		fk := &Constraint{
 			Type:             ConstraintTypeForeignKey,
 			Table:            t,
 			Columns:          Columns{c},
@@ -68,12 +172,21 @@ func (t *Table) AddColumn(c *Column) *Table {
 			OnDelete:         c.OnDelete,
 			OnUpdate:         c.OnUpdate,
 			Match:            c.Match,
-		})
+
+			DeferrableInitiallyDeferred:  c.DeferrableInitiallyDeferred,
+			DeferrableInitiallyImmediate: c.DeferrableInitiallyImmediate,
+		}
+		if r.OwnerForeignKey == nil {
+			r.OwnerForeignKey = fk
+		}
+		t.AddConstraint(fk)
 		// When constraint is created, redundant data from column needs to be removed.
 		c.Reference = nil
 		c.OnDelete = 0
 		c.OnUpdate = 0
 		c.Match = 0
+		c.DeferrableInitiallyDeferred = false
+		c.DeferrableInitiallyImmediate = false
 	}
 
 	return t.addColumn(c)
@@ -137,7 +250,17 @@ func (t *Table) addRelationship(r *Relationship, opts ...ColumnOption) *Table {
 		r.InversedTable.InversedRelationships = append(r.InversedTable.InversedRelationships, r)
 	}
 
-	r.OwnerTable.addColumn(NewColumn(name, nt, append([]ColumnOption{WithReference(pk)}, opts...)...))
+	c := NewColumn(name, nt, append([]ColumnOption{WithReference(pk)}, opts...)...)
+	r.OwnerTable.addColumn(c)
+	if r.OwnerForeignKey == nil {
+		r.OwnerForeignKey = &Constraint{
+			Type:             ConstraintTypeForeignKey,
+			Table:            r.OwnerTable,
+			Columns:          Columns{c},
+			ReferenceTable:   r.InversedTable,
+			ReferenceColumns: Columns{pk},
+		}
+	}
 
 	return t
 }
@@ -259,6 +382,11 @@ func (t *Table) PrimaryKey() (*Column, bool) {
 	return nil, false
 }
 
+// StorageParameter is a key value pair that tunes how table is stored, e.g. fillfactor=70.
+type StorageParameter struct {
+	Key, Value string
+}
+
 // TableOption configures how we set up the table.
 type TableOption func(*Table)
 
@@ -279,6 +407,21 @@ func WithTemporary() TableOption {
 	}
 }
 
+// WithTemp is an alias of WithTemporary.
+func WithTemp() TableOption {
+	return WithTemporary()
+}
+
+// WithUnlogged specified, the table is created as an unlogged table.
+// Data written to unlogged table is not written to the write-ahead log, which makes it considerably faster than ordinary table.
+// However, it is not crash-safe: it is automatically truncated after a crash or unclean shutdown and it is not replicated.
+// Table cannot be both temporary and unlogged.
+func WithUnlogged() TableOption {
+	return func(t *Table) {
+		t.Unlogged = true
+	}
+}
+
 // WithTableSpace pass the name of the tablespace in which the new table is to be created.
 // If not specified, default_tablespace is consulted, or temp_tablespaces if the table is temporary.
 func WithTableSpace(s string) TableOption {
@@ -287,6 +430,145 @@ func WithTableSpace(s string) TableOption {
 	}
 }
 
+// WithStorageParam adds storage parameter, e.g. fillfactor or autovacuum_enabled, to the table.
+// Parameters are set using WITH clause of CREATE TABLE in the order they were added.
+func WithStorageParam(key, value string) TableOption {
+	return func(t *Table) {
+		t.StorageParameters = append(t.StorageParameters, StorageParameter{Key: key, Value: value})
+	}
+}
+
+// WithPartitionBy sets partitioning strategy and partition key columns.
+// Table created that way is a partitioned table, its rows are stored in partitions created using NewPartition.
+func WithPartitionBy(strategy PartitionStrategy, columns ...string) TableOption {
+	return func(t *Table) {
+		t.PartitionStrategy = strategy
+		t.PartitionColumns = columns
+	}
+}
+
+// WithTablePrimaryKey is table option that sets primary key made of given columns.
+// It should be used if primary key consist of multiple columns, otherwise WithPrimaryKey column option is enough.
+func WithTablePrimaryKey(columns ...*Column) TableOption {
+	return func(t *Table) {
+		t.AddConstraint(PrimaryKey(t, columns...))
+	}
+}
+
+// WithUniqueConstraint is table option that adds unique constraint spanning given columns.
+// If name is empty, it is generated the same way as for any other constraint.
+func WithUniqueConstraint(name string, columns ...*Column) TableOption {
+	return func(t *Table) {
+		c := Unique(t, columns...)
+		c.Identifier = name
+		t.AddConstraint(c)
+	}
+}
+
+// WithDeferrableUniqueConstraint works like WithUniqueConstraint, but the constraint is deferrable, see WithDeferrable.
+func WithDeferrableUniqueConstraint(name string, initially DeferrableMode, columns ...*Column) TableOption {
+	return func(t *Table) {
+		c := Unique(t, columns...)
+		c.Identifier = name
+		WithDeferrable(initially)(c)
+		t.AddConstraint(c)
+	}
+}
+
+// WithTableCheck is table option that adds check constraint with given name and expression, e.g. "price > 0".
+func WithTableCheck(name, expression string) TableOption {
+	return func(t *Table) {
+		t.AddConstraint(NamedCheck(t, name, expression))
+	}
+}
+
+// WithExcludeConstraint is table option that adds exclusion constraint with given name, index method and elements,
+// e.g. "gist" and period WITH &&, which guarantees that no two rows have overlapping periods.
+func WithExcludeConstraint(name, method string, elements ...ExcludeElement) TableOption {
+	return func(t *Table) {
+		t.AddConstraint(NamedExclusion(t, name, method, elements...))
+	}
+}
+
+// WithReturning is table option that defines lightweight projection returned by dedicated insert and update methods.
+// Only given columns are listed in the RETURNING clause, which saves a second round-trip if the full entity is not needed.
+func WithReturning(columns ...*Column) TableOption {
+	return func(t *Table) {
+		t.Returning = columns
+	}
+}
+
+// WithSchema is table option that places the table in database schema with given name,
+// e.g. audit or reporting, instead of the schema it is added to.
+// Generated Go identifiers of such table are prefixed with the schema name, so they do not collide with tables of the same name.
+func WithSchema(name string) TableOption {
+	return func(t *Table) {
+		t.Namespace = name
+	}
+}
+
+// WithTableComment is table option that sets comment stored in the database using COMMENT ON TABLE statement.
+// Generated Go code uses it as a doc comment of the entity.
+func WithTableComment(text string) TableOption {
+	return func(t *Table) {
+		t.Comment = text
+	}
+}
+
+// WithSoftDelete is table option that enables soft delete support.
+// It adds nullable column named SoftDeleteColumn, unless other name is given,
+// rows are marked as deleted by setting it instead of being removed.
+// Generated queries skip rows marked as deleted, unless explicitly requested otherwise.
+func WithSoftDelete(column ...string) TableOption {
+	return func(t *Table) {
+		t.SoftDelete = true
+		t.SoftDeleteColumnName = SoftDeleteColumn
+		if len(column) > 0 && column[0] != "" {
+			t.SoftDeleteColumnName = column[0]
+		}
+		t.AddColumn(NewColumn(t.SoftDeleteColumnName, TypeTimestampTZ()))
+	}
+}
+
+// WithVersionColumn is table option that enables optimistic locking.
+// It adds NOT NULL bigint column with given name, generated update methods require its current value in the patch,
+// match it in the WHERE clause and increment it, so that concurrent modification is detected.
+func WithVersionColumn(name string) TableOption {
+	return func(t *Table) {
+		t.VersionColumnName = name
+		t.AddColumn(NewColumn(name, TypeIntegerBig(), WithNotNull(), WithDefault("1")))
+	}
+}
+
+// WithUUIDPrimaryKey is table option that adds UUID primary key column, named "id" unless other name is given,
+// that defaults to gen_random_uuid(), so that the database generates it if insert does not provide one.
+// Function is built in since PostgreSQL 13, older versions require pgcrypto extension.
+func WithUUIDPrimaryKey(column ...string) TableOption {
+	return func(t *Table) {
+		name := "id"
+		if len(column) > 0 && column[0] != "" {
+			name = column[0]
+		}
+		t.AddColumn(NewColumn(name, TypeUUID(), WithPrimaryKey(), WithDefault("gen_random_uuid()")))
+	}
+}
+
+// WithCreatedAt is table option that adds NOT NULL timestamp column with given name, set to the current time on insert.
+// Default is part of the table definition, so rows inserted by other means are covered as well.
+func WithCreatedAt(name string) TableOption {
+	return func(t *Table) {
+		t.AddColumn(NewColumn(name, TypeTimestampTZ(), WithNotNull(), WithDefault("NOW()")))
+	}
+}
+
+// WithUpdatedAt is table option that adds NOT NULL timestamp column with given name, set to the current time on insert
+// and by each generated update, unless patch sets it explicitly.
+func WithUpdatedAt(name string) TableOption {
+	return func(t *Table) {
+		t.AddColumn(NewColumn(name, TypeTimestampTZ(), WithNotNull(), WithDefault("NOW()", EventInsert, EventUpdate)))
+	}
+}
+
 // WithTableShortName ...
 func WithTableShortName(s string) TableOption {
 	return func(t *Table) {
@@ -22,6 +22,181 @@ func TestNewTable(t *testing.T) {
 	}
 }
 
+func TestWithUnlogged(t *testing.T) {
+	tbl := pqt.NewTable("test", pqt.WithUnlogged())
+	if !tbl.Unlogged {
+		t.Errorf("table should have field unlogged set to true")
+	}
+	if tbl.Temporary {
+		t.Errorf("table should not have field temporary set to true")
+	}
+	if tbl = pqt.NewTable("test", pqt.WithTemp()); !tbl.Temporary {
+		t.Errorf("table should have field temporary set to true")
+	}
+}
+
+func TestWithTablePrimaryKey(t *testing.T) {
+	userID := pqt.NewColumn("user_id", pqt.TypeIntegerBig())
+	roleID := pqt.NewColumn("role_id", pqt.TypeIntegerBig())
+	tbl := pqt.NewTable("user_role", pqt.WithTablePrimaryKey(userID, roleID)).
+		AddColumn(userID).
+		AddColumn(roleID)
+
+	if len(tbl.Constraints) != 1 {
+		t.Fatalf("table should have 1 constraint, but has %d", len(tbl.Constraints))
+	}
+	if tbl.Constraints[0].Type != pqt.ConstraintTypePrimaryKey {
+		t.Errorf("wrong constraint type: %s", tbl.Constraints[0].Type)
+	}
+	if name := tbl.Constraints[0].Name(); name != "public.user_role_user_id_role_id_pkey" {
+		t.Errorf("wrong constraint name: %s", name)
+	}
+}
+
+func TestWithReturning(t *testing.T) {
+	id := pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey())
+	updatedAt := pqt.NewColumn("updated_at", pqt.TypeTimestampTZ())
+	tbl := pqt.NewTable("user", pqt.WithReturning(id, updatedAt)).
+		AddColumn(id).
+		AddColumn(pqt.NewColumn("name", pqt.TypeText())).
+		AddColumn(updatedAt)
+
+	if len(tbl.Returning) != 2 {
+		t.Fatalf("table should have 2 returning columns, but has %d", len(tbl.Returning))
+	}
+	if tbl.Returning[0] != id || tbl.Returning[1] != updatedAt {
+		t.Errorf("wrong returning columns")
+	}
+}
+
+func TestWithSoftDelete(t *testing.T) {
+	tbl := pqt.NewTable("user", pqt.WithSoftDelete()).
+		AddColumn(pqt.NewColumn("id", pqt.TypeSerialBig(), pqt.WithPrimaryKey()))
+
+	if !tbl.SoftDelete {
+		t.Error("soft delete should be enabled")
+	}
+	if len(tbl.Columns) != 2 {
+		t.Fatalf("table should have 2 columns, but has %d", len(tbl.Columns))
+	}
+	c := tbl.Columns[0]
+	if c.Name != pqt.SoftDeleteColumn || c.Type != pqt.TypeTimestampTZ() || c.NotNull || c.Table != tbl {
+		t.Errorf("wrong soft delete column: %#v", c)
+	}
+}
+
+func TestWithSoftDelete_column(t *testing.T) {
+	tbl := pqt.NewTable("user", pqt.WithSoftDelete("removed_at"))
+
+	if tbl.SoftDeleteColumnName != "removed_at" {
+		t.Errorf("wrong soft delete column name: %s", tbl.SoftDeleteColumnName)
+	}
+	if len(tbl.Columns) != 1 || tbl.Columns[0].Name != "removed_at" {
+		t.Errorf("table should have removed_at column, got: %v", tbl.Columns)
+	}
+}
+
+func TestWithVersionColumn(t *testing.T) {
+	tbl := pqt.NewTable("user", pqt.WithVersionColumn("version"))
+
+	if tbl.VersionColumnName != "version" {
+		t.Errorf("wrong version column name: %s", tbl.VersionColumnName)
+	}
+	if len(tbl.Columns) != 1 {
+		t.Fatalf("table should have 1 column, but has %d", len(tbl.Columns))
+	}
+	c := tbl.Columns[0]
+	if c.Name != "version" || c.Type != pqt.TypeIntegerBig() || !c.NotNull || c.Table != tbl {
+		t.Errorf("wrong version column: %#v", c)
+	}
+	if d, ok := c.DefaultOn(pqt.EventInsert); !ok || d != "1" {
+		t.Errorf("wrong version column default: %s", d)
+	}
+}
+
+func TestWithSchema(t *testing.T) {
+	news := pqt.NewTable("news", pqt.WithSchema("reporting"))
+	user := pqt.NewTable("user")
+	pqt.NewSchema("content").AddTable(news).AddTable(user)
+
+	if news.SchemaName() != "reporting" || news.FullName() != "reporting.news" {
+		t.Errorf("wrong name of the table placed in other schema: %s", news.FullName())
+	}
+	if user.SchemaName() != "content" || user.FullName() != "content.user" {
+		t.Errorf("wrong name of the table: %s", user.FullName())
+	}
+}
+
+func TestWithCreatedAt_WithUpdatedAt(t *testing.T) {
+	tbl := pqt.NewTable("user", pqt.WithCreatedAt("created_at"), pqt.WithUpdatedAt("updated_at"))
+
+	if len(tbl.Columns) != 2 {
+		t.Fatalf("table should have 2 columns, but has %d", len(tbl.Columns))
+	}
+	created, updated := tbl.Columns[0], tbl.Columns[1]
+	if created.Name != "created_at" || created.Type != pqt.TypeTimestampTZ() || !created.NotNull {
+		t.Errorf("wrong created at column: %#v", created)
+	}
+	if _, ok := created.DefaultOn(pqt.EventUpdate); ok {
+		t.Error("created at column should not have update default")
+	}
+	if updated.Name != "updated_at" || updated.Type != pqt.TypeTimestampTZ() || !updated.NotNull {
+		t.Errorf("wrong updated at column: %#v", updated)
+	}
+	if d, ok := created.DefaultOn(pqt.EventInsert); !ok || d != "NOW()" {
+		t.Errorf("wrong created at column default: %s", d)
+	}
+	for _, e := range []pqt.Event{pqt.EventInsert, pqt.EventUpdate} {
+		if d, ok := updated.DefaultOn(e); !ok || d != "NOW()" {
+			t.Errorf("wrong updated at column %s default: %s", e, d)
+		}
+	}
+}
+
+func TestWithUUIDPrimaryKey(t *testing.T) {
+	for name, given := range map[string]*pqt.Table{
+		"id":    pqt.NewTable("token", pqt.WithUUIDPrimaryKey()),
+		"token": pqt.NewTable("token", pqt.WithUUIDPrimaryKey("token")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if len(given.Columns) != 1 {
+				t.Fatalf("table should have 1 column, but has %d", len(given.Columns))
+			}
+			pk := given.Columns[0]
+			if pk.Name != name || pk.Type != pqt.TypeUUID() || !pk.PrimaryKey {
+				t.Errorf("wrong primary key column: %#v", pk)
+			}
+			if d, ok := pk.DefaultOn(pqt.EventInsert); !ok || d != "gen_random_uuid()" {
+				t.Errorf("wrong primary key column default: %s", d)
+			}
+		})
+	}
+}
+
+func TestNewPartition(t *testing.T) {
+	parent := pqt.NewTable("parent", pqt.WithPartitionBy(pqt.PartitionStrategyList, "region"))
+	tbl := pqt.NewPartition(parent, "child", pqt.PartitionBoundsList("'eu'", "'us'"))
+
+	if parent.IsPartition() {
+		t.Errorf("parent table should not be a partition")
+	}
+	if !tbl.IsPartition() {
+		t.Errorf("child table should be a partition")
+	}
+	if tbl.PartitionOf != parent {
+		t.Errorf("child table should be a partition of parent table")
+	}
+	if tbl.PartitionBounds != "FOR VALUES IN ('eu', 'us')" {
+		t.Errorf("wrong partition bounds: %s", tbl.PartitionBounds)
+	}
+	if parent.PartitionStrategy != pqt.PartitionStrategyList {
+		t.Errorf("wrong partition strategy: %s", parent.PartitionStrategy)
+	}
+	if len(parent.PartitionColumns) != 1 || parent.PartitionColumns[0] != "region" {
+		t.Errorf("wrong partition columns: %v", parent.PartitionColumns)
+	}
+}
+
 func TestTable_AddColumn(t *testing.T) {
 	c0 := pqt.NewColumn("c0", pqt.TypeSerialBig(), pqt.WithPrimaryKey())
 	c1 := &pqt.Column{Name: "c1"}
@@ -0,0 +1,77 @@
+package pqt
+
+import (
+	"bytes"
+	"database/sql"
+)
+
+// TruncateOptions configures TRUNCATE statement executed by TruncateAll.
+type TruncateOptions struct {
+	// Cascade truncates also tables that have foreign key references to any of the truncated tables, even if they are not part of the schema.
+	Cascade bool
+	// RestartIdentity restarts sequences owned by columns of the truncated tables.
+	RestartIdentity bool
+}
+
+// TruncateAll removes all rows from each table of the schema using single TRUNCATE statement.
+// Tables are listed in dependency order, tables that refer to others come first.
+// Temporary tables and partitions are skipped, the latter are truncated together with their parent.
+func (s *Schema) TruncateAll(db *sql.DB, opts TruncateOptions) error {
+	tables := truncateOrder(s)
+	if len(tables) == 0 {
+		return nil
+	}
+
+	buf := bytes.NewBufferString("TRUNCATE ")
+	for i, t := range tables {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(t.FullName())
+	}
+	if opts.RestartIdentity {
+		buf.WriteString(" RESTART IDENTITY")
+	}
+	if opts.Cascade {
+		buf.WriteString(" CASCADE")
+	}
+
+	_, err := db.Exec(buf.String())
+	return err
+}
+
+// truncateOrder returns tables of the schema sorted in a way that each table is preceded by tables that refer to it.
+// Cycles are broken arbitrarily, it is fine as all tables are truncated by the same statement.
+func truncateOrder(s *Schema) []*Table {
+	dependents := make(map[*Table][]*Table)
+	for _, t := range s.Tables {
+		for _, c := range allConstraints(t) {
+			if c.Type == ConstraintTypeForeignKey && c.ReferenceTable != nil && c.ReferenceTable != t {
+				dependents[c.ReferenceTable] = append(dependents[c.ReferenceTable], t)
+			}
+		}
+	}
+
+	var (
+		ordered []*Table
+		visited = make(map[*Table]bool)
+		visit   func(t *Table)
+	)
+	visit = func(t *Table) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		for _, d := range dependents[t] {
+			visit(d)
+		}
+		if !t.Temporary && !t.IsPartition() {
+			ordered = append(ordered, t)
+		}
+	}
+	for _, t := range s.Tables {
+		visit(t)
+	}
+
+	return ordered
+}
@@ -0,0 +1,32 @@
+package pqt
+
+import (
+	"testing"
+)
+
+func TestTruncateOrder(t *testing.T) {
+	userID := NewColumn("id", TypeSerialBig(), WithPrimaryKey())
+	user := NewTable("user").AddColumn(userID)
+	newsID := NewColumn("id", TypeSerialBig(), WithPrimaryKey())
+	news := NewTable("news").
+		AddColumn(newsID).
+		AddColumn(NewColumn("author_id", TypeIntegerBig(), WithReference(userID)))
+	comment := NewTable("comment").
+		AddColumn(NewColumn("news_id", TypeIntegerBig(), WithReference(newsID))).
+		AddColumn(NewColumn("author_id", TypeIntegerBig(), WithReference(userID)))
+	session := NewTable("session", WithTemporary()).
+		AddColumn(NewColumn("user_id", TypeIntegerBig(), WithReference(userID)))
+
+	s := NewSchema("example").AddTable(user).AddTable(news).AddTable(comment).AddTable(session)
+
+	got := truncateOrder(s)
+	expected := []string{"example.comment", "example.news", "example.user"}
+	if len(got) != len(expected) {
+		t.Fatalf("wrong number of tables, expected %d but got %d", len(expected), len(got))
+	}
+	for i, name := range expected {
+		if got[i].FullName() != name {
+			t.Errorf("wrong table at position %d, expected %s but got %s", i, name, got[i].FullName())
+		}
+	}
+}
@@ -24,16 +24,10 @@ func (bt BaseType) Fingerprint() string {
 	return fmt.Sprintf("base: %s", bt.name)
 }
 
-// TypeDecimal ...
+// TypeDecimal is equivalent of TypeNumeric.
+// It panics if scale is greater than precision, see TypeNumeric.
 func TypeDecimal(precision, scale int) BaseType {
-	switch {
-	case precision == 0:
-		return BaseType{name: "DECIMAL"}
-	case precision != 0 && scale == 0:
-		return BaseType{name: fmt.Sprintf("DECIMAL(%d)", precision)}
-	default:
-		return BaseType{name: fmt.Sprintf("DECIMAL(%d,%d)", precision, scale)}
-	}
+	return numeric("DECIMAL", precision, scale)
 }
 
 // TypeReal ...
@@ -107,14 +101,24 @@ func TypeDoubleArray(l int) BaseType {
 // It is especially recommended for storing monetary amounts and other quantities where exactness is required.
 // Calculations with numeric values yield exact results where possible, e.g. addition, subtraction, multiplication.
 // However, calculations on numeric values are very slow compared to the integer types, or to the floating-point types described in the next section.
+// Zero precision means unconstrained numeric, e.g. TypeNumeric(12, 2) is suitable for money.
+// It panics if precision is negative or scale is greater than precision, so mistake is caught while the schema is built, not by the database.
 func TypeNumeric(precision, scale int) BaseType {
+	return numeric("NUMERIC", precision, scale)
+}
+
+func numeric(name string, precision, scale int) BaseType {
 	switch {
+	case precision < 0:
+		panic(fmt.Sprintf("pqt: %s precision cannot be negative, got %d", name, precision))
+	case scale > precision:
+		panic(fmt.Sprintf("pqt: %s scale cannot be greater than precision, got %d and %d", name, scale, precision))
 	case precision == 0:
-		return BaseType{name: "NUMERIC"}
-	case precision != 0 && scale == 0:
-		return BaseType{name: fmt.Sprintf("NUMERIC(%d)", precision)}
+		return BaseType{name: name}
+	case scale == 0:
+		return BaseType{name: fmt.Sprintf("%s(%d)", name, precision)}
 	default:
-		return BaseType{name: fmt.Sprintf("NUMERIC(%d,%d)", precision, scale)}
+		return BaseType{name: fmt.Sprintf("%s(%d,%d)", name, precision, scale)}
 	}
 }
 
@@ -154,6 +158,11 @@ func TypeTextArray(l int) BaseType {
 	return BaseType{name: fmt.Sprintf("TEXT[%d]", l)}
 }
 
+// TypeArray returns one-dimensional array of given base type, for example TypeArray(TypeText()) is TEXT[].
+func TypeArray(base Type) BaseType {
+	return BaseType{name: base.String() + "[]"}
+}
+
 // TypeVarchar ...
 func TypeVarchar(l int) BaseType {
 	if l == 0 {
@@ -241,6 +250,34 @@ func TypeEnumerated(name string, enums ...string) EnumeratedType {
 	}
 }
 
+// DomainType is a data type based on another one, with optional constraint on its values.
+// It allows to share the constraint between columns, for example domain of e-mail addresses.
+type DomainType struct {
+	name  string
+	Base  Type
+	Check string
+}
+
+// String implements Stringer interface.
+func (dt DomainType) String() string {
+	return dt.name
+}
+
+// Fingerprint implements Type interface.
+func (dt DomainType) Fingerprint() string {
+	return fmt.Sprintf("domain: %s %s CHECK (%s)", dt.name, dt.Base.Fingerprint(), dt.Check)
+}
+
+// TypeDomain allocates DomainType with given name, that is based on given type.
+// Check is an expression that refers to the value using VALUE keyword, it can be empty.
+func TypeDomain(name string, base Type, check string) DomainType {
+	return DomainType{
+		name:  name,
+		Base:  base,
+		Check: check,
+	}
+}
+
 // PseudoType ...
 type PseudoType struct {
 	name string
@@ -48,6 +48,21 @@ func TestTypeIntegerBigArray(t *testing.T) {
 	assertType(t, expected, got)
 }
 
+func TestTypeArray(t *testing.T) {
+	cases := map[string]pqt.Type{
+		"TEXT[]":    pqt.TypeArray(pqt.TypeText()),
+		"INTEGER[]": pqt.TypeArray(pqt.TypeInteger()),
+		"BIGINT[]":  pqt.TypeArray(pqt.TypeIntegerBig()),
+	}
+
+	for expected, got := range cases {
+		assertType(t, expected, got)
+	}
+	if pqt.TypeArray(pqt.TypeText()) != pqt.TypeTextArray(0) {
+		t.Error("generic text array should be equal to TypeTextArray")
+	}
+}
+
 func TestTypeDecimal_zeroPrecisionZeroScale(t *testing.T) {
 	expected := "DECIMAL"
 	got := pqt.TypeDecimal(0, 0)
@@ -90,6 +105,26 @@ func TestTypeNumeric(t *testing.T) {
 	assertType(t, expected, got)
 }
 
+func TestTypeNumeric_invalid(t *testing.T) {
+	cases := map[string]struct {
+		precision, scale int
+	}{
+		"negative-precision":      {precision: -1},
+		"scale-greater":           {precision: 4, scale: 5},
+		"scale-without-precision": {scale: 2},
+	}
+	for hint, c := range cases {
+		t.Run(hint, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			pqt.TypeNumeric(c.precision, c.scale)
+		})
+	}
+}
+
 func TestTypeDoubleArray_zero(t *testing.T) {
 	expected := "DOUBLE PRECISION[]"
 	got := pqt.TypeDoubleArray(0)
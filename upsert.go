@@ -0,0 +1,57 @@
+package pqt
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// ErrUpsertIgnored is returned by generated upsert methods if conflicting row was left untouched (ON CONFLICT DO NOTHING).
+var ErrUpsertIgnored = errors.New("pqt: upsert ignored due to conflict")
+
+// UpsertConflictTarget represents conflict target of INSERT ... ON CONFLICT statement.
+// It can be either a constraint name or a list of columns that are used to infer unique index.
+type UpsertConflictTarget struct {
+	Constraint string
+	Columns    []string
+}
+
+// ConflictOnConstraint returns conflict target that points explicitly to the constraint with given name.
+func ConflictOnConstraint(name string) UpsertConflictTarget {
+	return UpsertConflictTarget{Constraint: name}
+}
+
+// ConflictOnColumns returns conflict target that infers unique index from given columns.
+func ConflictOnColumns(columns ...string) UpsertConflictTarget {
+	return UpsertConflictTarget{Columns: columns}
+}
+
+// IsZero returns true if conflict target is not specified.
+func (uct UpsertConflictTarget) IsZero() bool {
+	return uct.Constraint == "" && len(uct.Columns) == 0
+}
+
+// HasColumn returns true if given column is part of the conflict target.
+func (uct UpsertConflictTarget) HasColumn(name string) bool {
+	for _, c := range uct.Columns {
+		if c == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String implements fmt.Stringer interface.
+// It returns conflict target as a part of SQL statement, for example "ON CONSTRAINT "example.news_title_key"" or "(title, lead)".
+func (uct UpsertConflictTarget) String() string {
+	switch {
+	case uct.Constraint != "":
+		return "ON CONSTRAINT " + pq.QuoteIdentifier(uct.Constraint)
+	case len(uct.Columns) > 0:
+		return "(" + strings.Join(uct.Columns, ", ") + ")"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,49 @@
+package pqt_test
+
+import (
+	"testing"
+
+	"github.com/piotrkowalczuk/pqt"
+)
+
+func TestUpsertConflictTarget_String(t *testing.T) {
+	cases := map[string]struct {
+		given    pqt.UpsertConflictTarget
+		expected string
+	}{
+		"empty": {
+			given:    pqt.UpsertConflictTarget{},
+			expected: "",
+		},
+		"constraint": {
+			given:    pqt.ConflictOnConstraint("example.news_title_key"),
+			expected: `ON CONSTRAINT "example.news_title_key"`,
+		},
+		"columns": {
+			given:    pqt.ConflictOnColumns("title", "lead"),
+			expected: "(title, lead)",
+		},
+	}
+
+	for hint, c := range cases {
+		if got := c.given.String(); got != c.expected {
+			t.Errorf("%s: wrong output, expected %s but got %s", hint, c.expected, got)
+		}
+		if c.given.IsZero() != (c.expected == "") {
+			t.Errorf("%s: wrong is zero result", hint)
+		}
+	}
+}
+
+func TestUpsertConflictTarget_HasColumn(t *testing.T) {
+	ct := pqt.ConflictOnColumns("title", "lead")
+	if !ct.HasColumn("lead") {
+		t.Error("conflict target should have lead column")
+	}
+	if ct.HasColumn("content") {
+		t.Error("conflict target should not have content column")
+	}
+	if pqt.ConflictOnConstraint("example.news_title_key").HasColumn("title") {
+		t.Error("constraint conflict target should not have any column")
+	}
+}